@@ -0,0 +1,45 @@
+// Command static exports the published content as a static site: HTML pages rendered with a theme,
+// the sitemap, the feeds, robots.txt and the media the pages link to, listed in manifest.json.
+// Entries and media that did not change since the previous export into the same directory are
+// left as they are.
+//
+//	static [-o dir] [-theme dir] [-full]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/mashurimansur/goCMS/internal/app"
+	staticusecase "github.com/mashurimansur/goCMS/internal/usecase/static"
+	"github.com/mashurimansur/goCMS/internal/utils/config"
+)
+
+func main() {
+	ctx := context.Background()
+
+	appConfig, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+
+	output := flag.String("o", appConfig.Static.OutputDir, "directory to export the site to")
+	themeDir := flag.String("theme", appConfig.Static.ThemeDir, "directory of the theme templates (default built-in theme)")
+	full := flag.Bool("full", false, "render every entry again instead of only the changed ones")
+	flag.Parse()
+
+	s, err := app.NewStatic(ctx, appConfig, *output, *themeDir)
+	if err != nil {
+		log.Fatalf("failed to initialize static export: %v", err)
+	}
+	result, err := s.Build(ctx, staticusecase.BuildOptions{Full: *full})
+	s.Close()
+	if err != nil {
+		log.Fatalf("static export failed: %v", err)
+	}
+
+	log.Printf("exported %d files to %s: %d pages rendered, %d unchanged, %d media copied, %d files removed",
+		len(result.Manifest.Files), *output, result.Rendered, result.Unchanged, result.Copied, result.Removed)
+}
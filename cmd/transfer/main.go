@@ -0,0 +1,129 @@
+// Command transfer exports the site to a JSON bundle and imports JSON bundles or WordPress WXR
+// exports into it.
+//
+//	transfer export [-o site.json]
+//	transfer import [-format json|wxr] [-slugs rename|skip|overwrite] [-dry-run] site.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/mashurimansur/goCMS/internal/adapter/wxr"
+	"github.com/mashurimansur/goCMS/internal/app"
+	"github.com/mashurimansur/goCMS/internal/domain/transfer"
+	transferusecase "github.com/mashurimansur/goCMS/internal/usecase/transfer"
+	"github.com/mashurimansur/goCMS/internal/utils/config"
+)
+
+const usage = `usage:
+  transfer export [-o file]
+  transfer import [-format json|wxr] [-slugs rename|skip|overwrite] [-dry-run] file`
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	ctx := context.Background()
+	appConfig, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+
+	var run func(context.Context, transferusecase.UseCase, []string) error
+	switch os.Args[1] {
+	case "export":
+		run = runExport
+	case "import":
+		run = runImport
+	default:
+		log.Fatal(usage)
+	}
+
+	t, err := app.NewTransfer(ctx, appConfig)
+	if err != nil {
+		log.Fatalf("failed to initialize transfer: %v", err)
+	}
+	err = run(ctx, t, os.Args[2:])
+	t.Close()
+	if err != nil {
+		log.Fatalf("%s failed: %v", os.Args[1], err)
+	}
+}
+
+func runExport(ctx context.Context, uc transferusecase.UseCase, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	output := flags.String("o", "", "file to write the bundle to (default stdout)")
+	flags.Parse(args)
+
+	bundle, err := uc.Export(ctx)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		return err
+	}
+	log.Printf("exported %d entries, %d media items and %d users; copy the media storage directory alongside the bundle",
+		len(bundle.Contents), len(bundle.Media), len(bundle.Users))
+	return nil
+}
+
+func runImport(ctx context.Context, uc transferusecase.UseCase, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "json", "format of the file: json or wxr")
+	slugs := flags.String("slugs", transferusecase.SlugRename, "what to do when a slug is taken: rename, skip or overwrite")
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without writing anything")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected one file to import\n%s", usage)
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var bundle *transfer.Bundle
+	switch *format {
+	case "json":
+		bundle = new(transfer.Bundle)
+		if err := json.NewDecoder(f).Decode(bundle); err != nil {
+			return fmt.Errorf("cannot parse bundle: %w", err)
+		}
+	case "wxr":
+		if bundle, err = wxr.Read(f); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	report, err := uc.Import(ctx, bundle, transferusecase.ImportOptions{SlugConflict: *slugs, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/bulk"
+	bulkusecase "github.com/mashurimansur/goCMS/internal/usecase/bulk"
+)
+
+// BulkHandler runs operations on many content entries or users at once.
+type BulkHandler struct {
+	bulkUseCase bulkusecase.UseCase
+}
+
+func NewBulkHandler(bulkUseCase bulkusecase.UseCase) *BulkHandler {
+	return &BulkHandler{
+		bulkUseCase: bulkUseCase,
+	}
+}
+
+func (h *BulkHandler) Register(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	operations := router.Group("/admin/bulk", authMiddleware)
+	operations.POST("/contents", h.runContents)
+	operations.POST("/users", h.runUsers)
+	operations.GET("/jobs/:id", h.getJob)
+}
+
+type bulkRequest struct {
+	Action string `json:"action" binding:"required"`
+	// IDs selects the items; without IDs the filter does.
+	IDs    []string    `json:"ids"`
+	Filter bulk.Filter `json:"filter"`
+	Params bulk.Params `json:"params"`
+	DryRun bool        `json:"dry_run"`
+}
+
+// @Summary      Bulk content operation
+// @Description  Publish, unpublish, archive, delete, re-tag (add_tags, remove_tags) or move content entries selected by IDs or a filter. Results are reported per entry; dry runs change nothing. Large operations run in the background and answer 202 with their job.
+// @Tags         bulk
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body  bulkRequest  true  "Bulk Request"
+// @Success      200  {object}  bulk.Job
+// @Success      202  {object}  bulk.Job
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/bulk/contents [post]
+func (h *BulkHandler) runContents(c *gin.Context) {
+	h.run(c, bulk.ResourceContent)
+}
+
+// @Summary      Bulk user operation
+// @Description  Delete users or set their status or role, selected by IDs or a filter on role and status. Only admins may run them. Results are reported per user; dry runs change nothing. Large operations run in the background and answer 202 with their job.
+// @Tags         bulk
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body  bulkRequest  true  "Bulk Request"
+// @Success      200  {object}  bulk.Job
+// @Success      202  {object}  bulk.Job
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/bulk/users [post]
+func (h *BulkHandler) runUsers(c *gin.Context) {
+	h.run(c, bulk.ResourceUser)
+}
+
+// run starts a bulk operation on a resource. Finished jobs are answered with 200, jobs left running
+// in the background with 202 and the URL to follow them at.
+func (h *BulkHandler) run(c *gin.Context, resource string) {
+	var req bulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.bulkUseCase.Run(c.Request.Context(), bulkusecase.Request{
+		Resource: resource,
+		Action:   req.Action,
+		IDs:      req.IDs,
+		Filter:   req.Filter,
+		Params:   req.Params,
+		DryRun:   req.DryRun,
+	}, c.GetString("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if job.Status == bulk.StatusPending {
+		c.Header("Location", "/api/v1/admin/bulk/jobs/"+job.ID)
+		c.JSON(http.StatusAccepted, job)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary      Get bulk job
+// @Description  Get the status, progress and per-item results of a bulk operation
+// @Tags         bulk
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {object}  bulk.Job
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/bulk/jobs/{id} [get]
+func (h *BulkHandler) getJob(c *gin.Context) {
+	job, err := h.bulkUseCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
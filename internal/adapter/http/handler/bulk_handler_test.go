@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/bulk"
+	bulkusecase "github.com/mashurimansur/goCMS/internal/usecase/bulk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockBulkUseCase is a mock implementation of bulkusecase.UseCase
+type MockBulkUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBulkUseCase) Run(ctx context.Context, req bulkusecase.Request, userID string) (*bulk.Job, error) {
+	args := m.Called(ctx, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bulk.Job), args.Error(1)
+}
+
+func (m *MockBulkUseCase) Get(ctx context.Context, id string) (*bulk.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bulk.Job), args.Error(1)
+}
+
+func (m *MockBulkUseCase) FailInterrupted(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func newBulkRouter(uc *MockBulkUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Next()
+	}
+	NewBulkHandler(uc).Register(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestBulkHandler_RunContents(t *testing.T) {
+	mockUseCase := new(MockBulkUseCase)
+	router := newBulkRouter(mockUseCase)
+
+	req := bulkusecase.Request{
+		Resource: bulk.ResourceContent, Action: bulk.ActionAddTags, IDs: []string{"post-1"},
+		Params: bulk.Params{TagIDs: []string{"go"}}, DryRun: true,
+	}
+	mockUseCase.On("Run", mock.Anything, req, "user-1").
+		Return(&bulk.Job{ID: "job-1", Status: bulk.StatusCompleted, Total: 1, Succeeded: 1}, nil)
+
+	body := `{"action":"add_tags","ids":["post-1"],"params":{"tag_ids":["go"]},"dry_run":true}`
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/v1/admin/bulk/contents", bytes.NewBufferString(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"succeeded":1`)
+}
+
+func TestBulkHandler_RunUsers_Background(t *testing.T) {
+	mockUseCase := new(MockBulkUseCase)
+	router := newBulkRouter(mockUseCase)
+
+	mockUseCase.On("Run", mock.Anything, mock.MatchedBy(func(req bulkusecase.Request) bool {
+		return req.Resource == bulk.ResourceUser && req.Filter.Role == "guest"
+	}), "user-1").Return(&bulk.Job{ID: "job-2", Status: bulk.StatusPending, Total: 500}, nil)
+
+	body := `{"action":"delete","filter":{"role":"guest"}}`
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/v1/admin/bulk/users", bytes.NewBufferString(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/api/v1/admin/bulk/jobs/job-2", w.Header().Get("Location"))
+}
+
+func TestBulkHandler_RunUsers_Forbidden(t *testing.T) {
+	mockUseCase := new(MockBulkUseCase)
+	router := newBulkRouter(mockUseCase)
+
+	mockUseCase.On("Run", mock.Anything, mock.Anything, "user-1").Return(nil, bulkusecase.ErrAdminRequired)
+
+	body := `{"action":"set_role","ids":["user-1"],"params":{"role":"admin"}}`
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/v1/admin/bulk/users", bytes.NewBufferString(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestBulkHandler_Run_Invalid(t *testing.T) {
+	mockUseCase := new(MockBulkUseCase)
+	router := newBulkRouter(mockUseCase)
+
+	mockUseCase.On("Run", mock.Anything, mock.Anything, "user-1").Return(nil, bulkusecase.ErrInvalidRequest)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/v1/admin/bulk/contents", bytes.NewBufferString(`{"action":"explode"}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	httpReq, _ = http.NewRequest("POST", "/api/v1/admin/bulk/contents", bytes.NewBufferString(`{}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUseCase.AssertNumberOfCalls(t, "Run", 1)
+}
+
+func TestBulkHandler_GetJob(t *testing.T) {
+	mockUseCase := new(MockBulkUseCase)
+	router := newBulkRouter(mockUseCase)
+
+	mockUseCase.On("Get", mock.Anything, "job-1").Return(&bulk.Job{ID: "job-1", Status: bulk.StatusRunning, Processed: 100}, nil)
+	mockUseCase.On("Get", mock.Anything, "missing").Return(nil, bulk.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/bulk/jobs/job-1", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"processed":100`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/admin/bulk/jobs/missing", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	categoryusecase "github.com/mashurimansur/goCMS/internal/usecase/category"
+)
+
+// CategoryHandler exposes admin and public HTTP endpoints for categories.
+type CategoryHandler struct {
+	categoryUseCase categoryusecase.UseCase
+}
+
+func NewCategoryHandler(categoryUseCase categoryusecase.UseCase) *CategoryHandler {
+	return &CategoryHandler{
+		categoryUseCase: categoryUseCase,
+	}
+}
+
+// Register mounts the public category routes, which serve readers identified by readerMiddleware,
+// and the admin routes behind authMiddleware.
+func (h *CategoryHandler) Register(router *gin.RouterGroup, authMiddleware, readerMiddleware gin.HandlerFunc) {
+	public := router.Group("/categories")
+	public.Use(readerMiddleware)
+	{
+		public.GET("", h.tree)
+		public.GET("/:slug/contents", h.listContent)
+	}
+
+	admin := router.Group("/admin/categories")
+	admin.Use(authMiddleware)
+	{
+		admin.POST("", h.create)
+		admin.GET("", h.list)
+		admin.GET("/:id", h.get)
+		admin.PUT("/:id", h.update)
+		admin.DELETE("/:id", h.delete)
+	}
+}
+
+type categoryRequest struct {
+	ParentID    string `json:"parent_id"`
+	Name        string `json:"name" binding:"required"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Position    int    `json:"position"`
+	// Version is the version being replaced on update; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+func (r categoryRequest) toCategory() *category.Category {
+	return &category.Category{
+		ParentID:    r.ParentID,
+		Name:        r.Name,
+		Slug:        r.Slug,
+		Description: r.Description,
+		Position:    r.Position,
+	}
+}
+
+// @Summary      Create category
+// @Description  Create a new category, optionally nested under a parent
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body categoryRequest true "Category Request"
+// @Success      201  {object}  category.Category
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/categories [post]
+func (h *CategoryHandler) create(c *gin.Context) {
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cat := req.toCategory()
+	if err := h.categoryUseCase.Create(c.Request.Context(), cat); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cat)
+}
+
+// @Summary      Get category
+// @Description  Get a category by ID
+// @Tags         categories
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Category ID"
+// @Success      200  {object}  category.Category
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/categories/{id} [get]
+func (h *CategoryHandler) get(c *gin.Context) {
+	cat, err := h.categoryUseCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, cat.Version)
+	c.JSON(http.StatusOK, cat)
+}
+
+// @Summary      Update category
+// @Description  Update a category by ID
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string           true   "Category ID"
+// @Param        If-Match  header  string           false  "ETag of the version being replaced"
+// @Param        request   body    categoryRequest  true   "Category Request"
+// @Success      200  {object}  category.Category
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/categories/{id} [put]
+func (h *CategoryHandler) update(c *gin.Context) {
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	cat := req.toCategory()
+	cat.ID = c.Param("id")
+	cat.Version = version
+	if err := h.categoryUseCase.Update(c.Request.Context(), cat); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, cat.Version)
+	c.JSON(http.StatusOK, cat)
+}
+
+// @Summary      Delete category
+// @Description  Move a category to the trash. Its child categories show up at the root until it is restored
+// @Tags         categories
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Category ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/categories/{id} [delete]
+func (h *CategoryHandler) delete(c *gin.Context) {
+	if err := h.categoryUseCase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully"})
+}
+
+// @Summary      List categories
+// @Description  List all categories as a flat list
+// @Tags         categories
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   category.Category
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/categories [get]
+func (h *CategoryHandler) list(c *gin.Context) {
+	categories, err := h.categoryUseCase.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// @Summary      Category tree
+// @Description  List all categories as a nested tree
+// @Tags         categories
+// @Produce      json
+// @Success      200  {array}   category.Category
+// @Failure      500  {object}  map[string]string
+// @Router       /categories [get]
+func (h *CategoryHandler) tree(c *gin.Context) {
+	tree, err := h.categoryUseCase.Tree(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+// @Summary      List content by category
+// @Description  List published content in a category and all of its descendants
+// @Tags         categories
+// @Produce      json
+// @Param        slug    path      string  true   "Category slug"
+// @Param        limit   query     int     false  "Limit"  default(10)
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Success      200  {array}   content.Content
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /categories/{slug}/contents [get]
+func (h *CategoryHandler) listContent(c *gin.Context) {
+	limit, offset := pagination(c)
+	entries, err := h.categoryUseCase.ListContent(c.Request.Context(), c.Param("slug"), limit, offset, viewer(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	categoryusecase "github.com/mashurimansur/goCMS/internal/usecase/category"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCategoryUseCase is a mock implementation of categoryusecase.UseCase
+type MockCategoryUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCategoryUseCase) Create(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCategoryUseCase) Get(ctx context.Context, id string) (*category.Category, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) Update(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCategoryUseCase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryUseCase) List(ctx context.Context) ([]*category.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) Tree(ctx context.Context) ([]*category.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) ListContent(ctx context.Context, slug string, limit, offset int, viewer content.Viewer) ([]*content.Content, error) {
+	args := m.Called(ctx, slug, limit, offset, viewer)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryUseCase) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newCategoryRouter(uc *MockCategoryUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) { c.Next() }
+	NewCategoryHandler(uc).Register(router.Group("/api/v1"), authMiddleware, func(c *gin.Context) { c.Next() })
+	return router
+}
+
+func TestCategoryHandler_Create(t *testing.T) {
+	mockUseCase := new(MockCategoryUseCase)
+	router := newCategoryRouter(mockUseCase)
+
+	body, _ := json.Marshal(categoryRequest{Name: "News", Slug: "news"})
+	mockUseCase.On("Create", mock.Anything, mock.AnythingOfType("*category.Category")).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/categories", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestCategoryHandler_Update_Cycle(t *testing.T) {
+	mockUseCase := new(MockCategoryUseCase)
+	router := newCategoryRouter(mockUseCase)
+
+	body, _ := json.Marshal(categoryRequest{Name: "News", Slug: "news", ParentID: "child"})
+	mockUseCase.On("Update", mock.Anything, mock.AnythingOfType("*category.Category")).Return(categoryusecase.ErrCyclicParent)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/categories/news", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCategoryHandler_Tree(t *testing.T) {
+	mockUseCase := new(MockCategoryUseCase)
+	router := newCategoryRouter(mockUseCase)
+
+	mockUseCase.On("Tree", mock.Anything).Return([]*category.Category{{ID: "news"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/categories", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCategoryHandler_ListContent_NotFound(t *testing.T) {
+	mockUseCase := new(MockCategoryUseCase)
+	router := newCategoryRouter(mockUseCase)
+
+	mockUseCase.On("ListContent", mock.Anything, "missing", 10, 0, content.Viewer{}).Return([]*content.Content(nil), category.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/categories/missing/contents", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
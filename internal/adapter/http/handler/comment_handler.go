@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/comment"
+	commentusecase "github.com/mashurimansur/goCMS/internal/usecase/comment"
+)
+
+// maxBulkComments caps the number of comments a single bulk moderation request may touch.
+const maxBulkComments = 500
+
+// CommentHandler exposes HTTP endpoints for reader comments and their moderation.
+type CommentHandler struct {
+	commentUseCase commentusecase.UseCase
+}
+
+func NewCommentHandler(commentUseCase commentusecase.UseCase) *CommentHandler {
+	return &CommentHandler{
+		commentUseCase: commentUseCase,
+	}
+}
+
+// Register mounts the public comment routes, which accept both anonymous and signed-in readers
+// through readerMiddleware, and the admin moderation routes behind authMiddleware.
+func (h *CommentHandler) Register(router *gin.RouterGroup, authMiddleware, readerMiddleware gin.HandlerFunc) {
+	comments := router.Group("/comments")
+	comments.Use(readerMiddleware)
+	{
+		comments.GET("", h.thread)
+		comments.POST("", h.submit)
+	}
+
+	admin := router.Group("/admin/comments")
+	admin.Use(authMiddleware)
+	{
+		admin.GET("", h.list)
+		admin.GET("/:id", h.get)
+		admin.POST("/bulk", h.bulk)
+		admin.DELETE("/:id", h.delete)
+	}
+}
+
+type commentRequest struct {
+	ContentID   string `json:"content_id" binding:"required"`
+	ParentID    string `json:"parent_id"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Body        string `json:"body" binding:"required"`
+}
+
+type bulkCommentRequest struct {
+	IDs    []string `json:"ids" binding:"required,min=1"`
+	Action string   `json:"action" binding:"required"`
+}
+
+type bulkCommentResponse struct {
+	Action   string `json:"action"`
+	Affected int64  `json:"affected"`
+}
+
+// @Summary      Submit comment
+// @Description  Comment on a published entry, optionally replying to an approved comment. Anonymous readers must give a name and email; signed-in readers comment under their account. The response status tells whether the comment awaits moderation
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        request body commentRequest true "Comment Request"
+// @Success      201  {object}  comment.Comment
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /comments [post]
+func (h *CommentHandler) submit(c *gin.Context) {
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cm := &comment.Comment{
+		ContentID:   req.ContentID,
+		ParentID:    req.ParentID,
+		AuthorID:    c.GetString("user_id"),
+		AuthorName:  req.AuthorName,
+		AuthorEmail: req.AuthorEmail,
+		Body:        req.Body,
+		IP:          c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+	}
+	if err := h.commentUseCase.Submit(c.Request.Context(), cm, viewer(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	cm.Redact()
+	c.JSON(http.StatusCreated, cm)
+}
+
+// @Summary      Get comment thread
+// @Description  List the approved comments of a published entry as a tree of replies
+// @Tags         comments
+// @Produce      json
+// @Param        content_id  query     string  true  "Content ID"
+// @Success      200  {array}   comment.Comment
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /comments [get]
+func (h *CommentHandler) thread(c *gin.Context) {
+	contentID := c.Query("content_id")
+	if contentID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content_id is required"})
+		return
+	}
+
+	thread, err := h.commentUseCase.Thread(c.Request.Context(), contentID, viewer(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+// @Summary      List comments
+// @Description  List comments newest first for moderation, optionally filtered by status and content
+// @Tags         comments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status      query     string  false  "Status (pending, approved, rejected, spam)"
+// @Param        content_id  query     string  false  "Content ID"
+// @Param        limit       query     int     false  "Limit"  default(10)
+// @Param        offset      query     int     false  "Offset" default(0)
+// @Success      200  {array}   comment.Comment
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/comments [get]
+func (h *CommentHandler) list(c *gin.Context) {
+	limit, offset := pagination(c)
+	comments, err := h.commentUseCase.List(c.Request.Context(), comment.ListFilter{
+		ContentID: c.Query("content_id"),
+		Status:    c.Query("status"),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// @Summary      Get comment
+// @Description  Get a comment by ID including its author details and spam score
+// @Tags         comments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Comment ID"
+// @Success      200  {object}  comment.Comment
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/comments/{id} [get]
+func (h *CommentHandler) get(c *gin.Context) {
+	cm, err := h.commentUseCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cm)
+}
+
+// @Summary      Bulk moderate comments
+// @Description  Approve, reject, mark as spam or delete several comments at once
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body bulkCommentRequest true "Bulk Moderation Request"
+// @Success      200  {object}  bulkCommentResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/comments/bulk [post]
+func (h *CommentHandler) bulk(c *gin.Context) {
+	var req bulkCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.IDs) > maxBulkComments {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "too many comments in one request"})
+		return
+	}
+
+	affected, err := h.commentUseCase.Moderate(c.Request.Context(), req.IDs, req.Action)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bulkCommentResponse{Action: req.Action, Affected: affected})
+}
+
+// @Summary      Delete comment
+// @Description  Move a comment and its replies to the trash
+// @Tags         comments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Comment ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/comments/{id} [delete]
+func (h *CommentHandler) delete(c *gin.Context) {
+	if err := h.commentUseCase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "comment deleted successfully"})
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/comment"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	commentusecase "github.com/mashurimansur/goCMS/internal/usecase/comment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCommentUseCase is a mock implementation of commentusecase.UseCase
+type MockCommentUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCommentUseCase) Submit(ctx context.Context, c *comment.Comment, viewer content.Viewer) error {
+	args := m.Called(ctx, c, viewer)
+	return args.Error(0)
+}
+
+func (m *MockCommentUseCase) Thread(ctx context.Context, contentID string, viewer content.Viewer) ([]*comment.Comment, error) {
+	args := m.Called(ctx, contentID, viewer)
+	return args.Get(0).([]*comment.Comment), args.Error(1)
+}
+
+func (m *MockCommentUseCase) Get(ctx context.Context, id string) (*comment.Comment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*comment.Comment), args.Error(1)
+}
+
+func (m *MockCommentUseCase) List(ctx context.Context, filter comment.ListFilter) ([]*comment.Comment, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*comment.Comment), args.Error(1)
+}
+
+func (m *MockCommentUseCase) Moderate(ctx context.Context, ids []string, action string) (int64, error) {
+	args := m.Called(ctx, ids, action)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCommentUseCase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCommentUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockCommentUseCase) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCommentUseCase) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newCommentRouter(uc *MockCommentUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// Like router.NewGinEngine without trusted proxies, clients are known by their connection.
+	_ = router.SetTrustedProxies(nil)
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "author-1")
+		c.Next()
+	}
+	readerMiddleware := func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Set("user_id", "reader-1")
+		}
+		c.Next()
+	}
+	NewCommentHandler(uc).Register(router.Group("/api/v1"), authMiddleware, readerMiddleware)
+	return router
+}
+
+func TestCommentHandler_Submit_Anonymous(t *testing.T) {
+	mockUseCase := new(MockCommentUseCase)
+	router := newCommentRouter(mockUseCase)
+
+	body, _ := json.Marshal(commentRequest{ContentID: "post-1", AuthorName: "Ana", AuthorEmail: "ana@example.com", Body: "Nice post"})
+	mockUseCase.On("Submit", mock.Anything, mock.MatchedBy(func(c *comment.Comment) bool {
+		return c.AuthorID == "" && c.AuthorEmail == "ana@example.com" && c.IP == "192.0.2.1" && c.UserAgent == "test-agent"
+	}), content.Viewer{}).Run(func(args mock.Arguments) {
+		args.Get(1).(*comment.Comment).Status = comment.StatusPending
+	}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/comments", bytes.NewBuffer(body))
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("User-Agent", "test-agent")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp comment.Comment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, comment.StatusPending, resp.Status)
+	assert.Empty(t, resp.AuthorEmail)
+	assert.Empty(t, resp.IP)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestCommentHandler_Submit_SpoofedForwardedFor(t *testing.T) {
+	mockUseCase := new(MockCommentUseCase)
+	router := newCommentRouter(mockUseCase)
+
+	// The spam score counts recent comments by address, so every request must keep the address
+	// of its connection whatever X-Forwarded-For claims.
+	mockUseCase.On("Submit", mock.Anything, mock.MatchedBy(func(c *comment.Comment) bool {
+		return c.IP == "192.0.2.1"
+	}), content.Viewer{}).Return(nil).Twice()
+
+	for _, forwarded := range []string{"198.51.100.1", "198.51.100.2"} {
+		body, _ := json.Marshal(commentRequest{ContentID: "post-1", AuthorName: "Ana", AuthorEmail: "ana@example.com", Body: "Nice post"})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/comments", bytes.NewBuffer(body))
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-Forwarded-For", forwarded)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestCommentHandler_Submit_Authenticated(t *testing.T) {
+	mockUseCase := new(MockCommentUseCase)
+	router := newCommentRouter(mockUseCase)
+
+	body, _ := json.Marshal(commentRequest{ContentID: "post-1", Body: "Great"})
+	mockUseCase.On("Submit", mock.Anything, mock.MatchedBy(func(c *comment.Comment) bool {
+		return c.AuthorID == "reader-1"
+	}), content.Viewer{UserID: "reader-1"}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/comments", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer token")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestCommentHandler_Submit_Invalid(t *testing.T) {
+	mockUseCase := new(MockCommentUseCase)
+	router := newCommentRouter(mockUseCase)
+
+	body, _ := json.Marshal(commentRequest{ContentID: "post-1", Body: "Hi"})
+	mockUseCase.On("Submit", mock.Anything, mock.Anything, content.Viewer{}).Return(commentusecase.ErrInvalidComment)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/comments", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCommentHandler_Thread(t *testing.T) {
+	mockUseCase := new(MockCommentUseCase)
+	router := newCommentRouter(mockUseCase)
+
+	mockUseCase.On("Thread", mock.Anything, "post-1", content.Viewer{}).Return([]*comment.Comment{
+		{ID: "c-1", Body: "First", Replies: []*comment.Comment{{ID: "c-2", ParentID: "c-1", Body: "Reply"}}},
+	}, nil)
+	mockUseCase.On("Thread", mock.Anything, "draft-1", content.Viewer{}).Return([]*comment.Comment(nil), content.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/comments?content_id=post-1", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp []comment.Comment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	require.Len(t, resp[0].Replies, 1)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/comments?content_id=draft-1", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/comments", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCommentHandler_List(t *testing.T) {
+	mockUseCase := new(MockCommentUseCase)
+	router := newCommentRouter(mockUseCase)
+
+	mockUseCase.On("List", mock.Anything, comment.ListFilter{Status: comment.StatusPending, Limit: 10}).
+		Return([]*comment.Comment{{ID: "c-1", Status: comment.StatusPending, AuthorEmail: "ana@example.com"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/comments?status=pending", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "ana@example.com")
+}
+
+func TestCommentHandler_Bulk(t *testing.T) {
+	mockUseCase := new(MockCommentUseCase)
+	router := newCommentRouter(mockUseCase)
+
+	body, _ := json.Marshal(bulkCommentRequest{IDs: []string{"c-1", "c-2"}, Action: commentusecase.ActionSpam})
+	mockUseCase.On("Moderate", mock.Anything, []string{"c-1", "c-2"}, "spam").Return(int64(2), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/comments/bulk", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"action":"spam","affected":2}`, w.Body.String())
+
+	body, _ = json.Marshal(bulkCommentRequest{IDs: []string{"c-1"}, Action: "publish"})
+	mockUseCase.On("Moderate", mock.Anything, []string{"c-1"}, "publish").Return(int64(0), commentusecase.ErrInvalidAction)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/admin/comments/bulk", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/admin/comments/bulk", bytes.NewBufferString(`{"ids":[],"action":"approve"}`))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCommentHandler_Delete_NotFound(t *testing.T) {
+	mockUseCase := new(MockCommentUseCase)
+	router := newCommentRouter(mockUseCase)
+
+	mockUseCase.On("Delete", mock.Anything, "missing").Return(comment.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/admin/comments/missing", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
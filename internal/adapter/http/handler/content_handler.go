@@ -0,0 +1,508 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	"github.com/mashurimansur/goCMS/internal/utils/locale"
+)
+
+// ContentHandler exposes admin and public HTTP endpoints for content entries.
+type ContentHandler struct {
+	contentUseCase contentusecase.UseCase
+}
+
+func NewContentHandler(contentUseCase contentusecase.UseCase) *ContentHandler {
+	return &ContentHandler{
+		contentUseCase: contentUseCase,
+	}
+}
+
+// Register mounts the public content routes, which serve readers identified by readerMiddleware,
+// and the admin routes behind authMiddleware.
+func (h *ContentHandler) Register(router *gin.RouterGroup, authMiddleware, readerMiddleware gin.HandlerFunc) {
+	public := router.Group("/contents")
+	public.Use(readerMiddleware)
+	{
+		public.GET("", h.listPublished)
+		public.GET("/search", h.searchPublished)
+		public.GET("/:type/:slug", h.getPublished)
+	}
+
+	admin := router.Group("/admin/contents")
+	admin.Use(authMiddleware)
+	{
+		admin.POST("", h.create)
+		admin.GET("", h.list)
+		admin.GET("/missing-translations", h.missingTranslations)
+		admin.GET("/search", h.search)
+		admin.POST("/reindex", h.reindex)
+		admin.GET("/:id", h.get)
+		admin.GET("/:id/translations", h.translations)
+		admin.PUT("/:id", h.update)
+		admin.DELETE("/:id", h.delete)
+	}
+
+	router.GET("/admin/block-types", authMiddleware, h.blockTypes)
+	router.GET("/locales", h.locales)
+}
+
+type contentRequest struct {
+	Type          string        `json:"type"`
+	Locale        string        `json:"locale"`
+	TranslationOf string        `json:"translation_of"`
+	Title         string        `json:"title" binding:"required"`
+	Slug          string        `json:"slug"`
+	Excerpt       string        `json:"excerpt"`
+	Body          string        `json:"body"`
+	BodyFormat    string        `json:"body_format"`
+	Blocks        []block.Block `json:"blocks"`
+	Status        string        `json:"status"`
+	PublishedAt   time.Time     `json:"published_at"`
+	CategoryIDs   []string      `json:"category_ids"`
+	TagIDs        []string      `json:"tag_ids"`
+	// Visibility is one of public, authenticated, roles or password; it is kept on update when blank.
+	Visibility   string   `json:"visibility"`
+	AllowedRoles []string `json:"allowed_roles"`
+	// Password sets the password of a password-protected entry; it is kept on update when blank.
+	Password string `json:"password"`
+	Teaser   bool   `json:"teaser"`
+	// Version is the version being replaced on update; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+func (r contentRequest) toContent() *content.Content {
+	return &content.Content{
+		Type:          r.Type,
+		Locale:        r.Locale,
+		TranslationOf: r.TranslationOf,
+		Title:         r.Title,
+		Slug:          r.Slug,
+		Excerpt:       r.Excerpt,
+		Body:          r.Body,
+		BodyFormat:    r.BodyFormat,
+		Blocks:        r.Blocks,
+		Status:        r.Status,
+		PublishedAt:   r.PublishedAt,
+		Visibility:    r.Visibility,
+		AllowedRoles:  r.AllowedRoles,
+		Password:      r.Password,
+		Teaser:        r.Teaser,
+	}
+}
+
+type contentResponse struct {
+	*content.Content
+	*contentusecase.Taxonomy
+}
+
+// @Summary      Create content
+// @Description  Create a new content entry with optional category and tag assignments. Set translation_of to the ID of an entry in the same translation group to add a translation in another locale
+// @Tags         contents
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body contentRequest true "Content Request"
+// @Success      201  {object}  content.Content
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /admin/contents [post]
+func (h *ContentHandler) create(c *gin.Context) {
+	var req contentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry := req.toContent()
+	entry.AuthorID = c.GetString("user_id")
+	if err := h.contentUseCase.Create(c.Request.Context(), entry, req.CategoryIDs, req.TagIDs); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// @Summary      Get content
+// @Description  Get a content entry by ID including its categories and tags
+// @Tags         contents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Content ID"
+// @Success      200  {object}  contentResponse
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/{id} [get]
+func (h *ContentHandler) get(c *gin.Context) {
+	entry, err := h.contentUseCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	taxonomy, err := h.contentUseCase.GetTaxonomy(c.Request.Context(), entry.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, entry.Version)
+	c.JSON(http.StatusOK, contentResponse{Content: entry, Taxonomy: taxonomy})
+}
+
+// @Summary      Update content
+// @Description  Update a content entry. Omitted category_ids or tag_ids leave assignments untouched. The locale and translation group cannot change
+// @Tags         contents
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string          true   "Content ID"
+// @Param        If-Match  header  string          false  "ETag of the version being replaced"
+// @Param        request   body    contentRequest  true   "Content Request"
+// @Success      200  {object}  content.Content
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/{id} [put]
+func (h *ContentHandler) update(c *gin.Context) {
+	var req contentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	entry := req.toContent()
+	entry.ID = c.Param("id")
+	entry.Version = version
+	if err := h.contentUseCase.Update(c.Request.Context(), entry, req.CategoryIDs, req.TagIDs); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, entry.Version)
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary      Delete content
+// @Description  Move a content entry to the trash. Trashing a source entry also trashes its translations
+// @Tags         contents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Content ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/{id} [delete]
+func (h *ContentHandler) delete(c *gin.Context) {
+	if err := h.contentUseCase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "content deleted successfully"})
+}
+
+// @Summary      List content
+// @Description  List content entries of any status with pagination
+// @Tags         contents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type    query     string  false  "Content type"
+// @Param        locale  query     string  false  "Locale"
+// @Param        status  query     string  false  "Status"
+// @Param        limit   query     int     false  "Limit"  default(10)
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Success      200  {array}   content.Content
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents [get]
+func (h *ContentHandler) list(c *gin.Context) {
+	limit, offset := pagination(c)
+	entries, err := h.contentUseCase.List(c.Request.Context(), content.ListFilter{
+		Type:     c.Query("type"),
+		Locale:   c.Query("locale"),
+		Status:   c.Query("status"),
+		AuthorID: c.Query("author_id"),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// @Summary      List published content
+// @Description  List published content entries with pagination, each translation group once in the negotiated locale or the first available fallback. Entries the reader may not read are left out, except teasers, which are listed locked
+// @Tags         contents
+// @Produce      json
+// @Param        type    query     string  false  "Content type"
+// @Param        locale  query     string  false  "Locale, overriding Accept-Language"
+// @Param        limit   query     int     false  "Limit"  default(10)
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Success      200  {array}   content.Content
+// @Failure      500  {object}  map[string]string
+// @Router       /contents [get]
+func (h *ContentHandler) listPublished(c *gin.Context) {
+	limit, offset := pagination(c)
+	v := viewer(c)
+	entries, err := h.contentUseCase.ListPublished(c.Request.Context(), content.ListFilter{
+		Type:     c.Query("type"),
+		AuthorID: c.Query("author_id"),
+		Viewer:   &v,
+		Limit:    limit,
+		Offset:   offset,
+	}, h.localeChain(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// @Summary      Search published content
+// @Description  Full-text search over published entries, best matches first. Title matches weigh more than body matches; hits carry highlighted titles and snippets, and facets count the matches per type, category and author. Entries the reader may not read are left out, except teasers, whose snippets come from the excerpt
+// @Tags         contents
+// @Produce      json
+// @Param        q            query     string  true   "Search text"
+// @Param        type         query     string  false  "Content type"
+// @Param        locale       query     string  false  "Locale"
+// @Param        category_id  query     string  false  "Category ID"
+// @Param        author_id    query     string  false  "Author ID"
+// @Param        limit        query     int     false  "Limit"  default(10)
+// @Param        offset       query     int     false  "Offset" default(0)
+// @Success      200  {object}  content.SearchResult
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /contents/search [get]
+func (h *ContentHandler) searchPublished(c *gin.Context) {
+	q := searchQuery(c)
+	v := viewer(c)
+	q.Viewer = &v
+	result, err := h.contentUseCase.SearchPublished(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary      Search content
+// @Description  Full-text search over every entry regardless of status, with highlighted hits and facets by type, category and author
+// @Tags         contents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q            query     string  true   "Search text"
+// @Param        type         query     string  false  "Content type"
+// @Param        locale       query     string  false  "Locale"
+// @Param        status       query     string  false  "Status"
+// @Param        category_id  query     string  false  "Category ID"
+// @Param        author_id    query     string  false  "Author ID"
+// @Param        limit        query     int     false  "Limit"  default(10)
+// @Param        offset       query     int     false  "Offset" default(0)
+// @Success      200  {object}  content.SearchResult
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/search [get]
+func (h *ContentHandler) search(c *gin.Context) {
+	q := searchQuery(c)
+	q.Status = c.Query("status")
+	result, err := h.contentUseCase.Search(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary      Rebuild search index
+// @Description  Re-index every content entry, e.g. after switching search backends
+// @Tags         contents
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]int
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/reindex [post]
+func (h *ContentHandler) reindex(c *gin.Context) {
+	indexed, err := h.contentUseCase.Reindex(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"indexed": indexed})
+}
+
+// searchQuery reads the search text, filters and pagination shared by the search endpoints.
+func searchQuery(c *gin.Context) content.SearchQuery {
+	limit, offset := pagination(c)
+	return content.SearchQuery{
+		Text:       c.Query("q"),
+		Type:       c.Query("type"),
+		Locale:     c.Query("locale"),
+		CategoryID: c.Query("category_id"),
+		AuthorID:   c.Query("author_id"),
+		Limit:      limit,
+		Offset:     offset,
+	}
+}
+
+// @Summary      Get published content
+// @Description  Get a published content entry by type and slug. The entry is returned in the negotiated locale, or the first available fallback, when it has translations. Restricted entries are not found unless the reader may read them; teasers are returned locked, with the excerpt but no body
+// @Tags         contents
+// @Produce      json
+// @Param        type                path      string  true   "Content type"
+// @Param        slug                path      string  true   "Slug"
+// @Param        locale              query     string  false  "Locale, overriding Accept-Language"
+// @Param        X-Content-Password  header    string  false  "Password of a password-protected entry, also read from the content_password cookie"
+// @Success      200  {object}  contentResponse
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /contents/{type}/{slug} [get]
+func (h *ContentHandler) getPublished(c *gin.Context) {
+	entry, err := h.contentUseCase.GetPublished(c.Request.Context(), c.Param("type"), c.Param("slug"), h.localeChain(c), viewer(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Header("Content-Language", entry.Locale)
+
+	taxonomy, err := h.contentUseCase.GetTaxonomy(c.Request.Context(), entry.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contentResponse{Content: entry, Taxonomy: taxonomy})
+}
+
+// @Summary      List block types
+// @Description  List the block types available to structured bodies together with the JSON Schema of their data
+// @Tags         contents
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   block.TypeInfo
+// @Router       /admin/block-types [get]
+func (h *ContentHandler) blockTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, h.contentUseCase.BlockTypes())
+}
+
+// @Summary      List translations
+// @Description  List the translation group of a content entry, source entry first
+// @Tags         contents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Content ID"
+// @Success      200  {array}   content.Content
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/{id}/translations [get]
+func (h *ContentHandler) translations(c *gin.Context) {
+	group, err := h.contentUseCase.Translations(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// @Summary      List missing translations
+// @Description  List source entries that have no translation yet in the given locale, or in any configured locale
+// @Tags         contents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        locale  query     string  false  "Locale"
+// @Param        type    query     string  false  "Content type"
+// @Param        limit   query     int     false  "Limit"  default(10)
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Success      200  {array}   contentusecase.TranslationGap
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/missing-translations [get]
+func (h *ContentHandler) missingTranslations(c *gin.Context) {
+	limit, offset := pagination(c)
+	gaps, err := h.contentUseCase.MissingTranslations(c.Request.Context(), c.Query("locale"), content.ListFilter{
+		Type:   c.Query("type"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gaps)
+}
+
+type localesResponse struct {
+	Default string `json:"default"`
+	locale.Settings
+}
+
+// @Summary      List locales
+// @Description  List the content locales, the default locale and the configured fallback chains
+// @Tags         contents
+// @Produce      json
+// @Success      200  {object}  localesResponse
+// @Router       /locales [get]
+func (h *ContentHandler) locales(c *gin.Context) {
+	settings := h.contentUseCase.Locales()
+	c.JSON(http.StatusOK, localesResponse{Default: settings.Default(), Settings: settings})
+}
+
+// localeChain negotiates the locale of a public request from ?locale= and Accept-Language and returns
+// its fallback chain.
+func (h *ContentHandler) localeChain(c *gin.Context) []string {
+	settings := h.contentUseCase.Locales()
+	c.Header("Vary", "Accept-Language")
+	return settings.Chain(settings.Negotiate(c.Query("locale"), c.GetHeader("Accept-Language")))
+}
+
+// ResolvePermalink serves published content at its public permalink and permanently redirects
+// former permalinks. It is meant to be installed as the engine's NoRoute handler.
+func (h *ContentHandler) ResolvePermalink(c *gin.Context) {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+
+	entry, location, err := h.contentUseCase.Resolve(c.Request.Context(), c.Request.URL.Path, h.localeChain(c), viewer(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if location != "" {
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+	c.Header("Content-Language", entry.Locale)
+
+	taxonomy, err := h.contentUseCase.GetTaxonomy(c.Request.Context(), entry.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contentResponse{Content: entry, Taxonomy: taxonomy})
+}
@@ -0,0 +1,413 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	"github.com/mashurimansur/goCMS/internal/utils/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockContentUseCase is a mock implementation of contentusecase.UseCase
+type MockContentUseCase struct {
+	mock.Mock
+}
+
+func (m *MockContentUseCase) Create(ctx context.Context, c *content.Content, categoryIDs, tagIDs []string) error {
+	args := m.Called(ctx, c, categoryIDs, tagIDs)
+	return args.Error(0)
+}
+
+func (m *MockContentUseCase) Get(ctx context.Context, id string) (*content.Content, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func (m *MockContentUseCase) GetPublished(ctx context.Context, contentType, slug string, locales []string, viewer content.Viewer) (*content.Content, error) {
+	args := m.Called(ctx, contentType, slug, locales, viewer)
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func (m *MockContentUseCase) Update(ctx context.Context, c *content.Content, categoryIDs, tagIDs []string) error {
+	args := m.Called(ctx, c, categoryIDs, tagIDs)
+	return args.Error(0)
+}
+
+func (m *MockContentUseCase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockContentUseCase) List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockContentUseCase) ListPublished(ctx context.Context, filter content.ListFilter, locales []string) ([]*content.Content, error) {
+	args := m.Called(ctx, filter, locales)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockContentUseCase) GetTaxonomy(ctx context.Context, id string) (*contentusecase.Taxonomy, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*contentusecase.Taxonomy), args.Error(1)
+}
+
+func (m *MockContentUseCase) Resolve(ctx context.Context, path string, locales []string, viewer content.Viewer) (*content.Content, string, error) {
+	args := m.Called(ctx, path, locales, viewer)
+	return args.Get(0).(*content.Content), args.String(1), args.Error(2)
+}
+
+func (m *MockContentUseCase) Translations(ctx context.Context, id string) ([]*content.Content, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockContentUseCase) MissingTranslations(ctx context.Context, loc string, filter content.ListFilter) ([]*contentusecase.TranslationGap, error) {
+	args := m.Called(ctx, loc, filter)
+	return args.Get(0).([]*contentusecase.TranslationGap), args.Error(1)
+}
+
+func (m *MockContentUseCase) Search(ctx context.Context, q content.SearchQuery) (*content.SearchResult, error) {
+	args := m.Called(ctx, q)
+	return args.Get(0).(*content.SearchResult), args.Error(1)
+}
+
+func (m *MockContentUseCase) SearchPublished(ctx context.Context, q content.SearchQuery) (*content.SearchResult, error) {
+	args := m.Called(ctx, q)
+	return args.Get(0).(*content.SearchResult), args.Error(1)
+}
+
+func (m *MockContentUseCase) Reindex(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockContentUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockContentUseCase) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockContentUseCase) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockContentUseCase) Locales() locale.Settings {
+	return m.Called().Get(0).(locale.Settings)
+}
+
+func (m *MockContentUseCase) BlockTypes() []block.TypeInfo {
+	args := m.Called()
+	return args.Get(0).([]block.TypeInfo)
+}
+
+func newContentRouter(uc *MockContentUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "author-1")
+		c.Next()
+	}
+	readerMiddleware := func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Set("user_id", "reader-1")
+			c.Set("user_role", "editor")
+		}
+		c.Next()
+	}
+	uc.On("Locales").Return(locale.Settings{Locales: []string{"id", "en"}, Fallbacks: map[string][]string{}}).Maybe()
+	NewContentHandler(uc).Register(router.Group("/api/v1"), authMiddleware, readerMiddleware)
+	return router
+}
+
+func TestContentHandler_Create(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	body, _ := json.Marshal(contentRequest{Title: "Hello", Slug: "hello", CategoryIDs: []string{"news"}})
+	mockUseCase.On("Create", mock.Anything, mock.MatchedBy(func(c *content.Content) bool {
+		return c.Title == "Hello" && c.AuthorID == "author-1"
+	}), []string{"news"}, []string(nil)).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/contents", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestContentHandler_Create_InvalidBody(t *testing.T) {
+	router := newContentRouter(new(MockContentUseCase))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/contents", bytes.NewBufferString(`{"title":""}`))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestContentHandler_Create_InvalidBlock(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	body := `{"title":"Landing","body_format":"blocks","blocks":[{"type":"carousel","data":{}}]}`
+	mockUseCase.On("Create", mock.Anything, mock.MatchedBy(func(c *content.Content) bool {
+		return len(c.Blocks) == 1 && c.Blocks[0].Type == "carousel"
+	}), []string(nil), []string(nil)).Return(&block.Error{Index: 0, Type: "carousel", Err: block.ErrUnknownType})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/contents", bytes.NewBufferString(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "block 0 (carousel)")
+}
+
+func TestContentHandler_Create_InvalidVisibility(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	body := `{"title":"Members","visibility":"roles"}`
+	mockUseCase.On("Create", mock.Anything, mock.MatchedBy(func(c *content.Content) bool {
+		return c.Visibility == content.VisibilityRoles
+	}), []string(nil), []string(nil)).Return(fmt.Errorf("%w: allowed_roles is required", content.ErrInvalidVisibility))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/contents", bytes.NewBufferString(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid visibility")
+}
+
+func TestContentHandler_BlockTypes(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	mockUseCase.On("BlockTypes").Return([]block.TypeInfo{{Type: "paragraph", Schema: json.RawMessage(`{"type":"object"}`)}})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/block-types", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"type":"paragraph","schema":{"type":"object"}}]`, w.Body.String())
+}
+
+func TestContentHandler_Update_NotFound(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	body, _ := json.Marshal(contentRequest{Title: "Hello", Slug: "hello"})
+	mockUseCase.On("Update", mock.Anything, mock.AnythingOfType("*content.Content"), []string(nil), []string(nil)).Return(content.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/contents/missing", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestContentHandler_GetPublished(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	entry := &content.Content{ID: "content-1", Title: "Hello", Locale: "en"}
+	mockUseCase.On("GetPublished", mock.Anything, "post", "hello", []string{"en", "id"}, content.Viewer{}).Return(entry, nil)
+	mockUseCase.On("GetTaxonomy", mock.Anything, "content-1").Return(&contentusecase.Taxonomy{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/contents/post/hello", nil)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9,id;q=0.8")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "Hello", got["title"])
+	assert.Contains(t, got, "categories")
+}
+
+func TestContentHandler_GetPublished_Viewer(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	entry := &content.Content{ID: "content-1", Title: "Hello", Locale: "en", Excerpt: "Intro", Locked: true}
+	viewer := content.Viewer{UserID: "reader-1", Role: "editor", Password: "s3cret"}
+	mockUseCase.On("GetPublished", mock.Anything, "post", "hello", mock.Anything, viewer).Return(entry, nil)
+	mockUseCase.On("GetTaxonomy", mock.Anything, "content-1").Return(&contentusecase.Taxonomy{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/contents/post/hello", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.AddCookie(&http.Cookie{Name: contentPasswordCookie, Value: "s3cret"})
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "private", w.Header().Get("Cache-Control"))
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, true, got["locked"])
+	assert.NotContains(t, got, "password_hash")
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestContentHandler_ListPublished(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	mockUseCase.On("ListPublished", mock.Anything, content.ListFilter{Type: "page", Viewer: &content.Viewer{}, Limit: 100, Offset: 0}, []string{"en", "id"}).Return([]*content.Content{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/contents?type=page&limit=1000&offset=-4&locale=en", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestContentHandler_Delete_Error(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	mockUseCase.On("Delete", mock.Anything, "content-1").Return(assert.AnError)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/admin/contents/content-1", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestContentHandler_ResolvePermalink(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+	router.NoRoute(NewContentHandler(mockUseCase).ResolvePermalink)
+
+	entry := &content.Content{ID: "content-1", Title: "Hello"}
+	mockUseCase.On("Resolve", mock.Anything, "/2025/02/hello", []string{"id"}, content.Viewer{}).Return(entry, "", nil)
+	mockUseCase.On("Resolve", mock.Anything, "/2025/01/old", []string{"id"}, content.Viewer{}).Return((*content.Content)(nil), "/2025/02/hello", nil)
+	mockUseCase.On("Resolve", mock.Anything, "/missing", []string{"id"}, content.Viewer{}).Return((*content.Content)(nil), "", content.ErrNotFound)
+	mockUseCase.On("GetTaxonomy", mock.Anything, "content-1").Return(&contentusecase.Taxonomy{}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/2025/02/hello", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/2025/01/old?ref=rss", nil))
+	require.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/2025/02/hello?ref=rss", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/2025/02/hello", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestContentHandler_MissingTranslations(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	mockUseCase.On("MissingTranslations", mock.Anything, "en", content.ListFilter{Type: "post", Limit: 10}).Return([]*contentusecase.TranslationGap{
+		{ContentID: "content-1", Locale: "id", Missing: []string{"en"}},
+	}, nil)
+	mockUseCase.On("MissingTranslations", mock.Anything, "fr", mock.Anything).Return([]*contentusecase.TranslationGap(nil), contentusecase.ErrUnsupportedLocale)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/admin/contents/missing-translations?locale=en&type=post", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"missing":["en"]`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/admin/contents/missing-translations?locale=fr", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestContentHandler_Locales(t *testing.T) {
+	router := newContentRouter(new(MockContentUseCase))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/locales", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"default":"id","locales":["id","en"],"fallbacks":{}}`, w.Body.String())
+}
+
+func TestContentHandler_SearchPublished(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	result := &content.SearchResult{
+		Total: 1,
+		Hits:  []*content.SearchHit{{Content: &content.Content{ID: "content-1"}, Score: 2, Title: "<mark>Go</mark> tips"}},
+		Facets: map[string][]content.FacetValue{
+			content.FacetType: {{Value: "post", Count: 1}},
+		},
+	}
+	mockUseCase.On("SearchPublished", mock.Anything, content.SearchQuery{Text: "go", CategoryID: "news", Viewer: &content.Viewer{}, Limit: 5}).Return(result, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/contents/search?q=go&category_id=news&limit=5", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp content.SearchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, "<mark>Go</mark> tips", resp.Hits[0].Title)
+	assert.Equal(t, []content.FacetValue{{Value: "post", Count: 1}}, resp.Facets[content.FacetType])
+}
+
+func TestContentHandler_Search_EmptyQuery(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	mockUseCase.On("Search", mock.Anything, content.SearchQuery{Status: "draft", Limit: 10}).Return((*content.SearchResult)(nil), contentusecase.ErrEmptyQuery)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/contents/search?status=draft", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestContentHandler_Reindex(t *testing.T) {
+	mockUseCase := new(MockContentUseCase)
+	router := newContentRouter(mockUseCase)
+
+	mockUseCase.On("Reindex", mock.Anything).Return(42, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/contents/reindex", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"indexed":42}`, w.Body.String())
+}
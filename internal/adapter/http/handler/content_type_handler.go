@@ -0,0 +1,462 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+	contenttypeusecase "github.com/mashurimansur/goCMS/internal/usecase/contenttype"
+)
+
+// ContentTypeHandler exposes the content type builder and the generic entry endpoints that serve
+// every defined type.
+type ContentTypeHandler struct {
+	contentTypeUseCase contenttypeusecase.UseCase
+}
+
+func NewContentTypeHandler(contentTypeUseCase contenttypeusecase.UseCase) *ContentTypeHandler {
+	return &ContentTypeHandler{
+		contentTypeUseCase: contentTypeUseCase,
+	}
+}
+
+func (h *ContentTypeHandler) Register(router *gin.RouterGroup, authMiddleware, readerMiddleware gin.HandlerFunc) {
+	public := router.Group("/entries")
+	public.Use(readerMiddleware)
+	{
+		public.GET("/:type", h.listPublishedEntries)
+		public.GET("/:type/:id", h.getPublishedEntry)
+	}
+
+	types := router.Group("/admin/content-types")
+	types.Use(authMiddleware)
+	{
+		types.POST("", h.createType)
+		types.GET("", h.listTypes)
+		types.GET("/:name", h.getType)
+		types.PUT("/:name", h.updateType)
+		types.DELETE("/:name", h.deleteType)
+	}
+
+	entries := router.Group("/admin/entries")
+	entries.Use(authMiddleware)
+	{
+		entries.POST("/:type", h.createEntry)
+		entries.GET("/:type", h.listEntries)
+		entries.GET("/:type/:id", h.getEntry)
+		entries.PUT("/:type/:id", h.updateEntry)
+		entries.DELETE("/:type/:id", h.deleteEntry)
+		entries.GET("/:type/:id/references", h.listReferences)
+	}
+}
+
+type contentTypeRequest struct {
+	Name        string              `json:"name"`
+	Label       string              `json:"label"`
+	Description string              `json:"description"`
+	Fields      []contenttype.Field `json:"fields" binding:"required"`
+	// Version is the version being replaced on update; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+func (r contentTypeRequest) toContentType() *contenttype.ContentType {
+	return &contenttype.ContentType{
+		Name:        r.Name,
+		Label:       r.Label,
+		Description: r.Description,
+		Fields:      r.Fields,
+	}
+}
+
+type entryRequest struct {
+	Status string `json:"status"`
+	// Visibility is one of public, authenticated, roles or password; it is kept on update when blank.
+	Visibility   string   `json:"visibility"`
+	AllowedRoles []string `json:"allowed_roles"`
+	// Password sets the password of a password-protected entry; it is kept on update when blank.
+	Password string         `json:"password"`
+	Data     map[string]any `json:"data" binding:"required"`
+	// Version is the version being replaced on update; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+func (r entryRequest) toEntry(contentType string) *contenttype.Entry {
+	return &contenttype.Entry{
+		Type:         contentType,
+		Status:       r.Status,
+		Visibility:   r.Visibility,
+		AllowedRoles: r.AllowedRoles,
+		Password:     r.Password,
+		Data:         r.Data,
+	}
+}
+
+// @Summary      Create content type
+// @Description  Define a content type with typed fields and validation rules. Indexed fields can be used to filter and sort entries
+// @Tags         content-types
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body contentTypeRequest true "Content Type Request"
+// @Success      201  {object}  contenttype.ContentType
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/content-types [post]
+func (h *ContentTypeHandler) createType(c *gin.Context) {
+	var req contentTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ct := req.toContentType()
+	if err := h.contentTypeUseCase.CreateType(c.Request.Context(), ct); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, ct)
+}
+
+// @Summary      List content types
+// @Description  List every content type definition
+// @Tags         content-types
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   contenttype.ContentType
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/content-types [get]
+func (h *ContentTypeHandler) listTypes(c *gin.Context) {
+	types, err := h.contentTypeUseCase.ListTypes(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types)
+}
+
+// @Summary      Get content type
+// @Description  Get a content type definition by name
+// @Tags         content-types
+// @Produce      json
+// @Security     BearerAuth
+// @Param        name  path      string  true  "Content type name"
+// @Success      200  {object}  contenttype.ContentType
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/content-types/{name} [get]
+func (h *ContentTypeHandler) getType(c *gin.Context) {
+	ct, err := h.contentTypeUseCase.GetType(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, ct.Version)
+	c.JSON(http.StatusOK, ct)
+}
+
+// @Summary      Update content type
+// @Description  Replace the label, description and fields of a content type. Existing entries are validated against the new fields when next saved
+// @Tags         content-types
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        name      path    string              true   "Content type name"
+// @Param        If-Match  header  string              false  "ETag of the version being replaced"
+// @Param        request   body    contentTypeRequest  true   "Content Type Request"
+// @Success      200  {object}  contenttype.ContentType
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/content-types/{name} [put]
+func (h *ContentTypeHandler) updateType(c *gin.Context) {
+	var req contentTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	ct := req.toContentType()
+	ct.Name = c.Param("name")
+	ct.Version = version
+	if err := h.contentTypeUseCase.UpdateType(c.Request.Context(), ct); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, ct.Version)
+	c.JSON(http.StatusOK, ct)
+}
+
+// @Summary      Delete content type
+// @Description  Delete a content type that has no entries, including entries in the trash
+// @Tags         content-types
+// @Produce      json
+// @Security     BearerAuth
+// @Param        name  path      string  true  "Content type name"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/content-types/{name} [delete]
+func (h *ContentTypeHandler) deleteType(c *gin.Context) {
+	if err := h.contentTypeUseCase.DeleteType(c.Request.Context(), c.Param("name")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "content type deleted successfully"})
+}
+
+// @Summary      Create entry
+// @Description  Create an entry of a content type. Data is validated against the type's fields
+// @Tags         entries
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type     path  string        true  "Content type name"
+// @Param        request  body  entryRequest  true  "Entry Request"
+// @Success      201  {object}  contenttype.Entry
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/entries/{type} [post]
+func (h *ContentTypeHandler) createEntry(c *gin.Context) {
+	var req entryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry := req.toEntry(c.Param("type"))
+	entry.AuthorID = c.GetString("user_id")
+	if err := h.contentTypeUseCase.CreateEntry(c.Request.Context(), entry); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// @Summary      List entries
+// @Description  List entries of a content type of any status. Indexed fields can be filtered with filter[field]=value and sorted with sort=field or sort=-field
+// @Tags         entries
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type    path      string  true   "Content type name"
+// @Param        status  query     string  false  "Status"
+// @Param        sort    query     string  false  "Indexed field to sort by, prefixed with - for descending order"
+// @Param        limit   query     int     false  "Limit"  default(10)
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Param        include query     string  false  "Comma-separated reference fields to embed, e.g. author,related.author"
+// @Success      200  {array}   contenttype.Entry
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/entries/{type} [get]
+func (h *ContentTypeHandler) listEntries(c *gin.Context) {
+	h.respondEntries(c, c.Query("status"), nil)
+}
+
+// @Summary      Get entry
+// @Description  Get an entry of a content type by ID
+// @Tags         entries
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Content type name"
+// @Param        id    path      string  true  "Entry ID"
+// @Param        include query     string  false  "Comma-separated reference fields to embed, e.g. author,related.author"
+// @Success      200  {object}  contenttype.Entry
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/entries/{type}/{id} [get]
+func (h *ContentTypeHandler) getEntry(c *gin.Context) {
+	entry, err := h.contentTypeUseCase.GetEntry(c.Request.Context(), c.Param("type"), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := h.include(c, []*contenttype.Entry{entry}, nil); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, entry.Version)
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary      Update entry
+// @Description  Replace the data of an entry. An omitted status keeps the current one
+// @Tags         entries
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type      path    string        true   "Content type name"
+// @Param        id        path    string        true   "Entry ID"
+// @Param        If-Match  header  string        false  "ETag of the version being replaced"
+// @Param        request   body    entryRequest  true   "Entry Request"
+// @Success      200  {object}  contenttype.Entry
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/entries/{type}/{id} [put]
+func (h *ContentTypeHandler) updateEntry(c *gin.Context) {
+	var req entryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	entry := req.toEntry(c.Param("type"))
+	entry.ID = c.Param("id")
+	entry.Version = version
+	if err := h.contentTypeUseCase.UpdateEntry(c.Request.Context(), entry); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, entry.Version)
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary      Delete entry
+// @Description  Move an entry of a content type to the trash. References pointing at the entry are handled by their on_delete rule: restrict refuses the delete, cascade trashes the referencing entry and set_null clears the reference
+// @Tags         entries
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Content type name"
+// @Param        id    path      string  true  "Entry ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /admin/entries/{type}/{id} [delete]
+func (h *ContentTypeHandler) deleteEntry(c *gin.Context) {
+	if err := h.contentTypeUseCase.DeleteEntry(c.Request.Context(), c.Param("type"), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "entry deleted successfully"})
+}
+
+// @Summary      List entry references
+// @Description  List the references pointing at an entry ("what links here")
+// @Tags         entries
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Content type name"
+// @Param        id    path      string  true  "Entry ID"
+// @Success      200  {array}   contenttype.Reference
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/entries/{type}/{id}/references [get]
+func (h *ContentTypeHandler) listReferences(c *gin.Context) {
+	refs, err := h.contentTypeUseCase.ListReferences(c.Request.Context(), c.Param("type"), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if refs == nil {
+		refs = []contenttype.Reference{}
+	}
+
+	c.JSON(http.StatusOK, refs)
+}
+
+// @Summary      List published entries
+// @Description  List the published entries of a content type the reader may see. Indexed fields can be filtered with filter[field]=value and sorted with sort=field or sort=-field
+// @Tags         entries
+// @Produce      json
+// @Param        type    path      string  true   "Content type name"
+// @Param        sort    query     string  false  "Indexed field to sort by, prefixed with - for descending order"
+// @Param        limit   query     int     false  "Limit"  default(10)
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Param        include query     string  false  "Comma-separated reference fields to embed, e.g. author,related.author"
+// @Success      200  {array}   contenttype.Entry
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /entries/{type} [get]
+func (h *ContentTypeHandler) listPublishedEntries(c *gin.Context) {
+	v := viewer(c).WithoutPassword()
+	h.respondEntries(c, contenttype.StatusPublished, &v)
+}
+
+// @Summary      Get published entry
+// @Description  Get a published entry of a content type by ID. Password-protected entries need the X-Content-Password header or content_password cookie
+// @Tags         entries
+// @Produce      json
+// @Param        type  path      string  true  "Content type name"
+// @Param        id    path      string  true  "Entry ID"
+// @Param        include query     string  false  "Comma-separated reference fields to embed, e.g. author,related.author"
+// @Success      200  {object}  contenttype.Entry
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /entries/{type}/{id} [get]
+func (h *ContentTypeHandler) getPublishedEntry(c *gin.Context) {
+	v := viewer(c)
+	entry, err := h.contentTypeUseCase.GetPublishedEntry(c.Request.Context(), c.Param("type"), c.Param("id"), v)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := h.include(c, []*contenttype.Entry{entry}, &v); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// respondEntries lists entries for a reader, or for an editor when v is nil.
+func (h *ContentTypeHandler) respondEntries(c *gin.Context, status string, v *content.Viewer) {
+	limit, offset := pagination(c)
+	entries, err := h.contentTypeUseCase.ListEntries(c.Request.Context(), c.Param("type"), contenttypeusecase.ListQuery{
+		Status:  status,
+		Filters: c.QueryMap("filter"),
+		Sort:    c.Query("sort"),
+		Limit:   limit,
+		Offset:  offset,
+		Viewer:  v,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := h.include(c, entries, v); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// include embeds the referenced entries named by the include query parameter.
+func (h *ContentTypeHandler) include(c *gin.Context, entries []*contenttype.Entry, v *content.Viewer) error {
+	include := c.Query("include")
+	if include == "" {
+		return nil
+	}
+	return h.contentTypeUseCase.IncludeReferences(c.Request.Context(), entries, strings.Split(include, ","), v)
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	contenttypeusecase "github.com/mashurimansur/goCMS/internal/usecase/contenttype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockContentTypeUseCase struct {
+	mock.Mock
+}
+
+func (m *MockContentTypeUseCase) CreateType(ctx context.Context, ct *contenttype.ContentType) error {
+	return m.Called(ctx, ct).Error(0)
+}
+
+func (m *MockContentTypeUseCase) GetType(ctx context.Context, name string) (*contenttype.ContentType, error) {
+	args := m.Called(ctx, name)
+	ct, _ := args.Get(0).(*contenttype.ContentType)
+	return ct, args.Error(1)
+}
+
+func (m *MockContentTypeUseCase) UpdateType(ctx context.Context, ct *contenttype.ContentType) error {
+	return m.Called(ctx, ct).Error(0)
+}
+
+func (m *MockContentTypeUseCase) DeleteType(ctx context.Context, name string) error {
+	return m.Called(ctx, name).Error(0)
+}
+
+func (m *MockContentTypeUseCase) ListTypes(ctx context.Context) ([]*contenttype.ContentType, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*contenttype.ContentType), args.Error(1)
+}
+
+func (m *MockContentTypeUseCase) CreateEntry(ctx context.Context, e *contenttype.Entry) error {
+	return m.Called(ctx, e).Error(0)
+}
+
+func (m *MockContentTypeUseCase) GetEntry(ctx context.Context, contentType, id string) (*contenttype.Entry, error) {
+	args := m.Called(ctx, contentType, id)
+	e, _ := args.Get(0).(*contenttype.Entry)
+	return e, args.Error(1)
+}
+
+func (m *MockContentTypeUseCase) GetPublishedEntry(ctx context.Context, contentType, id string, viewer content.Viewer) (*contenttype.Entry, error) {
+	args := m.Called(ctx, contentType, id, viewer)
+	e, _ := args.Get(0).(*contenttype.Entry)
+	return e, args.Error(1)
+}
+
+func (m *MockContentTypeUseCase) UpdateEntry(ctx context.Context, e *contenttype.Entry) error {
+	return m.Called(ctx, e).Error(0)
+}
+
+func (m *MockContentTypeUseCase) DeleteEntry(ctx context.Context, contentType, id string) error {
+	return m.Called(ctx, contentType, id).Error(0)
+}
+
+func (m *MockContentTypeUseCase) ListEntries(ctx context.Context, contentType string, query contenttypeusecase.ListQuery) ([]*contenttype.Entry, error) {
+	args := m.Called(ctx, contentType, query)
+	return args.Get(0).([]*contenttype.Entry), args.Error(1)
+}
+
+func (m *MockContentTypeUseCase) ListReferences(ctx context.Context, contentType, id string) ([]contenttype.Reference, error) {
+	args := m.Called(ctx, contentType, id)
+	refs, _ := args.Get(0).([]contenttype.Reference)
+	return refs, args.Error(1)
+}
+
+func (m *MockContentTypeUseCase) IncludeReferences(ctx context.Context, entries []*contenttype.Entry, include []string, viewer *content.Viewer) error {
+	return m.Called(ctx, entries, include, viewer).Error(0)
+}
+
+func (m *MockContentTypeUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockContentTypeUseCase) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockContentTypeUseCase) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newContentTypeRouter(uc *MockContentTypeUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "author-1")
+		c.Next()
+	}
+	readerMiddleware := func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Set("user_id", "reader-1")
+			c.Set("user_role", "editor")
+		}
+		c.Next()
+	}
+	NewContentTypeHandler(uc).Register(router.Group("/api/v1"), authMiddleware, readerMiddleware)
+	return router
+}
+
+func TestContentTypeHandler_CreateType(t *testing.T) {
+	mockUseCase := new(MockContentTypeUseCase)
+	router := newContentTypeRouter(mockUseCase)
+
+	body := `{"name":"product","fields":[{"name":"title","type":"text","required":true}]}`
+	mockUseCase.On("CreateType", mock.Anything, mock.MatchedBy(func(ct *contenttype.ContentType) bool {
+		return ct.Name == "product" && len(ct.Fields) == 1 && ct.Fields[0].Required
+	})).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/content-types", bytes.NewBufferString(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestContentTypeHandler_DeleteType_InUse(t *testing.T) {
+	mockUseCase := new(MockContentTypeUseCase)
+	router := newContentTypeRouter(mockUseCase)
+
+	mockUseCase.On("DeleteType", mock.Anything, "product").Return(contenttypeusecase.ErrTypeInUse)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/admin/content-types/product", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestContentTypeHandler_CreateEntry_ValidationError(t *testing.T) {
+	mockUseCase := new(MockContentTypeUseCase)
+	router := newContentTypeRouter(mockUseCase)
+
+	mockUseCase.On("CreateEntry", mock.Anything, mock.MatchedBy(func(e *contenttype.Entry) bool {
+		return e.Type == "product" && e.AuthorID == "author-1"
+	})).Return(&contenttype.ValidationError{Fields: map[string]string{"title": "is required"}})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/entries/product", bytes.NewBufferString(`{"data":{}}`))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"invalid entry: title is required","fields":{"title":"is required"}}`, w.Body.String())
+}
+
+func TestContentTypeHandler_ListPublishedEntries(t *testing.T) {
+	mockUseCase := new(MockContentTypeUseCase)
+	router := newContentTypeRouter(mockUseCase)
+
+	mockUseCase.On("ListEntries", mock.Anything, "product", contenttypeusecase.ListQuery{
+		Status:  contenttype.StatusPublished,
+		Filters: map[string]string{"color": "red"},
+		Sort:    "-price",
+		Limit:   5,
+		Viewer:  &content.Viewer{UserID: "reader-1", Role: "editor"},
+	}).Return([]*contenttype.Entry{{ID: "entry-1"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/entries/product?filter[color]=red&sort=-price&limit=5", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("X-Content-Password", "secret")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "private", w.Header().Get("Cache-Control"))
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestContentTypeHandler_GetPublishedEntry_NotFound(t *testing.T) {
+	mockUseCase := new(MockContentTypeUseCase)
+	router := newContentTypeRouter(mockUseCase)
+
+	mockUseCase.On("GetPublishedEntry", mock.Anything, "product", "missing", content.Viewer{}).Return(nil, contenttype.ErrEntryNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/entries/product/missing", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestContentTypeHandler_GetPublishedEntry_Include(t *testing.T) {
+	mockUseCase := new(MockContentTypeUseCase)
+	router := newContentTypeRouter(mockUseCase)
+
+	entry := &contenttype.Entry{ID: "a-1", Type: "article", Status: contenttype.StatusPublished}
+	viewer := content.Viewer{Password: "secret"}
+	mockUseCase.On("GetPublishedEntry", mock.Anything, "article", "a-1", viewer).Return(entry, nil)
+	mockUseCase.On("IncludeReferences", mock.Anything, []*contenttype.Entry{entry}, []string{"author", "related.author"}, &viewer).
+		Run(func(args mock.Arguments) {
+			entry.Included = map[string]any{"author": &contenttype.Entry{ID: "p-1"}}
+		}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/entries/article/a-1?include=author,related.author", nil)
+	req.Header.Set("X-Content-Password", "secret")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"included":{"author":{"id":"p-1"`)
+}
+
+func TestContentTypeHandler_DeleteEntry_Referenced(t *testing.T) {
+	mockUseCase := new(MockContentTypeUseCase)
+	router := newContentTypeRouter(mockUseCase)
+
+	mockUseCase.On("DeleteEntry", mock.Anything, "person", "p-1").Return(contenttypeusecase.ErrReferenced)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/admin/entries/person/p-1", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestContentTypeHandler_ListReferences(t *testing.T) {
+	mockUseCase := new(MockContentTypeUseCase)
+	router := newContentTypeRouter(mockUseCase)
+
+	mockUseCase.On("ListReferences", mock.Anything, "person", "p-1").Return([]contenttype.Reference{
+		{SourceType: "article", SourceID: "a-1", Field: "author", TargetType: "person", TargetID: "p-1"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/entries/person/p-1/references", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"source_id":"a-1"`)
+}
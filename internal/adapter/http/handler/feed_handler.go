@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	feedusecase "github.com/mashurimansur/goCMS/internal/usecase/feed"
+	"github.com/mashurimansur/goCMS/internal/utils/syndication"
+)
+
+// Feed formats, by the file name they are served under.
+const (
+	rssFile  = "feed.xml"
+	atomFile = "atom.xml"
+)
+
+// FeedHandler serves RSS and Atom feeds of the published content at the root of the site.
+type FeedHandler struct {
+	feedUseCase feedusecase.UseCase
+}
+
+func NewFeedHandler(feedUseCase feedusecase.UseCase) *FeedHandler {
+	return &FeedHandler{
+		feedUseCase: feedUseCase,
+	}
+}
+
+func (h *FeedHandler) Register(router gin.IRoutes) {
+	router.GET("/"+rssFile, h.site(rssFile))
+	router.GET("/"+atomFile, h.site(atomFile))
+	router.GET("/feeds/category/:slug/:file", h.scoped(h.feedUseCase.Category))
+	router.GET("/feeds/tag/:slug/:file", h.scoped(h.feedUseCase.Tag))
+	router.GET("/feeds/author/:slug/:file", h.scoped(h.feedUseCase.Author))
+}
+
+// @Summary      Site feed
+// @Description  The latest published entries as RSS 2.0 (/feed.xml) or Atom 1.0 (/atom.xml). Supports conditional requests via ETag and Last-Modified.
+// @Tags         feeds
+// @Produce      xml
+// @Success      200
+// @Success      304
+// @Failure      500  {object}  map[string]string
+// @Router       /feed.xml [get]
+// @Router       /atom.xml [get]
+func (h *FeedHandler) site(file string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		f, err := h.feedUseCase.Site(c.Request.Context())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		writeFeed(c, file, f)
+	}
+}
+
+// @Summary      Scoped feed
+// @Description  The latest published entries of a category (including its descendants), a tag or an author, as RSS 2.0 (feed.xml) or Atom 1.0 (atom.xml)
+// @Tags         feeds
+// @Produce      xml
+// @Param        slug  path  string  true  "Category or tag slug, or author username"
+// @Param        file  path  string  true  "feed.xml or atom.xml"
+// @Success      200
+// @Success      304
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /feeds/category/{slug}/{file} [get]
+// @Router       /feeds/tag/{slug}/{file} [get]
+// @Router       /feeds/author/{slug}/{file} [get]
+func (h *FeedHandler) scoped(load func(ctx context.Context, slug string) (*syndication.Feed, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		file := c.Param("file")
+		if file != rssFile && file != atomFile {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown feed format"})
+			return
+		}
+
+		f, err := load(c.Request.Context(), c.Param("slug"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		writeFeed(c, file, f)
+	}
+}
+
+// writeFeed encodes f in the format of file. Feeds are polled often, so the response carries a
+// content ETag and the time of the latest change to let readers revalidate cheaply.
+func writeFeed(c *gin.Context, file string, f *syndication.Feed) {
+	encode, contentType := syndication.RSS, "application/rss+xml; charset=utf-8"
+	if file == atomFile {
+		encode, contentType = syndication.Atom, "application/atom+xml; charset=utf-8"
+	}
+	body, err := encode(f)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	header := c.Writer.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("Cache-Control", "public, max-age=300")
+	header.Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+
+	http.ServeContent(c.Writer, c.Request, "", f.Updated, bytes.NewReader(body))
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	feedusecase "github.com/mashurimansur/goCMS/internal/usecase/feed"
+	"github.com/mashurimansur/goCMS/internal/utils/syndication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockFeedUseCase is a mock implementation of feedusecase.UseCase
+type MockFeedUseCase struct {
+	mock.Mock
+}
+
+func (m *MockFeedUseCase) Site(ctx context.Context) (*syndication.Feed, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*syndication.Feed), args.Error(1)
+}
+
+func (m *MockFeedUseCase) Category(ctx context.Context, slug string) (*syndication.Feed, error) {
+	args := m.Called(ctx, slug)
+	return args.Get(0).(*syndication.Feed), args.Error(1)
+}
+
+func (m *MockFeedUseCase) Tag(ctx context.Context, slug string) (*syndication.Feed, error) {
+	args := m.Called(ctx, slug)
+	return args.Get(0).(*syndication.Feed), args.Error(1)
+}
+
+func (m *MockFeedUseCase) Author(ctx context.Context, username string) (*syndication.Feed, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(*syndication.Feed), args.Error(1)
+}
+
+func newFeedRouter(uc *MockFeedUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewFeedHandler(uc).Register(router)
+	return router
+}
+
+func testSiteFeed() *syndication.Feed {
+	updated := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	return &syndication.Feed{
+		Title:   "Example",
+		Link:    "https://example.com/",
+		RSSURL:  "https://example.com/feed.xml",
+		AtomURL: "https://example.com/atom.xml",
+		Updated: updated,
+		Items: []syndication.Item{
+			{ID: "urn:uuid:post-1", Title: "Launch", Link: "https://example.com/launch", Published: updated, Updated: updated},
+		},
+	}
+}
+
+func TestFeedHandler_RSS(t *testing.T) {
+	mockUseCase := new(MockFeedUseCase)
+	router := newFeedRouter(mockUseCase)
+
+	mockUseCase.On("Site", mock.Anything).Return(testSiteFeed(), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/feed.xml", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Sun, 09 Mar 2025 10:00:00 GMT", w.Header().Get("Last-Modified"))
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), `<rss version="2.0"`)
+	assert.Contains(t, w.Body.String(), "<title>Launch</title>")
+}
+
+func TestFeedHandler_Atom(t *testing.T) {
+	mockUseCase := new(MockFeedUseCase)
+	router := newFeedRouter(mockUseCase)
+
+	mockUseCase.On("Site", mock.Anything).Return(testSiteFeed(), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/atom.xml", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `<feed xmlns="http://www.w3.org/2005/Atom">`)
+}
+
+func TestFeedHandler_NotModified(t *testing.T) {
+	mockUseCase := new(MockFeedUseCase)
+	router := newFeedRouter(mockUseCase)
+
+	mockUseCase.On("Site", mock.Anything).Return(testSiteFeed(), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/feed.xml", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/feed.xml", nil)
+	req.Header.Set("If-None-Match", etag)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/feed.xml", nil)
+	req.Header.Set("If-Modified-Since", "Sun, 09 Mar 2025 10:00:00 GMT")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/feed.xml", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestFeedHandler_Scoped(t *testing.T) {
+	mockUseCase := new(MockFeedUseCase)
+	router := newFeedRouter(mockUseCase)
+
+	mockUseCase.On("Category", mock.Anything, "news").Return(testSiteFeed(), nil)
+	mockUseCase.On("Tag", mock.Anything, "go").Return(testSiteFeed(), nil)
+	mockUseCase.On("Author", mock.Anything, "ana").Return(testSiteFeed(), nil)
+
+	for _, path := range []string{"/feeds/category/news/feed.xml", "/feeds/tag/go/atom.xml", "/feeds/author/ana/feed.xml"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestFeedHandler_Scoped_NotFound(t *testing.T) {
+	mockUseCase := new(MockFeedUseCase)
+	router := newFeedRouter(mockUseCase)
+
+	mockUseCase.On("Category", mock.Anything, "missing").Return((*syndication.Feed)(nil), category.ErrNotFound)
+	mockUseCase.On("Author", mock.Anything, "nobody").Return((*syndication.Feed)(nil), feedusecase.ErrUnknownAuthor)
+
+	for _, path := range []string{"/feeds/category/missing/feed.xml", "/feeds/author/nobody/atom.xml", "/feeds/tag/go/feed.json"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
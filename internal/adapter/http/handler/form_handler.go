@@ -0,0 +1,382 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/form"
+	formusecase "github.com/mashurimansur/goCMS/internal/usecase/form"
+)
+
+const (
+	// maxSubmissionSize caps the body of a public form submission in bytes.
+	maxSubmissionSize = 64 << 10
+	// defaultSuccessMessage answers submissions to forms without a success message.
+	defaultSuccessMessage = "Thank you, your submission has been received."
+)
+
+// FormHandler exposes HTTP endpoints for public form submissions and the admin form builder.
+type FormHandler struct {
+	formUseCase formusecase.UseCase
+}
+
+func NewFormHandler(formUseCase formusecase.UseCase) *FormHandler {
+	return &FormHandler{
+		formUseCase: formUseCase,
+	}
+}
+
+func (h *FormHandler) Register(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	forms := router.Group("/forms")
+	{
+		forms.GET("/:name", h.definition)
+		forms.POST("/:name/submissions", h.submit)
+	}
+
+	admin := router.Group("/admin/forms")
+	admin.Use(authMiddleware)
+	{
+		admin.POST("", h.create)
+		admin.GET("", h.list)
+		admin.GET("/:id", h.get)
+		admin.PUT("/:id", h.update)
+		admin.DELETE("/:id", h.delete)
+		admin.GET("/:id/submissions", h.submissions)
+		admin.GET("/:id/submissions/export", h.export)
+		admin.DELETE("/:id/submissions/:submission_id", h.deleteSubmission)
+	}
+}
+
+type formRequest struct {
+	Name           string       `json:"name"`
+	Title          string       `json:"title"`
+	Description    string       `json:"description"`
+	Fields         []form.Field `json:"fields"`
+	SuccessMessage string       `json:"success_message"`
+	RedirectURL    string       `json:"redirect_url"`
+	NotifyURL      string       `json:"notify_url"`
+	RetentionDays  int          `json:"retention_days"`
+	// Version is the version being replaced on update; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+func (r formRequest) toForm() *form.Form {
+	return &form.Form{
+		Name:           r.Name,
+		Title:          r.Title,
+		Description:    r.Description,
+		Fields:         r.Fields,
+		SuccessMessage: r.SuccessMessage,
+		RedirectURL:    r.RedirectURL,
+		NotifyURL:      r.NotifyURL,
+		RetentionDays:  r.RetentionDays,
+	}
+}
+
+type submissionResponse struct {
+	Message string `json:"message"`
+}
+
+// @Summary      Get form
+// @Description  Get the fields and validation rules of a form to render it
+// @Tags         forms
+// @Produce      json
+// @Param        name  path      string  true  "Form name"
+// @Success      200  {object}  form.Form
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /forms/{name} [get]
+func (h *FormHandler) definition(c *gin.Context) {
+	f, err := h.formUseCase.Definition(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, f)
+}
+
+// @Summary      Submit form
+// @Description  Submit a form as JSON, URL-encoded or multipart data keyed by field name. Invalid values are reported per field. Plain HTML forms are redirected to the redirect URL of the form when it has one. Submissions filling the hidden honeypot field are answered as usual but discarded, and addresses submitting too often are rate limited.
+// @Tags         forms
+// @Accept       json
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        name     path      string             true  "Form name"
+// @Param        request  body      map[string]string  true  "Field values"
+// @Success      201  {object}  submissionResponse
+// @Success      303
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /forms/{name}/submissions [post]
+func (h *FormHandler) submit(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSubmissionSize)
+	isJSON := c.ContentType() == gin.MIMEJSON
+	values, err := submittedValues(c, isJSON)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s := &form.Submission{
+		Data:      values,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	f, err := h.formUseCase.Submit(c.Request.Context(), c.Param("name"), s)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if !isJSON && f.RedirectURL != "" {
+		c.Redirect(http.StatusSeeOther, f.RedirectURL)
+		return
+	}
+	message := f.SuccessMessage
+	if message == "" {
+		message = defaultSuccessMessage
+	}
+	c.JSON(http.StatusCreated, submissionResponse{Message: message})
+}
+
+// submittedValues reads the field values of a submission from a JSON object of scalars or from
+// form data, keeping the first value of repeated form keys.
+func submittedValues(c *gin.Context, isJSON bool) (map[string]string, error) {
+	if isJSON {
+		var raw map[string]any
+		if err := json.NewDecoder(c.Request.Body).Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON body: %w", err)
+		}
+		values := make(map[string]string, len(raw))
+		for key, value := range raw {
+			switch v := value.(type) {
+			case nil:
+			case string:
+				values[key] = v
+			case bool, float64:
+				values[key] = fmt.Sprint(v)
+			default:
+				return nil, fmt.Errorf("value of %q must be a string, number or boolean", key)
+			}
+		}
+		return values, nil
+	}
+
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		if err := c.Request.ParseMultipartForm(maxSubmissionSize); err != nil {
+			return nil, fmt.Errorf("invalid form data: %w", err)
+		}
+	} else if err := c.Request.ParseForm(); err != nil {
+		return nil, fmt.Errorf("invalid form data: %w", err)
+	}
+	values := make(map[string]string, len(c.Request.PostForm))
+	for key, all := range c.Request.PostForm {
+		if len(all) > 0 {
+			values[key] = all[0]
+		}
+	}
+	return values, nil
+}
+
+// @Summary      Create form
+// @Description  Define a form with its fields, validation rules, success message or redirect, optional notification URL and submission retention in days
+// @Tags         forms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body formRequest true "Form Request"
+// @Success      201  {object}  form.Form
+// @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/forms [post]
+func (h *FormHandler) create(c *gin.Context) {
+	var req formRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	f := req.toForm()
+	if err := h.formUseCase.Create(c.Request.Context(), f); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, f.Version)
+	c.JSON(http.StatusCreated, f)
+}
+
+// @Summary      List forms
+// @Description  List every form ordered by name
+// @Tags         forms
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   form.Form
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/forms [get]
+func (h *FormHandler) list(c *gin.Context) {
+	forms, err := h.formUseCase.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, forms)
+}
+
+// @Summary      Get form settings
+// @Description  Get a form by ID with its private settings
+// @Tags         forms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Form ID"
+// @Success      200  {object}  form.Form
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/forms/{id} [get]
+func (h *FormHandler) get(c *gin.Context) {
+	f, err := h.formUseCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, f.Version)
+	c.JSON(http.StatusOK, f)
+}
+
+// @Summary      Update form
+// @Description  Replace the definition and settings of a form. Stored submissions keep the values they were submitted with.
+// @Tags         forms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string       true   "Form ID"
+// @Param        If-Match  header  string       false  "ETag of the version being replaced"
+// @Param        request   body    formRequest  true   "Form Request"
+// @Success      200  {object}  form.Form
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/forms/{id} [put]
+func (h *FormHandler) update(c *gin.Context) {
+	var req formRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	f := req.toForm()
+	f.ID = c.Param("id")
+	f.Version = version
+	if err := h.formUseCase.Update(c.Request.Context(), f); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, f.Version)
+	c.JSON(http.StatusOK, f)
+}
+
+// @Summary      Delete form
+// @Description  Delete a form together with its submissions
+// @Tags         forms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Form ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/forms/{id} [delete]
+func (h *FormHandler) delete(c *gin.Context) {
+	if err := h.formUseCase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "form deleted successfully"})
+}
+
+// @Summary      List submissions
+// @Description  List the submissions of a form, newest first
+// @Tags         forms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string  true   "Form ID"
+// @Param        limit   query     int     false  "Limit"  default(10)
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Success      200  {array}   form.Submission
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/forms/{id}/submissions [get]
+func (h *FormHandler) submissions(c *gin.Context) {
+	limit, offset := pagination(c)
+	submissions, err := h.formUseCase.Submissions(c.Request.Context(), c.Param("id"), limit, offset)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, submissions)
+}
+
+// @Summary      Export submissions
+// @Description  Download every submission of a form as CSV, with a column per current field
+// @Tags         forms
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Form ID"
+// @Success      200  {string}  string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/forms/{id}/submissions/export [get]
+func (h *FormHandler) export(c *gin.Context) {
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/csv; charset=utf-8")
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "submissions-" + c.Param("id") + ".csv"}))
+
+	if err := h.formUseCase.Export(c.Request.Context(), c.Param("id"), c.Writer); err != nil {
+		if c.Writer.Written() {
+			// The CSV is already on its way; cutting it short is all that is left to do.
+			_ = c.Error(err)
+			return
+		}
+		header.Del("Content-Type")
+		header.Del("Content-Disposition")
+		respondError(c, err)
+	}
+}
+
+// @Summary      Delete submission
+// @Description  Delete a submission of a form
+// @Tags         forms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path      string  true  "Form ID"
+// @Param        submission_id  path      string  true  "Submission ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/forms/{id}/submissions/{submission_id} [delete]
+func (h *FormHandler) deleteSubmission(c *gin.Context) {
+	if err := h.formUseCase.DeleteSubmission(c.Request.Context(), c.Param("id"), c.Param("submission_id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "submission deleted successfully"})
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/form"
+	formusecase "github.com/mashurimansur/goCMS/internal/usecase/form"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockFormUseCase is a mock implementation of formusecase.UseCase
+type MockFormUseCase struct {
+	mock.Mock
+}
+
+func (m *MockFormUseCase) Create(ctx context.Context, f *form.Form) error {
+	args := m.Called(ctx, f)
+	return args.Error(0)
+}
+
+func (m *MockFormUseCase) Get(ctx context.Context, id string) (*form.Form, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*form.Form), args.Error(1)
+}
+
+func (m *MockFormUseCase) Update(ctx context.Context, f *form.Form) error {
+	args := m.Called(ctx, f)
+	return args.Error(0)
+}
+
+func (m *MockFormUseCase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockFormUseCase) List(ctx context.Context) ([]*form.Form, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*form.Form), args.Error(1)
+}
+
+func (m *MockFormUseCase) Definition(ctx context.Context, name string) (*form.Form, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(*form.Form), args.Error(1)
+}
+
+func (m *MockFormUseCase) Submit(ctx context.Context, name string, s *form.Submission) (*form.Form, error) {
+	args := m.Called(ctx, name, s)
+	return args.Get(0).(*form.Form), args.Error(1)
+}
+
+func (m *MockFormUseCase) Submissions(ctx context.Context, formID string, limit, offset int) ([]*form.Submission, error) {
+	args := m.Called(ctx, formID, limit, offset)
+	return args.Get(0).([]*form.Submission), args.Error(1)
+}
+
+func (m *MockFormUseCase) DeleteSubmission(ctx context.Context, formID, id string) error {
+	args := m.Called(ctx, formID, id)
+	return args.Error(0)
+}
+
+func (m *MockFormUseCase) Export(ctx context.Context, formID string, w io.Writer) error {
+	args := m.Called(ctx, formID, w)
+	return args.Error(0)
+}
+
+func (m *MockFormUseCase) PurgeExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newFormRouter(uc *MockFormUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// Like router.NewGinEngine without trusted proxies, clients are known by their connection.
+	_ = router.SetTrustedProxies(nil)
+	authMiddleware := func(c *gin.Context) { c.Next() }
+	NewFormHandler(uc).Register(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func submissionWith(values map[string]string) interface{} {
+	return mock.MatchedBy(func(s *form.Submission) bool {
+		return assert.ObjectsAreEqual(values, s.Data)
+	})
+}
+
+func TestFormHandler_Submit_JSON(t *testing.T) {
+	mockUseCase := new(MockFormUseCase)
+	router := newFormRouter(mockUseCase)
+
+	values := map[string]string{"email": "ana@example.com", "seats": "2", "consent": "true"}
+	mockUseCase.On("Submit", mock.Anything, "contact", submissionWith(values)).
+		Return(&form.Form{Name: "contact", SuccessMessage: "Thanks!", RedirectURL: "/thank-you"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/forms/contact/submissions", bytes.NewBufferString(`{"email":"ana@example.com","seats":2,"consent":true,"phone":null}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"message":"Thanks!"}`, w.Body.String())
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestFormHandler_Submit_SpoofedForwardedFor(t *testing.T) {
+	mockUseCase := new(MockFormUseCase)
+	router := newFormRouter(mockUseCase)
+
+	fromClient := mock.MatchedBy(func(s *form.Submission) bool { return s.IP == "203.0.113.9" })
+	mockUseCase.On("Submit", mock.Anything, "contact", fromClient).Return(&form.Form{Name: "contact"}, nil).Once()
+	mockUseCase.On("Submit", mock.Anything, "contact", fromClient).Return((*form.Form)(nil), formusecase.ErrRateLimited).Once()
+
+	var codes []int
+	for _, forwarded := range []string{"198.51.100.1", "198.51.100.2"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/forms/contact/submissions", bytes.NewBufferString(`{"email":"ana@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwarded)
+		req.RemoteAddr = "203.0.113.9:52000"
+		router.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+
+	// A new X-Forwarded-For on every request does not make a new client.
+	assert.Equal(t, []int{http.StatusCreated, http.StatusTooManyRequests}, codes)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestFormHandler_Submit_HTMLForm(t *testing.T) {
+	mockUseCase := new(MockFormUseCase)
+	router := newFormRouter(mockUseCase)
+
+	mockUseCase.On("Submit", mock.Anything, "contact", submissionWith(map[string]string{"email": "ana@example.com", "topic": "sales"})).
+		Return(&form.Form{Name: "contact", RedirectURL: "/thank-you"}, nil)
+
+	body := url.Values{"email": {"ana@example.com"}, "topic": {"sales", "support"}}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/forms/contact/submissions", strings.NewReader(body.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/thank-you", w.Header().Get("Location"))
+}
+
+func TestFormHandler_Submit_Invalid(t *testing.T) {
+	mockUseCase := new(MockFormUseCase)
+	router := newFormRouter(mockUseCase)
+
+	mockUseCase.On("Submit", mock.Anything, "contact", mock.Anything).
+		Return((*form.Form)(nil), &form.ValidationError{Fields: map[string]string{"email": "is required"}})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/forms/contact/submissions", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var got struct {
+		Fields map[string]string `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "is required", got.Fields["email"])
+}
+
+func TestFormHandler_Submit_NestedValue(t *testing.T) {
+	mockUseCase := new(MockFormUseCase)
+	router := newFormRouter(mockUseCase)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/forms/contact/submissions", bytes.NewBufferString(`{"email":["a","b"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	mockUseCase.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFormHandler_Submit_RateLimited(t *testing.T) {
+	mockUseCase := new(MockFormUseCase)
+	router := newFormRouter(mockUseCase)
+
+	mockUseCase.On("Submit", mock.Anything, "contact", mock.Anything).Return((*form.Form)(nil), formusecase.ErrRateLimited)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/forms/contact/submissions", bytes.NewBufferString(`{"email":"ana@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestFormHandler_Export(t *testing.T) {
+	mockUseCase := new(MockFormUseCase)
+	router := newFormRouter(mockUseCase)
+
+	mockUseCase.On("Export", mock.Anything, "f-1", mock.Anything).
+		Run(func(args mock.Arguments) {
+			_, _ = io.WriteString(args.Get(2).(io.Writer), "id,submitted_at,email\n")
+		}).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/forms/f-1/submissions/export", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	assert.Equal(t, "id,submitted_at,email\n", w.Body.String())
+}
+
+func TestFormHandler_Export_NotFound(t *testing.T) {
+	mockUseCase := new(MockFormUseCase)
+	router := newFormRouter(mockUseCase)
+
+	mockUseCase.On("Export", mock.Anything, "missing", mock.Anything).Return(form.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/forms/missing/submissions/export", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
+
+func TestFormHandler_Create_Conflict(t *testing.T) {
+	mockUseCase := new(MockFormUseCase)
+	router := newFormRouter(mockUseCase)
+
+	body, _ := json.Marshal(formRequest{Name: "contact"})
+	mockUseCase.On("Create", mock.Anything, &form.Form{Name: "contact"}).Return(formusecase.ErrFormExists)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/forms", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
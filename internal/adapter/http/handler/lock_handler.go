@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	lockusecase "github.com/mashurimansur/goCMS/internal/usecase/lock"
+)
+
+// LockHandler exposes the advisory edit locks the admin UI uses to show who is editing what.
+type LockHandler struct {
+	lockUseCase lockusecase.UseCase
+}
+
+func NewLockHandler(lockUseCase lockusecase.UseCase) *LockHandler {
+	return &LockHandler{
+		lockUseCase: lockUseCase,
+	}
+}
+
+func (h *LockHandler) Register(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	locks := router.Group("/admin/locks", authMiddleware)
+	locks.GET("", h.list)
+	locks.GET("/:type/:id", h.get)
+	locks.POST("/:type/:id", h.acquire)
+	locks.POST("/:type/:id/heartbeat", h.heartbeat)
+	locks.POST("/:type/:id/takeover", h.takeover)
+	locks.DELETE("/:type/:id", h.release)
+}
+
+// respondLock writes the lock returned by an acquire or heartbeat. When someone else holds the lock
+// it is sent along with the conflict so the editor can say who.
+func respondLock(c *gin.Context, l *lock.Lock, err error) {
+	if errors.Is(err, lockusecase.ErrLocked) && l != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "lock": l})
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, l)
+}
+
+// @Summary      List edit locks
+// @Description  List who is currently editing resources of a type
+// @Tags         locks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  query     string  true  "Resource type, e.g. content"
+// @Success      200   {array}   lock.Lock
+// @Failure      400   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /admin/locks [get]
+func (h *LockHandler) list(c *gin.Context) {
+	locks, err := h.lockUseCase.List(c.Request.Context(), c.Query("type"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, locks)
+}
+
+// @Summary      Get edit lock
+// @Description  Get who is currently editing a resource
+// @Tags         locks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Resource type, e.g. content"
+// @Param        id    path      string  true  "Resource ID"
+// @Success      200   {object}  lock.Lock
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /admin/locks/{type}/{id} [get]
+func (h *LockHandler) get(c *gin.Context) {
+	l, err := h.lockUseCase.Get(c.Request.Context(), c.Param("type"), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, l)
+}
+
+// @Summary      Acquire edit lock
+// @Description  Start editing a resource. Fails with the current lock while someone else is editing it.
+// @Tags         locks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Resource type, e.g. content"
+// @Param        id    path      string  true  "Resource ID"
+// @Success      200   {object}  lock.Lock
+// @Failure      400   {object}  map[string]string
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]string
+// @Router       /admin/locks/{type}/{id} [post]
+func (h *LockHandler) acquire(c *gin.Context) {
+	l, err := h.lockUseCase.Acquire(c.Request.Context(), c.Param("type"), c.Param("id"), c.GetString("user_id"), false)
+	respondLock(c, l, err)
+}
+
+// @Summary      Send edit lock heartbeat
+// @Description  Keep editing a resource. Locks expire unless their holder sends heartbeats; fails with the current lock once someone else took it over.
+// @Tags         locks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Resource type, e.g. content"
+// @Param        id    path      string  true  "Resource ID"
+// @Success      200   {object}  lock.Lock
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]string
+// @Router       /admin/locks/{type}/{id}/heartbeat [post]
+func (h *LockHandler) heartbeat(c *gin.Context) {
+	l, err := h.lockUseCase.Heartbeat(c.Request.Context(), c.Param("type"), c.Param("id"), c.GetString("user_id"))
+	respondLock(c, l, err)
+}
+
+// @Summary      Take over edit lock
+// @Description  Start editing a resource even though someone else is editing it
+// @Tags         locks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Resource type, e.g. content"
+// @Param        id    path      string  true  "Resource ID"
+// @Success      200   {object}  lock.Lock
+// @Failure      400   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /admin/locks/{type}/{id}/takeover [post]
+func (h *LockHandler) takeover(c *gin.Context) {
+	l, err := h.lockUseCase.Acquire(c.Request.Context(), c.Param("type"), c.Param("id"), c.GetString("user_id"), true)
+	respondLock(c, l, err)
+}
+
+// @Summary      Release edit lock
+// @Description  Stop editing a resource
+// @Tags         locks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Resource type, e.g. content"
+// @Param        id    path      string  true  "Resource ID"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /admin/locks/{type}/{id} [delete]
+func (h *LockHandler) release(c *gin.Context) {
+	if err := h.lockUseCase.Release(c.Request.Context(), c.Param("type"), c.Param("id"), c.GetString("user_id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "lock released successfully"})
+}
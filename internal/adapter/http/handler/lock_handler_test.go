@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	lockusecase "github.com/mashurimansur/goCMS/internal/usecase/lock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockLockUseCase is a mock implementation of lockusecase.UseCase
+type MockLockUseCase struct {
+	mock.Mock
+}
+
+func (m *MockLockUseCase) Acquire(ctx context.Context, resourceType, resourceID, userID string, takeover bool) (*lock.Lock, error) {
+	args := m.Called(ctx, resourceType, resourceID, userID, takeover)
+	return args.Get(0).(*lock.Lock), args.Error(1)
+}
+
+func (m *MockLockUseCase) Heartbeat(ctx context.Context, resourceType, resourceID, userID string) (*lock.Lock, error) {
+	args := m.Called(ctx, resourceType, resourceID, userID)
+	return args.Get(0).(*lock.Lock), args.Error(1)
+}
+
+func (m *MockLockUseCase) Release(ctx context.Context, resourceType, resourceID, userID string) error {
+	args := m.Called(ctx, resourceType, resourceID, userID)
+	return args.Error(0)
+}
+
+func (m *MockLockUseCase) Get(ctx context.Context, resourceType, resourceID string) (*lock.Lock, error) {
+	args := m.Called(ctx, resourceType, resourceID)
+	return args.Get(0).(*lock.Lock), args.Error(1)
+}
+
+func (m *MockLockUseCase) List(ctx context.Context, resourceType string) ([]*lock.Lock, error) {
+	args := m.Called(ctx, resourceType)
+	return args.Get(0).([]*lock.Lock), args.Error(1)
+}
+
+func newLockRouter(uc *MockLockUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Next()
+	}
+	NewLockHandler(uc).Register(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestLockHandler_Acquire(t *testing.T) {
+	mockUseCase := new(MockLockUseCase)
+	router := newLockRouter(mockUseCase)
+
+	mockUseCase.On("Acquire", mock.Anything, "content", "post-1", "user-1", false).
+		Return(&lock.Lock{ResourceType: "content", ResourceID: "post-1", HolderID: "user-1", ExpiresAt: time.Now().Add(time.Minute)}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/locks/content/post-1", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"holder_id":"user-1"`)
+}
+
+func TestLockHandler_Acquire_Locked(t *testing.T) {
+	mockUseCase := new(MockLockUseCase)
+	router := newLockRouter(mockUseCase)
+
+	held := &lock.Lock{ResourceType: "content", ResourceID: "post-1", HolderID: "user-2", HolderName: "Budi"}
+	mockUseCase.On("Acquire", mock.Anything, "content", "post-1", "user-1", false).Return(held, lockusecase.ErrLocked)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/locks/content/post-1", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	var body struct {
+		Lock lock.Lock `json:"lock"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Budi", body.Lock.HolderName)
+}
+
+func TestLockHandler_Takeover(t *testing.T) {
+	mockUseCase := new(MockLockUseCase)
+	router := newLockRouter(mockUseCase)
+
+	mockUseCase.On("Acquire", mock.Anything, "content", "post-1", "user-1", true).
+		Return(&lock.Lock{ResourceType: "content", ResourceID: "post-1", HolderID: "user-1"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/locks/content/post-1/takeover", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLockHandler_Heartbeat_Lost(t *testing.T) {
+	mockUseCase := new(MockLockUseCase)
+	router := newLockRouter(mockUseCase)
+
+	mockUseCase.On("Heartbeat", mock.Anything, "content", "post-1", "user-1").Return((*lock.Lock)(nil), lock.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/locks/content/post-1/heartbeat", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLockHandler_List_InvalidResource(t *testing.T) {
+	mockUseCase := new(MockLockUseCase)
+	router := newLockRouter(mockUseCase)
+
+	mockUseCase.On("List", mock.Anything, "planet").Return([]*lock.Lock(nil), lockusecase.ErrInvalidResource)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/locks?type=planet", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,416 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	mediausecase "github.com/mashurimansur/goCMS/internal/usecase/media"
+	"github.com/mashurimansur/goCMS/internal/utils/imaging"
+)
+
+// multipartOverhead is the allowance for multipart boundaries and form fields on top of the file size limit.
+const multipartOverhead = 1 << 20
+
+// MediaHandler exposes admin upload endpoints and public file serving for media.
+type MediaHandler struct {
+	mediaUseCase  mediausecase.UseCase
+	maxUploadSize int64
+}
+
+func NewMediaHandler(mediaUseCase mediausecase.UseCase, maxUploadSize int64) *MediaHandler {
+	return &MediaHandler{
+		mediaUseCase:  mediaUseCase,
+		maxUploadSize: maxUploadSize,
+	}
+}
+
+func (h *MediaHandler) Register(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	public := router.Group("/media")
+	{
+		public.GET("/:id", h.serve)
+		public.HEAD("/:id", h.serve)
+		public.GET("/:id/sizes/:size", h.resize)
+		public.HEAD("/:id/sizes/:size", h.resize)
+		public.GET("/:id/transform", h.transform)
+		public.HEAD("/:id/transform", h.transform)
+	}
+
+	admin := router.Group("/admin/media")
+	admin.Use(authMiddleware)
+	{
+		admin.POST("", h.upload)
+		admin.GET("", h.list)
+		admin.GET("/:id", h.get)
+		admin.PUT("/:id", h.update)
+		admin.DELETE("/:id", h.delete)
+		admin.PUT("/:id/focal-point", h.setFocalPoint)
+		admin.POST("/:id/sign", h.sign)
+	}
+}
+
+type mediaRequest struct {
+	AltText  string `json:"alt_text"`
+	Filename string `json:"filename"`
+	// Version is the version being replaced; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+type focalPointRequest struct {
+	X *float64 `json:"x" binding:"required"`
+	Y *float64 `json:"y" binding:"required"`
+}
+
+type signTransformRequest struct {
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Fit     string `json:"fit"`
+	Format  string `json:"format"`
+	Quality int    `json:"quality"`
+	// TTLSeconds is how long the URL stays valid. It defaults to one day and is capped at 30 days.
+	TTLSeconds int `json:"ttl_seconds"`
+}
+
+func (r signTransformRequest) toParams() imaging.Params {
+	return imaging.Params{Width: r.Width, Height: r.Height, Fit: r.Fit, Format: r.Format, Quality: r.Quality}
+}
+
+const (
+	defaultSignedURLTTL = 24 * time.Hour
+	maxSignedURLTTL     = 30 * 24 * time.Hour
+)
+
+// @Summary      Upload media
+// @Description  Upload a file as multipart/form-data. The type is sniffed from content and checked against the allowlist
+// @Tags         media
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file      formData  file    true   "File"
+// @Param        alt_text  formData  string  false  "Alternative text"
+// @Success      201  {object}  media.Media
+// @Failure      400  {object}  map[string]string
+// @Failure      413  {object}  map[string]string
+// @Failure      415  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/media [post]
+func (h *MediaHandler) upload(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondError(c, mediausecase.ErrFileTooLarge)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if fileHeader.Size > h.maxUploadSize {
+		respondError(c, mediausecase.ErrFileTooLarge)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer file.Close()
+
+	m, err := h.mediaUseCase.Upload(c.Request.Context(), mediausecase.UploadInput{
+		Filename:   fileHeader.Filename,
+		AltText:    c.PostForm("alt_text"),
+		UploaderID: c.GetString("user_id"),
+		Body:       file,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, m)
+}
+
+// @Summary      Get media
+// @Description  Get media metadata by ID
+// @Tags         media
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Media ID"
+// @Success      200  {object}  media.Media
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/media/{id} [get]
+func (h *MediaHandler) get(c *gin.Context) {
+	m, err := h.mediaUseCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, m.Version)
+	c.JSON(http.StatusOK, m)
+}
+
+// @Summary      Update media
+// @Description  Update the alt text and display filename of a media item
+// @Tags         media
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string        true   "Media ID"
+// @Param        If-Match  header  string        false  "ETag of the version being replaced"
+// @Param        request   body    mediaRequest  true   "Media Request"
+// @Success      200  {object}  media.Media
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/media/{id} [put]
+func (h *MediaHandler) update(c *gin.Context) {
+	var req mediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	m := &media.Media{ID: c.Param("id"), AltText: req.AltText, Filename: req.Filename, Version: version}
+	if err := h.mediaUseCase.Update(c.Request.Context(), m); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, m.Version)
+	c.JSON(http.StatusOK, m)
+}
+
+// @Summary      Delete media
+// @Description  Move a media item to the trash. Its stored file is removed once the item is purged
+// @Tags         media
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Media ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/media/{id} [delete]
+func (h *MediaHandler) delete(c *gin.Context) {
+	if err := h.mediaUseCase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "media deleted successfully"})
+}
+
+// @Summary      List media
+// @Description  List media newest first, optionally filtered by MIME type prefix or uploader
+// @Tags         media
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type         query     string  false  "MIME type prefix, e.g. image/"
+// @Param        uploader_id  query     string  false  "Uploader ID"
+// @Param        limit        query     int     false  "Limit"  default(10)
+// @Param        offset       query     int     false  "Offset" default(0)
+// @Success      200  {array}   media.Media
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/media [get]
+func (h *MediaHandler) list(c *gin.Context) {
+	limit, offset := pagination(c)
+	items, err := h.mediaUseCase.List(c.Request.Context(), media.ListFilter{
+		MimePrefix: c.Query("type"),
+		UploaderID: c.Query("uploader_id"),
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary      Serve media file
+// @Description  Stream the stored file. Supports range requests and conditional requests via ETag
+// @Tags         media
+// @Produce      octet-stream
+// @Param        id   path  string  true  "Media ID"
+// @Success      200
+// @Success      206
+// @Success      304
+// @Failure      404  {object}  map[string]string
+// @Router       /media/{id} [get]
+func (h *MediaHandler) serve(c *gin.Context) {
+	m, file, err := h.mediaUseCase.Open(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer file.Close()
+
+	// Stored bytes never change for a given ID, so they can be cached indefinitely.
+	header := c.Writer.Header()
+	header.Set("Content-Type", m.MimeType)
+	header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	header.Set("ETag", `"`+m.ID+`"`)
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": m.Filename}))
+
+	http.ServeContent(c.Writer, c.Request, m.Filename, m.CreatedAt, file)
+}
+
+// @Summary      Serve image size
+// @Description  Stream a configured rendition of an image, generating it on first request
+// @Tags         media
+// @Produce      image/jpeg,image/png,image/gif
+// @Param        id    path  string  true  "Media ID"
+// @Param        size  path  string  true  "Size name, e.g. thumbnail"
+// @Success      200
+// @Success      304
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /media/{id}/sizes/{size} [get]
+func (h *MediaHandler) resize(c *gin.Context) {
+	rendition, err := h.mediaUseCase.Resize(c.Request.Context(), c.Param("id"), c.Param("size"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	serveRendition(c, rendition)
+}
+
+// @Summary      Serve ad-hoc transform
+// @Description  Stream an ad-hoc rendition. The query must carry a valid signature from the sign endpoint
+// @Tags         media
+// @Produce      image/jpeg,image/png,image/gif
+// @Param        id       path   string  true   "Media ID"
+// @Param        w        query  int     false  "Width"
+// @Param        h        query  int     false  "Height"
+// @Param        fit      query  string  false  "fit or crop"
+// @Param        fm       query  string  false  "Output format: jpeg, png or gif"
+// @Param        q        query  int     false  "JPEG quality"
+// @Param        expires  query  int     true   "Expiry as unix time"
+// @Param        sig      query  string  true   "Signature"
+// @Success      200
+// @Success      304
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /media/{id}/transform [get]
+func (h *MediaHandler) transform(c *gin.Context) {
+	var query struct {
+		Width   int    `form:"w"`
+		Height  int    `form:"h"`
+		Fit     string `form:"fit"`
+		Format  string `form:"fm"`
+		Quality int    `form:"q"`
+		Expires int64  `form:"expires" binding:"required"`
+		Sig     string `form:"sig" binding:"required"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	params := imaging.Params{Width: query.Width, Height: query.Height, Fit: query.Fit, Format: query.Format, Quality: query.Quality}
+	rendition, err := h.mediaUseCase.Transform(c.Request.Context(), c.Param("id"), params, query.Expires, query.Sig)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	serveRendition(c, rendition)
+}
+
+// @Summary      Set focal point
+// @Description  Set the point, in the 0..1 range, that cropped renditions keep in view
+// @Tags         media
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  string             true  "Media ID"
+// @Param        request body  focalPointRequest  true  "Focal Point"
+// @Success      200  {object}  media.Media
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/media/{id}/focal-point [put]
+func (h *MediaHandler) setFocalPoint(c *gin.Context) {
+	var req focalPointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	m, err := h.mediaUseCase.SetFocalPoint(c.Request.Context(), c.Param("id"), *req.X, *req.Y)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, m)
+}
+
+// @Summary      Sign transform URL
+// @Description  Create a time-limited URL for an ad-hoc rendition of an image
+// @Tags         media
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  string                true  "Media ID"
+// @Param        request body  signTransformRequest  true  "Transform"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/media/{id}/sign [post]
+func (h *MediaHandler) sign(c *gin.Context) {
+	var req signTransformRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = defaultSignedURLTTL
+	}
+	if ttl > maxSignedURLTTL {
+		ttl = maxSignedURLTTL
+	}
+
+	signed, err := h.mediaUseCase.SignTransform(c.Request.Context(), c.Param("id"), req.toParams(), ttl)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": signed})
+}
+
+// serveRendition streams a derived image. Renditions are revalidated daily because presets
+// and focal points can change what a URL renders; the ETag tracks the exact derivative.
+func serveRendition(c *gin.Context, r *mediausecase.Rendition) {
+	defer r.Body.Close()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", r.ContentType)
+	header.Set("Cache-Control", "public, max-age=86400")
+	header.Set("ETag", `"`+r.Key+`"`)
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(c.Writer, c.Request, "", r.Media.UpdatedAt, r.Body)
+}
@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	mediausecase "github.com/mashurimansur/goCMS/internal/usecase/media"
+	"github.com/mashurimansur/goCMS/internal/utils/imaging"
+	"github.com/mashurimansur/goCMS/internal/utils/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMediaUseCase is a mock implementation of mediausecase.UseCase
+type MockMediaUseCase struct {
+	mock.Mock
+}
+
+func (m *MockMediaUseCase) Upload(ctx context.Context, in mediausecase.UploadInput) (*media.Media, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*media.Media), args.Error(1)
+}
+
+func (m *MockMediaUseCase) Get(ctx context.Context, id string) (*media.Media, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*media.Media), args.Error(1)
+}
+
+func (m *MockMediaUseCase) Update(ctx context.Context, item *media.Media) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockMediaUseCase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMediaUseCase) List(ctx context.Context, filter media.ListFilter) ([]*media.Media, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*media.Media), args.Error(1)
+}
+
+func (m *MockMediaUseCase) Open(ctx context.Context, id string) (*media.Media, io.ReadSeekCloser, error) {
+	args := m.Called(ctx, id)
+	file, _ := args.Get(1).(io.ReadSeekCloser)
+	return args.Get(0).(*media.Media), file, args.Error(2)
+}
+
+func (m *MockMediaUseCase) SetFocalPoint(ctx context.Context, id string, x, y float64) (*media.Media, error) {
+	args := m.Called(ctx, id, x, y)
+	return args.Get(0).(*media.Media), args.Error(1)
+}
+
+func (m *MockMediaUseCase) Resize(ctx context.Context, id, size string) (*mediausecase.Rendition, error) {
+	args := m.Called(ctx, id, size)
+	return args.Get(0).(*mediausecase.Rendition), args.Error(1)
+}
+
+func (m *MockMediaUseCase) SignTransform(ctx context.Context, id string, p imaging.Params, ttl time.Duration) (string, error) {
+	args := m.Called(ctx, id, p, ttl)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockMediaUseCase) Transform(ctx context.Context, id string, p imaging.Params, expires int64, sig string) (*mediausecase.Rendition, error) {
+	args := m.Called(ctx, id, p, expires, sig)
+	return args.Get(0).(*mediausecase.Rendition), args.Error(1)
+}
+
+func (m *MockMediaUseCase) UploadAvatar(ctx context.Context, userID, filename string, body io.Reader) (string, error) {
+	args := m.Called(ctx, userID, filename, body)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockMediaUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockMediaUseCase) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMediaUseCase) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type readSeekNopCloser struct{ io.ReadSeeker }
+
+func (readSeekNopCloser) Close() error { return nil }
+
+func newMediaRouter(uc *MockMediaUseCase, maxUploadSize int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) { c.Set("user_id", "user-1"); c.Next() }
+	NewMediaHandler(uc, maxUploadSize).Register(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func multipartBody(t *testing.T, filename string, data []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	require.NoError(t, writer.WriteField("alt_text", "A cat"))
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestMediaHandler_Upload(t *testing.T) {
+	mockUseCase := new(MockMediaUseCase)
+	router := newMediaRouter(mockUseCase, 1<<20)
+
+	mockUseCase.On("Upload", mock.Anything, mock.MatchedBy(func(in mediausecase.UploadInput) bool {
+		return in.Filename == "cat.png" && in.AltText == "A cat" && in.UploaderID == "user-1"
+	})).Return(&media.Media{ID: "media-1", MimeType: "image/png"}, nil)
+
+	body, contentType := multipartBody(t, "cat.png", []byte("png-bytes"))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/media", body)
+	req.Header.Set("Content-Type", contentType)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestMediaHandler_Upload_TooLarge(t *testing.T) {
+	mockUseCase := new(MockMediaUseCase)
+	router := newMediaRouter(mockUseCase, 10)
+
+	body, contentType := multipartBody(t, "big.bin", bytes.Repeat([]byte("x"), 100))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/media", body)
+	req.Header.Set("Content-Type", contentType)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	mockUseCase.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything)
+}
+
+func TestMediaHandler_Upload_UnsupportedType(t *testing.T) {
+	mockUseCase := new(MockMediaUseCase)
+	router := newMediaRouter(mockUseCase, 1<<20)
+
+	mockUseCase.On("Upload", mock.Anything, mock.Anything).Return((*media.Media)(nil), mediausecase.ErrUnsupportedType)
+
+	body, contentType := multipartBody(t, "page.html", []byte("<html></html>"))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/media", body)
+	req.Header.Set("Content-Type", contentType)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+func TestMediaHandler_Serve(t *testing.T) {
+	m := &media.Media{ID: "media-1", Filename: "notes.txt", MimeType: "text/plain", CreatedAt: time.Now()}
+
+	t.Run("full", func(t *testing.T) {
+		mockUseCase := new(MockMediaUseCase)
+		router := newMediaRouter(mockUseCase, 1<<20)
+		mockUseCase.On("Open", mock.Anything, "media-1").Return(m, readSeekNopCloser{strings.NewReader("hello world")}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/media/media-1", nil)
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "hello world", w.Body.String())
+		assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Cache-Control"), "immutable")
+		assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+	})
+
+	t.Run("range", func(t *testing.T) {
+		mockUseCase := new(MockMediaUseCase)
+		router := newMediaRouter(mockUseCase, 1<<20)
+		mockUseCase.On("Open", mock.Anything, "media-1").Return(m, readSeekNopCloser{strings.NewReader("hello world")}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/media/media-1", nil)
+		req.Header.Set("Range", "bytes=6-10")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "world", w.Body.String())
+		assert.Equal(t, "bytes 6-10/11", w.Header().Get("Content-Range"))
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		mockUseCase := new(MockMediaUseCase)
+		router := newMediaRouter(mockUseCase, 1<<20)
+		mockUseCase.On("Open", mock.Anything, "media-1").Return(m, readSeekNopCloser{strings.NewReader("hello world")}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/media/media-1", nil)
+		req.Header.Set("If-None-Match", `"media-1"`)
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNotModified, w.Code)
+	})
+}
+
+func TestMediaHandler_Serve_NotFound(t *testing.T) {
+	mockUseCase := new(MockMediaUseCase)
+	router := newMediaRouter(mockUseCase, 1<<20)
+	mockUseCase.On("Open", mock.Anything, "missing").Return((*media.Media)(nil), nil, media.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/media/missing", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMediaHandler_Resize(t *testing.T) {
+	mockUseCase := new(MockMediaUseCase)
+	router := newMediaRouter(mockUseCase, 1<<20)
+
+	rendition := &mediausecase.Rendition{
+		Media:       &media.Media{ID: "media-1"},
+		Key:         "derivatives/media-1/w150-h150-crop.png",
+		ContentType: "image/png",
+		Body:        readSeekNopCloser{strings.NewReader("png")},
+	}
+	mockUseCase.On("Resize", mock.Anything, "media-1", "thumbnail").Return(rendition, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/media/media-1/sizes/thumbnail", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, `"derivatives/media-1/w150-h150-crop.png"`, w.Header().Get("ETag"))
+}
+
+func TestMediaHandler_Transform_InvalidSignature(t *testing.T) {
+	mockUseCase := new(MockMediaUseCase)
+	router := newMediaRouter(mockUseCase, 1<<20)
+
+	params := imaging.Params{Width: 300, Fit: "crop", Format: "png"}
+	mockUseCase.On("Transform", mock.Anything, "media-1", params, int64(1700000000), "bad").
+		Return((*mediausecase.Rendition)(nil), signer.ErrInvalidSignature)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/media/media-1/transform?w=300&fit=crop&fm=png&expires=1700000000&sig=bad", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestMediaHandler_Transform_MissingSignature(t *testing.T) {
+	mockUseCase := new(MockMediaUseCase)
+	router := newMediaRouter(mockUseCase, 1<<20)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/media/media-1/transform?w=300", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMediaHandler_Sign_ClampsTTL(t *testing.T) {
+	mockUseCase := new(MockMediaUseCase)
+	router := newMediaRouter(mockUseCase, 1<<20)
+
+	mockUseCase.On("SignTransform", mock.Anything, "media-1", imaging.Params{Width: 300}, maxSignedURLTTL).
+		Return("/api/v1/media/media-1/transform?sig=x", nil)
+
+	body, _ := json.Marshal(signTransformRequest{Width: 300, TTLSeconds: 365 * 24 * 3600})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/media/media-1/sign", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/api/v1/media/media-1/transform?sig=x")
+}
+
+func TestMediaHandler_SetFocalPoint(t *testing.T) {
+	mockUseCase := new(MockMediaUseCase)
+	router := newMediaRouter(mockUseCase, 1<<20)
+
+	mockUseCase.On("SetFocalPoint", mock.Anything, "media-1", 0.0, 1.0).Return(&media.Media{ID: "media-1"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/media/media-1/focal-point", strings.NewReader(`{"x":0,"y":1}`))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/api/v1/admin/media/media-1/focal-point", strings.NewReader(`{"x":0.5}`))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/menu"
+	menuusecase "github.com/mashurimansur/goCMS/internal/usecase/menu"
+)
+
+// MenuHandler exposes admin and public HTTP endpoints for navigation menus.
+type MenuHandler struct {
+	menuUseCase menuusecase.UseCase
+}
+
+func NewMenuHandler(menuUseCase menuusecase.UseCase) *MenuHandler {
+	return &MenuHandler{
+		menuUseCase: menuUseCase,
+	}
+}
+
+func (h *MenuHandler) Register(router *gin.RouterGroup, authMiddleware, readerMiddleware gin.HandlerFunc) {
+	router.GET("/menus/:name", readerMiddleware, h.resolve)
+
+	admin := router.Group("/admin/menus")
+	admin.Use(authMiddleware)
+	{
+		admin.POST("", h.create)
+		admin.GET("", h.list)
+		admin.GET("/:id", h.get)
+		admin.PUT("/:id", h.update)
+		admin.DELETE("/:id", h.delete)
+		admin.PUT("/:id/items", h.setItems)
+	}
+}
+
+type menuRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Title string `json:"title"`
+	// Version is the version being replaced on update; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+type menuItemRequest struct {
+	Label    string            `json:"label"`
+	LinkType string            `json:"link_type" binding:"required"`
+	TargetID string            `json:"target_id"`
+	URL      string            `json:"url"`
+	NewTab   bool              `json:"new_tab"`
+	Children []menuItemRequest `json:"children"`
+}
+
+type menuItemsRequest struct {
+	Items []menuItemRequest `json:"items" binding:"required,dive"`
+	// Version is the menu version being replaced; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+func toMenuItems(reqs []menuItemRequest) []*menu.Item {
+	items := make([]*menu.Item, 0, len(reqs))
+	for _, req := range reqs {
+		items = append(items, &menu.Item{
+			Label:    req.Label,
+			LinkType: req.LinkType,
+			TargetID: req.TargetID,
+			URL:      req.URL,
+			NewTab:   req.NewTab,
+			Children: toMenuItems(req.Children),
+		})
+	}
+	return items
+}
+
+// @Summary      Create menu
+// @Description  Create a new navigation menu
+// @Tags         menus
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body menuRequest true "Menu Request"
+// @Success      201  {object}  menu.Menu
+// @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/menus [post]
+func (h *MenuHandler) create(c *gin.Context) {
+	var req menuRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	m := &menu.Menu{Name: req.Name, Title: req.Title}
+	if err := h.menuUseCase.Create(c.Request.Context(), m); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, m)
+}
+
+// @Summary      List menus
+// @Description  List navigation menus ordered by name, without their items
+// @Tags         menus
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   menu.Menu
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/menus [get]
+func (h *MenuHandler) list(c *gin.Context) {
+	menus, err := h.menuUseCase.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, menus)
+}
+
+// @Summary      Get menu
+// @Description  Get a menu by ID with its full item tree, including items linking to unpublished entries
+// @Tags         menus
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Menu ID"
+// @Success      200  {object}  menu.Menu
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/menus/{id} [get]
+func (h *MenuHandler) get(c *gin.Context) {
+	m, err := h.menuUseCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, m.Version)
+	c.JSON(http.StatusOK, m)
+}
+
+// @Summary      Update menu
+// @Description  Rename a menu or change its title
+// @Tags         menus
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string       true   "Menu ID"
+// @Param        If-Match  header  string       false  "ETag of the version being replaced"
+// @Param        request   body    menuRequest  true   "Menu Request"
+// @Success      200  {object}  menu.Menu
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/menus/{id} [put]
+func (h *MenuHandler) update(c *gin.Context) {
+	var req menuRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	m := &menu.Menu{ID: c.Param("id"), Name: req.Name, Title: req.Title, Version: version}
+	if err := h.menuUseCase.Update(c.Request.Context(), m); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, m.Version)
+	c.JSON(http.StatusOK, m)
+}
+
+// @Summary      Delete menu
+// @Description  Move a menu and all of its items to the trash
+// @Tags         menus
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Menu ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/menus/{id} [delete]
+func (h *MenuHandler) delete(c *gin.Context) {
+	if err := h.menuUseCase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "menu deleted successfully"})
+}
+
+// @Summary      Set menu items
+// @Description  Replace the items of a menu with a nested tree, kept in the given order
+// @Tags         menus
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string            true   "Menu ID"
+// @Param        If-Match  header  string            false  "ETag of the menu version being replaced"
+// @Param        request   body    menuItemsRequest  true   "Menu Items Request"
+// @Success      200  {object}  menu.Menu
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/menus/{id}/items [put]
+func (h *MenuHandler) setItems(c *gin.Context) {
+	var req menuItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	m, err := h.menuUseCase.SetItems(c.Request.Context(), c.Param("id"), version, toMenuItems(req.Items))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, m.Version)
+	c.JSON(http.StatusOK, m)
+}
+
+// @Summary      Get menu tree
+// @Description  Get a menu by name with the current URLs of its items. Items linking to unpublished or deleted entries, or to entries the reader may not see, are left out.
+// @Tags         menus
+// @Produce      json
+// @Param        name  path      string  true  "Menu name"
+// @Success      200   {object}  menu.Menu
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /menus/{name} [get]
+func (h *MenuHandler) resolve(c *gin.Context) {
+	m, err := h.menuUseCase.Resolve(c.Request.Context(), c.Param("name"), viewer(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, m)
+}
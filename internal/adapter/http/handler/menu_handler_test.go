@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/menu"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	menuusecase "github.com/mashurimansur/goCMS/internal/usecase/menu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMenuUseCase is a mock implementation of menuusecase.UseCase
+type MockMenuUseCase struct {
+	mock.Mock
+}
+
+func (m *MockMenuUseCase) Create(ctx context.Context, mn *menu.Menu) error {
+	args := m.Called(ctx, mn)
+	return args.Error(0)
+}
+
+func (m *MockMenuUseCase) Get(ctx context.Context, id string) (*menu.Menu, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*menu.Menu), args.Error(1)
+}
+
+func (m *MockMenuUseCase) Update(ctx context.Context, mn *menu.Menu) error {
+	args := m.Called(ctx, mn)
+	return args.Error(0)
+}
+
+func (m *MockMenuUseCase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMenuUseCase) List(ctx context.Context) ([]*menu.Menu, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*menu.Menu), args.Error(1)
+}
+
+func (m *MockMenuUseCase) SetItems(ctx context.Context, id string, version int, items []*menu.Item) (*menu.Menu, error) {
+	args := m.Called(ctx, id, version, items)
+	return args.Get(0).(*menu.Menu), args.Error(1)
+}
+
+func (m *MockMenuUseCase) Resolve(ctx context.Context, name string, viewer content.Viewer) (*menu.Menu, error) {
+	args := m.Called(ctx, name, viewer)
+	return args.Get(0).(*menu.Menu), args.Error(1)
+}
+
+func (m *MockMenuUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockMenuUseCase) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMenuUseCase) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newMenuRouter(uc *MockMenuUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) { c.Next() }
+	readerMiddleware := func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Set("user_id", "reader-1")
+			c.Set("user_role", "editor")
+		}
+		c.Next()
+	}
+	NewMenuHandler(uc).Register(router.Group("/api/v1"), authMiddleware, readerMiddleware)
+	return router
+}
+
+func TestMenuHandler_Resolve(t *testing.T) {
+	mockUseCase := new(MockMenuUseCase)
+	router := newMenuRouter(mockUseCase)
+
+	resolved := &menu.Menu{ID: "menu-1", Name: "header", Items: []*menu.Item{{Label: "About", URL: "/about"}}}
+	mockUseCase.On("Resolve", mock.Anything, "header", content.Viewer{UserID: "reader-1", Role: "editor"}).Return(resolved, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/menus/header", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var got menu.Menu
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, "/about", got.Items[0].URL)
+}
+
+func TestMenuHandler_Resolve_NotFound(t *testing.T) {
+	mockUseCase := new(MockMenuUseCase)
+	router := newMenuRouter(mockUseCase)
+
+	mockUseCase.On("Resolve", mock.Anything, "missing", content.Viewer{}).Return((*menu.Menu)(nil), menu.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/menus/missing", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMenuHandler_Create_Conflict(t *testing.T) {
+	mockUseCase := new(MockMenuUseCase)
+	router := newMenuRouter(mockUseCase)
+
+	body, _ := json.Marshal(menuRequest{Name: "footer"})
+	mockUseCase.On("Create", mock.Anything, &menu.Menu{Name: "footer"}).Return(menuusecase.ErrMenuExists)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/menus", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestMenuHandler_SetItems(t *testing.T) {
+	mockUseCase := new(MockMenuUseCase)
+	router := newMenuRouter(mockUseCase)
+
+	body := []byte(`{"items":[{"label":"Docs","link_type":"url","url":"/docs","children":[{"link_type":"content","target_id":"page-1"}]}]}`)
+	expected := []*menu.Item{{Label: "Docs", LinkType: menu.LinkURL, URL: "/docs", Children: []*menu.Item{
+		{LinkType: menu.LinkContent, TargetID: "page-1", Children: []*menu.Item{}},
+	}}}
+	mockUseCase.On("SetItems", mock.Anything, "menu-1", 3, expected).Return(&menu.Menu{ID: "menu-1", Version: 4}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/menus/menu-1/items", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"3"`)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestMenuHandler_SetItems_Invalid(t *testing.T) {
+	mockUseCase := new(MockMenuUseCase)
+	router := newMenuRouter(mockUseCase)
+
+	mockUseCase.On("SetItems", mock.Anything, "menu-1", 1, mock.Anything).Return((*menu.Menu)(nil), menuusecase.ErrInvalidItem)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/menus/menu-1/items", bytes.NewBufferString(`{"version":1,"items":[{"label":"x","link_type":"page"}]}`))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
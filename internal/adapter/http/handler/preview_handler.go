@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	previewusecase "github.com/mashurimansur/goCMS/internal/usecase/preview"
+)
+
+// PreviewHandler exposes preview links that let people without an account view unpublished entries.
+type PreviewHandler struct {
+	previewUseCase previewusecase.UseCase
+}
+
+func NewPreviewHandler(previewUseCase previewusecase.UseCase) *PreviewHandler {
+	return &PreviewHandler{
+		previewUseCase: previewUseCase,
+	}
+}
+
+func (h *PreviewHandler) Register(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/preview", h.view)
+	router.POST("/admin/contents/:id/previews", authMiddleware, h.create)
+	router.GET("/admin/contents/:id/previews", authMiddleware, h.list)
+	router.DELETE("/admin/previews/:id", authMiddleware, h.revoke)
+}
+
+type previewRequest struct {
+	// ExpiresIn is a duration such as "48h". It defaults to the configured preview duration.
+	ExpiresIn string `json:"expires_in"`
+}
+
+// @Summary      View preview
+// @Description  Get the entry a preview token grants access to, whatever its status. The link stops working once it expires, is revoked or the entry changes.
+// @Tags         previews
+// @Produce      json
+// @Param        token  query     string  true  "Preview token"
+// @Success      200    {object}  content.Content
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      410    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /preview [get]
+func (h *PreviewHandler) view(c *gin.Context) {
+	// Previews are private: keep them out of caches and search engines, and the token out of referrers.
+	header := c.Writer.Header()
+	header.Set("Cache-Control", "private, no-store")
+	header.Set("X-Robots-Tag", "noindex, nofollow")
+	header.Set("Referrer-Policy", "no-referrer")
+
+	entry, err := h.previewUseCase.Resolve(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary      Create preview link
+// @Description  Issue a signed, expiring link to the current revision of an entry. The token is only returned once.
+// @Tags         previews
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  string          true   "Content ID"
+// @Param        request body  previewRequest  false  "Preview Request"
+// @Success      201  {object}  preview.Grant
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/{id}/previews [post]
+func (h *PreviewHandler) create(c *gin.Context) {
+	var req previewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	var duration time.Duration
+	if req.ExpiresIn != "" {
+		var err error
+		if duration, err = time.ParseDuration(req.ExpiresIn); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expires_in: " + err.Error()})
+			return
+		}
+	}
+
+	grant, err := h.previewUseCase.Create(c.Request.Context(), c.Param("id"), c.GetString("user_id"), duration)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, grant)
+}
+
+// @Summary      List preview links
+// @Description  List the preview links of an entry, newest first, including expired and revoked ones
+// @Tags         previews
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Content ID"
+// @Success      200  {array}   preview.Link
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/{id}/previews [get]
+func (h *PreviewHandler) list(c *gin.Context) {
+	links, err := h.previewUseCase.List(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// @Summary      Revoke preview link
+// @Description  Stop a preview link from working before it expires
+// @Tags         previews
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Preview link ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/previews/{id} [delete]
+func (h *PreviewHandler) revoke(c *gin.Context) {
+	if err := h.previewUseCase.Revoke(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "preview link revoked successfully"})
+}
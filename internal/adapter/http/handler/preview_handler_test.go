@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/preview"
+	previewusecase "github.com/mashurimansur/goCMS/internal/usecase/preview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPreviewUseCase is a mock implementation of previewusecase.UseCase
+type MockPreviewUseCase struct {
+	mock.Mock
+}
+
+func (m *MockPreviewUseCase) Create(ctx context.Context, contentID, userID string, duration time.Duration) (*preview.Grant, error) {
+	args := m.Called(ctx, contentID, userID, duration)
+	return args.Get(0).(*preview.Grant), args.Error(1)
+}
+
+func (m *MockPreviewUseCase) List(ctx context.Context, contentID string) ([]*preview.Link, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).([]*preview.Link), args.Error(1)
+}
+
+func (m *MockPreviewUseCase) Revoke(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPreviewUseCase) Resolve(ctx context.Context, tokenString string) (*content.Content, error) {
+	args := m.Called(ctx, tokenString)
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func newPreviewRouter(uc *MockPreviewUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Next()
+	}
+	NewPreviewHandler(uc).Register(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestPreviewHandler_View(t *testing.T) {
+	mockUseCase := new(MockPreviewUseCase)
+	router := newPreviewRouter(mockUseCase)
+
+	mockUseCase.On("Resolve", mock.Anything, "tok").Return(&content.Content{ID: "post-1", Status: content.StatusDraft}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/preview?token=tok", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"draft"`)
+	assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "noindex, nofollow", w.Header().Get("X-Robots-Tag"))
+}
+
+func TestPreviewHandler_View_Errors(t *testing.T) {
+	mockUseCase := new(MockPreviewUseCase)
+	router := newPreviewRouter(mockUseCase)
+
+	mockUseCase.On("Resolve", mock.Anything, "revoked").Return((*content.Content)(nil), previewusecase.ErrInvalidPreview)
+	mockUseCase.On("Resolve", mock.Anything, "old").Return((*content.Content)(nil), previewusecase.ErrPreviewOutdated)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/preview?token=revoked", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/preview?token=old", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusGone, w.Code)
+}
+
+func TestPreviewHandler_Create(t *testing.T) {
+	mockUseCase := new(MockPreviewUseCase)
+	router := newPreviewRouter(mockUseCase)
+
+	grant := &preview.Grant{Link: &preview.Link{ID: "link-1", ContentID: "post-1"}, Token: "tok", URL: "https://example.com/api/v1/preview?token=tok"}
+	mockUseCase.On("Create", mock.Anything, "post-1", "user-1", 48*time.Hour).Return(grant, nil)
+	mockUseCase.On("Create", mock.Anything, "post-2", "user-1", time.Duration(0)).Return(grant, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/contents/post-1/previews", bytes.NewBufferString(`{"expires_in":"48h"}`))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"tok"`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/admin/contents/post-2/previews", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/admin/contents/post-1/previews", bytes.NewBufferString(`{"expires_in":"two days"}`))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestPreviewHandler_Revoke(t *testing.T) {
+	mockUseCase := new(MockPreviewUseCase)
+	router := newPreviewRouter(mockUseCase)
+
+	mockUseCase.On("Revoke", mock.Anything, "link-1").Return(nil)
+	mockUseCase.On("Revoke", mock.Anything, "missing").Return(preview.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/admin/previews/link-1", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/api/v1/admin/previews/missing", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/redirect"
+	redirectusecase "github.com/mashurimansur/goCMS/internal/usecase/redirect"
+)
+
+// RedirectHandler exposes admin HTTP endpoints for managing redirect rules.
+type RedirectHandler struct {
+	redirectUseCase redirectusecase.UseCase
+}
+
+func NewRedirectHandler(redirectUseCase redirectusecase.UseCase) *RedirectHandler {
+	return &RedirectHandler{
+		redirectUseCase: redirectUseCase,
+	}
+}
+
+func (h *RedirectHandler) Register(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	admin := router.Group("/admin/redirects")
+	admin.Use(authMiddleware)
+	{
+		admin.POST("", h.create)
+		admin.GET("", h.list)
+		admin.GET("/:id", h.get)
+		admin.PUT("/:id", h.update)
+		admin.DELETE("/:id", h.delete)
+	}
+}
+
+type redirectRequest struct {
+	Source     string `json:"source" binding:"required"`
+	MatchType  string `json:"match_type"`
+	Target     string `json:"target"`
+	StatusCode int    `json:"status_code"`
+	Position   int    `json:"position"`
+	Enabled    *bool  `json:"enabled"`
+	// Version is the version being replaced on update; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+func (r redirectRequest) toRule() *redirect.Rule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return &redirect.Rule{
+		Source:     r.Source,
+		MatchType:  r.MatchType,
+		Target:     r.Target,
+		StatusCode: r.StatusCode,
+		Position:   r.Position,
+		Enabled:    enabled,
+	}
+}
+
+// @Summary      Create redirect rule
+// @Description  Create an exact, wildcard or regex redirect rule. Status defaults to 301
+// @Tags         redirects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body redirectRequest true "Redirect Request"
+// @Success      201  {object}  redirect.Rule
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/redirects [post]
+func (h *RedirectHandler) create(c *gin.Context) {
+	var req redirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := req.toRule()
+	if err := h.redirectUseCase.Create(c.Request.Context(), rule); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// @Summary      Get redirect rule
+// @Description  Get a redirect rule by ID including its hit counter
+// @Tags         redirects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Redirect rule ID"
+// @Success      200  {object}  redirect.Rule
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/redirects/{id} [get]
+func (h *RedirectHandler) get(c *gin.Context) {
+	rule, err := h.redirectUseCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, rule.Version)
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary      Update redirect rule
+// @Description  Update a redirect rule by ID
+// @Tags         redirects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string           true   "Redirect rule ID"
+// @Param        If-Match  header  string           false  "ETag of the version being replaced"
+// @Param        request   body    redirectRequest  true   "Redirect Request"
+// @Success      200  {object}  redirect.Rule
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/redirects/{id} [put]
+func (h *RedirectHandler) update(c *gin.Context) {
+	var req redirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	rule := req.toRule()
+	rule.ID = c.Param("id")
+	rule.Version = version
+	if err := h.redirectUseCase.Update(c.Request.Context(), rule); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, rule.Version)
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary      Delete redirect rule
+// @Description  Delete a redirect rule by ID
+// @Tags         redirects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Redirect rule ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/redirects/{id} [delete]
+func (h *RedirectHandler) delete(c *gin.Context) {
+	if err := h.redirectUseCase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "redirect rule deleted successfully"})
+}
+
+// @Summary      List redirect rules
+// @Description  List redirect rules in evaluation order with pagination
+// @Tags         redirects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit   query     int  false  "Limit"  default(10)
+// @Param        offset  query     int  false  "Offset" default(0)
+// @Success      200  {array}   redirect.Rule
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/redirects [get]
+func (h *RedirectHandler) list(c *gin.Context) {
+	limit, offset := pagination(c)
+	rules, err := h.redirectUseCase.List(c.Request.Context(), limit, offset)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/redirect"
+	redirectusecase "github.com/mashurimansur/goCMS/internal/usecase/redirect"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRedirectUseCase is a mock implementation of redirectusecase.UseCase
+type MockRedirectUseCase struct {
+	mock.Mock
+}
+
+func (m *MockRedirectUseCase) Create(ctx context.Context, r *redirect.Rule) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRedirectUseCase) Get(ctx context.Context, id string) (*redirect.Rule, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*redirect.Rule), args.Error(1)
+}
+
+func (m *MockRedirectUseCase) Update(ctx context.Context, r *redirect.Rule) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRedirectUseCase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRedirectUseCase) List(ctx context.Context, limit, offset int) ([]*redirect.Rule, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*redirect.Rule), args.Error(1)
+}
+
+func (m *MockRedirectUseCase) Reload(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockRedirectUseCase) Redirect(path string) (string, int, bool) {
+	args := m.Called(path)
+	return args.String(0), args.Int(1), args.Bool(2)
+}
+
+func (m *MockRedirectUseCase) FlushHits(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func newRedirectRouter(uc *MockRedirectUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) { c.Next() }
+	NewRedirectHandler(uc).Register(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestRedirectHandler_Create(t *testing.T) {
+	mockUseCase := new(MockRedirectUseCase)
+	router := newRedirectRouter(mockUseCase)
+
+	body, _ := json.Marshal(redirectRequest{Source: "/old", Target: "/new"})
+	mockUseCase.On("Create", mock.Anything, &redirect.Rule{Source: "/old", Target: "/new", Enabled: true}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/redirects", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestRedirectHandler_Create_Invalid(t *testing.T) {
+	mockUseCase := new(MockRedirectUseCase)
+	router := newRedirectRouter(mockUseCase)
+
+	body, _ := json.Marshal(redirectRequest{Source: "(", MatchType: redirect.MatchRegex, Target: "/new"})
+	mockUseCase.On("Create", mock.Anything, mock.Anything).Return(redirectusecase.ErrInvalidRule)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/redirects", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRedirectHandler_Get_NotFound(t *testing.T) {
+	mockUseCase := new(MockRedirectUseCase)
+	router := newRedirectRouter(mockUseCase)
+
+	mockUseCase.On("Get", mock.Anything, "missing").Return((*redirect.Rule)(nil), redirect.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/redirects/missing", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRedirectHandler_Update_Disable(t *testing.T) {
+	mockUseCase := new(MockRedirectUseCase)
+	router := newRedirectRouter(mockUseCase)
+
+	disabled := false
+	body, _ := json.Marshal(redirectRequest{Source: "/old", Target: "/new", Enabled: &disabled})
+	mockUseCase.On("Update", mock.Anything, &redirect.Rule{ID: "rule-1", Source: "/old", Target: "/new", Version: 2}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/redirects/rule-1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"2"`)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
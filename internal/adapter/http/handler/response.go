@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/bulk"
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/comment"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+	"github.com/mashurimansur/goCMS/internal/domain/form"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/menu"
+	"github.com/mashurimansur/goCMS/internal/domain/preview"
+	"github.com/mashurimansur/goCMS/internal/domain/redirect"
+	"github.com/mashurimansur/goCMS/internal/domain/seo"
+	"github.com/mashurimansur/goCMS/internal/domain/snippet"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	bulkusecase "github.com/mashurimansur/goCMS/internal/usecase/bulk"
+	categoryusecase "github.com/mashurimansur/goCMS/internal/usecase/category"
+	commentusecase "github.com/mashurimansur/goCMS/internal/usecase/comment"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	contenttypeusecase "github.com/mashurimansur/goCMS/internal/usecase/contenttype"
+	feedusecase "github.com/mashurimansur/goCMS/internal/usecase/feed"
+	formusecase "github.com/mashurimansur/goCMS/internal/usecase/form"
+	lockusecase "github.com/mashurimansur/goCMS/internal/usecase/lock"
+	mediausecase "github.com/mashurimansur/goCMS/internal/usecase/media"
+	menuusecase "github.com/mashurimansur/goCMS/internal/usecase/menu"
+	previewusecase "github.com/mashurimansur/goCMS/internal/usecase/preview"
+	redirectusecase "github.com/mashurimansur/goCMS/internal/usecase/redirect"
+	seousecase "github.com/mashurimansur/goCMS/internal/usecase/seo"
+	snippetusecase "github.com/mashurimansur/goCMS/internal/usecase/snippet"
+	tagusecase "github.com/mashurimansur/goCMS/internal/usecase/tag"
+	themeusecase "github.com/mashurimansur/goCMS/internal/usecase/theme"
+	trashusecase "github.com/mashurimansur/goCMS/internal/usecase/trash"
+	userusecase "github.com/mashurimansur/goCMS/internal/usecase/user"
+	"github.com/mashurimansur/goCMS/internal/utils/imaging"
+	"github.com/mashurimansur/goCMS/internal/utils/markdown"
+	"github.com/mashurimansur/goCMS/internal/utils/signer"
+	"github.com/mashurimansur/goCMS/internal/utils/slug"
+)
+
+const maxPageSize = 100
+
+// Readers unlock password-protected entries with the password in this header or cookie.
+const (
+	contentPasswordHeader = "X-Content-Password"
+	contentPasswordCookie = "content_password"
+)
+
+// respondError writes err as a JSON error payload using the status code that matches its kind.
+// Entry and form submission validation errors also list the problem of every offending field.
+func respondError(c *gin.Context, err error) {
+	var validationErr *contenttype.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error(), "fields": validationErr.Fields})
+		return
+	}
+	var submissionErr *form.ValidationError
+	if errors.As(err, &submissionErr) {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error(), "fields": submissionErr.Fields})
+		return
+	}
+
+	status := errorStatus(err)
+	// A stale version named by a precondition header is a failed precondition, not a conflict.
+	if errors.Is(err, lock.ErrVersionConflict) && c.GetHeader("If-Match") != "" {
+		status = http.StatusPreconditionFailed
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, content.ErrNotFound),
+		errors.Is(err, category.ErrNotFound),
+		errors.Is(err, tag.ErrNotFound),
+		errors.Is(err, redirect.ErrNotFound),
+		errors.Is(err, media.ErrNotFound),
+		errors.Is(err, contenttype.ErrNotFound),
+		errors.Is(err, contenttype.ErrEntryNotFound),
+		errors.Is(err, comment.ErrNotFound),
+		errors.Is(err, menu.ErrNotFound),
+		errors.Is(err, seo.ErrNotFound),
+		errors.Is(err, preview.ErrNotFound),
+		errors.Is(err, lock.ErrNotFound),
+		errors.Is(err, trash.ErrNotFound),
+		errors.Is(err, bulk.ErrNotFound),
+		errors.Is(err, theme.ErrNotFound),
+		errors.Is(err, snippet.ErrNotFound),
+		errors.Is(err, form.ErrNotFound),
+		errors.Is(err, form.ErrSubmissionNotFound),
+		errors.Is(err, mediausecase.ErrUnknownSize),
+		errors.Is(err, userusecase.ErrUserNotFound),
+		errors.Is(err, feedusecase.ErrUnknownAuthor):
+		return http.StatusNotFound
+	case errors.Is(err, contentusecase.ErrInvalidStatus),
+		errors.Is(err, content.ErrInvalidVisibility),
+		errors.Is(err, contentusecase.ErrUnsupportedLocale),
+		errors.Is(err, contentusecase.ErrInvalidTranslation),
+		errors.Is(err, contentusecase.ErrEmptyQuery),
+		errors.Is(err, categoryusecase.ErrCyclicParent),
+		errors.Is(err, tagusecase.ErrSelfMerge),
+		errors.Is(err, redirectusecase.ErrInvalidRule),
+		errors.Is(err, mediausecase.ErrEmptyFile),
+		errors.Is(err, mediausecase.ErrNotImage),
+		errors.Is(err, mediausecase.ErrInvalidFocalPoint),
+		errors.Is(err, imaging.ErrInvalidParams),
+		errors.Is(err, markdown.ErrUnknownFormat),
+		errors.Is(err, block.ErrUnknownType),
+		errors.Is(err, block.ErrInvalidData),
+		errors.Is(err, contenttype.ErrInvalidDefinition),
+		errors.Is(err, contenttype.ErrInvalidEntry),
+		errors.Is(err, contenttypeusecase.ErrInvalidStatus),
+		errors.Is(err, contenttypeusecase.ErrFieldNotIndexed),
+		errors.Is(err, contenttypeusecase.ErrInvalidInclude),
+		errors.Is(err, commentusecase.ErrInvalidComment),
+		errors.Is(err, commentusecase.ErrInvalidParent),
+		errors.Is(err, commentusecase.ErrInvalidAction),
+		errors.Is(err, menuusecase.ErrInvalidMenu),
+		errors.Is(err, menuusecase.ErrInvalidItem),
+		errors.Is(err, seousecase.ErrInvalidMetadata),
+		errors.Is(err, previewusecase.ErrInvalidDuration),
+		errors.Is(err, lockusecase.ErrInvalidResource),
+		errors.Is(err, trashusecase.ErrInvalidType),
+		errors.Is(err, bulkusecase.ErrInvalidRequest),
+		errors.Is(err, bulkusecase.ErrTooManyItems),
+		errors.Is(err, themeusecase.ErrInvalidTheme),
+		errors.Is(err, snippetusecase.ErrInvalidSnippet),
+		errors.Is(err, form.ErrInvalidDefinition),
+		errors.Is(err, form.ErrInvalidSubmission),
+		errors.Is(err, slug.ErrExhausted):
+		return http.StatusBadRequest
+	case errors.Is(err, signer.ErrInvalidSignature),
+		errors.Is(err, signer.ErrExpired),
+		errors.Is(err, previewusecase.ErrInvalidPreview),
+		errors.Is(err, bulkusecase.ErrAdminRequired),
+		errors.Is(err, userusecase.ErrAdminRequired),
+		errors.Is(err, trashusecase.ErrAdminRequired):
+		return http.StatusForbidden
+	case errors.Is(err, contenttypeusecase.ErrTypeInUse),
+		errors.Is(err, contenttypeusecase.ErrReferenced),
+		errors.Is(err, contentusecase.ErrTranslationExists),
+		errors.Is(err, menuusecase.ErrMenuExists),
+		errors.Is(err, snippetusecase.ErrSnippetExists),
+		errors.Is(err, snippetusecase.ErrSnippetInUse),
+		errors.Is(err, formusecase.ErrFormExists),
+		errors.Is(err, lock.ErrVersionConflict),
+		errors.Is(err, lockusecase.ErrLocked):
+		return http.StatusConflict
+	case errors.Is(err, previewusecase.ErrPreviewOutdated):
+		return http.StatusGone
+	case errors.Is(err, mediausecase.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, mediausecase.ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, formusecase.ErrRateLimited):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// viewer returns the reader of a public request, as identified by the reader middleware, along
+// with the content password they sent. Responses that depend on who reads them are not cached by
+// shared caches.
+func viewer(c *gin.Context) content.Viewer {
+	v := content.Viewer{
+		UserID:   c.GetString("user_id"),
+		Role:     c.GetString("user_role"),
+		Password: c.GetHeader(contentPasswordHeader),
+	}
+	if v.Password == "" {
+		v.Password, _ = c.Cookie(contentPasswordCookie)
+	}
+	if v != (content.Viewer{}) {
+		c.Header("Cache-Control", "private")
+	}
+	return v
+}
+
+// pagination reads limit and offset query parameters, clamping them to sane bounds.
+func pagination(c *gin.Context) (int, int) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
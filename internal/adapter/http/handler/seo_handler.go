@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/seo"
+	seousecase "github.com/mashurimansur/goCMS/internal/usecase/seo"
+)
+
+// SEOHandler exposes SEO metadata endpoints along with the site-level sitemap and robots.txt.
+type SEOHandler struct {
+	seoUseCase seousecase.UseCase
+}
+
+func NewSEOHandler(seoUseCase seousecase.UseCase) *SEOHandler {
+	return &SEOHandler{
+		seoUseCase: seoUseCase,
+	}
+}
+
+func (h *SEOHandler) Register(router *gin.RouterGroup, authMiddleware, readerMiddleware gin.HandlerFunc) {
+	router.GET("/seo/contents/:id", readerMiddleware, h.publishedTags)
+	router.GET("/admin/contents/:id/seo", authMiddleware, h.get)
+	router.PUT("/admin/contents/:id/seo", authMiddleware, h.save)
+}
+
+// RegisterSite installs the crawler endpoints at the root of the site.
+func (h *SEOHandler) RegisterSite(router gin.IRoutes) {
+	router.GET("/sitemap.xml", h.sitemap)
+	router.GET("/sitemaps/:file", h.sitemapPage)
+	router.GET("/robots.txt", h.robots)
+}
+
+type seoRequest struct {
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	CanonicalURL    string `json:"canonical_url"`
+	NoIndex         bool   `json:"noindex"`
+	OGImageID       string `json:"og_image_id"`
+}
+
+type seoResponse struct {
+	Metadata *seo.Metadata `json:"metadata"`
+	Tags     *seo.Tags     `json:"tags"`
+}
+
+// @Summary      Get SEO tags
+// @Description  Get the resolved head tags of a published content entry, with defaults derived from the entry for blank fields
+// @Tags         seo
+// @Produce      json
+// @Param        id   path      string  true  "Content ID"
+// @Success      200  {object}  seo.Tags
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /seo/contents/{id} [get]
+func (h *SEOHandler) publishedTags(c *gin.Context) {
+	tags, err := h.seoUseCase.PublishedTags(c.Request.Context(), c.Param("id"), viewer(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// @Summary      Get SEO metadata
+// @Description  Get the SEO fields stored for a content entry along with the tags they resolve to
+// @Tags         seo
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Content ID"
+// @Success      200  {object}  seoResponse
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/{id}/seo [get]
+func (h *SEOHandler) get(c *gin.Context) {
+	h.respondMetadata(c, http.StatusOK, c.Param("id"))
+}
+
+// @Summary      Save SEO metadata
+// @Description  Set the meta title and description, canonical URL, noindex flag and Open Graph image of a content entry. Blank fields use defaults.
+// @Tags         seo
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  string      true  "Content ID"
+// @Param        request body  seoRequest  true  "SEO Request"
+// @Success      200  {object}  seoResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/contents/{id}/seo [put]
+func (h *SEOHandler) save(c *gin.Context) {
+	var req seoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	m := &seo.Metadata{
+		ContentID:       c.Param("id"),
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+		CanonicalURL:    req.CanonicalURL,
+		NoIndex:         req.NoIndex,
+		OGImageID:       req.OGImageID,
+	}
+	if err := h.seoUseCase.Save(c.Request.Context(), m); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondMetadata(c, http.StatusOK, m.ContentID)
+}
+
+func (h *SEOHandler) respondMetadata(c *gin.Context, status int, contentID string) {
+	m, err := h.seoUseCase.Get(c.Request.Context(), contentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	tags, err := h.seoUseCase.Tags(c.Request.Context(), contentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(status, seoResponse{Metadata: m, Tags: tags})
+}
+
+// @Summary      Sitemap
+// @Description  List the published, indexable entries with their last modification time. Sites with more than 50,000 URLs get a sitemap index of /sitemaps/{n}.xml files instead.
+// @Tags         seo
+// @Produce      xml
+// @Success      200
+// @Failure      500  {object}  map[string]string
+// @Router       /sitemap.xml [get]
+func (h *SEOHandler) sitemap(c *gin.Context) {
+	sitemap, err := h.seoUseCase.Sitemap(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	writeSitemap(c, sitemap)
+}
+
+// @Summary      Sitemap page
+// @Description  Get one file of a sitemap split by the sitemap index, e.g. /sitemaps/2.xml
+// @Tags         seo
+// @Produce      xml
+// @Param        file  path  string  true  "Page file name, e.g. 2.xml"
+// @Success      200
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /sitemaps/{file} [get]
+func (h *SEOHandler) sitemapPage(c *gin.Context) {
+	n, err := strconv.Atoi(strings.TrimSuffix(c.Param("file"), ".xml"))
+	if err != nil || !strings.HasSuffix(c.Param("file"), ".xml") {
+		respondError(c, seo.ErrNotFound)
+		return
+	}
+
+	sitemap, err := h.seoUseCase.SitemapPage(c.Request.Context(), n)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	writeSitemap(c, sitemap)
+}
+
+// @Summary      robots.txt
+// @Description  Crawler rules built from the indexing settings, pointing to the sitemap
+// @Tags         seo
+// @Produce      plain
+// @Success      200  {string}  string
+// @Router       /robots.txt [get]
+func (h *SEOHandler) robots(c *gin.Context) {
+	c.String(http.StatusOK, h.seoUseCase.Robots())
+}
+
+func writeSitemap(c *gin.Context, sitemap *seo.Sitemap) {
+	body, err := sitemap.XML()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
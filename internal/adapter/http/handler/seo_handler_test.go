@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/seo"
+	seousecase "github.com/mashurimansur/goCMS/internal/usecase/seo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSEOUseCase is a mock implementation of seousecase.UseCase
+type MockSEOUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSEOUseCase) Get(ctx context.Context, contentID string) (*seo.Metadata, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).(*seo.Metadata), args.Error(1)
+}
+
+func (m *MockSEOUseCase) Save(ctx context.Context, md *seo.Metadata) error {
+	args := m.Called(ctx, md)
+	return args.Error(0)
+}
+
+func (m *MockSEOUseCase) Tags(ctx context.Context, contentID string) (*seo.Tags, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).(*seo.Tags), args.Error(1)
+}
+
+func (m *MockSEOUseCase) PublishedTags(ctx context.Context, contentID string, viewer content.Viewer) (*seo.Tags, error) {
+	args := m.Called(ctx, contentID, viewer)
+	return args.Get(0).(*seo.Tags), args.Error(1)
+}
+
+func (m *MockSEOUseCase) Sitemap(ctx context.Context) (*seo.Sitemap, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*seo.Sitemap), args.Error(1)
+}
+
+func (m *MockSEOUseCase) SitemapPage(ctx context.Context, n int) (*seo.Sitemap, error) {
+	args := m.Called(ctx, n)
+	return args.Get(0).(*seo.Sitemap), args.Error(1)
+}
+
+func (m *MockSEOUseCase) Robots() string {
+	return m.Called().String(0)
+}
+
+func newSEORouter(uc *MockSEOUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) { c.Next() }
+	h := NewSEOHandler(uc)
+	h.Register(router.Group("/api/v1"), authMiddleware, authMiddleware)
+	h.RegisterSite(router)
+	return router
+}
+
+func TestSEOHandler_Sitemap(t *testing.T) {
+	mockUseCase := new(MockSEOUseCase)
+	router := newSEORouter(mockUseCase)
+
+	mockUseCase.On("Sitemap", mock.Anything).Return(&seo.Sitemap{URLs: []seo.SitemapURL{
+		{Loc: "https://example.com/about?a=1&b=2", LastMod: time.Date(2025, 4, 1, 8, 0, 0, 0, time.UTC)},
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/sitemap.xml", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>https://example.com/about?a=1&amp;b=2</loc><lastmod>2025-04-01T08:00:00Z</lastmod></url></urlset>`,
+		w.Body.String())
+}
+
+func TestSEOHandler_SitemapIndex(t *testing.T) {
+	mockUseCase := new(MockSEOUseCase)
+	router := newSEORouter(mockUseCase)
+
+	mockUseCase.On("Sitemap", mock.Anything).Return(&seo.Sitemap{Pages: []string{"https://example.com/sitemaps/1.xml", "https://example.com/sitemaps/2.xml"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/sitemap.xml", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><sitemap><loc>https://example.com/sitemaps/1.xml</loc></sitemap>`)
+}
+
+func TestSEOHandler_SitemapPage(t *testing.T) {
+	mockUseCase := new(MockSEOUseCase)
+	router := newSEORouter(mockUseCase)
+
+	mockUseCase.On("SitemapPage", mock.Anything, 2).Return(&seo.Sitemap{URLs: []seo.SitemapURL{{Loc: "https://example.com/a"}}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/sitemaps/2.xml", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<url><loc>https://example.com/a</loc></url>")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sitemaps/two.xml", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSEOHandler_Robots(t *testing.T) {
+	mockUseCase := new(MockSEOUseCase)
+	router := newSEORouter(mockUseCase)
+
+	mockUseCase.On("Robots").Return("User-agent: *\nDisallow: /\n")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/robots.txt", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "User-agent: *\nDisallow: /\n", w.Body.String())
+}
+
+func TestSEOHandler_Save(t *testing.T) {
+	mockUseCase := new(MockSEOUseCase)
+	router := newSEORouter(mockUseCase)
+
+	stored := &seo.Metadata{ContentID: "post-1", MetaTitle: "We launched", NoIndex: true}
+	mockUseCase.On("Save", mock.Anything, &seo.Metadata{ContentID: "post-1", MetaTitle: "We launched", NoIndex: true}).Return(nil)
+	mockUseCase.On("Get", mock.Anything, "post-1").Return(stored, nil)
+	mockUseCase.On("Tags", mock.Anything, "post-1").Return(&seo.Tags{Title: "We launched", Robots: "noindex, follow"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/contents/post-1/seo", bytes.NewBufferString(`{"meta_title":"We launched","noindex":true}`))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"robots":"noindex, follow"`)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestSEOHandler_Save_Invalid(t *testing.T) {
+	mockUseCase := new(MockSEOUseCase)
+	router := newSEORouter(mockUseCase)
+
+	mockUseCase.On("Save", mock.Anything, mock.Anything).Return(seousecase.ErrInvalidMetadata)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/contents/post-1/seo", bytes.NewBufferString(`{"canonical_url":"javascript:alert(1)"}`))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/snippet"
+	snippetusecase "github.com/mashurimansur/goCMS/internal/usecase/snippet"
+)
+
+// SnippetHandler exposes admin HTTP endpoints for reusable content snippets.
+type SnippetHandler struct {
+	snippetUseCase snippetusecase.UseCase
+}
+
+func NewSnippetHandler(snippetUseCase snippetusecase.UseCase) *SnippetHandler {
+	return &SnippetHandler{
+		snippetUseCase: snippetUseCase,
+	}
+}
+
+func (h *SnippetHandler) Register(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	admin := router.Group("/admin/snippets")
+	admin.Use(authMiddleware)
+	{
+		admin.POST("", h.create)
+		admin.GET("", h.list)
+		admin.GET("/:id", h.get)
+		admin.GET("/:id/usages", h.usages)
+		admin.PUT("/:id", h.update)
+		admin.DELETE("/:id", h.delete)
+	}
+}
+
+type snippetRequest struct {
+	Name       string        `json:"name"`
+	Title      string        `json:"title"`
+	Body       string        `json:"body"`
+	BodyFormat string        `json:"body_format"`
+	Blocks     []block.Block `json:"blocks"`
+	// Version is the version being replaced on update; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+func (r snippetRequest) toSnippet() *snippet.Snippet {
+	return &snippet.Snippet{
+		Name:       r.Name,
+		Title:      r.Title,
+		Body:       r.Body,
+		BodyFormat: r.BodyFormat,
+		Blocks:     r.Blocks,
+	}
+}
+
+// @Summary      Create snippet
+// @Description  Create a reusable snippet. Content bodies include it with a {{snippet:name}} token, or a block of type snippet, resolved when entries are read for the public site.
+// @Tags         snippets
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body snippetRequest true "Snippet Request"
+// @Success      201  {object}  snippet.Snippet
+// @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/snippets [post]
+func (h *SnippetHandler) create(c *gin.Context) {
+	var req snippetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s := req.toSnippet()
+	if err := h.snippetUseCase.Create(c.Request.Context(), s); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, s.Version)
+	c.JSON(http.StatusCreated, s)
+}
+
+// @Summary      List snippets
+// @Description  List snippets ordered by name, each with the number of entries referencing it
+// @Tags         snippets
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit   query     int  false  "Limit"  default(10)
+// @Param        offset  query     int  false  "Offset" default(0)
+// @Success      200  {array}   snippet.Snippet
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/snippets [get]
+func (h *SnippetHandler) list(c *gin.Context) {
+	limit, offset := pagination(c)
+	snippets, err := h.snippetUseCase.List(c.Request.Context(), limit, offset)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, snippets)
+}
+
+// @Summary      Get snippet
+// @Description  Get a snippet by ID with the number of entries referencing it
+// @Tags         snippets
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Snippet ID"
+// @Success      200  {object}  snippet.Snippet
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/snippets/{id} [get]
+func (h *SnippetHandler) get(c *gin.Context) {
+	s, err := h.snippetUseCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, s.Version)
+	c.JSON(http.StatusOK, s)
+}
+
+// @Summary      List snippet usages
+// @Description  List the entries outside the trash whose body references a snippet, most recently updated first
+// @Tags         snippets
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string  true   "Snippet ID"
+// @Param        limit   query     int     false  "Limit"  default(10)
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Success      200  {array}   snippet.Usage
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/snippets/{id}/usages [get]
+func (h *SnippetHandler) usages(c *gin.Context) {
+	limit, offset := pagination(c)
+	usages, err := h.snippetUseCase.Usages(c.Request.Context(), c.Param("id"), limit, offset)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usages)
+}
+
+// @Summary      Update snippet
+// @Description  Change the title or body of a snippet. Every entry referencing it shows the new version, and its revision changes so static exports render it again. The name cannot change.
+// @Tags         snippets
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string          true   "Snippet ID"
+// @Param        If-Match  header  string          false  "ETag of the version being replaced"
+// @Param        request   body    snippetRequest  true   "Snippet Request"
+// @Success      200  {object}  snippet.Snippet
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/snippets/{id} [put]
+func (h *SnippetHandler) update(c *gin.Context) {
+	var req snippetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	s := req.toSnippet()
+	s.ID = c.Param("id")
+	s.Version = version
+	if err := h.snippetUseCase.Update(c.Request.Context(), s); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, s.Version)
+	c.JSON(http.StatusOK, s)
+}
+
+// @Summary      Delete snippet
+// @Description  Delete a snippet no entry references anymore
+// @Tags         snippets
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Snippet ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/snippets/{id} [delete]
+func (h *SnippetHandler) delete(c *gin.Context) {
+	if err := h.snippetUseCase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "snippet deleted successfully"})
+}
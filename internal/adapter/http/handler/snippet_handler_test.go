@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/snippet"
+	snippetusecase "github.com/mashurimansur/goCMS/internal/usecase/snippet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSnippetUseCase is a mock implementation of snippetusecase.UseCase
+type MockSnippetUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSnippetUseCase) Create(ctx context.Context, s *snippet.Snippet) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockSnippetUseCase) Get(ctx context.Context, id string) (*snippet.Snippet, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*snippet.Snippet), args.Error(1)
+}
+
+func (m *MockSnippetUseCase) Update(ctx context.Context, s *snippet.Snippet) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockSnippetUseCase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSnippetUseCase) List(ctx context.Context, limit, offset int) ([]*snippet.Snippet, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*snippet.Snippet), args.Error(1)
+}
+
+func (m *MockSnippetUseCase) Usages(ctx context.Context, id string, limit, offset int) ([]*snippet.Usage, error) {
+	args := m.Called(ctx, id, limit, offset)
+	return args.Get(0).([]*snippet.Usage), args.Error(1)
+}
+
+func (m *MockSnippetUseCase) Expand(ctx context.Context, entries ...*content.Content) error {
+	args := m.Called(ctx, entries)
+	return args.Error(0)
+}
+
+func newSnippetRouter(uc *MockSnippetUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) { c.Next() }
+	NewSnippetHandler(uc).Register(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestSnippetHandler_Create_Conflict(t *testing.T) {
+	mockUseCase := new(MockSnippetUseCase)
+	router := newSnippetRouter(mockUseCase)
+
+	body, _ := json.Marshal(snippetRequest{Name: "footer", Body: "Bye"})
+	mockUseCase.On("Create", mock.Anything, &snippet.Snippet{Name: "footer", Body: "Bye"}).Return(snippetusecase.ErrSnippetExists)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/snippets", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestSnippetHandler_Update(t *testing.T) {
+	mockUseCase := new(MockSnippetUseCase)
+	router := newSnippetRouter(mockUseCase)
+
+	body, _ := json.Marshal(snippetRequest{Body: "See you"})
+	mockUseCase.On("Update", mock.Anything, &snippet.Snippet{ID: "s-1", Body: "See you", Version: 2}).
+		Run(func(args mock.Arguments) {
+			s := args.Get(1).(*snippet.Snippet)
+			s.Version = 3
+			s.UsageCount = 5
+		}).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/snippets/s-1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"2"`)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	var got snippet.Snippet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, 5, got.UsageCount)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestSnippetHandler_Usages(t *testing.T) {
+	mockUseCase := new(MockSnippetUseCase)
+	router := newSnippetRouter(mockUseCase)
+
+	usages := []*snippet.Usage{{ContentID: "c-1", Type: "post", Title: "Hello"}}
+	mockUseCase.On("Usages", mock.Anything, "s-1", 20, 0).Return(usages, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/snippets/s-1/usages?limit=20", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var got []*snippet.Usage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, usages, got)
+}
+
+func TestSnippetHandler_Delete_InUse(t *testing.T) {
+	mockUseCase := new(MockSnippetUseCase)
+	router := newSnippetRouter(mockUseCase)
+
+	mockUseCase.On("Delete", mock.Anything, "s-1").Return(snippetusecase.ErrSnippetInUse)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/admin/snippets/s-1", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
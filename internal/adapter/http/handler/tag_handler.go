@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	tagusecase "github.com/mashurimansur/goCMS/internal/usecase/tag"
+)
+
+// TagHandler exposes admin and public HTTP endpoints for tags.
+type TagHandler struct {
+	tagUseCase tagusecase.UseCase
+}
+
+func NewTagHandler(tagUseCase tagusecase.UseCase) *TagHandler {
+	return &TagHandler{
+		tagUseCase: tagUseCase,
+	}
+}
+
+// Register mounts the public tag routes, which serve readers identified by readerMiddleware, and
+// the admin routes behind authMiddleware.
+func (h *TagHandler) Register(router *gin.RouterGroup, authMiddleware, readerMiddleware gin.HandlerFunc) {
+	public := router.Group("/tags")
+	public.Use(readerMiddleware)
+	{
+		public.GET("", h.list)
+		public.GET("/:slug/contents", h.listContent)
+	}
+
+	admin := router.Group("/admin/tags")
+	admin.Use(authMiddleware)
+	{
+		admin.POST("", h.create)
+		admin.GET("", h.list)
+		admin.GET("/autocomplete", h.autocomplete)
+		admin.GET("/:id", h.get)
+		admin.PUT("/:id", h.update)
+		admin.DELETE("/:id", h.delete)
+		admin.POST("/:id/merge", h.merge)
+	}
+}
+
+type tagRequest struct {
+	Name string `json:"name" binding:"required"`
+	Slug string `json:"slug"`
+	// Version is the version being replaced on update; the If-Match header takes precedence.
+	Version int `json:"version"`
+}
+
+type mergeTagRequest struct {
+	TargetID string `json:"target_id" binding:"required"`
+}
+
+// @Summary      Create tag
+// @Description  Create a new tag
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body tagRequest true "Tag Request"
+// @Success      201  {object}  tag.Tag
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/tags [post]
+func (h *TagHandler) create(c *gin.Context) {
+	var req tagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	t := &tag.Tag{Name: req.Name, Slug: req.Slug}
+	if err := h.tagUseCase.Create(c.Request.Context(), t); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, t)
+}
+
+// @Summary      Get tag
+// @Description  Get a tag by ID
+// @Tags         tags
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Tag ID"
+// @Success      200  {object}  tag.Tag
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/tags/{id} [get]
+func (h *TagHandler) get(c *gin.Context) {
+	t, err := h.tagUseCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, t.Version)
+	c.JSON(http.StatusOK, t)
+}
+
+// @Summary      Update tag
+// @Description  Update a tag by ID
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string      true   "Tag ID"
+// @Param        If-Match  header  string      false  "ETag of the version being replaced"
+// @Param        request   body    tagRequest  true   "Tag Request"
+// @Success      200  {object}  tag.Tag
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/tags/{id} [put]
+func (h *TagHandler) update(c *gin.Context) {
+	var req tagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c, req.Version)
+	if !ok {
+		return
+	}
+
+	t := &tag.Tag{ID: c.Param("id"), Name: req.Name, Slug: req.Slug, Version: version}
+	if err := h.tagUseCase.Update(c.Request.Context(), t); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, t.Version)
+	c.JSON(http.StatusOK, t)
+}
+
+// @Summary      Delete tag
+// @Description  Move a tag to the trash
+// @Tags         tags
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Tag ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/tags/{id} [delete]
+func (h *TagHandler) delete(c *gin.Context) {
+	if err := h.tagUseCase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "tag deleted successfully"})
+}
+
+// @Summary      List tags
+// @Description  List tags ordered by name with pagination
+// @Tags         tags
+// @Produce      json
+// @Param        limit   query     int  false  "Limit"  default(10)
+// @Param        offset  query     int  false  "Offset" default(0)
+// @Success      200  {array}   tag.Tag
+// @Failure      500  {object}  map[string]string
+// @Router       /tags [get]
+func (h *TagHandler) list(c *gin.Context) {
+	limit, offset := pagination(c)
+	tags, err := h.tagUseCase.List(c.Request.Context(), limit, offset)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// @Summary      Autocomplete tags
+// @Description  Suggest tags whose name starts with the query
+// @Tags         tags
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q      query     string  true   "Name prefix"
+// @Param        limit  query     int     false  "Limit"  default(10)
+// @Success      200  {array}   tag.Tag
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/tags/autocomplete [get]
+func (h *TagHandler) autocomplete(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	tags, err := h.tagUseCase.Autocomplete(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// @Summary      Merge tags
+// @Description  Move all content from this tag to the target tag and delete this tag
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  string           true  "Source tag ID"
+// @Param        request body  mergeTagRequest  true  "Merge Request"
+// @Success      200  {object}  tag.Tag
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/tags/{id}/merge [post]
+func (h *TagHandler) merge(c *gin.Context) {
+	var req mergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	target, err := h.tagUseCase.Merge(c.Request.Context(), c.Param("id"), req.TargetID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, target)
+}
+
+// @Summary      List content by tag
+// @Description  List published content carrying a tag
+// @Tags         tags
+// @Produce      json
+// @Param        slug    path      string  true   "Tag slug"
+// @Param        limit   query     int     false  "Limit"  default(10)
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Success      200  {array}   content.Content
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /tags/{slug}/contents [get]
+func (h *TagHandler) listContent(c *gin.Context) {
+	limit, offset := pagination(c)
+	entries, err := h.tagUseCase.ListContent(c.Request.Context(), c.Param("slug"), limit, offset, viewer(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
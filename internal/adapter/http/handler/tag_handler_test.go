@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	tagusecase "github.com/mashurimansur/goCMS/internal/usecase/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTagUseCase is a mock implementation of tagusecase.UseCase
+type MockTagUseCase struct {
+	mock.Mock
+}
+
+func (m *MockTagUseCase) Create(ctx context.Context, t *tag.Tag) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockTagUseCase) Get(ctx context.Context, id string) (*tag.Tag, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagUseCase) Update(ctx context.Context, t *tag.Tag) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockTagUseCase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagUseCase) List(ctx context.Context, limit, offset int) ([]*tag.Tag, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagUseCase) Autocomplete(ctx context.Context, prefix string, limit int) ([]*tag.Tag, error) {
+	args := m.Called(ctx, prefix, limit)
+	return args.Get(0).([]*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagUseCase) Merge(ctx context.Context, sourceID, targetID string) (*tag.Tag, error) {
+	args := m.Called(ctx, sourceID, targetID)
+	return args.Get(0).(*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagUseCase) ListContent(ctx context.Context, slug string, limit, offset int, viewer content.Viewer) ([]*content.Content, error) {
+	args := m.Called(ctx, slug, limit, offset, viewer)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockTagUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockTagUseCase) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagUseCase) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newTagRouter(uc *MockTagUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) { c.Next() }
+	NewTagHandler(uc).Register(router.Group("/api/v1"), authMiddleware, func(c *gin.Context) { c.Next() })
+	return router
+}
+
+func TestTagHandler_Autocomplete(t *testing.T) {
+	mockUseCase := new(MockTagUseCase)
+	router := newTagRouter(mockUseCase)
+
+	mockUseCase.On("Autocomplete", mock.Anything, "go", 5).Return([]*tag.Tag{{Name: "golang"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/tags/autocomplete?q=go&limit=5", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestTagHandler_Merge(t *testing.T) {
+	mockUseCase := new(MockTagUseCase)
+	router := newTagRouter(mockUseCase)
+
+	body, _ := json.Marshal(mergeTagRequest{TargetID: "target"})
+	mockUseCase.On("Merge", mock.Anything, "source", "target").Return(&tag.Tag{ID: "target"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/tags/source/merge", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestTagHandler_Merge_Self(t *testing.T) {
+	mockUseCase := new(MockTagUseCase)
+	router := newTagRouter(mockUseCase)
+
+	body, _ := json.Marshal(mergeTagRequest{TargetID: "same"})
+	mockUseCase.On("Merge", mock.Anything, "same", "same").Return((*tag.Tag)(nil), tagusecase.ErrSelfMerge)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/tags/same/merge", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTagHandler_ListContent(t *testing.T) {
+	mockUseCase := new(MockTagUseCase)
+	router := newTagRouter(mockUseCase)
+
+	mockUseCase.On("ListContent", mock.Anything, "go", 10, 0, content.Viewer{}).Return([]*content.Content{{ID: "content-1"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/tags/go/contents", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTagHandler_Create(t *testing.T) {
+	mockUseCase := new(MockTagUseCase)
+	router := newTagRouter(mockUseCase)
+
+	body, _ := json.Marshal(tagRequest{Name: "Go", Slug: "go"})
+	mockUseCase.On("Create", mock.Anything, &tag.Tag{Name: "Go", Slug: "go"}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/tags", bytes.NewBuffer(body))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestTagHandler_Update_IfMatch(t *testing.T) {
+	mockUseCase := new(MockTagUseCase)
+	router := newTagRouter(mockUseCase)
+
+	mockUseCase.On("Update", mock.Anything, &tag.Tag{ID: "tag-1", Name: "Go", Version: 3}).Run(func(args mock.Arguments) {
+		args.Get(1).(*tag.Tag).Version = 4
+	}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/tags/tag-1", bytes.NewBufferString(`{"name":"Go"}`))
+	req.Header.Set("If-Match", `"3"`)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+}
+
+func TestTagHandler_Update_VersionConflict(t *testing.T) {
+	mockUseCase := new(MockTagUseCase)
+	router := newTagRouter(mockUseCase)
+
+	mockUseCase.On("Update", mock.Anything, mock.AnythingOfType("*tag.Tag")).Return(lock.ErrVersionConflict)
+
+	// A stale If-Match header is a failed precondition.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/tags/tag-1", bytes.NewBufferString(`{"name":"Go"}`))
+	req.Header.Set("If-Match", `W/"2"`)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	// A stale version in the body is a conflict.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/api/v1/admin/tags/tag-1", bytes.NewBufferString(`{"name":"Go","version":2}`))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// If-Match must name a version.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/api/v1/admin/tags/tag-1", bytes.NewBufferString(`{"name":"Go"}`))
+	req.Header.Set("If-Match", `"abc"`)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	mockUseCase.AssertNumberOfCalls(t, "Update", 2)
+}
+
+func TestTagHandler_Update_VersionRequired(t *testing.T) {
+	mockUseCase := new(MockTagUseCase)
+	router := newTagRouter(mockUseCase)
+
+	// An update naming no version would overwrite whatever is current.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/tags/tag-1", bytes.NewBufferString(`{"name":"Go"}`))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	mockUseCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	// "*" overwrites knowingly.
+	mockUseCase.On("Update", mock.Anything, &tag.Tag{ID: "tag-1", Name: "Go"}).Return(nil)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/api/v1/admin/tags/tag-1", bytes.NewBufferString(`{"name":"Go"}`))
+	req.Header.Set("If-Match", "*")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
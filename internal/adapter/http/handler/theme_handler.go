@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+	themeusecase "github.com/mashurimansur/goCMS/internal/usecase/theme"
+	"github.com/mashurimansur/goCMS/internal/utils/locale"
+)
+
+// ThemeHandler serves the public site as HTML rendered by the active theme, along with the admin
+// endpoints switching themes.
+type ThemeHandler struct {
+	themeUseCase    themeusecase.UseCase
+	locales         locale.Settings
+	categoryPattern string
+	tagPattern      string
+}
+
+// NewThemeHandler returns a handler serving category and tag pages at categoryPattern and
+// tagPattern, paths holding a {slug} placeholder.
+func NewThemeHandler(themeUseCase themeusecase.UseCase, locales locale.Settings, categoryPattern, tagPattern string) *ThemeHandler {
+	return &ThemeHandler{
+		themeUseCase:    themeUseCase,
+		locales:         locales,
+		categoryPattern: categoryPattern,
+		tagPattern:      tagPattern,
+	}
+}
+
+func (h *ThemeHandler) Register(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	admin := router.Group("/admin/themes")
+	admin.Use(authMiddleware)
+	{
+		admin.GET("", h.list)
+		admin.PUT("/active", h.activate)
+	}
+}
+
+// RegisterSite installs the home page and the taxonomy pages at the root of the site, serving
+// readers identified by readerMiddleware. Entries are served by ResolvePermalink.
+func (h *ThemeHandler) RegisterSite(router gin.IRoutes, readerMiddleware gin.HandlerFunc) {
+	router.GET("/", readerMiddleware, h.home)
+	if h.categoryPattern != "" {
+		router.GET(routePath(h.categoryPattern), readerMiddleware, h.category)
+	}
+	if h.tagPattern != "" {
+		router.GET(routePath(h.tagPattern), readerMiddleware, h.tag)
+	}
+}
+
+// routePath turns a URL pattern with a {slug} placeholder into a route with a :slug parameter.
+func routePath(pattern string) string {
+	return strings.Replace(pattern, "{slug}", ":slug", 1)
+}
+
+type activateThemeRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// @Summary      List themes
+// @Description  List the installed themes along with the one the public site is rendered with
+// @Tags         themes
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  themeusecase.Themes
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/themes [get]
+func (h *ThemeHandler) list(c *gin.Context) {
+	themes, err := h.themeUseCase.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, themes)
+}
+
+// @Summary      Activate theme
+// @Description  Render the public site with another installed theme. Themes whose templates do not parse are rejected.
+// @Tags         themes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body  activateThemeRequest  true  "Theme"
+// @Success      200  {object}  themeusecase.Themes
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/themes/active [put]
+func (h *ThemeHandler) activate(c *gin.Context) {
+	var req activateThemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.themeUseCase.Activate(c.Request.Context(), req.Name); err != nil {
+		respondError(c, err)
+		return
+	}
+	h.list(c)
+}
+
+// @Summary      Home page
+// @Description  The latest entries rendered by the active theme
+// @Tags         themes
+// @Produce      html
+// @Param        page    query  int     false  "Page"  default(1)
+// @Param        locale  query  string  false  "Locale, overriding Accept-Language"
+// @Success      200  {string}  string
+// @Failure      404  {string}  string
+// @Router       / [get]
+func (h *ThemeHandler) home(c *gin.Context) {
+	page, err := h.themeUseCase.Home(c.Request.Context(), pageNumber(c), h.localeChain(c), viewer(c))
+	h.render(c, theme.TemplateHome, page, err)
+}
+
+// @Summary      Category page
+// @Description  The entries of a category and its descendants rendered by the active theme. The path follows CATEGORY_URL_PATTERN.
+// @Tags         themes
+// @Produce      html
+// @Param        slug    path   string  true   "Category slug"
+// @Param        page    query  int     false  "Page"  default(1)
+// @Param        locale  query  string  false  "Locale, overriding Accept-Language"
+// @Success      200  {string}  string
+// @Failure      404  {string}  string
+// @Router       /category/{slug} [get]
+func (h *ThemeHandler) category(c *gin.Context) {
+	page, err := h.themeUseCase.Category(c.Request.Context(), c.Param("slug"), pageNumber(c), h.localeChain(c), viewer(c))
+	h.render(c, theme.TemplateTaxonomy, page, err)
+}
+
+// @Summary      Tag page
+// @Description  The entries of a tag rendered by the active theme. The path follows TAG_URL_PATTERN.
+// @Tags         themes
+// @Produce      html
+// @Param        slug    path   string  true   "Tag slug"
+// @Param        page    query  int     false  "Page"  default(1)
+// @Param        locale  query  string  false  "Locale, overriding Accept-Language"
+// @Success      200  {string}  string
+// @Failure      404  {string}  string
+// @Router       /tag/{slug} [get]
+func (h *ThemeHandler) tag(c *gin.Context) {
+	page, err := h.themeUseCase.Tag(c.Request.Context(), c.Param("slug"), pageNumber(c), h.localeChain(c), viewer(c))
+	h.render(c, theme.TemplateTaxonomy, page, err)
+}
+
+// ResolvePermalink renders published content at its public permalink and permanently redirects
+// former permalinks. It replaces ContentHandler.ResolvePermalink as the engine's NoRoute handler
+// when the public site is rendered, so unknown paths get the theme's 404 page.
+func (h *ThemeHandler) ResolvePermalink(c *gin.Context) {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+
+	page, location, err := h.themeUseCase.Entry(c.Request.Context(), c.Request.URL.Path, h.localeChain(c), viewer(c))
+	if err == nil && location != "" {
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+	h.render(c, theme.TemplateSingle, page, err)
+}
+
+// render writes page rendered with the named template, or the error page matching err.
+func (h *ThemeHandler) render(c *gin.Context, template string, page *theme.Page, err error) {
+	status := http.StatusOK
+	if err != nil {
+		status = errorStatus(err)
+		if status != http.StatusNotFound {
+			log.Printf("cannot render %s: %v", c.Request.URL.Path, err)
+			c.String(status, http.StatusText(status))
+			return
+		}
+		template, page = theme.TemplateNotFound, h.themeUseCase.NotFound(c.Request.URL.Path, h.localeChain(c))
+	}
+	if page.Locale != "" {
+		c.Header("Content-Language", page.Locale)
+	}
+
+	var buf bytes.Buffer
+	if err := h.themeUseCase.Render(c.Request.Context(), &buf, template, page); err != nil {
+		if status == http.StatusNotFound {
+			// Themes need not ship a 404 page.
+			c.String(status, http.StatusText(status))
+			return
+		}
+		log.Printf("cannot render %s: %v", c.Request.URL.Path, err)
+		c.String(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+func (h *ThemeHandler) localeChain(c *gin.Context) []string {
+	c.Header("Vary", "Accept-Language")
+	return h.locales.Chain(h.locales.Negotiate(c.Query("locale"), c.GetHeader("Accept-Language")))
+}
+
+// pageNumber reads the page query parameter. Malformed numbers yield 0, which is not found.
+func pageNumber(c *gin.Context) int {
+	n, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
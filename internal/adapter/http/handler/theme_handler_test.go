@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+	themeusecase "github.com/mashurimansur/goCMS/internal/usecase/theme"
+	"github.com/mashurimansur/goCMS/internal/utils/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockThemeUseCase is a mock implementation of themeusecase.UseCase
+type MockThemeUseCase struct {
+	mock.Mock
+}
+
+func (m *MockThemeUseCase) List(ctx context.Context) (*themeusecase.Themes, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*themeusecase.Themes), args.Error(1)
+}
+
+func (m *MockThemeUseCase) Activate(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockThemeUseCase) Reload(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Render writes the template name and page title, failing for templates the test did not allow.
+func (m *MockThemeUseCase) Render(ctx context.Context, w io.Writer, template string, page *theme.Page) error {
+	args := m.Called(ctx, template)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s|%s", template, page.Title)
+	return err
+}
+
+func (m *MockThemeUseCase) Home(ctx context.Context, n int, locales []string, viewer content.Viewer) (*theme.Page, error) {
+	args := m.Called(ctx, n, locales, viewer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*theme.Page), args.Error(1)
+}
+
+func (m *MockThemeUseCase) Entry(ctx context.Context, path string, locales []string, viewer content.Viewer) (*theme.Page, string, error) {
+	args := m.Called(ctx, path, locales, viewer)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).(*theme.Page), args.String(1), args.Error(2)
+}
+
+func (m *MockThemeUseCase) Category(ctx context.Context, slug string, n int, locales []string, viewer content.Viewer) (*theme.Page, error) {
+	args := m.Called(ctx, slug, n, locales, viewer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*theme.Page), args.Error(1)
+}
+
+func (m *MockThemeUseCase) Tag(ctx context.Context, slug string, n int, locales []string, viewer content.Viewer) (*theme.Page, error) {
+	args := m.Called(ctx, slug, n, locales, viewer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*theme.Page), args.Error(1)
+}
+
+func (m *MockThemeUseCase) NotFound(path string, locales []string) *theme.Page {
+	return &theme.Page{Title: "Not found", Path: path}
+}
+
+func newThemeRouter(uc *MockThemeUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Next()
+	}
+	locales, _ := locale.Parse("en,id", "")
+	h := NewThemeHandler(uc, locales, "/category/{slug}", "/tag/{slug}")
+	h.Register(router.Group("/api/v1"), authMiddleware)
+	readerMiddleware := func(c *gin.Context) { c.Next() }
+	h.RegisterSite(router, readerMiddleware)
+	router.NoRoute(readerMiddleware, h.ResolvePermalink)
+	return router
+}
+
+func TestThemeHandler_Activate(t *testing.T) {
+	mockUseCase := new(MockThemeUseCase)
+	router := newThemeRouter(mockUseCase)
+
+	mockUseCase.On("Activate", mock.Anything, "minimal").Return(nil)
+	mockUseCase.On("List", mock.Anything).Return(&themeusecase.Themes{Active: "minimal", Available: []string{"default", "minimal"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/themes/active", strings.NewReader(`{"name":"minimal"}`))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"active":"minimal","available":["default","minimal"]}`, w.Body.String())
+}
+
+func TestThemeHandler_Activate_Invalid(t *testing.T) {
+	mockUseCase := new(MockThemeUseCase)
+	router := newThemeRouter(mockUseCase)
+
+	mockUseCase.On("Activate", mock.Anything, "broken").Return(themeusecase.ErrInvalidTheme)
+	mockUseCase.On("Activate", mock.Anything, "missing").Return(theme.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/themes/active", strings.NewReader(`{"name":"broken"}`))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/api/v1/admin/themes/active", strings.NewReader(`{"name":"missing"}`))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestThemeHandler_Home(t *testing.T) {
+	mockUseCase := new(MockThemeUseCase)
+	router := newThemeRouter(mockUseCase)
+
+	mockUseCase.On("Home", mock.Anything, 2, []string{"id", "en"}, content.Viewer{}).Return(&theme.Page{Title: "Beranda", Locale: "id"}, nil)
+	mockUseCase.On("Render", mock.Anything, theme.TemplateHome).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/?page=2", nil)
+	req.Header.Set("Accept-Language", "id")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "home|Beranda", w.Body.String())
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "id", w.Header().Get("Content-Language"))
+}
+
+func TestThemeHandler_Tag(t *testing.T) {
+	mockUseCase := new(MockThemeUseCase)
+	router := newThemeRouter(mockUseCase)
+
+	mockUseCase.On("Tag", mock.Anything, "go", 1, []string{"en"}, content.Viewer{}).Return(&theme.Page{Title: "Go"}, nil)
+	mockUseCase.On("Render", mock.Anything, theme.TemplateTaxonomy).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tag/go", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "taxonomy|Go", w.Body.String())
+}
+
+func TestThemeHandler_ResolvePermalink(t *testing.T) {
+	mockUseCase := new(MockThemeUseCase)
+	router := newThemeRouter(mockUseCase)
+
+	mockUseCase.On("Entry", mock.Anything, "/2025/04/hello", []string{"en"}, content.Viewer{}).Return(&theme.Page{Title: "Hello"}, "", nil)
+	mockUseCase.On("Entry", mock.Anything, "/old-hello", []string{"en"}, content.Viewer{}).Return(nil, "/2025/04/hello", nil)
+	mockUseCase.On("Render", mock.Anything, theme.TemplateSingle).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/2025/04/hello", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "single|Hello", w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/old-hello?ref=home", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/2025/04/hello?ref=home", w.Header().Get("Location"))
+}
+
+func TestThemeHandler_ResolvePermalink_NotFound(t *testing.T) {
+	mockUseCase := new(MockThemeUseCase)
+	router := newThemeRouter(mockUseCase)
+
+	mockUseCase.On("Entry", mock.Anything, "/missing", []string{"en"}, content.Viewer{}).Return(nil, "", content.ErrNotFound)
+	mockUseCase.On("Render", mock.Anything, theme.TemplateNotFound).Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/missing", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "404|Not found", w.Body.String())
+
+	// Themes without a 404 page fall back to plain text.
+	mockUseCase.On("Render", mock.Anything, theme.TemplateNotFound).Return(theme.ErrTemplateNotFound)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "Not Found", w.Body.String())
+}
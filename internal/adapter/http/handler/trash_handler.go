@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	trashusecase "github.com/mashurimansur/goCMS/internal/usecase/trash"
+)
+
+// TrashHandler lets signed-in users restore trashed items or purge them for good. Only admins manage
+// trashed users.
+type TrashHandler struct {
+	trashUseCase trashusecase.UseCase
+}
+
+func NewTrashHandler(trashUseCase trashusecase.UseCase) *TrashHandler {
+	return &TrashHandler{
+		trashUseCase: trashUseCase,
+	}
+}
+
+func (h *TrashHandler) Register(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	bin := router.Group("/admin/trash", authMiddleware)
+	bin.GET("", h.list)
+	bin.POST("/:type/:id/restore", h.restore)
+	bin.DELETE("/:type/:id", h.purge)
+}
+
+// @Summary      List trash
+// @Description  List trashed items, most recently trashed first, with the time each is purged
+// @Tags         trash
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type    query     string  false  "Item type: content, entry, category, tag, menu, comment, media or user"
+// @Param        limit   query     int     false  "Page size"  default(10)
+// @Param        offset  query     int     false  "Offset"     default(0)
+// @Success      200     {array}   trash.Item
+// @Failure      400     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Router       /admin/trash [get]
+func (h *TrashHandler) list(c *gin.Context) {
+	limit, offset := pagination(c)
+	items, err := h.trashUseCase.List(c.Request.Context(), c.Query("type"), limit, offset, c.GetString("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary      Restore from trash
+// @Description  Bring a trashed item back
+// @Tags         trash
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Item type: content, entry, category, tag, menu, comment, media or user"
+// @Param        id    path      string  true  "Item ID"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /admin/trash/{type}/{id}/restore [post]
+func (h *TrashHandler) restore(c *gin.Context) {
+	if err := h.trashUseCase.Restore(c.Request.Context(), c.Param("type"), c.Param("id"), c.GetString("user_id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "item restored successfully"})
+}
+
+// @Summary      Purge from trash
+// @Description  Delete a trashed item for good
+// @Tags         trash
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Item type: content, entry, category, tag, menu, comment, media or user"
+// @Param        id    path      string  true  "Item ID"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /admin/trash/{type}/{id} [delete]
+func (h *TrashHandler) purge(c *gin.Context) {
+	if err := h.trashUseCase.Purge(c.Request.Context(), c.Param("type"), c.Param("id"), c.GetString("user_id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "item purged successfully"})
+}
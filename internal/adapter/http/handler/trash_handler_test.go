@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	trashusecase "github.com/mashurimansur/goCMS/internal/usecase/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTrashUseCase is a mock implementation of trashusecase.UseCase
+type MockTrashUseCase struct {
+	mock.Mock
+}
+
+func (m *MockTrashUseCase) List(ctx context.Context, resourceType string, limit, offset int, userID string) ([]*trash.Item, error) {
+	args := m.Called(ctx, resourceType, limit, offset, userID)
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockTrashUseCase) Restore(ctx context.Context, resourceType, id, userID string) error {
+	args := m.Called(ctx, resourceType, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockTrashUseCase) Purge(ctx context.Context, resourceType, id, userID string) error {
+	args := m.Called(ctx, resourceType, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockTrashUseCase) PurgeExpired(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func newTrashRouter(uc *MockTrashUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Next()
+	}
+	NewTrashHandler(uc).Register(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestTrashHandler_List(t *testing.T) {
+	mockUseCase := new(MockTrashUseCase)
+	router := newTrashRouter(mockUseCase)
+
+	mockUseCase.On("List", mock.Anything, "media", 20, 0, "user-1").
+		Return([]*trash.Item{{Type: trash.TypeMedia, ID: "media-1", Title: "cat.png"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/trash?type=media&limit=20", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"title":"cat.png"`)
+}
+
+func TestTrashHandler_List_InvalidType(t *testing.T) {
+	mockUseCase := new(MockTrashUseCase)
+	router := newTrashRouter(mockUseCase)
+
+	mockUseCase.On("List", mock.Anything, "widget", 10, 0, "user-1").Return([]*trash.Item(nil), trashusecase.ErrInvalidType)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/admin/trash?type=widget", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTrashHandler_Restore(t *testing.T) {
+	mockUseCase := new(MockTrashUseCase)
+	router := newTrashRouter(mockUseCase)
+
+	mockUseCase.On("Restore", mock.Anything, "content", "post-1", "user-1").Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/trash/content/post-1/restore", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestTrashHandler_Purge_NotFound(t *testing.T) {
+	mockUseCase := new(MockTrashUseCase)
+	router := newTrashRouter(mockUseCase)
+
+	mockUseCase.On("Purge", mock.Anything, "user", "user-9", "user-1").Return(trash.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/admin/trash/user/user-9", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTrashHandler_Restore_UserNotAdmin(t *testing.T) {
+	mockUseCase := new(MockTrashUseCase)
+	router := newTrashRouter(mockUseCase)
+
+	mockUseCase.On("Restore", mock.Anything, "user", "user-9", "user-1").Return(trashusecase.ErrAdminRequired)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/trash/user/user-9/restore", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
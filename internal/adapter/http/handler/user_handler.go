@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mashurimansur/goCMS/internal/domain/user"
+	mediausecase "github.com/mashurimansur/goCMS/internal/usecase/media"
 	userusecase "github.com/mashurimansur/goCMS/internal/usecase/user"
 )
 
@@ -33,9 +35,13 @@ func (h *UserHandler) Register(router *gin.RouterGroup, authMiddleware gin.Handl
 		admin.PUT("/:id", h.updateProfile)
 		admin.GET("/", h.listUsers)
 		admin.DELETE("/:id", h.deleteUser)
+		admin.PUT("/:id/avatar", h.updateAvatar)
 	}
 }
 
+// maxAvatarRequestSize bounds avatar upload requests; the media pipeline applies its own file limit.
+const maxAvatarRequestSize = 5 << 20
+
 type registerRequest struct {
 	FullName string `json:"full_name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
@@ -138,6 +144,9 @@ func (h *UserHandler) getProfile(c *gin.Context) {
 		return
 	}
 
+	if u != nil {
+		setETag(c, u.Version)
+	}
 	c.JSON(http.StatusOK, u)
 }
 
@@ -147,10 +156,16 @@ func (h *UserHandler) getProfile(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        request body user.User true "User Update Request"
+// @Param        If-Match  header  string     false  "ETag of the version being replaced"
+// @Param        request   body    user.User  true   "User Update Request"
 // @Success      200  {object}  map[string]string
 // @Failure      400  {object}  map[string]string
 // @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Failure      428  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /users/profile [put]
 func (h *UserHandler) updateProfile(c *gin.Context) {
@@ -166,12 +181,19 @@ func (h *UserHandler) updateProfile(c *gin.Context) {
 	// 	return
 	// }
 
+	version, ok := ifMatch(c, u.Version)
+	if !ok {
+		return
+	}
+
 	u.ID = c.Param("id")
-	if err := h.userUseCase.UpdateProfile(c.Request.Context(), &u); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	u.Version = version
+	if err := h.userUseCase.UpdateProfile(c.Request.Context(), &u, c.GetString("user_id")); err != nil {
+		respondError(c, err)
 		return
 	}
 
+	setETag(c, u.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "profile updated successfully"})
 }
 
@@ -202,20 +224,68 @@ func (h *UserHandler) listUsers(c *gin.Context) {
 }
 
 // @Summary      Delete user
-// @Description  Delete a user by ID
+// @Description  Move a user to the trash
 // @Tags         users
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id   path      string  true  "User ID"
 // @Success      200  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /users/{id} [delete]
 func (h *UserHandler) deleteUser(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.userUseCase.DeleteUser(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userUseCase.DeleteUser(c.Request.Context(), id, c.GetString("user_id")); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
 }
+
+// @Summary      Upload avatar
+// @Description  Upload an image as the user's avatar. The avatar URL points at the avatar rendition
+// @Tags         users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      string  true  "User ID"
+// @Param        file  formData  file    true  "Avatar image"
+// @Success      200  {object}  user.User
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      413  {object}  map[string]string
+// @Failure      415  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/users/{id}/avatar [put]
+func (h *UserHandler) updateAvatar(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxAvatarRequestSize)
+
+	fileHeader, err := c.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondError(c, mediausecase.ErrFileTooLarge)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer file.Close()
+
+	u, err := h.userUseCase.UpdateAvatar(c.Request.Context(), c.Param("id"), c.GetString("user_id"), fileHeader.Filename, file)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, u.Version)
+	c.JSON(http.StatusOK, u)
+}
@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
 	"github.com/mashurimansur/goCMS/internal/domain/user"
+	mediausecase "github.com/mashurimansur/goCMS/internal/usecase/media"
+	userusecase "github.com/mashurimansur/goCMS/internal/usecase/user"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -35,8 +39,13 @@ func (m *MockUserUseCase) GetProfile(ctx context.Context, id string) (*user.User
 	return args.Get(0).(*user.User), args.Error(1)
 }
 
-func (m *MockUserUseCase) UpdateProfile(ctx context.Context, u *user.User) error {
-	args := m.Called(ctx, u)
+func (m *MockUserUseCase) Role(ctx context.Context, id string) (string, error) {
+	args := m.Called(ctx, id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserUseCase) UpdateProfile(ctx context.Context, u *user.User, actorID string) error {
+	args := m.Called(ctx, u, actorID)
 	return args.Error(0)
 }
 
@@ -45,11 +54,31 @@ func (m *MockUserUseCase) ListUsers(ctx context.Context, limit, offset int) ([]*
 	return args.Get(0).([]*user.User), args.Error(1)
 }
 
-func (m *MockUserUseCase) DeleteUser(ctx context.Context, id string) error {
+func (m *MockUserUseCase) UpdateAvatar(ctx context.Context, id, actorID, filename string, body io.Reader) (*user.User, error) {
+	args := m.Called(ctx, id, actorID, filename, body)
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockUserUseCase) Restore(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockUserUseCase) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserUseCase) DeleteUser(ctx context.Context, id, actorID string) error {
+	args := m.Called(ctx, id, actorID)
+	return args.Error(0)
+}
+
 func TestUserHandler_Register(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -160,10 +189,11 @@ func TestUserHandler_UpdateProfile(t *testing.T) {
 	reqBody := user.User{
 		FullName: "Updated User",
 		Email:    "updated@example.com",
+		Version:  1,
 	}
 	body, _ := json.Marshal(reqBody)
 
-	mockUseCase.On("UpdateProfile", mock.Anything, mock.AnythingOfType("*user.User")).Return(nil)
+	mockUseCase.On("UpdateProfile", mock.Anything, mock.AnythingOfType("*user.User"), "").Return(nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PUT", "/api/v1/admin/users/user-123", bytes.NewBuffer(body))
@@ -205,10 +235,11 @@ func TestUserHandler_UpdateProfile_Error(t *testing.T) {
 	reqBody := user.User{
 		FullName: "Updated User",
 		Email:    "updated@example.com",
+		Version:  1,
 	}
 	body, _ := json.Marshal(reqBody)
 
-	mockUseCase.On("UpdateProfile", mock.Anything, mock.AnythingOfType("*user.User")).Return(assert.AnError)
+	mockUseCase.On("UpdateProfile", mock.Anything, mock.AnythingOfType("*user.User"), "").Return(assert.AnError)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PUT", "/api/v1/admin/users/user-123", bytes.NewBuffer(body))
@@ -296,7 +327,7 @@ func TestUserHandler_DeleteUser(t *testing.T) {
 	authMiddleware := func(c *gin.Context) { c.Next() }
 	handler.Register(router.Group("/api/v1"), authMiddleware)
 
-	mockUseCase.On("DeleteUser", mock.Anything, "user-123").Return(nil)
+	mockUseCase.On("DeleteUser", mock.Anything, "user-123", "").Return(nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/api/v1/admin/users/user-123", nil)
@@ -316,7 +347,7 @@ func TestUserHandler_DeleteUser_Error(t *testing.T) {
 	authMiddleware := func(c *gin.Context) { c.Next() }
 	handler.Register(router.Group("/api/v1"), authMiddleware)
 
-	mockUseCase.On("DeleteUser", mock.Anything, "user-123").Return(assert.AnError)
+	mockUseCase.On("DeleteUser", mock.Anything, "user-123", "").Return(assert.AnError)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/api/v1/admin/users/user-123", nil)
@@ -326,6 +357,29 @@ func TestUserHandler_DeleteUser_Error(t *testing.T) {
 	mockUseCase.AssertExpectations(t)
 }
 
+func TestUserHandler_DeleteUser_NotAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUseCase := new(MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "user-456")
+		c.Next()
+	}
+	handler.Register(router.Group("/api/v1"), authMiddleware)
+
+	mockUseCase.On("DeleteUser", mock.Anything, "user-123", "user-456").Return(userusecase.ErrAdminRequired)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/admin/users/user-123", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
 func TestUserHandler_Register_Error(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -412,3 +466,48 @@ func TestUserHandler_Login_InvalidJSON(t *testing.T) {
 
 	require.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestUserHandler_UpdateAvatar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUseCase := new(MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) { c.Next() }
+	handler.Register(router.Group("/api/v1"), authMiddleware)
+
+	mockUseCase.On("UpdateAvatar", mock.Anything, "user-123", "", "me.png", mock.Anything).
+		Return(&user.User{ID: "user-123", AvatarURL: "/api/v1/media/m1/sizes/avatar"}, nil)
+
+	body, contentType := multipartBody(t, "me.png", []byte("png-bytes"))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/users/user-123/avatar", body)
+	req.Header.Set("Content-Type", contentType)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/api/v1/media/m1/sizes/avatar")
+}
+
+func TestUserHandler_UpdateAvatar_NotImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUseCase := new(MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) { c.Next() }
+	handler.Register(router.Group("/api/v1"), authMiddleware)
+
+	mockUseCase.On("UpdateAvatar", mock.Anything, "user-123", "", "cv.pdf", mock.Anything).
+		Return((*user.User)(nil), mediausecase.ErrNotImage)
+
+	body, contentType := multipartBody(t, "cv.pdf", []byte("%PDF-1.4"))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/admin/users/user-123/avatar", body)
+	req.Header.Set("Content-Type", contentType)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
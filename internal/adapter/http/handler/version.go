@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Editable resources carry a version that every update increments. Responses send it as the ETag
+// so editors can return it in If-Match: an update naming a version that is no longer current fails
+// with 412 instead of silently overwriting someone else's changes, and an update naming no version
+// at all fails with 428.
+
+// setETag sets the ETag of a response carrying a resource at version.
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// ifMatch returns the version an update expects from its If-Match header. Without the header the
+// version sent in the body is expected; "*" knowingly overwrites whatever version is current. An
+// update naming no version is answered with 428, a header that names anything but a single version
+// ETag with 412, and ok is false.
+func ifMatch(c *gin.Context, bodyVersion int) (version int, ok bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	switch {
+	case header == "" && bodyVersion > 0:
+		return bodyVersion, true
+	case header == "":
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "updates must name the version they replace in If-Match or the version field"})
+		return 0, false
+	case header == "*":
+		return 0, true
+	}
+
+	// Intermediaries may weaken ETags, so weak ones are accepted as well.
+	tag := strings.TrimPrefix(header, "W/")
+	if len(tag) > 2 && strings.HasPrefix(tag, `"`) && strings.HasSuffix(tag, `"`) {
+		if version, err := strconv.Atoi(tag[1 : len(tag)-1]); err == nil && version > 0 {
+			return version, true
+		}
+	}
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": "If-Match must name a single version ETag"})
+	return 0, false
+}
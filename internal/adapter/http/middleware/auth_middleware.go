@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -13,40 +14,90 @@ const (
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "authorization_payload"
 	userIDKey               = "user_id"
+	userRoleKey             = "user_role"
 )
 
 // AuthMiddleware creates a gin middleware for authorization
 func AuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
-
-		if len(authorizationHeader) == 0 {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header is not provided"})
-			return
+		if authenticate(ctx, tokenMaker) {
+			ctx.Next()
 		}
+	}
+}
 
-		fields := strings.Fields(authorizationHeader)
-		if len(fields) < 2 {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+// OptionalAuthMiddleware authorizes requests that carry an authorization header and lets anonymous
+// ones through, so handlers can serve both and tell them apart by the presence of the user ID.
+func OptionalAuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if len(ctx.GetHeader(authorizationHeaderKey)) > 0 && !authenticate(ctx, tokenMaker) {
 			return
 		}
+		ctx.Next()
+	}
+}
 
-		authorizationType := strings.ToLower(fields[0])
-		if authorizationType != authorizationTypeBearer {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unsupported authorization type"})
-			return
-		}
+// RoleFinder looks up the role of a user for ReaderMiddleware.
+type RoleFinder interface {
+	Role(ctx context.Context, userID string) (string, error)
+}
 
-		accessToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(accessToken)
-		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			return
+// ReaderMiddleware authorizes readers like OptionalAuthMiddleware and also sets the role of signed-in
+// ones, so public handlers can enforce per-entry visibility. Tokens of users that no longer exist
+// are rejected.
+func ReaderMiddleware(tokenMaker token.Maker, roles RoleFinder) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if len(ctx.GetHeader(authorizationHeaderKey)) > 0 {
+			if !authenticate(ctx, tokenMaker) {
+				return
+			}
+			role, err := roles.Role(ctx.Request.Context(), ctx.GetString(userIDKey))
+			if err != nil {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": token.ErrInvalidToken.Error()})
+				return
+			}
+			ctx.Set(userRoleKey, role)
 		}
-
-		ctx.Set(authorizationPayloadKey, payload)
-		// Assuming payload.ID is the user ID (UUID)
-		ctx.Set(userIDKey, payload.ID.String())
 		ctx.Next()
 	}
 }
+
+// authenticate verifies the bearer token of the request and stores its payload and user ID in the
+// context. It aborts the request and reports false when the token is missing or invalid.
+func authenticate(ctx *gin.Context, tokenMaker token.Maker) bool {
+	authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+
+	if len(authorizationHeader) == 0 {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header is not provided"})
+		return false
+	}
+
+	fields := strings.Fields(authorizationHeader)
+	if len(fields) < 2 {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+		return false
+	}
+
+	authorizationType := strings.ToLower(fields[0])
+	if authorizationType != authorizationTypeBearer {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unsupported authorization type"})
+		return false
+	}
+
+	accessToken := fields[1]
+	payload, err := tokenMaker.VerifyToken(accessToken)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return false
+	}
+	// Scoped tokens such as preview links only open the resource they were issued for.
+	if payload.Scope != "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": token.ErrInvalidToken.Error()})
+		return false
+	}
+
+	ctx.Set(authorizationPayloadKey, payload)
+	// Tokens are issued with the user ID as their subject; payload.ID identifies the token itself.
+	ctx.Set(userIDKey, payload.Username)
+	return true
+}
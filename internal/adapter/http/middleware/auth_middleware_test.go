@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -65,6 +67,17 @@ func TestAuthMiddleware(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "ScopedToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				scoped, _, err := tokenMaker.CreateScopedToken(uuid.New().String(), "preview:post-1", time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, scoped))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
@@ -118,3 +131,83 @@ func addAuthorization(
 	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, token)
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
 }
+
+func TestOptionalAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenMaker, err := token.NewPasetoMaker(token.RandomString(32))
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/auth", OptionalAuthMiddleware(tokenMaker), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"user_id": ctx.GetString(userIDKey)})
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/auth", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"user_id":""}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/auth", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user-1", time.Minute)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"user_id":"user-1"}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/auth", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user-1", -time.Minute)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+type roleFinderFunc func(ctx context.Context, userID string) (string, error)
+
+func (f roleFinderFunc) Role(ctx context.Context, userID string) (string, error) {
+	return f(ctx, userID)
+}
+
+func TestReaderMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenMaker, err := token.NewPasetoMaker(token.RandomString(32))
+	require.NoError(t, err)
+	roles := roleFinderFunc(func(_ context.Context, userID string) (string, error) {
+		if userID == "user-1" {
+			return "editor", nil
+		}
+		return "", errors.New("user not found")
+	})
+
+	router := gin.New()
+	router.GET("/auth", ReaderMiddleware(tokenMaker, roles), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"user_id": ctx.GetString(userIDKey), "user_role": ctx.GetString(userRoleKey)})
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/auth", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"user_id":"","user_role":""}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/auth", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user-1", time.Minute)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"user_id":"user-1","user_role":"editor"}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/auth", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user-2", time.Minute)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
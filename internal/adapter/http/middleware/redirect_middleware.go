@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Redirector decides whether a request path should be redirected.
+type Redirector interface {
+	Redirect(path string) (target string, status int, ok bool)
+}
+
+// RedirectMiddleware answers GET and HEAD requests that match a redirect rule before routing. The API
+// and the health check are never redirected, whatever the rules match.
+func RedirectMiddleware(redirector Redirector) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.Method != http.MethodGet && ctx.Request.Method != http.MethodHead {
+			ctx.Next()
+			return
+		}
+		if path := ctx.Request.URL.Path; strings.HasPrefix(path, "/api/") || path == "/health" {
+			ctx.Next()
+			return
+		}
+
+		target, status, ok := redirector.Redirect(ctx.Request.URL.Path)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		if status == http.StatusGone {
+			ctx.AbortWithStatusJSON(http.StatusGone, gin.H{"error": "this resource is no longer available"})
+			return
+		}
+
+		// Carry the original query string over unless the rule defines its own.
+		if query := ctx.Request.URL.RawQuery; query != "" && !strings.Contains(target, "?") {
+			target += "?" + query
+		}
+		ctx.Redirect(status, target)
+		ctx.Abort()
+	}
+}
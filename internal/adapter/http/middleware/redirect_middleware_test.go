@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRedirector map[string]struct {
+	target string
+	status int
+}
+
+func (s stubRedirector) Redirect(path string) (string, int, bool) {
+	r, ok := s[path]
+	return r.target, r.status, ok
+}
+
+func TestRedirectMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	redirector := stubRedirector{
+		"/old":     {target: "/new", status: http.StatusMovedPermanently},
+		"/promo":   {target: "/sale?utm=promo", status: http.StatusFound},
+		"/retired": {status: http.StatusGone},
+		// Like a pattern rule matching every path.
+		"/api/v1/posts": {target: "/new", status: http.StatusFound},
+		"/health":       {status: http.StatusGone},
+	}
+
+	router := gin.New()
+	router.Use(RedirectMiddleware(redirector))
+	router.Any("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	testCases := []struct {
+		name     string
+		method   string
+		url      string
+		status   int
+		location string
+	}{
+		{name: "Permanent", method: http.MethodGet, url: "/old?page=2", status: http.StatusMovedPermanently, location: "/new?page=2"},
+		{name: "TargetQueryWins", method: http.MethodGet, url: "/promo?x=1", status: http.StatusFound, location: "/sale?utm=promo"},
+		{name: "Gone", method: http.MethodGet, url: "/retired", status: http.StatusGone},
+		{name: "NoMatch", method: http.MethodGet, url: "/other", status: http.StatusOK},
+		{name: "IgnoresWrites", method: http.MethodPost, url: "/old", status: http.StatusOK},
+		{name: "SkipsAPI", method: http.MethodGet, url: "/api/v1/posts", status: http.StatusOK},
+		{name: "SkipsHealth", method: http.MethodGet, url: "/health", status: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(tc.method, tc.url, nil))
+
+			require.Equal(t, tc.status, recorder.Code)
+			require.Equal(t, tc.location, recorder.Header().Get("Location"))
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/mashurimansur/goCMS/internal/adapter/http/handler"
@@ -10,10 +12,34 @@ import (
 
 // Options configure the HTTP router and its dependencies.
 type Options struct {
-	Mode          string
-	PersonHandler *handler.PersonHandler
-	UserHandler   *handler.UserHandler
-	TokenMaker    token.Maker
+	Mode               string
+	PersonHandler      *handler.PersonHandler
+	UserHandler        *handler.UserHandler
+	ContentHandler     *handler.ContentHandler
+	CategoryHandler    *handler.CategoryHandler
+	TagHandler         *handler.TagHandler
+	RedirectHandler    *handler.RedirectHandler
+	MediaHandler       *handler.MediaHandler
+	ContentTypeHandler *handler.ContentTypeHandler
+	CommentHandler     *handler.CommentHandler
+	MenuHandler        *handler.MenuHandler
+	SEOHandler         *handler.SEOHandler
+	FeedHandler        *handler.FeedHandler
+	PreviewHandler     *handler.PreviewHandler
+	LockHandler        *handler.LockHandler
+	TrashHandler       *handler.TrashHandler
+	BulkHandler        *handler.BulkHandler
+	ThemeHandler       *handler.ThemeHandler
+	SnippetHandler     *handler.SnippetHandler
+	FormHandler        *handler.FormHandler
+	Redirects          middleware.Redirector
+	TokenMaker         token.Maker
+	// Roles looks up the roles of signed-in readers for members-only content. Without it readers
+	// are only told apart by whether they signed in.
+	Roles middleware.RoleFinder
+	// TrustedProxies lists the addresses or CIDR ranges of the reverse proxies whose X-Forwarded-For
+	// and X-Real-IP headers name the client. Without any, clients are known by their connection.
+	TrustedProxies []string
 }
 
 // NewGinEngine wires middleware stack and registers feature routes.
@@ -23,18 +49,84 @@ func NewGinEngine(opts Options) *gin.Engine {
 	}
 
 	engine := gin.New()
+	// Rate limits key on the client address, so it must not be whatever a request claims.
+	if err := engine.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		log.Printf("invalid trusted proxies, trusting none: %v", err)
+		_ = engine.SetTrustedProxies(nil)
+	}
 	engine.Use(gin.Logger(), gin.Recovery())
+	// Redirect rules run before routing so legacy URLs never reach feature handlers.
+	if opts.Redirects != nil {
+		engine.Use(middleware.RedirectMiddleware(opts.Redirects))
+	}
 
 	// Public routes
+	api := engine.Group("/api/v1")
+	authMiddleware := middleware.AuthMiddleware(opts.TokenMaker)
+	readerMiddleware := middleware.OptionalAuthMiddleware(opts.TokenMaker)
+	if opts.Roles != nil {
+		readerMiddleware = middleware.ReaderMiddleware(opts.TokenMaker, opts.Roles)
+	}
 	if opts.UserHandler != nil {
 		// Register public auth routes and protected user routes
-		authMiddleware := middleware.AuthMiddleware(opts.TokenMaker)
-		opts.UserHandler.Register(engine.Group("/api/v1"), authMiddleware)
+		opts.UserHandler.Register(api, authMiddleware)
+	}
+	if opts.ContentHandler != nil {
+		opts.ContentHandler.Register(api, authMiddleware, readerMiddleware)
+	}
+	if opts.CategoryHandler != nil {
+		opts.CategoryHandler.Register(api, authMiddleware, readerMiddleware)
+	}
+	if opts.TagHandler != nil {
+		opts.TagHandler.Register(api, authMiddleware, readerMiddleware)
+	}
+	if opts.RedirectHandler != nil {
+		opts.RedirectHandler.Register(api, authMiddleware)
+	}
+	if opts.MediaHandler != nil {
+		opts.MediaHandler.Register(api, authMiddleware)
+	}
+	if opts.ContentTypeHandler != nil {
+		opts.ContentTypeHandler.Register(api, authMiddleware, readerMiddleware)
+	}
+	if opts.CommentHandler != nil {
+		opts.CommentHandler.Register(api, authMiddleware, readerMiddleware)
+	}
+	if opts.MenuHandler != nil {
+		opts.MenuHandler.Register(api, authMiddleware, readerMiddleware)
+	}
+	if opts.SEOHandler != nil {
+		opts.SEOHandler.Register(api, authMiddleware, readerMiddleware)
+		opts.SEOHandler.RegisterSite(engine)
+	}
+	if opts.PreviewHandler != nil {
+		opts.PreviewHandler.Register(api, authMiddleware)
+	}
+	if opts.LockHandler != nil {
+		opts.LockHandler.Register(api, authMiddleware)
+	}
+	if opts.TrashHandler != nil {
+		opts.TrashHandler.Register(api, authMiddleware)
+	}
+	if opts.BulkHandler != nil {
+		opts.BulkHandler.Register(api, authMiddleware)
+	}
+	if opts.SnippetHandler != nil {
+		opts.SnippetHandler.Register(api, authMiddleware)
+	}
+	if opts.FormHandler != nil {
+		opts.FormHandler.Register(api, authMiddleware)
+	}
+	if opts.FeedHandler != nil {
+		opts.FeedHandler.Register(engine)
+	}
+	if opts.ThemeHandler != nil {
+		opts.ThemeHandler.Register(api, authMiddleware)
+		opts.ThemeHandler.RegisterSite(engine, readerMiddleware)
 	}
 
 	admin := engine.Group("/api/v1/admin")
 	if opts.TokenMaker != nil {
-		authMiddleware := middleware.AuthMiddleware(opts.TokenMaker)
 		admin.Use(authMiddleware)
 	}
 	if opts.PersonHandler != nil {
@@ -46,5 +138,13 @@ func NewGinEngine(opts Options) *gin.Engine {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Any other path is treated as a public content permalink.
+	switch {
+	case opts.ThemeHandler != nil:
+		engine.NoRoute(readerMiddleware, opts.ThemeHandler.ResolvePermalink)
+	case opts.ContentHandler != nil:
+		engine.NoRoute(readerMiddleware, opts.ContentHandler.ResolvePermalink)
+	}
+
 	return engine
 }
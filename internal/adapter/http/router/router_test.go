@@ -56,6 +56,28 @@ func TestNewGinEngine_HealthAndMissingHandler(t *testing.T) {
 	}
 }
 
+func TestNewGinEngine_TrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	clientIP := func(engine *gin.Engine) string {
+		var ip string
+		engine.GET("/ip", func(c *gin.Context) { ip = c.ClientIP() })
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = "10.0.0.5:52000"
+		req.Header.Set("X-Forwarded-For", "198.51.100.7")
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+		return ip
+	}
+
+	// Without trusted proxies the forwarding header is ignored.
+	if ip := clientIP(NewGinEngine(Options{})); ip != "10.0.0.5" {
+		t.Fatalf("expected the connection address, got %s", ip)
+	}
+	if ip := clientIP(NewGinEngine(Options{TrustedProxies: []string{"10.0.0.0/8"}})); ip != "198.51.100.7" {
+		t.Fatalf("expected the forwarded address, got %s", ip)
+	}
+}
+
 type stubPersonUseCase struct {
 	person domain.Person
 	err    error
@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/form"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body, as "sha256=<hex>", when a secret is
+// configured.
+const SignatureHeader = "X-GoCMS-Signature"
+
+// EventHeader names the event a request reports.
+const EventHeader = "X-GoCMS-Event"
+
+// EventSubmission is reported for every stored form submission.
+const EventSubmission = "form.submission"
+
+// defaultTimeout bounds a notification when no client is given.
+const defaultTimeout = 10 * time.Second
+
+// Notifier implements form.Notifier by posting submissions as JSON to the NotifyURL of their form.
+type Notifier struct {
+	client *http.Client
+	secret []byte
+}
+
+// NewNotifier returns a webhook notifier. A nil client uses one with a short timeout; an empty
+// secret leaves requests unsigned.
+func NewNotifier(client *http.Client, secret string) *Notifier {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Notifier{client: client, secret: []byte(secret)}
+}
+
+type formSummary struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+type payload struct {
+	Event      string           `json:"event"`
+	Form       formSummary      `json:"form"`
+	Submission *form.Submission `json:"submission"`
+}
+
+// Notify posts s to f.NotifyURL and fails unless the endpoint answers with a 2xx status.
+func (n *Notifier) Notify(ctx context.Context, f *form.Form, s *form.Submission) error {
+	body, err := json.Marshal(payload{
+		Event:      EventSubmission,
+		Form:       formSummary{ID: f.ID, Name: f.Name, Title: f.Title},
+		Submission: s,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.NotifyURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, EventSubmission)
+	if len(n.secret) > 0 {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body, which receivers compare with the signature header.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mashurimansur/goCMS/internal/domain/form"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifier_Notify(t *testing.T) {
+	var received payload
+	var signature, event string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		event = r.Header.Get(EventHeader)
+		assert.Equal(t, "sha256="+Sign([]byte("secret"), body), signature)
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	f := &form.Form{ID: "f-1", Name: "contact", Title: "Contact", NotifyURL: server.URL}
+	s := &form.Submission{ID: "s-1", FormID: "f-1", Data: map[string]string{"email": "ana@example.com"}}
+	require.NoError(t, NewNotifier(server.Client(), "secret").Notify(context.Background(), f, s))
+
+	assert.Equal(t, EventSubmission, event)
+	assert.Equal(t, "contact", received.Form.Name)
+	assert.Equal(t, "ana@example.com", received.Submission.Data["email"])
+}
+
+func TestNotifier_Notify_Unsigned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(SignatureHeader))
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	f := &form.Form{Name: "contact", NotifyURL: server.URL}
+	err := NewNotifier(server.Client(), "").Notify(context.Background(), f, &form.Submission{})
+	assert.ErrorContains(t, err, "500")
+}
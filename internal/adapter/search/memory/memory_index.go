@@ -0,0 +1,245 @@
+// Package memory implements content.Search with an inverted index held in process memory. It suits
+// single-instance deployments and tests that run without MySQL; the index is rebuilt on start-up.
+package memory
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/utils/fulltext"
+)
+
+// document is an indexed entry with the term frequencies of its title, body and excerpt.
+type document struct {
+	content      *content.Content
+	categoryIDs  []string
+	body         string
+	title        map[string]int
+	bodyTerms    map[string]int
+	excerptTerms map[string]int
+}
+
+// searchable returns the text besides the title that v may match against, with its term
+// frequencies: the body when v is admitted to the entry and the excerpt otherwise. A nil viewer is
+// admitted to every entry.
+func (d *document) searchable(v *content.Viewer) (string, map[string]int) {
+	if v == nil || d.content.Admits(*v) {
+		return d.body, d.bodyTerms
+	}
+	return d.content.Excerpt, d.excerptTerms
+}
+
+func (d *document) terms() []map[string]int {
+	return []map[string]int{d.title, d.bodyTerms, d.excerptTerms}
+}
+
+// Index implements content.Search with an inverted index from terms to the entries containing them.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]*document
+	postings map[string]map[string]struct{}
+}
+
+// NewIndex returns an empty in-memory index.
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string]struct{}),
+	}
+}
+
+// Index adds or replaces an entry.
+func (idx *Index) Index(ctx context.Context, c *content.Content, categoryIDs []string) error {
+	stored := *c
+	body := fulltext.PlainText(c.BodyHTML)
+	if body == "" {
+		body = c.Body
+	}
+	doc := &document{
+		content:      &stored,
+		categoryIDs:  slices.Clone(categoryIDs),
+		body:         body,
+		title:        frequencies(c.Title),
+		bodyTerms:    frequencies(body),
+		excerptTerms: frequencies(c.Excerpt),
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if previous, ok := idx.docs[c.ID]; ok && categoryIDs == nil {
+		doc.categoryIDs = previous.categoryIDs
+	}
+	idx.remove(c.ID)
+	idx.docs[c.ID] = doc
+	for _, terms := range doc.terms() {
+		for term := range terms {
+			if idx.postings[term] == nil {
+				idx.postings[term] = make(map[string]struct{})
+			}
+			idx.postings[term][c.ID] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// Remove drops an entry from the index. Removing an unknown entry is not an error.
+func (idx *Index) Remove(ctx context.Context, id string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+	return nil
+}
+
+// Search ranks the entries containing any query term by TF-IDF, weighting title matches by
+// fulltext.TitleBoost. Entries q.Viewer is not admitted to match on their title and excerpt only,
+// so their body takes no part in matching, scoring or snippets.
+func (idx *Index) Search(ctx context.Context, q content.SearchQuery) (*content.SearchResult, error) {
+	terms := unique(fulltext.Terms(q.Text))
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	scores := make(map[string]float64)
+	for _, term := range terms {
+		weights := make(map[string]float64)
+		for id := range idx.postings[term] {
+			doc := idx.docs[id]
+			_, text := doc.searchable(q.Viewer)
+			if weight := fulltext.TitleBoost*math.Sqrt(float64(doc.title[term])) + math.Sqrt(float64(text[term])); weight > 0 {
+				weights[id] = weight
+			}
+		}
+		if len(weights) == 0 {
+			continue
+		}
+		idf := math.Log(1 + float64(len(idx.docs))/float64(len(weights)))
+		for id, weight := range weights {
+			scores[id] += idf * weight
+		}
+	}
+
+	matches := make([]*document, 0, len(scores))
+	for id := range scores {
+		if doc := idx.docs[id]; matchesFilters(doc, q) {
+			matches = append(matches, doc)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if scores[a.content.ID] != scores[b.content.ID] {
+			return scores[a.content.ID] > scores[b.content.ID]
+		}
+		if !a.content.PublishedAt.Equal(b.content.PublishedAt) {
+			return a.content.PublishedAt.After(b.content.PublishedAt)
+		}
+		return a.content.ID < b.content.ID
+	})
+
+	result := &content.SearchResult{
+		Total:  len(matches),
+		Hits:   []*content.SearchHit{},
+		Facets: facets(matches),
+	}
+	end := min(q.Offset+q.Limit, len(matches))
+	for i := q.Offset; i < end; i++ {
+		doc := matches[i]
+		c := *doc.content
+		text, _ := doc.searchable(q.Viewer)
+		result.Hits = append(result.Hits, &content.SearchHit{
+			Content: &c,
+			Score:   scores[c.ID],
+			Title:   fulltext.Highlight(c.Title, terms),
+			Snippet: fulltext.Snippet(text, terms, fulltext.SnippetSize),
+		})
+	}
+	return result, nil
+}
+
+// remove unlinks an entry from the postings. Callers hold the write lock.
+func (idx *Index) remove(id string) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for _, terms := range doc.terms() {
+		for term := range terms {
+			delete(idx.postings[term], id)
+			if len(idx.postings[term]) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+	delete(idx.docs, id)
+}
+
+func matchesFilters(doc *document, q content.SearchQuery) bool {
+	c := doc.content
+	switch {
+	case q.Type != "" && c.Type != q.Type,
+		q.Locale != "" && c.Locale != q.Locale,
+		q.Status != "" && c.Status != q.Status,
+		q.AuthorID != "" && c.AuthorID != q.AuthorID,
+		q.CategoryID != "" && !slices.Contains(doc.categoryIDs, q.CategoryID),
+		!q.PublishedBefore.IsZero() && c.PublishedAt.After(q.PublishedBefore),
+		q.Viewer != nil && !c.Listed(*q.Viewer):
+		return false
+	}
+	return true
+}
+
+func facets(matches []*document) map[string][]content.FacetValue {
+	counts := map[string]map[string]int{
+		content.FacetType:     {},
+		content.FacetCategory: {},
+		content.FacetAuthor:   {},
+	}
+	for _, doc := range matches {
+		counts[content.FacetType][doc.content.Type]++
+		for _, id := range doc.categoryIDs {
+			counts[content.FacetCategory][id]++
+		}
+		if doc.content.AuthorID != "" {
+			counts[content.FacetAuthor][doc.content.AuthorID]++
+		}
+	}
+
+	out := make(map[string][]content.FacetValue, len(counts))
+	for name, values := range counts {
+		list := make([]content.FacetValue, 0, len(values))
+		for value, count := range values {
+			list = append(list, content.FacetValue{Value: value, Count: count})
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Count != list[j].Count {
+				return list[i].Count > list[j].Count
+			}
+			return list[i].Value < list[j].Value
+		})
+		out[name] = list
+	}
+	return out
+}
+
+func frequencies(text string) map[string]int {
+	freq := make(map[string]int)
+	for _, term := range fulltext.Terms(text) {
+		freq[term]++
+	}
+	return freq
+}
+
+func unique(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	out := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			out = append(out, term)
+		}
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex_TitleBoost(t *testing.T) {
+	idx := NewIndex()
+	ctx := context.Background()
+
+	require.NoError(t, idx.Index(ctx, &content.Content{ID: "body", Type: "post", Title: "Weekly notes", BodyHTML: "<p>Tips on <em>caching</em> and caching again.</p>"}, nil))
+	require.NoError(t, idx.Index(ctx, &content.Content{ID: "title", Type: "page", Title: "Caching", Body: "Short."}, nil))
+	require.NoError(t, idx.Index(ctx, &content.Content{ID: "other", Type: "post", Title: "Unrelated", Body: "Nothing here."}, nil))
+
+	result, err := idx.Search(ctx, content.SearchQuery{Text: "caching", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Total)
+	assert.Equal(t, "title", result.Hits[0].Content.ID)
+	assert.Equal(t, "body", result.Hits[1].Content.ID)
+	assert.Greater(t, result.Hits[0].Score, result.Hits[1].Score)
+	assert.Equal(t, "Tips on <mark>caching</mark> and <mark>caching</mark> again.", result.Hits[1].Snippet)
+	assert.Equal(t, []content.FacetValue{{Value: "page", Count: 1}, {Value: "post", Count: 1}}, result.Facets[content.FacetType])
+}
+
+func TestIndex_FiltersAndPagination(t *testing.T) {
+	idx := NewIndex()
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, idx.Index(ctx, &content.Content{ID: "a", Title: "Go tips", AuthorID: "ana", Status: content.StatusPublished, PublishedAt: now.Add(-2 * time.Hour)}, []string{"dev"}))
+	require.NoError(t, idx.Index(ctx, &content.Content{ID: "b", Title: "Go news", AuthorID: "budi", Status: content.StatusPublished, PublishedAt: now.Add(-time.Hour)}, []string{"news"}))
+	require.NoError(t, idx.Index(ctx, &content.Content{ID: "c", Title: "Go future", AuthorID: "ana", Status: content.StatusPublished, PublishedAt: now.Add(time.Hour)}, []string{"dev"}))
+
+	result, err := idx.Search(ctx, content.SearchQuery{Text: "go", PublishedBefore: now, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, "b", result.Hits[0].Content.ID, "equal scores fall back to newest first")
+
+	result, err = idx.Search(ctx, content.SearchQuery{Text: "go", PublishedBefore: now, Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, "a", result.Hits[0].Content.ID)
+
+	result, err = idx.Search(ctx, content.SearchQuery{Text: "go", AuthorID: "ana", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, []content.FacetValue{{Value: "dev", Count: 2}}, result.Facets[content.FacetCategory])
+	assert.Equal(t, []content.FacetValue{{Value: "ana", Count: 2}}, result.Facets[content.FacetAuthor])
+}
+
+func TestIndex_RestrictedBodies(t *testing.T) {
+	idx := NewIndex()
+	ctx := context.Background()
+
+	members := &content.Content{
+		ID: "members", Type: "post", Title: "Members update", Excerpt: "News for subscribers.", Body: "The secret roadmap.",
+		Visibility: content.VisibilityAuthenticated, Teaser: true,
+	}
+	require.NoError(t, idx.Index(ctx, members, []string{"news"}))
+	require.NoError(t, idx.Index(ctx, &content.Content{ID: "public", Type: "post", Title: "Public roadmap", Body: "Open plans."}, nil))
+
+	anonymous := &content.Viewer{}
+	result, err := idx.Search(ctx, content.SearchQuery{Text: "roadmap", Viewer: anonymous, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+	assert.Equal(t, "public", result.Hits[0].Content.ID)
+	assert.Empty(t, result.Facets[content.FacetCategory])
+
+	result, err = idx.Search(ctx, content.SearchQuery{Text: "subscribers", Viewer: anonymous, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Total)
+	assert.Equal(t, "News for <mark>subscribers</mark>.", result.Hits[0].Snippet)
+
+	result, err = idx.Search(ctx, content.SearchQuery{Text: "roadmap", Viewer: &content.Viewer{UserID: "user-1"}, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+}
+
+func TestIndex_ReplaceAndRemove(t *testing.T) {
+	idx := NewIndex()
+	ctx := context.Background()
+
+	require.NoError(t, idx.Index(ctx, &content.Content{ID: "a", Title: "Old title"}, []string{"dev"}))
+	require.NoError(t, idx.Index(ctx, &content.Content{ID: "a", Title: "New title"}, nil))
+
+	result, err := idx.Search(ctx, content.SearchQuery{Text: "old", Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, result.Total)
+
+	result, err = idx.Search(ctx, content.SearchQuery{Text: "new", CategoryID: "dev", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Total, "a nil category list keeps the indexed categories")
+
+	require.NoError(t, idx.Remove(ctx, "a"))
+	require.NoError(t, idx.Remove(ctx, "missing"))
+	result, err = idx.Search(ctx, content.SearchQuery{Text: "new", Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, result.Total)
+	assert.Empty(t, idx.postings)
+}
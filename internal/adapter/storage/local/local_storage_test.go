@@ -0,0 +1,64 @@
+package local
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_SaveOpenDelete(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	written, err := s.Save(ctx, "2025/12/file.txt", strings.NewReader("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), written)
+
+	f, err := s.Open(ctx, "2025/12/file.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, s.Delete(ctx, "2025/12/file.txt"))
+	require.NoError(t, s.Delete(ctx, "2025/12/file.txt"))
+
+	_, err = s.Open(ctx, "2025/12/file.txt")
+	assert.ErrorIs(t, err, media.ErrNotFound)
+}
+
+func TestStorage_RejectsTraversal(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"../escape.txt", "/etc/passwd", "a/../../b"} {
+		_, err := s.Save(context.Background(), key, strings.NewReader("x"))
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+func TestStorage_DeletePrefix(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, key := range []string{"derivatives/m1/a.jpg", "derivatives/m1/b.jpg", "derivatives/m2/a.jpg"} {
+		_, err := s.Save(ctx, key, strings.NewReader("x"))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, s.DeletePrefix(ctx, "derivatives/m1"))
+
+	_, err = s.Open(ctx, "derivatives/m1/a.jpg")
+	assert.ErrorIs(t, err, media.ErrNotFound)
+	f, err := s.Open(ctx, "derivatives/m2/a.jpg")
+	require.NoError(t, err)
+	f.Close()
+}
@@ -0,0 +1,247 @@
+// Package gotemplate renders pages with html/template themes. A theme is a file tree holding
+// layout.html, the page skeleton; one file per page template defining the "main" block of the
+// layout; optional partials/*.html shared by every page; and optional i18n/<locale>.json files
+// mapping message keys to translations.
+//
+// Page templates are looked up along a hierarchy, the first one the theme has being used:
+//
+//	single:   single-<type>.html, single.html
+//	home:     home.html, list.html
+//	taxonomy: taxonomy-<category|tag>.html, taxonomy.html, list.html
+//	404:      404.html
+//
+// Templates can call safeHTML, date, url, media, categoryURL, tagURL and t (see funcs).
+package gotemplate
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+)
+
+const layoutFile = "layout.html"
+
+//go:embed default
+var defaultTheme embed.FS
+
+// Default returns the built-in theme.
+func Default() fs.FS {
+	fsys, _ := fs.Sub(defaultTheme, "default")
+	return fsys
+}
+
+// Options configures the helper functions of templates.
+type Options struct {
+	// SiteURL is the absolute URL of the public site, which url prefixes paths with.
+	SiteURL string
+	// MediaBaseURL is the URL prefix media are served under, which media prefixes media IDs with.
+	MediaBaseURL string
+	// CategoryURLPattern and TagURLPattern are the paths of taxonomy pages, with a {slug}
+	// placeholder. They default to "/category/{slug}" and "/tag/{slug}".
+	CategoryURLPattern string
+	TagURLPattern      string
+}
+
+// Theme holds the parsed templates of a theme.
+type Theme struct {
+	pages       map[string]*template.Template
+	messages    map[string]map[string]string
+	fingerprint string
+}
+
+var _ theme.Renderer = (*Theme)(nil)
+
+// New parses the theme stored in fsys.
+func New(fsys fs.FS, opts Options) (*Theme, error) {
+	t := &Theme{pages: make(map[string]*template.Template), messages: make(map[string]map[string]string)}
+	if err := t.loadMessages(fsys); err != nil {
+		return nil, err
+	}
+
+	base, err := template.New(layoutFile).Funcs(t.funcs(opts)).ParseFS(fsys, layoutFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse theme layout: %w", err)
+	}
+	partials, err := fs.Glob(fsys, "partials/*.html")
+	if err != nil {
+		return nil, err
+	}
+	if len(partials) > 0 {
+		if base, err = base.ParseFS(fsys, partials...); err != nil {
+			return nil, fmt.Errorf("cannot parse theme partials: %w", err)
+		}
+	}
+
+	files, err := fs.Glob(fsys, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if file == layoutFile {
+			continue
+		}
+		page, err := template.Must(base.Clone()).ParseFS(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", file, err)
+		}
+		t.pages[strings.TrimSuffix(file, ".html")] = page
+	}
+	for _, required := range []string{theme.TemplateSingle, theme.TemplateList} {
+		if t.pages[required] == nil {
+			return nil, fmt.Errorf("theme has no %s.html", required)
+		}
+	}
+
+	if t.fingerprint, err = fingerprint(fsys); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Theme) Render(w io.Writer, name string, page *theme.Page) error {
+	for _, candidate := range candidates(name, page) {
+		if tmpl, ok := t.pages[candidate]; ok {
+			return tmpl.ExecuteTemplate(w, layoutFile, page)
+		}
+	}
+	return fmt.Errorf("%w: %q", theme.ErrTemplateNotFound, name)
+}
+
+func (t *Theme) Fingerprint() string {
+	return t.fingerprint
+}
+
+// candidates lists the page templates that can render the named one, most specific first.
+func candidates(name string, page *theme.Page) []string {
+	switch name {
+	case theme.TemplateSingle:
+		if page.Entry != nil {
+			return []string{"single-" + page.Entry.Type, theme.TemplateSingle}
+		}
+	case theme.TemplateHome:
+		return []string{theme.TemplateHome, theme.TemplateList}
+	case theme.TemplateTaxonomy:
+		if page.Term != nil {
+			return []string{"taxonomy-" + page.Term.Kind, theme.TemplateTaxonomy, theme.TemplateList}
+		}
+		return []string{theme.TemplateTaxonomy, theme.TemplateList}
+	}
+	return []string{name}
+}
+
+// loadMessages reads the translations of the theme, keyed by locale.
+func (t *Theme) loadMessages(fsys fs.FS) error {
+	files, err := fs.Glob(fsys, "i18n/*.json")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("cannot parse %s: %w", file, err)
+		}
+		t.messages[strings.TrimSuffix(path.Base(file), ".json")] = messages
+	}
+	return nil
+}
+
+// translate looks key up in locale, then in its base language, e.g. "pt" for "pt-BR". Missing
+// messages render as their key.
+func (t *Theme) translate(locale, key string, args ...interface{}) string {
+	message := key
+	for _, loc := range []string{locale, strings.SplitN(locale, "-", 2)[0]} {
+		if m, ok := t.messages[loc][key]; ok {
+			message = m
+			break
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+func (t *Theme) funcs(opts Options) template.FuncMap {
+	siteURL := strings.TrimSuffix(opts.SiteURL, "/")
+	mediaBaseURL := strings.TrimSuffix(opts.MediaBaseURL, "/")
+	categoryURL := termURL(opts.CategoryURLPattern, "/category/{slug}")
+	tagURL := termURL(opts.TagURLPattern, "/tag/{slug}")
+	return template.FuncMap{
+		// safeHTML marks HTML as trusted, such as the body of an entry, which is sanitized when saved.
+		"safeHTML": func(s string) template.HTML {
+			return template.HTML(s)
+		},
+		// date formats t with a Go time layout, leaving zero times blank.
+		"date": func(layout string, t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format(layout)
+		},
+		// url turns a site path, such as a permalink, into an absolute URL.
+		"url": func(p string) string {
+			if strings.HasPrefix(p, "/") && !strings.HasPrefix(p, "//") {
+				return siteURL + p
+			}
+			return p
+		},
+		// media returns the URL of a media item, or of one of its named sizes.
+		"media": func(id string, size ...string) string {
+			u := mediaBaseURL + "/" + url.PathEscape(id)
+			if len(size) > 0 && size[0] != "" {
+				u += "/sizes/" + url.PathEscape(size[0])
+			}
+			return u
+		},
+		// categoryURL and tagURL return the path of the page of a category or tag, by slug.
+		"categoryURL": categoryURL,
+		"tagURL":      tagURL,
+		// t translates a message key into a locale, formatting the message with args.
+		"t": t.translate,
+	}
+}
+
+func termURL(pattern, fallback string) func(slug string) string {
+	if pattern == "" {
+		pattern = fallback
+	}
+	return func(slug string) string {
+		return strings.Replace(pattern, "{slug}", url.PathEscape(slug), 1)
+	}
+}
+
+// fingerprint hashes the names and contents of every file of the theme.
+func fingerprint(fsys fs.FS) (string, error) {
+	h := sha256.New()
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", path, len(data))
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot read theme: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
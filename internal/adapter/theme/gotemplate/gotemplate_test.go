@@ -0,0 +1,143 @@
+package gotemplate
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOptions = Options{SiteURL: "https://example.com/", MediaBaseURL: "/api/v1/media", TagURLPattern: "/topics/{slug}"}
+
+func TestDefaultTheme(t *testing.T) {
+	th, err := New(Default(), testOptions)
+	require.NoError(t, err)
+
+	entry := &content.Content{
+		Title:       "Hello <World>",
+		Locale:      "id",
+		BodyHTML:    "<p>Body</p>",
+		Permalink:   "/hello",
+		PublishedAt: time.Date(2025, 4, 1, 8, 0, 0, 0, time.UTC),
+	}
+	site := theme.Site{Title: "Blog", URL: "https://example.com"}
+
+	var single bytes.Buffer
+	require.NoError(t, th.Render(&single, theme.TemplateSingle, &theme.Page{
+		Site: site, Title: entry.Title, Path: "/hello", Locale: "id", Entry: entry,
+		Categories: []*category.Category{{Name: "News", Slug: "news"}},
+		Tags:       []*tag.Tag{{Name: "Go", Slug: "go"}},
+	}))
+	assert.Contains(t, single.String(), `<html lang="id">`)
+	assert.Contains(t, single.String(), "<title>Hello &lt;World&gt; · Blog</title>")
+	assert.Contains(t, single.String(), `<link rel="canonical" href="https://example.com/hello">`)
+	assert.Contains(t, single.String(), "<p>Body</p>")
+	assert.Contains(t, single.String(), `<time datetime="2025-04-01">1 April 2025</time>`)
+	assert.Contains(t, single.String(), `<a href="/category/news">News</a>`)
+	assert.Contains(t, single.String(), `<a href="/topics/go">#Go</a>`)
+
+	locked := *entry
+	locked.Excerpt, locked.BodyHTML, locked.Locked = "Intro", "", true
+	single.Reset()
+	require.NoError(t, th.Render(&single, theme.TemplateSingle, &theme.Page{Site: site, Locale: "en", Entry: &locked}))
+	assert.Contains(t, single.String(), "<p>Intro</p>")
+	assert.Contains(t, single.String(), "This entry is for members only.")
+
+	var home bytes.Buffer
+	require.NoError(t, th.Render(&home, theme.TemplateHome, &theme.Page{Site: site, Locale: "en", Entries: []*content.Content{entry}, NextPage: 2}))
+	assert.Contains(t, home.String(), `<a href="/hello">Hello &lt;World&gt;</a>`)
+	assert.Contains(t, home.String(), "<title>Blog</title>")
+	assert.Contains(t, home.String(), `<a href="?page=2" rel="next">Older →</a>`)
+
+	var taxonomy bytes.Buffer
+	require.NoError(t, th.Render(&taxonomy, theme.TemplateTaxonomy, &theme.Page{Site: site, Locale: "id-ID", Term: &theme.Term{Kind: theme.TermTag, Name: "Go"}}))
+	assert.Contains(t, taxonomy.String(), "<h1>Go</h1>")
+	assert.Contains(t, taxonomy.String(), "Belum ada yang diterbitkan.")
+
+	var notFound bytes.Buffer
+	require.NoError(t, th.Render(&notFound, theme.TemplateNotFound, &theme.Page{Site: site, Locale: "fr"}))
+	assert.Contains(t, notFound.String(), "<h1>not_found</h1>")
+}
+
+func testTheme() fstest.MapFS {
+	return fstest.MapFS{
+		"layout.html":         {Data: []byte(`{{template "brand" .}}|{{block "main" .}}{{end}}`)},
+		"partials/brand.html": {Data: []byte(`{{define "brand"}}{{.Site.Title}}{{end}}`)},
+		"single.html":         {Data: []byte(`{{define "main"}}{{.Entry.Title}}{{end}}`)},
+		"single-page.html":    {Data: []byte(`{{define "main"}}page {{.Entry.Title}}{{end}}`)},
+		"list.html":           {Data: []byte(`{{define "main"}}{{len .Entries}}{{end}}`)},
+		"taxonomy-tag.html":   {Data: []byte(`{{define "main"}}tag {{.Term.Name}}{{end}}`)},
+		"i18n/en.json":        {Data: []byte(`{"greeting": "Hello %s"}`)},
+	}
+}
+
+func TestTheme_Hierarchy(t *testing.T) {
+	th, err := New(testTheme(), testOptions)
+	require.NoError(t, err)
+
+	render := func(name string, page *theme.Page) string {
+		page.Site = theme.Site{Title: "Blog"}
+		var out bytes.Buffer
+		require.NoError(t, th.Render(&out, name, page))
+		return out.String()
+	}
+	assert.Equal(t, "Blog|Hi", render(theme.TemplateSingle, &theme.Page{Entry: &content.Content{Type: "post", Title: "Hi"}}))
+	assert.Equal(t, "Blog|page About", render(theme.TemplateSingle, &theme.Page{Entry: &content.Content{Type: "page", Title: "About"}}))
+	assert.Equal(t, "Blog|0", render(theme.TemplateHome, &theme.Page{}))
+	assert.Equal(t, "Blog|tag Go", render(theme.TemplateTaxonomy, &theme.Page{Term: &theme.Term{Kind: theme.TermTag, Name: "Go"}}))
+	assert.Equal(t, "Blog|0", render(theme.TemplateTaxonomy, &theme.Page{Term: &theme.Term{Kind: theme.TermCategory}}))
+
+	var out bytes.Buffer
+	assert.ErrorIs(t, th.Render(&out, theme.TemplateNotFound, &theme.Page{}), theme.ErrTemplateNotFound)
+}
+
+func TestTheme_Funcs(t *testing.T) {
+	fsys := testTheme()
+	fsys["single.html"] = &fstest.MapFile{Data: []byte(
+		`{{define "main"}}{{url .Path}} {{media "m 1"}} {{media "m1" "thumb"}} {{tagURL "a b"}} {{t .Locale "greeting" "Budi"}} {{t .Locale "missing"}}{{end}}`,
+	)}
+	th, err := New(fsys, testOptions)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, th.Render(&out, theme.TemplateSingle, &theme.Page{Path: "/about", Locale: "en-GB", Entry: &content.Content{}}))
+	assert.Equal(t, "|https://example.com/about /api/v1/media/m%201 /api/v1/media/m1/sizes/thumb /topics/a%20b Hello Budi missing", out.String())
+}
+
+func TestTheme_Fingerprint(t *testing.T) {
+	fsys := testTheme()
+	th, err := New(fsys, testOptions)
+	require.NoError(t, err)
+
+	defaultTheme, err := New(Default(), testOptions)
+	require.NoError(t, err)
+	assert.NotEqual(t, defaultTheme.Fingerprint(), th.Fingerprint())
+
+	fsys["list.html"] = &fstest.MapFile{Data: []byte(`{{define "main"}}-{{end}}`)}
+	changed, err := New(fsys, testOptions)
+	require.NoError(t, err)
+	assert.NotEqual(t, th.Fingerprint(), changed.Fingerprint())
+}
+
+func TestTheme_Invalid(t *testing.T) {
+	_, err := New(fstest.MapFS{"single.html": {Data: []byte(`{{define "main"}}{{end}}`)}}, testOptions)
+	assert.Error(t, err)
+
+	_, err = New(fstest.MapFS{
+		"layout.html": {Data: []byte(`{{block "main" .}}{{end}}`)},
+		"single.html": {Data: []byte(`{{define "main"}}{{end}}`)},
+	}, testOptions)
+	assert.Error(t, err)
+
+	fsys := testTheme()
+	fsys["i18n/en.json"] = &fstest.MapFile{Data: []byte(`{`)}
+	_, err = New(fsys, testOptions)
+	assert.Error(t, err)
+}
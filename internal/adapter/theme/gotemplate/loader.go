@@ -0,0 +1,103 @@
+package gotemplate
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+)
+
+// Loader loads the themes installed as subdirectories of a themes directory. The built-in theme is
+// always available under theme.DefaultTheme, unless a directory of that name overrides it.
+type Loader struct {
+	dir    string
+	opts   Options
+	reload bool
+}
+
+var _ theme.Loader = (*Loader)(nil)
+
+// NewLoader returns a loader of the themes in dir. With reload set, loaded themes parse their
+// templates again on every render, so template edits show up without a restart.
+func NewLoader(dir string, opts Options, reload bool) *Loader {
+	return &Loader{dir: dir, opts: opts, reload: reload}
+}
+
+func (l *Loader) List() ([]string, error) {
+	names := []string{theme.DefaultTheme}
+	if l.dir == "" {
+		return names, nil
+	}
+	entries, err := os.ReadDir(l.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == theme.DefaultTheme {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(l.dir, e.Name(), layoutFile)); err == nil {
+			names = append(names, e.Name())
+		}
+	}
+	slices.Sort(names[1:])
+	return names, nil
+}
+
+func (l *Loader) Load(name string) (theme.Renderer, error) {
+	fsys, err := l.open(name)
+	if err != nil {
+		return nil, err
+	}
+	t, err := New(fsys, l.opts)
+	if err != nil {
+		return nil, err
+	}
+	if l.reload {
+		return &reloader{fsys: fsys, opts: l.opts}, nil
+	}
+	return t, nil
+}
+
+func (l *Loader) open(name string) (fs.FS, error) {
+	if name == "" || !fs.ValidPath(name) || filepath.Base(name) != name {
+		return nil, theme.ErrNotFound
+	}
+	if l.dir != "" {
+		dir := filepath.Join(l.dir, name)
+		if _, err := os.Stat(filepath.Join(dir, layoutFile)); err == nil {
+			return os.DirFS(dir), nil
+		}
+	}
+	if name == theme.DefaultTheme {
+		return Default(), nil
+	}
+	return nil, theme.ErrNotFound
+}
+
+// reloader parses its theme again before every render. Broken edits are reported by the render
+// instead of taking the site down for good.
+type reloader struct {
+	fsys fs.FS
+	opts Options
+}
+
+func (r *reloader) Render(w io.Writer, name string, page *theme.Page) error {
+	t, err := New(r.fsys, r.opts)
+	if err != nil {
+		return err
+	}
+	return t.Render(w, name, page)
+}
+
+func (r *reloader) Fingerprint() string {
+	sum, _ := fingerprint(r.fsys)
+	return sum
+}
@@ -0,0 +1,67 @@
+package gotemplate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTheme(t *testing.T, dir string, files map[string]string) {
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	}
+}
+
+func TestLoader(t *testing.T) {
+	dir := t.TempDir()
+	writeTheme(t, filepath.Join(dir, "minimal"), map[string]string{
+		"layout.html": `{{block "main" .}}{{end}}`,
+		"single.html": `{{define "main"}}v1 {{.Entry.Title}}{{end}}`,
+		"list.html":   `{{define "main"}}list{{end}}`,
+	})
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "not-a-theme"), 0o755))
+
+	loader := NewLoader(dir, Options{}, false)
+	names, err := loader.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{theme.DefaultTheme, "minimal"}, names)
+
+	_, err = loader.Load(theme.DefaultTheme)
+	require.NoError(t, err)
+	for _, name := range []string{"missing", "not-a-theme", "../minimal", "", "."} {
+		_, err = loader.Load(name)
+		assert.ErrorIs(t, err, theme.ErrNotFound, name)
+	}
+
+	minimal, err := loader.Load("minimal")
+	require.NoError(t, err)
+	reloading, err := NewLoader(dir, Options{}, true).Load("minimal")
+	require.NoError(t, err)
+	fingerprint := reloading.Fingerprint()
+
+	writeTheme(t, filepath.Join(dir, "minimal"), map[string]string{"single.html": `{{define "main"}}v2 {{.Entry.Title}}{{end}}`})
+	page := &theme.Page{Entry: &content.Content{Title: "Hi"}}
+
+	var out bytes.Buffer
+	require.NoError(t, minimal.Render(&out, theme.TemplateSingle, page))
+	assert.Equal(t, "v1 Hi", out.String())
+
+	out.Reset()
+	require.NoError(t, reloading.Render(&out, theme.TemplateSingle, page))
+	assert.Equal(t, "v2 Hi", out.String())
+	assert.NotEqual(t, fingerprint, reloading.Fingerprint())
+}
+
+func TestLoader_NoDirectory(t *testing.T) {
+	names, err := NewLoader(filepath.Join(t.TempDir(), "missing"), Options{}, false).List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{theme.DefaultTheme}, names)
+}
@@ -0,0 +1,219 @@
+// Package wxr reads WordPress eXtended RSS exports into transfer bundles. Posts and pages become
+// content entries, authors become users, and categories and tags keep their hierarchy and
+// assignments. Attachments, revisions, menu items and other post types are left out; media bytes
+// are not downloaded.
+package wxr
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/transfer"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+	"github.com/mashurimansur/goCMS/internal/utils/markdown"
+)
+
+// Elements are matched by local name since the wp namespace URI changes with the WXR version.
+type document struct {
+	Channel struct {
+		Authors    []author     `xml:"author"`
+		Categories []wpCategory `xml:"category"`
+		Tags       []wpTag      `xml:"tag"`
+		Items      []item       `xml:"item"`
+	} `xml:"channel"`
+}
+
+type author struct {
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+type wpCategory struct {
+	Nicename    string `xml:"category_nicename"`
+	Parent      string `xml:"category_parent"`
+	Name        string `xml:"cat_name"`
+	Description string `xml:"category_description"`
+}
+
+type wpTag struct {
+	Slug string `xml:"tag_slug"`
+	Name string `xml:"tag_name"`
+}
+
+type item struct {
+	Title   string    `xml:"title"`
+	Creator string    `xml:"creator"`
+	Encoded []encoded `xml:"encoded"`
+	PostID  string    `xml:"post_id"`
+	Date    string    `xml:"post_date"`
+	DateGMT string    `xml:"post_date_gmt"`
+	Name    string    `xml:"post_name"`
+	Status  string    `xml:"status"`
+	Type    string    `xml:"post_type"`
+	Terms   []term    `xml:"category"`
+}
+
+// encoded holds content:encoded or excerpt:encoded, told apart by their namespace.
+type encoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type term struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+// dateLayout is the layout of post dates; unset dates are written as zeros.
+const dateLayout = "2006-01-02 15:04:05"
+
+// Read parses a WXR export. Users are identified by login, categories and tags by slug and entries
+// by post ID, which the importer remaps to new IDs.
+func Read(r io.Reader) (*transfer.Bundle, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("cannot parse WXR: %w", err)
+	}
+
+	b := &transfer.Bundle{Version: transfer.FormatVersion}
+	for _, a := range doc.Channel.Authors {
+		b.Users = append(b.Users, &user.User{
+			ID:       a.Login,
+			Username: a.Login,
+			Email:    a.Email,
+			FullName: a.DisplayName,
+		})
+	}
+
+	categories := make(map[string]bool)
+	for _, c := range doc.Channel.Categories {
+		categories[c.Nicename] = true
+		b.Categories = append(b.Categories, &category.Category{
+			ID:          c.Nicename,
+			ParentID:    c.Parent,
+			Name:        c.Name,
+			Slug:        c.Nicename,
+			Description: c.Description,
+		})
+	}
+	tags := make(map[string]bool)
+	for _, t := range doc.Channel.Tags {
+		tags[t.Slug] = true
+		b.Tags = append(b.Tags, &tag.Tag{ID: t.Slug, Name: t.Name, Slug: t.Slug})
+	}
+
+	for _, it := range doc.Channel.Items {
+		status, ok := statuses[it.Status]
+		if !ok || (it.Type != "post" && it.Type != "page") {
+			continue
+		}
+
+		c := &transfer.Content{Content: content.Content{
+			ID:         it.PostID,
+			Type:       it.Type,
+			Title:      it.Title,
+			Slug:       it.Name,
+			BodyFormat: markdown.FormatHTML,
+			Status:     status,
+			AuthorID:   it.Creator,
+		}}
+		for _, e := range it.Encoded {
+			if strings.Contains(e.XMLName.Space, "excerpt") {
+				c.Excerpt = strings.TrimSpace(e.Value)
+			} else {
+				c.Body = autop(e.Value)
+			}
+		}
+		if date := parseDate(it.DateGMT, time.UTC); !date.IsZero() {
+			c.PublishedAt, c.CreatedAt = date, date
+		} else if date := parseDate(it.Date, time.Local); !date.IsZero() {
+			c.PublishedAt, c.CreatedAt = date, date
+		}
+		if status != content.StatusPublished {
+			c.PublishedAt = time.Time{}
+		}
+
+		// Terms used by items are not always declared in the channel.
+		for _, t := range it.Terms {
+			switch t.Domain {
+			case "category":
+				if !categories[t.Nicename] {
+					categories[t.Nicename] = true
+					b.Categories = append(b.Categories, &category.Category{ID: t.Nicename, Name: t.Name, Slug: t.Nicename})
+				}
+				c.CategoryIDs = append(c.CategoryIDs, t.Nicename)
+			case "post_tag":
+				if !tags[t.Nicename] {
+					tags[t.Nicename] = true
+					b.Tags = append(b.Tags, &tag.Tag{ID: t.Nicename, Name: t.Name, Slug: t.Nicename})
+				}
+				c.TagIDs = append(c.TagIDs, t.Nicename)
+			}
+		}
+		b.Contents = append(b.Contents, c)
+	}
+	return b, nil
+}
+
+// statuses maps the WordPress statuses worth importing to content statuses. Scheduled posts stay
+// scheduled through their future publication date; private and pending posts become drafts.
+var statuses = map[string]string{
+	"publish": content.StatusPublished,
+	"future":  content.StatusPublished,
+	"draft":   content.StatusDraft,
+	"pending": content.StatusDraft,
+	"private": content.StatusDraft,
+}
+
+func parseDate(s string, loc *time.Location) time.Time {
+	t, err := time.ParseInLocation(dateLayout, strings.TrimSpace(s), loc)
+	if err != nil || t.Year() < 1 {
+		return time.Time{}
+	}
+	return t
+}
+
+// autop wraps the paragraphs of classic-editor posts, which WordPress stores separated by blank
+// lines instead of <p> elements. Bodies that already contain paragraphs are left alone.
+func autop(body string) string {
+	body = strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n"))
+	if body == "" || strings.Contains(body, "<p>") || strings.Contains(body, "<p ") {
+		return body
+	}
+
+	var b strings.Builder
+	for _, paragraph := range strings.Split(body, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		if isBlock(paragraph) {
+			b.WriteString(paragraph)
+			continue
+		}
+		b.WriteString("<p>" + strings.ReplaceAll(paragraph, "\n", "<br>\n") + "</p>")
+	}
+	return b.String()
+}
+
+var blockTags = []string{"<h1", "<h2", "<h3", "<h4", "<h5", "<h6", "<ul", "<ol", "<blockquote", "<pre", "<table", "<figure", "<div", "<!--"}
+
+func isBlock(paragraph string) bool {
+	for _, t := range blockTags {
+		if strings.HasPrefix(paragraph, t) {
+			return true
+		}
+	}
+	return false
+}
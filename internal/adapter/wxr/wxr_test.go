@@ -0,0 +1,118 @@
+package wxr
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const export = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<title>Blog</title>
+	<wp:wxr_version>1.2</wp:wxr_version>
+	<wp:author>
+		<wp:author_id>1</wp:author_id>
+		<wp:author_login><![CDATA[budi]]></wp:author_login>
+		<wp:author_email><![CDATA[budi@example.com]]></wp:author_email>
+		<wp:author_display_name><![CDATA[Budi Santoso]]></wp:author_display_name>
+	</wp:author>
+	<wp:category>
+		<wp:term_id>2</wp:term_id>
+		<wp:category_nicename><![CDATA[news]]></wp:category_nicename>
+		<wp:category_parent><![CDATA[]]></wp:category_parent>
+		<wp:cat_name><![CDATA[News]]></wp:cat_name>
+	</wp:category>
+	<wp:category>
+		<wp:term_id>3</wp:term_id>
+		<wp:category_nicename><![CDATA[local]]></wp:category_nicename>
+		<wp:category_parent><![CDATA[news]]></wp:category_parent>
+		<wp:cat_name><![CDATA[Local]]></wp:cat_name>
+	</wp:category>
+	<wp:tag>
+		<wp:term_id>4</wp:term_id>
+		<wp:tag_slug><![CDATA[go]]></wp:tag_slug>
+		<wp:tag_name><![CDATA[Go]]></wp:tag_name>
+	</wp:tag>
+	<item>
+		<title>Hello World</title>
+		<dc:creator><![CDATA[budi]]></dc:creator>
+		<content:encoded><![CDATA[First paragraph.
+
+Second line
+continues.]]></content:encoded>
+		<excerpt:encoded><![CDATA[Short]]></excerpt:encoded>
+		<wp:post_id>10</wp:post_id>
+		<wp:post_date><![CDATA[2020-03-01 17:00:00]]></wp:post_date>
+		<wp:post_date_gmt><![CDATA[2020-03-01 10:00:00]]></wp:post_date_gmt>
+		<wp:post_name><![CDATA[hello-world]]></wp:post_name>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+		<category domain="category" nicename="local"><![CDATA[Local]]></category>
+		<category domain="post_tag" nicename="go"><![CDATA[Go]]></category>
+		<category domain="post_tag" nicename="gophers"><![CDATA[Gophers]]></category>
+	</item>
+	<item>
+		<title>About</title>
+		<dc:creator><![CDATA[budi]]></dc:creator>
+		<content:encoded><![CDATA[<p>About us</p>]]></content:encoded>
+		<wp:post_id>11</wp:post_id>
+		<wp:post_date_gmt><![CDATA[0000-00-00 00:00:00]]></wp:post_date_gmt>
+		<wp:post_name><![CDATA[about]]></wp:post_name>
+		<wp:status><![CDATA[draft]]></wp:status>
+		<wp:post_type><![CDATA[page]]></wp:post_type>
+	</item>
+	<item>
+		<title>photo.jpg</title>
+		<wp:post_id>12</wp:post_id>
+		<wp:status><![CDATA[inherit]]></wp:status>
+		<wp:post_type><![CDATA[attachment]]></wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func TestRead(t *testing.T) {
+	b, err := Read(strings.NewReader(export))
+	require.NoError(t, err)
+
+	require.Len(t, b.Users, 1)
+	assert.Equal(t, "budi", b.Users[0].ID)
+	assert.Equal(t, "budi@example.com", b.Users[0].Email)
+	assert.Equal(t, "Budi Santoso", b.Users[0].FullName)
+
+	require.Len(t, b.Categories, 2)
+	assert.Equal(t, "news", b.Categories[1].ParentID)
+	require.Len(t, b.Tags, 2)
+	assert.Equal(t, "gophers", b.Tags[1].Slug)
+
+	require.Len(t, b.Contents, 2)
+	post := b.Contents[0]
+	assert.Equal(t, "10", post.ID)
+	assert.Equal(t, "hello-world", post.Slug)
+	assert.Equal(t, content.StatusPublished, post.Status)
+	assert.Equal(t, "budi", post.AuthorID)
+	assert.Equal(t, "Short", post.Excerpt)
+	assert.Equal(t, "<p>First paragraph.</p>\n<p>Second line<br>\ncontinues.</p>", post.Body)
+	assert.Equal(t, time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC), post.PublishedAt)
+	assert.Equal(t, []string{"local"}, post.CategoryIDs)
+	assert.Equal(t, []string{"go", "gophers"}, post.TagIDs)
+
+	page := b.Contents[1]
+	assert.Equal(t, "page", page.Type)
+	assert.Equal(t, content.StatusDraft, page.Status)
+	assert.Equal(t, "<p>About us</p>", page.Body)
+	assert.True(t, page.PublishedAt.IsZero())
+}
+
+func TestRead_Invalid(t *testing.T) {
+	_, err := Read(strings.NewReader("<rss><channel>"))
+	assert.Error(t, err)
+}
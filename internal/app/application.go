@@ -2,21 +2,73 @@ package app
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/mashurimansur/goCMS/internal/adapter/http/handler"
 	"github.com/mashurimansur/goCMS/internal/adapter/http/router"
+	"github.com/mashurimansur/goCMS/internal/adapter/notify/webhook"
+	"github.com/mashurimansur/goCMS/internal/adapter/search/memory"
+	"github.com/mashurimansur/goCMS/internal/adapter/storage/local"
+	"github.com/mashurimansur/goCMS/internal/adapter/theme/gotemplate"
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	domaincategory "github.com/mashurimansur/goCMS/internal/domain/category"
+	domaincontent "github.com/mashurimansur/goCMS/internal/domain/content"
 	domainperson "github.com/mashurimansur/goCMS/internal/domain/person"
+	domainsnippet "github.com/mashurimansur/goCMS/internal/domain/snippet"
+	domaintag "github.com/mashurimansur/goCMS/internal/domain/tag"
+	domaintrash "github.com/mashurimansur/goCMS/internal/domain/trash"
+	sqlbulk "github.com/mashurimansur/goCMS/internal/repository/bulk"
+	sqlcategory "github.com/mashurimansur/goCMS/internal/repository/category"
+	sqlcomment "github.com/mashurimansur/goCMS/internal/repository/comment"
+	sqlcontent "github.com/mashurimansur/goCMS/internal/repository/content"
+	sqlcontenttype "github.com/mashurimansur/goCMS/internal/repository/contenttype"
+	sqlform "github.com/mashurimansur/goCMS/internal/repository/form"
+	sqllock "github.com/mashurimansur/goCMS/internal/repository/lock"
+	sqlmedia "github.com/mashurimansur/goCMS/internal/repository/media"
+	sqlmenu "github.com/mashurimansur/goCMS/internal/repository/menu"
 	sqlperson "github.com/mashurimansur/goCMS/internal/repository/person"
+	sqlpreview "github.com/mashurimansur/goCMS/internal/repository/preview"
+	sqlredirect "github.com/mashurimansur/goCMS/internal/repository/redirect"
+	sqlseo "github.com/mashurimansur/goCMS/internal/repository/seo"
+	sqlsetting "github.com/mashurimansur/goCMS/internal/repository/setting"
+	sqlsnippet "github.com/mashurimansur/goCMS/internal/repository/snippet"
+	sqltag "github.com/mashurimansur/goCMS/internal/repository/tag"
 	sqluser "github.com/mashurimansur/goCMS/internal/repository/user"
+	bulkusecase "github.com/mashurimansur/goCMS/internal/usecase/bulk"
+	categoryusecase "github.com/mashurimansur/goCMS/internal/usecase/category"
+	commentusecase "github.com/mashurimansur/goCMS/internal/usecase/comment"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	contenttypeusecase "github.com/mashurimansur/goCMS/internal/usecase/contenttype"
+	feedusecase "github.com/mashurimansur/goCMS/internal/usecase/feed"
+	formusecase "github.com/mashurimansur/goCMS/internal/usecase/form"
+	lockusecase "github.com/mashurimansur/goCMS/internal/usecase/lock"
+	mediausecase "github.com/mashurimansur/goCMS/internal/usecase/media"
+	menuusecase "github.com/mashurimansur/goCMS/internal/usecase/menu"
 	personusecase "github.com/mashurimansur/goCMS/internal/usecase/person"
+	previewusecase "github.com/mashurimansur/goCMS/internal/usecase/preview"
+	redirectusecase "github.com/mashurimansur/goCMS/internal/usecase/redirect"
+	seousecase "github.com/mashurimansur/goCMS/internal/usecase/seo"
+	snippetusecase "github.com/mashurimansur/goCMS/internal/usecase/snippet"
+	tagusecase "github.com/mashurimansur/goCMS/internal/usecase/tag"
+	themeusecase "github.com/mashurimansur/goCMS/internal/usecase/theme"
+	trashusecase "github.com/mashurimansur/goCMS/internal/usecase/trash"
 	userusecase "github.com/mashurimansur/goCMS/internal/usecase/user"
 	"github.com/mashurimansur/goCMS/internal/utils/config"
 	"github.com/mashurimansur/goCMS/internal/utils/database"
+	"github.com/mashurimansur/goCMS/internal/utils/imaging"
+	"github.com/mashurimansur/goCMS/internal/utils/locale"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+	"github.com/mashurimansur/goCMS/internal/utils/signer"
 	"github.com/mashurimansur/goCMS/internal/utils/token"
 )
 
@@ -25,8 +77,14 @@ type Application struct {
 	engine   *gin.Engine
 	httpAddr string
 	dbConn   *database.Connection
+
+	redirects      redirectusecase.UseCase
+	stopBackground context.CancelFunc
 }
 
+// redirectHitsFlushInterval controls how often in-memory redirect hit counters are persisted.
+const redirectHitsFlushInterval = time.Minute
+
 // New creates a fully wired application instance ready to run.
 func New(ctx context.Context, cfg config.AppConfig) (*Application, error) {
 	dbConn, err := database.NewConnection(ctx, cfg.Database)
@@ -50,21 +108,189 @@ func New(ctx context.Context, cfg config.AppConfig) (*Application, error) {
 		return nil, fmt.Errorf("cannot parse token duration: %w", err)
 	}
 
+	mediaOptions, err := buildMediaOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mediaStorage, err := local.NewStorage(cfg.Media.StoragePath)
+	if err != nil {
+		return nil, err
+	}
+	mediaUseCase := mediausecase.NewMediaUseCase(sqlmedia.NewMediaRepository(dbConn.DB), mediaStorage, mediaOptions)
+	mediaHandler := handler.NewMediaHandler(mediaUseCase, mediaOptions.MaxSize)
+
 	userRepo := sqluser.NewUserRepository(dbConn.DB)
-	userUseCase := userusecase.NewUserUseCase(userRepo, tokenMaker, tokenDuration)
+	userUseCase := userusecase.NewUserUseCase(userRepo, tokenMaker, tokenDuration, mediaUseCase)
 	userHandler := handler.NewUserHandler(userUseCase)
 
+	permalinks, err := permalink.Parse(cfg.PermalinkPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse permalink patterns: %w", err)
+	}
+
+	contentRepo := sqlcontent.NewContentRepository(dbConn.DB)
+	categoryRepo := sqlcategory.NewCategoryRepository(dbConn.DB)
+	tagRepo := sqltag.NewTagRepository(dbConn.DB)
+	snippetUseCase := buildSnippetUseCase(cfg, dbConn.DB)
+	contentUseCase, err := buildContentUseCase(ctx, cfg, dbConn.DB, contentRepo, categoryRepo, tagRepo, permalinks, snippetUseCase)
+	if err != nil {
+		return nil, err
+	}
+	contentHandler := handler.NewContentHandler(contentUseCase)
+	categoryUseCase := categoryusecase.NewCategoryUseCase(categoryRepo, contentRepo)
+	categoryHandler := handler.NewCategoryHandler(categoryUseCase)
+	tagUseCase := tagusecase.NewTagUseCase(tagRepo, contentRepo)
+	tagHandler := handler.NewTagHandler(tagUseCase)
+	snippetHandler := handler.NewSnippetHandler(snippetUseCase)
+
+	contentTypeUseCase := contenttypeusecase.NewContentTypeUseCase(
+		sqlcontenttype.NewContentTypeRepository(dbConn.DB),
+		sqlcontenttype.NewEntryRepository(dbConn.DB),
+		sqlcontenttype.NewReferenceRepository(dbConn.DB),
+	)
+	contentTypeHandler := handler.NewContentTypeHandler(contentTypeUseCase)
+
+	spamRules, err := buildSpamRules(cfg.Comments)
+	if err != nil {
+		return nil, err
+	}
+	commentUseCase := commentusecase.NewCommentUseCase(sqlcomment.NewCommentRepository(dbConn.DB), contentRepo, userRepo, spamRules)
+	commentHandler := handler.NewCommentHandler(commentUseCase)
+
+	if !strings.HasPrefix(cfg.CategoryURLPattern, "/") || !strings.Contains(cfg.CategoryURLPattern, "{slug}") {
+		return nil, fmt.Errorf("invalid category URL pattern %q: must start with / and contain {slug}", cfg.CategoryURLPattern)
+	}
+	menuUseCase := menuusecase.NewMenuUseCase(
+		sqlmenu.NewMenuRepository(dbConn.DB), contentRepo, categoryRepo, permalinks, cfg.CategoryURLPattern,
+	)
+	menuHandler := handler.NewMenuHandler(menuUseCase)
+
+	seoOptions, err := buildSEOOptions(cfg.SEO)
+	if err != nil {
+		return nil, err
+	}
+	seoHandler := handler.NewSEOHandler(seousecase.NewSEOUseCase(
+		sqlseo.NewSEORepository(dbConn.DB), contentRepo, mediaUseCase, permalinks, seoOptions,
+	))
+
+	feedOptions, err := buildFeedOptions(cfg.Feed, seoOptions)
+	if err != nil {
+		return nil, err
+	}
+	feedOptions.Locale = contentUseCase.Locales().Default()
+	feedHandler := handler.NewFeedHandler(feedusecase.NewFeedUseCase(contentRepo, categoryRepo, tagRepo, userRepo, permalinks, snippetUseCase, feedOptions))
+
+	previewOptions, err := buildPreviewOptions(cfg.Preview, seoOptions)
+	if err != nil {
+		return nil, err
+	}
+	previewHandler := handler.NewPreviewHandler(previewusecase.NewPreviewUseCase(
+		sqlpreview.NewPreviewRepository(dbConn.DB), contentUseCase, snippetUseCase, tokenMaker, previewOptions,
+	))
+
+	lockTTL, err := time.ParseDuration(cfg.LockTTL)
+	if err != nil || lockTTL <= 0 {
+		return nil, fmt.Errorf("invalid lock TTL %q", cfg.LockTTL)
+	}
+	lockHandler := handler.NewLockHandler(lockusecase.NewLockUseCase(
+		sqllock.NewLockRepository(dbConn.DB), userRepo, lockusecase.Options{TTL: lockTTL},
+	))
+
+	trashOptions, purgeInterval, err := buildTrashOptions(cfg.Trash)
+	if err != nil {
+		return nil, err
+	}
+	trashUseCase := trashusecase.NewTrashUseCase(map[string]domaintrash.Bin{
+		domaintrash.TypeContent:  contentUseCase,
+		domaintrash.TypeEntry:    contentTypeUseCase,
+		domaintrash.TypeCategory: categoryUseCase,
+		domaintrash.TypeTag:      tagUseCase,
+		domaintrash.TypeMenu:     menuUseCase,
+		domaintrash.TypeComment:  commentUseCase,
+		domaintrash.TypeMedia:    mediaUseCase,
+		domaintrash.TypeUser:     userUseCase,
+	}, userUseCase, trashOptions)
+	trashHandler := handler.NewTrashHandler(trashUseCase)
+
+	bulkOptions, err := buildBulkOptions(cfg.Bulk)
+	if err != nil {
+		return nil, err
+	}
+	bulkUseCase := bulkusecase.NewBulkUseCase(sqlbulk.NewBulkRepository(dbConn.DB), contentUseCase, userUseCase, bulkOptions)
+	if _, err := bulkUseCase.FailInterrupted(ctx); err != nil {
+		return nil, fmt.Errorf("cannot fail interrupted bulk jobs: %w", err)
+	}
+	bulkHandler := handler.NewBulkHandler(bulkUseCase)
+
+	formOptions, formPurgeInterval, err := buildFormOptions(cfg.Forms)
+	if err != nil {
+		return nil, err
+	}
+	formUseCase := formusecase.NewFormUseCase(
+		sqlform.NewFormRepository(dbConn.DB), webhook.NewNotifier(nil, cfg.Forms.WebhookSecret), formOptions,
+	)
+	formHandler := handler.NewFormHandler(formUseCase)
+
+	redirectUseCase := redirectusecase.NewRedirectUseCase(sqlredirect.NewRedirectRepository(dbConn.DB))
+	if err := redirectUseCase.Reload(ctx); err != nil {
+		return nil, fmt.Errorf("cannot load redirect rules: %w", err)
+	}
+	redirectHandler := handler.NewRedirectHandler(redirectUseCase)
+
+	rendering, err := strconv.ParseBool(cfg.Theme.Rendering)
+	if err != nil {
+		return nil, fmt.Errorf("invalid theme rendering flag %q", cfg.Theme.Rendering)
+	}
+	var themeHandler *handler.ThemeHandler
+	if rendering {
+		themeHandler, err = buildThemeHandler(ctx, cfg, dbConn.DB, contentUseCase, categoryRepo, tagRepo, seoOptions.SiteURL, feedOptions.Title)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	trustedProxies, err := buildTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
 	engine := router.NewGinEngine(router.Options{
-		Mode:          cfg.GinMode,
-		PersonHandler: personHandler,
-		UserHandler:   userHandler,
-		TokenMaker:    tokenMaker,
+		Mode:               cfg.GinMode,
+		TrustedProxies:     trustedProxies,
+		PersonHandler:      personHandler,
+		UserHandler:        userHandler,
+		ContentHandler:     contentHandler,
+		CategoryHandler:    categoryHandler,
+		TagHandler:         tagHandler,
+		RedirectHandler:    redirectHandler,
+		MediaHandler:       mediaHandler,
+		ContentTypeHandler: contentTypeHandler,
+		CommentHandler:     commentHandler,
+		MenuHandler:        menuHandler,
+		SEOHandler:         seoHandler,
+		FeedHandler:        feedHandler,
+		PreviewHandler:     previewHandler,
+		LockHandler:        lockHandler,
+		TrashHandler:       trashHandler,
+		BulkHandler:        bulkHandler,
+		ThemeHandler:       themeHandler,
+		SnippetHandler:     snippetHandler,
+		FormHandler:        formHandler,
+		Redirects:          redirectUseCase,
+		TokenMaker:         tokenMaker,
+		Roles:              userUseCase,
 	})
 
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	go flushRedirectHits(backgroundCtx, redirectUseCase)
+	go purgeTrash(backgroundCtx, trashUseCase, purgeInterval)
+	go purgeFormSubmissions(backgroundCtx, formUseCase, formPurgeInterval)
+
 	app := &Application{
-		engine:   engine,
-		httpAddr: cfg.HTTPAddr,
-		dbConn:   dbConn,
+		engine:         engine,
+		httpAddr:       cfg.HTTPAddr,
+		dbConn:         dbConn,
+		redirects:      redirectUseCase,
+		stopBackground: stopBackground,
 	}
 
 	return app, nil
@@ -86,13 +312,370 @@ func (a *Application) Run() error {
 
 // Close releases infrastructure resources such as database connections.
 func (a *Application) Close() error {
-	if a == nil || a.dbConn == nil {
+	if a == nil {
+		return nil
+	}
+	if a.stopBackground != nil {
+		a.stopBackground()
+	}
+	if a.redirects != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := a.redirects.FlushHits(ctx); err != nil {
+			log.Printf("cannot flush redirect hits: %v", err)
+		}
+	}
+	if a.dbConn == nil {
 		return nil
 	}
 
 	return a.dbConn.Close()
 }
 
+// flushRedirectHits periodically persists redirect hit counters until ctx is cancelled.
+func flushRedirectHits(ctx context.Context, redirects redirectusecase.UseCase) {
+	ticker := time.NewTicker(redirectHitsFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := redirects.FlushHits(ctx); err != nil {
+				log.Printf("cannot flush redirect hits: %v", err)
+			}
+		}
+	}
+}
+
+// purgeTrash periodically deletes the trashed items that outlived their retention until ctx is cancelled.
+func purgeTrash(ctx context.Context, bin trashusecase.UseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := bin.PurgeExpired(ctx); err != nil {
+				log.Printf("cannot purge trash: %v", err)
+			}
+		}
+	}
+}
+
+// purgeFormSubmissions periodically deletes the form submissions that outlived the retention of
+// their form until ctx is cancelled.
+func purgeFormSubmissions(ctx context.Context, forms formusecase.UseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := forms.PurgeExpired(ctx); err != nil {
+				log.Printf("cannot purge form submissions: %v", err)
+			}
+		}
+	}
+}
+
+// buildTrustedProxies parses the comma-separated addresses and CIDR ranges of trusted proxies.
+func buildTrustedProxies(raw string) ([]string, error) {
+	var proxies []string
+	for _, p := range strings.Split(raw, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if net.ParseIP(p) == nil {
+			if _, _, err := net.ParseCIDR(p); err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", p)
+			}
+		}
+		proxies = append(proxies, p)
+	}
+	return proxies, nil
+}
+
+// buildMediaOptions parses the upload limits, image sizes and URL signing key of the media library.
+func buildMediaOptions(cfg config.AppConfig) (mediausecase.Options, error) {
+	maxUploadSize, err := strconv.ParseInt(cfg.Media.MaxUploadSize, 10, 64)
+	if err != nil || maxUploadSize <= 0 {
+		return mediausecase.Options{}, fmt.Errorf("invalid media max upload size %q", cfg.Media.MaxUploadSize)
+	}
+	imageSizes, err := imaging.ParsePresets(cfg.Media.ImageSizes)
+	if err != nil {
+		return mediausecase.Options{}, fmt.Errorf("cannot parse media image sizes: %w", err)
+	}
+	opts := mediausecase.Options{
+		MaxSize:      maxUploadSize,
+		AllowedTypes: strings.Split(cfg.Media.AllowedTypes, ","),
+		BaseURL:      cfg.Media.BaseURL,
+		Sizes:        imageSizes,
+	}
+	// Without a key of their own, ad-hoc transforms stay disabled: a leaked transform signature
+	// must not tell anything about the key access tokens are signed with.
+	if cfg.Media.SigningKey == "" {
+		log.Printf("MEDIA_SIGNING_KEY is not set, ad-hoc image transforms are disabled")
+		return opts, nil
+	}
+	if cfg.Media.SigningKey == cfg.TokenSymmetricKey {
+		return mediausecase.Options{}, errors.New("MEDIA_SIGNING_KEY must differ from TOKEN_SYMMETRIC_KEY")
+	}
+	urlSigner, err := signer.New(cfg.Media.SigningKey)
+	if err != nil {
+		return mediausecase.Options{}, fmt.Errorf("cannot create media URL signer: %w", err)
+	}
+	opts.Signer = urlSigner
+	return opts, nil
+}
+
+// buildSpamRules parses the comment spam heuristics from their environment representation.
+func buildSpamRules(cfg config.CommentConfig) (commentusecase.SpamRules, error) {
+	maxLinks, err := strconv.Atoi(cfg.MaxLinks)
+	if err != nil || maxLinks < 0 {
+		return commentusecase.SpamRules{}, fmt.Errorf("invalid comment max links %q", cfg.MaxLinks)
+	}
+	maxPerIP, err := strconv.Atoi(cfg.MaxPerIP)
+	if err != nil || maxPerIP < 0 {
+		return commentusecase.SpamRules{}, fmt.Errorf("invalid comment max per IP %q", cfg.MaxPerIP)
+	}
+	window, err := time.ParseDuration(cfg.RateWindow)
+	if err != nil {
+		return commentusecase.SpamRules{}, fmt.Errorf("cannot parse comment rate window: %w", err)
+	}
+
+	var blocked []string
+	for _, word := range strings.Split(cfg.BlockedWords, ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			blocked = append(blocked, word)
+		}
+	}
+
+	return commentusecase.SpamRules{
+		MaxLinks:     maxLinks,
+		BlockedWords: blocked,
+		MaxPerIP:     maxPerIP,
+		RateWindow:   window,
+	}, nil
+}
+
+// buildSEOOptions parses the site-wide SEO settings from their environment representation.
+func buildSEOOptions(cfg config.SEOConfig) (seousecase.Options, error) {
+	siteURL, err := url.Parse(cfg.SiteURL)
+	if err != nil || (siteURL.Scheme != "http" && siteURL.Scheme != "https") || siteURL.Host == "" {
+		return seousecase.Options{}, fmt.Errorf("invalid site URL %q", cfg.SiteURL)
+	}
+	allowIndexing, err := strconv.ParseBool(cfg.AllowIndexing)
+	if err != nil {
+		return seousecase.Options{}, fmt.Errorf("invalid SEO allow indexing %q", cfg.AllowIndexing)
+	}
+
+	var disallow []string
+	for _, path := range strings.Split(cfg.RobotsDisallow, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			disallow = append(disallow, path)
+		}
+	}
+
+	return seousecase.Options{
+		SiteURL:       cfg.SiteURL,
+		SiteName:      cfg.SiteName,
+		AllowIndexing: allowIndexing,
+		Disallow:      disallow,
+	}, nil
+}
+
+// buildFeedOptions parses the feed settings from their environment representation. Feeds share the
+// site URL and name of the SEO settings.
+func buildFeedOptions(cfg config.FeedConfig, seo seousecase.Options) (feedusecase.Options, error) {
+	fullContent, err := strconv.ParseBool(cfg.FullContent)
+	if err != nil {
+		return feedusecase.Options{}, fmt.Errorf("invalid feed full content %q", cfg.FullContent)
+	}
+	size, err := strconv.Atoi(cfg.Size)
+	if err != nil || size <= 0 {
+		return feedusecase.Options{}, fmt.Errorf("invalid feed size %q", cfg.Size)
+	}
+
+	title := cfg.Title
+	if title == "" {
+		title = seo.SiteName
+	}
+	return feedusecase.Options{
+		SiteURL:     seo.SiteURL,
+		Title:       title,
+		Description: cfg.Description,
+		FullContent: fullContent,
+		Size:        size,
+		ContentType: cfg.ContentType,
+	}, nil
+}
+
+// buildPreviewOptions parses the preview link settings from their environment representation.
+// Without a preview URL links point to the preview endpoint of the API on the site.
+func buildPreviewOptions(cfg config.PreviewConfig, seo seousecase.Options) (previewusecase.Options, error) {
+	duration, err := time.ParseDuration(cfg.Duration)
+	if err != nil || duration <= 0 {
+		return previewusecase.Options{}, fmt.Errorf("invalid preview duration %q", cfg.Duration)
+	}
+	maxDuration, err := time.ParseDuration(cfg.MaxDuration)
+	if err != nil || maxDuration < duration {
+		return previewusecase.Options{}, fmt.Errorf("invalid preview max duration %q", cfg.MaxDuration)
+	}
+
+	previewURL := cfg.URL
+	if previewURL == "" {
+		previewURL = strings.TrimSuffix(seo.SiteURL, "/") + "/api/v1/preview"
+	}
+	return previewusecase.Options{
+		URL:         previewURL,
+		Duration:    duration,
+		MaxDuration: maxDuration,
+	}, nil
+}
+
+// buildTrashOptions parses the trash retention and how often expired items are purged.
+func buildTrashOptions(cfg config.TrashConfig) (trashusecase.Options, time.Duration, error) {
+	retention, err := time.ParseDuration(cfg.Retention)
+	if err != nil || retention <= 0 {
+		return trashusecase.Options{}, 0, fmt.Errorf("invalid trash retention %q", cfg.Retention)
+	}
+	interval, err := time.ParseDuration(cfg.PurgeInterval)
+	if err != nil || interval <= 0 {
+		return trashusecase.Options{}, 0, fmt.Errorf("invalid trash purge interval %q", cfg.PurgeInterval)
+	}
+	return trashusecase.Options{Retention: retention}, interval, nil
+}
+
+// buildFormOptions parses the protection of form submissions and how often expired ones are purged.
+func buildFormOptions(cfg config.FormConfig) (formusecase.Options, time.Duration, error) {
+	maxPerIP, err := strconv.Atoi(cfg.MaxPerIP)
+	if err != nil || maxPerIP < 0 {
+		return formusecase.Options{}, 0, fmt.Errorf("invalid form max per IP %q", cfg.MaxPerIP)
+	}
+	window, err := time.ParseDuration(cfg.RateWindow)
+	if err != nil {
+		return formusecase.Options{}, 0, fmt.Errorf("cannot parse form rate window: %w", err)
+	}
+	interval, err := time.ParseDuration(cfg.PurgeInterval)
+	if err != nil || interval <= 0 {
+		return formusecase.Options{}, 0, fmt.Errorf("invalid form purge interval %q", cfg.PurgeInterval)
+	}
+	return formusecase.Options{
+		HoneypotField: strings.TrimSpace(cfg.HoneypotField),
+		MaxPerIP:      maxPerIP,
+		RateWindow:    window,
+	}, interval, nil
+}
+
+// buildBulkOptions parses the limits of bulk operations.
+func buildBulkOptions(cfg config.BulkConfig) (bulkusecase.Options, error) {
+	syncLimit, err := strconv.Atoi(cfg.SyncLimit)
+	if err != nil || syncLimit <= 0 {
+		return bulkusecase.Options{}, fmt.Errorf("invalid bulk sync limit %q", cfg.SyncLimit)
+	}
+	maxItems, err := strconv.Atoi(cfg.MaxItems)
+	if err != nil || maxItems <= 0 {
+		return bulkusecase.Options{}, fmt.Errorf("invalid bulk max items %q", cfg.MaxItems)
+	}
+	return bulkusecase.Options{SyncLimit: syncLimit, MaxItems: maxItems}, nil
+}
+
+// buildThemeHandler wires the server-side rendering of the public site and loads the active theme.
+// Themes are parsed again on every render in debug mode so template edits show up right away.
+func buildThemeHandler(
+	ctx context.Context,
+	cfg config.AppConfig,
+	db *sql.DB,
+	contents contentusecase.UseCase,
+	categoryRepo domaincategory.Repository,
+	tagRepo domaintag.Repository,
+	siteURL, title string,
+) (*handler.ThemeHandler, error) {
+	if !strings.HasPrefix(cfg.TagURLPattern, "/") || !strings.Contains(cfg.TagURLPattern, "{slug}") {
+		return nil, fmt.Errorf("invalid tag URL pattern %q: must start with / and contain {slug}", cfg.TagURLPattern)
+	}
+	loader := gotemplate.NewLoader(cfg.Theme.Dir, gotemplate.Options{
+		SiteURL:            siteURL,
+		MediaBaseURL:       cfg.Media.BaseURL,
+		CategoryURLPattern: cfg.CategoryURLPattern,
+		TagURLPattern:      cfg.TagURLPattern,
+	}, cfg.GinMode == gin.DebugMode)
+
+	themeUseCase := themeusecase.NewThemeUseCase(contents, categoryRepo, tagRepo, sqlsetting.NewSettingRepository(db), loader, themeusecase.Options{
+		SiteURL:      siteURL,
+		Title:        title,
+		DefaultTheme: cfg.Theme.Default,
+	})
+	if err := themeUseCase.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return handler.NewThemeHandler(themeUseCase, contents.Locales(), cfg.CategoryURLPattern, cfg.TagURLPattern), nil
+}
+
+// blockOptions returns the options rendering content blocks, which link media under the media base URL.
+func blockOptions(cfg config.AppConfig) block.Options {
+	return block.Options{
+		MediaURL: func(id, size string) string {
+			if size != "" {
+				return cfg.Media.BaseURL + "/" + url.PathEscape(id) + "/sizes/" + url.PathEscape(size)
+			}
+			return cfg.Media.BaseURL + "/" + url.PathEscape(id)
+		},
+	}
+}
+
+// buildSnippetUseCase wires the snippet use case. Its block registry leaves out the snippet block
+// since snippets cannot nest.
+func buildSnippetUseCase(cfg config.AppConfig, db *sql.DB) snippetusecase.UseCase {
+	return snippetusecase.NewSnippetUseCase(sqlsnippet.NewSnippetRepository(db), block.NewDefaultRegistry(blockOptions(cfg)))
+}
+
+// buildContentUseCase wires the content use case shared by the HTTP application and the command
+// line tools, rebuilding the in-memory search index when that engine is configured. snippets
+// expands snippet references in public reads and may be nil for tools working on stored bodies.
+func buildContentUseCase(
+	ctx context.Context,
+	cfg config.AppConfig,
+	db *sql.DB,
+	contentRepo domaincontent.Repository,
+	categoryRepo domaincategory.Repository,
+	tagRepo domaintag.Repository,
+	permalinks permalink.Patterns,
+	snippets domaincontent.Snippets,
+) (contentusecase.UseCase, error) {
+	locales, err := locale.Parse(cfg.Locales, cfg.LocaleFallbacks)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse locales: %w", err)
+	}
+
+	redirectRepo := sqlcontent.NewRedirectRepository(db)
+	blocks := block.NewDefaultRegistry(blockOptions(cfg))
+	blocks.MustRegister(domainsnippet.BlockDefinition())
+	var search domaincontent.Search
+	switch cfg.SearchEngine {
+	case "mysql":
+		search = sqlcontent.NewSearchRepository(db)
+	case "memory":
+		search = memory.NewIndex()
+	default:
+		return nil, fmt.Errorf("unknown search engine %q", cfg.SearchEngine)
+	}
+	contentUseCase := contentusecase.NewContentUseCase(contentRepo, categoryRepo, tagRepo, redirectRepo, permalinks, blocks, locales, search, snippets)
+	if cfg.SearchEngine == "memory" {
+		if _, err := contentUseCase.Reindex(ctx); err != nil {
+			return nil, fmt.Errorf("cannot build search index: %w", err)
+		}
+	}
+	return contentUseCase, nil
+}
+
 func buildPersonRepository(dbConn *database.Connection) (domainperson.Repository, error) {
 	if dbConn == nil || dbConn.DB == nil {
 		return nil, errors.New("database connection is required for person repository")
@@ -36,6 +36,51 @@ func TestBuildPersonRepository(t *testing.T) {
 	}
 }
 
+func TestBuildTrustedProxies(t *testing.T) {
+	proxies, err := buildTrustedProxies(" 10.0.0.0/8, 192.0.2.1 ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(proxies) != 2 || proxies[0] != "10.0.0.0/8" || proxies[1] != "192.0.2.1" {
+		t.Fatalf("unexpected proxies %v", proxies)
+	}
+	if proxies, _ := buildTrustedProxies(""); proxies != nil {
+		t.Fatalf("expected no proxies, got %v", proxies)
+	}
+	if _, err := buildTrustedProxies("proxy.local"); err == nil {
+		t.Fatalf("expected error for an invalid proxy")
+	}
+}
+
+func TestBuildMediaOptions_SigningKey(t *testing.T) {
+	cfg := config.AppConfig{
+		TokenSymmetricKey: "12345678901234567890123456789012",
+		Media:             config.MediaConfig{MaxUploadSize: "1024"},
+	}
+
+	opts, err := buildMediaOptions(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Signer != nil {
+		t.Fatalf("expected transform signing to be disabled without a media signing key")
+	}
+
+	cfg.Media.SigningKey = cfg.TokenSymmetricKey
+	if _, err := buildMediaOptions(cfg); err == nil {
+		t.Fatalf("expected error when the media signing key is the token key")
+	}
+
+	cfg.Media.SigningKey = "abcdefghijklmnopqrstuvwxyz012345"
+	opts, err = buildMediaOptions(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Signer == nil {
+		t.Fatalf("expected a transform URL signer")
+	}
+}
+
 func TestApplicationNew_WithMissingDBConfig(t *testing.T) {
 	_, err := New(context.Background(), config.AppConfig{})
 	if err == nil {
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/mashurimansur/goCMS/internal/adapter/storage/local"
+	"github.com/mashurimansur/goCMS/internal/adapter/theme/gotemplate"
+	sqlcategory "github.com/mashurimansur/goCMS/internal/repository/category"
+	sqlcontent "github.com/mashurimansur/goCMS/internal/repository/content"
+	sqlmedia "github.com/mashurimansur/goCMS/internal/repository/media"
+	sqlseo "github.com/mashurimansur/goCMS/internal/repository/seo"
+	sqltag "github.com/mashurimansur/goCMS/internal/repository/tag"
+	sqluser "github.com/mashurimansur/goCMS/internal/repository/user"
+	feedusecase "github.com/mashurimansur/goCMS/internal/usecase/feed"
+	mediausecase "github.com/mashurimansur/goCMS/internal/usecase/media"
+	seousecase "github.com/mashurimansur/goCMS/internal/usecase/seo"
+	staticusecase "github.com/mashurimansur/goCMS/internal/usecase/static"
+	"github.com/mashurimansur/goCMS/internal/utils/config"
+	"github.com/mashurimansur/goCMS/internal/utils/database"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+)
+
+// Static wires the static site export for the static command, without the HTTP layer.
+type Static struct {
+	staticusecase.UseCase
+	dbConn *database.Connection
+}
+
+// NewStatic connects to the database and builds the static export writing to outputDir with the
+// theme in themeDir, or the built-in theme when themeDir is empty.
+func NewStatic(ctx context.Context, cfg config.AppConfig, outputDir, themeDir string) (*Static, error) {
+	dbConn, err := database.NewConnection(ctx, cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	uc, err := buildStaticUseCase(ctx, cfg, dbConn, outputDir, themeDir)
+	if err != nil {
+		dbConn.Close()
+		return nil, err
+	}
+	return &Static{UseCase: uc, dbConn: dbConn}, nil
+}
+
+// Close releases the database connection.
+func (s *Static) Close() error {
+	if s == nil || s.dbConn == nil {
+		return nil
+	}
+	return s.dbConn.Close()
+}
+
+func buildStaticUseCase(ctx context.Context, cfg config.AppConfig, dbConn *database.Connection, outputDir, themeDir string) (staticusecase.UseCase, error) {
+	permalinks, err := permalink.Parse(cfg.PermalinkPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse permalink patterns: %w", err)
+	}
+	contentRepo := sqlcontent.NewContentRepository(dbConn.DB)
+	categoryRepo := sqlcategory.NewCategoryRepository(dbConn.DB)
+	tagRepo := sqltag.NewTagRepository(dbConn.DB)
+	snippetUseCase := buildSnippetUseCase(cfg, dbConn.DB)
+	contentUseCase, err := buildContentUseCase(ctx, cfg, dbConn.DB, contentRepo, categoryRepo, tagRepo, permalinks, snippetUseCase)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaOptions, err := buildMediaOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mediaRepo := sqlmedia.NewMediaRepository(dbConn.DB)
+	mediaStorage, err := local.NewStorage(cfg.Media.StoragePath)
+	if err != nil {
+		return nil, err
+	}
+	mediaUseCase := mediausecase.NewMediaUseCase(mediaRepo, mediaStorage, mediaOptions)
+
+	seoOptions, err := buildSEOOptions(cfg.SEO)
+	if err != nil {
+		return nil, err
+	}
+	feedOptions, err := buildFeedOptions(cfg.Feed, seoOptions)
+	if err != nil {
+		return nil, err
+	}
+	feedOptions.Locale = contentUseCase.Locales().Default()
+
+	var themeFS fs.FS = gotemplate.Default()
+	if themeDir != "" {
+		themeFS = os.DirFS(themeDir)
+	}
+	renderer, err := gotemplate.New(themeFS, gotemplate.Options{
+		SiteURL:            seoOptions.SiteURL,
+		MediaBaseURL:       cfg.Media.BaseURL,
+		CategoryURLPattern: cfg.CategoryURLPattern,
+		TagURLPattern:      cfg.TagURLPattern,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output, err := local.NewStorage(outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return staticusecase.NewStaticUseCase(
+		contentUseCase, mediaRepo, mediaStorage,
+		seousecase.NewSEOUseCase(sqlseo.NewSEORepository(dbConn.DB), contentRepo, mediaUseCase, permalinks, seoOptions),
+		feedusecase.NewFeedUseCase(contentRepo, categoryRepo, tagRepo, sqluser.NewUserRepository(dbConn.DB), permalinks, snippetUseCase, feedOptions),
+		renderer, output,
+		staticusecase.Options{
+			SiteURL:      seoOptions.SiteURL,
+			Title:        feedOptions.Title,
+			MediaBaseURL: cfg.Media.BaseURL,
+		},
+	), nil
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	sqlcategory "github.com/mashurimansur/goCMS/internal/repository/category"
+	sqlcontent "github.com/mashurimansur/goCMS/internal/repository/content"
+	sqlmedia "github.com/mashurimansur/goCMS/internal/repository/media"
+	sqltag "github.com/mashurimansur/goCMS/internal/repository/tag"
+	sqluser "github.com/mashurimansur/goCMS/internal/repository/user"
+	transferusecase "github.com/mashurimansur/goCMS/internal/usecase/transfer"
+	"github.com/mashurimansur/goCMS/internal/utils/config"
+	"github.com/mashurimansur/goCMS/internal/utils/database"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+)
+
+// Transfer wires the import and export use case for the transfer command, without the HTTP layer.
+type Transfer struct {
+	transferusecase.UseCase
+	dbConn *database.Connection
+}
+
+// NewTransfer connects to the database and builds the transfer use case.
+func NewTransfer(ctx context.Context, cfg config.AppConfig) (*Transfer, error) {
+	dbConn, err := database.NewConnection(ctx, cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	permalinks, err := permalink.Parse(cfg.PermalinkPatterns)
+	if err != nil {
+		dbConn.Close()
+		return nil, fmt.Errorf("cannot parse permalink patterns: %w", err)
+	}
+
+	contentRepo := sqlcontent.NewContentRepository(dbConn.DB)
+	categoryRepo := sqlcategory.NewCategoryRepository(dbConn.DB)
+	tagRepo := sqltag.NewTagRepository(dbConn.DB)
+	contentUseCase, err := buildContentUseCase(ctx, cfg, dbConn.DB, contentRepo, categoryRepo, tagRepo, permalinks, nil)
+	if err != nil {
+		dbConn.Close()
+		return nil, err
+	}
+
+	return &Transfer{
+		UseCase: transferusecase.NewTransferUseCase(
+			contentUseCase, contentRepo, categoryRepo, tagRepo,
+			sqlmedia.NewMediaRepository(dbConn.DB), sqluser.NewUserRepository(dbConn.DB),
+		),
+		dbConn: dbConn,
+	}, nil
+}
+
+// Close releases the database connection.
+func (t *Transfer) Close() error {
+	if t == nil || t.dbConn == nil {
+		return nil
+	}
+	return t.dbConn.Close()
+}
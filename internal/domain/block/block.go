@@ -0,0 +1,243 @@
+// Package block models structured content bodies as an ordered list of typed blocks. Every block type
+// is described by a Definition that carries a JSON Schema for its data and renderers for HTML and plain
+// text; new types are added by registering further definitions with a Registry.
+package block
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+var (
+	// ErrUnknownType is returned for a block whose type has not been registered.
+	ErrUnknownType = errors.New("unknown block type")
+	// ErrInvalidData is returned for block data that does not satisfy the schema of its type.
+	ErrInvalidData = errors.New("invalid block data")
+	// ErrDuplicateType is returned when registering a type name twice.
+	ErrDuplicateType = errors.New("block type already registered")
+)
+
+// Block is a single typed unit of a structured body. Data holds the type-specific fields.
+type Block struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Definition describes a block type. Schema is a JSON Schema document validated against the block
+// data before it is rendered. HTML must escape everything it emits; Text returns the readable words of
+// the block for excerpts, feeds and search.
+type Definition struct {
+	Type   string
+	Schema string
+	HTML   func(data json.RawMessage) (string, error)
+	Text   func(data json.RawMessage) (string, error)
+}
+
+// Typed builds a Definition whose renderers receive the block data decoded into T.
+func Typed[T any](typ, schema string, html func(T) (string, error), text func(T) string) Definition {
+	decode := func(data json.RawMessage) (T, error) {
+		var v T
+		err := json.Unmarshal(data, &v)
+		return v, err
+	}
+	return Definition{
+		Type:   typ,
+		Schema: schema,
+		HTML: func(data json.RawMessage) (string, error) {
+			v, err := decode(data)
+			if err != nil {
+				return "", err
+			}
+			return html(v)
+		},
+		Text: func(data json.RawMessage) (string, error) {
+			v, err := decode(data)
+			if err != nil {
+				return "", err
+			}
+			return text(v), nil
+		},
+	}
+}
+
+// data returns the block data, treating a missing value as an empty object.
+func (b Block) data() json.RawMessage {
+	if len(b.Data) == 0 {
+		return json.RawMessage("{}")
+	}
+	return b.Data
+}
+
+// TypeInfo exposes a registered type and its schema, e.g. so editors can build their forms.
+type TypeInfo struct {
+	Type   string          `json:"type"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+// Error reports which block of a body failed validation or rendering.
+type Error struct {
+	Index int
+	Type  string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("block %d (%s): %v", e.Index, e.Type, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+type entry struct {
+	def    Definition
+	schema *jsonschema.Schema
+}
+
+// Registry holds the known block types. It is safe for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	types map[string]*entry
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[string]*entry)}
+}
+
+// Register compiles the schema of def and adds it to the registry.
+func (r *Registry) Register(def Definition) error {
+	if def.Type == "" || def.HTML == nil || def.Text == nil {
+		return fmt.Errorf("block type %q: type name and renderers are required", def.Type)
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(def.Schema))
+	if err != nil {
+		return fmt.Errorf("block type %q: cannot parse schema: %w", def.Type, err)
+	}
+	location := "block/" + def.Type + ".json"
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	if err := compiler.AddResource(location, doc); err != nil {
+		return fmt.Errorf("block type %q: %w", def.Type, err)
+	}
+	schema, err := compiler.Compile(location)
+	if err != nil {
+		return fmt.Errorf("block type %q: cannot compile schema: %w", def.Type, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.types[def.Type]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateType, def.Type)
+	}
+	r.types[def.Type] = &entry{def: def, schema: schema}
+	return nil
+}
+
+// MustRegister is like Register but panics on error. It is meant for built-in types.
+func (r *Registry) MustRegister(def Definition) {
+	if err := r.Register(def); err != nil {
+		panic(err)
+	}
+}
+
+// Types lists the registered types sorted by name.
+func (r *Registry) Types() []TypeInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]TypeInfo, 0, len(r.types))
+	for name, e := range r.types {
+		infos = append(infos, TypeInfo{Type: name, Schema: json.RawMessage(e.def.Schema)})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Type < infos[j].Type })
+	return infos
+}
+
+// Validate checks every block against the schema of its type.
+func (r *Registry) Validate(blocks []Block) error {
+	for i, b := range blocks {
+		if _, err := r.validate(b); err != nil {
+			return &Error{Index: i, Type: b.Type, Err: err}
+		}
+	}
+	return nil
+}
+
+// RenderHTML validates blocks and renders them to HTML, one element per block.
+func (r *Registry) RenderHTML(blocks []Block) (string, error) {
+	return r.render(blocks, "\n", func(d Definition) func(json.RawMessage) (string, error) { return d.HTML })
+}
+
+// RenderText validates blocks and renders their plain text, separating blocks with blank lines.
+func (r *Registry) RenderText(blocks []Block) (string, error) {
+	return r.render(blocks, "\n\n", func(d Definition) func(json.RawMessage) (string, error) { return d.Text })
+}
+
+func (r *Registry) render(blocks []Block, sep string, renderer func(Definition) func(json.RawMessage) (string, error)) (string, error) {
+	parts := make([]string, 0, len(blocks))
+	for i, b := range blocks {
+		def, err := r.validate(b)
+		if err == nil {
+			var out string
+			out, err = renderer(def)(b.data())
+			if out != "" {
+				parts = append(parts, out)
+			}
+		}
+		if err != nil {
+			return "", &Error{Index: i, Type: b.Type, Err: err}
+		}
+	}
+	return strings.Join(parts, sep), nil
+}
+
+func (r *Registry) validate(b Block) (Definition, error) {
+	r.mu.RLock()
+	e, ok := r.types[b.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return Definition{}, ErrUnknownType
+	}
+
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(b.data()))
+	if err != nil {
+		return Definition{}, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	if err := e.schema.Validate(instance); err != nil {
+		return Definition{}, fmt.Errorf("%w: %s", ErrInvalidData, describe(err))
+	}
+	return e.def, nil
+}
+
+// describe condenses a schema validation error into a single line naming the offending fields.
+func describe(err error) string {
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return err.Error()
+	}
+
+	var messages []string
+	for _, unit := range ve.BasicOutput().Errors {
+		if unit.Error == nil || len(unit.Errors) > 0 {
+			continue
+		}
+		location := unit.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		messages = append(messages, location+": "+unit.Error.String())
+	}
+	if len(messages) == 0 {
+		return err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,97 @@
+package block
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func blk(typ, data string) Block {
+	return Block{Type: typ, Data: json.RawMessage(data)}
+}
+
+func TestRegistry_RenderHTML(t *testing.T) {
+	r := NewDefaultRegistry(Options{})
+
+	out, err := r.RenderHTML([]Block{
+		blk(TypeHeading, `{"text":"Hello <World>","level":1,"anchor":"intro"}`),
+		blk(TypeParagraph, `{"text":"Some **bold** text <script>alert(1)</script>"}`),
+		blk(TypeImage, `{"media_id":"m-1","size":"large","alt":"A \"cat\"","caption":"Cat"}`),
+		blk(TypeQuote, `{"text":"Stay hungry","citation":"Someone"}`),
+		blk(TypeCode, `{"code":"a < b","language":"go"}`),
+		blk(TypeCallToAction, `{"text":"Sign up","url":"/signup"}`),
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, `<h1 id="intro">Hello &lt;World&gt;</h1>`)
+	assert.Contains(t, out, `<p>Some <strong>bold</strong> text alert(1)</p>`)
+	assert.NotContains(t, out, "<script>")
+	assert.Contains(t, out, `<img src="/media/m-1/sizes/large" alt="A &#34;cat&#34;" loading="lazy"><figcaption>Cat</figcaption>`)
+	assert.Contains(t, out, `<blockquote><p>Stay hungry</p><cite>Someone</cite></blockquote>`)
+	assert.Contains(t, out, `<pre><code class="language-go">a &lt; b</code></pre>`)
+	assert.Contains(t, out, `<a class="button button-primary" href="/signup">Sign up</a>`)
+}
+
+func TestRegistry_RenderText(t *testing.T) {
+	r := NewDefaultRegistry(Options{})
+
+	out, err := r.RenderText([]Block{
+		blk(TypeHeading, `{"text":"Title"}`),
+		blk(TypeQuote, `{"text":"Quoted","citation":"Author"}`),
+		blk(TypeImage, `{"media_id":"m-1"}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Title\n\nQuoted — Author", out)
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	r := NewDefaultRegistry(Options{})
+
+	err := r.Validate([]Block{
+		blk(TypeParagraph, `{"text":"ok"}`),
+		blk(TypeHeading, `{"text":"Too deep","level":7}`),
+	})
+	var blockErr *Error
+	require.ErrorAs(t, err, &blockErr)
+	assert.Equal(t, 1, blockErr.Index)
+	assert.ErrorIs(t, err, ErrInvalidData)
+	assert.Contains(t, err.Error(), "/level")
+
+	assert.ErrorIs(t, r.Validate([]Block{blk("carousel", `{}`)}), ErrUnknownType)
+	assert.ErrorIs(t, r.Validate([]Block{blk(TypeCallToAction, `{"text":"Go","url":"javascript:alert(1)"}`)}), ErrInvalidData)
+	assert.ErrorIs(t, r.Validate([]Block{blk(TypeParagraph, `{"text":"x","extra":true}`)}), ErrInvalidData)
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+	def := Typed("divider", `{"type":"object"}`,
+		func(struct{}) (string, error) { return "<hr>", nil },
+		func(struct{}) string { return "" })
+
+	require.NoError(t, r.Register(def))
+	assert.ErrorIs(t, r.Register(def), ErrDuplicateType)
+	assert.Error(t, r.Register(Definition{Type: "broken", Schema: `{"type":`, HTML: def.HTML, Text: def.Text}))
+
+	out, err := r.RenderHTML([]Block{{Type: "divider"}})
+	require.NoError(t, err)
+	assert.Equal(t, "<hr>", out)
+	assert.Equal(t, []TypeInfo{{Type: "divider", Schema: json.RawMessage(`{"type":"object"}`)}}, r.Types())
+}
+
+func TestPlayerURL(t *testing.T) {
+	assert.Equal(t, "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", playerURL("https://www.youtube.com/watch?v=dQw4w9WgXcQ"))
+	assert.Equal(t, "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", playerURL("https://youtu.be/dQw4w9WgXcQ"))
+	assert.Equal(t, "https://player.vimeo.com/video/76979871?dnt=1", playerURL("https://vimeo.com/76979871"))
+	assert.Empty(t, playerURL("https://example.com/video"))
+	assert.Empty(t, playerURL(`https://youtube.com/watch?v="><script>`))
+}
+
+func TestRegistry_RenderHTML_EmbedFallsBackToLink(t *testing.T) {
+	r := NewDefaultRegistry(Options{})
+
+	out, err := r.RenderHTML([]Block{blk(TypeEmbed, `{"url":"https://example.com/a?b=1&c=2"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, `<figure class="block-embed"><a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener">https://example.com/a?b=1&amp;c=2</a></figure>`, out)
+}
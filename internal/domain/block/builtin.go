@@ -0,0 +1,294 @@
+package block
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mashurimansur/goCMS/internal/utils/markdown"
+)
+
+// Built-in block types.
+const (
+	TypeParagraph    = "paragraph"
+	TypeHeading      = "heading"
+	TypeImage        = "image"
+	TypeQuote        = "quote"
+	TypeEmbed        = "embed"
+	TypeCode         = "code"
+	TypeCallToAction = "cta"
+)
+
+// Options configures the built-in block types.
+type Options struct {
+	// MediaURL returns the public URL of a media item, or of its named size when size is not empty.
+	MediaURL func(id, size string) string
+}
+
+// NewDefaultRegistry returns a registry holding the built-in block types.
+func NewDefaultRegistry(opts Options) *Registry {
+	mediaURL := opts.MediaURL
+	if mediaURL == nil {
+		mediaURL = func(id, size string) string {
+			if size != "" {
+				return "/media/" + url.PathEscape(id) + "/sizes/" + url.PathEscape(size)
+			}
+			return "/media/" + url.PathEscape(id)
+		}
+	}
+
+	r := NewRegistry()
+	r.MustRegister(paragraphBlock())
+	r.MustRegister(headingBlock())
+	r.MustRegister(imageBlock(mediaURL))
+	r.MustRegister(quoteBlock())
+	r.MustRegister(embedBlock())
+	r.MustRegister(codeBlock())
+	r.MustRegister(callToActionBlock())
+	return r
+}
+
+type paragraph struct {
+	Text string `json:"text"`
+}
+
+// paragraphBlock holds Markdown text, rendered and sanitized like a Markdown body.
+func paragraphBlock() Definition {
+	return Typed(TypeParagraph, `{
+		"type": "object",
+		"properties": {"text": {"type": "string", "minLength": 1}},
+		"required": ["text"],
+		"additionalProperties": false
+	}`, func(p paragraph) (string, error) {
+		out, err := markdown.Render(p.Text, markdown.FormatMarkdown)
+		return strings.TrimSpace(out), err
+	}, func(p paragraph) string {
+		return p.Text
+	})
+}
+
+type heading struct {
+	Text   string `json:"text"`
+	Level  int    `json:"level"`
+	Anchor string `json:"anchor"`
+}
+
+func headingBlock() Definition {
+	return Typed(TypeHeading, `{
+		"type": "object",
+		"properties": {
+			"text": {"type": "string", "minLength": 1},
+			"level": {"type": "integer", "minimum": 1, "maximum": 6},
+			"anchor": {"type": "string", "pattern": "^[A-Za-z][A-Za-z0-9_-]*$"}
+		},
+		"required": ["text"],
+		"additionalProperties": false
+	}`, func(h heading) (string, error) {
+		level := h.Level
+		if level == 0 {
+			level = 2
+		}
+		id := ""
+		if h.Anchor != "" {
+			id = fmt.Sprintf(` id="%s"`, html.EscapeString(h.Anchor))
+		}
+		return fmt.Sprintf("<h%d%s>%s</h%d>", level, id, html.EscapeString(h.Text), level), nil
+	}, func(h heading) string {
+		return h.Text
+	})
+}
+
+type image struct {
+	MediaID string `json:"media_id"`
+	Size    string `json:"size"`
+	Alt     string `json:"alt"`
+	Caption string `json:"caption"`
+}
+
+// imageBlock references an item of the media library, optionally one of its configured sizes.
+func imageBlock(mediaURL func(id, size string) string) Definition {
+	return Typed(TypeImage, `{
+		"type": "object",
+		"properties": {
+			"media_id": {"type": "string", "minLength": 1},
+			"size": {"type": "string"},
+			"alt": {"type": "string"},
+			"caption": {"type": "string"}
+		},
+		"required": ["media_id"],
+		"additionalProperties": false
+	}`, func(i image) (string, error) {
+		var b strings.Builder
+		fmt.Fprintf(&b, `<figure class="block-image"><img src="%s" alt="%s" loading="lazy">`,
+			html.EscapeString(mediaURL(i.MediaID, i.Size)), html.EscapeString(i.Alt))
+		if i.Caption != "" {
+			fmt.Fprintf(&b, "<figcaption>%s</figcaption>", html.EscapeString(i.Caption))
+		}
+		b.WriteString("</figure>")
+		return b.String(), nil
+	}, func(i image) string {
+		if i.Caption != "" {
+			return i.Caption
+		}
+		return i.Alt
+	})
+}
+
+type quote struct {
+	Text     string `json:"text"`
+	Citation string `json:"citation"`
+}
+
+func quoteBlock() Definition {
+	return Typed(TypeQuote, `{
+		"type": "object",
+		"properties": {
+			"text": {"type": "string", "minLength": 1},
+			"citation": {"type": "string"}
+		},
+		"required": ["text"],
+		"additionalProperties": false
+	}`, func(q quote) (string, error) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "<blockquote><p>%s</p>", html.EscapeString(q.Text))
+		if q.Citation != "" {
+			fmt.Fprintf(&b, "<cite>%s</cite>", html.EscapeString(q.Citation))
+		}
+		b.WriteString("</blockquote>")
+		return b.String(), nil
+	}, func(q quote) string {
+		if q.Citation != "" {
+			return q.Text + " — " + q.Citation
+		}
+		return q.Text
+	})
+}
+
+type embed struct {
+	URL     string `json:"url"`
+	Caption string `json:"caption"`
+}
+
+var (
+	youTubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{6,20}$`)
+	vimeoID   = regexp.MustCompile(`^[0-9]{1,12}$`)
+)
+
+// embedBlock turns links to known video providers into privacy-friendly players. Any other URL is
+// rendered as a plain link, so editors cannot inject arbitrary frames.
+func embedBlock() Definition {
+	return Typed(TypeEmbed, `{
+		"type": "object",
+		"properties": {
+			"url": {"type": "string", "format": "uri", "pattern": "^https?://"},
+			"caption": {"type": "string"}
+		},
+		"required": ["url"],
+		"additionalProperties": false
+	}`, func(e embed) (string, error) {
+		var b strings.Builder
+		b.WriteString(`<figure class="block-embed">`)
+		if player := playerURL(e.URL); player != "" {
+			fmt.Fprintf(&b, `<iframe src="%s" title="%s" loading="lazy" allowfullscreen></iframe>`,
+				html.EscapeString(player), html.EscapeString(e.Caption))
+		} else {
+			fmt.Fprintf(&b, `<a href="%s" rel="nofollow noopener">%s</a>`, html.EscapeString(e.URL), html.EscapeString(e.URL))
+		}
+		if e.Caption != "" {
+			fmt.Fprintf(&b, "<figcaption>%s</figcaption>", html.EscapeString(e.Caption))
+		}
+		b.WriteString("</figure>")
+		return b.String(), nil
+	}, func(e embed) string {
+		if e.Caption != "" {
+			return e.Caption + " (" + e.URL + ")"
+		}
+		return e.URL
+	})
+}
+
+// playerURL returns the embeddable player for a YouTube or Vimeo link, or "" for other URLs.
+func playerURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var id string
+	switch host {
+	case "youtube.com", "m.youtube.com":
+		id = u.Query().Get("v")
+		if len(segments) == 2 && (segments[0] == "embed" || segments[0] == "shorts") {
+			id = segments[1]
+		}
+		if youTubeID.MatchString(id) {
+			return "https://www.youtube-nocookie.com/embed/" + id
+		}
+	case "youtu.be":
+		if id = segments[0]; youTubeID.MatchString(id) {
+			return "https://www.youtube-nocookie.com/embed/" + id
+		}
+	case "vimeo.com":
+		if id = segments[len(segments)-1]; vimeoID.MatchString(id) {
+			return "https://player.vimeo.com/video/" + id + "?dnt=1"
+		}
+	}
+	return ""
+}
+
+type code struct {
+	Code     string `json:"code"`
+	Language string `json:"language"`
+}
+
+func codeBlock() Definition {
+	return Typed(TypeCode, `{
+		"type": "object",
+		"properties": {
+			"code": {"type": "string", "minLength": 1},
+			"language": {"type": "string", "pattern": "^[A-Za-z0-9_+-]*$"}
+		},
+		"required": ["code"],
+		"additionalProperties": false
+	}`, func(c code) (string, error) {
+		class := ""
+		if c.Language != "" {
+			class = fmt.Sprintf(` class="language-%s"`, c.Language)
+		}
+		return fmt.Sprintf("<pre><code%s>%s</code></pre>", class, html.EscapeString(c.Code)), nil
+	}, func(c code) string {
+		return c.Code
+	})
+}
+
+type callToAction struct {
+	Text  string `json:"text"`
+	URL   string `json:"url"`
+	Style string `json:"style"`
+}
+
+func callToActionBlock() Definition {
+	return Typed(TypeCallToAction, `{
+		"type": "object",
+		"properties": {
+			"text": {"type": "string", "minLength": 1},
+			"url": {"type": "string", "pattern": "^(https?://|/|#)"},
+			"style": {"enum": ["primary", "secondary"]}
+		},
+		"required": ["text", "url"],
+		"additionalProperties": false
+	}`, func(a callToAction) (string, error) {
+		style := a.Style
+		if style == "" {
+			style = "primary"
+		}
+		return fmt.Sprintf(`<p class="block-cta"><a class="button button-%s" href="%s">%s</a></p>`,
+			style, html.EscapeString(a.URL), html.EscapeString(a.Text)), nil
+	}, func(a callToAction) string {
+		return a.Text
+	})
+}
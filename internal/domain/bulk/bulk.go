@@ -0,0 +1,133 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a bulk job does not exist.
+var ErrNotFound = errors.New("bulk job not found")
+
+// Resources bulk operations run on.
+const (
+	ResourceContent = "content"
+	ResourceUser    = "user"
+)
+
+// Content actions.
+const (
+	ActionPublish    = "publish"
+	ActionUnpublish  = "unpublish"
+	ActionArchive    = "archive"
+	ActionAddTags    = "add_tags"
+	ActionRemoveTags = "remove_tags"
+	// ActionMove replaces the categories of entries.
+	ActionMove = "move"
+)
+
+// User actions.
+const (
+	ActionSetStatus = "set_status"
+	ActionSetRole   = "set_role"
+)
+
+// ActionDelete moves items of any resource to the trash.
+const ActionDelete = "delete"
+
+// Job statuses.
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Item result statuses. In a dry run they tell what would happen.
+const (
+	ResultSucceeded = "succeeded"
+	// ResultSkipped marks an item already in the state the action leads to.
+	ResultSkipped = "skipped"
+	ResultFailed  = "failed"
+)
+
+// Params carries the arguments of the actions that need one.
+type Params struct {
+	TagIDs      []string `json:"tag_ids,omitempty"`
+	CategoryIDs []string `json:"category_ids,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Role        string   `json:"role,omitempty"`
+}
+
+// Filter selects the items of an operation instead of a list of IDs. Type, locale, author, category
+// and tag apply to content; role applies to users; status applies to both.
+type Filter struct {
+	Type       string `json:"type,omitempty"`
+	Locale     string `json:"locale,omitempty"`
+	Status     string `json:"status,omitempty"`
+	AuthorID   string `json:"author_id,omitempty"`
+	CategoryID string `json:"category_id,omitempty"`
+	TagID      string `json:"tag_id,omitempty"`
+	Role       string `json:"role,omitempty"`
+}
+
+// Empty reports whether the filter selects every item.
+func (f Filter) Empty() bool {
+	return f == Filter{}
+}
+
+// Result is the outcome of an operation on one item.
+type Result struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Job is a bulk operation on many items. Items are processed in chunks and the job is saved after
+// every chunk, so its progress and per-item results can be followed while it runs.
+type Job struct {
+	ID       string `json:"id"`
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+	Params   Params `json:"params"`
+	// DryRun jobs report what the action would do without changing anything.
+	DryRun    bool     `json:"dry_run"`
+	Status    string   `json:"status"`
+	Total     int      `json:"total"`
+	Processed int      `json:"processed"`
+	Succeeded int      `json:"succeeded"`
+	Skipped   int      `json:"skipped"`
+	Failed    int      `json:"failed"`
+	Results   []Result `json:"results"`
+	// Error tells why a failed job stopped before processing every item.
+	Error      string    `json:"error,omitempty"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Record adds the result of an item to the job.
+func (j *Job) Record(r Result) {
+	j.Results = append(j.Results, r)
+	j.Processed++
+	switch r.Status {
+	case ResultSucceeded:
+		j.Succeeded++
+	case ResultSkipped:
+		j.Skipped++
+	default:
+		j.Failed++
+	}
+}
+
+// Repository abstracts the data source that stores bulk jobs.
+type Repository interface {
+	Create(ctx context.Context, j *Job) error
+	GetByID(ctx context.Context, id string) (*Job, error)
+	// Update saves the progress, status and results of a job.
+	Update(ctx context.Context, j *Job) error
+	// FailUnfinished marks every pending or running job as failed with the given error and returns
+	// how many it marked.
+	FailUnfinished(ctx context.Context, reason string, finishedAt time.Time) (int, error)
+}
@@ -0,0 +1,25 @@
+package bulk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJob_Record(t *testing.T) {
+	j := &Job{Total: 3}
+	j.Record(Result{ID: "1", Status: ResultSucceeded})
+	j.Record(Result{ID: "2", Status: ResultSkipped})
+	j.Record(Result{ID: "3", Status: ResultFailed, Error: "boom"})
+
+	assert.Equal(t, 3, j.Processed)
+	assert.Equal(t, 1, j.Succeeded)
+	assert.Equal(t, 1, j.Skipped)
+	assert.Equal(t, 1, j.Failed)
+	assert.Len(t, j.Results, 3)
+}
+
+func TestFilter_Empty(t *testing.T) {
+	assert.True(t, Filter{}.Empty())
+	assert.False(t, Filter{Status: "draft"}.Empty())
+}
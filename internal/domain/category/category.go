@@ -0,0 +1,85 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+)
+
+// ErrNotFound is returned when a category does not exist.
+var ErrNotFound = errors.New("category not found")
+
+// Category is a hierarchical classification for content.
+type Category struct {
+	ID          string      `json:"id"`
+	ParentID    string      `json:"parent_id"`
+	Name        string      `json:"name"`
+	Slug        string      `json:"slug"`
+	Description string      `json:"description"`
+	Position    int         `json:"position"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Version     int         `json:"version"`
+	Children    []*Category `json:"children,omitempty"`
+}
+
+// Repository abstracts the data source that stores categories and their content assignments.
+type Repository interface {
+	Create(ctx context.Context, c *Category) error
+	GetByID(ctx context.Context, id string) (*Category, error)
+	GetBySlug(ctx context.Context, slug string) (*Category, error)
+	// SlugInUse reports whether a category other than exceptID, trashed or not, holds the slug.
+	SlugInUse(ctx context.Context, slug, exceptID string) (bool, error)
+	Update(ctx context.Context, c *Category) error
+	// Delete moves a category to the trash.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*Category, error)
+	ListByContent(ctx context.Context, contentID string) ([]*Category, error)
+	SetContentCategories(ctx context.Context, contentID string, categoryIDs []string) error
+	trash.Bin
+}
+
+// BuildTree arranges a flat list of categories into a forest ordered by position.
+func BuildTree(categories []*Category) []*Category {
+	byID := make(map[string]*Category, len(categories))
+	for _, c := range categories {
+		c.Children = nil
+		byID[c.ID] = c
+	}
+
+	var roots []*Category
+	for _, c := range categories {
+		parent, ok := byID[c.ParentID]
+		if c.ParentID == "" || !ok {
+			roots = append(roots, c)
+			continue
+		}
+		parent.Children = append(parent.Children, c)
+	}
+
+	return roots
+}
+
+// DescendantIDs returns the ID of the given category followed by the IDs of all of its descendants.
+func DescendantIDs(categories []*Category, rootID string) []string {
+	childrenOf := make(map[string][]string, len(categories))
+	for _, c := range categories {
+		childrenOf[c.ParentID] = append(childrenOf[c.ParentID], c.ID)
+	}
+
+	ids := []string{rootID}
+	visited := map[string]bool{rootID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range childrenOf[ids[i]] {
+			if visited[child] {
+				continue
+			}
+			visited[child] = true
+			ids = append(ids, child)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,47 @@
+package category
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleCategories() []*Category {
+	return []*Category{
+		{ID: "news", Name: "News"},
+		{ID: "local", ParentID: "news", Name: "Local"},
+		{ID: "city", ParentID: "local", Name: "City"},
+		{ID: "sports", Name: "Sports"},
+		{ID: "orphan", ParentID: "missing", Name: "Orphan"},
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	roots := BuildTree(sampleCategories())
+
+	require.Len(t, roots, 3)
+	assert.Equal(t, "news", roots[0].ID)
+	require.Len(t, roots[0].Children, 1)
+	assert.Equal(t, "local", roots[0].Children[0].ID)
+	require.Len(t, roots[0].Children[0].Children, 1)
+	assert.Equal(t, "city", roots[0].Children[0].Children[0].ID)
+	assert.Equal(t, "sports", roots[1].ID)
+	assert.Equal(t, "orphan", roots[2].ID)
+}
+
+func TestDescendantIDs(t *testing.T) {
+	categories := sampleCategories()
+
+	assert.Equal(t, []string{"news", "local", "city"}, DescendantIDs(categories, "news"))
+	assert.Equal(t, []string{"sports"}, DescendantIDs(categories, "sports"))
+}
+
+func TestDescendantIDs_IgnoresCycles(t *testing.T) {
+	categories := []*Category{
+		{ID: "a", ParentID: "b"},
+		{ID: "b", ParentID: "a"},
+	}
+
+	assert.Equal(t, []string{"a", "b"}, DescendantIDs(categories, "a"))
+}
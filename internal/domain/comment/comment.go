@@ -0,0 +1,72 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+)
+
+// Moderation states of a comment.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+	StatusSpam     = "spam"
+)
+
+// ErrNotFound is returned when a comment does not exist.
+var ErrNotFound = errors.New("comment not found")
+
+// Comment is a reader's response to a content entry, optionally replying to another comment.
+// Authenticated authors carry their AuthorID; anonymous ones only a name and an email address.
+type Comment struct {
+	ID          string    `json:"id"`
+	ContentID   string    `json:"content_id"`
+	ParentID    string    `json:"parent_id,omitempty"`
+	AuthorID    string    `json:"author_id,omitempty"`
+	AuthorName  string    `json:"author_name"`
+	AuthorEmail string    `json:"author_email,omitempty"`
+	Body        string    `json:"body"`
+	Status      string    `json:"status"`
+	SpamScore   int       `json:"spam_score"`
+	IP          string    `json:"ip,omitempty"`
+	UserAgent   string    `json:"user_agent,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Replies holds approved child comments when the comment is returned as part of a thread.
+	Replies []*Comment `json:"replies,omitempty"`
+}
+
+// Redact clears the fields only moderators may see.
+func (c *Comment) Redact() {
+	c.AuthorEmail = ""
+	c.IP = ""
+	c.UserAgent = ""
+	c.SpamScore = 0
+}
+
+// ListFilter narrows down the comments returned by Repository.List.
+type ListFilter struct {
+	ContentID string
+	Status    string
+	Limit     int
+	Offset    int
+}
+
+// Repository abstracts the data source that stores comments.
+type Repository interface {
+	Create(ctx context.Context, c *Comment) error
+	GetByID(ctx context.Context, id string) (*Comment, error)
+	// List returns matching comments, oldest first when filtered by content and newest first otherwise.
+	List(ctx context.Context, filter ListFilter) ([]*Comment, error)
+	// SetStatus moves the given comments to status and reports how many were changed.
+	SetStatus(ctx context.Context, ids []string, status string) (int64, error)
+	// Delete moves the given comments and their replies to the trash, reporting how many of the given
+	// comments were trashed.
+	Delete(ctx context.Context, ids []string) (int64, error)
+	// CountRecentByIP counts the comments submitted from ip since the given time, trashed ones included.
+	CountRecentByIP(ctx context.Context, ip string, since time.Time) (int, error)
+	trash.Bin
+}
@@ -0,0 +1,356 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Content statuses.
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+// Visibilities decide who may read a published entry on the public site.
+const (
+	VisibilityPublic        = "public"
+	VisibilityAuthenticated = "authenticated"
+	VisibilityRoles         = "roles"
+	VisibilityPassword      = "password"
+)
+
+// FormatBlocks marks an entry whose body is composed of structured blocks instead of free text.
+const FormatBlocks = "blocks"
+
+var (
+	// ErrNotFound is returned when a content entry does not exist.
+	ErrNotFound = errors.New("content not found")
+	// ErrInvalidVisibility is returned when the visibility of an entry is unknown or incomplete.
+	ErrInvalidVisibility = errors.New("invalid visibility")
+)
+
+// Content models a piece of publishable content such as a post or a page.
+// Body is written in BodyFormat (markdown or html); BodyHTML holds the sanitized HTML rendered
+// from it when the entry was last saved. With the blocks format the source is Blocks, and Body holds
+// their plain-text rendering.
+//
+// Entries written in several languages form a translation group: a source entry and the
+// translations whose TranslationOf holds the source ID, at most one per locale. Every member has its
+// own slug and status.
+type Content struct {
+	ID            string        `json:"id"`
+	Type          string        `json:"type"`
+	Locale        string        `json:"locale"`
+	TranslationOf string        `json:"translation_of,omitempty"`
+	Title         string        `json:"title"`
+	Slug          string        `json:"slug"`
+	Excerpt       string        `json:"excerpt"`
+	Body          string        `json:"body"`
+	BodyFormat    string        `json:"body_format"`
+	BodyHTML      string        `json:"body_html"`
+	Blocks        []block.Block `json:"blocks,omitempty"`
+	Status        string        `json:"status"`
+	AuthorID      string        `json:"author_id"`
+	PublishedAt   time.Time     `json:"published_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	// Version is incremented by every update, which must name the version it replaces.
+	Version int `json:"version"`
+	// Permalink is the public path of the entry, derived from the configured pattern for its type.
+	Permalink string `json:"permalink"`
+	// SnippetRevision identifies the snippets expanded into BodyHTML when the entry was read for
+	// the public site, so the revision of the entry changes along with them.
+	SnippetRevision string `json:"-"`
+	// Visibility restricts who may read the entry: AllowedRoles lists the roles admitted with
+	// VisibilityRoles, and PasswordHash holds the bcrypt hash of the password asked for with
+	// VisibilityPassword. Password carries a new password on save and is never stored.
+	Visibility   string   `json:"visibility"`
+	AllowedRoles []string `json:"allowed_roles,omitempty"`
+	Password     string   `json:"-"`
+	PasswordHash string   `json:"-"`
+	// Teaser lists a restricted entry to every reader, locked for those it does not admit, instead
+	// of hiding it from them.
+	Teaser bool `json:"teaser"`
+	// Locked reports that the body was left out because the reader is not admitted.
+	Locked bool `json:"locked,omitempty"`
+}
+
+// PermalinkDate is the date the permalink of the entry is built from: its publication date, or its
+// creation date while it has never been published.
+func (c *Content) PermalinkDate() time.Time {
+	if !c.PublishedAt.IsZero() {
+		return c.PublishedAt
+	}
+	return c.CreatedAt
+}
+
+// Revision identifies the saved state of the entry. It changes whenever the entry is updated, or
+// one of the snippets expanded into its body is.
+func (c *Content) Revision() string {
+	if c.SnippetRevision != "" {
+		return strconv.Itoa(c.Version) + "-" + c.SnippetRevision
+	}
+	return strconv.Itoa(c.Version)
+}
+
+// Access returns the visibility settings of the entry.
+func (c *Content) Access() Access {
+	return Access{Visibility: c.Visibility, AllowedRoles: c.AllowedRoles, PasswordHash: c.PasswordHash}
+}
+
+// SetAccess replaces the visibility settings of the entry.
+func (c *Content) SetAccess(a Access) {
+	c.Visibility, c.AllowedRoles, c.PasswordHash = a.Visibility, a.AllowedRoles, a.PasswordHash
+}
+
+// Admits reports whether v may read the whole entry.
+func (c *Content) Admits(v Viewer) bool {
+	return c.Access().Admits(v)
+}
+
+// Visible reports whether v may see the entry at all, in full or as a teaser.
+func (c *Content) Visible(v Viewer) bool {
+	return c.Teaser || c.Admits(v)
+}
+
+// Listed reports whether the entry shows up for v in lists and searches, which ignore passwords the
+// same way ListFilter.Viewer does.
+func (c *Content) Listed(v Viewer) bool {
+	return c.Visible(v.WithoutPassword())
+}
+
+// Restrict prepares the entry for v and reports whether v may see it at all. Entries admitting v
+// are left whole, teasers are locked and any other entry is hidden from v.
+func (c *Content) Restrict(v Viewer) bool {
+	if c.Admits(v) {
+		return true
+	}
+	if !c.Teaser {
+		return false
+	}
+	c.Lock()
+	return true
+}
+
+// Lock leaves out everything but the title and excerpt of the entry and marks it locked.
+func (c *Content) Lock() {
+	c.Body = ""
+	c.BodyHTML = ""
+	c.Blocks = nil
+	c.Locked = true
+}
+
+// Access holds who may read an entry beyond the public: AllowedRoles lists the roles admitted with
+// VisibilityRoles and PasswordHash is the bcrypt hash of the password asked for with
+// VisibilityPassword. It is shared by content entries and the entries of admin-defined types.
+type Access struct {
+	Visibility   string
+	AllowedRoles []string
+	PasswordHash string
+}
+
+// Admits reports whether v may read an entry with these settings. Comparing the password of a
+// protected entry is slow on purpose, so callers checking many entries at once should leave
+// Viewer.Password empty.
+func (a Access) Admits(v Viewer) bool {
+	switch a.Visibility {
+	case "", VisibilityPublic:
+		return true
+	case VisibilityAuthenticated:
+		return v.UserID != ""
+	case VisibilityRoles:
+		return v.UserID != "" && v.Role != "" && slices.Contains(a.AllowedRoles, v.Role)
+	case VisibilityPassword:
+		return v.Password != "" && a.PasswordHash != "" &&
+			bcrypt.CompareHashAndPassword([]byte(a.PasswordHash), []byte(v.Password)) == nil
+	}
+	return false
+}
+
+// Prepare normalizes the settings before they are saved. The visibility defaults to public, roles
+// are trimmed and deduplicated, a non-empty password replaces the stored hash and settings that do
+// not apply to the visibility are cleared.
+func (a *Access) Prepare(password string) error {
+	if a.Visibility == "" {
+		a.Visibility = VisibilityPublic
+	}
+	roles := a.AllowedRoles
+	a.AllowedRoles = nil
+	if a.Visibility != VisibilityPassword {
+		a.PasswordHash = ""
+	}
+
+	switch a.Visibility {
+	case VisibilityPublic, VisibilityAuthenticated:
+	case VisibilityRoles:
+		for _, role := range roles {
+			if role = strings.TrimSpace(role); role != "" && !slices.Contains(a.AllowedRoles, role) {
+				a.AllowedRoles = append(a.AllowedRoles, role)
+			}
+		}
+		if len(a.AllowedRoles) == 0 {
+			return fmt.Errorf("%w: allowed_roles is required", ErrInvalidVisibility)
+		}
+	case VisibilityPassword:
+		if password != "" {
+			hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+			if err != nil {
+				return err
+			}
+			a.PasswordHash = string(hash)
+		}
+		if a.PasswordHash == "" {
+			return fmt.Errorf("%w: password is required", ErrInvalidVisibility)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidVisibility, a.Visibility)
+	}
+	return nil
+}
+
+// Viewer is the reader an entry is shown to. The zero Viewer is an anonymous reader.
+type Viewer struct {
+	UserID string
+	Role   string
+	// Password is the password the reader supplied for password-protected entries.
+	Password string
+}
+
+// WithoutPassword returns v without its password, for checking many entries at once.
+func (v Viewer) WithoutPassword() Viewer {
+	v.Password = ""
+	return v
+}
+
+// SlugRedirect remembers a former public path of a content entry so it can be redirected permanently.
+type SlugRedirect struct {
+	ID        string    `json:"id"`
+	ContentID string    `json:"content_id"`
+	OldPath   string    `json:"old_path"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListFilter narrows down the entries returned by Repository.List.
+type ListFilter struct {
+	Type     string
+	Locale   string
+	Status   string
+	AuthorID string
+	// SourcesOnly leaves out translations so every translation group is listed once.
+	SourcesOnly bool
+	// MissingLocales, when set, keeps source entries lacking a translation in any of these locales.
+	MissingLocales []string
+	// PublishedBefore, when set, excludes entries scheduled after the given time.
+	PublishedBefore time.Time
+	CategoryIDs     []string
+	TagID           string
+	// Viewer, when set, leaves out the restricted entries it may neither read nor see as a teaser.
+	// Passwords are not checked, so password-protected entries are only listed as teasers.
+	Viewer *Viewer
+	Limit  int
+	Offset int
+}
+
+// Repository abstracts the data source that stores content entries. Trashed entries are left out
+// of everything but the trash methods.
+type Repository interface {
+	Create(ctx context.Context, c *Content) error
+	GetByID(ctx context.Context, id string) (*Content, error)
+	GetBySlug(ctx context.Context, contentType, locale, slug string) (*Content, error)
+	// SlugInUse reports whether an entry other than exceptID holds the slug. Trashed entries keep
+	// their slugs until they are purged.
+	SlugInUse(ctx context.Context, contentType, locale, slug, exceptID string) (bool, error)
+	Update(ctx context.Context, c *Content) error
+	// Delete moves an entry to the trash, along with the translations of a source entry.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, filter ListFilter) ([]*Content, error)
+	// ListTranslations returns the translations of the given source entries.
+	ListTranslations(ctx context.Context, sourceIDs []string) ([]*Content, error)
+	// GetTrashed retrieves a trashed entry by ID.
+	GetTrashed(ctx context.Context, id string) (*Content, error)
+	// Restore takes an entry out of the trash along with the translations trashed with it.
+	// Purging a source entry also purges its translations.
+	trash.Bin
+}
+
+// RedirectRepository stores former paths of content entries.
+type RedirectRepository interface {
+	// Save records oldPath for the content entry, replacing any previous owner of that path.
+	Save(ctx context.Context, r *SlugRedirect) error
+	GetByPath(ctx context.Context, path string) (*SlugRedirect, error)
+	DeleteByPath(ctx context.Context, path string) error
+	ListByContent(ctx context.Context, contentID string) ([]*SlugRedirect, error)
+}
+
+// Search facets reported with every search result.
+const (
+	FacetType     = "type"
+	FacetCategory = "category"
+	FacetAuthor   = "author"
+)
+
+// SearchQuery describes a full-text search over content entries. Text is matched against titles
+// and bodies; the other fields narrow the matches down.
+type SearchQuery struct {
+	Text       string
+	Type       string
+	Locale     string
+	Status     string
+	CategoryID string
+	AuthorID   string
+	// PublishedBefore, when set, excludes entries scheduled after the given time.
+	PublishedBefore time.Time
+	// Viewer, when set, restricts the matches like ListFilter.Viewer does.
+	Viewer *Viewer
+	Limit  int
+	Offset int
+}
+
+// SearchHit is an entry matching a search. Title and Snippet are HTML-escaped with the matched
+// terms wrapped in <mark> tags.
+type SearchHit struct {
+	Content *Content `json:"content"`
+	Score   float64  `json:"score"`
+	Title   string   `json:"title"`
+	Snippet string   `json:"snippet"`
+}
+
+// FacetValue counts the matching entries sharing a facet value.
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchResult holds one page of hits, best first, along with the total number of matches and
+// their facet counts keyed by facet name.
+type SearchResult struct {
+	Total  int                     `json:"total"`
+	Hits   []*SearchHit            `json:"hits"`
+	Facets map[string][]FacetValue `json:"facets"`
+}
+
+// Snippets expands the snippet references of rendered bodies.
+type Snippets interface {
+	// Expand replaces the snippet references in the BodyHTML of entries with the current HTML of
+	// the snippets and sets the SnippetRevision of the entries referencing any.
+	Expand(ctx context.Context, entries ...*Content) error
+}
+
+// Search is the full-text index over content entries. Titles weigh more than bodies.
+type Search interface {
+	// Index adds or replaces an entry together with the categories it is assigned to. A nil
+	// categoryIDs keeps the categories recorded when the entry was last indexed.
+	Index(ctx context.Context, c *Content, categoryIDs []string) error
+	Remove(ctx context.Context, id string) error
+	Search(ctx context.Context, q SearchQuery) (*SearchResult, error)
+}
@@ -0,0 +1,74 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestContent_Admits(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	anonymous := Viewer{}
+	member := Viewer{UserID: "u-1", Role: "user"}
+	editor := Viewer{UserID: "u-2", Role: "editor"}
+
+	tests := []struct {
+		name  string
+		entry Content
+		admit []Viewer
+		deny  []Viewer
+	}{
+		{"public", Content{Visibility: VisibilityPublic}, []Viewer{anonymous, member}, nil},
+		{"unset", Content{}, []Viewer{anonymous}, nil},
+		{"authenticated", Content{Visibility: VisibilityAuthenticated}, []Viewer{member, editor}, []Viewer{anonymous}},
+		{"roles", Content{Visibility: VisibilityRoles, AllowedRoles: []string{"editor"}}, []Viewer{editor}, []Viewer{anonymous, member, {Role: "editor"}}},
+		{"password", Content{Visibility: VisibilityPassword, PasswordHash: string(hash)}, []Viewer{{Password: "s3cret"}}, []Viewer{anonymous, member, {Password: "wrong"}}},
+		{"unknown", Content{Visibility: "secret"}, nil, []Viewer{anonymous, editor}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, v := range tt.admit {
+				assert.True(t, tt.entry.Admits(v), "%+v", v)
+			}
+			for _, v := range tt.deny {
+				assert.False(t, tt.entry.Admits(v), "%+v", v)
+			}
+		})
+	}
+}
+
+func TestContent_Restrict(t *testing.T) {
+	members := Content{Visibility: VisibilityAuthenticated, Excerpt: "Intro", Body: "Full", BodyHTML: "<p>Full</p>"}
+
+	hidden := members
+	assert.False(t, hidden.Restrict(Viewer{}))
+
+	teaser := members
+	teaser.Teaser = true
+	require.True(t, teaser.Restrict(Viewer{}))
+	assert.True(t, teaser.Locked)
+	assert.Equal(t, "Intro", teaser.Excerpt)
+	assert.Empty(t, teaser.Body)
+	assert.Empty(t, teaser.BodyHTML)
+
+	open := members
+	require.True(t, open.Restrict(Viewer{UserID: "u-1"}))
+	assert.False(t, open.Locked)
+	assert.Equal(t, "<p>Full</p>", open.BodyHTML)
+}
+
+func TestContent_Listed(t *testing.T) {
+	protected := Content{Visibility: VisibilityPassword, PasswordHash: "hash"}
+	assert.False(t, protected.Listed(Viewer{Password: "anything"}))
+
+	protected.Teaser = true
+	assert.True(t, protected.Listed(Viewer{}))
+
+	roles := Content{Visibility: VisibilityRoles, AllowedRoles: []string{"editor"}}
+	assert.True(t, roles.Listed(Viewer{UserID: "u-2", Role: "editor"}))
+	assert.False(t, roles.Listed(Viewer{UserID: "u-1", Role: "user"}))
+}
@@ -0,0 +1,252 @@
+package contenttype
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+)
+
+// Field types available to content type definitions.
+const (
+	FieldText      = "text"
+	FieldRichText  = "rich_text"
+	FieldNumber    = "number"
+	FieldBoolean   = "boolean"
+	FieldDate      = "date"
+	FieldEnum      = "enum"
+	FieldMedia     = "media"
+	FieldReference = "reference"
+	FieldRepeater  = "repeater"
+)
+
+// Rules applied to referencing entries when a referenced entry is deleted.
+const (
+	OnDeleteRestrict = "restrict"
+	OnDeleteCascade  = "cascade"
+	OnDeleteSetNull  = "set_null"
+)
+
+// Entry statuses.
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+)
+
+var (
+	// ErrNotFound is returned when a content type does not exist.
+	ErrNotFound = errors.New("content type not found")
+	// ErrEntryNotFound is returned when an entry does not exist.
+	ErrEntryNotFound = errors.New("entry not found")
+)
+
+// ContentType is an admin-defined content shape. Name identifies the type in URLs and cannot change.
+type ContentType struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Label       string    `json:"label"`
+	Description string    `json:"description"`
+	Fields      []Field   `json:"fields"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Version     int       `json:"version"`
+}
+
+// Field describes one property of an entry. Options lists the allowed values of an enum and Fields
+// holds the item shape of a repeater. Indexed fields are backed by a database index and can be used to
+// filter and sort entries.
+//
+// A reference field points at entries of the Target type; with Multiple it holds a list of entry IDs.
+// OnDelete decides what happens to the referencing entry when a referenced entry is deleted.
+type Field struct {
+	Name     string   `json:"name"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Indexed  bool     `json:"indexed"`
+	Rules    Rules    `json:"rules"`
+	Options  []string `json:"options,omitempty"`
+	Target   string   `json:"target,omitempty"`
+	Multiple bool     `json:"multiple,omitempty"`
+	OnDelete string   `json:"on_delete,omitempty"`
+	Fields   []Field  `json:"fields,omitempty"`
+}
+
+// Rules are optional constraints on a field value. Length rules apply to text, value rules to
+// numbers and item rules to repeaters.
+type Rules struct {
+	MinLength *int     `json:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	MinItems  *int     `json:"min_items,omitempty"`
+	MaxItems  *int     `json:"max_items,omitempty"`
+}
+
+// Field returns the top-level field called name.
+func (ct *ContentType) Field(name string) (Field, bool) {
+	for _, f := range ct.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// IndexedFields returns the top-level fields that are backed by a database index.
+func (ct *ContentType) IndexedFields() []Field {
+	var fields []Field
+	for _, f := range ct.Fields {
+		if f.Indexed {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// ReferenceFields returns the reference fields of ct.
+func (ct *ContentType) ReferenceFields() []Field {
+	var fields []Field
+	for _, f := range ct.Fields {
+		if f.Type == FieldReference {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Entry is a stored instance of a content type. Data holds the field values keyed by field name.
+// Included is only filled on request and maps reference field names to the referenced entries,
+// a single *Entry or a []*Entry for fields holding multiple references.
+//
+// Visibility restricts who may read the entry on the public endpoints the way it does for content
+// entries; entries have no excerpt, so restricted ones are hidden rather than teased. Password
+// carries a new password on save and is never stored.
+type Entry struct {
+	ID           string         `json:"id"`
+	Type         string         `json:"type"`
+	Status       string         `json:"status"`
+	Visibility   string         `json:"visibility"`
+	AllowedRoles []string       `json:"allowed_roles,omitempty"`
+	Password     string         `json:"-"`
+	PasswordHash string         `json:"-"`
+	Data         map[string]any `json:"data"`
+	AuthorID     string         `json:"author_id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	Version      int            `json:"version"`
+	Included     map[string]any `json:"included,omitempty"`
+}
+
+// Access returns the visibility settings of the entry.
+func (e *Entry) Access() content.Access {
+	return content.Access{Visibility: e.Visibility, AllowedRoles: e.AllowedRoles, PasswordHash: e.PasswordHash}
+}
+
+// SetAccess replaces the visibility settings of the entry.
+func (e *Entry) SetAccess(a content.Access) {
+	e.Visibility, e.AllowedRoles, e.PasswordHash = a.Visibility, a.AllowedRoles, a.PasswordHash
+}
+
+// Admits reports whether v may read the entry.
+func (e *Entry) Admits(v content.Viewer) bool {
+	return e.Access().Admits(v)
+}
+
+// ReferencedIDs returns the entry IDs held by the reference field f of e.
+func (e *Entry) ReferencedIDs(f Field) []string {
+	switch v := e.Data[f.Name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		ids := make([]string, 0, len(v))
+		for _, id := range v {
+			if s, ok := id.(string); ok {
+				ids = append(ids, s)
+			}
+		}
+		return ids
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// Reference records that the Field of the source entry points at the target entry. References are
+// kept alongside entry data so reverse lookups do not need to scan every entry.
+type Reference struct {
+	SourceType string `json:"source_type"`
+	SourceID   string `json:"source_id"`
+	Field      string `json:"field"`
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+}
+
+// References lists the references held by e according to the reference fields of ct.
+func (ct *ContentType) References(e *Entry) []Reference {
+	var refs []Reference
+	for _, f := range ct.ReferenceFields() {
+		for _, id := range e.ReferencedIDs(f) {
+			refs = append(refs, Reference{SourceType: e.Type, SourceID: e.ID, Field: f.Name, TargetType: f.Target, TargetID: id})
+		}
+	}
+	return refs
+}
+
+// Condition restricts an entry listing to entries whose indexed field equals Value.
+type Condition struct {
+	Field Field
+	Value any
+}
+
+// EntryFilter narrows down the entries returned by EntryRepository.List. Sort names an indexed
+// field; entries are ordered by creation date when it is empty. When Viewer is set, restricted
+// entries the viewer may not read are left out; passwords are ignored.
+type EntryFilter struct {
+	Status     string
+	Conditions []Condition
+	Sort       *Field
+	Descending bool
+	Viewer     *content.Viewer
+	Limit      int
+	Offset     int
+}
+
+// Repository abstracts the data source that stores content type definitions.
+type Repository interface {
+	Create(ctx context.Context, ct *ContentType) error
+	GetByName(ctx context.Context, name string) (*ContentType, error)
+	Update(ctx context.Context, ct *ContentType) error
+	Delete(ctx context.Context, name string) error
+	List(ctx context.Context) ([]*ContentType, error)
+}
+
+// EntryRepository abstracts the data source that stores entries of every content type.
+type EntryRepository interface {
+	Create(ctx context.Context, e *Entry) error
+	GetByID(ctx context.Context, contentType, id string) (*Entry, error)
+	Update(ctx context.Context, e *Entry) error
+	// Delete moves an entry to the trash.
+	Delete(ctx context.Context, contentType, id string) error
+	List(ctx context.Context, contentType string, filter EntryFilter) ([]*Entry, error)
+	// Count returns the number of entries of a type, trashed ones included.
+	Count(ctx context.Context, contentType string) (int, error)
+	// SyncIndexes creates the indexes needed by the indexed fields of ct and drops the ones of
+	// fields that are no longer indexed.
+	SyncIndexes(ctx context.Context, ct *ContentType) error
+	// GetTrashed retrieves a trashed entry of any type by ID.
+	GetTrashed(ctx context.Context, id string) (*Entry, error)
+	trash.Bin
+}
+
+// ReferenceRepository abstracts the data source that stores references between entries.
+type ReferenceRepository interface {
+	// Replace swaps every reference held by the source entry for refs.
+	Replace(ctx context.Context, sourceID string, refs []Reference) error
+	// ListByTarget returns the references pointing at the target entry ("what links here").
+	ListByTarget(ctx context.Context, targetID string) ([]Reference, error)
+	DeleteBySource(ctx context.Context, sourceID string) error
+}
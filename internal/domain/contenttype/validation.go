@@ -0,0 +1,334 @@
+package contenttype
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mashurimansur/goCMS/internal/utils/markdown"
+)
+
+var (
+	// ErrInvalidDefinition is returned for a content type whose definition is inconsistent.
+	ErrInvalidDefinition = errors.New("invalid content type definition")
+	// ErrInvalidEntry is returned for entry data that does not satisfy its content type.
+	ErrInvalidEntry = errors.New("invalid entry")
+)
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,47}$`)
+
+// ValidationError lists the problems found in entry data, keyed by field path such as
+// "title" or "sections[1].heading".
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	paths := make([]string, 0, len(e.Fields))
+	for path := range e.Fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	problems := make([]string, len(paths))
+	for i, path := range paths {
+		problems[i] = path + " " + e.Fields[path]
+	}
+	return ErrInvalidEntry.Error() + ": " + strings.Join(problems, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidEntry
+}
+
+// ValidateDefinition checks that the type name, field names, field types and rules are consistent.
+func (ct *ContentType) ValidateDefinition() error {
+	if !namePattern.MatchString(ct.Name) {
+		return fmt.Errorf("%w: name must be lowercase letters, digits and underscores", ErrInvalidDefinition)
+	}
+	if len(ct.Fields) == 0 {
+		return fmt.Errorf("%w: at least one field is required", ErrInvalidDefinition)
+	}
+	return validateFields(ct.Fields, "", true)
+}
+
+func validateFields(fields []Field, prefix string, topLevel bool) error {
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		path := prefix + f.Name
+		if !namePattern.MatchString(f.Name) {
+			return fmt.Errorf("%w: field %q must be lowercase letters, digits and underscores", ErrInvalidDefinition, path)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("%w: field %q is defined twice", ErrInvalidDefinition, path)
+		}
+		seen[f.Name] = true
+
+		if err := validateField(f, path, topLevel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateField(f Field, path string, topLevel bool) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: field %q %s", ErrInvalidDefinition, path, fmt.Sprintf(format, args...))
+	}
+
+	switch f.Type {
+	case FieldText, FieldRichText, FieldNumber, FieldBoolean, FieldDate, FieldMedia:
+	case FieldEnum:
+		if len(f.Options) == 0 {
+			return invalid("needs at least one option")
+		}
+	case FieldReference:
+		if !namePattern.MatchString(f.Target) {
+			return invalid("needs a valid target type")
+		}
+		if !topLevel {
+			return invalid("cannot be nested in a repeater")
+		}
+		switch f.OnDelete {
+		case "", OnDeleteRestrict, OnDeleteCascade:
+		case OnDeleteSetNull:
+			if f.Required {
+				return invalid("is required and cannot be cleared on delete")
+			}
+		default:
+			return invalid("has unknown on_delete rule %q", f.OnDelete)
+		}
+	case FieldRepeater:
+		if len(f.Fields) == 0 {
+			return invalid("needs item fields")
+		}
+		if err := validateFields(f.Fields, path+".", false); err != nil {
+			return err
+		}
+	default:
+		return invalid("has unknown type %q", f.Type)
+	}
+
+	if f.Multiple && f.Type != FieldReference {
+		return invalid("cannot hold multiple values")
+	}
+	if f.Indexed && (!topLevel || f.Multiple || f.Type == FieldRichText || f.Type == FieldRepeater) {
+		return invalid("cannot be indexed")
+	}
+	if f.Rules.Pattern != "" {
+		if _, err := regexp.Compile(f.Rules.Pattern); err != nil {
+			return invalid("has an invalid pattern: %v", err)
+		}
+	}
+	if isNegative(f.Rules.MinLength) || isNegative(f.Rules.MaxLength) || isNegative(f.Rules.MinItems) || isNegative(f.Rules.MaxItems) {
+		return invalid("has a negative length rule")
+	}
+	return nil
+}
+
+func isNegative(n *int) bool {
+	return n != nil && *n < 0
+}
+
+// Validate checks data against the fields of ct and returns the normalized values: unknown keys are
+// rejected, rich text is sanitized and dates are converted to RFC 3339 in UTC.
+func (ct *ContentType) Validate(data map[string]any) (map[string]any, error) {
+	problems := make(map[string]string)
+	normalized := validateObject(ct.Fields, data, "", problems)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Fields: problems}
+	}
+	return normalized, nil
+}
+
+func validateObject(fields []Field, data map[string]any, prefix string, problems map[string]string) map[string]any {
+	for key := range data {
+		if !slices.ContainsFunc(fields, func(f Field) bool { return f.Name == key }) {
+			problems[prefix+key] = "is not a field of this type"
+		}
+	}
+
+	out := make(map[string]any, len(fields))
+	for _, f := range fields {
+		path := prefix + f.Name
+		value, ok := data[f.Name]
+		if !ok || value == nil || value == "" {
+			if f.Required {
+				problems[path] = "is required"
+			}
+			continue
+		}
+		if v, ok := validateValue(f, value, path, problems); ok {
+			out[f.Name] = v
+		}
+	}
+	return out
+}
+
+func validateValue(f Field, value any, path string, problems map[string]string) (any, bool) {
+	fail := func(msg string) (any, bool) {
+		problems[path] = msg
+		return nil, false
+	}
+
+	switch f.Type {
+	case FieldText, FieldRichText:
+		s, ok := value.(string)
+		if !ok {
+			return fail("must be a string")
+		}
+		if msg := checkLength(utf8.RuneCountInString(s), f.Rules.MinLength, f.Rules.MaxLength, "characters"); msg != "" {
+			return fail(msg)
+		}
+		if f.Rules.Pattern != "" && !regexp.MustCompile(f.Rules.Pattern).MatchString(s) {
+			return fail("does not match the required pattern")
+		}
+		if f.Type == FieldRichText {
+			return markdown.Sanitize(s), true
+		}
+		return s, true
+	case FieldNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return fail("must be a number")
+		}
+		if f.Rules.Min != nil && n < *f.Rules.Min {
+			return fail(fmt.Sprintf("must be at least %g", *f.Rules.Min))
+		}
+		if f.Rules.Max != nil && n > *f.Rules.Max {
+			return fail(fmt.Sprintf("must be at most %g", *f.Rules.Max))
+		}
+		return n, true
+	case FieldBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return fail("must be true or false")
+		}
+		return b, true
+	case FieldDate:
+		s, ok := value.(string)
+		if !ok {
+			return fail("must be a date string")
+		}
+		t, err := parseDate(s)
+		if err != nil {
+			return fail("must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+		}
+		return t, true
+	case FieldEnum:
+		s, ok := value.(string)
+		if !ok || !slices.Contains(f.Options, s) {
+			return fail("must be one of " + strings.Join(f.Options, ", "))
+		}
+		return s, true
+	case FieldReference:
+		if !f.Multiple {
+			s, ok := value.(string)
+			if !ok {
+				return fail("must be an entry ID")
+			}
+			return s, true
+		}
+		items, ok := value.([]any)
+		if !ok {
+			return fail("must be a list of entry IDs")
+		}
+		if msg := checkLength(len(items), f.Rules.MinItems, f.Rules.MaxItems, "items"); msg != "" {
+			return fail(msg)
+		}
+		ids := make([]any, 0, len(items))
+		for _, item := range items {
+			id, ok := item.(string)
+			if !ok || id == "" {
+				return fail("must be a list of entry IDs")
+			}
+			if !slices.Contains(ids, any(id)) {
+				ids = append(ids, id)
+			}
+		}
+		if f.Required && len(ids) == 0 {
+			return fail("is required")
+		}
+		return ids, true
+	case FieldMedia:
+		s, ok := value.(string)
+		if !ok {
+			return fail("must be an ID")
+		}
+		return s, true
+	case FieldRepeater:
+		items, ok := value.([]any)
+		if !ok {
+			return fail("must be a list")
+		}
+		if msg := checkLength(len(items), f.Rules.MinItems, f.Rules.MaxItems, "items"); msg != "" {
+			return fail(msg)
+		}
+		out := make([]any, 0, len(items))
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			obj, ok := item.(map[string]any)
+			if !ok {
+				problems[itemPath] = "must be an object"
+				continue
+			}
+			out = append(out, validateObject(f.Fields, obj, itemPath+".", problems))
+		}
+		return out, true
+	}
+	return fail("has an unsupported type")
+}
+
+func checkLength(n int, min, max *int, unit string) string {
+	if min != nil && n < *min {
+		return fmt.Sprintf("must have at least %d %s", *min, unit)
+	}
+	if max != nil && n > *max {
+		return fmt.Sprintf("must have at most %d %s", *max, unit)
+	}
+	return ""
+}
+
+// parseDate accepts a calendar date or an RFC 3339 timestamp and returns it as RFC 3339 in UTC,
+// so stored dates sort lexically.
+func parseDate(s string) (string, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		if t, err = time.Parse(time.DateOnly, s); err != nil {
+			return "", err
+		}
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// ParseFilterValue converts a query string value into the stored representation of an indexed field.
+func (f Field) ParseFilterValue(raw string) (any, error) {
+	switch f.Type {
+	case FieldNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidEntry, f.Name)
+		}
+		return n, nil
+	case FieldBoolean:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be true or false", ErrInvalidEntry, f.Name)
+		}
+		return b, nil
+	case FieldDate:
+		d, err := parseDate(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a date", ErrInvalidEntry, f.Name)
+		}
+		return d, nil
+	default:
+		return raw, nil
+	}
+}
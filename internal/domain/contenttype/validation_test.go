@@ -0,0 +1,147 @@
+package contenttype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(n int) *int { return &n }
+
+func floatPtr(n float64) *float64 { return &n }
+
+func productType() *ContentType {
+	return &ContentType{
+		Name: "product",
+		Fields: []Field{
+			{Name: "title", Type: FieldText, Required: true, Indexed: true, Rules: Rules{MaxLength: intPtr(20)}},
+			{Name: "description", Type: FieldRichText},
+			{Name: "price", Type: FieldNumber, Indexed: true, Rules: Rules{Min: floatPtr(0)}},
+			{Name: "in_stock", Type: FieldBoolean},
+			{Name: "released", Type: FieldDate},
+			{Name: "color", Type: FieldEnum, Options: []string{"red", "blue"}},
+			{Name: "photo", Type: FieldMedia},
+			{Name: "specs", Type: FieldRepeater, Rules: Rules{MaxItems: intPtr(2)}, Fields: []Field{
+				{Name: "label", Type: FieldText, Required: true},
+				{Name: "value", Type: FieldText},
+			}},
+		},
+	}
+}
+
+func TestContentType_ValidateDefinition(t *testing.T) {
+	assert.NoError(t, productType().ValidateDefinition())
+
+	cases := map[string]*ContentType{
+		"bad name":          {Name: "Product", Fields: []Field{{Name: "title", Type: FieldText}}},
+		"no fields":         {Name: "product"},
+		"duplicate field":   {Name: "product", Fields: []Field{{Name: "a", Type: FieldText}, {Name: "a", Type: FieldText}}},
+		"unknown type":      {Name: "product", Fields: []Field{{Name: "a", Type: "geo"}}},
+		"enum options":      {Name: "product", Fields: []Field{{Name: "a", Type: FieldEnum}}},
+		"empty repeater":    {Name: "product", Fields: []Field{{Name: "a", Type: FieldRepeater}}},
+		"indexed repeater":  {Name: "product", Fields: []Field{{Name: "a", Type: FieldRepeater, Indexed: true, Fields: []Field{{Name: "b", Type: FieldText}}}}},
+		"nested index":      {Name: "product", Fields: []Field{{Name: "a", Type: FieldRepeater, Fields: []Field{{Name: "b", Type: FieldText, Indexed: true}}}}},
+		"bad pattern":       {Name: "product", Fields: []Field{{Name: "a", Type: FieldText, Rules: Rules{Pattern: "("}}}},
+		"negative length":   {Name: "product", Fields: []Field{{Name: "a", Type: FieldText, Rules: Rules{MinLength: intPtr(-1)}}}},
+		"bad nested fields": {Name: "product", Fields: []Field{{Name: "a", Type: FieldRepeater, Fields: []Field{{Name: "B", Type: FieldText}}}}},
+		"untyped reference": {Name: "product", Fields: []Field{{Name: "a", Type: FieldReference}}},
+		"nested reference":  {Name: "product", Fields: []Field{{Name: "a", Type: FieldRepeater, Fields: []Field{{Name: "b", Type: FieldReference, Target: "brand"}}}}},
+		"unknown on_delete": {Name: "product", Fields: []Field{{Name: "a", Type: FieldReference, Target: "brand", OnDelete: "ignore"}}},
+		"required set_null": {Name: "product", Fields: []Field{{Name: "a", Type: FieldReference, Target: "brand", Required: true, OnDelete: OnDeleteSetNull}}},
+		"multiple text":     {Name: "product", Fields: []Field{{Name: "a", Type: FieldText, Multiple: true}}},
+	}
+	for name, ct := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, ct.ValidateDefinition(), ErrInvalidDefinition)
+		})
+	}
+}
+
+func TestContentType_Validate_Normalizes(t *testing.T) {
+	data, err := productType().Validate(map[string]any{
+		"title":       "Lamp",
+		"description": `<p onclick="x()">Bright</p><script>alert(1)</script>`,
+		"price":       12.5,
+		"in_stock":    true,
+		"released":    "2025-03-01",
+		"color":       "red",
+		"specs":       []any{map[string]any{"label": "Watt", "value": "40"}},
+		"photo":       "",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Lamp", data["title"])
+	assert.Equal(t, "<p>Bright</p>", data["description"])
+	assert.Equal(t, "2025-03-01T00:00:00Z", data["released"])
+	assert.Equal(t, []any{map[string]any{"label": "Watt", "value": "40"}}, data["specs"])
+	assert.NotContains(t, data, "photo")
+}
+
+func TestContentType_Validate_ReportsEveryProblem(t *testing.T) {
+	_, err := productType().Validate(map[string]any{
+		"title":    "A title that is far too long",
+		"price":    -1.0,
+		"in_stock": "yes",
+		"released": "yesterday",
+		"color":    "green",
+		"unknown":  1,
+		"specs":    []any{map[string]any{"value": "40"}, "oops", map[string]any{}},
+	})
+
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.ErrorIs(t, err, ErrInvalidEntry)
+	assert.Equal(t, map[string]string{
+		"title":    "must have at most 20 characters",
+		"price":    "must be at least 0",
+		"in_stock": "must be true or false",
+		"released": "must be a date (YYYY-MM-DD) or an RFC 3339 timestamp",
+		"color":    "must be one of red, blue",
+		"unknown":  "is not a field of this type",
+		"specs":    "must have at most 2 items",
+	}, verr.Fields)
+
+	_, err = productType().Validate(map[string]any{"specs": []any{map[string]any{"value": "40"}, "oops"}})
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, map[string]string{
+		"title":          "is required",
+		"specs[0].label": "is required",
+		"specs[1]":       "must be an object",
+	}, verr.Fields)
+}
+
+func TestField_ParseFilterValue(t *testing.T) {
+	v, err := Field{Name: "price", Type: FieldNumber}.ParseFilterValue("9.5")
+	require.NoError(t, err)
+	assert.Equal(t, 9.5, v)
+
+	v, err = Field{Name: "released", Type: FieldDate}.ParseFilterValue("2025-03-01")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-01T00:00:00Z", v)
+
+	_, err = Field{Name: "in_stock", Type: FieldBoolean}.ParseFilterValue("maybe")
+	assert.ErrorIs(t, err, ErrInvalidEntry)
+}
+
+func TestContentType_References(t *testing.T) {
+	ct := &ContentType{Name: "article", Fields: []Field{
+		{Name: "title", Type: FieldText},
+		{Name: "author", Type: FieldReference, Target: "person"},
+		{Name: "related", Type: FieldReference, Target: "article", Multiple: true, Rules: Rules{MaxItems: intPtr(3)}},
+	}}
+
+	data, err := ct.Validate(map[string]any{"author": "p-1", "related": []any{"a-2", "a-3", "a-2"}})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"a-2", "a-3"}, data["related"])
+
+	refs := ct.References(&Entry{ID: "a-1", Type: "article", Data: data})
+	assert.Equal(t, []Reference{
+		{SourceType: "article", SourceID: "a-1", Field: "author", TargetType: "person", TargetID: "p-1"},
+		{SourceType: "article", SourceID: "a-1", Field: "related", TargetType: "article", TargetID: "a-2"},
+		{SourceType: "article", SourceID: "a-1", Field: "related", TargetType: "article", TargetID: "a-3"},
+	}, refs)
+
+	_, err = ct.Validate(map[string]any{"related": "a-2"})
+	assert.ErrorIs(t, err, ErrInvalidEntry)
+}
@@ -0,0 +1,118 @@
+package form
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Field types available to form definitions. Every submitted value is kept as a string.
+const (
+	FieldText     = "text"
+	FieldTextarea = "textarea"
+	FieldEmail    = "email"
+	FieldURL      = "url"
+	FieldPhone    = "phone"
+	FieldNumber   = "number"
+	FieldDate     = "date"
+	FieldSelect   = "select"
+	FieldCheckbox = "checkbox"
+)
+
+var (
+	// ErrNotFound is returned when a form does not exist.
+	ErrNotFound = errors.New("form not found")
+	// ErrSubmissionNotFound is returned when a submission does not exist.
+	ErrSubmissionNotFound = errors.New("submission not found")
+)
+
+// Form is an admin-defined form such as "contact" or "newsletter". Name identifies it in the public
+// submission URL.
+//
+// After a successful submission readers see SuccessMessage, or plain HTML forms are redirected to
+// RedirectURL. Each stored submission is posted to NotifyURL when it is set, and submissions older
+// than RetentionDays are purged; zero keeps them until they are deleted.
+type Form struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	Fields         []Field   `json:"fields"`
+	SuccessMessage string    `json:"success_message"`
+	RedirectURL    string    `json:"redirect_url,omitempty"`
+	NotifyURL      string    `json:"notify_url,omitempty"`
+	RetentionDays  int       `json:"retention_days"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Version        int       `json:"version"`
+}
+
+// Field describes one input of a form. Options lists the choices of a select field.
+type Field struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Placeholder string   `json:"placeholder,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	Rules       Rules    `json:"rules"`
+}
+
+// Rules are optional constraints on a field value. Length rules and the pattern apply to text,
+// value rules to numbers.
+type Rules struct {
+	MinLength *int     `json:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+}
+
+// Redact clears the settings only administrators may see.
+func (f *Form) Redact() {
+	f.NotifyURL = ""
+	f.RetentionDays = 0
+}
+
+// Submission is a stored answer to a form. Data holds the validated values keyed by field name.
+type Submission struct {
+	ID        string            `json:"id"`
+	FormID    string            `json:"form_id"`
+	Data      map[string]string `json:"data"`
+	IP        string            `json:"ip,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Repository abstracts the data source that stores forms and their submissions.
+type Repository interface {
+	Create(ctx context.Context, f *Form) error
+	GetByID(ctx context.Context, id string) (*Form, error)
+	GetByName(ctx context.Context, name string) (*Form, error)
+	// Update updates a form if it is still at f.Version, then increments the version.
+	Update(ctx context.Context, f *Form) error
+	// Delete removes a form together with its submissions.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*Form, error)
+
+	CreateSubmission(ctx context.Context, s *Submission) error
+	// CreateSubmissionLimited inserts s unless max submissions to any form were already made from its
+	// address since the given time, and reports whether it did. Submissions from one address are
+	// counted and inserted one at a time.
+	CreateSubmissionLimited(ctx context.Context, s *Submission, since time.Time, max int) (bool, error)
+	GetSubmission(ctx context.Context, id string) (*Submission, error)
+	// ListSubmissions returns the submissions of a form, newest first.
+	ListSubmissions(ctx context.Context, formID string, limit, offset int) ([]*Submission, error)
+	// ListSubmissionsAfter returns up to limit submissions of a form that come after the given one
+	// newest first, starting with the newest when after is nil.
+	ListSubmissionsAfter(ctx context.Context, formID string, after *Submission, limit int) ([]*Submission, error)
+	DeleteSubmission(ctx context.Context, id string) error
+	// DeleteSubmissionsBefore removes the submissions of a form made before the given time and
+	// reports how many were removed.
+	DeleteSubmissionsBefore(ctx context.Context, formID string, before time.Time) (int64, error)
+}
+
+// Notifier is told about every stored submission of a form with a NotifyURL.
+type Notifier interface {
+	Notify(ctx context.Context, f *Form, s *Submission) error
+}
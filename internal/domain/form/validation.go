@@ -0,0 +1,217 @@
+package form
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidDefinition is returned for a form whose definition is inconsistent.
+	ErrInvalidDefinition = errors.New("invalid form definition")
+	// ErrInvalidSubmission is returned for submitted values that do not satisfy their form.
+	ErrInvalidSubmission = errors.New("invalid submission")
+)
+
+var (
+	namePattern      = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+	fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,47}$`)
+	phonePattern     = regexp.MustCompile(`^\+?[0-9][0-9 ()./-]{4,24}$`)
+)
+
+// maxValueLength caps the number of characters of any submitted value, whatever its rules say.
+const maxValueLength = 10000
+
+// ValidationError lists the problems found in a submission, keyed by field name.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	problems := make([]string, len(names))
+	for i, name := range names {
+		problems[i] = name + " " + e.Fields[name]
+	}
+	return ErrInvalidSubmission.Error() + ": " + strings.Join(problems, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidSubmission
+}
+
+// ValidName reports whether name can identify a form in URLs.
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// ValidateDefinition checks that the form name, field names, field types and rules are consistent.
+func (f *Form) ValidateDefinition() error {
+	if !ValidName(f.Name) {
+		return fmt.Errorf("%w: name must be lower-case letters, digits, dashes or underscores", ErrInvalidDefinition)
+	}
+	if len(f.Fields) == 0 {
+		return fmt.Errorf("%w: at least one field is required", ErrInvalidDefinition)
+	}
+	if f.RetentionDays < 0 {
+		return fmt.Errorf("%w: retention_days cannot be negative", ErrInvalidDefinition)
+	}
+
+	seen := make(map[string]bool, len(f.Fields))
+	for _, field := range f.Fields {
+		if !fieldNamePattern.MatchString(field.Name) {
+			return fmt.Errorf("%w: field %q must be lowercase letters, digits and underscores", ErrInvalidDefinition, field.Name)
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("%w: field %q is defined twice", ErrInvalidDefinition, field.Name)
+		}
+		seen[field.Name] = true
+
+		if err := validateField(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateField(f Field) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: field %q %s", ErrInvalidDefinition, f.Name, fmt.Sprintf(format, args...))
+	}
+
+	switch f.Type {
+	case FieldText, FieldTextarea, FieldEmail, FieldURL, FieldPhone, FieldNumber, FieldDate, FieldCheckbox:
+	case FieldSelect:
+		if len(f.Options) == 0 {
+			return invalid("needs at least one option")
+		}
+	default:
+		return invalid("has unknown type %q", f.Type)
+	}
+
+	if f.Rules.Pattern != "" {
+		if _, err := regexp.Compile(f.Rules.Pattern); err != nil {
+			return invalid("has an invalid pattern: %v", err)
+		}
+	}
+	if (f.Rules.MinLength != nil && *f.Rules.MinLength < 0) || (f.Rules.MaxLength != nil && *f.Rules.MaxLength < 0) {
+		return invalid("has a negative length rule")
+	}
+	return nil
+}
+
+// Validate checks submitted values against the fields of f and returns the normalized values of
+// every field: text is trimmed, checkboxes become "true" or "false" and keys that are not fields
+// of the form are dropped.
+func (f *Form) Validate(values map[string]string) (map[string]string, error) {
+	problems := make(map[string]string)
+	out := make(map[string]string, len(f.Fields))
+	for _, field := range f.Fields {
+		value := strings.TrimSpace(values[field.Name])
+		if field.Type == FieldCheckbox {
+			checked := isChecked(value)
+			if field.Required && !checked {
+				problems[field.Name] = "must be checked"
+			}
+			out[field.Name] = strconv.FormatBool(checked)
+			continue
+		}
+		if value == "" {
+			if field.Required {
+				problems[field.Name] = "is required"
+			}
+			out[field.Name] = ""
+			continue
+		}
+		if msg := validateValue(field, value); msg != "" {
+			problems[field.Name] = msg
+			continue
+		}
+		out[field.Name] = value
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Fields: problems}
+	}
+	return out, nil
+}
+
+// validateValue returns what is wrong with a non-empty value of field f, if anything.
+func validateValue(f Field, value string) string {
+	length := utf8.RuneCountInString(value)
+	if length > maxValueLength {
+		return fmt.Sprintf("must have at most %d characters", maxValueLength)
+	}
+
+	switch f.Type {
+	case FieldEmail:
+		if addr, err := mail.ParseAddress(value); err != nil || addr.Address != value {
+			return "must be a valid email address"
+		}
+	case FieldURL:
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "must be an http or https URL"
+		}
+	case FieldPhone:
+		if !phonePattern.MatchString(value) {
+			return "must be a phone number"
+		}
+	case FieldNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "must be a number"
+		}
+		if f.Rules.Min != nil && n < *f.Rules.Min {
+			return fmt.Sprintf("must be at least %g", *f.Rules.Min)
+		}
+		if f.Rules.Max != nil && n > *f.Rules.Max {
+			return fmt.Sprintf("must be at most %g", *f.Rules.Max)
+		}
+		return ""
+	case FieldDate:
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return "must be a date (YYYY-MM-DD)"
+		}
+		return ""
+	case FieldSelect:
+		if !slices.Contains(f.Options, value) {
+			return "must be one of " + strings.Join(f.Options, ", ")
+		}
+		return ""
+	}
+
+	if f.Rules.MinLength != nil && length < *f.Rules.MinLength {
+		return fmt.Sprintf("must have at least %d characters", *f.Rules.MinLength)
+	}
+	if f.Rules.MaxLength != nil && length > *f.Rules.MaxLength {
+		return fmt.Sprintf("must have at most %d characters", *f.Rules.MaxLength)
+	}
+	if f.Rules.Pattern != "" && !regexp.MustCompile(f.Rules.Pattern).MatchString(value) {
+		return "does not match the required pattern"
+	}
+	return ""
+}
+
+// isChecked interprets the value browsers and API clients send for a checkbox.
+func isChecked(value string) bool {
+	switch strings.ToLower(value) {
+	case "on", "yes":
+		return true
+	}
+	checked, _ := strconv.ParseBool(value)
+	return checked
+}
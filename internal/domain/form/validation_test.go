@@ -0,0 +1,103 @@
+package form
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(n int) *int { return &n }
+
+func floatPtr(n float64) *float64 { return &n }
+
+func contactForm() *Form {
+	return &Form{
+		Name: "contact",
+		Fields: []Field{
+			{Name: "name", Type: FieldText, Required: true, Rules: Rules{MaxLength: intPtr(10)}},
+			{Name: "email", Type: FieldEmail, Required: true},
+			{Name: "website", Type: FieldURL},
+			{Name: "phone", Type: FieldPhone},
+			{Name: "seats", Type: FieldNumber, Rules: Rules{Min: floatPtr(1), Max: floatPtr(5)}},
+			{Name: "day", Type: FieldDate},
+			{Name: "topic", Type: FieldSelect, Options: []string{"sales", "support"}},
+			{Name: "code", Type: FieldText, Rules: Rules{Pattern: `^[A-Z]{3}$`}},
+			{Name: "message", Type: FieldTextarea},
+			{Name: "consent", Type: FieldCheckbox, Required: true},
+			{Name: "newsletter", Type: FieldCheckbox},
+		},
+	}
+}
+
+func TestForm_ValidateDefinition(t *testing.T) {
+	assert.NoError(t, contactForm().ValidateDefinition())
+
+	cases := map[string]*Form{
+		"bad name":          {Name: "Contact Us", Fields: []Field{{Name: "a", Type: FieldText}}},
+		"no fields":         {Name: "contact"},
+		"negative days":     {Name: "contact", RetentionDays: -1, Fields: []Field{{Name: "a", Type: FieldText}}},
+		"bad field name":    {Name: "contact", Fields: []Field{{Name: "E-mail", Type: FieldEmail}}},
+		"duplicate field":   {Name: "contact", Fields: []Field{{Name: "a", Type: FieldText}, {Name: "a", Type: FieldText}}},
+		"unknown type":      {Name: "contact", Fields: []Field{{Name: "a", Type: "file"}}},
+		"select options":    {Name: "contact", Fields: []Field{{Name: "a", Type: FieldSelect}}},
+		"bad pattern":       {Name: "contact", Fields: []Field{{Name: "a", Type: FieldText, Rules: Rules{Pattern: "("}}}},
+		"negative length":   {Name: "contact", Fields: []Field{{Name: "a", Type: FieldText, Rules: Rules{MinLength: intPtr(-1)}}}},
+		"negative max size": {Name: "contact", Fields: []Field{{Name: "a", Type: FieldText, Rules: Rules{MaxLength: intPtr(-1)}}}},
+	}
+	for name, f := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, f.ValidateDefinition(), ErrInvalidDefinition)
+		})
+	}
+}
+
+func TestForm_Validate(t *testing.T) {
+	values, err := contactForm().Validate(map[string]string{
+		"name":    " Ana ",
+		"email":   "ana@example.com",
+		"website": "https://example.com",
+		"phone":   "+62 812-3456-7890",
+		"seats":   "2",
+		"day":     "2026-02-01",
+		"topic":   "sales",
+		"code":    "ABC",
+		"consent": "on",
+		"submit":  "Send",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Ana", values["name"])
+	assert.Equal(t, "true", values["consent"])
+	assert.Equal(t, "false", values["newsletter"])
+	assert.Equal(t, "", values["message"])
+	assert.NotContains(t, values, "submit")
+}
+
+func TestForm_Validate_Problems(t *testing.T) {
+	_, err := contactForm().Validate(map[string]string{
+		"name":    "Anastasia Maria",
+		"email":   "Ana <ana@example.com>",
+		"website": "ftp://example.com",
+		"phone":   "call me",
+		"seats":   "9",
+		"day":     "01/02/2026",
+		"topic":   "jobs",
+		"code":    "abc",
+	})
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.ErrorIs(t, err, ErrInvalidSubmission)
+	assert.Equal(t, map[string]string{
+		"name":    "must have at most 10 characters",
+		"email":   "must be a valid email address",
+		"website": "must be an http or https URL",
+		"phone":   "must be a phone number",
+		"seats":   "must be at most 5",
+		"day":     "must be a date (YYYY-MM-DD)",
+		"topic":   "must be one of sales, support",
+		"code":    "does not match the required pattern",
+		"consent": "must be checked",
+	}, validationErr.Fields)
+}
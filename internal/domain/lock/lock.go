@@ -0,0 +1,78 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrVersionConflict is returned when saving a resource that changed since the version the
+	// editor started from.
+	ErrVersionConflict = errors.New("resource was modified by someone else")
+	// ErrNotFound is returned when a resource is not locked.
+	ErrNotFound = errors.New("lock not found")
+)
+
+// Resource types that can be locked for editing.
+const (
+	ResourceContent     = "content"
+	ResourceCategory    = "category"
+	ResourceTag         = "tag"
+	ResourceMenu        = "menu"
+	ResourceMedia       = "media"
+	ResourceRedirect    = "redirect"
+	ResourceContentType = "content_type"
+	ResourceEntry       = "entry"
+	ResourceUser        = "user"
+)
+
+// ResourceTypes lists the resource types that can be locked.
+var ResourceTypes = []string{
+	ResourceContent, ResourceCategory, ResourceTag, ResourceMenu, ResourceMedia,
+	ResourceRedirect, ResourceContentType, ResourceEntry, ResourceUser,
+}
+
+// Expect returns the version an update must replace. Editors name the version they started from;
+// an update that names none, such as an HTTP update sent with "If-Match: *", replaces whatever
+// version is current.
+func Expect(expected, current int) (int, error) {
+	if expected != 0 && expected != current {
+		return 0, fmt.Errorf("%w: expected version %d, current version is %d", ErrVersionConflict, expected, current)
+	}
+	return current, nil
+}
+
+// Lock tells other editors that someone is editing a resource. Locks are advisory: saving is guarded
+// by versions, locks only let editors see each other. A lock expires unless its holder keeps sending
+// heartbeats.
+type Lock struct {
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	HolderID     string `json:"holder_id"`
+	// HolderName is the display name of the holder, resolved when the lock is read.
+	HolderName  string    `json:"holder_name"`
+	AcquiredAt  time.Time `json:"acquired_at"`
+	HeartbeatAt time.Time `json:"heartbeat_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Active reports whether the lock is still held at the given time.
+func (l *Lock) Active(now time.Time) bool {
+	return now.Before(l.ExpiresAt)
+}
+
+// Repository abstracts the data source that stores edit locks.
+type Repository interface {
+	Get(ctx context.Context, resourceType, resourceID string) (*Lock, error)
+	// Save inserts the lock of a resource or replaces the stored one.
+	Save(ctx context.Context, l *Lock) error
+	// Claim saves l unless someone else holds an active lock on its resource at now, and reports
+	// whether it did. A holder extending their own lock keeps its acquired time.
+	Claim(ctx context.Context, l *Lock, now time.Time) (bool, error)
+	// Delete releases the lock of a resource if holderID holds it.
+	Delete(ctx context.Context, resourceType, resourceID, holderID string) error
+	// ListActive returns the locks of a resource type that have not expired at the given time.
+	ListActive(ctx context.Context, resourceType string, now time.Time) ([]*Lock, error)
+}
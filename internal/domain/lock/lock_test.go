@@ -0,0 +1,30 @@
+package lock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpect(t *testing.T) {
+	version, err := Expect(0, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, version)
+
+	version, err = Expect(4, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, version)
+
+	_, err = Expect(3, 4)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+}
+
+func TestLock_Active(t *testing.T) {
+	now := time.Now()
+	l := &Lock{ExpiresAt: now.Add(time.Minute)}
+
+	assert.True(t, l.Active(now))
+	assert.False(t, l.Active(now.Add(time.Minute)))
+}
@@ -0,0 +1,76 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+)
+
+// ErrNotFound is returned when a media item or its stored object does not exist.
+var ErrNotFound = errors.New("media not found")
+
+// Media describes an uploaded file. The bytes live in a Storage under StorageKey.
+type Media struct {
+	ID         string `json:"id"`
+	Filename   string `json:"filename"`
+	StorageKey string `json:"-"`
+	MimeType   string `json:"mime_type"`
+	Size       int64  `json:"size"`
+	Width      int    `json:"width,omitempty"`
+	Height     int    `json:"height,omitempty"`
+	// FocalX and FocalY mark, in the 0..1 range, the point crops keep in view.
+	FocalX     float64 `json:"focal_x"`
+	FocalY     float64 `json:"focal_y"`
+	AltText    string  `json:"alt_text"`
+	UploaderID string  `json:"uploader_id,omitempty"`
+	URL        string  `json:"url"`
+	// Sizes maps configured rendition names to their URLs. Only images have sizes.
+	Sizes     map[string]string `json:"sizes,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Version   int               `json:"version"`
+}
+
+// IsImage reports whether the media item is a raster image.
+func (m *Media) IsImage() bool {
+	return strings.HasPrefix(m.MimeType, "image/")
+}
+
+// ListFilter narrows a media listing. Zero values are ignored.
+type ListFilter struct {
+	// MimePrefix matches the beginning of the MIME type, e.g. "image/".
+	MimePrefix string
+	UploaderID string
+	Limit      int
+	Offset     int
+}
+
+// Repository abstracts the data source that stores media metadata. Trashed media are left out of
+// everything but the trash methods.
+type Repository interface {
+	Create(ctx context.Context, m *Media) error
+	GetByID(ctx context.Context, id string) (*Media, error)
+	Update(ctx context.Context, m *Media) error
+	// Delete moves a media row to the trash.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, filter ListFilter) ([]*Media, error)
+	// GetTrashed retrieves a trashed media row by ID.
+	GetTrashed(ctx context.Context, id string) (*Media, error)
+	trash.Bin
+}
+
+// Storage abstracts the backend that holds media bytes, such as a local directory or an object store.
+type Storage interface {
+	// Save writes r under key and returns the number of bytes written.
+	Save(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open returns a seekable reader for key, or ErrNotFound.
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every key below prefix, such as all derivatives of one item.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
@@ -0,0 +1,87 @@
+package menu
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+)
+
+// Link types of a menu item.
+const (
+	LinkContent  = "content"
+	LinkCategory = "category"
+	LinkURL      = "url"
+)
+
+// ErrNotFound is returned when a menu does not exist.
+var ErrNotFound = errors.New("menu not found")
+
+// Menu is a named navigation menu such as "header" or "footer".
+type Menu struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int       `json:"version"`
+	Items     []*Item   `json:"items,omitempty"`
+}
+
+// Item is an entry of a menu. Content and category items point to TargetID and get their URL
+// from the target's current permalink when the menu is read; URL items link to URL as given.
+// Position orders the items of a menu depth-first, so parents always precede their children.
+type Item struct {
+	ID       string  `json:"id"`
+	MenuID   string  `json:"menu_id"`
+	ParentID string  `json:"parent_id,omitempty"`
+	Label    string  `json:"label"`
+	LinkType string  `json:"link_type"`
+	TargetID string  `json:"target_id,omitempty"`
+	URL      string  `json:"url"`
+	Position int     `json:"position"`
+	NewTab   bool    `json:"new_tab"`
+	Children []*Item `json:"children,omitempty"`
+}
+
+// BuildTree nests items under their parents, keeping their order. Items whose parent is missing
+// become roots.
+func BuildTree(items []*Item) []*Item {
+	byID := make(map[string]*Item, len(items))
+	for _, item := range items {
+		item.Children = nil
+		byID[item.ID] = item
+	}
+
+	var roots []*Item
+	for _, item := range items {
+		parent, ok := byID[item.ParentID]
+		if item.ParentID == "" || !ok {
+			roots = append(roots, item)
+			continue
+		}
+		parent.Children = append(parent.Children, item)
+	}
+
+	return roots
+}
+
+// Repository abstracts the data source that stores menus and their items.
+type Repository interface {
+	Create(ctx context.Context, m *Menu) error
+	GetByID(ctx context.Context, id string) (*Menu, error)
+	GetByName(ctx context.Context, name string) (*Menu, error)
+	// NameInUse reports whether a menu other than exceptID, trashed or not, holds the name.
+	NameInUse(ctx context.Context, name, exceptID string) (bool, error)
+	Update(ctx context.Context, m *Menu) error
+	// Delete moves a menu to the trash.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*Menu, error)
+	// ListItems returns the items of a menu ordered by position.
+	ListItems(ctx context.Context, menuID string) ([]*Item, error)
+	// ReplaceItems swaps every item of a menu for items in a single transaction, if the menu is
+	// still at m.Version, then increments the version.
+	ReplaceItems(ctx context.Context, m *Menu, items []*Item) error
+	trash.Bin
+}
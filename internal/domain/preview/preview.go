@@ -0,0 +1,46 @@
+package preview
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a preview link does not exist.
+var ErrNotFound = errors.New("preview link not found")
+
+// Link lets people without an account view one revision of a content entry, usually a draft,
+// until it expires or is revoked. The link ID is the ID of the signed token handed out with it.
+type Link struct {
+	ID        string `json:"id"`
+	ContentID string `json:"content_id"`
+	// Revision is the revision of the entry the link was created for.
+	Revision  string    `json:"revision"`
+	CreatedBy string    `json:"created_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Active reports whether the link still grants access at the given time.
+func (l *Link) Active(now time.Time) bool {
+	return l.RevokedAt.IsZero() && now.Before(l.ExpiresAt)
+}
+
+// Grant is a newly created link together with its token, which is not stored and only shown once.
+type Grant struct {
+	Link  *Link  `json:"link"`
+	Token string `json:"token"`
+	// URL is where the preview can be viewed with the token.
+	URL string `json:"url"`
+}
+
+// Repository abstracts the data source that stores preview links.
+type Repository interface {
+	Create(ctx context.Context, l *Link) error
+	GetByID(ctx context.Context, id string) (*Link, error)
+	// ListByContent returns the links of an entry, newest first.
+	ListByContent(ctx context.Context, contentID string) ([]*Link, error)
+	// Revoke marks a link as revoked at the given time.
+	Revoke(ctx context.Context, id string, at time.Time) error
+}
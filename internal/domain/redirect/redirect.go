@@ -0,0 +1,47 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Match types supported by redirect rules.
+const (
+	MatchExact    = "exact"
+	MatchWildcard = "wildcard"
+	MatchRegex    = "regex"
+)
+
+// ErrNotFound is returned when a redirect rule does not exist.
+var ErrNotFound = errors.New("redirect rule not found")
+
+// Rule sends requests whose path matches Source to Target with StatusCode.
+// Wildcard and regex rules may reference captured groups in Target as $1, $2, ...
+// Rules answering 410 Gone do not need a Target.
+type Rule struct {
+	ID         string    `json:"id"`
+	Source     string    `json:"source"`
+	MatchType  string    `json:"match_type"`
+	Target     string    `json:"target"`
+	StatusCode int       `json:"status_code"`
+	Position   int       `json:"position"`
+	Enabled    bool      `json:"enabled"`
+	Hits       int64     `json:"hits"`
+	LastHitAt  time.Time `json:"last_hit_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Version    int       `json:"version"`
+}
+
+// Repository abstracts the data source that stores redirect rules.
+type Repository interface {
+	Create(ctx context.Context, r *Rule) error
+	GetByID(ctx context.Context, id string) (*Rule, error)
+	Update(ctx context.Context, r *Rule) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, limit, offset int) ([]*Rule, error)
+	// ListEnabled returns every enabled rule ordered by position, for building the matcher.
+	ListEnabled(ctx context.Context) ([]*Rule, error)
+	IncrementHits(ctx context.Context, id string, hits int64, at time.Time) error
+}
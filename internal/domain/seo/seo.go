@@ -0,0 +1,128 @@
+package seo
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a content entry has no stored SEO metadata.
+var ErrNotFound = errors.New("seo metadata not found")
+
+// Metadata holds the search-engine settings editors set on a content entry. Blank fields fall
+// back to defaults derived from the entry when tags are resolved.
+type Metadata struct {
+	ContentID       string `json:"content_id"`
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	// CanonicalURL is an absolute URL or a site-relative path. It defaults to the entry's permalink.
+	CanonicalURL string `json:"canonical_url"`
+	// NoIndex keeps the entry out of search engines and the sitemap.
+	NoIndex bool `json:"noindex"`
+	// OGImageID is the media item shared as the Open Graph image.
+	OGImageID string    `json:"og_image_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Tags are the resolved values a page head renders for a content entry. URLs are absolute.
+type Tags struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Canonical   string    `json:"canonical"`
+	Robots      string    `json:"robots"`
+	OpenGraph   OpenGraph `json:"open_graph"`
+}
+
+// OpenGraph holds the og:* properties of a page.
+type OpenGraph struct {
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Image       string `json:"image,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+	Locale      string `json:"locale"`
+}
+
+// SitemapEntry is a published, indexable content entry listed in the sitemap.
+type SitemapEntry struct {
+	ContentID   string
+	Type        string
+	Slug        string
+	Locale      string
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+}
+
+// SitemapURL is a <url> of a sitemap.
+type SitemapURL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// Sitemap is either a URL set or, when the site has more URLs than fit in one sitemap, an index
+// of the sitemap pages listed in Pages.
+type Sitemap struct {
+	URLs  []SitemapURL
+	Pages []string
+}
+
+// sitemapNamespace is the XML namespace of the sitemap protocol.
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// XML encodes the sitemap as a sitemap protocol document, with its XML declaration: a sitemap index
+// when Pages is set, a URL set otherwise.
+func (s *Sitemap) XML() ([]byte, error) {
+	var doc interface{}
+	if len(s.Pages) > 0 {
+		index := sitemapIndex{XMLNS: sitemapNamespace, Sitemaps: make([]sitemapURL, len(s.Pages))}
+		for i, loc := range s.Pages {
+			index.Sitemaps[i] = sitemapURL{Loc: loc}
+		}
+		doc = index
+	} else {
+		set := sitemapURLSet{XMLNS: sitemapNamespace, URLs: make([]sitemapURL, len(s.URLs))}
+		for i, u := range s.URLs {
+			set.URLs[i] = sitemapURL{Loc: u.Loc}
+			if !u.LastMod.IsZero() {
+				set.URLs[i].LastMod = u.LastMod.UTC().Format(time.RFC3339)
+			}
+		}
+		doc = set
+	}
+
+	body, err := xml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// Repository abstracts the data source that stores SEO metadata.
+type Repository interface {
+	Get(ctx context.Context, contentID string) (*Metadata, error)
+	// Save inserts or replaces the metadata of m.ContentID.
+	Save(ctx context.Context, m *Metadata) error
+	// CountSitemapEntries counts the entries published at now that are not marked noindex.
+	CountSitemapEntries(ctx context.Context, now time.Time) (int, error)
+	// ListSitemapEntries lists the entries counted by CountSitemapEntries, oldest first.
+	ListSitemapEntries(ctx context.Context, now time.Time, limit, offset int) ([]*SitemapEntry, error)
+}
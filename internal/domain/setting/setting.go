@@ -0,0 +1,31 @@
+package setting
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Keys of the site settings.
+const (
+	// KeyTheme names the theme the public site is rendered with.
+	KeyTheme = "theme"
+)
+
+// ErrNotFound is returned when a setting has never been saved.
+var ErrNotFound = errors.New("setting not found")
+
+// Setting is a site-wide setting changed at runtime by administrators, unlike the configuration
+// read from the environment at startup.
+type Setting struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Repository abstracts the data source that stores settings.
+type Repository interface {
+	Get(ctx context.Context, key string) (*Setting, error)
+	// Save inserts the setting or replaces its stored value.
+	Save(ctx context.Context, s *Setting) error
+}
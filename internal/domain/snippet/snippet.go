@@ -0,0 +1,153 @@
+// Package snippet models reusable blocks of content such as footers, disclaimers or promo banners.
+// Content bodies reference a snippet by name with a {{snippet:name}} token, which is replaced by the
+// current HTML of the snippet whenever the entry is read for the public site. Tokens inside code, such
+// as a fenced or inline markdown code sample documenting the syntax, are left as written.
+package snippet
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+)
+
+// BlockType is the block type referencing a snippet from a structured body.
+const BlockType = "snippet"
+
+// ErrNotFound is returned when a snippet does not exist.
+var ErrNotFound = errors.New("snippet not found")
+
+// nameExpr is the expression snippet names must match: lower-case letters, digits, dashes or
+// underscores.
+const nameExpr = `[a-z0-9][a-z0-9_-]{0,63}`
+
+var (
+	namePattern = regexp.MustCompile(`^` + nameExpr + `$`)
+	// referencePattern matches a reference token, alone in a paragraph or inline. A paragraph holding
+	// nothing but the token is replaced as a whole since snippets usually render block elements.
+	referencePattern = regexp.MustCompile(`<p>\s*\{\{snippet:(` + nameExpr + `)\}\}\s*</p>|\{\{snippet:(` + nameExpr + `)\}\}`)
+	// codePattern matches pre and code elements, which markdown renders fenced and inline code to.
+	// Their text is shown as written, so tokens in them are not references.
+	codePattern = regexp.MustCompile(`(?is)<pre\b.*?</pre\s*>|<code\b.*?</code\s*>`)
+)
+
+// Snippet is a named block of content shared by many entries. Body is written in BodyFormat
+// (markdown, html or blocks, like content bodies); BodyHTML holds the sanitized HTML rendered from
+// it when the snippet was last saved.
+type Snippet struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Title      string        `json:"title"`
+	Body       string        `json:"body"`
+	BodyFormat string        `json:"body_format"`
+	BodyHTML   string        `json:"body_html"`
+	Blocks     []block.Block `json:"blocks,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	Version    int           `json:"version"`
+	// UsageCount is the number of entries referencing the snippet, filled in when listing.
+	UsageCount int `json:"usage_count"`
+}
+
+// Usage is an entry whose body references a snippet.
+type Usage struct {
+	ContentID string `json:"content_id"`
+	Type      string `json:"type"`
+	Locale    string `json:"locale"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+}
+
+// ValidName reports whether name can be referenced from content bodies.
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Reference returns the token referencing the named snippet from a content body.
+func Reference(name string) string {
+	return "{{snippet:" + name + "}}"
+}
+
+// References lists the names of the snippets referenced by html, each once, in order.
+func References(html string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	outsideCode(html, func(text string) string {
+		for _, m := range referencePattern.FindAllStringSubmatch(text, -1) {
+			name := m[1] + m[2]
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		return text
+	})
+	return names
+}
+
+// Expand replaces the references in html with the HTML of the snippets they name. References to
+// missing snippets render nothing.
+func Expand(html string, snippets map[string]*Snippet) string {
+	return outsideCode(html, func(text string) string {
+		return referencePattern.ReplaceAllStringFunc(text, func(token string) string {
+			m := referencePattern.FindStringSubmatch(token)
+			if s, ok := snippets[m[1]+m[2]]; ok {
+				return s.BodyHTML
+			}
+			return ""
+		})
+	})
+}
+
+// outsideCode replaces each stretch of html outside pre and code elements with what f returns for
+// it, keeping the code elements as they are.
+func outsideCode(html string, f func(text string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range codePattern.FindAllStringIndex(html, -1) {
+		b.WriteString(f(html[last:loc[0]]))
+		b.WriteString(html[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(f(html[last:]))
+	return b.String()
+}
+
+// BlockDefinition describes the block type referencing a snippet, which renders to its token.
+func BlockDefinition() block.Definition {
+	type reference struct {
+		Name string `json:"name"`
+	}
+	return block.Typed(BlockType, `{
+		"type": "object",
+		"properties": {"name": {"type": "string", "pattern": "^`+nameExpr+`$"}},
+		"required": ["name"],
+		"additionalProperties": false
+	}`, func(r reference) (string, error) {
+		return Reference(r.Name), nil
+	}, func(reference) string {
+		return ""
+	})
+}
+
+// Repository abstracts the data source that stores snippets.
+type Repository interface {
+	Create(ctx context.Context, s *Snippet) error
+	GetByID(ctx context.Context, id string) (*Snippet, error)
+	GetByName(ctx context.Context, name string) (*Snippet, error)
+	// ListByNames returns the snippets with the given names that exist.
+	ListByNames(ctx context.Context, names []string) ([]*Snippet, error)
+	// Update saves s if it is still at s.Version, then increments the version.
+	Update(ctx context.Context, s *Snippet) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, limit, offset int) ([]*Snippet, error)
+	// ListUsages returns the entries outside the trash whose body references the named snippet.
+	// References are recorded with References when an entry is saved, so tokens in code do not count.
+	ListUsages(ctx context.Context, name string, limit, offset int) ([]*Usage, error)
+	// CountUsages returns the number of entries outside the trash referencing each named snippet,
+	// from the same recorded references as ListUsages.
+	CountUsages(ctx context.Context, names []string) (map[string]int, error)
+}
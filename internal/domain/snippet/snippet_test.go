@@ -0,0 +1,49 @@
+package snippet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferences(t *testing.T) {
+	html := `<p>{{snippet:promo}}</p><p>Read the {{snippet:disclaimer}} and {{snippet:promo}}.</p>{{snippet:Bad}}`
+	assert.Equal(t, []string{"promo", "disclaimer"}, References(html))
+	assert.Nil(t, References("<p>No snippets</p>"))
+}
+
+func TestExpand(t *testing.T) {
+	snippets := map[string]*Snippet{
+		"promo":      {Name: "promo", BodyHTML: `<aside>Sale!</aside>`},
+		"disclaimer": {Name: "disclaimer", BodyHTML: `fine print`},
+	}
+	html := "<p>Intro</p>\n<p> {{snippet:promo}} </p>\n<p>See the {{snippet:disclaimer}}.</p>{{snippet:gone}}"
+	assert.Equal(t, "<p>Intro</p>\n<aside>Sale!</aside>\n<p>See the fine print.</p>", Expand(html, snippets))
+}
+
+func TestExpand_SkipsCode(t *testing.T) {
+	snippets := map[string]*Snippet{"promo": {Name: "promo", BodyHTML: `<aside>Sale!</aside>`}}
+	html := "<p>Write <code>{{snippet:promo}}</code> to show:</p>\n" +
+		"<pre class=\"language-html\"><code>&lt;p&gt;\n{{snippet:promo}}\n</code></pre>\n" +
+		"<p>{{snippet:promo}}</p>"
+	assert.Equal(t, "<p>Write <code>{{snippet:promo}}</code> to show:</p>\n"+
+		"<pre class=\"language-html\"><code>&lt;p&gt;\n{{snippet:promo}}\n</code></pre>\n"+
+		"<aside>Sale!</aside>", Expand(html, snippets))
+
+	assert.Nil(t, References("<p><code>{{snippet:promo}}</code></p><pre>{{snippet:footer}}</pre>"))
+}
+
+func TestBlockDefinition(t *testing.T) {
+	registry := block.NewRegistry()
+	require.NoError(t, registry.Register(BlockDefinition()))
+
+	html, err := registry.RenderHTML([]block.Block{{Type: BlockType, Data: json.RawMessage(`{"name":"footer"}`)}})
+	require.NoError(t, err)
+	assert.Equal(t, Reference("footer"), html)
+
+	err = registry.Validate([]block.Block{{Type: BlockType, Data: json.RawMessage(`{"name":"Not Valid"}`)}})
+	assert.ErrorIs(t, err, block.ErrInvalidData)
+}
@@ -0,0 +1,37 @@
+package static
+
+import "time"
+
+// ManifestPath is where the manifest is written in the output directory.
+const ManifestPath = "manifest.json"
+
+// Sources of generated files other than content entries and media items, whose sources are
+// their IDs prefixed with SourceContent and SourceMedia.
+const (
+	SourceContent = "content:"
+	SourceMedia   = "media:"
+	SourceIndex   = "index"
+	SourceFeed    = "feed"
+	SourceSitemap = "sitemap"
+	SourceRobots  = "robots"
+)
+
+// Manifest lists the files of a static export. The next export reads it back to rebuild only the
+// entries and media that changed and to remove the files nothing generates anymore.
+type Manifest struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	// Fingerprint identifies the templates the pages were rendered with.
+	Fingerprint string `json:"fingerprint"`
+	Files       []File `json:"files"`
+}
+
+// File is a generated file. Revision is the revision of its source when it has one, and Media
+// lists the media items a page links to.
+type File struct {
+	Path     string   `json:"path"`
+	Size     int64    `json:"size"`
+	SHA256   string   `json:"sha256"`
+	Source   string   `json:"source"`
+	Revision string   `json:"revision,omitempty"`
+	Media    []string `json:"media,omitempty"`
+}
@@ -0,0 +1,42 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+)
+
+// ErrNotFound is returned when a tag does not exist.
+var ErrNotFound = errors.New("tag not found")
+
+// Tag is a flat, free-form label attached to content.
+type Tag struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int       `json:"version"`
+}
+
+// Repository abstracts the data source that stores tags and their content assignments.
+type Repository interface {
+	Create(ctx context.Context, t *Tag) error
+	GetByID(ctx context.Context, id string) (*Tag, error)
+	GetBySlug(ctx context.Context, slug string) (*Tag, error)
+	// SlugInUse reports whether a tag other than exceptID, trashed or not, holds the slug.
+	SlugInUse(ctx context.Context, slug, exceptID string) (bool, error)
+	Update(ctx context.Context, t *Tag) error
+	// Delete moves a tag to the trash.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, limit, offset int) ([]*Tag, error)
+	// Search returns tags whose name starts with the given prefix, for autocomplete.
+	Search(ctx context.Context, prefix string, limit int) ([]*Tag, error)
+	ListByContent(ctx context.Context, contentID string) ([]*Tag, error)
+	SetContentTags(ctx context.Context, contentID string, tagIDs []string) error
+	// Merge reassigns all content of the source tag to the target tag and removes the source.
+	Merge(ctx context.Context, sourceID, targetID string) error
+	trash.Bin
+}
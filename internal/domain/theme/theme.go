@@ -0,0 +1,89 @@
+package theme
+
+import (
+	"errors"
+	"io"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+)
+
+// Page templates. Themes must provide single and list; the others fall back along the template
+// hierarchy of the renderer, e.g. from taxonomy to list.
+const (
+	// TemplateSingle renders one content entry.
+	TemplateSingle = "single"
+	// TemplateList renders a list of entries.
+	TemplateList = "list"
+	// TemplateHome renders the home page, a list of the latest entries.
+	TemplateHome = "home"
+	// TemplateTaxonomy renders the entries of a category or a tag.
+	TemplateTaxonomy = "taxonomy"
+	// TemplateNotFound renders the page of paths nothing is published at.
+	TemplateNotFound = "404"
+)
+
+// DefaultTheme is the name of the built-in theme.
+const DefaultTheme = "default"
+
+// Term kinds.
+const (
+	TermCategory = "category"
+	TermTag      = "tag"
+)
+
+var (
+	// ErrNotFound is returned when loading a theme that does not exist.
+	ErrNotFound = errors.New("theme not found")
+	// ErrTemplateNotFound is returned when rendering a page template a theme does not have.
+	ErrTemplateNotFound = errors.New("template not found")
+)
+
+// Site describes the site pages are rendered for.
+type Site struct {
+	Title string
+	// URL is the absolute URL of the public site, without a trailing slash.
+	URL string
+}
+
+// Term is the category or tag a taxonomy page lists the entries of.
+type Term struct {
+	Kind        string
+	Name        string
+	Slug        string
+	Description string
+}
+
+// Page is the data templates are executed with. Entry, with its Categories and Tags, is set on
+// single pages; Entries on lists, along with Term on taxonomy pages. PrevPage and NextPage number
+// the neighbouring pages of a list, zero when there is none.
+type Page struct {
+	Site       Site
+	Title      string
+	Path       string
+	Locale     string
+	Entry      *content.Content
+	Categories []*category.Category
+	Tags       []*tag.Tag
+	Entries    []*content.Content
+	Term       *Term
+	PrevPage   int
+	NextPage   int
+}
+
+// Renderer renders pages with the templates of a theme.
+type Renderer interface {
+	// Render executes the named page template with page and writes the HTML to w.
+	Render(w io.Writer, template string, page *Page) error
+	// Fingerprint changes whenever the templates change, so rendered pages can be told stale.
+	Fingerprint() string
+}
+
+// Loader finds the installed themes.
+type Loader interface {
+	// List returns the names of the installed themes, the built-in theme included.
+	List() ([]string, error)
+	// Load parses the named theme, or returns ErrNotFound.
+	Load(name string) (Renderer, error)
+}
@@ -0,0 +1,47 @@
+package transfer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+)
+
+// FormatVersion is the version of the bundles written by exports. Imports read bundles of this
+// version and older.
+const FormatVersion = 1
+
+// ErrUnsupportedVersion is returned when importing a bundle written by a newer format version.
+var ErrUnsupportedVersion = errors.New("unsupported bundle version")
+
+// Bundle is a portable copy of a site: its content with their taxonomies, the media metadata and
+// the users. Password hashes are never part of a bundle, and media bytes travel separately from the
+// storage directory. Items reference each other by the IDs they had on the exporting site.
+type Bundle struct {
+	Version    int                  `json:"version"`
+	ExportedAt time.Time            `json:"exported_at"`
+	Users      []*user.User         `json:"users"`
+	Categories []*category.Category `json:"categories"`
+	Tags       []*tag.Tag           `json:"tags"`
+	Media      []*Media             `json:"media"`
+	Contents   []*Content           `json:"contents"`
+}
+
+// Media is the metadata of a media item along with the storage key of its bytes.
+type Media struct {
+	media.Media
+	StorageKey string `json:"storage_key"`
+}
+
+// Content is a content entry along with the categories and tags it is assigned to.
+type Content struct {
+	content.Content
+	CategoryIDs []string `json:"category_ids"`
+	TagIDs      []string `json:"tag_ids"`
+	// PasswordHash carries the hash of a password-protected entry, which the API never shows.
+	PasswordHash string `json:"password_hash,omitempty"`
+}
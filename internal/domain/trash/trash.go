@@ -0,0 +1,53 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when an item is not in the trash.
+var ErrNotFound = errors.New("item not found in trash")
+
+// Resource types that are moved to the trash when deleted.
+const (
+	TypeContent  = "content"
+	TypeEntry    = "entry"
+	TypeCategory = "category"
+	TypeTag      = "tag"
+	TypeMenu     = "menu"
+	TypeComment  = "comment"
+	TypeMedia    = "media"
+	TypeUser     = "user"
+)
+
+// Types lists the resource types that have a trash.
+var Types = []string{TypeContent, TypeEntry, TypeCategory, TypeTag, TypeMenu, TypeComment, TypeMedia, TypeUser}
+
+// Item is a deleted resource waiting in the trash to be restored or purged.
+type Item struct {
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// DeletedAt is when the item was moved to the trash.
+	DeletedAt time.Time `json:"deleted_at"`
+	// PurgeAt is when the item is deleted permanently unless it is restored first.
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// ListFilter narrows down the items returned by Bin.ListTrashed.
+type ListFilter struct {
+	// DeletedBefore, when set, keeps the items trashed before the given time.
+	DeletedBefore time.Time
+	Limit         int
+	Offset        int
+}
+
+// Bin holds the trashed items of one resource type. Items are listed most recently deleted first.
+type Bin interface {
+	ListTrashed(ctx context.Context, filter ListFilter) ([]*Item, error)
+	// Restore takes an item out of the trash.
+	Restore(ctx context.Context, id string) error
+	// Purge deletes a trashed item permanently.
+	Purge(ctx context.Context, id string) error
+}
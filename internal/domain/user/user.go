@@ -3,6 +3,15 @@ package user
 import (
 	"context"
 	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+)
+
+// Roles every installation knows. Other roles may be assigned freely, e.g. to restrict content.
+const (
+	RoleUser = "user"
+	// RoleAdmin may manage other users.
+	RoleAdmin = "admin"
 )
 
 // User models the user data.
@@ -21,15 +30,19 @@ type User struct {
 	PhoneVerified bool      `json:"phone_verified"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
+	Version       int       `json:"version"`
 }
 
-// Repository abstracts the data source that stores user information.
+// Repository abstracts the data source that stores user information. Trashed users are left out of
+// everything but the trash methods.
 type Repository interface {
 	Create(ctx context.Context, user *User) error
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByID(ctx context.Context, id string) (*User, error)
 	GetByUsername(ctx context.Context, username string) (*User, error)
 	Update(ctx context.Context, user *User) error
+	// Delete moves a user to the trash.
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, limit, offset int) ([]*User, error)
+	trash.Bin
 }
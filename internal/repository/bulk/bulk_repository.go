@@ -0,0 +1,153 @@
+package bulk
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/bulk"
+)
+
+const selectColumns = `id, resource, action, params, dry_run, status, total, processed, succeeded, skipped, failed, results, error, created_by, created_at, started_at, finished_at`
+
+// BulkRepository implements bulk.Repository for MySQL. Params and results are stored as JSON.
+type BulkRepository struct {
+	db *sql.DB
+}
+
+// NewBulkRepository creates a new MySQL bulk job repository.
+func NewBulkRepository(db *sql.DB) bulk.Repository {
+	return &BulkRepository{db: db}
+}
+
+// Create inserts a new bulk job into the database.
+func (r *BulkRepository) Create(ctx context.Context, j *bulk.Job) error {
+	if j.ID == "" {
+		j.ID = uuid.New().String()
+	}
+	if j.CreatedAt.IsZero() {
+		j.CreatedAt = time.Now()
+	}
+	params, results, err := marshalJob(j)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO bulk_jobs (`+selectColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		j.ID, j.Resource, j.Action, params, j.DryRun, j.Status, j.Total, j.Processed, j.Succeeded, j.Skipped, j.Failed, results,
+		nullString(j.Error), nullString(j.CreatedBy), j.CreatedAt, nullTime(j.StartedAt), nullTime(j.FinishedAt),
+	)
+	return err
+}
+
+// GetByID retrieves a bulk job by ID.
+func (r *BulkRepository) GetByID(ctx context.Context, id string) (*bulk.Job, error) {
+	j, err := scanJob(r.db.QueryRowContext(ctx, `SELECT `+selectColumns+` FROM bulk_jobs WHERE id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, bulk.ErrNotFound
+		}
+		return nil, err
+	}
+	return j, nil
+}
+
+// Update saves the status, counters and results of a bulk job.
+func (r *BulkRepository) Update(ctx context.Context, j *bulk.Job) error {
+	_, results, err := marshalJob(j)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE bulk_jobs
+		SET status = ?, total = ?, processed = ?, succeeded = ?, skipped = ?, failed = ?, results = ?, error = ?, started_at = ?, finished_at = ?
+		WHERE id = ?`,
+		j.Status, j.Total, j.Processed, j.Succeeded, j.Skipped, j.Failed, results,
+		nullString(j.Error), nullTime(j.StartedAt), nullTime(j.FinishedAt), j.ID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return bulk.ErrNotFound
+	}
+	return nil
+}
+
+// FailUnfinished marks the pending and running jobs as failed.
+func (r *BulkRepository) FailUnfinished(ctx context.Context, reason string, finishedAt time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE bulk_jobs SET status = ?, error = ?, finished_at = ? WHERE status IN (?, ?)`,
+		bulk.StatusFailed, reason, finishedAt, bulk.StatusPending, bulk.StatusRunning,
+	)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	return int(affected), err
+}
+
+func marshalJob(j *bulk.Job) ([]byte, []byte, error) {
+	params, err := json.Marshal(j.Params)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := j.Results
+	if results == nil {
+		results = []bulk.Result{}
+	}
+	encoded, err := json.Marshal(results)
+	if err != nil {
+		return nil, nil, err
+	}
+	return params, encoded, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(s scanner) (*bulk.Job, error) {
+	j := &bulk.Job{}
+	var params, results []byte
+	var jobErr, createdBy sql.NullString
+	var startedAt, finishedAt sql.NullTime
+	if err := s.Scan(
+		&j.ID, &j.Resource, &j.Action, &params, &j.DryRun, &j.Status, &j.Total, &j.Processed, &j.Succeeded, &j.Skipped, &j.Failed,
+		&results, &jobErr, &createdBy, &j.CreatedAt, &startedAt, &finishedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(params, &j.Params); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(results, &j.Results); err != nil {
+		return nil, err
+	}
+	j.Error = jobErr.String
+	j.CreatedBy = createdBy.String
+	if startedAt.Valid {
+		j.StartedAt = startedAt.Time
+	}
+	if finishedAt.Valid {
+		j.FinishedAt = finishedAt.Time
+	}
+	return j, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
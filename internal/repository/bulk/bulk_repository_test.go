@@ -0,0 +1,109 @@
+package bulk
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/bulk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jobColumns = []string{
+	"id", "resource", "action", "params", "dry_run", "status", "total", "processed", "succeeded", "skipped", "failed",
+	"results", "error", "created_by", "created_at", "started_at", "finished_at",
+}
+
+func TestBulkRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewBulkRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO bulk_jobs")).
+		WithArgs(sqlmock.AnyArg(), "content", "add_tags", []byte(`{"tag_ids":["tag-1"]}`), true, "pending", 2, 0, 0, 0, 0, []byte(`[]`),
+			sql.NullString{}, sql.NullString{String: "user-1", Valid: true}, sqlmock.AnyArg(), sql.NullTime{}, sql.NullTime{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	j := &bulk.Job{
+		Resource: bulk.ResourceContent, Action: bulk.ActionAddTags, Params: bulk.Params{TagIDs: []string{"tag-1"}},
+		DryRun: true, Status: bulk.StatusPending, Total: 2, CreatedBy: "user-1",
+	}
+	require.NoError(t, repo.Create(context.Background(), j))
+	assert.NotEmpty(t, j.ID)
+	assert.False(t, j.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBulkRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewBulkRepository(db)
+
+	startedAt := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM bulk_jobs WHERE id = ?")).
+		WithArgs("job-1").
+		WillReturnRows(sqlmock.NewRows(jobColumns).AddRow(
+			"job-1", "user", "set_role", `{"role":"editor"}`, false, "running", 2, 1, 1, 0, 0,
+			`[{"id":"user-1","status":"succeeded"}]`, nil, "user-9", time.Now(), startedAt, nil,
+		))
+
+	j, err := repo.GetByID(context.Background(), "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, "editor", j.Params.Role)
+	assert.Equal(t, []bulk.Result{{ID: "user-1", Status: bulk.ResultSucceeded}}, j.Results)
+	assert.Equal(t, startedAt, j.StartedAt)
+	assert.True(t, j.FinishedAt.IsZero())
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM bulk_jobs WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, bulk.ErrNotFound)
+}
+
+func TestBulkRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewBulkRepository(db)
+
+	finishedAt := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE bulk_jobs")).
+		WithArgs("completed", 1, 1, 0, 0, 1, []byte(`[{"id":"post-1","status":"failed","error":"boom"}]`),
+			sql.NullString{}, sql.NullTime{}, sql.NullTime{Time: finishedAt, Valid: true}, "job-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	j := &bulk.Job{ID: "job-1", Status: bulk.StatusCompleted, Total: 1, FinishedAt: finishedAt}
+	j.Record(bulk.Result{ID: "post-1", Status: bulk.ResultFailed, Error: "boom"})
+	require.NoError(t, repo.Update(context.Background(), j))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE bulk_jobs")).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Update(context.Background(), &bulk.Job{ID: "missing"}), bulk.ErrNotFound)
+}
+
+func TestBulkRepository_FailUnfinished(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewBulkRepository(db)
+
+	finishedAt := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE bulk_jobs SET status = ?, error = ?, finished_at = ? WHERE status IN (?, ?)")).
+		WithArgs("failed", "interrupted", finishedAt, "pending", "running").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	n, err := repo.FailUnfinished(context.Background(), "interrupted", finishedAt)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
@@ -0,0 +1,276 @@
+package category
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/repository"
+)
+
+const selectColumns = `id, parent_id, name, slug, description, position, created_at, updated_at, version`
+
+// CategoryRepository implements category.Repository for MySQL.
+type CategoryRepository struct {
+	db *sql.DB
+}
+
+// NewCategoryRepository creates a new MySQL category repository.
+func NewCategoryRepository(db *sql.DB) category.Repository {
+	return &CategoryRepository{db: db}
+}
+
+// Create inserts a new category into the database.
+func (r *CategoryRepository) Create(ctx context.Context, c *category.Category) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = time.Now()
+	}
+
+	c.Version = 1
+
+	query := `
+		INSERT INTO categories (id, parent_id, name, slug, description, position, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		c.ID, nullString(c.ParentID), c.Name, c.Slug, c.Description, c.Position, c.CreatedAt, c.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a category by ID.
+func (r *CategoryRepository) GetByID(ctx context.Context, id string) (*category.Category, error) {
+	query := `SELECT ` + selectColumns + ` FROM categories WHERE id = ? AND deleted_at IS NULL`
+	return scanOne(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetBySlug retrieves a category by slug.
+func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*category.Category, error) {
+	query := `SELECT ` + selectColumns + ` FROM categories WHERE slug = ? AND deleted_at IS NULL`
+	return scanOne(r.db.QueryRowContext(ctx, query, slug))
+}
+
+// SlugInUse reports whether a category other than exceptID, trashed or not, holds the slug.
+func (r *CategoryRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM categories WHERE slug = ? AND id <> ?`, slug, exceptID,
+	).Scan(&count)
+	return count > 0, err
+}
+
+// Update updates an existing category if it is still at c.Version, then increments the version.
+func (r *CategoryRepository) Update(ctx context.Context, c *category.Category) error {
+	updatedAt := time.Now()
+	query := `
+		UPDATE categories
+		SET parent_id = ?, name = ?, slug = ?, description = ?, position = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+	res, err := r.db.ExecContext(ctx, query,
+		nullString(c.ParentID), c.Name, c.Slug, c.Description, c.Position, updatedAt, c.ID, c.Version,
+	)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByID(ctx, c.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	c.UpdatedAt = updatedAt
+	c.Version++
+	return nil
+}
+
+// Delete moves a category to the trash. Its children show up at the root until it is restored.
+func (r *CategoryRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE categories SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+// List retrieves all categories ordered by position and name.
+func (r *CategoryRepository) List(ctx context.Context) ([]*category.Category, error) {
+	query := `SELECT ` + selectColumns + ` FROM categories WHERE deleted_at IS NULL ORDER BY position, name`
+	return r.query(ctx, query)
+}
+
+// ListByContent retrieves the categories assigned to a content entry.
+func (r *CategoryRepository) ListByContent(ctx context.Context, contentID string) ([]*category.Category, error) {
+	query := `
+		SELECT c.id, c.parent_id, c.name, c.slug, c.description, c.position, c.created_at, c.updated_at, c.version
+		FROM categories c
+		JOIN content_categories cc ON cc.category_id = c.id
+		WHERE cc.content_id = ? AND c.deleted_at IS NULL
+		ORDER BY c.position, c.name
+	`
+	return r.query(ctx, query, contentID)
+}
+
+// SetContentCategories replaces the categories assigned to a content entry. Assignments to trashed
+// categories are kept so that restoring a category brings them back.
+func (r *CategoryRepository) SetContentCategories(ctx context.Context, contentID string, categoryIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM content_categories
+		WHERE content_id = ? AND category_id IN (SELECT id FROM categories WHERE deleted_at IS NULL)
+	`, contentID); err != nil {
+		return err
+	}
+	for _, categoryID := range categoryIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO content_categories (content_id, category_id) VALUES (?, ?)`, contentID, categoryID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListTrashed retrieves trashed categories, most recently deleted first.
+func (r *CategoryRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	query := `SELECT id, name, deleted_at FROM categories WHERE deleted_at IS NOT NULL`
+	var args []interface{}
+	if !filter.DeletedBefore.IsZero() {
+		query += ` AND deleted_at < ?`
+		args = append(args, filter.DeletedBefore)
+	}
+	query += ` ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*trash.Item
+	for rows.Next() {
+		item := &trash.Item{Type: trash.TypeCategory}
+		if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Restore takes a category out of the trash, back under its parent if the parent is still there.
+func (r *CategoryRepository) Restore(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE categories SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireTrashed(res)
+}
+
+// Purge permanently deletes a trashed category. Its children are re-parented to the root and its
+// content assignments are removed by the foreign keys.
+func (r *CategoryRepository) Purge(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM categories WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireTrashed(res)
+}
+
+func (r *CategoryRepository) query(ctx context.Context, query string, args ...interface{}) ([]*category.Category, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*category.Category
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOne(row *sql.Row) (*category.Category, error) {
+	c, err := scanCategory(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, category.ErrNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func scanCategory(s scanner) (*category.Category, error) {
+	c := &category.Category{}
+	var parentID, description sql.NullString
+
+	err := s.Scan(&c.ID, &parentID, &c.Name, &c.Slug, &description, &c.Position, &c.CreatedAt, &c.UpdatedAt, &c.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ParentID = parentID.String
+	c.Description = description.String
+
+	return c, nil
+}
+
+func requireAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return category.ErrNotFound
+	}
+	return nil
+}
+
+func requireTrashed(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return trash.ErrNotFound
+	}
+	return nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,221 @@
+package category
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var categoryColumns = []string{"id", "parent_id", "name", "slug", "description", "position", "created_at", "updated_at", "version"}
+
+func TestCategoryRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	c := &category.Category{ParentID: "parent", Name: "Local", Slug: "local"}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO categories")).
+		WithArgs(sqlmock.AnyArg(), sql.NullString{String: "parent", Valid: true}, c.Name, c.Slug, c.Description, c.Position, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.Create(context.Background(), c))
+	assert.NotEmpty(t, c.ID)
+}
+
+func TestCategoryRepository_GetBySlug_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, parent_id, name, slug")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	c, err := repo.GetBySlug(context.Background(), "missing")
+	assert.ErrorIs(t, err, category.ErrNotFound)
+	assert.Nil(t, c)
+}
+
+func TestCategoryRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(categoryColumns).
+		AddRow("news", nil, "News", "news", nil, 0, now, now, 1).
+		AddRow("local", "news", "Local", "local", "Local news", 1, now, now, 2)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM categories WHERE deleted_at IS NULL ORDER BY position, name")).
+		WillReturnRows(rows)
+
+	categories, err := repo.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	assert.Empty(t, categories[0].ParentID)
+	assert.Equal(t, "news", categories[1].ParentID)
+	assert.Equal(t, "Local news", categories[1].Description)
+}
+
+func TestCategoryRepository_Update_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE categories")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM categories WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	err = repo.Update(context.Background(), &category.Category{ID: "missing"})
+	assert.ErrorIs(t, err, category.ErrNotFound)
+}
+
+func TestCategoryRepository_Update_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("WHERE id = ? AND version = ?")).
+		WithArgs(nil, "News", "news", "", 0, sqlmock.AnyArg(), "news", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM categories WHERE id = ?")).
+		WithArgs("news").
+		WillReturnRows(sqlmock.NewRows(categoryColumns).AddRow("news", nil, "News", "news", nil, 0, now, now, 2))
+
+	c := &category.Category{ID: "news", Name: "News", Slug: "news", Version: 1}
+	err = repo.Update(context.Background(), c)
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
+	assert.Equal(t, 1, c.Version)
+}
+
+func TestCategoryRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE categories SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), "news").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), "news"), category.ErrNotFound)
+}
+
+func TestCategoryRepository_SlugInUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM categories WHERE slug = ? AND id <> ?")).
+		WithArgs("news", "local").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	inUse, err := repo.SlugInUse(context.Background(), "news", "local")
+	require.NoError(t, err)
+	assert.True(t, inUse)
+}
+
+func TestCategoryRepository_ListTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM categories WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?")).
+		WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deleted_at"}).AddRow("news", "News", time.Now()))
+
+	items, err := repo.ListTrashed(context.Background(), trash.ListFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, trash.TypeCategory, items[0].Type)
+	assert.Equal(t, "News", items[0].Title)
+}
+
+func TestCategoryRepository_RestoreAndPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE categories SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("news").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Restore(context.Background(), "news"))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM categories WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("news").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Purge(context.Background(), "news"), trash.ErrNotFound)
+}
+
+func TestCategoryRepository_SetContentCategories(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("WHERE content_id = ? AND category_id IN (SELECT id FROM categories WHERE deleted_at IS NULL)")).
+		WithArgs("content-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO content_categories")).
+		WithArgs("content-1", "news").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO content_categories")).
+		WithArgs("content-1", "local").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.SetContentCategories(context.Background(), "content-1", []string{"news", "local"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_SetContentCategories_RollsBackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM content_categories")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO content_categories")).
+		WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	err = repo.SetContentCategories(context.Background(), "content-1", []string{"missing"})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,318 @@
+package comment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/comment"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+)
+
+const selectColumns = `id, content_id, parent_id, author_id, author_name, author_email, body, status, spam_score, ip, user_agent, created_at, updated_at`
+
+// CommentRepository implements comment.Repository for MySQL.
+type CommentRepository struct {
+	db *sql.DB
+}
+
+// NewCommentRepository creates a new MySQL comment repository.
+func NewCommentRepository(db *sql.DB) comment.Repository {
+	return &CommentRepository{db: db}
+}
+
+// Create inserts a new comment into the database.
+func (r *CommentRepository) Create(ctx context.Context, c *comment.Comment) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO comments (
+			id, content_id, parent_id, author_id, author_name, author_email, body, status, spam_score, ip, user_agent, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		c.ID, c.ContentID, nullString(c.ParentID), nullString(c.AuthorID), c.AuthorName, nullString(c.AuthorEmail), c.Body, c.Status, c.SpamScore, nullString(c.IP), nullString(c.UserAgent), c.CreatedAt, c.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a comment by ID.
+func (r *CommentRepository) GetByID(ctx context.Context, id string) (*comment.Comment, error) {
+	query := `SELECT ` + selectColumns + ` FROM comments WHERE id = ? AND deleted_at IS NULL`
+	c, err := scanComment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, comment.ErrNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// List retrieves comments matching the filter. A content's thread is read in posting order,
+// the moderation queue newest first.
+func (r *CommentRepository) List(ctx context.Context, filter comment.ListFilter) ([]*comment.Comment, error) {
+	var (
+		conditions = []string{"deleted_at IS NULL"}
+		args       []interface{}
+	)
+
+	if filter.ContentID != "" {
+		conditions = append(conditions, "content_id = ?")
+		args = append(args, filter.ContentID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	query := `SELECT ` + selectColumns + ` FROM comments WHERE ` + strings.Join(conditions, " AND ")
+	if filter.ContentID != "" {
+		query += ` ORDER BY created_at ASC`
+	} else {
+		query += ` ORDER BY created_at DESC`
+	}
+	query += ` LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*comment.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// SetStatus moves the given comments to status.
+func (r *CommentRepository) SetStatus(ctx context.Context, ids []string, status string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	placeholders, args := inClause(ids)
+	args = append([]interface{}{status, time.Now()}, args...)
+	res, err := r.db.ExecContext(ctx, `UPDATE comments SET status = ?, updated_at = ? WHERE id IN (`+placeholders+`) AND deleted_at IS NULL`, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Delete moves the given comments to the trash together with their replies. Only the given comments
+// are counted.
+func (r *CommentRepository) Delete(ctx context.Context, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	deletedAt := time.Now()
+	placeholders, args := inClause(ids)
+	res, err := tx.ExecContext(ctx, `UPDATE comments SET deleted_at = ? WHERE id IN (`+placeholders+`) AND deleted_at IS NULL`,
+		append([]interface{}{deletedAt}, args...)...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	// Replies are trashed one level at a time, at the same time as their parent so that they are
+	// restored with it.
+	parents := ids
+	for len(parents) > 0 {
+		placeholders, args := inClause(parents)
+		replies, err := queryIDs(ctx, tx, `SELECT id FROM comments WHERE parent_id IN (`+placeholders+`) AND deleted_at IS NULL`, args...)
+		if err != nil {
+			return 0, err
+		}
+		if len(replies) > 0 {
+			placeholders, args := inClause(replies)
+			if _, err := tx.ExecContext(ctx, `UPDATE comments SET deleted_at = ? WHERE id IN (`+placeholders+`)`,
+				append([]interface{}{deletedAt}, args...)...,
+			); err != nil {
+				return 0, err
+			}
+		}
+		parents = replies
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// ListTrashed retrieves trashed comments, most recently deleted first. Replies trashed along with
+// their parent are not listed: they follow the parent.
+func (r *CommentRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	query := `SELECT id, LEFT(body, 80), deleted_at FROM comments WHERE deleted_at IS NOT NULL
+		AND (parent_id IS NULL OR parent_id IN (SELECT id FROM comments WHERE deleted_at IS NULL))`
+	var args []interface{}
+	if !filter.DeletedBefore.IsZero() {
+		query += ` AND deleted_at < ?`
+		args = append(args, filter.DeletedBefore)
+	}
+	query += ` ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*trash.Item
+	for rows.Next() {
+		item := &trash.Item{Type: trash.TypeComment}
+		if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Restore takes a comment out of the trash together with the replies trashed along with it.
+func (r *CommentRepository) Restore(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var deletedAt time.Time
+	err = tx.QueryRowContext(ctx, `SELECT deleted_at FROM comments WHERE id = ? AND deleted_at IS NOT NULL`, id).Scan(&deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return trash.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	for ids := []string{id}; len(ids) > 0; {
+		placeholders, args := inClause(ids)
+		if _, err := tx.ExecContext(ctx, `UPDATE comments SET deleted_at = NULL WHERE id IN (`+placeholders+`)`, args...); err != nil {
+			return err
+		}
+		if ids, err = queryIDs(ctx, tx, `SELECT id FROM comments WHERE parent_id IN (`+placeholders+`) AND deleted_at = ?`,
+			append(args, deletedAt)...,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Purge permanently deletes a trashed comment; replies go with it through the parent foreign key.
+func (r *CommentRepository) Purge(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM comments WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return trash.ErrNotFound
+	}
+	return nil
+}
+
+// CountRecentByIP counts the comments submitted from ip since the given time, trashed ones included.
+func (r *CommentRepository) CountRecentByIP(ctx context.Context, ip string, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM comments WHERE ip = ? AND created_at >= ?`, ip, since).Scan(&count)
+	return count, err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanComment(s scanner) (*comment.Comment, error) {
+	c := &comment.Comment{}
+	var parentID, authorID, authorEmail, ip, userAgent sql.NullString
+
+	err := s.Scan(
+		&c.ID, &c.ContentID, &parentID, &authorID, &c.AuthorName, &authorEmail, &c.Body, &c.Status, &c.SpamScore, &ip, &userAgent, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ParentID = parentID.String
+	c.AuthorID = authorID.String
+	c.AuthorEmail = authorEmail.String
+	c.IP = ip.String
+	c.UserAgent = userAgent.String
+
+	return c, nil
+}
+
+func queryIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func inClause(ids []string) (string, []interface{}) {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","), args
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,199 @@
+package comment
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/comment"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var commentColumns = []string{"id", "content_id", "parent_id", "author_id", "author_name", "author_email", "body", "status", "spam_score", "ip", "user_agent", "created_at", "updated_at"}
+
+func TestCommentRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCommentRepository(db)
+
+	c := &comment.Comment{ContentID: "content-1", AuthorName: "Ana", AuthorEmail: "ana@example.com", Body: "Nice post", Status: comment.StatusPending, IP: "10.0.0.1"}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO comments")).
+		WithArgs(sqlmock.AnyArg(), "content-1", sql.NullString{}, sql.NullString{}, "Ana", sql.NullString{String: "ana@example.com", Valid: true}, "Nice post", "pending", 0, sql.NullString{String: "10.0.0.1", Valid: true}, sql.NullString{}, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.Create(context.Background(), c))
+	assert.NotEmpty(t, c.ID)
+}
+
+func TestCommentRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCommentRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM comments WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, comment.ErrNotFound)
+}
+
+func TestCommentRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCommentRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(commentColumns).
+		AddRow("c-1", "content-1", nil, "user-1", "Budi", nil, "First", "approved", 0, nil, nil, now, now).
+		AddRow("c-2", "content-1", "c-1", nil, "Ana", "ana@example.com", "Reply", "approved", 0, "10.0.0.1", "curl", now, now)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM comments WHERE deleted_at IS NULL AND content_id = ? AND status = ? ORDER BY created_at ASC LIMIT ? OFFSET ?")).
+		WithArgs("content-1", "approved", 50, 0).
+		WillReturnRows(rows)
+
+	comments, err := repo.List(context.Background(), comment.ListFilter{ContentID: "content-1", Status: comment.StatusApproved, Limit: 50})
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, "user-1", comments[0].AuthorID)
+	assert.Empty(t, comments[0].ParentID)
+	assert.Equal(t, "c-1", comments[1].ParentID)
+	assert.Equal(t, "10.0.0.1", comments[1].IP)
+}
+
+func TestCommentRepository_SetStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCommentRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE comments SET status = ?, updated_at = ? WHERE id IN (?,?) AND deleted_at IS NULL")).
+		WithArgs("spam", sqlmock.AnyArg(), "c-1", "c-2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	affected, err := repo.SetStatus(context.Background(), []string{"c-1", "c-2"}, comment.StatusSpam)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+}
+
+func TestCommentRepository_Delete_TrashesReplies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCommentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE comments SET deleted_at = ? WHERE id IN (?) AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), "c-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM comments WHERE parent_id IN (?) AND deleted_at IS NULL")).
+		WithArgs("c-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("c-2").AddRow("c-3"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE comments SET deleted_at = ? WHERE id IN (?,?)")).
+		WithArgs(sqlmock.AnyArg(), "c-2", "c-3").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM comments WHERE parent_id IN (?,?) AND deleted_at IS NULL")).
+		WithArgs("c-2", "c-3").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	affected, err := repo.Delete(context.Background(), []string{"c-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_ListTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCommentRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("AND (parent_id IS NULL OR parent_id IN (SELECT id FROM comments WHERE deleted_at IS NULL)) ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?")).
+		WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "body", "deleted_at"}).AddRow("c-1", "Nice post", time.Now()))
+
+	items, err := repo.ListTrashed(context.Background(), trash.ListFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, trash.TypeComment, items[0].Type)
+	assert.Equal(t, "Nice post", items[0].Title)
+}
+
+func TestCommentRepository_Restore_RestoresReplies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCommentRepository(db)
+
+	deletedAt := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT deleted_at FROM comments WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("c-1").
+		WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE comments SET deleted_at = NULL WHERE id IN (?)")).
+		WithArgs("c-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM comments WHERE parent_id IN (?) AND deleted_at = ?")).
+		WithArgs("c-1", deletedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("c-2"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE comments SET deleted_at = NULL WHERE id IN (?)")).
+		WithArgs("c-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM comments WHERE parent_id IN (?) AND deleted_at = ?")).
+		WithArgs("c-2", deletedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Restore(context.Background(), "c-1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_Restore_NotTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCommentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT deleted_at FROM comments WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("c-1").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, repo.Restore(context.Background(), "c-1"), trash.ErrNotFound)
+}
+
+func TestCommentRepository_CountRecentByIP(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCommentRepository(db)
+
+	since := time.Now().Add(-10 * time.Minute)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM comments WHERE ip = ? AND created_at >= ?")).
+		WithArgs("10.0.0.1", since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := repo.CountRecentByIP(context.Background(), "10.0.0.1", since)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
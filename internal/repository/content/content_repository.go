@@ -0,0 +1,480 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/snippet"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/repository"
+)
+
+const selectColumns = `id, type, locale, translation_of, title, slug, excerpt, body, body_format, body_html, blocks, status, visibility, allowed_roles, password_hash, teaser, author_id, published_at, created_at, updated_at, version`
+
+// ContentRepository implements content.Repository for MySQL.
+type ContentRepository struct {
+	db *sql.DB
+}
+
+// NewContentRepository creates a new MySQL content repository.
+func NewContentRepository(db *sql.DB) content.Repository {
+	return &ContentRepository{db: db}
+}
+
+// Create inserts a new content entry into the database along with the snippets its body references.
+func (r *ContentRepository) Create(ctx context.Context, c *content.Content) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = time.Now()
+	}
+	c.Version = 1
+
+	query := `
+		INSERT INTO contents (
+			id, type, locale, translation_of, title, slug, excerpt, body, body_format, body_html, blocks, status, visibility, allowed_roles, password_hash, teaser, author_id, published_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	blocks, err := marshalBlocks(c.Blocks)
+	if err != nil {
+		return err
+	}
+	roles, err := marshalRoles(c.AllowedRoles)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query,
+		c.ID, c.Type, c.Locale, nullString(c.TranslationOf), c.Title, c.Slug, c.Excerpt, c.Body, c.BodyFormat, c.BodyHTML, blocks, c.Status, visibility(c), roles, nullString(c.PasswordHash), c.Teaser, nullString(c.AuthorID), nullTime(c.PublishedAt), c.CreatedAt, c.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if err := replaceSnippets(ctx, tx, c); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetByID retrieves a content entry by ID.
+func (r *ContentRepository) GetByID(ctx context.Context, id string) (*content.Content, error) {
+	query := `SELECT ` + selectColumns + ` FROM contents WHERE id = ? AND deleted_at IS NULL`
+	return scanOne(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetBySlug retrieves a content entry by its type, locale and slug.
+func (r *ContentRepository) GetBySlug(ctx context.Context, contentType, locale, slug string) (*content.Content, error) {
+	query := `SELECT ` + selectColumns + ` FROM contents WHERE type = ? AND locale = ? AND slug = ? AND deleted_at IS NULL`
+	return scanOne(r.db.QueryRowContext(ctx, query, contentType, locale, slug))
+}
+
+// SlugInUse reports whether an entry other than exceptID, trashed or not, holds the slug.
+func (r *ContentRepository) SlugInUse(ctx context.Context, contentType, locale, slug, exceptID string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM contents WHERE type = ? AND locale = ? AND slug = ? AND id <> ?`,
+		contentType, locale, slug, exceptID,
+	).Scan(&count)
+	return count > 0, err
+}
+
+// Update updates an existing content entry and the snippets its body references if it is still at
+// c.Version, then increments the version.
+func (r *ContentRepository) Update(ctx context.Context, c *content.Content) error {
+	blocks, err := marshalBlocks(c.Blocks)
+	if err != nil {
+		return err
+	}
+	roles, err := marshalRoles(c.AllowedRoles)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := time.Now()
+	query := `
+		UPDATE contents
+		SET type = ?, locale = ?, translation_of = ?, title = ?, slug = ?, excerpt = ?, body = ?, body_format = ?, body_html = ?, blocks = ?, status = ?,
+			visibility = ?, allowed_roles = ?, password_hash = ?, teaser = ?, author_id = ?, published_at = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query,
+		c.Type, c.Locale, nullString(c.TranslationOf), c.Title, c.Slug, c.Excerpt, c.Body, c.BodyFormat, c.BodyHTML, blocks, c.Status,
+		visibility(c), roles, nullString(c.PasswordHash), c.Teaser, nullString(c.AuthorID), nullTime(c.PublishedAt), updatedAt, c.ID, c.Version,
+	)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByID(ctx, c.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := replaceSnippets(ctx, tx, c); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.UpdatedAt = updatedAt
+	c.Version++
+	return nil
+}
+
+// Delete moves a content entry to the trash. The translations of a source entry are trashed with it
+// and share its deletion time, which tells them apart from translations trashed on their own.
+func (r *ContentRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE contents SET deleted_at = ? WHERE (id = ? OR translation_of = ?) AND deleted_at IS NULL`,
+		time.Now(), id, id,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+// List retrieves content entries matching the filter, newest first.
+func (r *ContentRepository) List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	var (
+		conditions = []string{"deleted_at IS NULL"}
+		args       []interface{}
+	)
+
+	if filter.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, filter.Type)
+	}
+	if filter.Locale != "" {
+		conditions = append(conditions, "locale = ?")
+		args = append(args, filter.Locale)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.SourcesOnly {
+		conditions = append(conditions, "translation_of IS NULL")
+	}
+	if len(filter.MissingLocales) > 0 {
+		missing := make([]string, len(filter.MissingLocales))
+		for i, locale := range filter.MissingLocales {
+			missing[i] = "(locale <> ? AND id NOT IN (SELECT translation_of FROM contents WHERE translation_of IS NOT NULL AND locale = ? AND deleted_at IS NULL))"
+			args = append(args, locale, locale)
+		}
+		conditions = append(conditions, "translation_of IS NULL", "("+strings.Join(missing, " OR ")+")")
+	}
+	if !filter.PublishedBefore.IsZero() {
+		conditions = append(conditions, "published_at <= ?")
+		args = append(args, filter.PublishedBefore)
+	}
+	if filter.AuthorID != "" {
+		conditions = append(conditions, "author_id = ?")
+		args = append(args, filter.AuthorID)
+	}
+	if len(filter.CategoryIDs) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(filter.CategoryIDs)), ",")
+		conditions = append(conditions, "id IN (SELECT content_id FROM content_categories WHERE category_id IN ("+placeholders+"))")
+		for _, id := range filter.CategoryIDs {
+			args = append(args, id)
+		}
+	}
+	if filter.TagID != "" {
+		conditions = append(conditions, "id IN (SELECT content_id FROM content_tags WHERE tag_id = ?)")
+		args = append(args, filter.TagID)
+	}
+	if filter.Viewer != nil {
+		condition, viewerArgs := viewerCondition(*filter.Viewer)
+		conditions = append(conditions, condition)
+		args = append(args, viewerArgs...)
+	}
+
+	query := `SELECT ` + selectColumns + ` FROM contents WHERE ` + strings.Join(conditions, " AND ") +
+		` ORDER BY COALESCE(published_at, created_at) DESC LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	return r.query(ctx, query, args...)
+}
+
+// ListTranslations retrieves the translations of the given source entries ordered by locale.
+func (r *ContentRepository) ListTranslations(ctx context.Context, sourceIDs []string) ([]*content.Content, error) {
+	if len(sourceIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(sourceIDs)), ",")
+	args := make([]interface{}, len(sourceIDs))
+	for i, id := range sourceIDs {
+		args[i] = id
+	}
+	query := `SELECT ` + selectColumns + ` FROM contents WHERE translation_of IN (` + placeholders + `) AND deleted_at IS NULL ORDER BY locale`
+	return r.query(ctx, query, args...)
+}
+
+// GetTrashed retrieves a trashed content entry by ID.
+func (r *ContentRepository) GetTrashed(ctx context.Context, id string) (*content.Content, error) {
+	query := `SELECT ` + selectColumns + ` FROM contents WHERE id = ? AND deleted_at IS NOT NULL`
+	c, err := scanOne(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, content.ErrNotFound) {
+		return nil, trash.ErrNotFound
+	}
+	return c, err
+}
+
+// ListTrashed retrieves trashed entries, most recently deleted first. Translations trashed along
+// with their source entry are not listed: they follow the source entry.
+func (r *ContentRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	query := `SELECT id, title, deleted_at FROM contents WHERE deleted_at IS NOT NULL
+		AND (translation_of IS NULL OR translation_of IN (SELECT id FROM contents WHERE deleted_at IS NULL))`
+	var args []interface{}
+	if !filter.DeletedBefore.IsZero() {
+		query += ` AND deleted_at < ?`
+		args = append(args, filter.DeletedBefore)
+	}
+	query += ` ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*trash.Item
+	for rows.Next() {
+		item := &trash.Item{Type: trash.TypeContent}
+		if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Restore takes a content entry out of the trash along with the translations trashed with it.
+func (r *ContentRepository) Restore(ctx context.Context, id string) error {
+	var deletedAt time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT deleted_at FROM contents WHERE id = ? AND deleted_at IS NOT NULL`, id).Scan(&deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return trash.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`UPDATE contents SET deleted_at = NULL WHERE (id = ? OR translation_of = ?) AND deleted_at = ?`,
+		id, id, deletedAt,
+	)
+	return err
+}
+
+// Purge permanently deletes a trashed content entry. Its translations are deleted with it.
+func (r *ContentRepository) Purge(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM contents WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return trash.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ContentRepository) query(ctx context.Context, query string, args ...interface{}) ([]*content.Content, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contents []*content.Content
+	for rows.Next() {
+		c, err := scanContent(rows)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return contents, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOne(row *sql.Row) (*content.Content, error) {
+	c, err := scanContent(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, content.ErrNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func scanContent(s scanner) (*content.Content, error) {
+	c := &content.Content{}
+	var translationOf, excerpt, body, bodyHTML, passwordHash, authorID sql.NullString
+	var blocks, roles []byte
+	var publishedAt sql.NullTime
+
+	err := s.Scan(
+		&c.ID, &c.Type, &c.Locale, &translationOf, &c.Title, &c.Slug, &excerpt, &body, &c.BodyFormat, &bodyHTML, &blocks, &c.Status,
+		&c.Visibility, &roles, &passwordHash, &c.Teaser, &authorID, &publishedAt, &c.CreatedAt, &c.UpdatedAt, &c.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.TranslationOf = translationOf.String
+	c.Excerpt = excerpt.String
+	c.Body = body.String
+	c.BodyHTML = bodyHTML.String
+	c.PasswordHash = passwordHash.String
+	c.AuthorID = authorID.String
+	if len(blocks) > 0 {
+		if err := json.Unmarshal(blocks, &c.Blocks); err != nil {
+			return nil, err
+		}
+	}
+	if len(roles) > 0 {
+		if err := json.Unmarshal(roles, &c.AllowedRoles); err != nil {
+			return nil, err
+		}
+	}
+	if publishedAt.Valid {
+		c.PublishedAt = publishedAt.Time
+	}
+
+	return c, nil
+}
+
+func requireAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return content.ErrNotFound
+	}
+	return nil
+}
+
+// replaceSnippets records the snippets the body of c references, found like the public site expands
+// them, so tokens quoted in code do not count as usages.
+func replaceSnippets(ctx context.Context, tx *sql.Tx, c *content.Content) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM content_snippets WHERE content_id = ?`, c.ID); err != nil {
+		return err
+	}
+	names := snippet.References(c.BodyHTML)
+	if len(names) == 0 {
+		return nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?),", len(names)), ",")
+	args := make([]interface{}, 0, len(names)*2)
+	for _, name := range names {
+		args = append(args, c.ID, name)
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO content_snippets (content_id, name) VALUES `+placeholders, args...)
+	return err
+}
+
+// marshalBlocks encodes blocks for the JSON column, storing NULL for entries without blocks.
+func marshalBlocks(blocks []block.Block) (interface{}, error) {
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(blocks)
+}
+
+// marshalRoles encodes the allowed roles for the JSON column, storing NULL when there are none.
+func marshalRoles(roles []string) (interface{}, error) {
+	if len(roles) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(roles)
+}
+
+// visibility stores entries saved without a visibility as public.
+func visibility(c *content.Content) string {
+	if c.Visibility == "" {
+		return content.VisibilityPublic
+	}
+	return c.Visibility
+}
+
+// viewerCondition keeps the entries v may read without a password, along with every teaser. It is
+// shared by List and the search queries.
+func viewerCondition(v content.Viewer) (string, []interface{}) {
+	return visibilityCondition(v, true)
+}
+
+// admitCondition keeps the entries v may read in full without a password.
+func admitCondition(v content.Viewer) (string, []interface{}) {
+	return visibilityCondition(v, false)
+}
+
+func visibilityCondition(v content.Viewer, teasers bool) (string, []interface{}) {
+	visible := []string{"visibility = '" + content.VisibilityPublic + "'"}
+	if teasers {
+		visible = append(visible, "teaser = TRUE")
+	}
+	var args []interface{}
+	if v.UserID != "" {
+		visible = append(visible, "visibility = '"+content.VisibilityAuthenticated+"'")
+		if v.Role != "" {
+			visible = append(visible, "(visibility = '"+content.VisibilityRoles+"' AND JSON_CONTAINS(allowed_roles, JSON_QUOTE(?)))")
+			args = append(args, v.Role)
+		}
+	}
+	return "(" + strings.Join(visible, " OR ") + ")", args
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
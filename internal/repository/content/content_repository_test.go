@@ -0,0 +1,323 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var contentColumns = []string{"id", "type", "locale", "translation_of", "title", "slug", "excerpt", "body", "body_format", "body_html", "blocks", "status", "visibility", "allowed_roles", "password_hash", "teaser", "author_id", "published_at", "created_at", "updated_at", "version"}
+
+func TestContentRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	c := &content.Content{ID: "content-1", Type: "post", Locale: "en", Title: "Hello", Slug: "hello", Status: content.StatusDraft,
+		BodyHTML: "<p>{{snippet:footer}}</p><code>{{snippet:promo}}</code>"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO contents")).
+		WithArgs(c.ID, c.Type, c.Locale, sql.NullString{}, c.Title, c.Slug, c.Excerpt, c.Body, c.BodyFormat, c.BodyHTML, nil, c.Status, content.VisibilityPublic, nil, sql.NullString{}, false, sql.NullString{}, sql.NullTime{}, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM content_snippets WHERE content_id = ?")).
+		WithArgs("content-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// The token quoted in code is not a reference.
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO content_snippets (content_id, name) VALUES (?, ?)")).
+		WithArgs("content-1", "footer").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = repo.Create(context.Background(), c)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, c.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContentRepository_GetBySlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(contentColumns).
+		AddRow("content-1", "post", "id", "content-0", "Hello", "hello", nil, "body", "blocks", "<p>body</p>", []byte(`[{"type":"paragraph","data":{"text":"body"}}]`), "published", "roles", []byte(`["editor"]`), nil, true, "user-1", now, now, now, 1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, type, locale, translation_of, title, slug")).
+		WithArgs("post", "id", "hello").
+		WillReturnRows(rows)
+
+	c, err := repo.GetBySlug(context.Background(), "post", "id", "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "content-1", c.ID)
+	assert.Equal(t, "id", c.Locale)
+	assert.Equal(t, "content-0", c.TranslationOf)
+	assert.Equal(t, "user-1", c.AuthorID)
+	assert.Empty(t, c.Excerpt)
+	assert.Equal(t, "<p>body</p>", c.BodyHTML)
+	require.Len(t, c.Blocks, 1)
+	assert.Equal(t, "paragraph", c.Blocks[0].Type)
+	assert.JSONEq(t, `{"text":"body"}`, string(c.Blocks[0].Data))
+	assert.Equal(t, now, c.PublishedAt)
+	assert.Equal(t, content.VisibilityRoles, c.Visibility)
+	assert.Equal(t, []string{"editor"}, c.AllowedRoles)
+	assert.True(t, c.Teaser)
+}
+
+func TestContentRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, type, locale, translation_of, title, slug")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	c, err := repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, content.ErrNotFound)
+	assert.Nil(t, c)
+}
+
+func TestContentRepository_Update_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE contents")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM contents WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err = repo.Update(context.Background(), &content.Content{ID: "missing"})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+}
+
+func TestContentRepository_Update_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("WHERE id = ? AND version = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM contents WHERE id = ?")).
+		WithArgs("content-1").
+		WillReturnRows(sqlmock.NewRows(contentColumns).
+			AddRow("content-1", "post", "en", nil, "Hello", "hello", nil, "body", "markdown", nil, nil, "draft", "public", nil, nil, false, nil, nil, now, now, 5))
+	mock.ExpectRollback()
+
+	c := &content.Content{ID: "content-1", Title: "Stale", Version: 4}
+	err = repo.Update(context.Background(), c)
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
+	assert.Equal(t, 4, c.Version)
+}
+
+func TestContentRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE contents SET deleted_at = ? WHERE (id = ? OR translation_of = ?) AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), "content-1", "content-1").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	assert.NoError(t, repo.Delete(context.Background(), "content-1"))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE contents SET deleted_at = ?")).
+		WithArgs(sqlmock.AnyArg(), "missing", "missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), "missing"), content.ErrNotFound)
+}
+
+func TestContentRepository_SlugInUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM contents WHERE type = ? AND locale = ? AND slug = ? AND id <> ?")).
+		WithArgs("post", "en", "hello", "content-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	inUse, err := repo.SlugInUse(context.Background(), "post", "en", "hello", "content-1")
+	require.NoError(t, err)
+	assert.True(t, inUse)
+}
+
+func TestContentRepository_ListTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	before := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, deleted_at FROM contents WHERE deleted_at IS NOT NULL")).
+		WithArgs(before, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}).AddRow("content-1", "Hello", before.Add(-time.Hour)))
+
+	items, err := repo.ListTrashed(context.Background(), trash.ListFilter{DeletedBefore: before, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, trash.TypeContent, items[0].Type)
+	assert.Equal(t, "Hello", items[0].Title)
+}
+
+func TestContentRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	deletedAt := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT deleted_at FROM contents WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("content-1").
+		WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE contents SET deleted_at = NULL WHERE (id = ? OR translation_of = ?) AND deleted_at = ?")).
+		WithArgs("content-1", "content-1", deletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	require.NoError(t, repo.Restore(context.Background(), "content-1"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT deleted_at FROM contents")).
+		WithArgs("content-2").
+		WillReturnError(sql.ErrNoRows)
+
+	assert.ErrorIs(t, repo.Restore(context.Background(), "content-2"), trash.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContentRepository_Purge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM contents WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("content-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Purge(context.Background(), "content-1"))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM contents WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("content-2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Purge(context.Background(), "content-2"), trash.ErrNotFound)
+}
+
+func TestContentRepository_List_WithFilters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(contentColumns).
+		AddRow("content-1", "post", "en", nil, "Hello", "hello", "excerpt", "body", "markdown", nil, nil, "published", "public", nil, nil, false, nil, now, now, now, 1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE deleted_at IS NULL AND status = ? AND id IN (SELECT content_id FROM content_categories WHERE category_id IN (?,?)) AND id IN (SELECT content_id FROM content_tags WHERE tag_id = ?)")).
+		WithArgs("published", "cat-1", "cat-2", "tag-1", 10, 0).
+		WillReturnRows(rows)
+
+	contents, err := repo.List(context.Background(), content.ListFilter{
+		Status:      content.StatusPublished,
+		CategoryIDs: []string{"cat-1", "cat-2"},
+		TagID:       "tag-1",
+		Limit:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	assert.Equal(t, "excerpt", contents[0].Excerpt)
+}
+
+func TestContentRepository_List_MissingLocales(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE deleted_at IS NULL AND type = ? AND translation_of IS NULL AND ((locale <> ? AND id NOT IN (SELECT translation_of FROM contents WHERE translation_of IS NOT NULL AND locale = ? AND deleted_at IS NULL)) OR (locale <> ? AND id NOT IN")).
+		WithArgs("post", "en", "en", "id", "id", 10, 0).
+		WillReturnRows(sqlmock.NewRows(contentColumns))
+
+	_, err = repo.List(context.Background(), content.ListFilter{Type: "post", MissingLocales: []string{"en", "id"}, Limit: 10})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContentRepository_ListTranslations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(contentColumns).
+		AddRow("content-2", "post", "id", "content-1", "Halo", "halo", nil, "body", "markdown", nil, nil, "draft", "public", nil, nil, false, nil, nil, now, now, 1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM contents WHERE translation_of IN (?,?) AND deleted_at IS NULL ORDER BY locale")).
+		WithArgs("content-1", "content-3").
+		WillReturnRows(rows)
+
+	translations, err := repo.ListTranslations(context.Background(), []string{"content-1", "content-3"})
+	require.NoError(t, err)
+	require.Len(t, translations, 1)
+	assert.Equal(t, "content-1", translations[0].TranslationOf)
+
+	translations, err = repo.ListTranslations(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, translations)
+}
+
+func TestContentRepository_List_Viewer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE deleted_at IS NULL AND status = ? AND (visibility = 'public' OR teaser = TRUE)")).
+		WithArgs("published", 10, 0).
+		WillReturnRows(sqlmock.NewRows(contentColumns))
+	_, err = repo.List(context.Background(), content.ListFilter{Status: content.StatusPublished, Viewer: &content.Viewer{}, Limit: 10})
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE deleted_at IS NULL AND (visibility = 'public' OR teaser = TRUE OR visibility = 'authenticated' OR (visibility = 'roles' AND JSON_CONTAINS(allowed_roles, JSON_QUOTE(?))))")).
+		WithArgs("editor", 10, 0).
+		WillReturnRows(sqlmock.NewRows(contentColumns))
+	_, err = repo.List(context.Background(), content.ListFilter{Viewer: &content.Viewer{UserID: "user-1", Role: "editor"}, Limit: 10})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,85 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+)
+
+// RedirectRepository implements content.RedirectRepository for MySQL.
+type RedirectRepository struct {
+	db *sql.DB
+}
+
+// NewRedirectRepository creates a new MySQL slug redirect repository.
+func NewRedirectRepository(db *sql.DB) content.RedirectRepository {
+	return &RedirectRepository{db: db}
+}
+
+// Save records a former path. An existing row for the same path is reassigned to the new content entry.
+func (r *RedirectRepository) Save(ctx context.Context, redirect *content.SlugRedirect) error {
+	if redirect.ID == "" {
+		redirect.ID = uuid.New().String()
+	}
+	if redirect.CreatedAt.IsZero() {
+		redirect.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO slug_redirects (id, content_id, old_path, created_at)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE content_id = VALUES(content_id), created_at = VALUES(created_at)
+	`
+	_, err := r.db.ExecContext(ctx, query, redirect.ID, redirect.ContentID, redirect.OldPath, redirect.CreatedAt)
+	return err
+}
+
+// GetByPath retrieves the redirect registered for a former path.
+func (r *RedirectRepository) GetByPath(ctx context.Context, path string) (*content.SlugRedirect, error) {
+	query := `SELECT id, content_id, old_path, created_at FROM slug_redirects WHERE old_path = ?`
+
+	redirect := &content.SlugRedirect{}
+	err := r.db.QueryRowContext(ctx, query, path).Scan(&redirect.ID, &redirect.ContentID, &redirect.OldPath, &redirect.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, content.ErrNotFound
+		}
+		return nil, err
+	}
+	return redirect, nil
+}
+
+// DeleteByPath removes the redirect for a path, typically because the path is live again.
+func (r *RedirectRepository) DeleteByPath(ctx context.Context, path string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM slug_redirects WHERE old_path = ?`, path)
+	return err
+}
+
+// ListByContent retrieves all former paths of a content entry.
+func (r *RedirectRepository) ListByContent(ctx context.Context, contentID string) ([]*content.SlugRedirect, error) {
+	query := `SELECT id, content_id, old_path, created_at FROM slug_redirects WHERE content_id = ? ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, contentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var redirects []*content.SlugRedirect
+	for rows.Next() {
+		redirect := &content.SlugRedirect{}
+		if err := rows.Scan(&redirect.ID, &redirect.ContentID, &redirect.OldPath, &redirect.CreatedAt); err != nil {
+			return nil, err
+		}
+		redirects = append(redirects, redirect)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return redirects, nil
+}
@@ -0,0 +1,76 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRedirectRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO slug_redirects")).
+		WithArgs(sqlmock.AnyArg(), "content-1", "/2025/01/old", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	redirect := &content.SlugRedirect{ContentID: "content-1", OldPath: "/2025/01/old"}
+	assert.NoError(t, repo.Save(context.Background(), redirect))
+	assert.NotEmpty(t, redirect.ID)
+}
+
+func TestRedirectRepository_GetByPath(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRedirectRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "content_id", "old_path", "created_at"}).
+		AddRow("redirect-1", "content-1", "/2025/01/old", time.Now())
+	mock.ExpectQuery(regexp.QuoteMeta("FROM slug_redirects WHERE old_path = ?")).
+		WithArgs("/2025/01/old").
+		WillReturnRows(rows)
+
+	redirect, err := repo.GetByPath(context.Background(), "/2025/01/old")
+	require.NoError(t, err)
+	assert.Equal(t, "content-1", redirect.ContentID)
+}
+
+func TestRedirectRepository_GetByPath_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRedirectRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM slug_redirects WHERE old_path = ?")).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByPath(context.Background(), "/missing")
+	assert.ErrorIs(t, err, content.ErrNotFound)
+}
+
+func TestRedirectRepository_DeleteByPath(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRedirectRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM slug_redirects WHERE old_path = ?")).
+		WithArgs("/about").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.DeleteByPath(context.Background(), "/about"))
+}
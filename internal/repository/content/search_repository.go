@@ -0,0 +1,186 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/utils/fulltext"
+)
+
+// matchTitleBody, matchTitleExcerpt and matchTitle use the FULLTEXT indexes on contents in natural
+// language mode.
+const (
+	matchTitleBody    = `MATCH(title, body) AGAINST (? IN NATURAL LANGUAGE MODE)`
+	matchTitleExcerpt = `MATCH(title, excerpt) AGAINST (? IN NATURAL LANGUAGE MODE)`
+	matchTitle        = `MATCH(title) AGAINST (? IN NATURAL LANGUAGE MODE)`
+)
+
+// SearchRepository implements content.Search with MySQL FULLTEXT indexes on the contents table.
+type SearchRepository struct {
+	db *sql.DB
+}
+
+// NewSearchRepository creates a new MySQL full-text search backend.
+func NewSearchRepository(db *sql.DB) content.Search {
+	return &SearchRepository{db: db}
+}
+
+// Index is a no-op: InnoDB keeps the FULLTEXT indexes in step with the contents table.
+func (r *SearchRepository) Index(ctx context.Context, c *content.Content, categoryIDs []string) error {
+	return nil
+}
+
+// Remove is a no-op: deleted rows leave the FULLTEXT indexes with them, and trashed rows are
+// filtered out of every search.
+func (r *SearchRepository) Remove(ctx context.Context, id string) error {
+	return nil
+}
+
+// Search ranks matching entries by MySQL relevance, weighting the title score by fulltext.TitleBoost.
+// Teasers q.Viewer may not open match, score and produce snippets on their title and excerpt only.
+func (r *SearchRepository) Search(ctx context.Context, q content.SearchQuery) (*content.SearchResult, error) {
+	where, args := searchConditions(q)
+	result := &content.SearchResult{Hits: []*content.SearchHit{}}
+
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM contents WHERE `+where, args...).Scan(&result.Total); err != nil {
+		return nil, err
+	}
+
+	score, hitArgs := relevance(q)
+	query := fmt.Sprintf(`SELECT %s, %s AS score FROM contents WHERE %s ORDER BY score DESC, COALESCE(published_at, created_at) DESC LIMIT ? OFFSET ?`,
+		selectColumns, score, where)
+	hitArgs = append(hitArgs, args...)
+	hitArgs = append(hitArgs, q.Limit, q.Offset)
+	rows, err := r.db.QueryContext(ctx, query, hitArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	terms := fulltext.Terms(q.Text)
+	for rows.Next() {
+		hit := &content.SearchHit{}
+		c, err := scanContent(scoredRow{rows: rows, score: &hit.Score})
+		if err != nil {
+			return nil, err
+		}
+		body := fulltext.PlainText(c.BodyHTML)
+		if body == "" {
+			body = c.Body
+		}
+		if q.Viewer != nil && !c.Admits(*q.Viewer) {
+			body = c.Excerpt
+		}
+		hit.Content = c
+		hit.Title = fulltext.Highlight(c.Title, terms)
+		hit.Snippet = fulltext.Snippet(body, terms, fulltext.SnippetSize)
+		result.Hits = append(result.Hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	facetQueries := map[string]string{
+		content.FacetType:     `SELECT type, COUNT(*) FROM contents WHERE ` + where + ` GROUP BY type`,
+		content.FacetAuthor:   `SELECT author_id, COUNT(*) FROM contents WHERE author_id IS NOT NULL AND ` + where + ` GROUP BY author_id`,
+		content.FacetCategory: `SELECT category_id, COUNT(*) FROM content_categories WHERE category_id IN (SELECT id FROM categories WHERE deleted_at IS NULL) AND content_id IN (SELECT id FROM contents WHERE ` + where + `) GROUP BY category_id`,
+	}
+	result.Facets = make(map[string][]content.FacetValue, len(facetQueries))
+	for _, name := range []string{content.FacetType, content.FacetCategory, content.FacetAuthor} {
+		values, err := r.facet(ctx, facetQueries[name], args)
+		if err != nil {
+			return nil, err
+		}
+		result.Facets[name] = values
+	}
+
+	return result, nil
+}
+
+// facet runs a grouped count query, most frequent value first.
+func (r *SearchRepository) facet(ctx context.Context, query string, args []interface{}) ([]content.FacetValue, error) {
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY COUNT(*) DESC, 1`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []content.FacetValue{}
+	for rows.Next() {
+		var v content.FacetValue
+		if err := rows.Scan(&v.Value, &v.Count); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// searchConditions builds the WHERE clause shared by the hit, count and facet queries.
+func searchConditions(q content.SearchQuery) (string, []interface{}) {
+	match, args := matchCondition(q)
+	conditions := []string{match, "deleted_at IS NULL"}
+
+	if q.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, q.Type)
+	}
+	if q.Locale != "" {
+		conditions = append(conditions, "locale = ?")
+		args = append(args, q.Locale)
+	}
+	if q.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, q.Status)
+	}
+	if q.AuthorID != "" {
+		conditions = append(conditions, "author_id = ?")
+		args = append(args, q.AuthorID)
+	}
+	if q.CategoryID != "" {
+		conditions = append(conditions, "id IN (SELECT content_id FROM content_categories WHERE category_id = ?)")
+		args = append(args, q.CategoryID)
+	}
+	if !q.PublishedBefore.IsZero() {
+		conditions = append(conditions, "published_at <= ?")
+		args = append(args, q.PublishedBefore)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// matchCondition matches the title and body of the entries q.Viewer may read and the title and
+// excerpt of the teasers it may not, which leaves every other restricted entry out.
+func matchCondition(q content.SearchQuery) (string, []interface{}) {
+	if q.Viewer == nil {
+		return matchTitleBody, []interface{}{q.Text}
+	}
+	admit, admitArgs := admitCondition(*q.Viewer)
+	condition := "((" + admit + " AND " + matchTitleBody + ") OR (teaser = TRUE AND NOT " + admit + " AND " + matchTitleExcerpt + "))"
+	args := append(append([]interface{}{}, admitArgs...), q.Text)
+	args = append(append(args, admitArgs...), q.Text)
+	return condition, args
+}
+
+// relevance scores matches on the same columns matchCondition matched them on.
+func relevance(q content.SearchQuery) (string, []interface{}) {
+	if q.Viewer == nil {
+		return fmt.Sprintf("%s * %d + %s", matchTitle, fulltext.TitleBoost, matchTitleBody), []interface{}{q.Text, q.Text}
+	}
+	admit, admitArgs := admitCondition(*q.Viewer)
+	args := append(append([]interface{}{q.Text}, admitArgs...), q.Text, q.Text)
+	return fmt.Sprintf("%s * %d + IF(%s, %s, %s)", matchTitle, fulltext.TitleBoost, admit, matchTitleBody, matchTitleExcerpt), args
+}
+
+// scoredRow reads the relevance score selected after the content columns.
+type scoredRow struct {
+	rows  *sql.Rows
+	score *float64
+}
+
+func (s scoredRow) Scan(dest ...interface{}) error {
+	return s.rows.Scan(append(dest, s.score)...)
+}
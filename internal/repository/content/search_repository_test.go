@@ -0,0 +1,92 @@
+package content
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRepository_Search(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSearchRepository(db)
+
+	q := content.SearchQuery{Text: "gopher", Type: "post", CategoryID: "news", Limit: 10}
+	where := "WHERE MATCH(title, body) AGAINST (? IN NATURAL LANGUAGE MODE) AND deleted_at IS NULL AND type = ? AND id IN (SELECT content_id FROM content_categories WHERE category_id = ?)"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM contents "+where)).
+		WithArgs("gopher", "post", "news").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	now := time.Now()
+	rows := sqlmock.NewRows(append(contentColumns, "score")).
+		AddRow("content-1", "post", "en", nil, "Gopher news", "gopher-news", nil, "Body", "markdown", "<p>The <strong>gopher</strong> is back.</p>", nil, "published", "public", nil, nil, false, "author-1", now, now, now, 1, 4.5)
+	mock.ExpectQuery(regexp.QuoteMeta("MATCH(title) AGAINST (? IN NATURAL LANGUAGE MODE) * 3 + MATCH(title, body) AGAINST (? IN NATURAL LANGUAGE MODE) AS score FROM contents "+where+" ORDER BY score DESC")).
+		WithArgs("gopher", "gopher", "gopher", "post", "news", 10, 0).
+		WillReturnRows(rows)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT type, COUNT(*) FROM contents "+where+" GROUP BY type")).
+		WithArgs("gopher", "post", "news").
+		WillReturnRows(sqlmock.NewRows([]string{"type", "count"}).AddRow("post", 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT category_id, COUNT(*) FROM content_categories WHERE category_id IN (SELECT id FROM categories WHERE deleted_at IS NULL) AND content_id IN (SELECT id FROM contents "+where+") GROUP BY category_id")).
+		WithArgs("gopher", "post", "news").
+		WillReturnRows(sqlmock.NewRows([]string{"category_id", "count"}).AddRow("news", 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT author_id, COUNT(*) FROM contents WHERE author_id IS NOT NULL AND MATCH")).
+		WithArgs("gopher", "post", "news").
+		WillReturnRows(sqlmock.NewRows([]string{"author_id", "count"}).AddRow("author-1", 1))
+
+	result, err := repo.Search(context.Background(), q)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, 4.5, result.Hits[0].Score)
+	assert.Equal(t, "<mark>Gopher</mark> news", result.Hits[0].Title)
+	assert.Equal(t, "The <mark>gopher</mark> is back.", result.Hits[0].Snippet)
+	assert.Equal(t, []content.FacetValue{{Value: "news", Count: 1}}, result.Facets[content.FacetCategory])
+	assert.Equal(t, []content.FacetValue{{Value: "author-1", Count: 1}}, result.Facets[content.FacetAuthor])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchRepository_Search_RestrictedBodies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSearchRepository(db)
+
+	q := content.SearchQuery{Text: "roadmap", Viewer: &content.Viewer{}, Limit: 10}
+	admit := "(visibility = 'public')"
+	where := "WHERE ((" + admit + " AND MATCH(title, body) AGAINST (? IN NATURAL LANGUAGE MODE)) OR " +
+		"(teaser = TRUE AND NOT " + admit + " AND MATCH(title, excerpt) AGAINST (? IN NATURAL LANGUAGE MODE))) AND deleted_at IS NULL"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM contents "+where)).
+		WithArgs("roadmap", "roadmap").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	now := time.Now()
+	rows := sqlmock.NewRows(append(contentColumns, "score")).
+		AddRow("content-1", "post", "en", nil, "Members update", "members-update", "The roadmap for members.", "Secret roadmap", "markdown", "<p>Secret roadmap</p>", nil, "published", "authenticated", nil, nil, true, "author-1", now, now, now, 1, 2.5)
+	mock.ExpectQuery(regexp.QuoteMeta("MATCH(title) AGAINST (? IN NATURAL LANGUAGE MODE) * 3 + IF("+admit+", MATCH(title, body) AGAINST (? IN NATURAL LANGUAGE MODE), "+
+		"MATCH(title, excerpt) AGAINST (? IN NATURAL LANGUAGE MODE)) AS score FROM contents "+where)).
+		WithArgs("roadmap", "roadmap", "roadmap", "roadmap", "roadmap", 10, 0).
+		WillReturnRows(rows)
+	for _, facet := range []string{"GROUP BY type", "GROUP BY category_id", "GROUP BY author_id"} {
+		mock.ExpectQuery(regexp.QuoteMeta(facet)).
+			WithArgs("roadmap", "roadmap").
+			WillReturnRows(sqlmock.NewRows([]string{"value", "count"}))
+	}
+
+	result, err := repo.Search(context.Background(), q)
+	require.NoError(t, err)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, "The <mark>roadmap</mark> for members.", result.Hits[0].Snippet)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
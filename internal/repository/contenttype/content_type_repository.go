@@ -0,0 +1,147 @@
+package contenttype
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+	"github.com/mashurimansur/goCMS/internal/repository"
+)
+
+const selectTypeColumns = `id, name, label, description, fields, created_at, updated_at, version`
+
+// ContentTypeRepository implements contenttype.Repository for MySQL.
+type ContentTypeRepository struct {
+	db *sql.DB
+}
+
+// NewContentTypeRepository creates a new MySQL content type repository.
+func NewContentTypeRepository(db *sql.DB) contenttype.Repository {
+	return &ContentTypeRepository{db: db}
+}
+
+// Create inserts a new content type definition.
+func (r *ContentTypeRepository) Create(ctx context.Context, ct *contenttype.ContentType) error {
+	if ct.ID == "" {
+		ct.ID = uuid.New().String()
+	}
+	if ct.CreatedAt.IsZero() {
+		ct.CreatedAt = time.Now()
+	}
+	if ct.UpdatedAt.IsZero() {
+		ct.UpdatedAt = time.Now()
+	}
+	ct.Version = 1
+	fields, err := json.Marshal(ct.Fields)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO content_types (id, name, label, description, fields, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = r.db.ExecContext(ctx, query, ct.ID, ct.Name, ct.Label, ct.Description, fields, ct.CreatedAt, ct.UpdatedAt)
+	return err
+}
+
+// GetByName retrieves a content type by its name.
+func (r *ContentTypeRepository) GetByName(ctx context.Context, name string) (*contenttype.ContentType, error) {
+	query := `SELECT ` + selectTypeColumns + ` FROM content_types WHERE name = ?`
+	ct, err := scanType(r.db.QueryRowContext(ctx, query, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, contenttype.ErrNotFound
+		}
+		return nil, err
+	}
+	return ct, nil
+}
+
+// Update replaces the label, description and fields of a content type if it is still at
+// ct.Version, then increments the version.
+func (r *ContentTypeRepository) Update(ctx context.Context, ct *contenttype.ContentType) error {
+	fields, err := json.Marshal(ct.Fields)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := time.Now()
+	query := `UPDATE content_types SET label = ?, description = ?, fields = ?, updated_at = ?, version = version + 1 WHERE name = ? AND version = ?`
+	res, err := r.db.ExecContext(ctx, query, ct.Label, ct.Description, fields, updatedAt, ct.Name, ct.Version)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByName(ctx, ct.Name)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	ct.UpdatedAt = updatedAt
+	ct.Version++
+	return nil
+}
+
+// Delete removes a content type definition by name.
+func (r *ContentTypeRepository) Delete(ctx context.Context, name string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM content_types WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, contenttype.ErrNotFound)
+}
+
+// List retrieves every content type ordered by name.
+func (r *ContentTypeRepository) List(ctx context.Context) ([]*contenttype.ContentType, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+selectTypeColumns+` FROM content_types ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var types []*contenttype.ContentType
+	for rows.Next() {
+		ct, err := scanType(rows)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, ct)
+	}
+	return types, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanType(s scanner) (*contenttype.ContentType, error) {
+	ct := &contenttype.ContentType{}
+	var description sql.NullString
+	var fields []byte
+
+	if err := s.Scan(&ct.ID, &ct.Name, &ct.Label, &description, &fields, &ct.CreatedAt, &ct.UpdatedAt, &ct.Version); err != nil {
+		return nil, err
+	}
+	ct.Description = description.String
+	if err := json.Unmarshal(fields, &ct.Fields); err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+func requireAffected(res sql.Result, notFound error) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package contenttype
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentTypeRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentTypeRepository(db)
+	ct := &contenttype.ContentType{Name: "product", Label: "Product", Fields: []contenttype.Field{{Name: "title", Type: contenttype.FieldText}}}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO content_types")).
+		WithArgs(sqlmock.AnyArg(), "product", "Product", "", []byte(`[{"name":"title","label":"","type":"text","required":false,"indexed":false,"rules":{}}]`), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.Create(context.Background(), ct))
+	assert.NotEmpty(t, ct.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContentTypeRepository_GetByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentTypeRepository(db)
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "label", "description", "fields", "created_at", "updated_at", "version"}).
+		AddRow("type-1", "product", "Product", nil, []byte(`[{"name":"price","type":"number","indexed":true}]`), now, now, 2)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, label, description, fields, created_at, updated_at, version FROM content_types WHERE name = ?")).
+		WithArgs("product").
+		WillReturnRows(rows)
+
+	ct, err := repo.GetByName(context.Background(), "product")
+	require.NoError(t, err)
+	assert.Equal(t, "type-1", ct.ID)
+	require.Len(t, ct.Fields, 1)
+	assert.True(t, ct.Fields[0].Indexed)
+}
+
+func TestContentTypeRepository_GetByName_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentTypeRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM content_types WHERE name = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByName(context.Background(), "missing")
+	assert.ErrorIs(t, err, contenttype.ErrNotFound)
+}
+
+func TestContentTypeRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewContentTypeRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM content_types WHERE name = ?")).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), "missing"), contenttype.ErrNotFound)
+}
@@ -0,0 +1,369 @@
+package contenttype
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/repository"
+)
+
+const selectEntryColumns = `id, type, status, visibility, allowed_roles, password_hash, data, author_id, created_at, updated_at, version`
+
+// EntryRepository implements contenttype.EntryRepository for MySQL. Entries of every type share the
+// content_entries table; indexed fields are materialised as virtual generated columns over the JSON
+// data so filters and sorting can use a regular index.
+type EntryRepository struct {
+	db *sql.DB
+}
+
+// NewEntryRepository creates a new MySQL entry repository.
+func NewEntryRepository(db *sql.DB) contenttype.EntryRepository {
+	return &EntryRepository{db: db}
+}
+
+// Create inserts a new entry.
+func (r *EntryRepository) Create(ctx context.Context, e *contenttype.Entry) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	if e.UpdatedAt.IsZero() {
+		e.UpdatedAt = time.Now()
+	}
+	e.Version = 1
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	roles, err := marshalRoles(e.AllowedRoles)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO content_entries (id, type, status, visibility, allowed_roles, password_hash, data, author_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = r.db.ExecContext(ctx, query, e.ID, e.Type, e.Status, visibility(e), roles, nullString(e.PasswordHash), data,
+		nullString(e.AuthorID), e.CreatedAt, e.UpdatedAt)
+	return err
+}
+
+// GetByID retrieves an entry of the given type by ID.
+func (r *EntryRepository) GetByID(ctx context.Context, contentType, id string) (*contenttype.Entry, error) {
+	query := `SELECT ` + selectEntryColumns + ` FROM content_entries WHERE type = ? AND id = ? AND deleted_at IS NULL`
+	e, err := scanEntry(r.db.QueryRowContext(ctx, query, contentType, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, contenttype.ErrEntryNotFound
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// GetTrashed retrieves a trashed entry of any type by ID.
+func (r *EntryRepository) GetTrashed(ctx context.Context, id string) (*contenttype.Entry, error) {
+	query := `SELECT ` + selectEntryColumns + ` FROM content_entries WHERE id = ? AND deleted_at IS NOT NULL`
+	e, err := scanEntry(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, trash.ErrNotFound
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// Update replaces the status, visibility and data of an entry if it is still at e.Version, then
+// increments the version.
+func (r *EntryRepository) Update(ctx context.Context, e *contenttype.Entry) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	roles, err := marshalRoles(e.AllowedRoles)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := time.Now()
+	query := `
+		UPDATE content_entries
+		SET status = ?, visibility = ?, allowed_roles = ?, password_hash = ?, data = ?, updated_at = ?, version = version + 1
+		WHERE type = ? AND id = ? AND version = ? AND deleted_at IS NULL
+	`
+	res, err := r.db.ExecContext(ctx, query, e.Status, visibility(e), roles, nullString(e.PasswordHash), data, updatedAt,
+		e.Type, e.ID, e.Version)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByID(ctx, e.Type, e.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	e.UpdatedAt = updatedAt
+	e.Version++
+	return nil
+}
+
+// Delete moves an entry of the given type to the trash.
+func (r *EntryRepository) Delete(ctx context.Context, contentType, id string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE content_entries SET deleted_at = ? WHERE type = ? AND id = ? AND deleted_at IS NULL`,
+		time.Now(), contentType, id,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, contenttype.ErrEntryNotFound)
+}
+
+// List retrieves entries of a type matching the filter.
+func (r *EntryRepository) List(ctx context.Context, contentType string, filter contenttype.EntryFilter) ([]*contenttype.Entry, error) {
+	conditions := []string{"type = ?", "deleted_at IS NULL"}
+	args := []interface{}{contentType}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Viewer != nil {
+		cond, condArgs := viewerCondition(*filter.Viewer)
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+	for _, cond := range filter.Conditions {
+		conditions = append(conditions, indexColumn(contentType, cond.Field)+" = ?")
+		args = append(args, cond.Value)
+	}
+
+	order := "created_at"
+	if filter.Sort != nil {
+		order = indexColumn(contentType, *filter.Sort)
+	}
+	direction := " ASC"
+	if filter.Descending {
+		direction = " DESC"
+	}
+
+	query := `SELECT ` + selectEntryColumns + ` FROM content_entries WHERE ` + strings.Join(conditions, " AND ") +
+		` ORDER BY ` + order + direction + `, id LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*contenttype.Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Count returns the number of entries of a type, trashed ones included.
+func (r *EntryRepository) Count(ctx context.Context, contentType string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM content_entries WHERE type = ?`, contentType).Scan(&n)
+	return n, err
+}
+
+// ListTrashed retrieves trashed entries of every type, most recently deleted first. Entries are
+// titled after their title or name field, falling back to their type.
+func (r *EntryRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	query := `
+		SELECT id, COALESCE(JSON_UNQUOTE(JSON_EXTRACT(data, '$.title')), JSON_UNQUOTE(JSON_EXTRACT(data, '$.name')), type), deleted_at
+		FROM content_entries WHERE deleted_at IS NOT NULL`
+	var args []interface{}
+	if !filter.DeletedBefore.IsZero() {
+		query += ` AND deleted_at < ?`
+		args = append(args, filter.DeletedBefore)
+	}
+	query += ` ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*trash.Item
+	for rows.Next() {
+		item := &trash.Item{Type: trash.TypeEntry}
+		if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// Restore takes an entry out of the trash.
+func (r *EntryRepository) Restore(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE content_entries SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, trash.ErrNotFound)
+}
+
+// Purge permanently deletes a trashed entry; its references go with it through the foreign key.
+func (r *EntryRepository) Purge(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM content_entries WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, trash.ErrNotFound)
+}
+
+// SyncIndexes reconciles the generated index columns of ct with its indexed fields. Each column
+// carries a "type.field" comment so the columns of a type can be found again; the column name
+// hashes the field type too, so changing the type of a field rebuilds its index.
+func (r *EntryRepository) SyncIndexes(ctx context.Context, ct *contenttype.ContentType) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT COLUMN_NAME FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'content_entries' AND COLUMN_COMMENT LIKE ?
+	`, escapeLike(ct.Name)+".%")
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var changes []string
+	for _, f := range ct.IndexedFields() {
+		column := indexColumn(ct.Name, f)
+		if existing[column] {
+			delete(existing, column)
+			continue
+		}
+		sqlType, expr := generatedExpression(f)
+		changes = append(changes,
+			fmt.Sprintf("ADD COLUMN %s %s GENERATED ALWAYS AS (%s) VIRTUAL COMMENT '%s.%s'", column, sqlType, expr, ct.Name, f.Name),
+			fmt.Sprintf("ADD INDEX %s (type, %s)", column, column),
+		)
+	}
+	for column := range existing {
+		changes = append(changes, "DROP COLUMN "+column)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	_, err = r.db.ExecContext(ctx, `ALTER TABLE content_entries `+strings.Join(changes, ", "))
+	return err
+}
+
+// indexColumn names the generated column backing an indexed field. Type and field names are
+// validated identifiers, but hashing keeps the name within MySQL's 64 character limit.
+func indexColumn(contentType string, f contenttype.Field) string {
+	sum := sha1.Sum([]byte(contentType + "." + f.Name + ":" + f.Type))
+	return "ix_" + hex.EncodeToString(sum[:])[:16]
+}
+
+// generatedExpression returns the SQL type and JSON extraction expression of an indexed field.
+func generatedExpression(f contenttype.Field) (string, string) {
+	path := fmt.Sprintf(`data, '$."%s"'`, f.Name)
+	switch f.Type {
+	case contenttype.FieldNumber:
+		return "DECIMAL(30,10)", "CAST(JSON_EXTRACT(" + path + ") AS DECIMAL(30,10))"
+	case contenttype.FieldBoolean:
+		return "TINYINT(1)", "JSON_EXTRACT(" + path + ") = CAST('true' AS JSON)"
+	default:
+		return "VARCHAR(255)", "LEFT(JSON_UNQUOTE(JSON_EXTRACT(" + path + ")), 255)"
+	}
+}
+
+func scanEntry(s scanner) (*contenttype.Entry, error) {
+	e := &contenttype.Entry{}
+	var data, roles []byte
+	var passwordHash, authorID sql.NullString
+
+	err := s.Scan(&e.ID, &e.Type, &e.Status, &e.Visibility, &roles, &passwordHash, &data, &authorID, &e.CreatedAt, &e.UpdatedAt, &e.Version)
+	if err != nil {
+		return nil, err
+	}
+	e.PasswordHash = passwordHash.String
+	e.AuthorID = authorID.String
+	if len(roles) > 0 {
+		if err := json.Unmarshal(roles, &e.AllowedRoles); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(data, &e.Data); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func marshalRoles(roles []string) (interface{}, error) {
+	if len(roles) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(roles)
+}
+
+// visibility stores entries saved without a visibility as public.
+func visibility(e *contenttype.Entry) string {
+	if e.Visibility == "" {
+		return content.VisibilityPublic
+	}
+	return e.Visibility
+}
+
+// viewerCondition keeps the entries v may read without a password.
+func viewerCondition(v content.Viewer) (string, []interface{}) {
+	visible := []string{"visibility = '" + content.VisibilityPublic + "'"}
+	var args []interface{}
+	if v.UserID != "" {
+		visible = append(visible, "visibility = '"+content.VisibilityAuthenticated+"'")
+		if v.Role != "" {
+			visible = append(visible, "(visibility = '"+content.VisibilityRoles+"' AND JSON_CONTAINS(allowed_roles, JSON_QUOTE(?)))")
+			args = append(args, v.Role)
+		}
+	}
+	return "(" + strings.Join(visible, " OR ") + ")", args
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,211 @@
+package contenttype
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var entryColumns = []string{"id", "type", "status", "visibility", "allowed_roles", "password_hash", "data", "author_id", "created_at", "updated_at", "version"}
+
+func TestEntryRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEntryRepository(db)
+	e := &contenttype.Entry{Type: "product", Status: contenttype.StatusDraft, Data: map[string]any{"title": "Lamp"}}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO content_entries")).
+		WithArgs(sqlmock.AnyArg(), "product", "draft", "public", nil, sql.NullString{}, []byte(`{"title":"Lamp"}`), sql.NullString{}, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.Create(context.Background(), e))
+	assert.NotEmpty(t, e.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEntryRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEntryRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM content_entries WHERE type = ? AND id = ?")).
+		WithArgs("product", "missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByID(context.Background(), "product", "missing")
+	assert.ErrorIs(t, err, contenttype.ErrEntryNotFound)
+}
+
+func TestEntryRepository_Update_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEntryRepository(db)
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("WHERE type = ? AND id = ? AND version = ?")).
+		WithArgs("published", "public", nil, sql.NullString{}, []byte(`{"title":"Lamp"}`), sqlmock.AnyArg(), "product", "entry-1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM content_entries WHERE type = ? AND id = ?")).
+		WithArgs("product", "entry-1").
+		WillReturnRows(sqlmock.NewRows(entryColumns).AddRow("entry-1", "product", "draft", "public", nil, nil, []byte(`{}`), nil, now, now, 2))
+
+	e := &contenttype.Entry{ID: "entry-1", Type: "product", Status: contenttype.StatusPublished, Data: map[string]any{"title": "Lamp"}, Version: 1}
+	err = repo.Update(context.Background(), e)
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEntryRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEntryRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE content_entries SET deleted_at = ? WHERE type = ? AND id = ? AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), "product", "entry-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), "product", "entry-1"), contenttype.ErrEntryNotFound)
+}
+
+func TestEntryRepository_ListTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEntryRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM content_entries WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?")).
+		WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}).AddRow("entry-1", "Lamp", time.Now()))
+
+	items, err := repo.ListTrashed(context.Background(), trash.ListFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, trash.TypeEntry, items[0].Type)
+	assert.Equal(t, "Lamp", items[0].Title)
+}
+
+func TestEntryRepository_RestoreAndPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEntryRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM content_entries WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("entry-1").
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetTrashed(context.Background(), "entry-1")
+	assert.ErrorIs(t, err, trash.ErrNotFound)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE content_entries SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("entry-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Restore(context.Background(), "entry-1"))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM content_entries WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("entry-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Purge(context.Background(), "entry-1"), trash.ErrNotFound)
+}
+
+func TestEntryRepository_List_UsesIndexColumns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEntryRepository(db)
+	color := contenttype.Field{Name: "color", Type: contenttype.FieldEnum, Indexed: true}
+	price := contenttype.Field{Name: "price", Type: contenttype.FieldNumber, Indexed: true}
+
+	now := time.Now()
+	rows := sqlmock.NewRows(entryColumns).
+		AddRow("entry-1", "product", "published", "public", nil, nil, []byte(`{"color":"red","price":10}`), "user-1", now, now, 1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE type = ? AND deleted_at IS NULL AND status = ? AND "+indexColumn("product", color)+" = ? ORDER BY "+indexColumn("product", price)+" DESC, id LIMIT ? OFFSET ?")).
+		WithArgs("product", "published", "red", 10, 0).
+		WillReturnRows(rows)
+
+	entries, err := repo.List(context.Background(), "product", contenttype.EntryFilter{
+		Status:     contenttype.StatusPublished,
+		Conditions: []contenttype.Condition{{Field: color, Value: "red"}},
+		Sort:       &price,
+		Descending: true,
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{"color": "red", "price": float64(10)}, entries[0].Data)
+	assert.Equal(t, "user-1", entries[0].AuthorID)
+}
+
+func TestEntryRepository_List_FiltersByViewer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEntryRepository(db)
+	now := time.Now()
+	rows := sqlmock.NewRows(entryColumns).
+		AddRow("entry-1", "product", "published", "roles", []byte(`["editor"]`), nil, []byte(`{}`), nil, now, now, 1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE type = ? AND deleted_at IS NULL AND status = ? AND (visibility = 'public' OR visibility = 'authenticated' OR "+
+		"(visibility = 'roles' AND JSON_CONTAINS(allowed_roles, JSON_QUOTE(?)))) ORDER BY created_at ASC")).
+		WithArgs("product", "published", "editor", 10, 0).
+		WillReturnRows(rows)
+
+	entries, err := repo.List(context.Background(), "product", contenttype.EntryFilter{
+		Status: contenttype.StatusPublished,
+		Viewer: &content.Viewer{UserID: "user-1", Role: "editor"},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, []string{"editor"}, entries[0].AllowedRoles)
+}
+
+func TestEntryRepository_SyncIndexes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEntryRepository(db)
+	price := contenttype.Field{Name: "price", Type: contenttype.FieldNumber, Indexed: true}
+	sku := contenttype.Field{Name: "sku", Type: contenttype.FieldText, Indexed: true}
+	ct := &contenttype.ContentType{Name: "product", Fields: []contenttype.Field{price, sku, {Name: "notes", Type: contenttype.FieldText}}}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM information_schema.COLUMNS")).
+		WithArgs(`product.%`).
+		WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME"}).AddRow(indexColumn("product", price)).AddRow("ix_stale"))
+
+	skuColumn := indexColumn("product", sku)
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE content_entries " +
+		"ADD COLUMN " + skuColumn + ` VARCHAR(255) GENERATED ALWAYS AS (LEFT(JSON_UNQUOTE(JSON_EXTRACT(data, '$."sku"')), 255)) VIRTUAL COMMENT 'product.sku', ` +
+		"ADD INDEX " + skuColumn + " (type, " + skuColumn + "), " +
+		"DROP COLUMN ix_stale")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.NoError(t, repo.SyncIndexes(context.Background(), ct))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIndexColumn_ChangesWithFieldType(t *testing.T) {
+	text := indexColumn("product", contenttype.Field{Name: "code", Type: contenttype.FieldText})
+	number := indexColumn("product", contenttype.Field{Name: "code", Type: contenttype.FieldNumber})
+
+	assert.Len(t, text, 19)
+	assert.NotEqual(t, text, number)
+}
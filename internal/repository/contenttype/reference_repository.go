@@ -0,0 +1,75 @@
+package contenttype
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+)
+
+// ReferenceRepository implements contenttype.ReferenceRepository for MySQL.
+type ReferenceRepository struct {
+	db *sql.DB
+}
+
+// NewReferenceRepository creates a new MySQL entry reference repository.
+func NewReferenceRepository(db *sql.DB) contenttype.ReferenceRepository {
+	return &ReferenceRepository{db: db}
+}
+
+// Replace swaps the references held by the source entry inside a transaction.
+func (r *ReferenceRepository) Replace(ctx context.Context, sourceID string, refs []contenttype.Reference) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM entry_references WHERE source_id = ?`, sourceID); err != nil {
+		return err
+	}
+	if len(refs) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?),", len(refs)), ",")
+		args := make([]interface{}, 0, len(refs)*5)
+		for _, ref := range refs {
+			args = append(args, sourceID, ref.Field, ref.TargetID, ref.SourceType, ref.TargetType)
+		}
+		query := `INSERT INTO entry_references (source_id, field, target_id, source_type, target_type) VALUES ` + placeholders
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListByTarget returns the references pointing at the target entry.
+func (r *ReferenceRepository) ListByTarget(ctx context.Context, targetID string) ([]contenttype.Reference, error) {
+	query := `
+		SELECT source_type, source_id, field, target_type, target_id
+		FROM entry_references WHERE target_id = ?
+		ORDER BY source_type, source_id, field
+	`
+	rows, err := r.db.QueryContext(ctx, query, targetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []contenttype.Reference
+	for rows.Next() {
+		var ref contenttype.Reference
+		if err := rows.Scan(&ref.SourceType, &ref.SourceID, &ref.Field, &ref.TargetType, &ref.TargetID); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
+
+// DeleteBySource removes every reference held by the source entry.
+func (r *ReferenceRepository) DeleteBySource(ctx context.Context, sourceID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM entry_references WHERE source_id = ?`, sourceID)
+	return err
+}
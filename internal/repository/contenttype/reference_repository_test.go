@@ -0,0 +1,54 @@
+package contenttype
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceRepository_Replace(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewReferenceRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM entry_references WHERE source_id = ?")).
+		WithArgs("a-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO entry_references (source_id, field, target_id, source_type, target_type) VALUES (?, ?, ?, ?, ?),(?, ?, ?, ?, ?)")).
+		WithArgs("a-1", "author", "p-1", "article", "person", "a-1", "related", "a-2", "article", "article").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err = repo.Replace(context.Background(), "a-1", []contenttype.Reference{
+		{SourceType: "article", SourceID: "a-1", Field: "author", TargetType: "person", TargetID: "p-1"},
+		{SourceType: "article", SourceID: "a-1", Field: "related", TargetType: "article", TargetID: "a-2"},
+	})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReferenceRepository_ListByTarget(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewReferenceRepository(db)
+	rows := sqlmock.NewRows([]string{"source_type", "source_id", "field", "target_type", "target_id"}).
+		AddRow("article", "a-1", "author", "person", "p-1")
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM entry_references WHERE target_id = ?")).
+		WithArgs("p-1").
+		WillReturnRows(rows)
+
+	refs, err := repo.ListByTarget(context.Background(), "p-1")
+	require.NoError(t, err)
+	assert.Equal(t, []contenttype.Reference{{SourceType: "article", SourceID: "a-1", Field: "author", TargetType: "person", TargetID: "p-1"}}, refs)
+}
@@ -0,0 +1,311 @@
+package form
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/form"
+	"github.com/mashurimansur/goCMS/internal/repository"
+)
+
+const (
+	selectColumns           = `id, name, title, description, fields, success_message, redirect_url, notify_url, retention_days, created_at, updated_at, version`
+	selectSubmissionColumns = `id, form_id, data, ip, user_agent, created_at`
+)
+
+// FormRepository implements form.Repository for MySQL.
+type FormRepository struct {
+	db *sql.DB
+}
+
+// NewFormRepository creates a new MySQL form repository.
+func NewFormRepository(db *sql.DB) form.Repository {
+	return &FormRepository{db: db}
+}
+
+// Create inserts a new form into the database.
+func (r *FormRepository) Create(ctx context.Context, f *form.Form) error {
+	if f.ID == "" {
+		f.ID = uuid.New().String()
+	}
+	if f.CreatedAt.IsZero() {
+		f.CreatedAt = time.Now()
+	}
+	if f.UpdatedAt.IsZero() {
+		f.UpdatedAt = time.Now()
+	}
+	f.Version = 1
+
+	fields, err := json.Marshal(f.Fields)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO forms (id, name, title, description, fields, success_message, redirect_url, notify_url, retention_days, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, f.ID, f.Name, f.Title, f.Description, fields, f.SuccessMessage, f.RedirectURL, f.NotifyURL, f.RetentionDays, f.CreatedAt, f.UpdatedAt)
+	return err
+}
+
+// GetByID retrieves a form by ID.
+func (r *FormRepository) GetByID(ctx context.Context, id string) (*form.Form, error) {
+	query := `SELECT ` + selectColumns + ` FROM forms WHERE id = ?`
+	return scanOne(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByName retrieves a form by its name.
+func (r *FormRepository) GetByName(ctx context.Context, name string) (*form.Form, error) {
+	query := `SELECT ` + selectColumns + ` FROM forms WHERE name = ?`
+	return scanOne(r.db.QueryRowContext(ctx, query, name))
+}
+
+// Update updates an existing form if it is still at f.Version, then increments the version.
+func (r *FormRepository) Update(ctx context.Context, f *form.Form) error {
+	fields, err := json.Marshal(f.Fields)
+	if err != nil {
+		return err
+	}
+	updatedAt := time.Now()
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE forms SET name = ?, title = ?, description = ?, fields = ?, success_message = ?, redirect_url = ?, notify_url = ?, retention_days = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ?
+	`, f.Name, f.Title, f.Description, fields, f.SuccessMessage, f.RedirectURL, f.NotifyURL, f.RetentionDays, updatedAt, f.ID, f.Version)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByID(ctx, f.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	f.UpdatedAt = updatedAt
+	f.Version++
+	return nil
+}
+
+// Delete deletes a form by ID; its submissions go with it through the foreign key.
+func (r *FormRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM forms WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, form.ErrNotFound)
+}
+
+// List retrieves every form ordered by name.
+func (r *FormRepository) List(ctx context.Context) ([]*form.Form, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+selectColumns+` FROM forms ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var forms []*form.Form
+	for rows.Next() {
+		f, err := scanForm(rows)
+		if err != nil {
+			return nil, err
+		}
+		forms = append(forms, f)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return forms, nil
+}
+
+// CreateSubmission inserts a new submission into the database.
+func (r *FormRepository) CreateSubmission(ctx context.Context, s *form.Submission) error {
+	return insertSubmission(ctx, r.db, s)
+}
+
+// CreateSubmissionLimited inserts s unless max submissions were already made from its address since
+// the given time, and reports whether it did. The row of the address in form_rate_limits stays
+// locked from the count to the commit, so concurrent submissions from one address queue up.
+func (r *FormRepository) CreateSubmissionLimited(ctx context.Context, s *form.Submission, since time.Time, max int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO form_rate_limits (ip) VALUES (?) ON DUPLICATE KEY UPDATE ip = ip`, s.IP); err != nil {
+		return false, err
+	}
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM form_submissions WHERE ip = ? AND created_at >= ?`, s.IP, since).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	if count >= max {
+		return false, nil
+	}
+	if err := insertSubmission(ctx, tx, s); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// GetSubmission retrieves a submission by ID.
+func (r *FormRepository) GetSubmission(ctx context.Context, id string) (*form.Submission, error) {
+	query := `SELECT ` + selectSubmissionColumns + ` FROM form_submissions WHERE id = ?`
+	s, err := scanSubmission(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, form.ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// ListSubmissions retrieves the submissions of a form, newest first, with pagination.
+func (r *FormRepository) ListSubmissions(ctx context.Context, formID string, limit, offset int) ([]*form.Submission, error) {
+	return r.querySubmissions(ctx, `
+		SELECT `+selectSubmissionColumns+` FROM form_submissions
+		WHERE form_id = ? ORDER BY created_at DESC, id LIMIT ? OFFSET ?
+	`, formID, limit, offset)
+}
+
+// ListSubmissionsAfter retrieves up to limit submissions of a form that come after the given one,
+// newest first. Pages are found by the sort key rather than skipped, so they stay cheap however deep
+// and do not shift when submissions arrive meanwhile.
+func (r *FormRepository) ListSubmissionsAfter(ctx context.Context, formID string, after *form.Submission, limit int) ([]*form.Submission, error) {
+	if after == nil {
+		return r.ListSubmissions(ctx, formID, limit, 0)
+	}
+	return r.querySubmissions(ctx, `
+		SELECT `+selectSubmissionColumns+` FROM form_submissions
+		WHERE form_id = ? AND (created_at < ? OR (created_at = ? AND id > ?))
+		ORDER BY created_at DESC, id LIMIT ?
+	`, formID, after.CreatedAt, after.CreatedAt, after.ID, limit)
+}
+
+func (r *FormRepository) querySubmissions(ctx context.Context, query string, args ...interface{}) ([]*form.Submission, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	submissions := []*form.Submission{}
+	for rows.Next() {
+		s, err := scanSubmission(rows)
+		if err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, s)
+	}
+	return submissions, rows.Err()
+}
+
+// DeleteSubmission deletes a submission by ID.
+func (r *FormRepository) DeleteSubmission(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM form_submissions WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, form.ErrSubmissionNotFound)
+}
+
+// DeleteSubmissionsBefore removes the submissions of a form made before the given time.
+func (r *FormRepository) DeleteSubmissionsBefore(ctx context.Context, formID string, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM form_submissions WHERE form_id = ? AND created_at < ?`, formID, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertSubmission(ctx context.Context, db execer, s *form.Submission) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now()
+	}
+
+	data, err := json.Marshal(s.Data)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx,
+		`INSERT INTO form_submissions (id, form_id, data, ip, user_agent, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		s.ID, s.FormID, data, nullString(s.IP), nullString(s.UserAgent), s.CreatedAt,
+	)
+	return err
+}
+
+func scanOne(row *sql.Row) (*form.Form, error) {
+	f, err := scanForm(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, form.ErrNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func scanForm(row scanner) (*form.Form, error) {
+	f := &form.Form{}
+	var description sql.NullString
+	var fields []byte
+	err := row.Scan(
+		&f.ID, &f.Name, &f.Title, &description, &fields, &f.SuccessMessage, &f.RedirectURL, &f.NotifyURL, &f.RetentionDays, &f.CreatedAt, &f.UpdatedAt, &f.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	f.Description = description.String
+	if err := json.Unmarshal(fields, &f.Fields); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func scanSubmission(row scanner) (*form.Submission, error) {
+	s := &form.Submission{}
+	var data []byte
+	var ip, userAgent sql.NullString
+	if err := row.Scan(&s.ID, &s.FormID, &data, &ip, &userAgent, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	s.IP = ip.String
+	s.UserAgent = userAgent.String
+	if err := json.Unmarshal(data, &s.Data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// requireAffected returns notFound when res changed no row.
+func requireAffected(res sql.Result, notFound error) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
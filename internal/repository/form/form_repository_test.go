@@ -0,0 +1,229 @@
+package form
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/form"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	columns           = []string{"id", "name", "title", "description", "fields", "success_message", "redirect_url", "notify_url", "retention_days", "created_at", "updated_at", "version"}
+	submissionColumns = []string{"id", "form_id", "data", "ip", "user_agent", "created_at"}
+)
+
+func TestFormRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFormRepository(db)
+
+	f := &form.Form{Name: "contact", Title: "Contact", Fields: []form.Field{{Name: "email", Type: form.FieldEmail, Required: true}}, RetentionDays: 90}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO forms")).
+		WithArgs(sqlmock.AnyArg(), "contact", "Contact", "", []byte(`[{"name":"email","label":"","type":"email","required":true,"rules":{}}]`), "", "", "", 90, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.Create(context.Background(), f))
+	assert.NotEmpty(t, f.ID)
+	assert.Equal(t, 1, f.Version)
+}
+
+func TestFormRepository_GetByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFormRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM forms WHERE name = ?")).
+		WithArgs("contact").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("f-1", "contact", "Contact", nil, []byte(`[{"name":"email","type":"email"}]`), "Thanks!", "", "https://hooks.example.com", 30, now, now, 2))
+
+	f, err := repo.GetByName(context.Background(), "contact")
+	require.NoError(t, err)
+	assert.Equal(t, "f-1", f.ID)
+	assert.Equal(t, []form.Field{{Name: "email", Type: form.FieldEmail}}, f.Fields)
+	assert.Equal(t, "https://hooks.example.com", f.NotifyURL)
+	assert.Equal(t, 30, f.RetentionDays)
+	assert.Equal(t, 2, f.Version)
+}
+
+func TestFormRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFormRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM forms WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, form.ErrNotFound)
+}
+
+func TestFormRepository_Update_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFormRepository(db)
+
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE forms SET name = ?")).
+		WithArgs("contact", "Contact", "", []byte(`null`), "", "", "", 0, sqlmock.AnyArg(), "f-1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM forms WHERE id = ?")).
+		WithArgs("f-1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("f-1", "contact", "Contact", nil, []byte(`[]`), "", "", "", 0, now, now, 2))
+
+	err = repo.Update(context.Background(), &form.Form{ID: "f-1", Name: "contact", Title: "Contact", Version: 1})
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
+}
+
+func TestFormRepository_CreateSubmission(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFormRepository(db)
+
+	s := &form.Submission{FormID: "f-1", Data: map[string]string{"email": "ana@example.com"}, IP: "203.0.113.9"}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO form_submissions")).
+		WithArgs(sqlmock.AnyArg(), "f-1", []byte(`{"email":"ana@example.com"}`), sql.NullString{String: "203.0.113.9", Valid: true}, sql.NullString{}, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.CreateSubmission(context.Background(), s))
+	assert.NotEmpty(t, s.ID)
+	assert.False(t, s.CreatedAt.IsZero())
+}
+
+func TestFormRepository_ListSubmissions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFormRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM form_submissions")).
+		WithArgs("f-1", 10, 0).
+		WillReturnRows(sqlmock.NewRows(submissionColumns).
+			AddRow("s-1", "f-1", []byte(`{"email":"ana@example.com"}`), "203.0.113.9", nil, now))
+
+	submissions, err := repo.ListSubmissions(context.Background(), "f-1", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, submissions, 1)
+	assert.Equal(t, map[string]string{"email": "ana@example.com"}, submissions[0].Data)
+	assert.Equal(t, "203.0.113.9", submissions[0].IP)
+}
+
+func TestFormRepository_CreateSubmissionLimited(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFormRepository(db)
+
+	since := time.Now().Add(-time.Minute)
+	ip := sql.NullString{String: "203.0.113.9", Valid: true}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO form_rate_limits (ip) VALUES (?) ON DUPLICATE KEY UPDATE ip = ip")).
+		WithArgs("203.0.113.9").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM form_submissions WHERE ip = ? AND created_at >= ?")).
+		WithArgs("203.0.113.9", since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO form_submissions")).
+		WithArgs(sqlmock.AnyArg(), "f-1", []byte(`{"email":"ana@example.com"}`), ip, sql.NullString{}, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	s := &form.Submission{FormID: "f-1", Data: map[string]string{"email": "ana@example.com"}, IP: "203.0.113.9"}
+	stored, err := repo.CreateSubmissionLimited(context.Background(), s, since, 2)
+	require.NoError(t, err)
+	assert.True(t, stored)
+	assert.NotEmpty(t, s.ID)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO form_rate_limits")).
+		WithArgs("203.0.113.9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM form_submissions")).
+		WithArgs("203.0.113.9", since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectRollback()
+
+	s = &form.Submission{FormID: "f-1", Data: map[string]string{"email": "bo@example.com"}, IP: "203.0.113.9"}
+	stored, err = repo.CreateSubmissionLimited(context.Background(), s, since, 2)
+	require.NoError(t, err)
+	assert.False(t, stored)
+	assert.Empty(t, s.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFormRepository_ListSubmissionsAfter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFormRepository(db)
+
+	now := time.Now()
+	after := &form.Submission{ID: "s-1", CreatedAt: now}
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE form_id = ? AND (created_at < ? OR (created_at = ? AND id > ?)) ORDER BY created_at DESC, id LIMIT ?")).
+		WithArgs("f-1", now, now, "s-1", 10).
+		WillReturnRows(sqlmock.NewRows(submissionColumns).
+			AddRow("s-2", "f-1", []byte(`{"email":"bo@example.com"}`), nil, nil, now))
+
+	submissions, err := repo.ListSubmissionsAfter(context.Background(), "f-1", after, 10)
+	require.NoError(t, err)
+	require.Len(t, submissions, 1)
+	assert.Equal(t, "s-2", submissions[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFormRepository_DeleteSubmissionsBefore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFormRepository(db)
+
+	before := time.Now().AddDate(0, 0, -30)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM form_submissions WHERE form_id = ? AND created_at < ?")).
+		WithArgs("f-1", before).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	purged, err := repo.DeleteSubmissionsBefore(context.Background(), "f-1", before)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+}
+
+func TestFormRepository_DeleteSubmission_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewFormRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM form_submissions WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.DeleteSubmission(context.Background(), "missing"), form.ErrSubmissionNotFound)
+}
@@ -0,0 +1,134 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+)
+
+const selectColumns = `resource_type, resource_id, holder_id, acquired_at, heartbeat_at, expires_at`
+
+// LockRepository implements lock.Repository for MySQL.
+type LockRepository struct {
+	db *sql.DB
+}
+
+// NewLockRepository creates a new MySQL edit lock repository.
+func NewLockRepository(db *sql.DB) lock.Repository {
+	return &LockRepository{db: db}
+}
+
+// Get retrieves the lock of a resource, expired or not.
+func (r *LockRepository) Get(ctx context.Context, resourceType, resourceID string) (*lock.Lock, error) {
+	l, err := scanLock(r.db.QueryRowContext(ctx,
+		`SELECT `+selectColumns+` FROM edit_locks WHERE resource_type = ? AND resource_id = ?`, resourceType, resourceID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, lock.ErrNotFound
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+// Save inserts the lock of a resource or replaces the stored one.
+func (r *LockRepository) Save(ctx context.Context, l *lock.Lock) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO edit_locks (`+selectColumns+`) VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE holder_id = VALUES(holder_id), acquired_at = VALUES(acquired_at),
+			heartbeat_at = VALUES(heartbeat_at), expires_at = VALUES(expires_at)`,
+		l.ResourceType, l.ResourceID, l.HolderID, l.AcquiredAt, l.HeartbeatAt, l.ExpiresAt,
+	)
+	return err
+}
+
+// claimable is the condition under which a claim replaces the stored lock: the claimant already holds
+// it or it expired. MySQL applies the assignments below in order, so expires_at goes last and the
+// condition still sees the stored holder and expiry in every assignment before it.
+const claimable = `(holder_id = VALUES(holder_id) OR expires_at <= ?)`
+
+// Claim saves l unless someone else holds an active lock on its resource at now, and reports whether
+// it did. The check and the write are one statement, so two editors cannot both claim a free lock.
+func (r *LockRepository) Claim(ctx context.Context, l *lock.Lock, now time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO edit_locks (`+selectColumns+`) VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			acquired_at = IF(holder_id = VALUES(holder_id), acquired_at, IF(expires_at <= ?, VALUES(acquired_at), acquired_at)),
+			heartbeat_at = IF(`+claimable+`, VALUES(heartbeat_at), heartbeat_at),
+			holder_id = IF(`+claimable+`, VALUES(holder_id), holder_id),
+			expires_at = IF(`+claimable+`, VALUES(expires_at), expires_at)`,
+		l.ResourceType, l.ResourceID, l.HolderID, l.AcquiredAt, l.HeartbeatAt, l.ExpiresAt,
+		now, now, now, now,
+	)
+	if err != nil {
+		return false, err
+	}
+	// MySQL reports 1 for an inserted row, 2 for a replaced one and 0 when the row was left alone.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Delete releases the lock of a resource if holderID holds it.
+func (r *LockRepository) Delete(ctx context.Context, resourceType, resourceID, holderID string) error {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM edit_locks WHERE resource_type = ? AND resource_id = ? AND holder_id = ?`,
+		resourceType, resourceID, holderID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return lock.ErrNotFound
+	}
+	return nil
+}
+
+// ListActive returns the locks of a resource type that have not expired at the given time.
+func (r *LockRepository) ListActive(ctx context.Context, resourceType string, now time.Time) ([]*lock.Lock, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+selectColumns+` FROM edit_locks WHERE resource_type = ? AND expires_at > ? ORDER BY acquired_at, resource_id`,
+		resourceType, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var locks []*lock.Lock
+	for rows.Next() {
+		l, err := scanLock(rows)
+		if err != nil {
+			return nil, err
+		}
+		locks = append(locks, l)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return locks, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLock(s scanner) (*lock.Lock, error) {
+	l := &lock.Lock{}
+	if err := s.Scan(&l.ResourceType, &l.ResourceID, &l.HolderID, &l.AcquiredAt, &l.HeartbeatAt, &l.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
@@ -0,0 +1,126 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var lockColumns = []string{"resource_type", "resource_id", "holder_id", "acquired_at", "heartbeat_at", "expires_at"}
+
+func TestLockRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLockRepository(db)
+
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO edit_locks")).
+		WithArgs("content", "post-1", "user-1", now, now, now.Add(time.Minute)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	l := &lock.Lock{
+		ResourceType: "content", ResourceID: "post-1", HolderID: "user-1",
+		AcquiredAt: now, HeartbeatAt: now, ExpiresAt: now.Add(time.Minute),
+	}
+	require.NoError(t, repo.Save(context.Background(), l))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLockRepository_Claim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLockRepository(db)
+
+	now := time.Now()
+	l := &lock.Lock{
+		ResourceType: "content", ResourceID: "post-1", HolderID: "user-1",
+		AcquiredAt: now, HeartbeatAt: now, ExpiresAt: now.Add(time.Minute),
+	}
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO edit_locks")).
+		WithArgs("content", "post-1", "user-1", now, now, now.Add(time.Minute), now, now, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	claimed, err := repo.Claim(context.Background(), l, now)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	mock.ExpectExec(regexp.QuoteMeta("holder_id = IF((holder_id = VALUES(holder_id) OR expires_at <= ?), VALUES(holder_id), holder_id)")).
+		WithArgs("content", "post-1", "user-1", now, now, now.Add(time.Minute), now, now, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	claimed, err = repo.Claim(context.Background(), l, now)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLockRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLockRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM edit_locks WHERE resource_type = ? AND resource_id = ?")).
+		WithArgs("content", "post-1").
+		WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("content", "post-1", "user-1", now, now, now.Add(time.Minute)))
+
+	l, err := repo.Get(context.Background(), "content", "post-1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", l.HolderID)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM edit_locks WHERE resource_type = ? AND resource_id = ?")).
+		WithArgs("content", "post-2").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.Get(context.Background(), "content", "post-2")
+	assert.ErrorIs(t, err, lock.ErrNotFound)
+}
+
+func TestLockRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLockRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM edit_locks WHERE resource_type = ? AND resource_id = ? AND holder_id = ?")).
+		WithArgs("content", "post-1", "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Delete(context.Background(), "content", "post-1", "user-1"))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM edit_locks")).
+		WithArgs("content", "post-1", "user-2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Delete(context.Background(), "content", "post-1", "user-2"), lock.ErrNotFound)
+}
+
+func TestLockRepository_ListActive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLockRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM edit_locks WHERE resource_type = ? AND expires_at > ?")).
+		WithArgs("content", now).
+		WillReturnRows(sqlmock.NewRows(lockColumns).
+			AddRow("content", "post-1", "user-1", now, now, now.Add(time.Minute)).
+			AddRow("content", "post-2", "user-2", now, now, now.Add(time.Minute)))
+
+	locks, err := repo.ListActive(context.Background(), "content", now)
+	require.NoError(t, err)
+	require.Len(t, locks, 2)
+	assert.Equal(t, "post-2", locks[1].ResourceID)
+}
@@ -0,0 +1,245 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/repository"
+)
+
+const selectColumns = `id, filename, storage_key, mime_type, size, width, height, focal_x, focal_y, alt_text, uploader_id, created_at, updated_at, version`
+
+// MediaRepository implements media.Repository for MySQL.
+type MediaRepository struct {
+	db *sql.DB
+}
+
+// NewMediaRepository creates a new MySQL media repository.
+func NewMediaRepository(db *sql.DB) media.Repository {
+	return &MediaRepository{db: db}
+}
+
+// Create inserts a new media row into the database.
+func (r *MediaRepository) Create(ctx context.Context, m *media.Media) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+	if m.UpdatedAt.IsZero() {
+		m.UpdatedAt = time.Now()
+	}
+
+	m.Version = 1
+
+	query := `
+		INSERT INTO media (id, filename, storage_key, mime_type, size, width, height, focal_x, focal_y, alt_text, uploader_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		m.ID, m.Filename, m.StorageKey, m.MimeType, m.Size, m.Width, m.Height, m.FocalX, m.FocalY, m.AltText, nullString(m.UploaderID), m.CreatedAt, m.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a media row by ID.
+func (r *MediaRepository) GetByID(ctx context.Context, id string) (*media.Media, error) {
+	query := `SELECT ` + selectColumns + ` FROM media WHERE id = ? AND deleted_at IS NULL`
+	m, err := scanMedia(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, media.ErrNotFound
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// Update updates the editable metadata of a media row if it is still at m.Version, then increments
+// the version.
+func (r *MediaRepository) Update(ctx context.Context, m *media.Media) error {
+	updatedAt := time.Now()
+	res, err := r.db.ExecContext(ctx, `UPDATE media SET filename = ?, alt_text = ?, focal_x = ?, focal_y = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL`,
+		m.Filename, m.AltText, m.FocalX, m.FocalY, updatedAt, m.ID, m.Version,
+	)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByID(ctx, m.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	m.UpdatedAt = updatedAt
+	m.Version++
+	return nil
+}
+
+// Delete moves a media row to the trash.
+func (r *MediaRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE media SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+// List retrieves media rows matching filter, newest first.
+func (r *MediaRepository) List(ctx context.Context, filter media.ListFilter) ([]*media.Media, error) {
+	var (
+		conditions = []string{"deleted_at IS NULL"}
+		args       []interface{}
+	)
+	if filter.MimePrefix != "" {
+		conditions = append(conditions, "mime_type LIKE ?")
+		args = append(args, escapeLike(filter.MimePrefix)+"%")
+	}
+	if filter.UploaderID != "" {
+		conditions = append(conditions, "uploader_id = ?")
+		args = append(args, filter.UploaderID)
+	}
+
+	query := `SELECT ` + selectColumns + ` FROM media WHERE ` + strings.Join(conditions, " AND ") +
+		` ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*media.Media
+	for rows.Next() {
+		m, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetTrashed retrieves a trashed media row by ID.
+func (r *MediaRepository) GetTrashed(ctx context.Context, id string) (*media.Media, error) {
+	query := `SELECT ` + selectColumns + ` FROM media WHERE id = ? AND deleted_at IS NOT NULL`
+	m, err := scanMedia(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, trash.ErrNotFound
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// ListTrashed retrieves trashed media rows, most recently deleted first.
+func (r *MediaRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	query := `SELECT id, filename, deleted_at FROM media WHERE deleted_at IS NOT NULL`
+	var args []interface{}
+	if !filter.DeletedBefore.IsZero() {
+		query += ` AND deleted_at < ?`
+		args = append(args, filter.DeletedBefore)
+	}
+	query += ` ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*trash.Item
+	for rows.Next() {
+		item := &trash.Item{Type: trash.TypeMedia}
+		if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Restore takes a media row out of the trash.
+func (r *MediaRepository) Restore(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE media SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireTrashed(res)
+}
+
+// Purge permanently deletes a trashed media row.
+func (r *MediaRepository) Purge(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM media WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireTrashed(res)
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMedia(s scanner) (*media.Media, error) {
+	m := &media.Media{}
+	var uploaderID sql.NullString
+	err := s.Scan(&m.ID, &m.Filename, &m.StorageKey, &m.MimeType, &m.Size, &m.Width, &m.Height, &m.FocalX, &m.FocalY, &m.AltText,
+		&uploaderID, &m.CreatedAt, &m.UpdatedAt, &m.Version)
+	if err != nil {
+		return nil, err
+	}
+	m.UploaderID = uploaderID.String
+	return m, nil
+}
+
+func requireAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return media.ErrNotFound
+	}
+	return nil
+}
+
+func requireTrashed(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return trash.ErrNotFound
+	}
+	return nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
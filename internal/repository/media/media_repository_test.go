@@ -0,0 +1,148 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var mediaColumns = []string{"id", "filename", "storage_key", "mime_type", "size", "width", "height", "focal_x", "focal_y", "alt_text", "uploader_id", "created_at", "updated_at", "version"}
+
+func TestMediaRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMediaRepository(db)
+
+	m := &media.Media{Filename: "cat.png", StorageKey: "2025/12/abc.png", MimeType: "image/png", Size: 42, Width: 10, Height: 5}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO media")).
+		WithArgs(sqlmock.AnyArg(), m.Filename, m.StorageKey, m.MimeType, m.Size, m.Width, m.Height, m.FocalX, m.FocalY, m.AltText,
+			sql.NullString{}, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.Create(context.Background(), m))
+	assert.NotEmpty(t, m.ID)
+}
+
+func TestMediaRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMediaRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectColumns + " FROM media WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	m, err := repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, media.ErrNotFound)
+	assert.Nil(t, m)
+}
+
+func TestMediaRepository_List_Filters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMediaRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(mediaColumns).
+		AddRow("media-1", "cat.png", "k", "image/png", 42, 10, 5, 0.25, 0.75, "A cat", "user-1", now, now, 1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM media WHERE deleted_at IS NULL AND mime_type LIKE ? AND uploader_id = ? ORDER BY created_at DESC LIMIT ? OFFSET ?")).
+		WithArgs("image/%", "user-1", 10, 0).
+		WillReturnRows(rows)
+
+	items, err := repo.List(context.Background(), media.ListFilter{MimePrefix: "image/", UploaderID: "user-1", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "user-1", items[0].UploaderID)
+	assert.Equal(t, 10, items[0].Width)
+	assert.Equal(t, 0.25, items[0].FocalX)
+}
+
+func TestMediaRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMediaRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE media SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), "missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), "missing"), media.ErrNotFound)
+}
+
+func TestMediaRepository_GetTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMediaRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM media WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("media-1").
+		WillReturnRows(sqlmock.NewRows(mediaColumns).
+			AddRow("media-1", "a.png", "2025/01/a.png", "image/png", 10, 1, 1, 0.5, 0.5, "", nil, now, now, 1))
+
+	m, err := repo.GetTrashed(context.Background(), "media-1")
+	require.NoError(t, err)
+	assert.Equal(t, "2025/01/a.png", m.StorageKey)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM media WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("media-2").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetTrashed(context.Background(), "media-2")
+	assert.ErrorIs(t, err, trash.ErrNotFound)
+}
+
+func TestMediaRepository_RestoreAndPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMediaRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE media SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("media-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Restore(context.Background(), "media-1"))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM media WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("media-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Purge(context.Background(), "media-1"), trash.ErrNotFound)
+}
+
+func TestMediaRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMediaRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("WHERE id = ? AND version = ?")).
+		WithArgs("cat.png", "A cat", 0.5, 0.5, sqlmock.AnyArg(), "media-1", 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	m := &media.Media{ID: "media-1", Filename: "cat.png", AltText: "A cat", FocalX: 0.5, FocalY: 0.5, Version: 4}
+	require.NoError(t, repo.Update(context.Background(), m))
+	assert.Equal(t, 5, m.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,298 @@
+package menu
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/menu"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/repository"
+)
+
+const (
+	selectColumns     = `id, name, title, created_at, updated_at, version`
+	selectItemColumns = `id, menu_id, parent_id, label, link_type, target_id, url, position, new_tab`
+)
+
+// MenuRepository implements menu.Repository for MySQL.
+type MenuRepository struct {
+	db *sql.DB
+}
+
+// NewMenuRepository creates a new MySQL menu repository.
+func NewMenuRepository(db *sql.DB) menu.Repository {
+	return &MenuRepository{db: db}
+}
+
+// Create inserts a new menu into the database.
+func (r *MenuRepository) Create(ctx context.Context, m *menu.Menu) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+	if m.UpdatedAt.IsZero() {
+		m.UpdatedAt = time.Now()
+	}
+
+	m.Version = 1
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO menus (id, name, title, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		m.ID, m.Name, m.Title, m.CreatedAt, m.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a menu by ID.
+func (r *MenuRepository) GetByID(ctx context.Context, id string) (*menu.Menu, error) {
+	query := `SELECT ` + selectColumns + ` FROM menus WHERE id = ? AND deleted_at IS NULL`
+	return scanOne(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByName retrieves a menu by its name.
+func (r *MenuRepository) GetByName(ctx context.Context, name string) (*menu.Menu, error) {
+	query := `SELECT ` + selectColumns + ` FROM menus WHERE name = ? AND deleted_at IS NULL`
+	return scanOne(r.db.QueryRowContext(ctx, query, name))
+}
+
+// NameInUse reports whether a menu other than exceptID, trashed or not, holds the name.
+func (r *MenuRepository) NameInUse(ctx context.Context, name, exceptID string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM menus WHERE name = ? AND id <> ?`, name, exceptID,
+	).Scan(&count)
+	return count > 0, err
+}
+
+// Update updates an existing menu if it is still at m.Version, then increments the version.
+func (r *MenuRepository) Update(ctx context.Context, m *menu.Menu) error {
+	updatedAt := time.Now()
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE menus SET name = ?, title = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL`,
+		m.Name, m.Title, updatedAt, m.ID, m.Version,
+	)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByID(ctx, m.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	m.UpdatedAt = updatedAt
+	m.Version++
+	return nil
+}
+
+// Delete moves a menu to the trash together with its items.
+func (r *MenuRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE menus SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+// List retrieves all menus ordered by name.
+func (r *MenuRepository) List(ctx context.Context) ([]*menu.Menu, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+selectColumns+` FROM menus WHERE deleted_at IS NULL ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var menus []*menu.Menu
+	for rows.Next() {
+		m, err := scanMenu(rows)
+		if err != nil {
+			return nil, err
+		}
+		menus = append(menus, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return menus, nil
+}
+
+// ListItems retrieves the items of a menu ordered by position.
+func (r *MenuRepository) ListItems(ctx context.Context, menuID string) ([]*menu.Item, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+selectItemColumns+` FROM menu_items WHERE menu_id = ? ORDER BY position`, menuID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*menu.Item
+	for rows.Next() {
+		item := &menu.Item{}
+		var parentID, targetID, url sql.NullString
+		if err := rows.Scan(&item.ID, &item.MenuID, &parentID, &item.Label, &item.LinkType, &targetID, &url, &item.Position, &item.NewTab); err != nil {
+			return nil, err
+		}
+		item.ParentID = parentID.String
+		item.TargetID = targetID.String
+		item.URL = url.String
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// ReplaceItems deletes the items of a menu and inserts items in their place. Items are inserted in
+// order, so parents must precede their children. The items are part of the menu, so the swap is
+// guarded by and increments the version of the menu.
+func (r *MenuRepository) ReplaceItems(ctx context.Context, m *menu.Menu, items []*menu.Item) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	updatedAt := time.Now()
+	res, err := tx.ExecContext(ctx, `UPDATE menus SET updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL`, updatedAt, m.ID, m.Version)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByID(ctx, m.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM menu_items WHERE menu_id = ?`, m.ID); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.ID == "" {
+			item.ID = uuid.New().String()
+		}
+		item.MenuID = m.ID
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO menu_items (`+selectItemColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			item.ID, item.MenuID, nullString(item.ParentID), item.Label, item.LinkType, nullString(item.TargetID), nullString(item.URL), item.Position, item.NewTab,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	m.UpdatedAt = updatedAt
+	m.Version++
+	return nil
+}
+
+// ListTrashed retrieves trashed menus, most recently deleted first.
+func (r *MenuRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	query := `SELECT id, title, deleted_at FROM menus WHERE deleted_at IS NOT NULL`
+	var args []interface{}
+	if !filter.DeletedBefore.IsZero() {
+		query += ` AND deleted_at < ?`
+		args = append(args, filter.DeletedBefore)
+	}
+	query += ` ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*trash.Item
+	for rows.Next() {
+		item := &trash.Item{Type: trash.TypeMenu}
+		if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Restore takes a menu and its items out of the trash.
+func (r *MenuRepository) Restore(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE menus SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireTrashed(res)
+}
+
+// Purge permanently deletes a trashed menu; its items go with it through the foreign key.
+func (r *MenuRepository) Purge(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM menus WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireTrashed(res)
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOne(row *sql.Row) (*menu.Menu, error) {
+	m, err := scanMenu(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, menu.ErrNotFound
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func scanMenu(s scanner) (*menu.Menu, error) {
+	m := &menu.Menu{}
+	if err := s.Scan(&m.ID, &m.Name, &m.Title, &m.CreatedAt, &m.UpdatedAt, &m.Version); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func requireAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return menu.ErrNotFound
+	}
+	return nil
+}
+
+func requireTrashed(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return trash.ErrNotFound
+	}
+	return nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,215 @@
+package menu
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/menu"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var itemColumns = []string{"id", "menu_id", "parent_id", "label", "link_type", "target_id", "url", "position", "new_tab"}
+
+func TestMenuRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMenuRepository(db)
+
+	m := &menu.Menu{Name: "header", Title: "Header"}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO menus")).
+		WithArgs(sqlmock.AnyArg(), "header", "Header", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.Create(context.Background(), m))
+	assert.NotEmpty(t, m.ID)
+}
+
+func TestMenuRepository_GetByName_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMenuRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM menus WHERE name = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByName(context.Background(), "missing")
+	assert.ErrorIs(t, err, menu.ErrNotFound)
+}
+
+func TestMenuRepository_Update_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMenuRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE menus SET name = ?, title = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ?")).
+		WithArgs("header", "Header", sqlmock.AnyArg(), "menu-1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM menus WHERE id = ?")).
+		WithArgs("menu-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "title", "created_at", "updated_at", "version"}).
+			AddRow("menu-1", "header", "Header", time.Now(), time.Now(), 2))
+
+	err = repo.Update(context.Background(), &menu.Menu{ID: "menu-1", Name: "header", Title: "Header", Version: 1})
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
+}
+
+func TestMenuRepository_NameInUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMenuRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM menus WHERE name = ? AND id <> ?")).
+		WithArgs("header", "").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	inUse, err := repo.NameInUse(context.Background(), "header", "")
+	require.NoError(t, err)
+	assert.False(t, inUse)
+}
+
+func TestMenuRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMenuRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE menus SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), "menu-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Delete(context.Background(), "menu-1"))
+}
+
+func TestMenuRepository_ListTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMenuRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM menus WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?")).
+		WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}).AddRow("menu-1", "Header", time.Now()))
+
+	items, err := repo.ListTrashed(context.Background(), trash.ListFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, trash.TypeMenu, items[0].Type)
+}
+
+func TestMenuRepository_RestoreAndPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMenuRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE menus SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("menu-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Restore(context.Background(), "menu-1"))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM menus WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("menu-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Purge(context.Background(), "menu-1"), trash.ErrNotFound)
+}
+
+func TestMenuRepository_ListItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMenuRepository(db)
+
+	rows := sqlmock.NewRows(itemColumns).
+		AddRow("i-1", "menu-1", nil, "Blog", "category", "cat-1", nil, 0, false).
+		AddRow("i-2", "menu-1", "i-1", "Docs", "url", nil, "https://docs.example.com", 1, true)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM menu_items WHERE menu_id = ? ORDER BY position")).
+		WithArgs("menu-1").
+		WillReturnRows(rows)
+
+	items, err := repo.ListItems(context.Background(), "menu-1")
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "cat-1", items[0].TargetID)
+	assert.Empty(t, items[0].ParentID)
+	assert.Equal(t, "i-1", items[1].ParentID)
+	assert.Equal(t, "https://docs.example.com", items[1].URL)
+	assert.True(t, items[1].NewTab)
+}
+
+func TestMenuRepository_ReplaceItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMenuRepository(db)
+
+	items := []*menu.Item{
+		{ID: "i-1", Label: "Home", LinkType: menu.LinkURL, URL: "/", Position: 0},
+		{ID: "i-2", ParentID: "i-1", Label: "About", LinkType: menu.LinkContent, TargetID: "page-1", Position: 1},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE menus SET updated_at = ?, version = version + 1 WHERE id = ? AND version = ?")).
+		WithArgs(sqlmock.AnyArg(), "menu-1", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM menu_items WHERE menu_id = ?")).
+		WithArgs("menu-1").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO menu_items")).
+		WithArgs("i-1", "menu-1", sql.NullString{}, "Home", "url", sql.NullString{}, sql.NullString{String: "/", Valid: true}, 0, false).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO menu_items")).
+		WithArgs("i-2", "menu-1", sql.NullString{String: "i-1", Valid: true}, "About", "content", sql.NullString{String: "page-1", Valid: true}, sql.NullString{}, 1, false).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	m := &menu.Menu{ID: "menu-1", Version: 2}
+	require.NoError(t, repo.ReplaceItems(context.Background(), m, items))
+	assert.Equal(t, "menu-1", items[1].MenuID)
+	assert.Equal(t, 3, m.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMenuRepository_ReplaceItems_RollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMenuRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE menus")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM menu_items WHERE menu_id = ?")).
+		WithArgs("menu-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO menu_items")).
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	err = repo.ReplaceItems(context.Background(), &menu.Menu{ID: "menu-1", Version: 1}, []*menu.Item{{Label: "Home", LinkType: menu.LinkURL, URL: "/"}})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,119 @@
+package preview
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/preview"
+)
+
+const selectColumns = `id, content_id, revision, created_by, expires_at, revoked_at, created_at`
+
+// PreviewRepository implements preview.Repository for MySQL.
+type PreviewRepository struct {
+	db *sql.DB
+}
+
+// NewPreviewRepository creates a new MySQL preview link repository.
+func NewPreviewRepository(db *sql.DB) preview.Repository {
+	return &PreviewRepository{db: db}
+}
+
+// Create inserts a new preview link into the database.
+func (r *PreviewRepository) Create(ctx context.Context, l *preview.Link) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO preview_links (`+selectColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		l.ID, l.ContentID, l.Revision, nullString(l.CreatedBy), l.ExpiresAt, nullTime(l.RevokedAt), l.CreatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a preview link by ID.
+func (r *PreviewRepository) GetByID(ctx context.Context, id string) (*preview.Link, error) {
+	l, err := scanLink(r.db.QueryRowContext(ctx, `SELECT `+selectColumns+` FROM preview_links WHERE id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, preview.ErrNotFound
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+// ListByContent retrieves the preview links of a content entry, newest first.
+func (r *PreviewRepository) ListByContent(ctx context.Context, contentID string) ([]*preview.Link, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+selectColumns+` FROM preview_links WHERE content_id = ? ORDER BY created_at DESC, id`, contentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*preview.Link
+	for rows.Next() {
+		l, err := scanLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// Revoke marks a preview link as revoked.
+func (r *PreviewRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE preview_links SET revoked_at = ? WHERE id = ?`, at, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return preview.ErrNotFound
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLink(s scanner) (*preview.Link, error) {
+	l := &preview.Link{}
+	var createdBy sql.NullString
+	var revokedAt sql.NullTime
+	if err := s.Scan(&l.ID, &l.ContentID, &l.Revision, &createdBy, &l.ExpiresAt, &revokedAt, &l.CreatedAt); err != nil {
+		return nil, err
+	}
+	l.CreatedBy = createdBy.String
+	if revokedAt.Valid {
+		l.RevokedAt = revokedAt.Time
+	}
+	return l, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
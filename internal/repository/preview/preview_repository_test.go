@@ -0,0 +1,95 @@
+package preview
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/preview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var linkColumns = []string{"id", "content_id", "revision", "created_by", "expires_at", "revoked_at", "created_at"}
+
+func TestPreviewRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPreviewRepository(db)
+
+	expiresAt := time.Now().Add(time.Hour)
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO preview_links")).
+		WithArgs("link-1", "post-1", "3", sql.NullString{String: "user-1", Valid: true}, expiresAt, sql.NullTime{}, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	l := &preview.Link{ID: "link-1", ContentID: "post-1", Revision: "3", CreatedBy: "user-1", ExpiresAt: expiresAt}
+	require.NoError(t, repo.Create(context.Background(), l))
+	assert.False(t, l.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreviewRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPreviewRepository(db)
+
+	revokedAt := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM preview_links WHERE id = ?")).
+		WithArgs("link-1").
+		WillReturnRows(sqlmock.NewRows(linkColumns).AddRow("link-1", "post-1", "3", nil, time.Now(), revokedAt, time.Now()))
+
+	l, err := repo.GetByID(context.Background(), "link-1")
+	require.NoError(t, err)
+	assert.Equal(t, "post-1", l.ContentID)
+	assert.Empty(t, l.CreatedBy)
+	assert.Equal(t, revokedAt, l.RevokedAt)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM preview_links WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, preview.ErrNotFound)
+}
+
+func TestPreviewRepository_ListByContent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPreviewRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM preview_links WHERE content_id = ? ORDER BY created_at DESC")).
+		WithArgs("post-1").
+		WillReturnRows(sqlmock.NewRows(linkColumns).
+			AddRow("link-2", "post-1", "2", "user-1", time.Now(), nil, time.Now()).
+			AddRow("link-1", "post-1", "1", "user-1", time.Now(), nil, time.Now()))
+
+	links, err := repo.ListByContent(context.Background(), "post-1")
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, "link-2", links[0].ID)
+	assert.True(t, links[0].RevokedAt.IsZero())
+}
+
+func TestPreviewRepository_Revoke(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPreviewRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE preview_links SET revoked_at = ? WHERE id = ?")).
+		WithArgs(sqlmock.AnyArg(), "missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Revoke(context.Background(), "missing", time.Now())
+	assert.ErrorIs(t, err, preview.ErrNotFound)
+}
@@ -0,0 +1,173 @@
+package redirect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/redirect"
+	"github.com/mashurimansur/goCMS/internal/repository"
+)
+
+const selectColumns = `id, source, match_type, target, status_code, position, enabled, hits, last_hit_at, created_at, updated_at, version`
+
+// RedirectRepository implements redirect.Repository for MySQL.
+type RedirectRepository struct {
+	db *sql.DB
+}
+
+// NewRedirectRepository creates a new MySQL redirect rule repository.
+func NewRedirectRepository(db *sql.DB) redirect.Repository {
+	return &RedirectRepository{db: db}
+}
+
+// Create inserts a new redirect rule into the database.
+func (r *RedirectRepository) Create(ctx context.Context, rule *redirect.Rule) error {
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+	}
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = time.Now()
+	}
+	if rule.UpdatedAt.IsZero() {
+		rule.UpdatedAt = time.Now()
+	}
+
+	rule.Version = 1
+
+	query := `
+		INSERT INTO redirect_rules (id, source, match_type, target, status_code, position, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		rule.ID, rule.Source, rule.MatchType, rule.Target, rule.StatusCode, rule.Position, rule.Enabled, rule.CreatedAt, rule.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a redirect rule by ID.
+func (r *RedirectRepository) GetByID(ctx context.Context, id string) (*redirect.Rule, error) {
+	query := `SELECT ` + selectColumns + ` FROM redirect_rules WHERE id = ?`
+	rule, err := scanRule(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, redirect.ErrNotFound
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Update updates an existing redirect rule if it is still at rule.Version, then increments the
+// version. Hit counters are left untouched and do not count as changes.
+func (r *RedirectRepository) Update(ctx context.Context, rule *redirect.Rule) error {
+	updatedAt := time.Now()
+	query := `
+		UPDATE redirect_rules
+		SET source = ?, match_type = ?, target = ?, status_code = ?, position = ?, enabled = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ?
+	`
+	res, err := r.db.ExecContext(ctx, query,
+		rule.Source, rule.MatchType, rule.Target, rule.StatusCode, rule.Position, rule.Enabled, updatedAt, rule.ID, rule.Version,
+	)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByID(ctx, rule.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	rule.UpdatedAt = updatedAt
+	rule.Version++
+	return nil
+}
+
+// Delete deletes a redirect rule by ID.
+func (r *RedirectRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM redirect_rules WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+// List retrieves redirect rules ordered by position with pagination.
+func (r *RedirectRepository) List(ctx context.Context, limit, offset int) ([]*redirect.Rule, error) {
+	query := `SELECT ` + selectColumns + ` FROM redirect_rules ORDER BY position, created_at LIMIT ? OFFSET ?`
+	return r.query(ctx, query, limit, offset)
+}
+
+// ListEnabled retrieves all enabled redirect rules ordered by position.
+func (r *RedirectRepository) ListEnabled(ctx context.Context) ([]*redirect.Rule, error) {
+	query := `SELECT ` + selectColumns + ` FROM redirect_rules WHERE enabled = 1 ORDER BY position, created_at`
+	return r.query(ctx, query)
+}
+
+// IncrementHits adds hits to the counter of a rule and records when it was last hit.
+func (r *RedirectRepository) IncrementHits(ctx context.Context, id string, hits int64, at time.Time) error {
+	query := `UPDATE redirect_rules SET hits = hits + ?, last_hit_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, hits, at, id)
+	return err
+}
+
+func (r *RedirectRepository) query(ctx context.Context, query string, args ...interface{}) ([]*redirect.Rule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*redirect.Rule
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRule(s scanner) (*redirect.Rule, error) {
+	rule := &redirect.Rule{}
+	var lastHitAt sql.NullTime
+
+	err := s.Scan(
+		&rule.ID, &rule.Source, &rule.MatchType, &rule.Target, &rule.StatusCode, &rule.Position, &rule.Enabled,
+		&rule.Hits, &lastHitAt, &rule.CreatedAt, &rule.UpdatedAt, &rule.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastHitAt.Valid {
+		rule.LastHitAt = lastHitAt.Time
+	}
+
+	return rule, nil
+}
+
+func requireAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return redirect.ErrNotFound
+	}
+	return nil
+}
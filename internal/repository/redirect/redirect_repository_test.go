@@ -0,0 +1,116 @@
+package redirect
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/redirect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ruleColumns = []string{"id", "source", "match_type", "target", "status_code", "position", "enabled", "hits", "last_hit_at", "created_at", "updated_at", "version"}
+
+func TestRedirectRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRedirectRepository(db)
+
+	rule := &redirect.Rule{Source: "/old", MatchType: redirect.MatchExact, Target: "/new", StatusCode: 301, Enabled: true}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO redirect_rules")).
+		WithArgs(sqlmock.AnyArg(), "/old", "exact", "/new", 301, 0, true, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.Create(context.Background(), rule))
+	assert.NotEmpty(t, rule.ID)
+}
+
+func TestRedirectRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRedirectRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM redirect_rules WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, redirect.ErrNotFound)
+}
+
+func TestRedirectRepository_ListEnabled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRedirectRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(ruleColumns).
+		AddRow("rule-1", "/old", "exact", "/new", 301, 0, true, 12, now, now, now, 3).
+		AddRow("rule-2", "/blog/*", "wildcard", "/news/$1", 302, 1, true, 0, nil, now, now, 1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE enabled = 1 ORDER BY position")).
+		WillReturnRows(rows)
+
+	rules, err := repo.ListEnabled(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, int64(12), rules[0].Hits)
+	assert.True(t, rules[1].LastHitAt.IsZero())
+}
+
+func TestRedirectRepository_Update_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRedirectRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("WHERE id = ? AND version = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM redirect_rules WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	err = repo.Update(context.Background(), &redirect.Rule{ID: "missing", Version: 1})
+	assert.ErrorIs(t, err, redirect.ErrNotFound)
+}
+
+func TestRedirectRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRedirectRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM redirect_rules")).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), "missing"), redirect.ErrNotFound)
+}
+
+func TestRedirectRepository_IncrementHits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRedirectRepository(db)
+
+	at := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE redirect_rules SET hits = hits + ?, last_hit_at = ? WHERE id = ?")).
+		WithArgs(int64(3), at, "rule-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.IncrementHits(context.Background(), "rule-1", 3, at))
+}
@@ -0,0 +1,99 @@
+package seo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/seo"
+)
+
+const selectColumns = `content_id, meta_title, meta_description, canonical_url, noindex, og_image_id, updated_at`
+
+// sitemapConditions selects published entries, not trashed nor marked noindex, that anonymous readers
+// can reach: public entries and teasers. It takes the status and the current time as arguments.
+const sitemapConditions = `FROM contents c LEFT JOIN content_seo s ON s.content_id = c.id
+	WHERE c.status = ? AND c.published_at <= ? AND c.deleted_at IS NULL AND (s.noindex IS NULL OR s.noindex = FALSE)
+	AND (c.visibility = 'public' OR c.teaser = TRUE)`
+
+// SEORepository implements seo.Repository for MySQL.
+type SEORepository struct {
+	db *sql.DB
+}
+
+// NewSEORepository creates a new MySQL SEO metadata repository.
+func NewSEORepository(db *sql.DB) seo.Repository {
+	return &SEORepository{db: db}
+}
+
+// Get retrieves the SEO metadata of a content entry.
+func (r *SEORepository) Get(ctx context.Context, contentID string) (*seo.Metadata, error) {
+	m := &seo.Metadata{}
+	var title, description, canonical, ogImageID sql.NullString
+	err := r.db.QueryRowContext(ctx, `SELECT `+selectColumns+` FROM content_seo WHERE content_id = ?`, contentID).
+		Scan(&m.ContentID, &title, &description, &canonical, &m.NoIndex, &ogImageID, &m.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, seo.ErrNotFound
+		}
+		return nil, err
+	}
+
+	m.MetaTitle = title.String
+	m.MetaDescription = description.String
+	m.CanonicalURL = canonical.String
+	m.OGImageID = ogImageID.String
+	return m, nil
+}
+
+// Save inserts the SEO metadata of a content entry or replaces the stored one.
+func (r *SEORepository) Save(ctx context.Context, m *seo.Metadata) error {
+	m.UpdatedAt = time.Now()
+	query := `
+		INSERT INTO content_seo (` + selectColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE meta_title = VALUES(meta_title), meta_description = VALUES(meta_description),
+			canonical_url = VALUES(canonical_url), noindex = VALUES(noindex), og_image_id = VALUES(og_image_id), updated_at = VALUES(updated_at)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		m.ContentID, nullString(m.MetaTitle), nullString(m.MetaDescription), nullString(m.CanonicalURL), m.NoIndex, nullString(m.OGImageID), m.UpdatedAt,
+	)
+	return err
+}
+
+// CountSitemapEntries counts the published entries not marked noindex.
+func (r *SEORepository) CountSitemapEntries(ctx context.Context, now time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) `+sitemapConditions, content.StatusPublished, now).Scan(&count)
+	return count, err
+}
+
+// ListSitemapEntries lists the published entries not marked noindex in publication order.
+func (r *SEORepository) ListSitemapEntries(ctx context.Context, now time.Time, limit, offset int) ([]*seo.SitemapEntry, error) {
+	query := `SELECT c.id, c.type, c.slug, c.locale, c.published_at, c.updated_at ` + sitemapConditions + ` ORDER BY c.published_at, c.id LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query, content.StatusPublished, now, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*seo.SitemapEntry
+	for rows.Next() {
+		e := &seo.SitemapEntry{}
+		if err := rows.Scan(&e.ContentID, &e.Type, &e.Slug, &e.Locale, &e.PublishedAt, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,95 @@
+package seo
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/seo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSEORepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSEORepository(db)
+
+	rows := sqlmock.NewRows([]string{"content_id", "meta_title", "meta_description", "canonical_url", "noindex", "og_image_id", "updated_at"}).
+		AddRow("post-1", "Launch", nil, nil, true, "media-1", time.Now())
+	mock.ExpectQuery(regexp.QuoteMeta("FROM content_seo WHERE content_id = ?")).
+		WithArgs("post-1").
+		WillReturnRows(rows)
+
+	m, err := repo.Get(context.Background(), "post-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Launch", m.MetaTitle)
+	assert.Empty(t, m.MetaDescription)
+	assert.True(t, m.NoIndex)
+	assert.Equal(t, "media-1", m.OGImageID)
+}
+
+func TestSEORepository_Get_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSEORepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM content_seo WHERE content_id = ?")).
+		WithArgs("post-1").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.Get(context.Background(), "post-1")
+	assert.ErrorIs(t, err, seo.ErrNotFound)
+}
+
+func TestSEORepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSEORepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO content_seo")).
+		WithArgs("post-1", sql.NullString{String: "Launch", Valid: true}, sql.NullString{}, sql.NullString{String: "/launch", Valid: true}, false, sql.NullString{}, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	m := &seo.Metadata{ContentID: "post-1", MetaTitle: "Launch", CanonicalURL: "/launch"}
+	require.NoError(t, repo.Save(context.Background(), m))
+	assert.False(t, m.UpdatedAt.IsZero())
+}
+
+func TestSEORepository_ListSitemapEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSEORepository(db)
+
+	now := time.Now()
+	published := now.Add(-time.Hour)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM contents c LEFT JOIN content_seo s")).
+		WithArgs("published", now).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("(s.noindex IS NULL OR s.noindex = FALSE)\n\tAND (c.visibility = 'public' OR c.teaser = TRUE) ORDER BY c.published_at, c.id LIMIT ? OFFSET ?")).
+		WithArgs("published", now, 50000, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "slug", "locale", "published_at", "updated_at"}).
+			AddRow("post-1", "post", "launch", "en", published, now).
+			AddRow("page-1", "page", "about", "en", published, now))
+
+	count, err := repo.CountSitemapEntries(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	entries, err := repo.ListSitemapEntries(context.Background(), now, 50000, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "launch", entries[0].Slug)
+	assert.Equal(t, published, entries[1].PublishedAt)
+}
@@ -0,0 +1,45 @@
+package setting
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/setting"
+)
+
+// SettingRepository implements setting.Repository for MySQL.
+type SettingRepository struct {
+	db *sql.DB
+}
+
+// NewSettingRepository creates a new MySQL settings repository.
+func NewSettingRepository(db *sql.DB) setting.Repository {
+	return &SettingRepository{db: db}
+}
+
+// Get retrieves a setting by key.
+func (r *SettingRepository) Get(ctx context.Context, key string) (*setting.Setting, error) {
+	s := &setting.Setting{}
+	err := r.db.QueryRowContext(ctx, "SELECT `key`, value, updated_at FROM settings WHERE `key` = ?", key).
+		Scan(&s.Key, &s.Value, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, setting.ErrNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save inserts the setting or replaces its stored value.
+func (r *SettingRepository) Save(ctx context.Context, s *setting.Setting) error {
+	s.UpdatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO settings (`key`, value, updated_at) VALUES (?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE value = VALUES(value), updated_at = VALUES(updated_at)",
+		s.Key, s.Value, s.UpdatedAt,
+	)
+	return err
+}
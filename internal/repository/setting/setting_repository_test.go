@@ -0,0 +1,56 @@
+package setting
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSettingRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM settings WHERE `key` = ?")).
+		WithArgs("theme").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "value", "updated_at"}).AddRow("theme", "minimal", now))
+
+	s, err := repo.Get(context.Background(), "theme")
+	require.NoError(t, err)
+	assert.Equal(t, "minimal", s.Value)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM settings WHERE `key` = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.Get(context.Background(), "missing")
+	assert.ErrorIs(t, err, setting.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSettingRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSettingRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO settings")).
+		WithArgs("theme", "minimal", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s := &setting.Setting{Key: "theme", Value: "minimal"}
+	require.NoError(t, repo.Save(context.Background(), s))
+	assert.False(t, s.UpdatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,243 @@
+package snippet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/snippet"
+	"github.com/mashurimansur/goCMS/internal/repository"
+)
+
+const selectColumns = `id, name, title, body, body_format, body_html, blocks, created_at, updated_at, version`
+
+// SnippetRepository implements snippet.Repository for MySQL.
+type SnippetRepository struct {
+	db *sql.DB
+}
+
+// NewSnippetRepository creates a new MySQL snippet repository.
+func NewSnippetRepository(db *sql.DB) snippet.Repository {
+	return &SnippetRepository{db: db}
+}
+
+// Create inserts a new snippet into the database.
+func (r *SnippetRepository) Create(ctx context.Context, s *snippet.Snippet) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now()
+	}
+	if s.UpdatedAt.IsZero() {
+		s.UpdatedAt = time.Now()
+	}
+	s.Version = 1
+
+	blocks, err := marshalBlocks(s.Blocks)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO snippets (id, name, title, body, body_format, body_html, blocks, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.ID, s.Name, s.Title, s.Body, s.BodyFormat, s.BodyHTML, blocks, s.CreatedAt, s.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a snippet by ID.
+func (r *SnippetRepository) GetByID(ctx context.Context, id string) (*snippet.Snippet, error) {
+	query := `SELECT ` + selectColumns + ` FROM snippets WHERE id = ?`
+	return scanOne(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByName retrieves a snippet by its name.
+func (r *SnippetRepository) GetByName(ctx context.Context, name string) (*snippet.Snippet, error) {
+	query := `SELECT ` + selectColumns + ` FROM snippets WHERE name = ?`
+	return scanOne(r.db.QueryRowContext(ctx, query, name))
+}
+
+// ListByNames retrieves the snippets with the given names.
+func (r *SnippetRepository) ListByNames(ctx context.Context, names []string) ([]*snippet.Snippet, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		args[i] = name
+	}
+	query := `SELECT ` + selectColumns + ` FROM snippets WHERE name IN (?` + strings.Repeat(`, ?`, len(names)-1) + `)`
+	return r.query(ctx, query, args...)
+}
+
+// Update updates an existing snippet if it is still at s.Version, then increments the version.
+func (r *SnippetRepository) Update(ctx context.Context, s *snippet.Snippet) error {
+	blocks, err := marshalBlocks(s.Blocks)
+	if err != nil {
+		return err
+	}
+	updatedAt := time.Now()
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE snippets SET title = ?, body = ?, body_format = ?, body_html = ?, blocks = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ?
+	`, s.Title, s.Body, s.BodyFormat, s.BodyHTML, blocks, updatedAt, s.ID, s.Version)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByID(ctx, s.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.UpdatedAt = updatedAt
+	s.Version++
+	return nil
+}
+
+// Delete deletes a snippet by ID.
+func (r *SnippetRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM snippets WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return snippet.ErrNotFound
+	}
+	return nil
+}
+
+// List retrieves snippets ordered by name with pagination.
+func (r *SnippetRepository) List(ctx context.Context, limit, offset int) ([]*snippet.Snippet, error) {
+	query := `SELECT ` + selectColumns + ` FROM snippets ORDER BY name LIMIT ? OFFSET ?`
+	return r.query(ctx, query, limit, offset)
+}
+
+// ListUsages retrieves the entries recorded as referencing the named snippet, most recently updated
+// first.
+func (r *SnippetRepository) ListUsages(ctx context.Context, name string, limit, offset int) ([]*snippet.Usage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.type, c.locale, c.title, c.status FROM contents c
+		JOIN content_snippets cs ON cs.content_id = c.id
+		WHERE cs.name = ? AND c.deleted_at IS NULL
+		ORDER BY c.updated_at DESC LIMIT ? OFFSET ?
+	`, name, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usages := []*snippet.Usage{}
+	for rows.Next() {
+		u := &snippet.Usage{}
+		if err := rows.Scan(&u.ContentID, &u.Type, &u.Locale, &u.Title, &u.Status); err != nil {
+			return nil, err
+		}
+		usages = append(usages, u)
+	}
+	return usages, rows.Err()
+}
+
+// CountUsages counts the entries recorded as referencing each named snippet.
+func (r *SnippetRepository) CountUsages(ctx context.Context, names []string) (map[string]int, error) {
+	counts := make(map[string]int, len(names))
+	if len(names) == 0 {
+		return counts, nil
+	}
+
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		counts[name] = 0
+		args[i] = name
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT cs.name, COUNT(*) FROM content_snippets cs
+		JOIN contents c ON c.id = cs.content_id
+		WHERE cs.name IN (?`+strings.Repeat(`, ?`, len(names)-1)+`) AND c.deleted_at IS NULL
+		GROUP BY cs.name
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var count int
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, err
+		}
+		counts[name] = count
+	}
+	return counts, rows.Err()
+}
+
+func (r *SnippetRepository) query(ctx context.Context, query string, args ...interface{}) ([]*snippet.Snippet, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snippets []*snippet.Snippet
+	for rows.Next() {
+		s, err := scanSnippet(rows)
+		if err != nil {
+			return nil, err
+		}
+		snippets = append(snippets, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return snippets, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOne(row *sql.Row) (*snippet.Snippet, error) {
+	s, err := scanSnippet(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, snippet.ErrNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func scanSnippet(row scanner) (*snippet.Snippet, error) {
+	s := &snippet.Snippet{}
+	var body, bodyHTML sql.NullString
+	var blocks []byte
+	if err := row.Scan(&s.ID, &s.Name, &s.Title, &body, &s.BodyFormat, &bodyHTML, &blocks, &s.CreatedAt, &s.UpdatedAt, &s.Version); err != nil {
+		return nil, err
+	}
+	s.Body = body.String
+	s.BodyHTML = bodyHTML.String
+	if len(blocks) > 0 {
+		if err := json.Unmarshal(blocks, &s.Blocks); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func marshalBlocks(blocks []block.Block) (interface{}, error) {
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(blocks)
+}
@@ -0,0 +1,123 @@
+package snippet
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/snippet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var columns = []string{"id", "name", "title", "body", "body_format", "body_html", "blocks", "created_at", "updated_at", "version"}
+
+func TestSnippetRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSnippetRepository(db)
+
+	s := &snippet.Snippet{Name: "footer", Title: "Footer", Body: "Bye", BodyFormat: "markdown", BodyHTML: "<p>Bye</p>"}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO snippets")).
+		WithArgs(sqlmock.AnyArg(), "footer", "Footer", "Bye", "markdown", "<p>Bye</p>", nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.Create(context.Background(), s))
+	assert.NotEmpty(t, s.ID)
+	assert.Equal(t, 1, s.Version)
+}
+
+func TestSnippetRepository_ListByNames(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSnippetRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM snippets WHERE name IN (?, ?)")).
+		WithArgs("footer", "promo").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("s-1", "promo", "Promo", nil, "blocks", "<p>Sale</p>", []byte(`[{"type":"paragraph","data":{"text":"Sale"}}]`), now, now, 3))
+
+	snippets, err := repo.ListByNames(context.Background(), []string{"footer", "promo"})
+	require.NoError(t, err)
+	require.Len(t, snippets, 1)
+	assert.Equal(t, "promo", snippets[0].Name)
+	assert.Equal(t, 3, snippets[0].Version)
+	assert.Len(t, snippets[0].Blocks, 1)
+}
+
+func TestSnippetRepository_GetByName_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSnippetRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM snippets WHERE name = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByName(context.Background(), "missing")
+	assert.ErrorIs(t, err, snippet.ErrNotFound)
+}
+
+func TestSnippetRepository_Update_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSnippetRepository(db)
+
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE snippets SET title = ?")).
+		WithArgs("Footer", "Bye", "markdown", "<p>Bye</p>", nil, sqlmock.AnyArg(), "s-1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM snippets WHERE id = ?")).
+		WithArgs("s-1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("s-1", "footer", "Footer", "Bye", "markdown", "<p>Bye</p>", nil, now, now, 2))
+
+	err = repo.Update(context.Background(), &snippet.Snippet{ID: "s-1", Title: "Footer", Body: "Bye", BodyFormat: "markdown", BodyHTML: "<p>Bye</p>", Version: 1})
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
+}
+
+func TestSnippetRepository_ListUsages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSnippetRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("JOIN content_snippets cs ON cs.content_id = c.id WHERE cs.name = ? AND c.deleted_at IS NULL")).
+		WithArgs("promo_2025", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "locale", "title", "status"}).
+			AddRow("c-1", "post", "en", "Hello", "published"))
+
+	usages, err := repo.ListUsages(context.Background(), "promo_2025", 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []*snippet.Usage{{ContentID: "c-1", Type: "post", Locale: "en", Title: "Hello", Status: "published"}}, usages)
+}
+
+func TestSnippetRepository_CountUsages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSnippetRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE cs.name IN (?, ?) AND c.deleted_at IS NULL GROUP BY cs.name")).
+		WithArgs("footer", "promo").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "count"}).AddRow("footer", 14))
+
+	counts, err := repo.CountUsages(context.Background(), []string{"footer", "promo"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"footer": 14, "promo": 0}, counts)
+}
@@ -0,0 +1,280 @@
+package tag
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/repository"
+)
+
+const selectColumns = `id, name, slug, created_at, updated_at, version`
+
+// TagRepository implements tag.Repository for MySQL.
+type TagRepository struct {
+	db *sql.DB
+}
+
+// NewTagRepository creates a new MySQL tag repository.
+func NewTagRepository(db *sql.DB) tag.Repository {
+	return &TagRepository{db: db}
+}
+
+// Create inserts a new tag into the database.
+func (r *TagRepository) Create(ctx context.Context, t *tag.Tag) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+	if t.UpdatedAt.IsZero() {
+		t.UpdatedAt = time.Now()
+	}
+
+	t.Version = 1
+
+	query := `INSERT INTO tags (id, name, slug, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, t.ID, t.Name, t.Slug, t.CreatedAt, t.UpdatedAt)
+	return err
+}
+
+// GetByID retrieves a tag by ID.
+func (r *TagRepository) GetByID(ctx context.Context, id string) (*tag.Tag, error) {
+	query := `SELECT ` + selectColumns + ` FROM tags WHERE id = ? AND deleted_at IS NULL`
+	return scanOne(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetBySlug retrieves a tag by slug.
+func (r *TagRepository) GetBySlug(ctx context.Context, slug string) (*tag.Tag, error) {
+	query := `SELECT ` + selectColumns + ` FROM tags WHERE slug = ? AND deleted_at IS NULL`
+	return scanOne(r.db.QueryRowContext(ctx, query, slug))
+}
+
+// SlugInUse reports whether a tag other than exceptID, trashed or not, holds the slug.
+func (r *TagRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM tags WHERE slug = ? AND id <> ?`, slug, exceptID,
+	).Scan(&count)
+	return count > 0, err
+}
+
+// Update updates an existing tag if it is still at t.Version, then increments the version.
+func (r *TagRepository) Update(ctx context.Context, t *tag.Tag) error {
+	updatedAt := time.Now()
+	res, err := r.db.ExecContext(ctx, `UPDATE tags SET name = ?, slug = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL`,
+		t.Name, t.Slug, updatedAt, t.ID, t.Version,
+	)
+	if err != nil {
+		return err
+	}
+	err = repository.RequireVersion(res, func() error {
+		_, err := r.GetByID(ctx, t.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	t.UpdatedAt = updatedAt
+	t.Version++
+	return nil
+}
+
+// Delete moves a tag to the trash.
+func (r *TagRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE tags SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+// List retrieves tags ordered by name with pagination.
+func (r *TagRepository) List(ctx context.Context, limit, offset int) ([]*tag.Tag, error) {
+	query := `SELECT ` + selectColumns + ` FROM tags WHERE deleted_at IS NULL ORDER BY name LIMIT ? OFFSET ?`
+	return r.query(ctx, query, limit, offset)
+}
+
+// Search retrieves tags whose name starts with prefix.
+func (r *TagRepository) Search(ctx context.Context, prefix string, limit int) ([]*tag.Tag, error) {
+	query := `SELECT ` + selectColumns + ` FROM tags WHERE name LIKE ? AND deleted_at IS NULL ORDER BY name LIMIT ?`
+	return r.query(ctx, query, escapeLike(prefix)+"%", limit)
+}
+
+// ListByContent retrieves the tags assigned to a content entry.
+func (r *TagRepository) ListByContent(ctx context.Context, contentID string) ([]*tag.Tag, error) {
+	query := `
+		SELECT t.id, t.name, t.slug, t.created_at, t.updated_at, t.version
+		FROM tags t
+		JOIN content_tags ct ON ct.tag_id = t.id
+		WHERE ct.content_id = ? AND t.deleted_at IS NULL
+		ORDER BY t.name
+	`
+	return r.query(ctx, query, contentID)
+}
+
+// SetContentTags replaces the tags assigned to a content entry. Assignments to trashed tags are kept
+// so that restoring a tag brings them back.
+func (r *TagRepository) SetContentTags(ctx context.Context, contentID string, tagIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM content_tags
+		WHERE content_id = ? AND tag_id IN (SELECT id FROM tags WHERE deleted_at IS NULL)
+	`, contentID); err != nil {
+		return err
+	}
+	for _, tagID := range tagIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO content_tags (content_id, tag_id) VALUES (?, ?)`, contentID, tagID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Merge moves every content assignment from sourceID to targetID and deletes the source tag.
+func (r *TagRepository) Merge(ctx context.Context, sourceID, targetID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// INSERT IGNORE skips content that already carries the target tag.
+	if _, err := tx.ExecContext(ctx, `
+		INSERT IGNORE INTO content_tags (content_id, tag_id)
+		SELECT content_id, ? FROM content_tags WHERE tag_id = ?
+	`, targetID, sourceID); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = ? AND deleted_at IS NULL`, sourceID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(res); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListTrashed retrieves trashed tags, most recently deleted first.
+func (r *TagRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	query := `SELECT id, name, deleted_at FROM tags WHERE deleted_at IS NOT NULL`
+	var args []interface{}
+	if !filter.DeletedBefore.IsZero() {
+		query += ` AND deleted_at < ?`
+		args = append(args, filter.DeletedBefore)
+	}
+	query += ` ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*trash.Item
+	for rows.Next() {
+		item := &trash.Item{Type: trash.TypeTag}
+		if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Restore takes a tag out of the trash.
+func (r *TagRepository) Restore(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE tags SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireTrashed(res)
+}
+
+// Purge permanently deletes a trashed tag. Its content assignments are removed by the foreign key.
+func (r *TagRepository) Purge(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM tags WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireTrashed(res)
+}
+
+func (r *TagRepository) query(ctx context.Context, query string, args ...interface{}) ([]*tag.Tag, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*tag.Tag
+	for rows.Next() {
+		t := &tag.Tag{}
+		if err := rows.Scan(&t.ID, &t.Name, &t.Slug, &t.CreatedAt, &t.UpdatedAt, &t.Version); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func scanOne(row *sql.Row) (*tag.Tag, error) {
+	t := &tag.Tag{}
+	err := row.Scan(&t.ID, &t.Name, &t.Slug, &t.CreatedAt, &t.UpdatedAt, &t.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, tag.ErrNotFound
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func requireAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return tag.ErrNotFound
+	}
+	return nil
+}
+
+func requireTrashed(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return trash.ErrNotFound
+	}
+	return nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
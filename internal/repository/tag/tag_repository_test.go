@@ -0,0 +1,228 @@
+package tag
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tagColumns = []string{"id", "name", "slug", "created_at", "updated_at", "version"}
+
+func TestTagRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	tg := &tag.Tag{Name: "Go", Slug: "go"}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO tags")).
+		WithArgs(sqlmock.AnyArg(), tg.Name, tg.Slug, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.Create(context.Background(), tg))
+	assert.NotEmpty(t, tg.ID)
+	assert.Equal(t, 1, tg.Version)
+}
+
+func TestTagRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tags SET name = ?, slug = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ?")).
+		WithArgs("Go", "go", sqlmock.AnyArg(), "tag-1", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tg := &tag.Tag{ID: "tag-1", Name: "Go", Slug: "go", Version: 2}
+	require.NoError(t, repo.Update(context.Background(), tg))
+	assert.Equal(t, 3, tg.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_Update_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tags")).
+		WithArgs("Go", "go", sqlmock.AnyArg(), "tag-1", 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM tags WHERE id = ?")).
+		WithArgs("tag-1").
+		WillReturnRows(sqlmock.NewRows(tagColumns).AddRow("tag-1", "Golang", "golang", time.Now(), time.Now(), 3))
+
+	tg := &tag.Tag{ID: "tag-1", Name: "Go", Slug: "go", Version: 2}
+	err = repo.Update(context.Background(), tg)
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
+	assert.Equal(t, 2, tg.Version)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tags")).
+		WithArgs("Go", "go", sqlmock.AnyArg(), "missing", 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM tags WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	err = repo.Update(context.Background(), &tag.Tag{ID: "missing", Name: "Go", Slug: "go", Version: 2})
+	assert.ErrorIs(t, err, tag.ErrNotFound)
+}
+
+func TestTagRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, slug")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	tg, err := repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, tag.ErrNotFound)
+	assert.Nil(t, tg)
+}
+
+func TestTagRepository_Search_EscapesWildcards(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(tagColumns).AddRow("tag-1", "100%_go", "100-go", now, now, 1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE name LIKE ?")).
+		WithArgs(`100\%\_%`, 5).
+		WillReturnRows(rows)
+
+	tags, err := repo.Search(context.Background(), "100%_", 5)
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "tag-1", tags[0].ID)
+}
+
+func TestTagRepository_Merge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO content_tags")).
+		WithArgs("target", "source").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM tags WHERE id = ?")).
+		WithArgs("source").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Merge(context.Background(), "source", "target"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_Merge_MissingSource(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO content_tags")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM tags WHERE id = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.Merge(context.Background(), "missing", "target")
+	assert.ErrorIs(t, err, tag.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tags SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), "tag-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Delete(context.Background(), "tag-1"))
+}
+
+func TestTagRepository_ListTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	before := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM tags WHERE deleted_at IS NOT NULL AND deleted_at < ? ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?")).
+		WithArgs(before, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deleted_at"}).AddRow("tag-1", "Go", time.Now()))
+
+	items, err := repo.ListTrashed(context.Background(), trash.ListFilter{DeletedBefore: before, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, trash.TypeTag, items[0].Type)
+}
+
+func TestTagRepository_RestoreAndPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tags SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("tag-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Restore(context.Background(), "tag-1"), trash.ErrNotFound)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM tags WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("tag-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Purge(context.Background(), "tag-1"))
+}
+
+func TestTagRepository_SetContentTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("WHERE content_id = ? AND tag_id IN (SELECT id FROM tags WHERE deleted_at IS NULL)")).
+		WithArgs("content-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO content_tags")).
+		WithArgs("content-1", "tag-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.SetContentTags(context.Background(), "content-1", []string{"tag-1"}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
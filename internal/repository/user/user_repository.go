@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
 	"github.com/mashurimansur/goCMS/internal/domain/user"
 )
 
@@ -31,6 +33,7 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 	if u.UpdatedAt.IsZero() {
 		u.UpdatedAt = time.Now()
 	}
+	u.Version = 1
 
 	query := `
 		INSERT INTO users (
@@ -47,9 +50,9 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 // GetByEmail retrieves a user by email.
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	query := `
-		SELECT id, full_name, username, email, phone, password_hash, avatar_url, role, status, last_login, email_verified, phone_verified, created_at, updated_at
+		SELECT id, full_name, username, email, phone, password_hash, avatar_url, role, status, last_login, email_verified, phone_verified, created_at, updated_at, version
 		FROM users
-		WHERE email = ?
+		WHERE email = ? AND deleted_at IS NULL
 	`
 	return r.scanUser(ctx, query, email)
 }
@@ -57,9 +60,9 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.Us
 // GetByID retrieves a user by ID.
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
 	query := `
-		SELECT id, full_name, username, email, phone, password_hash, avatar_url, role, status, last_login, email_verified, phone_verified, created_at, updated_at
+		SELECT id, full_name, username, email, phone, password_hash, avatar_url, role, status, last_login, email_verified, phone_verified, created_at, updated_at, version
 		FROM users
-		WHERE id = ?
+		WHERE id = ? AND deleted_at IS NULL
 	`
 	return r.scanUser(ctx, query, id)
 }
@@ -67,9 +70,9 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*user.User, er
 // GetByUsername retrieves a user by username.
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*user.User, error) {
 	query := `
-		SELECT id, full_name, username, email, phone, password_hash, avatar_url, role, status, last_login, email_verified, phone_verified, created_at, updated_at
+		SELECT id, full_name, username, email, phone, password_hash, avatar_url, role, status, last_login, email_verified, phone_verified, created_at, updated_at, version
 		FROM users
-		WHERE username = ?
+		WHERE username = ? AND deleted_at IS NULL
 	`
 	return r.scanUser(ctx, query, username)
 }
@@ -82,7 +85,7 @@ func (r *UserRepository) scanUser(ctx context.Context, query string, args ...int
 	var username sql.NullString
 
 	err := r.db.QueryRowContext(ctx, query, args...).Scan(
-		&u.ID, &u.FullName, &username, &u.Email, &phone, &u.PasswordHash, &avatarURL, &u.Role, &u.Status, &lastLogin, &u.EmailVerified, &u.PhoneVerified, &u.CreatedAt, &u.UpdatedAt,
+		&u.ID, &u.FullName, &username, &u.Email, &phone, &u.PasswordHash, &avatarURL, &u.Role, &u.Status, &lastLogin, &u.EmailVerified, &u.PhoneVerified, &u.CreatedAt, &u.UpdatedAt, &u.Version,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -107,32 +110,52 @@ func (r *UserRepository) scanUser(ctx context.Context, query string, args ...int
 	return u, nil
 }
 
-// Update updates an existing user.
+// Update updates an existing user if it is still at u.Version, then increments the version.
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
-	u.UpdatedAt = time.Now()
+	updatedAt := time.Now()
 	query := `
 		UPDATE users
-		SET full_name = ?, username = ?, email = ?, phone = ?, avatar_url = ?, role = ?, status = ?, email_verified = ?, phone_verified = ?, updated_at = ?
-		WHERE id = ?
+		SET full_name = ?, username = ?, email = ?, phone = ?, avatar_url = ?, role = ?, status = ?, email_verified = ?, phone_verified = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		u.FullName, u.Username, u.Email, u.Phone, u.AvatarURL, u.Role, u.Status, u.EmailVerified, u.PhoneVerified, u.UpdatedAt, u.ID,
+	res, err := r.db.ExecContext(ctx, query,
+		u.FullName, u.Username, u.Email, u.Phone, u.AvatarURL, u.Role, u.Status, u.EmailVerified, u.PhoneVerified, updatedAt, u.ID, u.Version,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// Like the getters, a missing user is not an error; a user saved in the meantime is.
+		existing, err := r.GetByID(ctx, u.ID)
+		if err != nil || existing == nil {
+			return err
+		}
+		return lock.ErrVersionConflict
+	}
+
+	u.UpdatedAt = updatedAt
+	u.Version++
+	return nil
 }
 
-// Delete deletes a user by ID.
+// Delete moves a user to the trash.
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM users WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, id)
+	query := `UPDATE users SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, time.Now(), id)
 	return err
 }
 
 // List retrieves a list of users with pagination.
 func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*user.User, error) {
 	query := `
-		SELECT id, full_name, username, email, phone, password_hash, avatar_url, role, status, last_login, email_verified, phone_verified, created_at, updated_at
+		SELECT id, full_name, username, email, phone, password_hash, avatar_url, role, status, last_login, email_verified, phone_verified, created_at, updated_at, version
 		FROM users
+		WHERE deleted_at IS NULL
 		LIMIT ? OFFSET ?
 	`
 	rows, err := r.db.QueryContext(ctx, query, limit, offset)
@@ -150,7 +173,7 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*user.U
 		var username sql.NullString
 
 		err := rows.Scan(
-			&u.ID, &u.FullName, &username, &u.Email, &phone, &u.PasswordHash, &avatarURL, &u.Role, &u.Status, &lastLogin, &u.EmailVerified, &u.PhoneVerified, &u.CreatedAt, &u.UpdatedAt,
+			&u.ID, &u.FullName, &username, &u.Email, &phone, &u.PasswordHash, &avatarURL, &u.Role, &u.Status, &lastLogin, &u.EmailVerified, &u.PhoneVerified, &u.CreatedAt, &u.UpdatedAt, &u.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -178,3 +201,65 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*user.U
 
 	return users, nil
 }
+
+// ListTrashed retrieves trashed users, most recently deleted first.
+func (r *UserRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	query := `SELECT id, COALESCE(NULLIF(full_name, ''), username, email), deleted_at FROM users WHERE deleted_at IS NOT NULL`
+	var args []interface{}
+	if !filter.DeletedBefore.IsZero() {
+		query += ` AND deleted_at < ?`
+		args = append(args, filter.DeletedBefore)
+	}
+	query += ` ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*trash.Item
+	for rows.Next() {
+		item := &trash.Item{Type: trash.TypeUser}
+		if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Restore takes a user out of the trash.
+func (r *UserRepository) Restore(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE users SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireTrashed(res)
+}
+
+// Purge permanently deletes a trashed user.
+func (r *UserRepository) Purge(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireTrashed(res)
+}
+
+func requireTrashed(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return trash.ErrNotFound
+	}
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
 	"github.com/mashurimansur/goCMS/internal/domain/user"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -49,8 +51,8 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 	repo := NewUserRepository(db)
 
 	email := "test@example.com"
-	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at"}).
-		AddRow("uuid", "Test User", "testuser", email, "1234567890", "hash", "avatar.jpg", "user", "active", time.Now(), true, true, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at", "version"}).
+		AddRow("uuid", "Test User", "testuser", email, "1234567890", "hash", "avatar.jpg", "user", "active", time.Now(), true, true, time.Now(), time.Now(), 1)
 
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, full_name, username, email")).
 		WithArgs(email).
@@ -86,8 +88,8 @@ func TestUserRepository_GetByID(t *testing.T) {
 	repo := NewUserRepository(db)
 
 	userID := "test-uuid"
-	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at"}).
-		AddRow(userID, "Test User", "testuser", "test@example.com", "1234567890", "hash", "avatar.jpg", "user", "active", time.Now(), true, true, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at", "version"}).
+		AddRow(userID, "Test User", "testuser", "test@example.com", "1234567890", "hash", "avatar.jpg", "user", "active", time.Now(), true, true, time.Now(), time.Now(), 1)
 
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, full_name, username, email")).
 		WithArgs(userID).
@@ -107,8 +109,8 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 	repo := NewUserRepository(db)
 
 	username := "testuser"
-	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at"}).
-		AddRow("uuid", "Test User", username, "test@example.com", "1234567890", "hash", "avatar.jpg", "user", "active", time.Now(), true, true, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at", "version"}).
+		AddRow("uuid", "Test User", username, "test@example.com", "1234567890", "hash", "avatar.jpg", "user", "active", time.Now(), true, true, time.Now(), time.Now(), 1)
 
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, full_name, username, email")).
 		WithArgs(username).
@@ -135,15 +137,36 @@ func TestUserRepository_Update(t *testing.T) {
 		Phone:    "9876543210",
 		Role:     "admin",
 		Status:   "active",
+		Version:  1,
 	}
 
 	mock.ExpectExec(regexp.QuoteMeta("UPDATE users")).
-		WithArgs(u.FullName, u.Username, u.Email, u.Phone, u.AvatarURL, u.Role, u.Status, u.EmailVerified, u.PhoneVerified, sqlmock.AnyArg(), u.ID).
+		WithArgs(u.FullName, u.Username, u.Email, u.Phone, u.AvatarURL, u.Role, u.Status, u.EmailVerified, u.PhoneVerified, sqlmock.AnyArg(), u.ID, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	err = repo.Update(context.Background(), u)
 	assert.NoError(t, err)
 	assert.NotZero(t, u.UpdatedAt)
+	assert.Equal(t, 2, u.Version)
+}
+
+func TestUserRepository_Update_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("WHERE id = ? AND version = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at", "version"}).
+		AddRow("uuid", "Test User", "testuser", "test@example.com", nil, "hash", nil, "user", "active", nil, true, false, time.Now(), time.Now(), 3)
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE id = ?")).
+		WithArgs("uuid").
+		WillReturnRows(rows)
+
+	err = repo.Update(context.Background(), &user.User{ID: "uuid", FullName: "Stale", Version: 2})
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
 }
 
 func TestUserRepository_Delete(t *testing.T) {
@@ -155,14 +178,49 @@ func TestUserRepository_Delete(t *testing.T) {
 
 	userID := "test-uuid"
 
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users WHERE id = ?")).
-		WithArgs(userID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), userID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	err = repo.Delete(context.Background(), userID)
 	assert.NoError(t, err)
 }
 
+func TestUserRepository_ListTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?")).
+		WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}).AddRow("uuid1", "User 1", time.Now()))
+
+	items, err := repo.ListTrashed(context.Background(), trash.ListFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, trash.TypeUser, items[0].Type)
+}
+
+func TestUserRepository_RestoreAndPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("uuid1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Restore(context.Background(), "uuid1"), trash.ErrNotFound)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs("uuid1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Purge(context.Background(), "uuid1"))
+}
+
 func TestUserRepository_List(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -170,9 +228,9 @@ func TestUserRepository_List(t *testing.T) {
 
 	repo := NewUserRepository(db)
 
-	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at"}).
-		AddRow("uuid1", "User 1", "user1", "user1@example.com", "1234567890", "hash", "avatar.jpg", "user", "active", time.Now(), true, true, time.Now(), time.Now()).
-		AddRow("uuid2", "User 2", "user2", "user2@example.com", "9876543210", "hash", "avatar2.jpg", "user", "active", time.Now(), true, true, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at", "version"}).
+		AddRow("uuid1", "User 1", "user1", "user1@example.com", "1234567890", "hash", "avatar.jpg", "user", "active", time.Now(), true, true, time.Now(), time.Now(), 1).
+		AddRow("uuid2", "User 2", "user2", "user2@example.com", "9876543210", "hash", "avatar2.jpg", "user", "active", time.Now(), true, true, time.Now(), time.Now(), 1)
 
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, full_name, username, email")).
 		WithArgs(10, 0).
@@ -191,7 +249,7 @@ func TestUserRepository_List_Empty(t *testing.T) {
 
 	repo := NewUserRepository(db)
 
-	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at"})
+	rows := sqlmock.NewRows([]string{"id", "full_name", "username", "email", "phone", "password_hash", "avatar_url", "role", "status", "last_login", "email_verified", "phone_verified", "created_at", "updated_at", "version"})
 
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, full_name, username, email")).
 		WithArgs(10, 0).
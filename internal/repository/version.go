@@ -0,0 +1,24 @@
+// Package repository holds what the MySQL repositories share.
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+)
+
+// RequireVersion explains a version-guarded update that matched no row: lookup reports whether the
+// row is gone, otherwise it was saved by someone else in the meantime.
+func RequireVersion(res sql.Result, lookup func() error) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+	if err := lookup(); err != nil {
+		return err
+	}
+	return lock.ErrVersionConflict
+}
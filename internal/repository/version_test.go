@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireVersion(t *testing.T) {
+	errGone := errors.New("gone")
+	called := false
+	lookup := func() error {
+		called = true
+		return nil
+	}
+
+	assert.NoError(t, RequireVersion(sqlmock.NewResult(0, 1), lookup))
+	assert.False(t, called)
+
+	assert.ErrorIs(t, RequireVersion(sqlmock.NewResult(0, 0), lookup), lock.ErrVersionConflict)
+	assert.True(t, called)
+
+	err := RequireVersion(sqlmock.NewResult(0, 0), func() error { return errGone })
+	assert.ErrorIs(t, err, errGone)
+}
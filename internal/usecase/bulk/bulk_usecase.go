@@ -0,0 +1,454 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/bulk"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	userusecase "github.com/mashurimansur/goCMS/internal/usecase/user"
+)
+
+var (
+	// ErrInvalidRequest is returned for operations with an unknown action, missing parameters or no
+	// item selection.
+	ErrInvalidRequest = errors.New("invalid bulk request")
+	// ErrTooManyItems is returned when an operation selects more items than allowed.
+	ErrTooManyItems = errors.New("too many items for a bulk operation")
+	// ErrAdminRequired is returned when someone other than an admin runs an operation on users.
+	ErrAdminRequired = errors.New("bulk user operations require the admin role")
+)
+
+// interruptedError is recorded on jobs that were still unfinished when the server stopped.
+const interruptedError = "interrupted by a server restart"
+
+// Defaults used when Options leave a field empty.
+const (
+	DefaultSyncLimit = 50
+	DefaultMaxItems  = 10000
+	// chunkSize is the number of items processed between two saves of a job.
+	chunkSize = 100
+)
+
+// Options configures bulk operations.
+type Options struct {
+	// SyncLimit is the largest number of items an operation processes before responding. Larger
+	// operations run in the background and are followed through their job.
+	SyncLimit int
+	// MaxItems is the largest number of items a single operation may select.
+	MaxItems int
+}
+
+// Request describes a bulk operation. Items are selected by IDs or, when none are given, by a
+// filter that must narrow them down.
+type Request struct {
+	Resource string
+	Action   string
+	IDs      []string
+	Filter   bulk.Filter
+	Params   bulk.Params
+	DryRun   bool
+}
+
+type UseCase interface {
+	// Run starts a bulk operation on behalf of userID. Small operations are processed right away and
+	// the finished job is returned; larger ones are returned pending and processed in the background.
+	// Operations on users are reserved to admins.
+	Run(ctx context.Context, req Request, userID string) (*bulk.Job, error)
+	// Get returns a job with its progress and the results of the items processed so far.
+	Get(ctx context.Context, id string) (*bulk.Job, error)
+	// FailInterrupted marks the jobs a previous run of the server left pending or running as failed,
+	// since nothing processes them any more, and returns how many it marked. It is meant to be called
+	// once at start-up, before any new job runs.
+	FailInterrupted(ctx context.Context) (int, error)
+}
+
+// actions lists the actions of every resource.
+var actions = map[string][]string{
+	bulk.ResourceContent: {
+		bulk.ActionPublish, bulk.ActionUnpublish, bulk.ActionArchive, bulk.ActionDelete,
+		bulk.ActionAddTags, bulk.ActionRemoveTags, bulk.ActionMove,
+	},
+	bulk.ResourceUser: {bulk.ActionDelete, bulk.ActionSetStatus, bulk.ActionSetRole},
+}
+
+type bulkUseCase struct {
+	bulkRepo bulk.Repository
+	contents contentusecase.UseCase
+	users    userusecase.UseCase
+	opts     Options
+}
+
+func NewBulkUseCase(bulkRepo bulk.Repository, contents contentusecase.UseCase, users userusecase.UseCase, opts Options) UseCase {
+	if opts.SyncLimit <= 0 {
+		opts.SyncLimit = DefaultSyncLimit
+	}
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = DefaultMaxItems
+	}
+	return &bulkUseCase{
+		bulkRepo: bulkRepo,
+		contents: contents,
+		users:    users,
+		opts:     opts,
+	}
+}
+
+func (uc *bulkUseCase) Run(ctx context.Context, req Request, userID string) (*bulk.Job, error) {
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+	if req.Resource == bulk.ResourceUser {
+		role, err := uc.users.Role(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if role != user.RoleAdmin {
+			return nil, ErrAdminRequired
+		}
+	}
+	ids, err := uc.selectItems(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &bulk.Job{
+		Resource:  req.Resource,
+		Action:    req.Action,
+		Params:    req.Params,
+		DryRun:    req.DryRun,
+		Status:    bulk.StatusPending,
+		Total:     len(ids),
+		CreatedBy: userID,
+	}
+	if err := uc.bulkRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if len(ids) <= uc.opts.SyncLimit {
+		if err := uc.process(ctx, job, ids); err != nil {
+			return nil, err
+		}
+		return job, nil
+	}
+
+	// The background run outlives the request, and the caller gets a copy it can read safely.
+	pending := *job
+	go uc.runInBackground(context.WithoutCancel(ctx), job, ids)
+	return &pending, nil
+}
+
+func (uc *bulkUseCase) Get(ctx context.Context, id string) (*bulk.Job, error) {
+	return uc.bulkRepo.GetByID(ctx, id)
+}
+
+func (uc *bulkUseCase) FailInterrupted(ctx context.Context) (int, error) {
+	return uc.bulkRepo.FailUnfinished(ctx, interruptedError, time.Now())
+}
+
+// runInBackground processes a job, recording why it stopped if it could not finish.
+func (uc *bulkUseCase) runInBackground(ctx context.Context, job *bulk.Job, ids []string) {
+	if err := uc.process(ctx, job, ids); err != nil {
+		job.Status = bulk.StatusFailed
+		job.Error = err.Error()
+		job.FinishedAt = time.Now()
+		_ = uc.bulkRepo.Update(ctx, job)
+	}
+}
+
+// process applies the action of a job to every item, saving the job after each chunk. Failing items
+// are recorded and do not stop the job; only failing to save it does.
+func (uc *bulkUseCase) process(ctx context.Context, job *bulk.Job, ids []string) error {
+	job.Status = bulk.StatusRunning
+	job.StartedAt = time.Now()
+	if err := uc.bulkRepo.Update(ctx, job); err != nil {
+		return err
+	}
+
+	for chunk := range slices.Chunk(ids, chunkSize) {
+		for _, id := range chunk {
+			job.Record(uc.apply(ctx, job, id))
+		}
+		if job.Processed < job.Total {
+			if err := uc.bulkRepo.Update(ctx, job); err != nil {
+				return err
+			}
+		}
+	}
+
+	job.Status = bulk.StatusCompleted
+	job.FinishedAt = time.Now()
+	return uc.bulkRepo.Update(ctx, job)
+}
+
+func (uc *bulkUseCase) apply(ctx context.Context, job *bulk.Job, id string) bulk.Result {
+	var changed bool
+	var err error
+	if job.Resource == bulk.ResourceContent {
+		changed, err = uc.applyContent(ctx, job, id)
+	} else {
+		changed, err = uc.applyUser(ctx, job, id)
+	}
+
+	switch {
+	case err != nil:
+		return bulk.Result{ID: id, Status: bulk.ResultFailed, Error: err.Error()}
+	case !changed:
+		return bulk.Result{ID: id, Status: bulk.ResultSkipped}
+	default:
+		return bulk.Result{ID: id, Status: bulk.ResultSucceeded}
+	}
+}
+
+// applyContent applies the action of a job to a content entry and reports whether it changed, or
+// would change in a dry run.
+func (uc *bulkUseCase) applyContent(ctx context.Context, job *bulk.Job, id string) (bool, error) {
+	c, err := uc.contents.Get(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if job.Action == bulk.ActionDelete {
+		if job.DryRun {
+			return true, nil
+		}
+		return true, uc.contents.Delete(ctx, id)
+	}
+
+	taxonomy, err := uc.contents.GetTaxonomy(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	categoryIDs := make([]string, 0, len(taxonomy.Categories))
+	for _, cat := range taxonomy.Categories {
+		categoryIDs = append(categoryIDs, cat.ID)
+	}
+	tagIDs := make([]string, 0, len(taxonomy.Tags))
+	for _, t := range taxonomy.Tags {
+		tagIDs = append(tagIDs, t.ID)
+	}
+
+	// Updates keep the taxonomy they are not about; categories are always passed along so the search
+	// index keeps them.
+	var newTagIDs []string
+	changed := false
+	switch job.Action {
+	case bulk.ActionPublish:
+		changed = setStatus(c, content.StatusPublished)
+	case bulk.ActionUnpublish:
+		changed = setStatus(c, content.StatusDraft)
+	case bulk.ActionArchive:
+		changed = setStatus(c, content.StatusArchived)
+	case bulk.ActionAddTags:
+		newTagIDs = tagIDs
+		for _, tagID := range job.Params.TagIDs {
+			if !slices.Contains(newTagIDs, tagID) {
+				newTagIDs = append(newTagIDs, tagID)
+				changed = true
+			}
+		}
+	case bulk.ActionRemoveTags:
+		newTagIDs = slices.DeleteFunc(slices.Clone(tagIDs), func(tagID string) bool {
+			return slices.Contains(job.Params.TagIDs, tagID)
+		})
+		changed = len(newTagIDs) != len(tagIDs)
+	case bulk.ActionMove:
+		changed = !sameSet(categoryIDs, job.Params.CategoryIDs)
+		categoryIDs = job.Params.CategoryIDs
+	}
+	if !changed || job.DryRun {
+		return changed, nil
+	}
+	return true, uc.contents.Update(ctx, c, categoryIDs, newTagIDs)
+}
+
+// applyUser applies the action of a job to a user and reports whether it changed, or would change in
+// a dry run.
+func (uc *bulkUseCase) applyUser(ctx context.Context, job *bulk.Job, id string) (bool, error) {
+	u, err := uc.users.GetProfile(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if u == nil {
+		return false, userusecase.ErrUserNotFound
+	}
+
+	changed := true
+	switch job.Action {
+	case bulk.ActionDelete:
+		if job.DryRun {
+			return true, nil
+		}
+		return true, uc.users.DeleteUser(ctx, id, job.CreatedBy)
+	case bulk.ActionSetStatus:
+		changed = u.Status != job.Params.Status
+		u.Status = job.Params.Status
+	case bulk.ActionSetRole:
+		changed = u.Role != job.Params.Role
+		u.Role = job.Params.Role
+	}
+	if !changed || job.DryRun {
+		return changed, nil
+	}
+	return true, uc.users.UpdateProfile(ctx, u, job.CreatedBy)
+}
+
+// selectItems returns the IDs an operation applies to, without duplicates.
+func (uc *bulkUseCase) selectItems(ctx context.Context, req Request) ([]string, error) {
+	var ids []string
+	if len(req.IDs) > 0 {
+		seen := make(map[string]bool, len(req.IDs))
+		for _, id := range req.IDs {
+			if id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	} else {
+		var err error
+		if req.Resource == bulk.ResourceContent {
+			ids, err = uc.filterContents(ctx, req.Filter)
+		} else {
+			ids, err = uc.filterUsers(ctx, req.Filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ids) > uc.opts.MaxItems {
+		return nil, fmt.Errorf("%w: %d items selected, at most %d allowed", ErrTooManyItems, len(ids), uc.opts.MaxItems)
+	}
+	return ids, nil
+}
+
+// filterContents lists the IDs of the entries matching a filter, reading at most one more than
+// allowed.
+func (uc *bulkUseCase) filterContents(ctx context.Context, f bulk.Filter) ([]string, error) {
+	filter := content.ListFilter{
+		Type:     f.Type,
+		Locale:   f.Locale,
+		Status:   f.Status,
+		AuthorID: f.AuthorID,
+		TagID:    f.TagID,
+		Limit:    chunkSize,
+	}
+	if f.CategoryID != "" {
+		filter.CategoryIDs = []string{f.CategoryID}
+	}
+
+	var ids []string
+	for len(ids) <= uc.opts.MaxItems {
+		page, err := uc.contents.List(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range page {
+			ids = append(ids, c.ID)
+		}
+		if len(page) < filter.Limit {
+			break
+		}
+		filter.Offset += filter.Limit
+	}
+	return ids, nil
+}
+
+// filterUsers lists the IDs of the users matching a filter, reading at most one more than allowed.
+// Users are few enough to be matched while paging through all of them.
+func (uc *bulkUseCase) filterUsers(ctx context.Context, f bulk.Filter) ([]string, error) {
+	var ids []string
+	for offset := 0; len(ids) <= uc.opts.MaxItems; offset += chunkSize {
+		page, err := uc.users.ListUsers(ctx, chunkSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range page {
+			if matchesUser(u, f) {
+				ids = append(ids, u.ID)
+			}
+		}
+		if len(page) < chunkSize {
+			break
+		}
+	}
+	return ids, nil
+}
+
+func matchesUser(u *user.User, f bulk.Filter) bool {
+	return (f.Role == "" || u.Role == f.Role) && (f.Status == "" || u.Status == f.Status)
+}
+
+// validate checks that a request names an action of its resource with the parameters it needs and
+// selects items.
+func validate(req Request) error {
+	known, ok := actions[req.Resource]
+	if !ok {
+		return fmt.Errorf("%w: unknown resource %q", ErrInvalidRequest, req.Resource)
+	}
+	if !slices.Contains(known, req.Action) {
+		return fmt.Errorf("%w: unknown %s action %q", ErrInvalidRequest, req.Resource, req.Action)
+	}
+
+	switch req.Action {
+	case bulk.ActionAddTags, bulk.ActionRemoveTags:
+		if len(req.Params.TagIDs) == 0 {
+			return fmt.Errorf("%w: %s needs tag_ids", ErrInvalidRequest, req.Action)
+		}
+	case bulk.ActionMove:
+		if len(req.Params.CategoryIDs) == 0 {
+			return fmt.Errorf("%w: %s needs category_ids", ErrInvalidRequest, req.Action)
+		}
+	case bulk.ActionSetStatus:
+		if req.Params.Status == "" {
+			return fmt.Errorf("%w: %s needs a status", ErrInvalidRequest, req.Action)
+		}
+	case bulk.ActionSetRole:
+		if req.Params.Role == "" {
+			return fmt.Errorf("%w: %s needs a role", ErrInvalidRequest, req.Action)
+		}
+	}
+
+	if len(req.IDs) > 0 {
+		if !req.Filter.Empty() {
+			return fmt.Errorf("%w: select items by ids or by a filter, not both", ErrInvalidRequest)
+		}
+		return nil
+	}
+	if req.Filter.Empty() {
+		return fmt.Errorf("%w: select items by ids or by a filter", ErrInvalidRequest)
+	}
+	if req.Resource == bulk.ResourceUser && (req.Filter != bulk.Filter{Role: req.Filter.Role, Status: req.Filter.Status}) {
+		return fmt.Errorf("%w: users can only be filtered by role and status", ErrInvalidRequest)
+	}
+	if req.Resource == bulk.ResourceContent && req.Filter.Role != "" {
+		return fmt.Errorf("%w: content cannot be filtered by role", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// setStatus changes the status of an entry and reports whether it differed.
+func setStatus(c *content.Content, status string) bool {
+	if c.Status == status {
+		return false
+	}
+	c.Status = status
+	return true
+}
+
+// sameSet reports whether a and b hold the same IDs in any order.
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, id := range a {
+		if !slices.Contains(b, id) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,310 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/bulk"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	userusecase "github.com/mashurimansur/goCMS/internal/usecase/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockBulkRepository struct {
+	mock.Mock
+}
+
+func (m *MockBulkRepository) Create(ctx context.Context, j *bulk.Job) error {
+	args := m.Called(ctx, j)
+	j.ID = "job-1"
+	return args.Error(0)
+}
+
+func (m *MockBulkRepository) GetByID(ctx context.Context, id string) (*bulk.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bulk.Job), args.Error(1)
+}
+
+func (m *MockBulkRepository) Update(ctx context.Context, j *bulk.Job) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *MockBulkRepository) FailUnfinished(ctx context.Context, reason string, finishedAt time.Time) (int, error) {
+	args := m.Called(ctx, reason, finishedAt)
+	return args.Int(0), args.Error(1)
+}
+
+type MockContentUseCase struct {
+	mock.Mock
+	contentusecase.UseCase
+}
+
+func (m *MockContentUseCase) Get(ctx context.Context, id string) (*content.Content, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func (m *MockContentUseCase) GetTaxonomy(ctx context.Context, id string) (*contentusecase.Taxonomy, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*contentusecase.Taxonomy), args.Error(1)
+}
+
+func (m *MockContentUseCase) Update(ctx context.Context, c *content.Content, categoryIDs, tagIDs []string) error {
+	args := m.Called(ctx, c, categoryIDs, tagIDs)
+	return args.Error(0)
+}
+
+func (m *MockContentUseCase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockContentUseCase) List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+type MockUserUseCase struct {
+	mock.Mock
+	userusecase.UseCase
+}
+
+func (m *MockUserUseCase) GetProfile(ctx context.Context, id string) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserUseCase) Role(ctx context.Context, id string) (string, error) {
+	args := m.Called(ctx, id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserUseCase) UpdateProfile(ctx context.Context, u *user.User, actorID string) error {
+	args := m.Called(ctx, u, actorID)
+	return args.Error(0)
+}
+
+func (m *MockUserUseCase) ListUsers(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+func (m *MockUserUseCase) DeleteUser(ctx context.Context, id, actorID string) error {
+	args := m.Called(ctx, id, actorID)
+	return args.Error(0)
+}
+
+func newTestUseCase(opts Options) (UseCase, *MockBulkRepository, *MockContentUseCase, *MockUserUseCase) {
+	repo := new(MockBulkRepository)
+	contents := new(MockContentUseCase)
+	users := new(MockUserUseCase)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*bulk.Job")).Return(nil)
+	repo.On("Update", mock.Anything, mock.AnythingOfType("*bulk.Job")).Return(nil)
+	users.On("Role", mock.Anything, "admin-1").Return(user.RoleAdmin, nil)
+	return NewBulkUseCase(repo, contents, users, opts), repo, contents, users
+}
+
+func TestBulkUseCase_Run_Publish(t *testing.T) {
+	uc, _, contents, _ := newTestUseCase(Options{})
+
+	contents.On("Get", mock.Anything, "post-1").Return(&content.Content{ID: "post-1", Status: content.StatusDraft}, nil)
+	contents.On("Get", mock.Anything, "post-2").Return(&content.Content{ID: "post-2", Status: content.StatusPublished}, nil)
+	contents.On("Get", mock.Anything, "post-3").Return(nil, content.ErrNotFound)
+	contents.On("GetTaxonomy", mock.Anything, mock.Anything).Return(&contentusecase.Taxonomy{}, nil)
+	contents.On("Update", mock.Anything, mock.MatchedBy(func(c *content.Content) bool {
+		return c.ID == "post-1" && c.Status == content.StatusPublished
+	}), []string{}, []string(nil)).Return(nil)
+
+	job, err := uc.Run(context.Background(), Request{
+		Resource: bulk.ResourceContent, Action: bulk.ActionPublish, IDs: []string{"post-1", "post-2", "post-3", "post-1"},
+	}, "user-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, bulk.StatusCompleted, job.Status)
+	assert.Equal(t, "user-1", job.CreatedBy)
+	assert.Equal(t, 3, job.Total)
+	assert.Equal(t, []bulk.Result{
+		{ID: "post-1", Status: bulk.ResultSucceeded},
+		{ID: "post-2", Status: bulk.ResultSkipped},
+		{ID: "post-3", Status: bulk.ResultFailed, Error: content.ErrNotFound.Error()},
+	}, job.Results)
+	contents.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestBulkUseCase_Run_AddTags_DryRun(t *testing.T) {
+	uc, _, contents, _ := newTestUseCase(Options{})
+
+	contents.On("Get", mock.Anything, "post-1").Return(&content.Content{ID: "post-1"}, nil)
+	contents.On("Get", mock.Anything, "post-2").Return(&content.Content{ID: "post-2"}, nil)
+	contents.On("GetTaxonomy", mock.Anything, "post-1").Return(&contentusecase.Taxonomy{Tags: []*tag.Tag{{ID: "go"}}}, nil)
+	contents.On("GetTaxonomy", mock.Anything, "post-2").Return(&contentusecase.Taxonomy{}, nil)
+
+	job, err := uc.Run(context.Background(), Request{
+		Resource: bulk.ResourceContent, Action: bulk.ActionAddTags, IDs: []string{"post-1", "post-2"},
+		Params: bulk.Params{TagIDs: []string{"go"}}, DryRun: true,
+	}, "user-1")
+	require.NoError(t, err)
+
+	assert.True(t, job.DryRun)
+	assert.Equal(t, 1, job.Skipped)
+	assert.Equal(t, 1, job.Succeeded)
+	contents.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBulkUseCase_Run_RemoveTags(t *testing.T) {
+	uc, _, contents, _ := newTestUseCase(Options{})
+
+	contents.On("Get", mock.Anything, "post-1").Return(&content.Content{ID: "post-1"}, nil)
+	contents.On("GetTaxonomy", mock.Anything, "post-1").Return(&contentusecase.Taxonomy{Tags: []*tag.Tag{{ID: "go"}, {ID: "rust"}}}, nil)
+	contents.On("Update", mock.Anything, mock.Anything, []string{}, []string{"rust"}).Return(nil)
+
+	job, err := uc.Run(context.Background(), Request{
+		Resource: bulk.ResourceContent, Action: bulk.ActionRemoveTags, IDs: []string{"post-1"},
+		Params: bulk.Params{TagIDs: []string{"go"}},
+	}, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, job.Succeeded)
+	contents.AssertExpectations(t)
+}
+
+func TestBulkUseCase_Run_Filter(t *testing.T) {
+	uc, _, contents, _ := newTestUseCase(Options{})
+
+	contents.On("List", mock.Anything, content.ListFilter{Status: content.StatusDraft, CategoryIDs: []string{"news"}, Limit: chunkSize}).
+		Return([]*content.Content{{ID: "post-1"}}, nil)
+	contents.On("Get", mock.Anything, "post-1").Return(&content.Content{ID: "post-1"}, nil)
+	contents.On("Delete", mock.Anything, "post-1").Return(nil)
+
+	job, err := uc.Run(context.Background(), Request{
+		Resource: bulk.ResourceContent, Action: bulk.ActionDelete,
+		Filter: bulk.Filter{Status: content.StatusDraft, CategoryID: "news"},
+	}, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, job.Succeeded)
+	contents.AssertExpectations(t)
+}
+
+func TestBulkUseCase_Run_SetRole(t *testing.T) {
+	uc, _, _, users := newTestUseCase(Options{})
+
+	users.On("ListUsers", mock.Anything, chunkSize, 0).Return([]*user.User{
+		{ID: "user-1", Role: "user", Status: "active"},
+		{ID: "user-2", Role: "user", Status: "suspended"},
+	}, nil)
+	users.On("GetProfile", mock.Anything, "user-1").Return(&user.User{ID: "user-1", Role: "user", Status: "active"}, nil)
+	users.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+		return u.ID == "user-1" && u.Role == "editor"
+	}), "admin-1").Return(nil)
+
+	job, err := uc.Run(context.Background(), Request{
+		Resource: bulk.ResourceUser, Action: bulk.ActionSetRole, Filter: bulk.Filter{Status: "active"},
+		Params: bulk.Params{Role: "editor"},
+	}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, []bulk.Result{{ID: "user-1", Status: bulk.ResultSucceeded}}, job.Results)
+	users.AssertExpectations(t)
+}
+
+func TestBulkUseCase_Run_Background(t *testing.T) {
+	uc, repo, _, users := newTestUseCase(Options{SyncLimit: 1})
+
+	done := make(chan struct{})
+	repo.ExpectedCalls = nil
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*bulk.Job")).Return(nil)
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(j *bulk.Job) bool { return j.Status != bulk.StatusCompleted })).Return(nil)
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(j *bulk.Job) bool { return j.Status == bulk.StatusCompleted })).
+		Return(nil).Run(func(mock.Arguments) { close(done) })
+	users.On("GetProfile", mock.Anything, mock.Anything).Return(&user.User{}, nil)
+	users.On("DeleteUser", mock.Anything, mock.Anything, "admin-1").Return(nil)
+
+	job, err := uc.Run(context.Background(), Request{
+		Resource: bulk.ResourceUser, Action: bulk.ActionDelete, IDs: []string{"user-1", "user-2"},
+	}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, bulk.StatusPending, job.Status)
+	assert.Equal(t, "job-1", job.ID)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("background job did not complete")
+	}
+	users.AssertNumberOfCalls(t, "DeleteUser", 2)
+}
+
+func TestBulkUseCase_Run_Invalid(t *testing.T) {
+	uc, _, _, _ := newTestUseCase(Options{MaxItems: 2})
+
+	cases := []Request{
+		{Resource: "widget", Action: bulk.ActionDelete, IDs: []string{"1"}},
+		{Resource: bulk.ResourceUser, Action: bulk.ActionPublish, IDs: []string{"1"}},
+		{Resource: bulk.ResourceContent, Action: bulk.ActionAddTags, IDs: []string{"1"}},
+		{Resource: bulk.ResourceContent, Action: bulk.ActionDelete},
+		{Resource: bulk.ResourceContent, Action: bulk.ActionDelete, IDs: []string{"1"}, Filter: bulk.Filter{Status: "draft"}},
+		{Resource: bulk.ResourceUser, Action: bulk.ActionDelete, Filter: bulk.Filter{Type: "post"}},
+	}
+	for i, req := range cases {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			_, err := uc.Run(context.Background(), req, "user-1")
+			assert.ErrorIs(t, err, ErrInvalidRequest)
+		})
+	}
+
+	_, err := uc.Run(context.Background(), Request{
+		Resource: bulk.ResourceContent, Action: bulk.ActionDelete, IDs: []string{"1", "2", "3"},
+	}, "user-1")
+	assert.ErrorIs(t, err, ErrTooManyItems)
+}
+
+func TestBulkUseCase_Run_SaveFails(t *testing.T) {
+	uc, repo, _, _ := newTestUseCase(Options{})
+	repo.ExpectedCalls = nil
+	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	_, err := uc.Run(context.Background(), Request{
+		Resource: bulk.ResourceUser, Action: bulk.ActionDelete, IDs: []string{"user-1"},
+	}, "admin-1")
+	assert.EqualError(t, err, "db down")
+}
+
+func TestBulkUseCase_Run_UsersRequireAdmin(t *testing.T) {
+	uc, repo, _, users := newTestUseCase(Options{})
+	users.On("Role", mock.Anything, "user-9").Return(user.RoleUser, nil)
+
+	for _, action := range []string{bulk.ActionSetRole, bulk.ActionSetStatus, bulk.ActionDelete} {
+		_, err := uc.Run(context.Background(), Request{
+			Resource: bulk.ResourceUser, Action: action, IDs: []string{"user-9"},
+			Params: bulk.Params{Role: user.RoleAdmin, Status: "active"},
+		}, "user-9")
+		assert.ErrorIs(t, err, ErrAdminRequired, action)
+	}
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	users.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBulkUseCase_FailInterrupted(t *testing.T) {
+	uc, repo, _, _ := newTestUseCase(Options{})
+	repo.On("FailUnfinished", mock.Anything, interruptedError, mock.AnythingOfType("time.Time")).Return(3, nil)
+
+	n, err := uc.FailInterrupted(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+}
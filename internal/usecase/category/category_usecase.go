@@ -0,0 +1,160 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/utils/slug"
+)
+
+// ErrCyclicParent is returned when a category would become its own ancestor.
+var ErrCyclicParent = errors.New("category cannot be nested under itself or its descendants")
+
+type UseCase interface {
+	Create(ctx context.Context, c *category.Category) error
+	Get(ctx context.Context, id string) (*category.Category, error)
+	Update(ctx context.Context, c *category.Category) error
+	// Delete moves a category to the trash.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*category.Category, error)
+	Tree(ctx context.Context) ([]*category.Category, error)
+	// ListContent returns published content in the category identified by slug and in all of its descendants.
+	// Entries are restricted to viewer, with the teasers it is not admitted to locked.
+	ListContent(ctx context.Context, slug string, limit, offset int, viewer content.Viewer) ([]*content.Content, error)
+	// The trash of categories. Children of a trashed category show up at the root until it is
+	// restored; content keeps its assignments to it.
+	trash.Bin
+}
+
+type categoryUseCase struct {
+	categoryRepo category.Repository
+	contentRepo  content.Repository
+}
+
+func NewCategoryUseCase(categoryRepo category.Repository, contentRepo content.Repository) UseCase {
+	return &categoryUseCase{
+		categoryRepo: categoryRepo,
+		contentRepo:  contentRepo,
+	}
+}
+
+func (uc *categoryUseCase) Create(ctx context.Context, c *category.Category) error {
+	if c.ParentID != "" {
+		if _, err := uc.categoryRepo.GetByID(ctx, c.ParentID); err != nil {
+			return err
+		}
+	}
+	if err := uc.assignSlug(ctx, c); err != nil {
+		return err
+	}
+	return uc.categoryRepo.Create(ctx, c)
+}
+
+func (uc *categoryUseCase) Get(ctx context.Context, id string) (*category.Category, error) {
+	return uc.categoryRepo.GetByID(ctx, id)
+}
+
+func (uc *categoryUseCase) Update(ctx context.Context, c *category.Category) error {
+	existing, err := uc.categoryRepo.GetByID(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	if c.Version, err = lock.Expect(c.Version, existing.Version); err != nil {
+		return err
+	}
+	if c.ParentID != "" {
+		all, err := uc.categoryRepo.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, id := range category.DescendantIDs(all, c.ID) {
+			if id == c.ParentID {
+				return ErrCyclicParent
+			}
+		}
+	}
+	if err := uc.assignSlug(ctx, c); err != nil {
+		return err
+	}
+	return uc.categoryRepo.Update(ctx, c)
+}
+
+func (uc *categoryUseCase) Delete(ctx context.Context, id string) error {
+	return uc.categoryRepo.Delete(ctx, id)
+}
+
+func (uc *categoryUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	return uc.categoryRepo.ListTrashed(ctx, filter)
+}
+
+func (uc *categoryUseCase) Restore(ctx context.Context, id string) error {
+	return uc.categoryRepo.Restore(ctx, id)
+}
+
+func (uc *categoryUseCase) Purge(ctx context.Context, id string) error {
+	return uc.categoryRepo.Purge(ctx, id)
+}
+
+func (uc *categoryUseCase) List(ctx context.Context) ([]*category.Category, error) {
+	return uc.categoryRepo.List(ctx)
+}
+
+func (uc *categoryUseCase) Tree(ctx context.Context) ([]*category.Category, error) {
+	all, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return category.BuildTree(all), nil
+}
+
+func (uc *categoryUseCase) ListContent(ctx context.Context, slug string, limit, offset int, viewer content.Viewer) ([]*content.Content, error) {
+	root, err := uc.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	all, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	viewer = viewer.WithoutPassword()
+	entries, err := uc.contentRepo.List(ctx, content.ListFilter{
+		Status:          content.StatusPublished,
+		PublishedBefore: time.Now(),
+		CategoryIDs:     category.DescendantIDs(all, root.ID),
+		Viewer:          &viewer,
+		Limit:           limit,
+		Offset:          offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range entries {
+		c.Restrict(viewer)
+	}
+	return entries, nil
+}
+
+// assignSlug derives the slug from the name when missing and makes it unique. Slugs of trashed
+// categories stay taken so the categories can be restored.
+func (uc *categoryUseCase) assignSlug(ctx context.Context, c *category.Category) error {
+	base := c.Slug
+	if base == "" {
+		base = c.Name
+	}
+
+	unique, err := slug.Unique(ctx, base, func(ctx context.Context, candidate string) (bool, error) {
+		return uc.categoryRepo.SlugInUse(ctx, candidate, c.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Slug = unique
+	return nil
+}
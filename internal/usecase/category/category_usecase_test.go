@@ -0,0 +1,201 @@
+package category
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Create(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) GetByID(ctx context.Context, id string) (*category.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetBySlug(ctx context.Context, slug string) (*category.Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) List(ctx context.Context) ([]*category.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListByContent(ctx context.Context, contentID string) ([]*category.Category, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) SetContentCategories(ctx context.Context, contentID string, categoryIDs []string) error {
+	args := m.Called(ctx, contentID, categoryIDs)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockContentRepository struct {
+	mock.Mock
+	content.Repository
+}
+
+func (m *MockContentRepository) List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func categoryFixtures() []*category.Category {
+	return []*category.Category{
+		{ID: "news", Slug: "news"},
+		{ID: "local", ParentID: "news", Slug: "local"},
+		{ID: "city", ParentID: "local", Slug: "city"},
+		{ID: "sports", Slug: "sports"},
+	}
+}
+
+func TestCategoryUseCase_Create_RequiresExistingParent(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	uc := NewCategoryUseCase(repo, new(MockContentRepository))
+
+	repo.On("GetByID", mock.Anything, "missing").Return(nil, category.ErrNotFound)
+
+	err := uc.Create(context.Background(), &category.Category{Name: "Child", ParentID: "missing"})
+	assert.ErrorIs(t, err, category.ErrNotFound)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCategoryUseCase_Update_RejectsCycles(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	uc := NewCategoryUseCase(repo, new(MockContentRepository))
+
+	repo.On("GetByID", mock.Anything, "news").Return(&category.Category{ID: "news", Version: 1}, nil)
+	repo.On("List", mock.Anything).Return(categoryFixtures(), nil)
+
+	err := uc.Update(context.Background(), &category.Category{ID: "news", ParentID: "city"})
+	assert.ErrorIs(t, err, ErrCyclicParent)
+
+	err = uc.Update(context.Background(), &category.Category{ID: "news", ParentID: "news"})
+	assert.ErrorIs(t, err, ErrCyclicParent)
+}
+
+func TestCategoryUseCase_Update(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	uc := NewCategoryUseCase(repo, new(MockContentRepository))
+
+	c := &category.Category{ID: "sports", ParentID: "news", Name: "Sports", Slug: "sports"}
+	repo.On("GetByID", mock.Anything, "sports").Return(&category.Category{ID: "sports", Version: 2}, nil)
+	repo.On("List", mock.Anything).Return(categoryFixtures(), nil)
+	repo.On("SlugInUse", mock.Anything, "sports", "sports").Return(false, nil)
+	repo.On("Update", mock.Anything, c).Return(nil)
+
+	assert.NoError(t, uc.Update(context.Background(), c))
+	assert.Equal(t, 2, c.Version)
+	repo.AssertExpectations(t)
+}
+
+func TestCategoryUseCase_Update_VersionConflict(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	uc := NewCategoryUseCase(repo, new(MockContentRepository))
+
+	repo.On("GetByID", mock.Anything, "sports").Return(&category.Category{ID: "sports", Version: 3}, nil)
+
+	err := uc.Update(context.Background(), &category.Category{ID: "sports", Name: "Sports", Version: 2})
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCategoryUseCase_Tree(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	uc := NewCategoryUseCase(repo, new(MockContentRepository))
+
+	repo.On("List", mock.Anything).Return(categoryFixtures(), nil)
+
+	tree, err := uc.Tree(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "local", tree[0].Children[0].ID)
+}
+
+func TestCategoryUseCase_ListContent_IncludesDescendants(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	contentRepo := new(MockContentRepository)
+	uc := NewCategoryUseCase(repo, contentRepo)
+
+	repo.On("GetBySlug", mock.Anything, "local").Return(&category.Category{ID: "local"}, nil)
+	repo.On("List", mock.Anything).Return(categoryFixtures(), nil)
+	contentRepo.On("List", mock.Anything, mock.MatchedBy(func(f content.ListFilter) bool {
+		return f.Status == content.StatusPublished &&
+			assert.ObjectsAreEqual([]string{"local", "city"}, f.CategoryIDs) &&
+			f.Limit == 10 && f.Offset == 20
+	})).Return([]*content.Content{{ID: "content-1"}}, nil)
+
+	entries, err := uc.ListContent(context.Background(), "local", 10, 20, content.Viewer{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	contentRepo.AssertExpectations(t)
+}
+
+func TestCategoryUseCase_Create_GeneratesSlug(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	uc := NewCategoryUseCase(repo, new(MockContentRepository))
+
+	repo.On("SlugInUse", mock.Anything, "berita-terkini", "").Return(false, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(c *category.Category) bool {
+		return c.Slug == "berita-terkini"
+	})).Return(nil)
+
+	assert.NoError(t, uc.Create(context.Background(), &category.Category{Name: "Berita Terkini"}))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,249 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mashurimansur/goCMS/internal/domain/comment"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+)
+
+var (
+	// ErrInvalidComment is returned when a submitted comment is incomplete or malformed.
+	ErrInvalidComment = errors.New("invalid comment")
+	// ErrInvalidParent is returned when a reply targets a comment that is not approved on the same entry.
+	ErrInvalidParent = errors.New("parent comment must be an approved comment on the same content")
+	// ErrInvalidAction is returned when a moderation action is not recognized.
+	ErrInvalidAction = errors.New("invalid moderation action")
+)
+
+// Moderation actions accepted by Moderate.
+const (
+	ActionApprove = "approve"
+	ActionReject  = "reject"
+	ActionSpam    = "spam"
+	ActionDelete  = "delete"
+)
+
+// maxBodyLength caps the number of characters in a comment body.
+const maxBodyLength = 5000
+
+// maxThreadSize caps the number of approved comments loaded for one thread.
+const maxThreadSize = 1000
+
+var actionStatuses = map[string]string{
+	ActionApprove: comment.StatusApproved,
+	ActionReject:  comment.StatusRejected,
+	ActionSpam:    comment.StatusSpam,
+}
+
+type UseCase interface {
+	// Submit stores a reader's comment on a published entry viewer may read. Its status is decided by
+	// the spam rules: high scores are filed as spam, anonymous or suspicious comments wait for
+	// moderation and clean comments by signed-in users are approved right away.
+	Submit(ctx context.Context, c *comment.Comment, viewer content.Viewer) error
+	// Thread returns the approved comments of a published entry viewer may read as a tree, without
+	// private author data.
+	Thread(ctx context.Context, contentID string, viewer content.Viewer) ([]*comment.Comment, error)
+	Get(ctx context.Context, id string) (*comment.Comment, error)
+	List(ctx context.Context, filter comment.ListFilter) ([]*comment.Comment, error)
+	// Moderate applies action to the given comments and reports how many were affected.
+	Moderate(ctx context.Context, ids []string, action string) (int64, error)
+	// Delete moves a comment and its replies to the trash.
+	Delete(ctx context.Context, id string) error
+	// The trash of comments. Restoring a comment restores the replies trashed along with it.
+	trash.Bin
+}
+
+type commentUseCase struct {
+	repo        comment.Repository
+	contentRepo content.Repository
+	userRepo    user.Repository
+	rules       SpamRules
+}
+
+func NewCommentUseCase(repo comment.Repository, contentRepo content.Repository, userRepo user.Repository, rules SpamRules) UseCase {
+	return &commentUseCase{
+		repo:        repo,
+		contentRepo: contentRepo,
+		userRepo:    userRepo,
+		rules:       rules,
+	}
+}
+
+func (uc *commentUseCase) Submit(ctx context.Context, c *comment.Comment, viewer content.Viewer) error {
+	if err := uc.prepare(ctx, c); err != nil {
+		return err
+	}
+	if err := uc.requirePublished(ctx, c.ContentID, viewer); err != nil {
+		return err
+	}
+	if c.ParentID != "" {
+		parent, err := uc.repo.GetByID(ctx, c.ParentID)
+		if err != nil && !errors.Is(err, comment.ErrNotFound) {
+			return err
+		}
+		if parent == nil || parent.ContentID != c.ContentID || parent.Status != comment.StatusApproved {
+			return ErrInvalidParent
+		}
+	}
+
+	recent := 0
+	if c.IP != "" && uc.rules.MaxPerIP > 0 {
+		var err error
+		recent, err = uc.repo.CountRecentByIP(ctx, c.IP, time.Now().Add(-uc.rules.RateWindow))
+		if err != nil {
+			return err
+		}
+	}
+
+	c.SpamScore = uc.rules.score(c.Body, recent)
+	switch {
+	case c.SpamScore >= uc.rules.threshold():
+		c.Status = comment.StatusSpam
+	case c.SpamScore == 0 && c.AuthorID != "":
+		c.Status = comment.StatusApproved
+	default:
+		c.Status = comment.StatusPending
+	}
+
+	return uc.repo.Create(ctx, c)
+}
+
+func (uc *commentUseCase) Thread(ctx context.Context, contentID string, viewer content.Viewer) ([]*comment.Comment, error) {
+	if err := uc.requirePublished(ctx, contentID, viewer); err != nil {
+		return nil, err
+	}
+
+	comments, err := uc.repo.List(ctx, comment.ListFilter{
+		ContentID: contentID,
+		Status:    comment.StatusApproved,
+		Limit:     maxThreadSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buildThread(comments), nil
+}
+
+func (uc *commentUseCase) Get(ctx context.Context, id string) (*comment.Comment, error) {
+	return uc.repo.GetByID(ctx, id)
+}
+
+func (uc *commentUseCase) List(ctx context.Context, filter comment.ListFilter) ([]*comment.Comment, error) {
+	return uc.repo.List(ctx, filter)
+}
+
+func (uc *commentUseCase) Moderate(ctx context.Context, ids []string, action string) (int64, error) {
+	if action == ActionDelete {
+		return uc.repo.Delete(ctx, ids)
+	}
+	status, ok := actionStatuses[action]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAction, action)
+	}
+	return uc.repo.SetStatus(ctx, ids, status)
+}
+
+func (uc *commentUseCase) Delete(ctx context.Context, id string) error {
+	affected, err := uc.repo.Delete(ctx, []string{id})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return comment.ErrNotFound
+	}
+	return nil
+}
+
+func (uc *commentUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	return uc.repo.ListTrashed(ctx, filter)
+}
+
+func (uc *commentUseCase) Restore(ctx context.Context, id string) error {
+	return uc.repo.Restore(ctx, id)
+}
+
+func (uc *commentUseCase) Purge(ctx context.Context, id string) error {
+	return uc.repo.Purge(ctx, id)
+}
+
+// prepare normalizes the submitted fields and fills in the author of signed-in readers.
+func (uc *commentUseCase) prepare(ctx context.Context, c *comment.Comment) error {
+	c.ID = ""
+	c.Body = strings.TrimSpace(c.Body)
+	if c.Body == "" {
+		return fmt.Errorf("%w: body is required", ErrInvalidComment)
+	}
+	if utf8.RuneCountInString(c.Body) > maxBodyLength {
+		return fmt.Errorf("%w: body exceeds %d characters", ErrInvalidComment, maxBodyLength)
+	}
+
+	if c.AuthorID != "" {
+		u, err := uc.userRepo.GetByID(ctx, c.AuthorID)
+		if err != nil {
+			return err
+		}
+		if u == nil {
+			return fmt.Errorf("%w: author does not exist", ErrInvalidComment)
+		}
+		c.AuthorName = u.FullName
+		if c.AuthorName == "" {
+			c.AuthorName = u.Username
+		}
+		c.AuthorEmail = u.Email
+		return nil
+	}
+
+	c.AuthorName = strings.TrimSpace(c.AuthorName)
+	c.AuthorEmail = strings.TrimSpace(c.AuthorEmail)
+	if c.AuthorName == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidComment)
+	}
+	if addr, err := mail.ParseAddress(c.AuthorEmail); err != nil || addr.Address != c.AuthorEmail {
+		return fmt.Errorf("%w: a valid email address is required", ErrInvalidComment)
+	}
+	return nil
+}
+
+// requirePublished reports drafts, scheduled entries and entries viewer may not read as missing so
+// readers cannot comment on them. The discussion of a teaser is as restricted as its body.
+func (uc *commentUseCase) requirePublished(ctx context.Context, id string, viewer content.Viewer) error {
+	c, err := uc.contentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.Status != content.StatusPublished || c.PublishedAt.After(time.Now()) || !c.Admits(viewer) {
+		return content.ErrNotFound
+	}
+	return nil
+}
+
+// buildThread nests comments under their parents, keeping posting order. Replies whose parent
+// is not part of the list are left out so hidden comments do not leak through their children.
+func buildThread(comments []*comment.Comment) []*comment.Comment {
+	byID := make(map[string]*comment.Comment, len(comments))
+	for _, c := range comments {
+		c.Redact()
+		byID[c.ID] = c
+	}
+
+	roots := make([]*comment.Comment, 0, len(comments))
+	for _, c := range comments {
+		if c.ParentID == "" {
+			roots = append(roots, c)
+			continue
+		}
+		if parent, ok := byID[c.ParentID]; ok {
+			parent.Replies = append(parent.Replies, c)
+		}
+	}
+	return roots
+}
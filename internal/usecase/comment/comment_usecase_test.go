@@ -0,0 +1,351 @@
+package comment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/comment"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(ctx context.Context, c *comment.Comment) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetByID(ctx context.Context, id string) (*comment.Comment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*comment.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) List(ctx context.Context, filter comment.ListFilter) ([]*comment.Comment, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*comment.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) SetStatus(ctx context.Context, ids []string, status string) (int64, error) {
+	args := m.Called(ctx, ids, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCommentRepository) Delete(ctx context.Context, ids []string) (int64, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCommentRepository) CountRecentByIP(ctx context.Context, ip string, since time.Time) (int, error) {
+	args := m.Called(ctx, ip, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCommentRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockCommentRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockContentRepository struct {
+	mock.Mock
+}
+
+func (m *MockContentRepository) Create(ctx context.Context, c *content.Content) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockContentRepository) GetByID(ctx context.Context, id string) (*content.Content, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) GetBySlug(ctx context.Context, contentType, locale, slug string) (*content.Content, error) {
+	args := m.Called(ctx, contentType, locale, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) Update(ctx context.Context, c *content.Content) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockContentRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockContentRepository) List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) ListTranslations(ctx context.Context, sourceIDs []string) ([]*content.Content, error) {
+	args := m.Called(ctx, sourceIDs)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) SlugInUse(ctx context.Context, contentType, locale, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, contentType, locale, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockContentRepository) GetTrashed(ctx context.Context, id string) (*content.Content, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockContentRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockContentRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockUserRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+var testRules = SpamRules{MaxLinks: 1, BlockedWords: []string{"casino"}, MaxPerIP: 3, RateWindow: 10 * time.Minute}
+
+func newTestUseCase() (UseCase, *MockCommentRepository, *MockContentRepository, *MockUserRepository) {
+	repo := new(MockCommentRepository)
+	contentRepo := new(MockContentRepository)
+	userRepo := new(MockUserRepository)
+	return NewCommentUseCase(repo, contentRepo, userRepo, testRules), repo, contentRepo, userRepo
+}
+
+func publishedPost() *content.Content {
+	return &content.Content{ID: "post-1", Status: content.StatusPublished, PublishedAt: time.Now().Add(-time.Hour)}
+}
+
+func TestSpamRules_Score(t *testing.T) {
+	assert.Equal(t, 0, testRules.score("Thanks, see https://example.com", 0))
+	assert.Equal(t, 2, testRules.score("http://a.example www.b.example https://c.example", 0))
+	assert.Equal(t, 4, testRules.score("Best CASINO, casino bonus", 0))
+	assert.Equal(t, 3, testRules.score("hello", 3))
+	assert.Equal(t, 0, testRules.score("hello", 2))
+}
+
+func TestCommentUseCase_Submit_Anonymous(t *testing.T) {
+	uc, repo, contentRepo, _ := newTestUseCase()
+
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(publishedPost(), nil)
+	repo.On("CountRecentByIP", mock.Anything, "10.0.0.1", mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	c := &comment.Comment{ContentID: "post-1", AuthorName: " Ana ", AuthorEmail: "ana@example.com", Body: " Nice post ", IP: "10.0.0.1"}
+	require.NoError(t, uc.Submit(context.Background(), c, content.Viewer{}))
+	assert.Equal(t, comment.StatusPending, c.Status)
+	assert.Equal(t, "Ana", c.AuthorName)
+	assert.Equal(t, "Nice post", c.Body)
+}
+
+func TestCommentUseCase_Submit_AuthenticatedIsApproved(t *testing.T) {
+	uc, repo, contentRepo, userRepo := newTestUseCase()
+
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(publishedPost(), nil)
+	userRepo.On("GetByID", mock.Anything, "user-1").Return(&user.User{ID: "user-1", FullName: "Budi", Email: "budi@example.com"}, nil)
+	repo.On("CountRecentByIP", mock.Anything, "10.0.0.1", mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	c := &comment.Comment{ContentID: "post-1", AuthorID: "user-1", AuthorName: "Someone else", Body: "Great", IP: "10.0.0.1"}
+	require.NoError(t, uc.Submit(context.Background(), c, content.Viewer{}))
+	assert.Equal(t, comment.StatusApproved, c.Status)
+	assert.Equal(t, "Budi", c.AuthorName)
+	assert.Equal(t, "budi@example.com", c.AuthorEmail)
+}
+
+func TestCommentUseCase_Submit_Spam(t *testing.T) {
+	uc, repo, contentRepo, userRepo := newTestUseCase()
+
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(publishedPost(), nil)
+	userRepo.On("GetByID", mock.Anything, "user-1").Return(&user.User{ID: "user-1", FullName: "Budi"}, nil)
+	repo.On("CountRecentByIP", mock.Anything, "10.0.0.1", mock.Anything).Return(5, nil)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	c := &comment.Comment{ContentID: "post-1", AuthorID: "user-1", Body: "hello again", IP: "10.0.0.1"}
+	require.NoError(t, uc.Submit(context.Background(), c, content.Viewer{}))
+	assert.Equal(t, comment.StatusSpam, c.Status)
+	assert.Equal(t, 3, c.SpamScore)
+}
+
+func TestCommentUseCase_Submit_Invalid(t *testing.T) {
+	uc, _, contentRepo, _ := newTestUseCase()
+
+	err := uc.Submit(context.Background(), &comment.Comment{ContentID: "post-1", AuthorName: "Ana", AuthorEmail: "not-an-email", Body: "Hi"}, content.Viewer{})
+	assert.ErrorIs(t, err, ErrInvalidComment)
+
+	err = uc.Submit(context.Background(), &comment.Comment{ContentID: "post-1", AuthorName: "Ana", AuthorEmail: "ana@example.com", Body: "   "}, content.Viewer{})
+	assert.ErrorIs(t, err, ErrInvalidComment)
+
+	contentRepo.On("GetByID", mock.Anything, "draft-1").Return(&content.Content{ID: "draft-1", Status: content.StatusDraft}, nil)
+	err = uc.Submit(context.Background(), &comment.Comment{ContentID: "draft-1", AuthorName: "Ana", AuthorEmail: "ana@example.com", Body: "Hi"}, content.Viewer{})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+}
+
+func TestCommentUseCase_Submit_InvalidParent(t *testing.T) {
+	uc, repo, contentRepo, _ := newTestUseCase()
+
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(publishedPost(), nil)
+	repo.On("GetByID", mock.Anything, "pending-1").Return(&comment.Comment{ID: "pending-1", ContentID: "post-1", Status: comment.StatusPending}, nil)
+	repo.On("GetByID", mock.Anything, "other-1").Return(&comment.Comment{ID: "other-1", ContentID: "post-2", Status: comment.StatusApproved}, nil)
+	repo.On("GetByID", mock.Anything, "missing").Return(nil, comment.ErrNotFound)
+
+	for _, parentID := range []string{"pending-1", "other-1", "missing"} {
+		c := &comment.Comment{ContentID: "post-1", ParentID: parentID, AuthorName: "Ana", AuthorEmail: "ana@example.com", Body: "Reply"}
+		assert.ErrorIs(t, uc.Submit(context.Background(), c, content.Viewer{}), ErrInvalidParent, parentID)
+	}
+}
+
+func TestCommentUseCase_Thread(t *testing.T) {
+	uc, repo, contentRepo, _ := newTestUseCase()
+
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(publishedPost(), nil)
+	repo.On("List", mock.Anything, comment.ListFilter{ContentID: "post-1", Status: comment.StatusApproved, Limit: maxThreadSize}).Return([]*comment.Comment{
+		{ID: "c-1", ContentID: "post-1", AuthorEmail: "ana@example.com", IP: "10.0.0.1", Body: "First"},
+		{ID: "c-2", ContentID: "post-1", ParentID: "c-1", Body: "Reply"},
+		{ID: "c-3", ContentID: "post-1", ParentID: "hidden", Body: "Orphan"},
+		{ID: "c-4", ContentID: "post-1", Body: "Second"},
+	}, nil)
+
+	thread, err := uc.Thread(context.Background(), "post-1", content.Viewer{})
+	require.NoError(t, err)
+	require.Len(t, thread, 2)
+	assert.Equal(t, "c-1", thread[0].ID)
+	assert.Empty(t, thread[0].AuthorEmail)
+	assert.Empty(t, thread[0].IP)
+	require.Len(t, thread[0].Replies, 1)
+	assert.Equal(t, "c-2", thread[0].Replies[0].ID)
+	assert.Equal(t, "c-4", thread[1].ID)
+}
+
+func TestCommentUseCase_Thread_MembersOnly(t *testing.T) {
+	uc, repo, contentRepo, _ := newTestUseCase()
+
+	post := publishedPost()
+	post.Visibility = content.VisibilityAuthenticated
+	post.Teaser = true
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+	repo.On("List", mock.Anything, mock.Anything).Return([]*comment.Comment{}, nil)
+
+	_, err := uc.Thread(context.Background(), "post-1", content.Viewer{})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+
+	_, err = uc.Thread(context.Background(), "post-1", content.Viewer{UserID: "user-1"})
+	assert.NoError(t, err)
+}
+
+func TestCommentUseCase_Moderate(t *testing.T) {
+	uc, repo, _, _ := newTestUseCase()
+
+	ids := []string{"c-1", "c-2"}
+	repo.On("SetStatus", mock.Anything, ids, comment.StatusApproved).Return(int64(2), nil)
+	repo.On("Delete", mock.Anything, ids).Return(int64(2), nil)
+
+	affected, err := uc.Moderate(context.Background(), ids, ActionApprove)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+
+	affected, err = uc.Moderate(context.Background(), ids, ActionDelete)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+
+	_, err = uc.Moderate(context.Background(), ids, "publish")
+	assert.ErrorIs(t, err, ErrInvalidAction)
+}
+
+func TestCommentUseCase_Delete_NotFound(t *testing.T) {
+	uc, repo, _, _ := newTestUseCase()
+
+	repo.On("Delete", mock.Anything, []string{"missing"}).Return(int64(0), nil)
+
+	assert.ErrorIs(t, uc.Delete(context.Background(), "missing"), comment.ErrNotFound)
+}
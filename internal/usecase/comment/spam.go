@@ -0,0 +1,61 @@
+package comment
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultSpamThreshold is the score at which a comment is filed as spam when SpamRules leave it unset.
+const defaultSpamThreshold = 3
+
+// Points added to a comment's spam score by each rule.
+const (
+	linkPoints        = 1
+	blockedWordPoints = 2
+	ratePoints        = 3
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://|\bwww\.`)
+
+// SpamRules configure the heuristics that score incoming comments.
+type SpamRules struct {
+	// MaxLinks is the number of links tolerated before every further link adds to the score.
+	MaxLinks int
+	// BlockedWords add to the score every time one of them appears, case-insensitively.
+	BlockedWords []string
+	// MaxPerIP is the number of comments an address may submit within RateWindow before
+	// further ones are penalized. Zero disables the rate rule.
+	MaxPerIP   int
+	RateWindow time.Duration
+	// Threshold is the score from which a comment is filed as spam.
+	Threshold int
+}
+
+// score rates body, posted by an address that already submitted recent comments within the rate window.
+func (r SpamRules) score(body string, recent int) int {
+	score := 0
+	if links := len(linkPattern.FindAllStringIndex(body, -1)); links > r.MaxLinks {
+		score += (links - r.MaxLinks) * linkPoints
+	}
+
+	lower := strings.ToLower(body)
+	for _, word := range r.BlockedWords {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" {
+			score += strings.Count(lower, word) * blockedWordPoints
+		}
+	}
+
+	if r.MaxPerIP > 0 && recent >= r.MaxPerIP {
+		score += ratePoints
+	}
+	return score
+}
+
+func (r SpamRules) threshold() int {
+	if r.Threshold > 0 {
+		return r.Threshold
+	}
+	return defaultSpamThreshold
+}
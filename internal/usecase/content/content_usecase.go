@@ -0,0 +1,804 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/utils/fulltext"
+	"github.com/mashurimansur/goCMS/internal/utils/locale"
+	"github.com/mashurimansur/goCMS/internal/utils/markdown"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+	"github.com/mashurimansur/goCMS/internal/utils/slug"
+)
+
+var (
+	// ErrInvalidStatus is returned when a content entry carries an unknown status.
+	ErrInvalidStatus = errors.New("invalid content status")
+	// ErrUnsupportedLocale is returned for a locale missing from the configured locale list.
+	ErrUnsupportedLocale = errors.New("unsupported locale")
+	// ErrInvalidTranslation is returned when a translation names an entry that does not exist.
+	ErrInvalidTranslation = errors.New("invalid translation")
+	// ErrTranslationExists is returned when a translation group already has an entry in the locale.
+	ErrTranslationExists = errors.New("translation already exists")
+	// ErrEmptyQuery is returned when a search query holds no searchable term.
+	ErrEmptyQuery = errors.New("search query is empty")
+)
+
+// reindexBatchSize is the number of entries read per page while rebuilding the search index.
+const reindexBatchSize = 200
+
+// Taxonomy carries the category and tag assignments of a content entry.
+type Taxonomy struct {
+	Categories []*category.Category `json:"categories"`
+	Tags       []*tag.Tag           `json:"tags"`
+}
+
+// TranslationGap reports the configured locales a source entry has no translation in yet.
+type TranslationGap struct {
+	ContentID string   `json:"content_id"`
+	Type      string   `json:"type"`
+	Title     string   `json:"title"`
+	Locale    string   `json:"locale"`
+	Missing   []string `json:"missing"`
+}
+
+type UseCase interface {
+	Create(ctx context.Context, c *content.Content, categoryIDs, tagIDs []string) error
+	Get(ctx context.Context, id string) (*content.Content, error)
+	// GetPublished finds a published entry by slug in any locale and returns the member of its
+	// translation group preferred by locales, a fallback chain such as [ms id en], that viewer may
+	// see. Teasers viewer is not admitted to come locked.
+	GetPublished(ctx context.Context, contentType, slug string, locales []string, viewer content.Viewer) (*content.Content, error)
+	Update(ctx context.Context, c *content.Content, categoryIDs, tagIDs []string) error
+	// Delete moves an entry to the trash, along with the translations of a source entry.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error)
+	// ListPublished lists published entries. With a locale chain every translation group is listed
+	// once, through its published source entry, in the preferred locale available. Entries are
+	// restricted to filter.Viewer, an anonymous reader when unset, with teasers locked.
+	ListPublished(ctx context.Context, filter content.ListFilter, locales []string) ([]*content.Content, error)
+	GetTaxonomy(ctx context.Context, id string) (*Taxonomy, error)
+	// Resolve finds the published entry served at path. When path is a former permalink of an
+	// entry, the entry's current permalink is returned instead so callers can redirect. Slugs shared
+	// by several locales resolve in the order of locales. Entries are restricted to viewer like
+	// GetPublished does.
+	Resolve(ctx context.Context, path string, locales []string, viewer content.Viewer) (*content.Content, string, error)
+	// Translations returns the translation group of an entry, source entry first.
+	Translations(ctx context.Context, id string) ([]*content.Content, error)
+	// MissingTranslations reports source entries lacking a translation in loc, or in any configured
+	// locale when loc is empty.
+	MissingTranslations(ctx context.Context, loc string, filter content.ListFilter) ([]*TranslationGap, error)
+	// Search runs a full-text search over every entry, for editors.
+	Search(ctx context.Context, q content.SearchQuery) (*content.SearchResult, error)
+	// SearchPublished runs a full-text search restricted to published entries, and to q.Viewer like
+	// ListPublished does. Locked hits carry their highlighted excerpt as snippet.
+	SearchPublished(ctx context.Context, q content.SearchQuery) (*content.SearchResult, error)
+	// Reindex rebuilds the search index from the repository and reports how many entries it indexed.
+	Reindex(ctx context.Context) (int, error)
+	// The trash of content entries. Restored entries are indexed for search again; a translation can
+	// only be restored while its source entry is not in the trash.
+	trash.Bin
+	Locales() locale.Settings
+	BlockTypes() []block.TypeInfo
+}
+
+type contentUseCase struct {
+	contentRepo  content.Repository
+	categoryRepo category.Repository
+	tagRepo      tag.Repository
+	redirectRepo content.RedirectRepository
+	permalinks   permalink.Patterns
+	blocks       *block.Registry
+	locales      locale.Settings
+	search       content.Search
+	snippets     content.Snippets
+}
+
+// NewContentUseCase returns the content use case. snippets expands the snippet references of
+// entries read for the public site and may be nil when snippets are not used.
+func NewContentUseCase(contentRepo content.Repository, categoryRepo category.Repository, tagRepo tag.Repository, redirectRepo content.RedirectRepository, permalinks permalink.Patterns, blocks *block.Registry, locales locale.Settings, search content.Search, snippets content.Snippets) UseCase {
+	return &contentUseCase{
+		contentRepo:  contentRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		redirectRepo: redirectRepo,
+		permalinks:   permalinks,
+		blocks:       blocks,
+		locales:      locales,
+		search:       search,
+		snippets:     snippets,
+	}
+}
+
+func (uc *contentUseCase) Create(ctx context.Context, c *content.Content, categoryIDs, tagIDs []string) error {
+	if err := uc.prepare(c); err != nil {
+		return err
+	}
+	if c.TranslationOf != "" {
+		if err := uc.linkTranslation(ctx, c); err != nil {
+			return err
+		}
+	}
+	if err := uc.assignSlug(ctx, c); err != nil {
+		return err
+	}
+	if err := uc.contentRepo.Create(ctx, c); err != nil {
+		return err
+	}
+	uc.setPermalink(c)
+	if err := uc.assignTaxonomy(ctx, c.ID, categoryIDs, tagIDs); err != nil {
+		return err
+	}
+	return uc.search.Index(ctx, c, categoryIDs)
+}
+
+func (uc *contentUseCase) Get(ctx context.Context, id string) (*content.Content, error) {
+	c, err := uc.contentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	uc.setPermalink(c)
+	return c, nil
+}
+
+func (uc *contentUseCase) GetPublished(ctx context.Context, contentType, slug string, locales []string, viewer content.Viewer) (*content.Content, error) {
+	found, err := uc.findPublished(ctx, contentType, slug, locales)
+	if err != nil {
+		return nil, err
+	}
+	c, err := uc.localize(ctx, found, locales, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Restrict(viewer) {
+		return nil, content.ErrNotFound
+	}
+	uc.setPermalink(c)
+	return c, uc.expand(ctx, c)
+}
+
+func (uc *contentUseCase) Update(ctx context.Context, c *content.Content, categoryIDs, tagIDs []string) error {
+	previous, err := uc.contentRepo.GetByID(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	// Fields the editor does not send keep their stored values so the permalink date stays stable.
+	// The locale and translation group of an entry are fixed when it is created.
+	c.CreatedAt = previous.CreatedAt
+	c.Locale = previous.Locale
+	c.TranslationOf = previous.TranslationOf
+	if c.AuthorID == "" {
+		c.AuthorID = previous.AuthorID
+	}
+	if c.PublishedAt.IsZero() {
+		c.PublishedAt = previous.PublishedAt
+	}
+	if c.BodyFormat == "" {
+		c.BodyFormat = previous.BodyFormat
+	}
+	// Restricted entries stay restricted, and teased, unless the editor says otherwise, and keep their
+	// password until a new one is set.
+	if c.Visibility == "" {
+		c.Visibility = previous.Visibility
+		c.Teaser = previous.Teaser
+		if c.AllowedRoles == nil {
+			c.AllowedRoles = previous.AllowedRoles
+		}
+	}
+	if c.Password == "" {
+		c.PasswordHash = previous.PasswordHash
+	}
+	if c.Version, err = lock.Expect(c.Version, previous.Version); err != nil {
+		return err
+	}
+	if err := uc.prepare(c); err != nil {
+		return err
+	}
+	if err := uc.assignSlug(ctx, c); err != nil {
+		return err
+	}
+	if err := uc.contentRepo.Update(ctx, c); err != nil {
+		return err
+	}
+
+	uc.setPermalink(c)
+	if err := uc.trackPermalinkChange(ctx, previous, c); err != nil {
+		return err
+	}
+	if err := uc.assignTaxonomy(ctx, c.ID, categoryIDs, tagIDs); err != nil {
+		return err
+	}
+	return uc.search.Index(ctx, c, categoryIDs)
+}
+
+func (uc *contentUseCase) Delete(ctx context.Context, id string) error {
+	// Translations are trashed along with their source entry, so they leave the index too.
+	translations, err := uc.contentRepo.ListTranslations(ctx, []string{id})
+	if err != nil {
+		return err
+	}
+	if err := uc.contentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	for _, t := range append(translations, &content.Content{ID: id}) {
+		if err := uc.search.Remove(ctx, t.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *contentUseCase) List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	return uc.list(ctx, filter)
+}
+
+func (uc *contentUseCase) ListPublished(ctx context.Context, filter content.ListFilter, locales []string) ([]*content.Content, error) {
+	filter.Status = content.StatusPublished
+	filter.PublishedBefore = time.Now()
+	viewer := listViewer(filter.Viewer)
+	filter.Viewer = &viewer
+	if len(locales) == 0 {
+		entries, err := uc.list(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		// The repository left out the entries hidden from viewer; teasers remain to be locked.
+		for _, c := range entries {
+			c.Restrict(viewer)
+		}
+		return entries, uc.expand(ctx, entries...)
+	}
+
+	filter.SourcesOnly = true
+	sources, err := uc.contentRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := uc.groups(ctx, sources)
+	if err != nil {
+		return nil, err
+	}
+	for i, source := range sources {
+		sources[i] = preferred(visible(groups[source.ID], viewer), locales, source)
+		sources[i].Restrict(viewer)
+		uc.setPermalink(sources[i])
+	}
+	return sources, uc.expand(ctx, sources...)
+}
+
+func (uc *contentUseCase) list(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	entries, err := uc.contentRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range entries {
+		uc.setPermalink(c)
+	}
+	return entries, nil
+}
+
+func (uc *contentUseCase) Search(ctx context.Context, q content.SearchQuery) (*content.SearchResult, error) {
+	if len(fulltext.Terms(q.Text)) == 0 {
+		return nil, ErrEmptyQuery
+	}
+	result, err := uc.search.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range result.Hits {
+		// Hits carry a highlighted snippet; the full body would only bloat the result page.
+		hit.Content.Body = ""
+		hit.Content.BodyHTML = ""
+		hit.Content.Blocks = nil
+		uc.setPermalink(hit.Content)
+	}
+	return result, nil
+}
+
+func (uc *contentUseCase) SearchPublished(ctx context.Context, q content.SearchQuery) (*content.SearchResult, error) {
+	q.Status = content.StatusPublished
+	q.PublishedBefore = time.Now()
+	viewer := listViewer(q.Viewer)
+	q.Viewer = &viewer
+	result, err := uc.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	terms := fulltext.Terms(q.Text)
+	for _, hit := range result.Hits {
+		hit.Content.Restrict(viewer)
+		if hit.Content.Locked {
+			// The snippet was cut from the body the reader is not admitted to.
+			hit.Snippet = fulltext.Highlight(hit.Content.Excerpt, terms)
+			continue
+		}
+		if err := uc.expand(ctx, hit.Content); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (uc *contentUseCase) Reindex(ctx context.Context) (int, error) {
+	indexed := 0
+	for offset := 0; ; offset += reindexBatchSize {
+		entries, err := uc.contentRepo.List(ctx, content.ListFilter{Limit: reindexBatchSize, Offset: offset})
+		if err != nil {
+			return indexed, err
+		}
+		for _, c := range entries {
+			if err := uc.index(ctx, c); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		if len(entries) < reindexBatchSize {
+			return indexed, nil
+		}
+	}
+}
+
+func (uc *contentUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	return uc.contentRepo.ListTrashed(ctx, filter)
+}
+
+func (uc *contentUseCase) Restore(ctx context.Context, id string) error {
+	c, err := uc.contentRepo.GetTrashed(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.TranslationOf != "" {
+		_, err := uc.contentRepo.GetByID(ctx, c.TranslationOf)
+		if errors.Is(err, content.ErrNotFound) {
+			return fmt.Errorf("%w: restore source entry %s first", ErrInvalidTranslation, c.TranslationOf)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := uc.contentRepo.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	// Translations trashed along with a source entry come back with it.
+	translations, err := uc.contentRepo.ListTranslations(ctx, []string{id})
+	if err != nil {
+		return err
+	}
+	for _, t := range append([]*content.Content{c}, translations...) {
+		if err := uc.index(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *contentUseCase) Purge(ctx context.Context, id string) error {
+	return uc.contentRepo.Purge(ctx, id)
+}
+
+func (uc *contentUseCase) GetTaxonomy(ctx context.Context, id string) (*Taxonomy, error) {
+	categories, err := uc.categoryRepo.ListByContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := uc.tagRepo.ListByContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &Taxonomy{Categories: categories, Tags: tags}, nil
+}
+
+func (uc *contentUseCase) Resolve(ctx context.Context, path string, locales []string, viewer content.Viewer) (*content.Content, string, error) {
+	path = normalizePath(path)
+
+	if fields, ok := uc.permalinks.Match(path); ok {
+		c, err := uc.lookup(ctx, fields, locales)
+		if err != nil && !errors.Is(err, content.ErrNotFound) {
+			return nil, "", err
+		}
+		if c != nil && isPublished(c) && c.Visible(viewer) {
+			uc.setPermalink(c)
+			if c.Permalink == path {
+				c.Restrict(viewer)
+				return c, "", uc.expand(ctx, c)
+			}
+			// The entry exists under a different date or type segment; point at its canonical path.
+			return nil, c.Permalink, nil
+		}
+	}
+
+	redirect, err := uc.redirectRepo.GetByPath(ctx, path)
+	if err != nil {
+		return nil, "", err
+	}
+	c, err := uc.contentRepo.GetByID(ctx, redirect.ContentID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !isPublished(c) || !c.Visible(viewer) {
+		return nil, "", content.ErrNotFound
+	}
+	uc.setPermalink(c)
+	return nil, c.Permalink, nil
+}
+
+func (uc *contentUseCase) Translations(ctx context.Context, id string) ([]*content.Content, error) {
+	c, err := uc.contentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	group, err := uc.group(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range group {
+		uc.setPermalink(member)
+	}
+	return group, nil
+}
+
+func (uc *contentUseCase) MissingTranslations(ctx context.Context, loc string, filter content.ListFilter) ([]*TranslationGap, error) {
+	filter.MissingLocales = uc.locales.Locales
+	if loc != "" {
+		loc = locale.Normalize(loc)
+		if !uc.locales.Supported(loc) {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedLocale, loc)
+		}
+		filter.MissingLocales = []string{loc}
+	}
+
+	sources, err := uc.contentRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := uc.groups(ctx, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	gaps := make([]*TranslationGap, 0, len(sources))
+	for _, source := range sources {
+		gap := &TranslationGap{ContentID: source.ID, Type: source.Type, Title: source.Title, Locale: source.Locale, Missing: []string{}}
+		for _, l := range uc.locales.Locales {
+			if !slices.ContainsFunc(groups[source.ID], func(member *content.Content) bool { return member.Locale == l }) {
+				gap.Missing = append(gap.Missing, l)
+			}
+		}
+		gaps = append(gaps, gap)
+	}
+	return gaps, nil
+}
+
+func (uc *contentUseCase) Locales() locale.Settings {
+	return uc.locales
+}
+
+func (uc *contentUseCase) BlockTypes() []block.TypeInfo {
+	return uc.blocks.Types()
+}
+
+func (uc *contentUseCase) lookup(ctx context.Context, fields permalink.Fields, locales []string) (*content.Content, error) {
+	if fields.Slug != "" && fields.Locale != "" {
+		return uc.contentRepo.GetBySlug(ctx, fields.Type, fields.Locale, fields.Slug)
+	}
+	if fields.Slug != "" {
+		return uc.findPublished(ctx, fields.Type, fields.Slug, locales)
+	}
+	c, err := uc.contentRepo.GetByID(ctx, fields.ID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Type != fields.Type {
+		return nil, content.ErrNotFound
+	}
+	return c, nil
+}
+
+// index adds an entry to the search index along with its categories.
+func (uc *contentUseCase) index(ctx context.Context, c *content.Content) error {
+	categories, err := uc.categoryRepo.ListByContent(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	categoryIDs := make([]string, len(categories))
+	for i, cat := range categories {
+		categoryIDs[i] = cat.ID
+	}
+	return uc.search.Index(ctx, c, categoryIDs)
+}
+
+// assignSlug derives the slug from the title when missing and makes it unique within the content type.
+// Slugs of trashed entries stay taken so the entries can be restored.
+func (uc *contentUseCase) assignSlug(ctx context.Context, c *content.Content) error {
+	base := c.Slug
+	if base == "" {
+		base = c.Title
+	}
+
+	unique, err := slug.Unique(ctx, base, func(ctx context.Context, candidate string) (bool, error) {
+		return uc.contentRepo.SlugInUse(ctx, c.Type, c.Locale, candidate, c.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Slug = unique
+	return nil
+}
+
+// findPublished looks slug up in the locales of the chain first and then in every other configured
+// locale, since a slug identifies an entry whatever its language. The first published match wins.
+func (uc *contentUseCase) findPublished(ctx context.Context, contentType, slug string, chain []string) (*content.Content, error) {
+	order := slices.Clone(chain)
+	for _, l := range uc.locales.Locales {
+		if !slices.Contains(order, l) {
+			order = append(order, l)
+		}
+	}
+
+	for _, l := range order {
+		c, err := uc.contentRepo.GetBySlug(ctx, contentType, l, slug)
+		if errors.Is(err, content.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isPublished(c) {
+			return c, nil
+		}
+	}
+	return nil, content.ErrNotFound
+}
+
+// localize swaps c for the published member of its translation group viewer may see in the first
+// locale of chain that has one. c is kept when no locale of the chain has such a version.
+func (uc *contentUseCase) localize(ctx context.Context, c *content.Content, chain []string, viewer content.Viewer) (*content.Content, error) {
+	if len(chain) == 0 || c.Locale == chain[0] {
+		return c, nil
+	}
+	group, err := uc.group(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return preferred(visible(group, viewer), chain, c), nil
+}
+
+// group returns the translation group of c, source entry first.
+func (uc *contentUseCase) group(ctx context.Context, c *content.Content) ([]*content.Content, error) {
+	source := c
+	if c.TranslationOf != "" {
+		var err error
+		if source, err = uc.contentRepo.GetByID(ctx, c.TranslationOf); err != nil {
+			return nil, err
+		}
+	}
+	translations, err := uc.contentRepo.ListTranslations(ctx, []string{source.ID})
+	if err != nil {
+		return nil, err
+	}
+	return append([]*content.Content{source}, translations...), nil
+}
+
+// groups loads the translation groups of source entries in one query, keyed by source ID.
+func (uc *contentUseCase) groups(ctx context.Context, sources []*content.Content) (map[string][]*content.Content, error) {
+	groups := make(map[string][]*content.Content, len(sources))
+	ids := make([]string, len(sources))
+	for i, source := range sources {
+		ids[i] = source.ID
+		groups[source.ID] = []*content.Content{source}
+	}
+
+	translations, err := uc.contentRepo.ListTranslations(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range translations {
+		groups[t.TranslationOf] = append(groups[t.TranslationOf], t)
+	}
+	return groups, nil
+}
+
+// linkTranslation attaches a new entry to the translation group of the entry named by TranslationOf,
+// which may be the source entry or one of its translations. Translations share the type of the source.
+func (uc *contentUseCase) linkTranslation(ctx context.Context, c *content.Content) error {
+	target, err := uc.contentRepo.GetByID(ctx, c.TranslationOf)
+	if errors.Is(err, content.ErrNotFound) {
+		return fmt.Errorf("%w: entry %s does not exist", ErrInvalidTranslation, c.TranslationOf)
+	}
+	if err != nil {
+		return err
+	}
+
+	group, err := uc.group(ctx, target)
+	if err != nil {
+		return err
+	}
+	for _, member := range group {
+		if member.Locale == c.Locale {
+			return fmt.Errorf("%w: %s already has a %s version", ErrTranslationExists, group[0].ID, c.Locale)
+		}
+	}
+	c.TranslationOf = group[0].ID
+	c.Type = group[0].Type
+	return nil
+}
+
+// trackPermalinkChange records the previous public path of an entry whose permalink changed.
+func (uc *contentUseCase) trackPermalinkChange(ctx context.Context, previous, current *content.Content) error {
+	uc.setPermalink(previous)
+	if previous.Permalink == current.Permalink {
+		return nil
+	}
+
+	// The new path may have been a redirect source before; it is live again now.
+	if err := uc.redirectRepo.DeleteByPath(ctx, current.Permalink); err != nil {
+		return err
+	}
+	if previous.Status != content.StatusPublished {
+		return nil
+	}
+	return uc.redirectRepo.Save(ctx, &content.SlugRedirect{ContentID: current.ID, OldPath: previous.Permalink})
+}
+
+func (uc *contentUseCase) setPermalink(c *content.Content) {
+	c.Permalink = uc.permalinks.Build(permalink.Fields{
+		ID:     c.ID,
+		Type:   c.Type,
+		Slug:   c.Slug,
+		Locale: c.Locale,
+		Date:   c.PermalinkDate(),
+	})
+}
+
+// assignTaxonomy replaces category and tag assignments. A nil slice leaves the assignment untouched.
+func (uc *contentUseCase) assignTaxonomy(ctx context.Context, contentID string, categoryIDs, tagIDs []string) error {
+	if categoryIDs != nil {
+		if err := uc.categoryRepo.SetContentCategories(ctx, contentID, categoryIDs); err != nil {
+			return err
+		}
+	}
+	if tagIDs != nil {
+		if err := uc.tagRepo.SetContentTags(ctx, contentID, tagIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepare applies defaults, validates the locale, visibility and status and renders the body to
+// sanitized HTML.
+func (uc *contentUseCase) prepare(c *content.Content) error {
+	if c.Type == "" {
+		c.Type = "post"
+	}
+	if c.Locale == "" {
+		c.Locale = uc.locales.Default()
+	}
+	c.Locale = locale.Normalize(c.Locale)
+	if !uc.locales.Supported(c.Locale) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedLocale, c.Locale)
+	}
+	if c.BodyFormat == "" {
+		c.BodyFormat = markdown.FormatMarkdown
+	}
+	if err := uc.renderBody(c); err != nil {
+		return err
+	}
+
+	if err := prepareVisibility(c); err != nil {
+		return err
+	}
+
+	if c.Status == "" {
+		c.Status = content.StatusDraft
+	}
+	switch c.Status {
+	case content.StatusDraft, content.StatusArchived:
+	case content.StatusPublished:
+		if c.PublishedAt.IsZero() {
+			c.PublishedAt = time.Now()
+		}
+	default:
+		return ErrInvalidStatus
+	}
+	return nil
+}
+
+// renderBody fills BodyHTML from the source of the entry. Blocks are validated against their schemas
+// and also rendered to plain text, which becomes the body used by excerpts, feeds and search.
+func (uc *contentUseCase) renderBody(c *content.Content) error {
+	if c.BodyFormat != content.FormatBlocks {
+		c.Blocks = nil
+		html, err := markdown.Render(c.Body, c.BodyFormat)
+		if err != nil {
+			return err
+		}
+		c.BodyHTML = html
+		return nil
+	}
+
+	html, err := uc.blocks.RenderHTML(c.Blocks)
+	if err != nil {
+		return err
+	}
+	text, err := uc.blocks.RenderText(c.Blocks)
+	if err != nil {
+		return err
+	}
+	c.BodyHTML = html
+	c.Body = text
+	return nil
+}
+
+// prepareVisibility defaults entries to public, checks the roles of role-restricted entries and
+// hashes a new password. Settings that do not apply to the visibility are cleared.
+func prepareVisibility(c *content.Content) error {
+	access := c.Access()
+	if err := access.Prepare(c.Password); err != nil {
+		return err
+	}
+	c.SetAccess(access)
+	c.Password = ""
+	return nil
+}
+
+// expand resolves the snippet references of entries read for the public site.
+func (uc *contentUseCase) expand(ctx context.Context, entries ...*content.Content) error {
+	if uc.snippets == nil || len(entries) == 0 {
+		return nil
+	}
+	return uc.snippets.Expand(ctx, entries...)
+}
+
+// listViewer returns the reader lists are restricted to: v, or an anonymous reader when v is nil.
+// Passwords only open single entries, so lists need not compare them.
+func listViewer(v *content.Viewer) content.Viewer {
+	if v == nil {
+		return content.Viewer{}
+	}
+	return v.WithoutPassword()
+}
+
+// visible returns the members of group viewer may see, in full or as a teaser.
+func visible(group []*content.Content, viewer content.Viewer) []*content.Content {
+	var members []*content.Content
+	for _, member := range group {
+		if member.Visible(viewer) {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// preferred returns the published member of group in the first locale of chain that has one, or
+// fallback when there is none.
+func preferred(group []*content.Content, chain []string, fallback *content.Content) *content.Content {
+	for _, l := range chain {
+		for _, member := range group {
+			if member.Locale == l && isPublished(member) {
+				return member
+			}
+		}
+	}
+	return fallback
+}
+
+func normalizePath(path string) string {
+	return "/" + strings.Trim(path, "/")
+}
+
+func isPublished(c *content.Content) bool {
+	return c.Status == content.StatusPublished && !c.PublishedAt.After(time.Now())
+}
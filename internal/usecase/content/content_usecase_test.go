@@ -0,0 +1,736 @@
+package content
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/adapter/search/memory"
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/utils/locale"
+	"github.com/mashurimansur/goCMS/internal/utils/markdown"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockContentRepository struct {
+	mock.Mock
+}
+
+func (m *MockContentRepository) Create(ctx context.Context, c *content.Content) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockContentRepository) GetByID(ctx context.Context, id string) (*content.Content, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) GetBySlug(ctx context.Context, contentType, locale, slug string) (*content.Content, error) {
+	args := m.Called(ctx, contentType, locale, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) SlugInUse(ctx context.Context, contentType, locale, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, contentType, locale, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockContentRepository) Update(ctx context.Context, c *content.Content) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockContentRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockContentRepository) List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) ListTranslations(ctx context.Context, sourceIDs []string) ([]*content.Content, error) {
+	args := m.Called(ctx, sourceIDs)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) GetTrashed(ctx context.Context, id string) (*content.Content, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockContentRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockContentRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockRedirectRepository struct {
+	mock.Mock
+}
+
+func (m *MockRedirectRepository) Save(ctx context.Context, r *content.SlugRedirect) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRedirectRepository) GetByPath(ctx context.Context, path string) (*content.SlugRedirect, error) {
+	args := m.Called(ctx, path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.SlugRedirect), args.Error(1)
+}
+
+func (m *MockRedirectRepository) DeleteByPath(ctx context.Context, path string) error {
+	args := m.Called(ctx, path)
+	return args.Error(0)
+}
+
+func (m *MockRedirectRepository) ListByContent(ctx context.Context, contentID string) ([]*content.SlugRedirect, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).([]*content.SlugRedirect), args.Error(1)
+}
+
+var (
+	testPermalinks = permalink.Patterns{"post": "/{year}/{month}/{slug}", "page": "/{slug}"}
+	testBlocks     = block.NewDefaultRegistry(block.Options{})
+	testLocales    = locale.Settings{Locales: []string{"en"}}
+)
+
+type MockCategoryRepository struct {
+	mock.Mock
+	category.Repository
+}
+
+func (m *MockCategoryRepository) ListByContent(ctx context.Context, contentID string) ([]*category.Category, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) SetContentCategories(ctx context.Context, contentID string, categoryIDs []string) error {
+	args := m.Called(ctx, contentID, categoryIDs)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockTagRepository struct {
+	mock.Mock
+	tag.Repository
+}
+
+func (m *MockTagRepository) ListByContent(ctx context.Context, contentID string) ([]*tag.Tag, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).([]*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) SetContentTags(ctx context.Context, contentID string, tagIDs []string) error {
+	args := m.Called(ctx, contentID, tagIDs)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTagRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockTagRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestContentUseCase_Create_AssignsTaxonomy(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	categoryRepo := new(MockCategoryRepository)
+	tagRepo := new(MockTagRepository)
+	uc := NewContentUseCase(contentRepo, categoryRepo, tagRepo, new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	c := &content.Content{Title: "Hello World", Status: content.StatusPublished}
+
+	contentRepo.On("SlugInUse", mock.Anything, "post", "en", "hello-world", "").Return(true, nil)
+	contentRepo.On("SlugInUse", mock.Anything, "post", "en", "hello-world-2", "").Return(false, nil)
+	contentRepo.On("Create", mock.Anything, mock.MatchedBy(func(arg *content.Content) bool {
+		return arg.Type == "post" && arg.Slug == "hello-world-2" && !arg.PublishedAt.IsZero()
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*content.Content).ID = "content-1"
+	}).Return(nil)
+	categoryRepo.On("SetContentCategories", mock.Anything, "content-1", []string{"news"}).Return(nil)
+	tagRepo.On("SetContentTags", mock.Anything, "content-1", []string{"go"}).Return(nil)
+
+	err := uc.Create(context.Background(), c, []string{"news"}, []string{"go"})
+	assert.NoError(t, err)
+	assert.Equal(t, c.PublishedAt.Format("/2006/01/")+"hello-world-2", c.Permalink)
+	contentRepo.AssertExpectations(t)
+	categoryRepo.AssertExpectations(t)
+	tagRepo.AssertExpectations(t)
+}
+
+func TestContentUseCase_Create_InvalidStatus(t *testing.T) {
+	uc := NewContentUseCase(new(MockContentRepository), new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	err := uc.Create(context.Background(), &content.Content{Status: "unknown"}, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
+
+func TestContentUseCase_Create_RendersSanitizedHTML(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	c := &content.Content{Title: "Hello", Body: "**Bold** <script>alert(1)</script>"}
+	contentRepo.On("SlugInUse", mock.Anything, "post", "en", "hello", "").Return(false, nil)
+	contentRepo.On("Create", mock.Anything, c).Return(nil)
+
+	err := uc.Create(context.Background(), c, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "markdown", c.BodyFormat)
+	assert.Contains(t, c.BodyHTML, "<strong>Bold</strong>")
+	assert.NotContains(t, c.BodyHTML, "<script")
+}
+
+func TestContentUseCase_Create_UnknownBodyFormat(t *testing.T) {
+	uc := NewContentUseCase(new(MockContentRepository), new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	err := uc.Create(context.Background(), &content.Content{Title: "Hello", BodyFormat: "textile"}, nil, nil)
+	assert.ErrorIs(t, err, markdown.ErrUnknownFormat)
+}
+
+func TestContentUseCase_Create_RendersBlocks(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	c := &content.Content{Title: "Landing", BodyFormat: content.FormatBlocks, Blocks: []block.Block{
+		{Type: block.TypeHeading, Data: []byte(`{"text":"Welcome"}`)},
+		{Type: block.TypeCallToAction, Data: []byte(`{"text":"Start","url":"/start"}`)},
+	}}
+	contentRepo.On("SlugInUse", mock.Anything, "post", "en", "landing", "").Return(false, nil)
+	contentRepo.On("Create", mock.Anything, c).Return(nil)
+
+	err := uc.Create(context.Background(), c, nil, nil)
+	require.NoError(t, err)
+	assert.Contains(t, c.BodyHTML, "<h2>Welcome</h2>")
+	assert.Contains(t, c.BodyHTML, `href="/start"`)
+	assert.Equal(t, "Welcome\n\nStart", c.Body)
+}
+
+func TestContentUseCase_Create_InvalidBlock(t *testing.T) {
+	uc := NewContentUseCase(new(MockContentRepository), new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	err := uc.Create(context.Background(), &content.Content{Title: "Landing", BodyFormat: content.FormatBlocks, Blocks: []block.Block{
+		{Type: block.TypeImage, Data: []byte(`{"alt":"missing media"}`)},
+	}}, nil, nil)
+	assert.ErrorIs(t, err, block.ErrInvalidData)
+}
+
+func TestContentUseCase_Update_KeepsBodyFormat(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	c := &content.Content{ID: "content-1", Title: "Hello", Slug: "hello", Body: "<p onclick=\"x()\">Hi</p>"}
+	contentRepo.On("GetByID", mock.Anything, "content-1").Return(&content.Content{ID: "content-1", Slug: "hello", Type: "post", BodyFormat: "html", Status: content.StatusDraft}, nil)
+	contentRepo.On("SlugInUse", mock.Anything, "post", "en", "hello", "content-1").Return(false, nil)
+	contentRepo.On("Update", mock.Anything, c).Return(nil)
+
+	err := uc.Update(context.Background(), c, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "html", c.BodyFormat)
+	assert.Equal(t, "<p>Hi</p>", c.BodyHTML)
+}
+
+func TestContentUseCase_Update_KeepsVisibility(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	c := &content.Content{ID: "content-1", Title: "Hello", Slug: "hello", Body: "Hi"}
+	contentRepo.On("GetByID", mock.Anything, "content-1").Return(&content.Content{ID: "content-1", Slug: "hello", Type: "post", Status: content.StatusDraft, Visibility: content.VisibilityPassword, PasswordHash: "hash", Teaser: true}, nil)
+	contentRepo.On("SlugInUse", mock.Anything, "post", "en", "hello", "content-1").Return(false, nil)
+	contentRepo.On("Update", mock.Anything, c).Return(nil)
+
+	require.NoError(t, uc.Update(context.Background(), c, nil, nil))
+	assert.Equal(t, content.VisibilityPassword, c.Visibility)
+	assert.Equal(t, "hash", c.PasswordHash)
+	assert.True(t, c.Teaser)
+}
+
+func TestContentUseCase_Update_NilTaxonomyLeavesAssignments(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	categoryRepo := new(MockCategoryRepository)
+	tagRepo := new(MockTagRepository)
+	uc := NewContentUseCase(contentRepo, categoryRepo, tagRepo, new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	c := &content.Content{ID: "content-1", Title: "Hello", Slug: "hello"}
+	contentRepo.On("GetByID", mock.Anything, "content-1").Return(&content.Content{ID: "content-1", Slug: "hello", Type: "post", Status: content.StatusDraft}, nil)
+	contentRepo.On("SlugInUse", mock.Anything, "post", "en", "hello", "content-1").Return(false, nil)
+	contentRepo.On("Update", mock.Anything, c).Return(nil)
+	tagRepo.On("SetContentTags", mock.Anything, "content-1", []string{}).Return(nil)
+
+	err := uc.Update(context.Background(), c, nil, []string{})
+	assert.NoError(t, err)
+	tagRepo.AssertExpectations(t)
+	categoryRepo.AssertNotCalled(t, "SetContentCategories", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestContentUseCase_GetPublished_HidesDraftsAndScheduled(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	draft := &content.Content{ID: "1", Status: content.StatusDraft}
+	scheduled := &content.Content{ID: "2", Status: content.StatusPublished, PublishedAt: time.Now().Add(time.Hour)}
+	live := &content.Content{ID: "3", Status: content.StatusPublished, PublishedAt: time.Now().Add(-time.Hour)}
+	contentRepo.On("GetBySlug", mock.Anything, "post", "en", "draft").Return(draft, nil)
+	contentRepo.On("GetBySlug", mock.Anything, "post", "en", "scheduled").Return(scheduled, nil)
+	contentRepo.On("GetBySlug", mock.Anything, "post", "en", "live").Return(live, nil)
+
+	_, err := uc.GetPublished(context.Background(), "post", "draft", nil, content.Viewer{})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+	_, err = uc.GetPublished(context.Background(), "post", "scheduled", nil, content.Viewer{})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+
+	got, err := uc.GetPublished(context.Background(), "post", "live", nil, content.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, live, got)
+}
+
+func TestContentUseCase_GetPublished_RestrictsVisibility(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	past := time.Now().Add(-time.Hour)
+	members := func() *content.Content {
+		return &content.Content{ID: "1", Status: content.StatusPublished, PublishedAt: past, Visibility: content.VisibilityAuthenticated, Excerpt: "Intro", BodyHTML: "<p>Full</p>"}
+	}
+	teaser := func() *content.Content {
+		c := members()
+		c.Teaser = true
+		return c
+	}
+	contentRepo.On("GetBySlug", mock.Anything, "post", "en", "members").Return(members(), nil).Once()
+	contentRepo.On("GetBySlug", mock.Anything, "post", "en", "teaser").Return(teaser(), nil).Once()
+	contentRepo.On("GetBySlug", mock.Anything, "post", "en", "teaser").Return(teaser(), nil).Once()
+
+	_, err := uc.GetPublished(context.Background(), "post", "members", nil, content.Viewer{})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+
+	got, err := uc.GetPublished(context.Background(), "post", "teaser", nil, content.Viewer{})
+	require.NoError(t, err)
+	assert.True(t, got.Locked)
+	assert.Equal(t, "Intro", got.Excerpt)
+	assert.Empty(t, got.BodyHTML)
+
+	got, err = uc.GetPublished(context.Background(), "post", "teaser", nil, content.Viewer{UserID: "user-1"})
+	require.NoError(t, err)
+	assert.False(t, got.Locked)
+	assert.Equal(t, "<p>Full</p>", got.BodyHTML)
+}
+
+func TestContentUseCase_ListPublished_FiltersByViewer(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	contentRepo.On("List", mock.Anything, mock.MatchedBy(func(f content.ListFilter) bool {
+		return f.Viewer != nil && *f.Viewer == content.Viewer{}
+	})).Return([]*content.Content{
+		{ID: "1", Visibility: content.VisibilityPassword, Teaser: true, BodyHTML: "<p>Full</p>"},
+	}, nil)
+
+	entries, err := uc.ListPublished(context.Background(), content.ListFilter{Viewer: &content.Viewer{Password: "s3cret"}}, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, entries[0].Locked)
+	assert.Empty(t, entries[0].BodyHTML)
+}
+
+func TestPrepareVisibility(t *testing.T) {
+	c := &content.Content{}
+	require.NoError(t, prepareVisibility(c))
+	assert.Equal(t, content.VisibilityPublic, c.Visibility)
+
+	c = &content.Content{Visibility: content.VisibilityRoles, AllowedRoles: []string{" editor ", "editor", "", "admin"}, PasswordHash: "hash"}
+	require.NoError(t, prepareVisibility(c))
+	assert.Equal(t, []string{"editor", "admin"}, c.AllowedRoles)
+	assert.Empty(t, c.PasswordHash)
+
+	c = &content.Content{Visibility: content.VisibilityPassword, Password: "s3cret", AllowedRoles: []string{"editor"}}
+	require.NoError(t, prepareVisibility(c))
+	assert.Empty(t, c.Password)
+	assert.Nil(t, c.AllowedRoles)
+	assert.True(t, c.Admits(content.Viewer{Password: "s3cret"}))
+
+	assert.ErrorIs(t, prepareVisibility(&content.Content{Visibility: content.VisibilityRoles}), content.ErrInvalidVisibility)
+	assert.ErrorIs(t, prepareVisibility(&content.Content{Visibility: content.VisibilityPassword}), content.ErrInvalidVisibility)
+	assert.ErrorIs(t, prepareVisibility(&content.Content{Visibility: "secret"}), content.ErrInvalidVisibility)
+}
+
+func TestContentUseCase_ListPublished_ForcesStatus(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	contentRepo.On("List", mock.Anything, mock.MatchedBy(func(f content.ListFilter) bool {
+		return f.Status == content.StatusPublished && !f.PublishedBefore.IsZero() && f.Limit == 5
+	})).Return([]*content.Content{}, nil)
+
+	_, err := uc.ListPublished(context.Background(), content.ListFilter{Status: content.StatusDraft, Limit: 5}, nil)
+	assert.NoError(t, err)
+	contentRepo.AssertExpectations(t)
+}
+
+func TestContentUseCase_GetTaxonomy(t *testing.T) {
+	categoryRepo := new(MockCategoryRepository)
+	tagRepo := new(MockTagRepository)
+	uc := NewContentUseCase(new(MockContentRepository), categoryRepo, tagRepo, new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	categoryRepo.On("ListByContent", mock.Anything, "content-1").Return([]*category.Category{{ID: "news"}}, nil)
+	tagRepo.On("ListByContent", mock.Anything, "content-1").Return([]*tag.Tag{{ID: "go"}}, nil)
+
+	taxonomy, err := uc.GetTaxonomy(context.Background(), "content-1")
+	require.NoError(t, err)
+	assert.Len(t, taxonomy.Categories, 1)
+	assert.Len(t, taxonomy.Tags, 1)
+}
+
+func TestContentUseCase_Update_RecordsRedirectOnSlugChange(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	redirectRepo := new(MockRedirectRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), redirectRepo, testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	publishedAt := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	previous := &content.Content{ID: "content-1", Type: "post", Slug: "old-title", Status: content.StatusPublished, PublishedAt: publishedAt, AuthorID: "author-1"}
+	c := &content.Content{ID: "content-1", Type: "post", Title: "New Title", Status: content.StatusPublished}
+
+	contentRepo.On("GetByID", mock.Anything, "content-1").Return(previous, nil)
+	contentRepo.On("SlugInUse", mock.Anything, "post", "en", "new-title", "content-1").Return(false, nil)
+	contentRepo.On("Update", mock.Anything, mock.MatchedBy(func(arg *content.Content) bool {
+		return arg.Slug == "new-title" && arg.PublishedAt.Equal(publishedAt) && arg.AuthorID == "author-1"
+	})).Return(nil)
+	redirectRepo.On("DeleteByPath", mock.Anything, "/2025/01/new-title").Return(nil)
+	redirectRepo.On("Save", mock.Anything, &content.SlugRedirect{ContentID: "content-1", OldPath: "/2025/01/old-title"}).Return(nil)
+
+	err := uc.Update(context.Background(), c, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/2025/01/new-title", c.Permalink)
+	contentRepo.AssertExpectations(t)
+	redirectRepo.AssertExpectations(t)
+}
+
+func TestContentUseCase_Update_DraftDoesNotRecordRedirect(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	redirectRepo := new(MockRedirectRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), redirectRepo, testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	previous := &content.Content{ID: "content-1", Type: "page", Slug: "draft", Status: content.StatusDraft}
+	c := &content.Content{ID: "content-1", Type: "page", Slug: "about"}
+
+	contentRepo.On("GetByID", mock.Anything, "content-1").Return(previous, nil)
+	contentRepo.On("SlugInUse", mock.Anything, "page", "en", "about", "content-1").Return(false, nil)
+	contentRepo.On("Update", mock.Anything, c).Return(nil)
+	redirectRepo.On("DeleteByPath", mock.Anything, "/about").Return(nil)
+
+	require.NoError(t, uc.Update(context.Background(), c, nil, nil))
+	redirectRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestContentUseCase_Resolve(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	redirectRepo := new(MockRedirectRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), redirectRepo, testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	publishedAt := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	live := &content.Content{ID: "content-1", Type: "post", Slug: "hello", Status: content.StatusPublished, PublishedAt: publishedAt}
+	contentRepo.On("GetBySlug", mock.Anything, "post", "en", "hello").Return(live, nil)
+	contentRepo.On("GetBySlug", mock.Anything, "post", "en", "old").Return(nil, content.ErrNotFound)
+	contentRepo.On("GetBySlug", mock.Anything, "page", "en", "gone").Return(nil, content.ErrNotFound)
+	contentRepo.On("GetByID", mock.Anything, "content-1").Return(live, nil)
+	redirectRepo.On("GetByPath", mock.Anything, "/2025/01/old").Return(&content.SlugRedirect{ContentID: "content-1"}, nil)
+	redirectRepo.On("GetByPath", mock.Anything, "/gone").Return(nil, content.ErrNotFound)
+
+	got, location, err := uc.Resolve(context.Background(), "/2025/02/hello/", nil, content.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, live, got)
+	assert.Empty(t, location)
+
+	got, location, err = uc.Resolve(context.Background(), "/2024/12/hello", nil, content.Viewer{})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	assert.Equal(t, "/2025/02/hello", location)
+
+	got, location, err = uc.Resolve(context.Background(), "/2025/01/old", nil, content.Viewer{})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	assert.Equal(t, "/2025/02/hello", location)
+
+	_, _, err = uc.Resolve(context.Background(), "/gone", nil, content.Viewer{})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+}
+
+func newMultilingualUseCase(contentRepo *MockContentRepository) UseCase {
+	locales, _ := locale.Parse("id,en,ms", "ms=id")
+	return NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, locales, memory.NewIndex(), nil)
+}
+
+func TestContentUseCase_Create_Translation(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := newMultilingualUseCase(contentRepo)
+
+	source := &content.Content{ID: "content-1", Type: "page", Locale: "id", Slug: "tentang"}
+	contentRepo.On("GetByID", mock.Anything, "content-1").Return(source, nil)
+	contentRepo.On("ListTranslations", mock.Anything, []string{"content-1"}).Return([]*content.Content{
+		{ID: "content-2", Type: "page", Locale: "ms", TranslationOf: "content-1"},
+	}, nil)
+	contentRepo.On("SlugInUse", mock.Anything, "page", "en", "about", "").Return(false, nil)
+	contentRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *content.Content) bool {
+		return c.Type == "page" && c.Locale == "en" && c.TranslationOf == "content-1"
+	})).Return(nil)
+
+	err := uc.Create(context.Background(), &content.Content{Title: "About", Locale: "EN", TranslationOf: "content-1"}, nil, nil)
+	require.NoError(t, err)
+
+	err = uc.Create(context.Background(), &content.Content{Title: "Tentang", Locale: "ms", TranslationOf: "content-1"}, nil, nil)
+	assert.ErrorIs(t, err, ErrTranslationExists)
+	err = uc.Create(context.Background(), &content.Content{Title: "Über", Locale: "de"}, nil, nil)
+	assert.ErrorIs(t, err, ErrUnsupportedLocale)
+	contentRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestContentUseCase_GetPublished_FallsBack(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := newMultilingualUseCase(contentRepo)
+
+	past := time.Now().Add(-time.Hour)
+	source := &content.Content{ID: "content-1", Type: "post", Locale: "id", Slug: "halo", Status: content.StatusPublished, PublishedAt: past}
+	draftEnglish := &content.Content{ID: "content-2", Type: "post", Locale: "en", Slug: "hello", TranslationOf: "content-1", Status: content.StatusDraft}
+	contentRepo.On("GetBySlug", mock.Anything, "post", "ms", "halo").Return(nil, content.ErrNotFound)
+	contentRepo.On("GetBySlug", mock.Anything, "post", "en", "halo").Return(nil, content.ErrNotFound)
+	contentRepo.On("GetBySlug", mock.Anything, "post", "id", "halo").Return(source, nil)
+	contentRepo.On("ListTranslations", mock.Anything, []string{"content-1"}).Return([]*content.Content{draftEnglish}, nil)
+
+	got, err := uc.GetPublished(context.Background(), "post", "halo", uc.Locales().Chain("ms"), content.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, "content-1", got.ID)
+
+	got, err = uc.GetPublished(context.Background(), "post", "halo", []string{"en", "id"}, content.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, "content-1", got.ID, "unpublished translations are skipped")
+
+	draftEnglish.Status = content.StatusPublished
+	draftEnglish.PublishedAt = past
+	got, err = uc.GetPublished(context.Background(), "post", "halo", []string{"en", "id"}, content.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, "content-2", got.ID)
+}
+
+func TestContentUseCase_ListPublished_Localizes(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := newMultilingualUseCase(contentRepo)
+
+	past := time.Now().Add(-time.Hour)
+	sources := []*content.Content{
+		{ID: "content-1", Type: "post", Locale: "id", Status: content.StatusPublished, PublishedAt: past},
+		{ID: "content-3", Type: "post", Locale: "id", Status: content.StatusPublished, PublishedAt: past},
+	}
+	contentRepo.On("List", mock.Anything, mock.MatchedBy(func(f content.ListFilter) bool {
+		return f.SourcesOnly && f.Status == content.StatusPublished
+	})).Return(sources, nil)
+	contentRepo.On("ListTranslations", mock.Anything, []string{"content-1", "content-3"}).Return([]*content.Content{
+		{ID: "content-2", Type: "post", Locale: "en", TranslationOf: "content-1", Status: content.StatusPublished, PublishedAt: past},
+	}, nil)
+
+	entries, err := uc.ListPublished(context.Background(), content.ListFilter{Limit: 10}, []string{"en", "id"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "content-2", entries[0].ID)
+	assert.Equal(t, "content-3", entries[1].ID)
+}
+
+func TestContentUseCase_MissingTranslations(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := newMultilingualUseCase(contentRepo)
+
+	contentRepo.On("List", mock.Anything, mock.MatchedBy(func(f content.ListFilter) bool {
+		return assert.ObjectsAreEqual([]string{"id", "en", "ms"}, f.MissingLocales)
+	})).Return([]*content.Content{{ID: "content-1", Type: "post", Locale: "id", Title: "Halo"}}, nil)
+	contentRepo.On("ListTranslations", mock.Anything, []string{"content-1"}).Return([]*content.Content{
+		{ID: "content-2", Locale: "en", TranslationOf: "content-1"},
+	}, nil)
+
+	gaps, err := uc.MissingTranslations(context.Background(), "", content.ListFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, gaps, 1)
+	assert.Equal(t, []string{"ms"}, gaps[0].Missing)
+
+	_, err = uc.MissingTranslations(context.Background(), "fr", content.ListFilter{})
+	assert.ErrorIs(t, err, ErrUnsupportedLocale)
+}
+
+func TestContentUseCase_Search_IndexesOnSave(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	categoryRepo := new(MockCategoryRepository)
+	uc := NewContentUseCase(contentRepo, categoryRepo, new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	contentRepo.On("SlugInUse", mock.Anything, "post", "en", mock.Anything, "").Return(false, nil)
+	contentRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		c := args.Get(1).(*content.Content)
+		c.ID = c.Slug
+	}).Return(nil)
+	categoryRepo.On("SetContentCategories", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, uc.Create(context.Background(), &content.Content{Title: "Gopher news", Body: "Weekly roundup.", Status: content.StatusPublished, PublishedAt: past}, []string{"news"}, nil))
+	require.NoError(t, uc.Create(context.Background(), &content.Content{Title: "Release notes", Body: "A gopher fixed the build.", Status: content.StatusPublished, PublishedAt: past}, []string{"dev"}, nil))
+	require.NoError(t, uc.Create(context.Background(), &content.Content{Title: "Gopher draft", Body: "Not yet.", Status: content.StatusDraft}, nil, nil))
+
+	result, err := uc.SearchPublished(context.Background(), content.SearchQuery{Text: "gopher", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	require.Len(t, result.Hits, 2)
+	assert.Equal(t, "gopher-news", result.Hits[0].Content.ID)
+	assert.Equal(t, "<mark>Gopher</mark> news", result.Hits[0].Title)
+	assert.Equal(t, "A <mark>gopher</mark> fixed the build.", result.Hits[1].Snippet)
+	assert.Empty(t, result.Hits[1].Content.BodyHTML)
+	assert.NotEmpty(t, result.Hits[1].Content.Permalink)
+	assert.ElementsMatch(t, []content.FacetValue{{Value: "dev", Count: 1}, {Value: "news", Count: 1}}, result.Facets[content.FacetCategory])
+
+	result, err = uc.Search(context.Background(), content.SearchQuery{Text: "gopher", CategoryID: "dev", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, "release-notes", result.Hits[0].Content.ID)
+
+	result, err = uc.Search(context.Background(), content.SearchQuery{Text: "gopher", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, []content.FacetValue{{Value: "post", Count: 3}}, result.Facets[content.FacetType])
+}
+
+func TestContentUseCase_Delete_RemovesFromIndex(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	index := memory.NewIndex()
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, index, nil)
+
+	require.NoError(t, index.Index(context.Background(), &content.Content{ID: "source", Title: "Hello world"}, nil))
+	require.NoError(t, index.Index(context.Background(), &content.Content{ID: "translation", Title: "Halo world", TranslationOf: "source"}, nil))
+	contentRepo.On("ListTranslations", mock.Anything, []string{"source"}).Return([]*content.Content{{ID: "translation"}}, nil)
+	contentRepo.On("Delete", mock.Anything, "source").Return(nil)
+
+	require.NoError(t, uc.Delete(context.Background(), "source"))
+
+	result, err := uc.Search(context.Background(), content.SearchQuery{Text: "world", Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, result.Total)
+}
+
+func TestContentUseCase_Restore_IndexesAgain(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	categoryRepo := new(MockCategoryRepository)
+	uc := NewContentUseCase(contentRepo, categoryRepo, new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	contentRepo.On("GetTrashed", mock.Anything, "source").Return(&content.Content{ID: "source", Title: "Hello world"}, nil)
+	contentRepo.On("Restore", mock.Anything, "source").Return(nil)
+	contentRepo.On("ListTranslations", mock.Anything, []string{"source"}).Return([]*content.Content{{ID: "translation", Title: "Halo world", TranslationOf: "source"}}, nil)
+	categoryRepo.On("ListByContent", mock.Anything, mock.Anything).Return([]*category.Category{}, nil)
+
+	require.NoError(t, uc.Restore(context.Background(), "source"))
+
+	result, err := uc.Search(context.Background(), content.SearchQuery{Text: "world", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+}
+
+func TestContentUseCase_Restore_TranslationOfTrashedSource(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	uc := NewContentUseCase(contentRepo, new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	contentRepo.On("GetTrashed", mock.Anything, "translation").Return(&content.Content{ID: "translation", TranslationOf: "source"}, nil)
+	contentRepo.On("GetByID", mock.Anything, "source").Return(nil, content.ErrNotFound)
+
+	err := uc.Restore(context.Background(), "translation")
+	assert.ErrorIs(t, err, ErrInvalidTranslation)
+	contentRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+}
+
+func TestContentUseCase_Search_EmptyQuery(t *testing.T) {
+	uc := NewContentUseCase(new(MockContentRepository), new(MockCategoryRepository), new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	_, err := uc.Search(context.Background(), content.SearchQuery{Text: " ? "})
+	assert.ErrorIs(t, err, ErrEmptyQuery)
+}
+
+func TestContentUseCase_Reindex(t *testing.T) {
+	contentRepo := new(MockContentRepository)
+	categoryRepo := new(MockCategoryRepository)
+	uc := NewContentUseCase(contentRepo, categoryRepo, new(MockTagRepository), new(MockRedirectRepository), testPermalinks, testBlocks, testLocales, memory.NewIndex(), nil)
+
+	contentRepo.On("List", mock.Anything, content.ListFilter{Limit: reindexBatchSize}).Return([]*content.Content{
+		{ID: "a", Type: "post", Title: "Search engines"},
+		{ID: "b", Type: "page", Title: "About"},
+	}, nil)
+	categoryRepo.On("ListByContent", mock.Anything, "a").Return([]*category.Category{{ID: "tech"}}, nil)
+	categoryRepo.On("ListByContent", mock.Anything, "b").Return([]*category.Category{}, nil)
+
+	indexed, err := uc.Reindex(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, indexed)
+
+	result, err := uc.Search(context.Background(), content.SearchQuery{Text: "search", CategoryID: "tech", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+}
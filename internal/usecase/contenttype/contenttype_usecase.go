@@ -0,0 +1,588 @@
+package contenttype
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+)
+
+var (
+	// ErrTypeInUse is returned when deleting a content type that still has entries, in the trash or not.
+	ErrTypeInUse = errors.New("content type still has entries")
+	// ErrInvalidStatus is returned when an entry carries an unknown status.
+	ErrInvalidStatus = errors.New("invalid entry status")
+	// ErrFieldNotIndexed is returned when filtering or sorting entries by a field without an index.
+	ErrFieldNotIndexed = errors.New("field is not indexed")
+	// ErrReferenced is returned when deleting an entry that a restricting reference still points at.
+	ErrReferenced = errors.New("entry is referenced by other entries")
+	// ErrInvalidInclude is returned for an include path that does not follow reference fields.
+	ErrInvalidInclude = errors.New("invalid include")
+)
+
+// MaxIncludeDepth bounds how many reference hops an include path may follow.
+const MaxIncludeDepth = 3
+
+// ListQuery describes an entry listing in request terms. Filters maps indexed field names to the
+// value they must equal; Sort names an indexed field, prefixed with "-" for descending order.
+// Viewer, when set, leaves out the entries the viewer may not read.
+type ListQuery struct {
+	Status  string
+	Filters map[string]string
+	Sort    string
+	Limit   int
+	Offset  int
+	Viewer  *content.Viewer
+}
+
+type UseCase interface {
+	CreateType(ctx context.Context, ct *contenttype.ContentType) error
+	GetType(ctx context.Context, name string) (*contenttype.ContentType, error)
+	UpdateType(ctx context.Context, ct *contenttype.ContentType) error
+	// DeleteType removes a content type that has no entries left, trashed ones included.
+	DeleteType(ctx context.Context, name string) error
+	ListTypes(ctx context.Context) ([]*contenttype.ContentType, error)
+
+	CreateEntry(ctx context.Context, e *contenttype.Entry) error
+	GetEntry(ctx context.Context, contentType, id string) (*contenttype.Entry, error)
+	// GetPublishedEntry returns a published entry that viewer may read.
+	GetPublishedEntry(ctx context.Context, contentType, id string, viewer content.Viewer) (*contenttype.Entry, error)
+	UpdateEntry(ctx context.Context, e *contenttype.Entry) error
+	// DeleteEntry moves an entry to the trash after applying the on_delete rule of every reference
+	// pointing at it: restricting references abort the delete, cascading ones trash the referencing
+	// entry too and set_null ones remove the reference from the referencing entry.
+	DeleteEntry(ctx context.Context, contentType, id string) error
+	ListEntries(ctx context.Context, contentType string, query ListQuery) ([]*contenttype.Entry, error)
+	// ListReferences returns the references pointing at an entry.
+	ListReferences(ctx context.Context, contentType, id string) ([]contenttype.Reference, error)
+	// IncludeReferences embeds referenced entries into entries following include paths such as
+	// "author" or "related.author". With a viewer, unpublished entries and the entries the viewer may
+	// not read are left out; a nil viewer includes everything.
+	IncludeReferences(ctx context.Context, entries []*contenttype.Entry, include []string, viewer *content.Viewer) error
+	// The trash of entries of every type. Restored entries record their references again.
+	trash.Bin
+}
+
+type contentTypeUseCase struct {
+	typeRepo  contenttype.Repository
+	entryRepo contenttype.EntryRepository
+	refRepo   contenttype.ReferenceRepository
+}
+
+func NewContentTypeUseCase(typeRepo contenttype.Repository, entryRepo contenttype.EntryRepository, refRepo contenttype.ReferenceRepository) UseCase {
+	return &contentTypeUseCase{
+		typeRepo:  typeRepo,
+		entryRepo: entryRepo,
+		refRepo:   refRepo,
+	}
+}
+
+func (uc *contentTypeUseCase) CreateType(ctx context.Context, ct *contenttype.ContentType) error {
+	if err := uc.validateType(ctx, ct); err != nil {
+		return err
+	}
+	if err := uc.typeRepo.Create(ctx, ct); err != nil {
+		return err
+	}
+	return uc.entryRepo.SyncIndexes(ctx, ct)
+}
+
+func (uc *contentTypeUseCase) GetType(ctx context.Context, name string) (*contenttype.ContentType, error) {
+	return uc.typeRepo.GetByName(ctx, name)
+}
+
+func (uc *contentTypeUseCase) UpdateType(ctx context.Context, ct *contenttype.ContentType) error {
+	existing, err := uc.typeRepo.GetByName(ctx, ct.Name)
+	if err != nil {
+		return err
+	}
+	if ct.Version, err = lock.Expect(ct.Version, existing.Version); err != nil {
+		return err
+	}
+	if err := uc.validateType(ctx, ct); err != nil {
+		return err
+	}
+	if err := uc.typeRepo.Update(ctx, ct); err != nil {
+		return err
+	}
+	return uc.entryRepo.SyncIndexes(ctx, ct)
+}
+
+func (uc *contentTypeUseCase) DeleteType(ctx context.Context, name string) error {
+	count, err := uc.entryRepo.Count(ctx, name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrTypeInUse
+	}
+	types, err := uc.typeRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range types {
+		if other.Name != name && slices.Contains(referenceTargets(other.Fields), name) {
+			return fmt.Errorf("%w: referenced by %s", ErrTypeInUse, other.Name)
+		}
+	}
+	if err := uc.typeRepo.Delete(ctx, name); err != nil {
+		return err
+	}
+	// Without indexed fields every generated column of the type is dropped.
+	return uc.entryRepo.SyncIndexes(ctx, &contenttype.ContentType{Name: name})
+}
+
+func (uc *contentTypeUseCase) ListTypes(ctx context.Context) ([]*contenttype.ContentType, error) {
+	return uc.typeRepo.List(ctx)
+}
+
+func (uc *contentTypeUseCase) CreateEntry(ctx context.Context, e *contenttype.Entry) error {
+	ct, err := uc.typeRepo.GetByName(ctx, e.Type)
+	if err != nil {
+		return err
+	}
+	if e.Status == "" {
+		e.Status = contenttype.StatusDraft
+	}
+	if err := uc.prepareEntry(ctx, ct, e); err != nil {
+		return err
+	}
+	if err := uc.entryRepo.Create(ctx, e); err != nil {
+		return err
+	}
+	return uc.refRepo.Replace(ctx, e.ID, ct.References(e))
+}
+
+func (uc *contentTypeUseCase) GetEntry(ctx context.Context, contentType, id string) (*contenttype.Entry, error) {
+	return uc.entryRepo.GetByID(ctx, contentType, id)
+}
+
+func (uc *contentTypeUseCase) GetPublishedEntry(ctx context.Context, contentType, id string, viewer content.Viewer) (*contenttype.Entry, error) {
+	e, err := uc.entryRepo.GetByID(ctx, contentType, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != contenttype.StatusPublished || !e.Admits(viewer) {
+		return nil, contenttype.ErrEntryNotFound
+	}
+	return e, nil
+}
+
+func (uc *contentTypeUseCase) UpdateEntry(ctx context.Context, e *contenttype.Entry) error {
+	ct, err := uc.typeRepo.GetByName(ctx, e.Type)
+	if err != nil {
+		return err
+	}
+	previous, err := uc.entryRepo.GetByID(ctx, e.Type, e.ID)
+	if err != nil {
+		return err
+	}
+	if e.Version, err = lock.Expect(e.Version, previous.Version); err != nil {
+		return err
+	}
+	e.AuthorID = previous.AuthorID
+	e.CreatedAt = previous.CreatedAt
+	if e.Status == "" {
+		e.Status = previous.Status
+	}
+	// Restricted entries stay restricted unless the editor says otherwise, and keep their password
+	// until a new one is set.
+	if e.Visibility == "" {
+		e.Visibility = previous.Visibility
+		if e.AllowedRoles == nil {
+			e.AllowedRoles = previous.AllowedRoles
+		}
+	}
+	if e.Password == "" {
+		e.PasswordHash = previous.PasswordHash
+	}
+	if err := uc.prepareEntry(ctx, ct, e); err != nil {
+		return err
+	}
+	if err := uc.entryRepo.Update(ctx, e); err != nil {
+		return err
+	}
+	return uc.refRepo.Replace(ctx, e.ID, ct.References(e))
+}
+
+func (uc *contentTypeUseCase) DeleteEntry(ctx context.Context, contentType, id string) error {
+	if _, err := uc.entryRepo.GetByID(ctx, contentType, id); err != nil {
+		return err
+	}
+
+	plan := &deletePlan{doomed: map[string]string{}, types: map[string]*contenttype.ContentType{}}
+	if err := uc.planDelete(ctx, plan, contentType, id); err != nil {
+		return err
+	}
+	for _, ref := range plan.restricted {
+		if _, ok := plan.doomed[ref.SourceID]; !ok {
+			return fmt.Errorf("%w: %s %s links here via %s", ErrReferenced, ref.SourceType, ref.SourceID, ref.Field)
+		}
+	}
+
+	for _, ref := range plan.cleared {
+		if _, ok := plan.doomed[ref.SourceID]; ok {
+			continue
+		}
+		if err := uc.clearReference(ctx, plan.types[ref.SourceType], ref); err != nil {
+			return err
+		}
+	}
+	for _, doomed := range plan.order {
+		if err := uc.refRepo.DeleteBySource(ctx, doomed); err != nil {
+			return err
+		}
+		if err := uc.entryRepo.Delete(ctx, plan.doomed[doomed], doomed); err != nil && !errors.Is(err, contenttype.ErrEntryNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *contentTypeUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	return uc.entryRepo.ListTrashed(ctx, filter)
+}
+
+func (uc *contentTypeUseCase) Restore(ctx context.Context, id string) error {
+	e, err := uc.entryRepo.GetTrashed(ctx, id)
+	if err != nil {
+		return err
+	}
+	ct, err := uc.typeRepo.GetByName(ctx, e.Type)
+	if err != nil {
+		return err
+	}
+	if err := uc.entryRepo.Restore(ctx, id); err != nil {
+		return err
+	}
+	return uc.refRepo.Replace(ctx, e.ID, ct.References(e))
+}
+
+func (uc *contentTypeUseCase) Purge(ctx context.Context, id string) error {
+	return uc.entryRepo.Purge(ctx, id)
+}
+
+func (uc *contentTypeUseCase) ListReferences(ctx context.Context, contentType, id string) ([]contenttype.Reference, error) {
+	if _, err := uc.entryRepo.GetByID(ctx, contentType, id); err != nil {
+		return nil, err
+	}
+	return uc.refRepo.ListByTarget(ctx, id)
+}
+
+func (uc *contentTypeUseCase) IncludeReferences(ctx context.Context, entries []*contenttype.Entry, include []string, viewer *content.Viewer) error {
+	tree := includeTree{}
+	for _, path := range include {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		segments := strings.Split(path, ".")
+		if len(segments) > MaxIncludeDepth {
+			return fmt.Errorf("%w: %s follows more than %d references", ErrInvalidInclude, path, MaxIncludeDepth)
+		}
+		tree.add(segments)
+	}
+	if len(tree) == 0 {
+		return nil
+	}
+
+	loader := &includeLoader{
+		uc:      uc,
+		viewer:  viewer,
+		types:   map[string]*contenttype.ContentType{},
+		entries: map[string]*contenttype.Entry{},
+	}
+	return loader.expand(ctx, entries, tree)
+}
+
+func (uc *contentTypeUseCase) ListEntries(ctx context.Context, contentType string, query ListQuery) ([]*contenttype.Entry, error) {
+	ct, err := uc.typeRepo.GetByName(ctx, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := contenttype.EntryFilter{Status: query.Status, Viewer: query.Viewer, Limit: query.Limit, Offset: query.Offset}
+	for name, raw := range query.Filters {
+		field, err := indexedField(ct, name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := field.ParseFilterValue(raw)
+		if err != nil {
+			return nil, err
+		}
+		filter.Conditions = append(filter.Conditions, contenttype.Condition{Field: field, Value: value})
+	}
+	if query.Sort != "" {
+		name := strings.TrimPrefix(query.Sort, "-")
+		field, err := indexedField(ct, name)
+		if err != nil {
+			return nil, err
+		}
+		filter.Sort = &field
+		filter.Descending = name != query.Sort
+	} else {
+		filter.Descending = true
+	}
+
+	return uc.entryRepo.List(ctx, contentType, filter)
+}
+
+// validateType checks the definition and that every reference field targets an existing type.
+func (uc *contentTypeUseCase) validateType(ctx context.Context, ct *contenttype.ContentType) error {
+	if err := ct.ValidateDefinition(); err != nil {
+		return err
+	}
+	for _, target := range referenceTargets(ct.Fields) {
+		if target == ct.Name {
+			continue
+		}
+		if _, err := uc.typeRepo.GetByName(ctx, target); err != nil {
+			if errors.Is(err, contenttype.ErrNotFound) {
+				return fmt.Errorf("%w: reference target %q does not exist", contenttype.ErrInvalidDefinition, target)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func referenceTargets(fields []contenttype.Field) []string {
+	var targets []string
+	for _, f := range fields {
+		if f.Type == contenttype.FieldReference && f.Target != "" {
+			targets = append(targets, f.Target)
+		}
+		targets = append(targets, referenceTargets(f.Fields)...)
+	}
+	return targets
+}
+
+func indexedField(ct *contenttype.ContentType, name string) (contenttype.Field, error) {
+	field, ok := ct.Field(name)
+	if !ok || !field.Indexed {
+		return contenttype.Field{}, fmt.Errorf("%w: %s", ErrFieldNotIndexed, name)
+	}
+	return field, nil
+}
+
+// prepareEntry validates the status and visibility, replaces the entry data with its normalized
+// form and checks that every referenced entry exists.
+func (uc *contentTypeUseCase) prepareEntry(ctx context.Context, ct *contenttype.ContentType, e *contenttype.Entry) error {
+	switch e.Status {
+	case contenttype.StatusDraft, contenttype.StatusPublished:
+	default:
+		return ErrInvalidStatus
+	}
+	access := e.Access()
+	if err := access.Prepare(e.Password); err != nil {
+		return err
+	}
+	e.SetAccess(access)
+	e.Password = ""
+
+	data, err := ct.Validate(e.Data)
+	if err != nil {
+		return err
+	}
+	e.Data = data
+
+	problems := make(map[string]string)
+	for _, f := range ct.ReferenceFields() {
+		for _, id := range e.ReferencedIDs(f) {
+			if f.Target == e.Type && id == e.ID {
+				continue
+			}
+			_, err := uc.entryRepo.GetByID(ctx, f.Target, id)
+			if errors.Is(err, contenttype.ErrEntryNotFound) {
+				problems[f.Name] = fmt.Sprintf("references missing %s entry %s", f.Target, id)
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &contenttype.ValidationError{Fields: problems}
+	}
+	return nil
+}
+
+// deletePlan collects everything a delete touches before anything is changed, so a restricting
+// reference found deep in a cascade aborts the whole delete.
+type deletePlan struct {
+	doomed     map[string]string
+	order      []string
+	restricted []contenttype.Reference
+	cleared    []contenttype.Reference
+	types      map[string]*contenttype.ContentType
+}
+
+func (uc *contentTypeUseCase) planDelete(ctx context.Context, plan *deletePlan, contentType, id string) error {
+	plan.doomed[id] = contentType
+	plan.order = append(plan.order, id)
+
+	refs, err := uc.refRepo.ListByTarget(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if _, ok := plan.doomed[ref.SourceID]; ok {
+			continue
+		}
+		source, ok := plan.types[ref.SourceType]
+		if !ok {
+			if source, err = uc.typeRepo.GetByName(ctx, ref.SourceType); err != nil {
+				return err
+			}
+			plan.types[ref.SourceType] = source
+		}
+
+		field, _ := source.Field(ref.Field)
+		switch field.OnDelete {
+		case contenttype.OnDeleteCascade:
+			if err := uc.planDelete(ctx, plan, ref.SourceType, ref.SourceID); err != nil {
+				return err
+			}
+		case contenttype.OnDeleteSetNull:
+			plan.cleared = append(plan.cleared, ref)
+		default:
+			plan.restricted = append(plan.restricted, ref)
+		}
+	}
+	return nil
+}
+
+// clearReference removes the target of ref from the referencing entry.
+func (uc *contentTypeUseCase) clearReference(ctx context.Context, ct *contenttype.ContentType, ref contenttype.Reference) error {
+	e, err := uc.entryRepo.GetByID(ctx, ref.SourceType, ref.SourceID)
+	if err != nil {
+		return err
+	}
+	field, _ := ct.Field(ref.Field)
+	if field.Multiple {
+		remaining := make([]any, 0)
+		for _, id := range e.ReferencedIDs(field) {
+			if id != ref.TargetID {
+				remaining = append(remaining, id)
+			}
+		}
+		e.Data[field.Name] = remaining
+	} else {
+		delete(e.Data, field.Name)
+	}
+
+	if err := uc.entryRepo.Update(ctx, e); err != nil {
+		return err
+	}
+	return uc.refRepo.Replace(ctx, e.ID, ct.References(e))
+}
+
+// includeTree holds include paths as nested field names, e.g. "related.author" becomes
+// {"related": {"author": {}}}.
+type includeTree map[string]includeTree
+
+func (t includeTree) add(segments []string) {
+	child, ok := t[segments[0]]
+	if !ok {
+		child = includeTree{}
+		t[segments[0]] = child
+	}
+	if len(segments) > 1 {
+		child.add(segments[1:])
+	}
+}
+
+// includeLoader resolves include trees, loading every referenced entry at most once per request.
+type includeLoader struct {
+	uc      *contentTypeUseCase
+	viewer  *content.Viewer
+	types   map[string]*contenttype.ContentType
+	entries map[string]*contenttype.Entry
+}
+
+func (l *includeLoader) expand(ctx context.Context, entries []*contenttype.Entry, tree includeTree) error {
+	for _, e := range entries {
+		ct, err := l.contentType(ctx, e.Type)
+		if err != nil {
+			return err
+		}
+		for name, subtree := range tree {
+			field, ok := ct.Field(name)
+			if !ok || field.Type != contenttype.FieldReference {
+				return fmt.Errorf("%w: %s is not a reference field of %s", ErrInvalidInclude, name, ct.Name)
+			}
+
+			var included []*contenttype.Entry
+			for _, id := range e.ReferencedIDs(field) {
+				target, err := l.entry(ctx, field.Target, id)
+				if err != nil {
+					return err
+				}
+				if target != nil {
+					included = append(included, target)
+				}
+			}
+			if len(subtree) > 0 {
+				if err := l.expand(ctx, included, subtree); err != nil {
+					return err
+				}
+			}
+
+			if e.Included == nil {
+				e.Included = make(map[string]any)
+			}
+			switch {
+			case field.Multiple:
+				e.Included[name] = included
+			case len(included) == 1:
+				e.Included[name] = included[0]
+			default:
+				e.Included[name] = nil
+			}
+		}
+	}
+	return nil
+}
+
+func (l *includeLoader) contentType(ctx context.Context, name string) (*contenttype.ContentType, error) {
+	if ct, ok := l.types[name]; ok {
+		return ct, nil
+	}
+	ct, err := l.uc.typeRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	l.types[name] = ct
+	return ct, nil
+}
+
+// entry returns a fresh copy of a referenced entry, or nil when it is missing or hidden. The
+// password a viewer sent unlocks the requested entry only, never the entries it references. Copies keep
+// entries that are included in several places from sharing, and cycling through, Included maps.
+func (l *includeLoader) entry(ctx context.Context, contentType, id string) (*contenttype.Entry, error) {
+	cached, ok := l.entries[id]
+	if !ok {
+		e, err := l.uc.entryRepo.GetByID(ctx, contentType, id)
+		if err != nil && !errors.Is(err, contenttype.ErrEntryNotFound) {
+			return nil, err
+		}
+		if e != nil && l.viewer != nil && (e.Status != contenttype.StatusPublished || !e.Admits(l.viewer.WithoutPassword())) {
+			e = nil
+		}
+		l.entries[id] = e
+		cached = e
+	}
+	if cached == nil {
+		return nil, nil
+	}
+	copied := *cached
+	copied.Included = nil
+	return &copied, nil
+}
@@ -0,0 +1,426 @@
+package contenttype
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/contenttype"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTypeRepository struct {
+	mock.Mock
+}
+
+func (m *MockTypeRepository) Create(ctx context.Context, ct *contenttype.ContentType) error {
+	return m.Called(ctx, ct).Error(0)
+}
+
+func (m *MockTypeRepository) GetByName(ctx context.Context, name string) (*contenttype.ContentType, error) {
+	args := m.Called(ctx, name)
+	ct, _ := args.Get(0).(*contenttype.ContentType)
+	return ct, args.Error(1)
+}
+
+func (m *MockTypeRepository) Update(ctx context.Context, ct *contenttype.ContentType) error {
+	return m.Called(ctx, ct).Error(0)
+}
+
+func (m *MockTypeRepository) Delete(ctx context.Context, name string) error {
+	return m.Called(ctx, name).Error(0)
+}
+
+func (m *MockTypeRepository) List(ctx context.Context) ([]*contenttype.ContentType, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*contenttype.ContentType), args.Error(1)
+}
+
+type MockEntryRepository struct {
+	mock.Mock
+}
+
+func (m *MockEntryRepository) Create(ctx context.Context, e *contenttype.Entry) error {
+	return m.Called(ctx, e).Error(0)
+}
+
+func (m *MockEntryRepository) GetByID(ctx context.Context, contentType, id string) (*contenttype.Entry, error) {
+	args := m.Called(ctx, contentType, id)
+	e, _ := args.Get(0).(*contenttype.Entry)
+	return e, args.Error(1)
+}
+
+func (m *MockEntryRepository) Update(ctx context.Context, e *contenttype.Entry) error {
+	return m.Called(ctx, e).Error(0)
+}
+
+func (m *MockEntryRepository) Delete(ctx context.Context, contentType, id string) error {
+	return m.Called(ctx, contentType, id).Error(0)
+}
+
+func (m *MockEntryRepository) List(ctx context.Context, contentType string, filter contenttype.EntryFilter) ([]*contenttype.Entry, error) {
+	args := m.Called(ctx, contentType, filter)
+	return args.Get(0).([]*contenttype.Entry), args.Error(1)
+}
+
+func (m *MockEntryRepository) Count(ctx context.Context, contentType string) (int, error) {
+	args := m.Called(ctx, contentType)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockEntryRepository) SyncIndexes(ctx context.Context, ct *contenttype.ContentType) error {
+	return m.Called(ctx, ct).Error(0)
+}
+
+func (m *MockEntryRepository) GetTrashed(ctx context.Context, id string) (*contenttype.Entry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*contenttype.Entry), args.Error(1)
+}
+
+func (m *MockEntryRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockEntryRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEntryRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockReferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockReferenceRepository) Replace(ctx context.Context, sourceID string, refs []contenttype.Reference) error {
+	return m.Called(ctx, sourceID, refs).Error(0)
+}
+
+func (m *MockReferenceRepository) ListByTarget(ctx context.Context, targetID string) ([]contenttype.Reference, error) {
+	args := m.Called(ctx, targetID)
+	refs, _ := args.Get(0).([]contenttype.Reference)
+	return refs, args.Error(1)
+}
+
+func (m *MockReferenceRepository) DeleteBySource(ctx context.Context, sourceID string) error {
+	return m.Called(ctx, sourceID).Error(0)
+}
+
+func productType() *contenttype.ContentType {
+	return &contenttype.ContentType{
+		Name: "product",
+		Fields: []contenttype.Field{
+			{Name: "title", Type: contenttype.FieldText, Required: true},
+			{Name: "price", Type: contenttype.FieldNumber, Indexed: true},
+			{Name: "brand", Type: contenttype.FieldReference, Target: "brand"},
+		},
+	}
+}
+
+func TestContentTypeUseCase_CreateType_SyncsIndexes(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	entryRepo := new(MockEntryRepository)
+	uc := NewContentTypeUseCase(typeRepo, entryRepo, new(MockReferenceRepository))
+
+	ct := productType()
+	typeRepo.On("GetByName", mock.Anything, "brand").Return(&contenttype.ContentType{Name: "brand"}, nil)
+	typeRepo.On("Create", mock.Anything, ct).Return(nil)
+	entryRepo.On("SyncIndexes", mock.Anything, ct).Return(nil)
+
+	require.NoError(t, uc.CreateType(context.Background(), ct))
+	typeRepo.AssertExpectations(t)
+	entryRepo.AssertExpectations(t)
+}
+
+func TestContentTypeUseCase_CreateType_UnknownReferenceTarget(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	uc := NewContentTypeUseCase(typeRepo, new(MockEntryRepository), new(MockReferenceRepository))
+
+	typeRepo.On("GetByName", mock.Anything, "brand").Return(nil, contenttype.ErrNotFound)
+
+	err := uc.CreateType(context.Background(), productType())
+	assert.ErrorIs(t, err, contenttype.ErrInvalidDefinition)
+	typeRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestContentTypeUseCase_DeleteType_InUse(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	entryRepo := new(MockEntryRepository)
+	uc := NewContentTypeUseCase(typeRepo, entryRepo, new(MockReferenceRepository))
+
+	entryRepo.On("Count", mock.Anything, "product").Return(3, nil)
+
+	assert.ErrorIs(t, uc.DeleteType(context.Background(), "product"), ErrTypeInUse)
+	typeRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestContentTypeUseCase_CreateEntry_Validates(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	entryRepo := new(MockEntryRepository)
+	refRepo := new(MockReferenceRepository)
+	uc := NewContentTypeUseCase(typeRepo, entryRepo, refRepo)
+
+	typeRepo.On("GetByName", mock.Anything, "product").Return(productType(), nil)
+	entryRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *contenttype.Entry) bool {
+		return e.Status == contenttype.StatusDraft && e.Data["title"] == "Lamp"
+	})).Return(nil)
+	refRepo.On("Replace", mock.Anything, mock.Anything, []contenttype.Reference(nil)).Return(nil)
+
+	err := uc.CreateEntry(context.Background(), &contenttype.Entry{Type: "product", Data: map[string]any{"title": "Lamp", "price": 10.0}})
+	require.NoError(t, err)
+
+	err = uc.CreateEntry(context.Background(), &contenttype.Entry{Type: "product", Data: map[string]any{"price": "free"}})
+	assert.ErrorIs(t, err, contenttype.ErrInvalidEntry)
+
+	err = uc.CreateEntry(context.Background(), &contenttype.Entry{Type: "product", Status: "hidden", Data: map[string]any{"title": "Lamp"}})
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+	entryRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestContentTypeUseCase_UpdateEntry_KeepsAuthorAndStatus(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	entryRepo := new(MockEntryRepository)
+	refRepo := new(MockReferenceRepository)
+	uc := NewContentTypeUseCase(typeRepo, entryRepo, refRepo)
+
+	typeRepo.On("GetByName", mock.Anything, "product").Return(productType(), nil)
+	entryRepo.On("GetByID", mock.Anything, "product", "entry-1").Return(&contenttype.Entry{
+		ID: "entry-1", Type: "product", Status: contenttype.StatusPublished, AuthorID: "user-1",
+		Visibility: content.VisibilityRoles, AllowedRoles: []string{"editor"},
+	}, nil)
+	entryRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	refRepo.On("Replace", mock.Anything, "entry-1", []contenttype.Reference(nil)).Return(nil)
+
+	e := &contenttype.Entry{ID: "entry-1", Type: "product", Data: map[string]any{"title": "Lamp"}}
+	require.NoError(t, uc.UpdateEntry(context.Background(), e))
+	assert.Equal(t, contenttype.StatusPublished, e.Status)
+	assert.Equal(t, "user-1", e.AuthorID)
+	assert.Equal(t, content.VisibilityRoles, e.Visibility)
+	assert.Equal(t, []string{"editor"}, e.AllowedRoles)
+}
+
+func TestContentTypeUseCase_CreateEntry_InvalidVisibility(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	uc := NewContentTypeUseCase(typeRepo, new(MockEntryRepository), new(MockReferenceRepository))
+
+	typeRepo.On("GetByName", mock.Anything, "product").Return(productType(), nil)
+
+	e := &contenttype.Entry{Type: "product", Visibility: content.VisibilityRoles, Data: map[string]any{"title": "Lamp"}}
+	assert.ErrorIs(t, uc.CreateEntry(context.Background(), e), content.ErrInvalidVisibility)
+}
+
+func TestContentTypeUseCase_GetPublishedEntry_HidesDrafts(t *testing.T) {
+	entryRepo := new(MockEntryRepository)
+	uc := NewContentTypeUseCase(new(MockTypeRepository), entryRepo, new(MockReferenceRepository))
+
+	entryRepo.On("GetByID", mock.Anything, "product", "entry-1").Return(&contenttype.Entry{ID: "entry-1", Status: contenttype.StatusDraft}, nil)
+
+	_, err := uc.GetPublishedEntry(context.Background(), "product", "entry-1", content.Viewer{})
+	assert.ErrorIs(t, err, contenttype.ErrEntryNotFound)
+}
+
+func TestContentTypeUseCase_GetPublishedEntry_HidesRestricted(t *testing.T) {
+	entryRepo := new(MockEntryRepository)
+	uc := NewContentTypeUseCase(new(MockTypeRepository), entryRepo, new(MockReferenceRepository))
+
+	entryRepo.On("GetByID", mock.Anything, "product", "entry-1").Return(&contenttype.Entry{
+		ID: "entry-1", Status: contenttype.StatusPublished, Visibility: content.VisibilityAuthenticated,
+	}, nil)
+
+	_, err := uc.GetPublishedEntry(context.Background(), "product", "entry-1", content.Viewer{})
+	assert.ErrorIs(t, err, contenttype.ErrEntryNotFound)
+
+	e, err := uc.GetPublishedEntry(context.Background(), "product", "entry-1", content.Viewer{UserID: "user-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "entry-1", e.ID)
+}
+
+func TestContentTypeUseCase_ListEntries_BuildsFilter(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	entryRepo := new(MockEntryRepository)
+	uc := NewContentTypeUseCase(typeRepo, entryRepo, new(MockReferenceRepository))
+
+	ct := productType()
+	price, _ := ct.Field("price")
+	typeRepo.On("GetByName", mock.Anything, "product").Return(ct, nil)
+	entryRepo.On("List", mock.Anything, "product", contenttype.EntryFilter{
+		Status:     contenttype.StatusPublished,
+		Conditions: []contenttype.Condition{{Field: price, Value: 10.0}},
+		Sort:       &price,
+		Descending: true,
+		Limit:      10,
+	}).Return([]*contenttype.Entry{{ID: "entry-1"}}, nil)
+
+	entries, err := uc.ListEntries(context.Background(), "product", ListQuery{
+		Status:  contenttype.StatusPublished,
+		Filters: map[string]string{"price": "10"},
+		Sort:    "-price",
+		Limit:   10,
+	})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	_, err = uc.ListEntries(context.Background(), "product", ListQuery{Filters: map[string]string{"title": "Lamp"}})
+	assert.ErrorIs(t, err, ErrFieldNotIndexed)
+}
+
+func TestContentTypeUseCase_CreateEntry_ChecksReferences(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	entryRepo := new(MockEntryRepository)
+	refRepo := new(MockReferenceRepository)
+	uc := NewContentTypeUseCase(typeRepo, entryRepo, refRepo)
+
+	typeRepo.On("GetByName", mock.Anything, "product").Return(productType(), nil)
+	entryRepo.On("GetByID", mock.Anything, "brand", "brand-1").Return(&contenttype.Entry{ID: "brand-1", Type: "brand"}, nil)
+	entryRepo.On("GetByID", mock.Anything, "brand", "brand-2").Return(nil, contenttype.ErrEntryNotFound)
+	entryRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*contenttype.Entry).ID = "entry-1"
+	}).Return(nil)
+	refRepo.On("Replace", mock.Anything, "entry-1", []contenttype.Reference{
+		{SourceType: "product", SourceID: "entry-1", Field: "brand", TargetType: "brand", TargetID: "brand-1"},
+	}).Return(nil)
+
+	err := uc.CreateEntry(context.Background(), &contenttype.Entry{Type: "product", Data: map[string]any{"title": "Lamp", "brand": "brand-1"}})
+	require.NoError(t, err)
+
+	err = uc.CreateEntry(context.Background(), &contenttype.Entry{Type: "product", Data: map[string]any{"title": "Lamp", "brand": "brand-2"}})
+	var verr *contenttype.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "references missing brand entry brand-2", verr.Fields["brand"])
+	entryRepo.AssertNumberOfCalls(t, "Create", 1)
+	refRepo.AssertExpectations(t)
+}
+
+func TestContentTypeUseCase_DeleteEntry_Restricted(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	entryRepo := new(MockEntryRepository)
+	refRepo := new(MockReferenceRepository)
+	uc := NewContentTypeUseCase(typeRepo, entryRepo, refRepo)
+
+	entryRepo.On("GetByID", mock.Anything, "brand", "brand-1").Return(&contenttype.Entry{ID: "brand-1", Type: "brand"}, nil)
+	refRepo.On("ListByTarget", mock.Anything, "brand-1").Return([]contenttype.Reference{
+		{SourceType: "product", SourceID: "entry-1", Field: "brand", TargetType: "brand", TargetID: "brand-1"},
+	}, nil)
+	typeRepo.On("GetByName", mock.Anything, "product").Return(productType(), nil)
+
+	assert.ErrorIs(t, uc.DeleteEntry(context.Background(), "brand", "brand-1"), ErrReferenced)
+	entryRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestContentTypeUseCase_DeleteEntry_AppliesRules(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	entryRepo := new(MockEntryRepository)
+	refRepo := new(MockReferenceRepository)
+	uc := NewContentTypeUseCase(typeRepo, entryRepo, refRepo)
+
+	product := &contenttype.ContentType{Name: "product", Fields: []contenttype.Field{
+		{Name: "brand", Type: contenttype.FieldReference, Target: "brand", OnDelete: contenttype.OnDeleteCascade},
+	}}
+	review := &contenttype.ContentType{Name: "review", Fields: []contenttype.Field{
+		{Name: "products", Type: contenttype.FieldReference, Target: "product", Multiple: true, OnDelete: contenttype.OnDeleteSetNull},
+	}}
+	typeRepo.On("GetByName", mock.Anything, "product").Return(product, nil)
+	typeRepo.On("GetByName", mock.Anything, "review").Return(review, nil)
+
+	entryRepo.On("GetByID", mock.Anything, "brand", "brand-1").Return(&contenttype.Entry{ID: "brand-1", Type: "brand"}, nil)
+	entryRepo.On("GetByID", mock.Anything, "review", "review-1").Return(&contenttype.Entry{
+		ID: "review-1", Type: "review", Data: map[string]any{"products": []any{"entry-1", "entry-2"}},
+	}, nil)
+	refRepo.On("ListByTarget", mock.Anything, "brand-1").Return([]contenttype.Reference{
+		{SourceType: "product", SourceID: "entry-1", Field: "brand", TargetType: "brand", TargetID: "brand-1"},
+	}, nil)
+	refRepo.On("ListByTarget", mock.Anything, "entry-1").Return([]contenttype.Reference{
+		{SourceType: "review", SourceID: "review-1", Field: "products", TargetType: "product", TargetID: "entry-1"},
+	}, nil)
+
+	entryRepo.On("Update", mock.Anything, mock.MatchedBy(func(e *contenttype.Entry) bool {
+		return e.ID == "review-1" && assert.ObjectsAreEqual([]any{"entry-2"}, e.Data["products"])
+	})).Return(nil)
+	refRepo.On("Replace", mock.Anything, "review-1", []contenttype.Reference{
+		{SourceType: "review", SourceID: "review-1", Field: "products", TargetType: "product", TargetID: "entry-2"},
+	}).Return(nil)
+	refRepo.On("DeleteBySource", mock.Anything, mock.Anything).Return(nil)
+	entryRepo.On("Delete", mock.Anything, "brand", "brand-1").Return(nil)
+	entryRepo.On("Delete", mock.Anything, "product", "entry-1").Return(nil)
+
+	require.NoError(t, uc.DeleteEntry(context.Background(), "brand", "brand-1"))
+	entryRepo.AssertExpectations(t)
+	refRepo.AssertExpectations(t)
+}
+
+func TestContentTypeUseCase_Restore_RecordsReferences(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	entryRepo := new(MockEntryRepository)
+	refRepo := new(MockReferenceRepository)
+	uc := NewContentTypeUseCase(typeRepo, entryRepo, refRepo)
+
+	entryRepo.On("GetTrashed", mock.Anything, "entry-1").Return(&contenttype.Entry{
+		ID: "entry-1", Type: "product", Data: map[string]any{"title": "Lamp", "brand": "brand-1"},
+	}, nil)
+	typeRepo.On("GetByName", mock.Anything, "product").Return(productType(), nil)
+	entryRepo.On("Restore", mock.Anything, "entry-1").Return(nil)
+	refRepo.On("Replace", mock.Anything, "entry-1", []contenttype.Reference{
+		{SourceType: "product", SourceID: "entry-1", Field: "brand", TargetType: "brand", TargetID: "brand-1"},
+	}).Return(nil)
+
+	require.NoError(t, uc.Restore(context.Background(), "entry-1"))
+	refRepo.AssertExpectations(t)
+}
+
+func TestContentTypeUseCase_Restore_NotTrashed(t *testing.T) {
+	entryRepo := new(MockEntryRepository)
+	uc := NewContentTypeUseCase(new(MockTypeRepository), entryRepo, new(MockReferenceRepository))
+
+	entryRepo.On("GetTrashed", mock.Anything, "entry-1").Return(nil, trash.ErrNotFound)
+
+	assert.ErrorIs(t, uc.Restore(context.Background(), "entry-1"), trash.ErrNotFound)
+	entryRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+}
+
+func TestContentTypeUseCase_IncludeReferences(t *testing.T) {
+	typeRepo := new(MockTypeRepository)
+	entryRepo := new(MockEntryRepository)
+	uc := NewContentTypeUseCase(typeRepo, entryRepo, new(MockReferenceRepository))
+
+	article := &contenttype.ContentType{Name: "article", Fields: []contenttype.Field{
+		{Name: "title", Type: contenttype.FieldText},
+		{Name: "author", Type: contenttype.FieldReference, Target: "person"},
+		{Name: "related", Type: contenttype.FieldReference, Target: "article", Multiple: true},
+	}}
+	typeRepo.On("GetByName", mock.Anything, "article").Return(article, nil)
+	typeRepo.On("GetByName", mock.Anything, "person").Return(&contenttype.ContentType{Name: "person"}, nil)
+	entryRepo.On("GetByID", mock.Anything, "article", "a-2").Return(&contenttype.Entry{
+		ID: "a-2", Type: "article", Status: contenttype.StatusPublished, Data: map[string]any{"author": "p-1"},
+	}, nil)
+	entryRepo.On("GetByID", mock.Anything, "article", "a-3").Return(&contenttype.Entry{ID: "a-3", Type: "article", Status: contenttype.StatusDraft}, nil)
+	entryRepo.On("GetByID", mock.Anything, "article", "a-4").Return(&contenttype.Entry{
+		ID: "a-4", Type: "article", Status: contenttype.StatusPublished, Visibility: content.VisibilityAuthenticated,
+	}, nil)
+	entryRepo.On("GetByID", mock.Anything, "person", "p-1").Return(&contenttype.Entry{ID: "p-1", Type: "person", Status: contenttype.StatusPublished}, nil)
+
+	e := &contenttype.Entry{ID: "a-1", Type: "article", Data: map[string]any{"author": "p-1", "related": []any{"a-2", "a-3", "a-4"}}}
+	require.NoError(t, uc.IncludeReferences(context.Background(), []*contenttype.Entry{e}, []string{"author", "related.author"}, &content.Viewer{}))
+
+	assert.Equal(t, "p-1", e.Included["author"].(*contenttype.Entry).ID)
+	related := e.Included["related"].([]*contenttype.Entry)
+	require.Len(t, related, 1)
+	assert.Equal(t, "p-1", related[0].Included["author"].(*contenttype.Entry).ID)
+
+	err := uc.IncludeReferences(context.Background(), []*contenttype.Entry{e}, []string{"title"}, &content.Viewer{})
+	assert.ErrorIs(t, err, ErrInvalidInclude)
+	err = uc.IncludeReferences(context.Background(), []*contenttype.Entry{e}, []string{"related.related.related.author"}, &content.Viewer{})
+	assert.ErrorIs(t, err, ErrInvalidInclude)
+}
@@ -0,0 +1,232 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+	"github.com/mashurimansur/goCMS/internal/utils/fulltext"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+	"github.com/mashurimansur/goCMS/internal/utils/syndication"
+)
+
+// ErrUnknownAuthor is returned when an author feed is requested for a username nobody has.
+var ErrUnknownAuthor = errors.New("author not found")
+
+// Defaults applied to zero Options fields.
+const (
+	DefaultSize        = 20
+	DefaultContentType = "post"
+	// maxSize keeps feeds small enough for readers polling them often.
+	maxSize = 100
+)
+
+// summaryLength is the length of summaries derived from the entry body.
+const summaryLength = 300
+
+// Options configures the feeds.
+type Options struct {
+	// SiteURL is the absolute URL of the public site, e.g. "https://example.com".
+	SiteURL     string
+	Title       string
+	Description string
+	// FullContent syndicates the rendered body of every entry instead of its summary only.
+	FullContent bool
+	// Size is the number of entries per feed.
+	Size int
+	// ContentType is the type of the syndicated entries.
+	ContentType string
+	// Locale restricts the feeds to entries written in that locale when set.
+	Locale string
+}
+
+type UseCase interface {
+	// Site returns the feed of the latest entries of the whole site.
+	Site(ctx context.Context) (*syndication.Feed, error)
+	// Category returns the feed of the category identified by slug, including its descendants.
+	Category(ctx context.Context, slug string) (*syndication.Feed, error)
+	Tag(ctx context.Context, slug string) (*syndication.Feed, error)
+	Author(ctx context.Context, username string) (*syndication.Feed, error)
+}
+
+type feedUseCase struct {
+	contentRepo  content.Repository
+	categoryRepo category.Repository
+	tagRepo      tag.Repository
+	userRepo     user.Repository
+	permalinks   permalink.Patterns
+	snippets     content.Snippets
+	opts         Options
+}
+
+func NewFeedUseCase(contentRepo content.Repository, categoryRepo category.Repository, tagRepo tag.Repository, userRepo user.Repository, permalinks permalink.Patterns, snippets content.Snippets, opts Options) UseCase {
+	opts.SiteURL = strings.TrimSuffix(opts.SiteURL, "/")
+	if opts.Title == "" {
+		// Feeds must have a title; the site URL is what readers would show anyway.
+		opts.Title = opts.SiteURL
+	}
+	if opts.Size <= 0 {
+		opts.Size = DefaultSize
+	}
+	if opts.Size > maxSize {
+		opts.Size = maxSize
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = DefaultContentType
+	}
+	return &feedUseCase{
+		contentRepo:  contentRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		userRepo:     userRepo,
+		permalinks:   permalinks,
+		snippets:     snippets,
+		opts:         opts,
+	}
+}
+
+func (uc *feedUseCase) Site(ctx context.Context) (*syndication.Feed, error) {
+	return uc.build(ctx, "", "", content.ListFilter{})
+}
+
+func (uc *feedUseCase) Category(ctx context.Context, slug string) (*syndication.Feed, error) {
+	root, err := uc.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	all, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.build(ctx, root.Name, "/feeds/category/"+root.Slug, content.ListFilter{
+		CategoryIDs: category.DescendantIDs(all, root.ID),
+	})
+}
+
+func (uc *feedUseCase) Tag(ctx context.Context, slug string) (*syndication.Feed, error) {
+	t, err := uc.tagRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return uc.build(ctx, t.Name, "/feeds/tag/"+t.Slug, content.ListFilter{TagID: t.ID})
+}
+
+func (uc *feedUseCase) Author(ctx context.Context, username string) (*syndication.Feed, error) {
+	u, err := uc.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUnknownAuthor
+	}
+	return uc.build(ctx, authorName(u), "/feeds/author/"+u.Username, content.ListFilter{AuthorID: u.ID})
+}
+
+// build lists the latest published entries matching filter. A non-empty scope is appended to the
+// site title and prefix is the path the scoped feed is served under.
+func (uc *feedUseCase) build(ctx context.Context, scope, prefix string, filter content.ListFilter) (*syndication.Feed, error) {
+	filter.Type = uc.opts.ContentType
+	filter.Locale = uc.opts.Locale
+	filter.Status = content.StatusPublished
+	filter.PublishedBefore = time.Now()
+	filter.Limit = uc.opts.Size
+	// Feed readers are anonymous: restricted entries are left out unless they are teasers, which are
+	// syndicated with their excerpt only.
+	filter.Viewer = &content.Viewer{}
+	entries, err := uc.contentRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range entries {
+		c.Restrict(*filter.Viewer)
+	}
+	if uc.snippets != nil {
+		if err := uc.snippets.Expand(ctx, entries...); err != nil {
+			return nil, err
+		}
+	}
+
+	f := &syndication.Feed{
+		Title:       uc.opts.Title,
+		Description: uc.opts.Description,
+		Link:        uc.opts.SiteURL + "/",
+		RSSURL:      uc.opts.SiteURL + prefix + "/feed.xml",
+		AtomURL:     uc.opts.SiteURL + prefix + "/atom.xml",
+		// An empty feed was never updated; the epoch marks its modification time as unknown.
+		Updated: time.Unix(0, 0).UTC(),
+		Items:   make([]syndication.Item, 0, len(entries)),
+	}
+	if scope != "" {
+		f.Title += " - " + scope
+	}
+
+	authors := map[string]string{}
+	for _, c := range entries {
+		item, err := uc.item(ctx, c, authors)
+		if err != nil {
+			return nil, err
+		}
+		if item.Updated.After(f.Updated) {
+			f.Updated = item.Updated
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f, nil
+}
+
+func (uc *feedUseCase) item(ctx context.Context, c *content.Content, authors map[string]string) (syndication.Item, error) {
+	item := syndication.Item{
+		ID:        "urn:uuid:" + c.ID,
+		Title:     c.Title,
+		Link:      uc.opts.SiteURL + uc.permalinks.Build(permalink.Fields{ID: c.ID, Type: c.Type, Slug: c.Slug, Locale: c.Locale, Date: c.PublishedAt}),
+		Summary:   c.Excerpt,
+		Published: c.PublishedAt,
+		Updated:   c.UpdatedAt,
+	}
+	if item.Summary == "" {
+		item.Summary = fulltext.Summary(c.BodyHTML, c.Body, summaryLength)
+	}
+	if uc.opts.FullContent {
+		item.Content = c.BodyHTML
+	}
+	if item.Updated.Before(item.Published) {
+		item.Updated = item.Published
+	}
+
+	if c.AuthorID != "" {
+		name, ok := authors[c.AuthorID]
+		if !ok {
+			u, err := uc.userRepo.GetByID(ctx, c.AuthorID)
+			if err != nil {
+				return item, err
+			}
+			if u != nil {
+				name = authorName(u)
+			}
+			authors[c.AuthorID] = name
+		}
+		item.Author = name
+	}
+
+	categories, err := uc.categoryRepo.ListByContent(ctx, c.ID)
+	if err != nil {
+		return item, err
+	}
+	for _, cat := range categories {
+		item.Categories = append(item.Categories, cat.Name)
+	}
+	return item, nil
+}
+
+func authorName(u *user.User) string {
+	if u.FullName != "" {
+		return u.FullName
+	}
+	return u.Username
+}
@@ -0,0 +1,352 @@
+package feed
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockContentRepository struct {
+	mock.Mock
+	content.Repository
+}
+
+func (m *MockContentRepository) List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+type MockCategoryRepository struct {
+	mock.Mock
+	category.Repository
+}
+
+func (m *MockCategoryRepository) GetBySlug(ctx context.Context, slug string) (*category.Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) List(ctx context.Context) ([]*category.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListByContent(ctx context.Context, contentID string) ([]*category.Category, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockTagRepository struct {
+	mock.Mock
+	tag.Repository
+}
+
+func (m *MockTagRepository) GetBySlug(ctx context.Context, slug string) (*tag.Tag, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTagRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockTagRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+	user.Repository
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+var (
+	testPermalinks = permalink.Patterns{"post": "/{year}/{month}/{slug}"}
+	testOptions    = Options{SiteURL: "https://example.com/", Title: "Example", Description: "News"}
+)
+
+type mocks struct {
+	content  *MockContentRepository
+	category *MockCategoryRepository
+	tag      *MockTagRepository
+	user     *MockUserRepository
+}
+
+func newTestUseCase(opts Options) (UseCase, mocks) {
+	m := mocks{
+		content:  new(MockContentRepository),
+		category: new(MockCategoryRepository),
+		tag:      new(MockTagRepository),
+		user:     new(MockUserRepository),
+	}
+	return NewFeedUseCase(m.content, m.category, m.tag, m.user, testPermalinks, nil, opts), m
+}
+
+func posts() []*content.Content {
+	published := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	return []*content.Content{
+		{
+			ID: "post-2", Type: "post", Title: "Second", Slug: "second", Excerpt: "The second post.",
+			BodyHTML: "<p>Second <b>body</b></p>", AuthorID: "user-1",
+			PublishedAt: published.Add(24 * time.Hour), UpdatedAt: published.Add(48 * time.Hour),
+		},
+		{
+			ID: "post-1", Type: "post", Title: "First", Slug: "first",
+			BodyHTML: "<p>" + strings.Repeat("word ", 100) + "</p>", AuthorID: "user-1",
+			PublishedAt: published, UpdatedAt: published.Add(-time.Hour),
+		},
+	}
+}
+
+func publishedFilter(filter content.ListFilter) interface{} {
+	return mock.MatchedBy(func(got content.ListFilter) bool {
+		filter.PublishedBefore = got.PublishedBefore
+		filter.Viewer = &content.Viewer{}
+		return !got.PublishedBefore.IsZero() && assert.ObjectsAreEqual(filter, got)
+	})
+}
+
+func TestFeedUseCase_Site(t *testing.T) {
+	uc, m := newTestUseCase(testOptions)
+
+	m.content.On("List", mock.Anything, publishedFilter(content.ListFilter{
+		Type: "post", Status: content.StatusPublished, Limit: DefaultSize,
+	})).Return(posts(), nil)
+	m.user.On("GetByID", mock.Anything, "user-1").Return(&user.User{ID: "user-1", Username: "ana", FullName: "Ana"}, nil).Once()
+	m.category.On("ListByContent", mock.Anything, "post-2").Return([]*category.Category{{Name: "News"}}, nil)
+	m.category.On("ListByContent", mock.Anything, "post-1").Return([]*category.Category{}, nil)
+
+	f, err := uc.Site(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Example", f.Title)
+	assert.Equal(t, "https://example.com/", f.Link)
+	assert.Equal(t, "https://example.com/feed.xml", f.RSSURL)
+	assert.Equal(t, "https://example.com/atom.xml", f.AtomURL)
+	assert.Equal(t, posts()[0].UpdatedAt, f.Updated)
+
+	require.Len(t, f.Items, 2)
+	assert.Equal(t, "urn:uuid:post-2", f.Items[0].ID)
+	assert.Equal(t, "https://example.com/2025/03/second", f.Items[0].Link)
+	assert.Equal(t, "Ana", f.Items[0].Author)
+	assert.Equal(t, []string{"News"}, f.Items[0].Categories)
+	assert.Equal(t, "The second post.", f.Items[0].Summary)
+	assert.Empty(t, f.Items[0].Content, "excerpt mode leaves the body out")
+
+	assert.True(t, strings.HasSuffix(f.Items[1].Summary, "…"))
+	assert.Equal(t, f.Items[1].Published, f.Items[1].Updated, "an entry is never updated before it was published")
+	m.user.AssertExpectations(t)
+}
+
+func TestFeedUseCase_Site_FullContent(t *testing.T) {
+	opts := testOptions
+	opts.FullContent = true
+	opts.Size = 1
+	opts.ContentType = "news"
+	uc, m := newTestUseCase(opts)
+
+	m.content.On("List", mock.Anything, publishedFilter(content.ListFilter{
+		Type: "news", Status: content.StatusPublished, Limit: 1,
+	})).Return(posts()[:1], nil)
+	m.user.On("GetByID", mock.Anything, "user-1").Return(nil, nil)
+	m.category.On("ListByContent", mock.Anything, "post-2").Return([]*category.Category{}, nil)
+
+	f, err := uc.Site(context.Background())
+	require.NoError(t, err)
+	require.Len(t, f.Items, 1)
+	assert.Equal(t, "<p>Second <b>body</b></p>", f.Items[0].Content)
+	assert.Empty(t, f.Items[0].Author)
+}
+
+func TestFeedUseCase_Site_LocksTeasers(t *testing.T) {
+	opts := testOptions
+	opts.FullContent = true
+	uc, m := newTestUseCase(opts)
+
+	entry := posts()[1]
+	entry.AuthorID = ""
+	entry.Visibility = content.VisibilityAuthenticated
+	entry.Teaser = true
+	m.content.On("List", mock.Anything, mock.Anything).Return([]*content.Content{entry}, nil)
+	m.category.On("ListByContent", mock.Anything, "post-1").Return([]*category.Category{}, nil)
+
+	f, err := uc.Site(context.Background())
+	require.NoError(t, err)
+	require.Len(t, f.Items, 1)
+	assert.Empty(t, f.Items[0].Content)
+	assert.Empty(t, f.Items[0].Summary, "the summary is not cut from a locked body")
+}
+
+// expandFunc adapts a function to content.Snippets.
+type expandFunc func(entries ...*content.Content)
+
+func (f expandFunc) Expand(_ context.Context, entries ...*content.Content) error {
+	f(entries...)
+	return nil
+}
+
+func TestFeedUseCase_Site_ExpandsSnippets(t *testing.T) {
+	opts := testOptions
+	opts.FullContent = true
+	m := mocks{
+		content:  new(MockContentRepository),
+		category: new(MockCategoryRepository),
+		tag:      new(MockTagRepository),
+		user:     new(MockUserRepository),
+	}
+	uc := NewFeedUseCase(m.content, m.category, m.tag, m.user, testPermalinks, expandFunc(func(entries ...*content.Content) {
+		for _, c := range entries {
+			c.BodyHTML = strings.ReplaceAll(c.BodyHTML, "{{snippet:footer}}", "<p>Bye</p>")
+		}
+	}), opts)
+
+	entry := posts()[0]
+	entry.AuthorID = ""
+	entry.BodyHTML = "<p>Hello</p>{{snippet:footer}}"
+	m.content.On("List", mock.Anything, mock.Anything).Return([]*content.Content{entry}, nil)
+	m.category.On("ListByContent", mock.Anything, "post-2").Return([]*category.Category{}, nil)
+
+	f, err := uc.Site(context.Background())
+	require.NoError(t, err)
+	require.Len(t, f.Items, 1)
+	assert.Equal(t, "<p>Hello</p><p>Bye</p>", f.Items[0].Content)
+}
+
+func TestFeedUseCase_Site_Empty(t *testing.T) {
+	uc, m := newTestUseCase(testOptions)
+
+	m.content.On("List", mock.Anything, mock.Anything).Return([]*content.Content{}, nil)
+
+	f, err := uc.Site(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, f.Items)
+	assert.Equal(t, time.Unix(0, 0).UTC(), f.Updated)
+}
+
+func TestFeedUseCase_Category(t *testing.T) {
+	uc, m := newTestUseCase(testOptions)
+
+	m.category.On("GetBySlug", mock.Anything, "news").Return(&category.Category{ID: "news", Name: "News", Slug: "news"}, nil)
+	m.category.On("List", mock.Anything).Return([]*category.Category{
+		{ID: "news"}, {ID: "local", ParentID: "news"}, {ID: "sports"},
+	}, nil)
+	m.content.On("List", mock.Anything, publishedFilter(content.ListFilter{
+		Type: "post", Status: content.StatusPublished, Limit: DefaultSize, CategoryIDs: []string{"news", "local"},
+	})).Return([]*content.Content{}, nil)
+
+	f, err := uc.Category(context.Background(), "news")
+	require.NoError(t, err)
+	assert.Equal(t, "Example - News", f.Title)
+	assert.Equal(t, "https://example.com/feeds/category/news/feed.xml", f.RSSURL)
+	assert.Equal(t, "https://example.com/feeds/category/news/atom.xml", f.AtomURL)
+}
+
+func TestFeedUseCase_Category_NotFound(t *testing.T) {
+	uc, m := newTestUseCase(testOptions)
+
+	m.category.On("GetBySlug", mock.Anything, "missing").Return(nil, category.ErrNotFound)
+
+	_, err := uc.Category(context.Background(), "missing")
+	assert.ErrorIs(t, err, category.ErrNotFound)
+}
+
+func TestFeedUseCase_Tag(t *testing.T) {
+	uc, m := newTestUseCase(testOptions)
+
+	m.tag.On("GetBySlug", mock.Anything, "go").Return(&tag.Tag{ID: "tag-1", Name: "Go", Slug: "go"}, nil)
+	m.content.On("List", mock.Anything, publishedFilter(content.ListFilter{
+		Type: "post", Status: content.StatusPublished, Limit: DefaultSize, TagID: "tag-1",
+	})).Return([]*content.Content{}, nil)
+
+	f, err := uc.Tag(context.Background(), "go")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/feeds/tag/go/atom.xml", f.AtomURL)
+}
+
+func TestFeedUseCase_Author(t *testing.T) {
+	uc, m := newTestUseCase(testOptions)
+
+	m.user.On("GetByUsername", mock.Anything, "ana").Return(&user.User{ID: "user-1", Username: "ana"}, nil)
+	m.content.On("List", mock.Anything, publishedFilter(content.ListFilter{
+		Type: "post", Status: content.StatusPublished, Limit: DefaultSize, AuthorID: "user-1",
+	})).Return([]*content.Content{}, nil)
+
+	f, err := uc.Author(context.Background(), "ana")
+	require.NoError(t, err)
+	assert.Equal(t, "Example - ana", f.Title)
+	assert.Equal(t, "https://example.com/feeds/author/ana/feed.xml", f.RSSURL)
+
+	m.user.On("GetByUsername", mock.Anything, "nobody").Return(nil, nil)
+	_, err = uc.Author(context.Background(), "nobody")
+	assert.ErrorIs(t, err, ErrUnknownAuthor)
+}
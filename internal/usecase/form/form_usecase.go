@@ -0,0 +1,328 @@
+package form
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mashurimansur/goCMS/internal/domain/form"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+)
+
+var (
+	// ErrFormExists is returned when another form already uses the name.
+	ErrFormExists = errors.New("form name already exists")
+	// ErrRateLimited is returned when an address submitted too many forms recently.
+	ErrRateLimited = errors.New("too many submissions, try again later")
+)
+
+// DefaultHoneypotField is the hidden input that only bots fill in when Options leaves it empty.
+const DefaultHoneypotField = "hp_website"
+
+// exportPageSize is the number of submissions read at once while exporting.
+const exportPageSize = 500
+
+// maxUserAgentLength caps the stored user agent of a submission.
+const maxUserAgentLength = 255
+
+// notifyTimeout bounds how long a notification may take once the submission is stored.
+const notifyTimeout = 30 * time.Second
+
+// Options configure the protection of public submissions.
+type Options struct {
+	// HoneypotField names the hidden input bots fill in. Submissions carrying a value for it are
+	// answered like any other but not stored.
+	HoneypotField string
+	// MaxPerIP is the number of submissions one address may make within RateWindow. Zero disables
+	// the limit.
+	MaxPerIP   int
+	RateWindow time.Duration
+}
+
+type UseCase interface {
+	Create(ctx context.Context, f *form.Form) error
+	Get(ctx context.Context, id string) (*form.Form, error)
+	Update(ctx context.Context, f *form.Form) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*form.Form, error)
+	// Definition returns the form named name for public display, without its private settings.
+	Definition(ctx context.Context, name string) (*form.Form, error)
+	// Submit validates and stores the Data of s as a submission to the form named name and returns
+	// the form. Submissions caught by the honeypot are not stored and leave s.ID empty. Forms with a
+	// NotifyURL are notified in the background.
+	Submit(ctx context.Context, name string, s *form.Submission) (*form.Form, error)
+	// Submissions lists the submissions of a form, newest first.
+	Submissions(ctx context.Context, formID string, limit, offset int) ([]*form.Submission, error)
+	DeleteSubmission(ctx context.Context, formID, id string) error
+	// Export writes every submission of a form to w as CSV, newest first, with a column per field.
+	// Nothing is written when the form does not exist.
+	Export(ctx context.Context, formID string, w io.Writer) error
+	// PurgeExpired deletes the submissions older than the retention of their form and reports how
+	// many were removed.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type formUseCase struct {
+	formRepo form.Repository
+	notifier form.Notifier
+	opts     Options
+}
+
+// NewFormUseCase creates the form use case. notifier may be nil, in which case no notification is
+// sent.
+func NewFormUseCase(formRepo form.Repository, notifier form.Notifier, opts Options) UseCase {
+	if opts.HoneypotField == "" {
+		opts.HoneypotField = DefaultHoneypotField
+	}
+	return &formUseCase{
+		formRepo: formRepo,
+		notifier: notifier,
+		opts:     opts,
+	}
+}
+
+func (uc *formUseCase) Create(ctx context.Context, f *form.Form) error {
+	if err := uc.prepare(ctx, f); err != nil {
+		return err
+	}
+	return uc.formRepo.Create(ctx, f)
+}
+
+func (uc *formUseCase) Get(ctx context.Context, id string) (*form.Form, error) {
+	return uc.formRepo.GetByID(ctx, id)
+}
+
+func (uc *formUseCase) Update(ctx context.Context, f *form.Form) error {
+	existing, err := uc.formRepo.GetByID(ctx, f.ID)
+	if err != nil {
+		return err
+	}
+	if f.Version, err = lock.Expect(f.Version, existing.Version); err != nil {
+		return err
+	}
+	f.CreatedAt = existing.CreatedAt
+	if err := uc.prepare(ctx, f); err != nil {
+		return err
+	}
+	return uc.formRepo.Update(ctx, f)
+}
+
+func (uc *formUseCase) Delete(ctx context.Context, id string) error {
+	return uc.formRepo.Delete(ctx, id)
+}
+
+func (uc *formUseCase) List(ctx context.Context) ([]*form.Form, error) {
+	forms, err := uc.formRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if forms == nil {
+		return []*form.Form{}, nil
+	}
+	return forms, nil
+}
+
+func (uc *formUseCase) Definition(ctx context.Context, name string) (*form.Form, error) {
+	f, err := uc.formRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	f.Redact()
+	return f, nil
+}
+
+func (uc *formUseCase) Submit(ctx context.Context, name string, s *form.Submission) (*form.Form, error) {
+	f, err := uc.formRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	s.ID = ""
+	if strings.TrimSpace(s.Data[uc.opts.HoneypotField]) != "" {
+		// Bots are told they succeeded so they do not retry with another strategy.
+		return f, nil
+	}
+
+	data, err := f.Validate(s.Data)
+	if err != nil {
+		return nil, err
+	}
+	s.FormID = f.ID
+	s.Data = data
+	s.UserAgent = truncate(s.UserAgent, maxUserAgentLength)
+	s.CreatedAt = time.Time{}
+	if s.IP != "" && uc.opts.MaxPerIP > 0 {
+		stored, err := uc.formRepo.CreateSubmissionLimited(ctx, s, time.Now().Add(-uc.opts.RateWindow), uc.opts.MaxPerIP)
+		if err != nil {
+			return nil, err
+		}
+		if !stored {
+			return nil, ErrRateLimited
+		}
+	} else if err := uc.formRepo.CreateSubmission(ctx, s); err != nil {
+		return nil, err
+	}
+
+	if f.NotifyURL != "" && uc.notifier != nil {
+		go uc.notify(context.WithoutCancel(ctx), f, s)
+	}
+	return f, nil
+}
+
+func (uc *formUseCase) Submissions(ctx context.Context, formID string, limit, offset int) ([]*form.Submission, error) {
+	if _, err := uc.formRepo.GetByID(ctx, formID); err != nil {
+		return nil, err
+	}
+	return uc.formRepo.ListSubmissions(ctx, formID, limit, offset)
+}
+
+func (uc *formUseCase) DeleteSubmission(ctx context.Context, formID, id string) error {
+	s, err := uc.formRepo.GetSubmission(ctx, id)
+	if err != nil {
+		return err
+	}
+	if s.FormID != formID {
+		return form.ErrSubmissionNotFound
+	}
+	return uc.formRepo.DeleteSubmission(ctx, id)
+}
+
+func (uc *formUseCase) Export(ctx context.Context, formID string, w io.Writer) error {
+	f, err := uc.formRepo.GetByID(ctx, formID)
+	if err != nil {
+		return err
+	}
+
+	out := csv.NewWriter(w)
+	header := []string{"id", "submitted_at"}
+	for _, field := range f.Fields {
+		header = append(header, field.Name)
+	}
+	if err := out.Write(append(header, "ip", "user_agent")); err != nil {
+		return err
+	}
+
+	var last *form.Submission
+	for {
+		page, err := uc.formRepo.ListSubmissionsAfter(ctx, formID, last, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, s := range page {
+			record := []string{s.ID, s.CreatedAt.UTC().Format(time.RFC3339)}
+			for _, field := range f.Fields {
+				record = append(record, csvCell(s.Data[field.Name]))
+			}
+			if err := out.Write(append(record, s.IP, csvCell(s.UserAgent))); err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			break
+		}
+		last = page[len(page)-1]
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func (uc *formUseCase) PurgeExpired(ctx context.Context) (int64, error) {
+	forms, err := uc.formRepo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	for _, f := range forms {
+		if f.RetentionDays == 0 {
+			continue
+		}
+		n, err := uc.formRepo.DeleteSubmissionsBefore(ctx, f.ID, time.Now().AddDate(0, 0, -f.RetentionDays))
+		if err != nil {
+			return purged, err
+		}
+		purged += n
+	}
+	return purged, nil
+}
+
+// prepare normalizes f and checks its definition, URLs and name.
+func (uc *formUseCase) prepare(ctx context.Context, f *form.Form) error {
+	f.Name = strings.TrimSpace(f.Name)
+	f.Title = strings.TrimSpace(f.Title)
+	if f.Title == "" {
+		f.Title = f.Name
+	}
+	if err := f.ValidateDefinition(); err != nil {
+		return err
+	}
+	for _, field := range f.Fields {
+		if field.Name == uc.opts.HoneypotField {
+			return fmt.Errorf("%w: field %q is reserved for the spam trap", form.ErrInvalidDefinition, field.Name)
+		}
+	}
+	if err := validURL(f.RedirectURL, true); err != nil {
+		return fmt.Errorf("%w: redirect_url %v", form.ErrInvalidDefinition, err)
+	}
+	if err := validURL(f.NotifyURL, false); err != nil {
+		return fmt.Errorf("%w: notify_url %v", form.ErrInvalidDefinition, err)
+	}
+
+	existing, err := uc.formRepo.GetByName(ctx, f.Name)
+	if err != nil && !errors.Is(err, form.ErrNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != f.ID {
+		return ErrFormExists
+	}
+	return nil
+}
+
+// notify tells the notifier about a stored submission, logging failures since the reader has
+// already been answered.
+func (uc *formUseCase) notify(ctx context.Context, f *form.Form, s *form.Submission) {
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	if err := uc.notifier.Notify(ctx, f, s); err != nil {
+		log.Printf("cannot notify about submission %s to form %q: %v", s.ID, f.Name, err)
+	}
+}
+
+// validURL accepts an empty value, an absolute http or https URL and, with allowPath, a site path.
+func validURL(raw string, allowPath bool) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return errors.New("is not a valid URL")
+	}
+	if allowPath && u.Scheme == "" && u.Host == "" && strings.HasPrefix(u.Path, "/") && !strings.HasPrefix(raw, "//") {
+		return nil
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// csvCell keeps spreadsheet applications from evaluating submitted values as formulas.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
@@ -0,0 +1,371 @@
+package form
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/form"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository is an in-memory form.Repository.
+type memoryRepository struct {
+	forms       map[string]*form.Form
+	submissions []*form.Submission
+	nextID      int
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{forms: map[string]*form.Form{}}
+}
+
+func (r *memoryRepository) id(prefix string) string {
+	r.nextID++
+	return fmt.Sprintf("%s-%d", prefix, r.nextID)
+}
+
+func (r *memoryRepository) Create(_ context.Context, f *form.Form) error {
+	f.ID = r.id("f")
+	f.Version = 1
+	stored := *f
+	r.forms[f.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) GetByID(_ context.Context, id string) (*form.Form, error) {
+	f, ok := r.forms[id]
+	if !ok {
+		return nil, form.ErrNotFound
+	}
+	found := *f
+	return &found, nil
+}
+
+func (r *memoryRepository) GetByName(ctx context.Context, name string) (*form.Form, error) {
+	for id, f := range r.forms {
+		if f.Name == name {
+			return r.GetByID(ctx, id)
+		}
+	}
+	return nil, form.ErrNotFound
+}
+
+func (r *memoryRepository) Update(_ context.Context, f *form.Form) error {
+	stored, ok := r.forms[f.ID]
+	if !ok {
+		return form.ErrNotFound
+	}
+	if stored.Version != f.Version {
+		return lock.ErrVersionConflict
+	}
+	f.Version++
+	updated := *f
+	r.forms[f.ID] = &updated
+	return nil
+}
+
+func (r *memoryRepository) Delete(_ context.Context, id string) error {
+	delete(r.forms, id)
+	return nil
+}
+
+func (r *memoryRepository) List(_ context.Context) ([]*form.Form, error) {
+	var all []*form.Form
+	for _, f := range r.forms {
+		found := *f
+		all = append(all, &found)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all, nil
+}
+
+func (r *memoryRepository) CreateSubmission(_ context.Context, s *form.Submission) error {
+	s.ID = r.id("s")
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now()
+	}
+	stored := *s
+	r.submissions = append(r.submissions, &stored)
+	return nil
+}
+
+func (r *memoryRepository) GetSubmission(_ context.Context, id string) (*form.Submission, error) {
+	for _, s := range r.submissions {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, form.ErrSubmissionNotFound
+}
+
+func (r *memoryRepository) ListSubmissions(_ context.Context, formID string, limit, offset int) ([]*form.Submission, error) {
+	page := []*form.Submission{}
+	for i := len(r.submissions) - 1; i >= 0; i-- {
+		if r.submissions[i].FormID == formID {
+			page = append(page, r.submissions[i])
+		}
+	}
+	if offset > len(page) {
+		offset = len(page)
+	}
+	page = page[offset:]
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	return page, nil
+}
+
+func (r *memoryRepository) ListSubmissionsAfter(_ context.Context, formID string, after *form.Submission, limit int) ([]*form.Submission, error) {
+	page := []*form.Submission{}
+	for _, s := range r.submissions {
+		if s.FormID == formID && (after == nil || s.CreatedAt.Before(after.CreatedAt) || s.CreatedAt.Equal(after.CreatedAt) && s.ID > after.ID) {
+			page = append(page, s)
+		}
+	}
+	sort.Slice(page, func(i, j int) bool {
+		if !page[i].CreatedAt.Equal(page[j].CreatedAt) {
+			return page[i].CreatedAt.After(page[j].CreatedAt)
+		}
+		return page[i].ID < page[j].ID
+	})
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	return page, nil
+}
+
+func (r *memoryRepository) DeleteSubmission(_ context.Context, id string) error {
+	return r.deleteWhere(func(s *form.Submission) bool { return s.ID == id })
+}
+
+func (r *memoryRepository) DeleteSubmissionsBefore(_ context.Context, formID string, before time.Time) (int64, error) {
+	count := len(r.submissions)
+	r.deleteWhere(func(s *form.Submission) bool { return s.FormID == formID && s.CreatedAt.Before(before) })
+	return int64(count - len(r.submissions)), nil
+}
+
+func (r *memoryRepository) deleteWhere(match func(*form.Submission) bool) error {
+	kept := r.submissions[:0]
+	for _, s := range r.submissions {
+		if !match(s) {
+			kept = append(kept, s)
+		}
+	}
+	r.submissions = kept
+	return nil
+}
+
+func (r *memoryRepository) CreateSubmissionLimited(ctx context.Context, s *form.Submission, since time.Time, max int) (bool, error) {
+	count := 0
+	for _, stored := range r.submissions {
+		if stored.IP == s.IP && !stored.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	if count >= max {
+		return false, nil
+	}
+	return true, r.CreateSubmission(ctx, s)
+}
+
+// notifierFunc adapts a function to form.Notifier.
+type notifierFunc func(f *form.Form, s *form.Submission)
+
+func (fn notifierFunc) Notify(_ context.Context, f *form.Form, s *form.Submission) error {
+	fn(f, s)
+	return nil
+}
+
+func contactForm() *form.Form {
+	return &form.Form{
+		Name: "contact",
+		Fields: []form.Field{
+			{Name: "email", Type: form.FieldEmail, Required: true},
+			{Name: "message", Type: form.FieldTextarea},
+		},
+		SuccessMessage: "Thanks!",
+	}
+}
+
+func TestFormUseCase_Create(t *testing.T) {
+	uc := NewFormUseCase(newMemoryRepository(), nil, Options{})
+	ctx := context.Background()
+
+	f := contactForm()
+	require.NoError(t, uc.Create(ctx, f))
+	assert.Equal(t, "contact", f.Title)
+
+	assert.ErrorIs(t, uc.Create(ctx, contactForm()), ErrFormExists)
+
+	trap := contactForm()
+	trap.Name = "trap"
+	trap.Fields = append(trap.Fields, form.Field{Name: DefaultHoneypotField, Type: form.FieldURL})
+	assert.ErrorIs(t, uc.Create(ctx, trap), form.ErrInvalidDefinition)
+
+	hook := contactForm()
+	hook.Name = "hook"
+	hook.NotifyURL = "/hooks/contact"
+	assert.ErrorIs(t, uc.Create(ctx, hook), form.ErrInvalidDefinition)
+	hook.NotifyURL = "https://hooks.example.com/contact"
+	hook.RedirectURL = "/thank-you"
+	assert.NoError(t, uc.Create(ctx, hook))
+}
+
+func TestFormUseCase_Submit(t *testing.T) {
+	notified := make(chan *form.Submission, 1)
+	repo := newMemoryRepository()
+	uc := NewFormUseCase(repo, notifierFunc(func(_ *form.Form, s *form.Submission) { notified <- s }), Options{MaxPerIP: 1, RateWindow: time.Minute})
+	ctx := context.Background()
+
+	f := contactForm()
+	f.NotifyURL = "https://hooks.example.com/contact"
+	require.NoError(t, uc.Create(ctx, f))
+
+	s := &form.Submission{Data: map[string]string{"email": "ana@example.com", "extra": "x"}, IP: "203.0.113.9"}
+	got, err := uc.Submit(ctx, "contact", s)
+	require.NoError(t, err)
+	assert.Equal(t, "Thanks!", got.SuccessMessage)
+	assert.NotEmpty(t, s.ID)
+	assert.Equal(t, map[string]string{"email": "ana@example.com", "message": ""}, s.Data)
+
+	select {
+	case n := <-notified:
+		assert.Equal(t, s.ID, n.ID)
+	case <-time.After(time.Second):
+		t.Fatal("submission was not notified")
+	}
+
+	_, err = uc.Submit(ctx, "contact", &form.Submission{Data: map[string]string{"email": "bo@example.com"}, IP: "203.0.113.9"})
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	_, err = uc.Submit(ctx, "contact", &form.Submission{Data: map[string]string{"email": "nope"}, IP: "198.51.100.7"})
+	assert.ErrorIs(t, err, form.ErrInvalidSubmission)
+
+	_, err = uc.Submit(ctx, "missing", &form.Submission{})
+	assert.ErrorIs(t, err, form.ErrNotFound)
+}
+
+func TestFormUseCase_Submit_Honeypot(t *testing.T) {
+	repo := newMemoryRepository()
+	uc := NewFormUseCase(repo, nil, Options{})
+	ctx := context.Background()
+	require.NoError(t, uc.Create(ctx, contactForm()))
+
+	s := &form.Submission{Data: map[string]string{"email": "bot@example.com", DefaultHoneypotField: "http://spam.example"}}
+	f, err := uc.Submit(ctx, "contact", s)
+	require.NoError(t, err)
+	assert.Equal(t, "Thanks!", f.SuccessMessage)
+	assert.Empty(t, s.ID)
+	assert.Empty(t, repo.submissions)
+}
+
+func TestFormUseCase_Definition(t *testing.T) {
+	uc := NewFormUseCase(newMemoryRepository(), nil, Options{})
+	ctx := context.Background()
+
+	f := contactForm()
+	f.NotifyURL = "https://hooks.example.com/contact"
+	f.RetentionDays = 30
+	require.NoError(t, uc.Create(ctx, f))
+
+	public, err := uc.Definition(ctx, "contact")
+	require.NoError(t, err)
+	assert.Empty(t, public.NotifyURL)
+	assert.Zero(t, public.RetentionDays)
+	assert.Len(t, public.Fields, 2)
+}
+
+func TestFormUseCase_Export(t *testing.T) {
+	repo := newMemoryRepository()
+	uc := NewFormUseCase(repo, nil, Options{})
+	ctx := context.Background()
+
+	f := contactForm()
+	require.NoError(t, uc.Create(ctx, f))
+	submitted := time.Date(2026, 1, 16, 9, 30, 0, 0, time.UTC)
+	require.NoError(t, repo.CreateSubmission(ctx, &form.Submission{
+		FormID: f.ID, Data: map[string]string{"email": "ana@example.com", "message": "=HYPERLINK(\"x\")"}, IP: "203.0.113.9", CreatedAt: submitted,
+	}))
+
+	var buf bytes.Buffer
+	require.NoError(t, uc.Export(ctx, f.ID, &buf))
+	assert.Equal(t, "id,submitted_at,email,message,ip,user_agent\n"+
+		"s-2,2026-01-16T09:30:00Z,ana@example.com,\"'=HYPERLINK(\"\"x\"\")\",203.0.113.9,\n", buf.String())
+
+	buf.Reset()
+	assert.ErrorIs(t, uc.Export(ctx, "missing", &buf), form.ErrNotFound)
+	assert.Zero(t, buf.Len())
+}
+
+func TestFormUseCase_Export_Pages(t *testing.T) {
+	repo := newMemoryRepository()
+	uc := NewFormUseCase(repo, nil, Options{})
+	ctx := context.Background()
+
+	f := contactForm()
+	require.NoError(t, uc.Create(ctx, f))
+	// Two pages of submissions sharing a few timestamps, so pages also break between equal times.
+	submitted := time.Date(2026, 1, 16, 9, 30, 0, 0, time.UTC)
+	total := exportPageSize + 10
+	for i := 0; i < total; i++ {
+		require.NoError(t, repo.CreateSubmission(ctx, &form.Submission{
+			FormID: f.ID, Data: map[string]string{"email": "ana@example.com"}, CreatedAt: submitted.Add(time.Duration(i/7) * time.Second),
+		}))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, uc.Export(ctx, f.ID, &buf))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, total+1)
+	seen := make(map[string]bool)
+	for _, line := range lines[1:] {
+		id := strings.SplitN(line, ",", 2)[0]
+		assert.False(t, seen[id], "submission %s exported twice", id)
+		seen[id] = true
+	}
+}
+
+func TestFormUseCase_PurgeExpired(t *testing.T) {
+	repo := newMemoryRepository()
+	uc := NewFormUseCase(repo, nil, Options{})
+	ctx := context.Background()
+
+	kept := contactForm()
+	require.NoError(t, uc.Create(ctx, kept))
+	expiring := contactForm()
+	expiring.Name = "signup"
+	expiring.RetentionDays = 30
+	require.NoError(t, uc.Create(ctx, expiring))
+
+	old := time.Now().AddDate(0, 0, -31)
+	require.NoError(t, repo.CreateSubmission(ctx, &form.Submission{FormID: kept.ID, CreatedAt: old}))
+	require.NoError(t, repo.CreateSubmission(ctx, &form.Submission{FormID: expiring.ID, CreatedAt: old}))
+	require.NoError(t, repo.CreateSubmission(ctx, &form.Submission{FormID: expiring.ID}))
+
+	purged, err := uc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.Len(t, repo.submissions, 2)
+}
+
+func TestFormUseCase_DeleteSubmission(t *testing.T) {
+	repo := newMemoryRepository()
+	uc := NewFormUseCase(repo, nil, Options{})
+	ctx := context.Background()
+
+	f := contactForm()
+	require.NoError(t, uc.Create(ctx, f))
+	s := &form.Submission{FormID: f.ID}
+	require.NoError(t, repo.CreateSubmission(ctx, s))
+
+	assert.ErrorIs(t, uc.DeleteSubmission(ctx, "other", s.ID), form.ErrSubmissionNotFound)
+	require.NoError(t, uc.DeleteSubmission(ctx, f.ID, s.ID))
+	assert.Empty(t, repo.submissions)
+}
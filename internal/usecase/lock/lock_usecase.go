@@ -0,0 +1,250 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+)
+
+var (
+	// ErrInvalidResource is returned for resource types that cannot be locked.
+	ErrInvalidResource = errors.New("invalid lock resource")
+	// ErrLocked is returned when someone else holds the lock of a resource.
+	ErrLocked = errors.New("resource is being edited by someone else")
+)
+
+// DefaultTTL is how long a lock is held without heartbeats unless Options say otherwise.
+const DefaultTTL = 2 * time.Minute
+
+// Options configures edit locks.
+type Options struct {
+	// TTL is how long a lock is held after it was acquired or its last heartbeat.
+	TTL time.Duration
+}
+
+type UseCase interface {
+	// Acquire locks a resource for userID, or extends the lock userID already holds. When someone else
+	// holds the lock, ErrLocked is returned along with their lock unless takeover is set.
+	Acquire(ctx context.Context, resourceType, resourceID, userID string, takeover bool) (*lock.Lock, error)
+	// Heartbeat extends the lock userID holds. It fails with ErrLocked, along with the current lock,
+	// once someone else took the lock over.
+	Heartbeat(ctx context.Context, resourceType, resourceID, userID string) (*lock.Lock, error)
+	// Release gives up the lock userID holds. Releasing a lock that is not held is not an error.
+	Release(ctx context.Context, resourceType, resourceID, userID string) error
+	// Get returns the active lock of a resource.
+	Get(ctx context.Context, resourceType, resourceID string) (*lock.Lock, error)
+	// List returns the active locks of a resource type.
+	List(ctx context.Context, resourceType string) ([]*lock.Lock, error)
+}
+
+type lockUseCase struct {
+	lockRepo lock.Repository
+	userRepo user.Repository
+	opts     Options
+}
+
+func NewLockUseCase(lockRepo lock.Repository, userRepo user.Repository, opts Options) UseCase {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	return &lockUseCase{
+		lockRepo: lockRepo,
+		userRepo: userRepo,
+		opts:     opts,
+	}
+}
+
+func (uc *lockUseCase) Acquire(ctx context.Context, resourceType, resourceID, userID string, takeover bool) (*lock.Lock, error) {
+	if err := validateResource(resourceType, resourceID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	current, err := uc.active(ctx, resourceType, resourceID, now)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.HolderID != userID && !takeover {
+		return current, fmt.Errorf("%w: %s holds the lock", ErrLocked, current.HolderName)
+	}
+
+	l := &lock.Lock{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		HolderID:     userID,
+		AcquiredAt:   now,
+	}
+	if current != nil && current.HolderID == userID {
+		l = current
+	}
+	if takeover {
+		return uc.extend(ctx, l, now)
+	}
+	return uc.claim(ctx, l, now)
+}
+
+func (uc *lockUseCase) Heartbeat(ctx context.Context, resourceType, resourceID, userID string) (*lock.Lock, error) {
+	if err := validateResource(resourceType, resourceID); err != nil {
+		return nil, err
+	}
+
+	l, err := uc.lockRepo.Get(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if l.HolderID != userID {
+		if !l.Active(now) {
+			return nil, lock.ErrNotFound
+		}
+		if err := uc.resolveHolder(ctx, l); err != nil {
+			return nil, err
+		}
+		return l, fmt.Errorf("%w: %s took the lock over", ErrLocked, l.HolderName)
+	}
+
+	// A lock that expired while nobody else claimed it is still the holder's to extend.
+	if err := uc.resolveHolder(ctx, l); err != nil {
+		return nil, err
+	}
+	return uc.claim(ctx, l, now)
+}
+
+func (uc *lockUseCase) Release(ctx context.Context, resourceType, resourceID, userID string) error {
+	if err := validateResource(resourceType, resourceID); err != nil {
+		return err
+	}
+
+	err := uc.lockRepo.Delete(ctx, resourceType, resourceID, userID)
+	if errors.Is(err, lock.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (uc *lockUseCase) Get(ctx context.Context, resourceType, resourceID string) (*lock.Lock, error) {
+	if err := validateResource(resourceType, resourceID); err != nil {
+		return nil, err
+	}
+
+	l, err := uc.active(ctx, resourceType, resourceID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return nil, lock.ErrNotFound
+	}
+	return l, nil
+}
+
+func (uc *lockUseCase) List(ctx context.Context, resourceType string) ([]*lock.Lock, error) {
+	if !slices.Contains(lock.ResourceTypes, resourceType) {
+		return nil, fmt.Errorf("%w: unknown resource type %q", ErrInvalidResource, resourceType)
+	}
+
+	locks, err := uc.lockRepo.ListActive(ctx, resourceType, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range locks {
+		if err := uc.resolveHolder(ctx, l); err != nil {
+			return nil, err
+		}
+	}
+	return locks, nil
+}
+
+// active returns the unexpired lock of a resource with its holder resolved, or nil when it is free.
+func (uc *lockUseCase) active(ctx context.Context, resourceType, resourceID string, now time.Time) (*lock.Lock, error) {
+	l, err := uc.lockRepo.Get(ctx, resourceType, resourceID)
+	if errors.Is(err, lock.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !l.Active(now) {
+		return nil, nil
+	}
+	if err := uc.resolveHolder(ctx, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// extend saves l as held from now for another TTL, whoever holds the stored lock.
+func (uc *lockUseCase) extend(ctx context.Context, l *lock.Lock, now time.Time) (*lock.Lock, error) {
+	l.HeartbeatAt = now
+	l.ExpiresAt = now.Add(uc.opts.TTL)
+	if err := uc.lockRepo.Save(ctx, l); err != nil {
+		return nil, err
+	}
+	return uc.withHolder(ctx, l)
+}
+
+// claim saves l as held from now for another TTL unless someone else holds an active lock on its
+// resource by then, in which case ErrLocked is returned along with their lock.
+func (uc *lockUseCase) claim(ctx context.Context, l *lock.Lock, now time.Time) (*lock.Lock, error) {
+	l.HeartbeatAt = now
+	l.ExpiresAt = now.Add(uc.opts.TTL)
+	claimed, err := uc.lockRepo.Claim(ctx, l, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		// A claim that changes nothing, such as a second heartbeat within the same second, is not
+		// reported as saved either, so look at who holds the lock now.
+		current, err := uc.active(ctx, l.ResourceType, l.ResourceID, now)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			return nil, fmt.Errorf("%w: the lock changed hands, try again", ErrLocked)
+		}
+		if current.HolderID != l.HolderID {
+			return current, fmt.Errorf("%w: %s holds the lock", ErrLocked, current.HolderName)
+		}
+	}
+	return uc.withHolder(ctx, l)
+}
+
+// withHolder returns l with its holder resolved.
+func (uc *lockUseCase) withHolder(ctx context.Context, l *lock.Lock) (*lock.Lock, error) {
+	if l.HolderName == "" {
+		if err := uc.resolveHolder(ctx, l); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// resolveHolder fills in the name editors see the holder of a lock as.
+func (uc *lockUseCase) resolveHolder(ctx context.Context, l *lock.Lock) error {
+	u, err := uc.userRepo.GetByID(ctx, l.HolderID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return nil
+	}
+	l.HolderName = u.FullName
+	if l.HolderName == "" {
+		l.HolderName = u.Username
+	}
+	return nil
+}
+
+func validateResource(resourceType, resourceID string) error {
+	if !slices.Contains(lock.ResourceTypes, resourceType) {
+		return fmt.Errorf("%w: unknown resource type %q", ErrInvalidResource, resourceType)
+	}
+	if resourceID == "" {
+		return fmt.Errorf("%w: resource ID is required", ErrInvalidResource)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockLockRepository struct {
+	mock.Mock
+}
+
+func (m *MockLockRepository) Get(ctx context.Context, resourceType, resourceID string) (*lock.Lock, error) {
+	args := m.Called(ctx, resourceType, resourceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*lock.Lock), args.Error(1)
+}
+
+func (m *MockLockRepository) Save(ctx context.Context, l *lock.Lock) error {
+	args := m.Called(ctx, l)
+	return args.Error(0)
+}
+
+func (m *MockLockRepository) Claim(ctx context.Context, l *lock.Lock, now time.Time) (bool, error) {
+	args := m.Called(ctx, l, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLockRepository) Delete(ctx context.Context, resourceType, resourceID, holderID string) error {
+	args := m.Called(ctx, resourceType, resourceID, holderID)
+	return args.Error(0)
+}
+
+func (m *MockLockRepository) ListActive(ctx context.Context, resourceType string, now time.Time) ([]*lock.Lock, error) {
+	args := m.Called(ctx, resourceType, now)
+	return args.Get(0).([]*lock.Lock), args.Error(1)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+	user.Repository
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func newTestUseCase() (UseCase, *MockLockRepository) {
+	repo := new(MockLockRepository)
+	users := new(MockUserRepository)
+	users.On("GetByID", mock.Anything, "user-1").Return(&user.User{ID: "user-1", FullName: "Ana Editor"}, nil)
+	users.On("GetByID", mock.Anything, "user-2").Return(&user.User{ID: "user-2", Username: "budi"}, nil)
+	return NewLockUseCase(repo, users, Options{}), repo
+}
+
+func heldBy(holderID string, expiresIn time.Duration) *lock.Lock {
+	now := time.Now()
+	return &lock.Lock{
+		ResourceType: lock.ResourceContent, ResourceID: "post-1", HolderID: holderID,
+		AcquiredAt: now.Add(-time.Minute), HeartbeatAt: now.Add(-time.Minute), ExpiresAt: now.Add(expiresIn),
+	}
+}
+
+func TestLockUseCase_Acquire(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	repo.On("Get", mock.Anything, lock.ResourceContent, "post-1").Return(nil, lock.ErrNotFound)
+	repo.On("Claim", mock.Anything, mock.AnythingOfType("*lock.Lock"), mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	l, err := uc.Acquire(context.Background(), lock.ResourceContent, "post-1", "user-1", false)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", l.HolderID)
+	assert.Equal(t, "Ana Editor", l.HolderName)
+	assert.WithinDuration(t, time.Now().Add(DefaultTTL), l.ExpiresAt, time.Second)
+}
+
+func TestLockUseCase_Acquire_Locked(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	repo.On("Get", mock.Anything, lock.ResourceContent, "post-1").Return(heldBy("user-2", time.Minute), nil)
+
+	l, err := uc.Acquire(context.Background(), lock.ResourceContent, "post-1", "user-1", false)
+	assert.ErrorIs(t, err, ErrLocked)
+	require.NotNil(t, l)
+	assert.Equal(t, "budi", l.HolderName)
+	repo.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLockUseCase_Acquire_LostRace(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	repo.On("Get", mock.Anything, lock.ResourceContent, "post-1").Return(nil, lock.ErrNotFound).Once()
+	repo.On("Claim", mock.Anything, mock.AnythingOfType("*lock.Lock"), mock.AnythingOfType("time.Time")).Return(false, nil)
+	repo.On("Get", mock.Anything, lock.ResourceContent, "post-1").Return(heldBy("user-2", time.Minute), nil).Once()
+
+	l, err := uc.Acquire(context.Background(), lock.ResourceContent, "post-1", "user-1", false)
+	assert.ErrorIs(t, err, ErrLocked)
+	require.NotNil(t, l)
+	assert.Equal(t, "user-2", l.HolderID)
+}
+
+func TestLockUseCase_Acquire_Takeover(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	repo.On("Get", mock.Anything, lock.ResourceContent, "post-1").Return(heldBy("user-2", time.Minute), nil)
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(l *lock.Lock) bool { return l.HolderID == "user-1" })).Return(nil)
+
+	l, err := uc.Acquire(context.Background(), lock.ResourceContent, "post-1", "user-1", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Ana Editor", l.HolderName)
+}
+
+func TestLockUseCase_Acquire_Expired(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	repo.On("Get", mock.Anything, lock.ResourceContent, "post-1").Return(heldBy("user-2", -time.Second), nil)
+	repo.On("Claim", mock.Anything, mock.AnythingOfType("*lock.Lock"), mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	l, err := uc.Acquire(context.Background(), lock.ResourceContent, "post-1", "user-1", false)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", l.HolderID)
+}
+
+func TestLockUseCase_Acquire_InvalidResource(t *testing.T) {
+	uc, _ := newTestUseCase()
+
+	_, err := uc.Acquire(context.Background(), "planet", "earth", "user-1", false)
+	assert.ErrorIs(t, err, ErrInvalidResource)
+}
+
+func TestLockUseCase_Heartbeat(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	held := heldBy("user-1", 10*time.Second)
+	acquiredAt := held.AcquiredAt
+	repo.On("Get", mock.Anything, lock.ResourceContent, "post-1").Return(held, nil)
+	repo.On("Claim", mock.Anything, held, mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	l, err := uc.Heartbeat(context.Background(), lock.ResourceContent, "post-1", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, acquiredAt, l.AcquiredAt)
+	assert.WithinDuration(t, time.Now().Add(DefaultTTL), l.ExpiresAt, time.Second)
+}
+
+func TestLockUseCase_Heartbeat_TakenOver(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	repo.On("Get", mock.Anything, lock.ResourceContent, "post-1").Return(heldBy("user-2", time.Minute), nil)
+
+	l, err := uc.Heartbeat(context.Background(), lock.ResourceContent, "post-1", "user-1")
+	assert.ErrorIs(t, err, ErrLocked)
+	assert.Equal(t, "user-2", l.HolderID)
+}
+
+func TestLockUseCase_Release(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	repo.On("Delete", mock.Anything, lock.ResourceContent, "post-1", "user-1").Return(nil)
+	repo.On("Delete", mock.Anything, lock.ResourceContent, "post-2", "user-1").Return(lock.ErrNotFound)
+
+	require.NoError(t, uc.Release(context.Background(), lock.ResourceContent, "post-1", "user-1"))
+	require.NoError(t, uc.Release(context.Background(), lock.ResourceContent, "post-2", "user-1"))
+}
+
+func TestLockUseCase_Get_Expired(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	repo.On("Get", mock.Anything, lock.ResourceContent, "post-1").Return(heldBy("user-1", -time.Second), nil)
+
+	_, err := uc.Get(context.Background(), lock.ResourceContent, "post-1")
+	assert.ErrorIs(t, err, lock.ErrNotFound)
+}
+
+func TestLockUseCase_List(t *testing.T) {
+	uc, repo := newTestUseCase()
+
+	repo.On("ListActive", mock.Anything, lock.ResourceContent, mock.AnythingOfType("time.Time")).
+		Return([]*lock.Lock{heldBy("user-1", time.Minute), heldBy("user-2", time.Minute)}, nil)
+
+	locks, err := uc.List(context.Background(), lock.ResourceContent)
+	require.NoError(t, err)
+	require.Len(t, locks, 2)
+	assert.Equal(t, "Ana Editor", locks[0].HolderName)
+	assert.Equal(t, "budi", locks[1].HolderName)
+}
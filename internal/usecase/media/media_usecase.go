@@ -0,0 +1,515 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF decoder for dimension detection
+	_ "image/jpeg" // register JPEG decoder for dimension detection
+	_ "image/png"  // register PNG decoder for dimension detection
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/utils/imaging"
+	"github.com/mashurimansur/goCMS/internal/utils/signer"
+)
+
+var (
+	// ErrEmptyFile is returned when an upload carries no bytes.
+	ErrEmptyFile = errors.New("uploaded file is empty")
+	// ErrFileTooLarge is returned when an upload exceeds the configured size limit.
+	ErrFileTooLarge = errors.New("uploaded file is too large")
+	// ErrUnsupportedType is returned when the sniffed MIME type is not in the allowlist.
+	ErrUnsupportedType = errors.New("unsupported media type")
+	// ErrNotImage is returned when an image operation targets a non-image media item.
+	ErrNotImage = errors.New("media is not an image")
+	// ErrUnknownSize is returned when a rendition name is not configured.
+	ErrUnknownSize = errors.New("unknown image size")
+	// ErrInvalidFocalPoint is returned when a focal point coordinate is outside 0..1.
+	ErrInvalidFocalPoint = errors.New("focal point must be between 0 and 1")
+)
+
+// AvatarSize is the rendition name used for user avatars when it is configured.
+const AvatarSize = "avatar"
+
+// maxSourcePixels guards against decompression bombs when decoding images for renditions.
+const maxSourcePixels = 50_000_000
+
+// decodableFormats maps image MIME types the standard library can decode to imaging formats.
+var decodableFormats = map[string]string{
+	"image/jpeg": imaging.FormatJPEG,
+	"image/png":  imaging.FormatPNG,
+	"image/gif":  imaging.FormatGIF,
+}
+
+// sniffLength is the number of leading bytes http.DetectContentType looks at.
+const sniffLength = 512
+
+// extensions maps sniffed MIME types to the file extension used for storage keys.
+var extensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"image/bmp":       ".bmp",
+	"application/pdf": ".pdf",
+	"application/zip": ".zip",
+	"audio/mpeg":      ".mp3",
+	"audio/wave":      ".wav",
+	"video/mp4":       ".mp4",
+	"video/webm":      ".webm",
+	"text/plain":      ".txt",
+}
+
+// Options configure upload limits and how public URLs are built.
+type Options struct {
+	// MaxSize is the largest accepted upload in bytes.
+	MaxSize int64
+	// AllowedTypes lists the sniffed MIME types accepted for upload.
+	AllowedTypes []string
+	// BaseURL is prefixed to the media ID to form its public URL.
+	BaseURL string
+	// Sizes are the named renditions every image offers, e.g. thumbnail or avatar.
+	Sizes map[string]imaging.Params
+	// Signer signs ad-hoc transform URLs. Without it ad-hoc transforms are rejected.
+	Signer *signer.Signer
+}
+
+// Rendition is a derived image ready to be streamed. Callers close Body.
+type Rendition struct {
+	Media       *media.Media
+	Key         string
+	ContentType string
+	Body        io.ReadSeekCloser
+}
+
+// UploadInput carries an upload stream and its client-supplied metadata.
+type UploadInput struct {
+	Filename   string
+	AltText    string
+	UploaderID string
+	Body       io.Reader
+}
+
+type UseCase interface {
+	Upload(ctx context.Context, in UploadInput) (*media.Media, error)
+	Get(ctx context.Context, id string) (*media.Media, error)
+	// Update changes the editable metadata (alt text and filename) of m.ID and refreshes m.
+	Update(ctx context.Context, m *media.Media) error
+	// Delete moves a media item to the trash. Its files are kept until it is purged.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, filter media.ListFilter) ([]*media.Media, error)
+	// Open returns the metadata and a seekable stream of the stored bytes. Callers close the stream.
+	Open(ctx context.Context, id string) (*media.Media, io.ReadSeekCloser, error)
+	SetFocalPoint(ctx context.Context, id string, x, y float64) (*media.Media, error)
+	// Resize returns the configured rendition named size, generating and caching it on first use.
+	Resize(ctx context.Context, id, size string) (*Rendition, error)
+	// SignTransform returns a URL for an ad-hoc rendition that stays valid for ttl.
+	SignTransform(ctx context.Context, id string, p imaging.Params, ttl time.Duration) (string, error)
+	// Transform returns an ad-hoc rendition after checking the signature produced by SignTransform.
+	Transform(ctx context.Context, id string, p imaging.Params, expires int64, sig string) (*Rendition, error)
+	// UploadAvatar stores an image uploaded by userID and returns the URL to use as the avatar.
+	UploadAvatar(ctx context.Context, userID, filename string, body io.Reader) (string, error)
+	// The trash of media items. Purging an item deletes its stored files too.
+	trash.Bin
+}
+
+type mediaUseCase struct {
+	repo    media.Repository
+	storage media.Storage
+	opts    Options
+	allowed map[string]bool
+}
+
+func NewMediaUseCase(repo media.Repository, storage media.Storage, opts Options) UseCase {
+	allowed := make(map[string]bool, len(opts.AllowedTypes))
+	for _, t := range opts.AllowedTypes {
+		allowed[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+
+	return &mediaUseCase{
+		repo:    repo,
+		storage: storage,
+		opts:    opts,
+		allowed: allowed,
+	}
+}
+
+func (uc *mediaUseCase) Upload(ctx context.Context, in UploadInput) (*media.Media, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(in.Body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrEmptyFile
+	}
+	head = head[:n]
+
+	mimeType := sniff(head)
+	if !uc.allowed[mimeType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, mimeType)
+	}
+
+	key := time.Now().UTC().Format("2006/01/") + uuid.New().String() + extensions[mimeType]
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head), in.Body), uc.opts.MaxSize+1)
+	size, err := uc.storage.Save(ctx, key, body)
+	if err != nil {
+		return nil, err
+	}
+	if size > uc.opts.MaxSize {
+		_ = uc.storage.Delete(ctx, key)
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrFileTooLarge, uc.opts.MaxSize)
+	}
+
+	m := &media.Media{
+		Filename:   cleanFilename(in.Filename, mimeType),
+		StorageKey: key,
+		MimeType:   mimeType,
+		Size:       size,
+		AltText:    strings.TrimSpace(in.AltText),
+		UploaderID: in.UploaderID,
+		FocalX:     0.5,
+		FocalY:     0.5,
+	}
+	if m.IsImage() {
+		m.Width, m.Height = uc.dimensions(ctx, key)
+	}
+
+	if err := uc.repo.Create(ctx, m); err != nil {
+		_ = uc.storage.Delete(ctx, key)
+		return nil, err
+	}
+
+	uc.setURL(m)
+	return m, nil
+}
+
+func (uc *mediaUseCase) Get(ctx context.Context, id string) (*media.Media, error) {
+	m, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	uc.setURL(m)
+	return m, nil
+}
+
+func (uc *mediaUseCase) Update(ctx context.Context, m *media.Media) error {
+	existing, err := uc.repo.GetByID(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := lock.Expect(m.Version, existing.Version); err != nil {
+		return err
+	}
+
+	existing.AltText = strings.TrimSpace(m.AltText)
+	if m.Filename != "" {
+		existing.Filename = cleanFilename(m.Filename, existing.MimeType)
+	}
+	if err := uc.repo.Update(ctx, existing); err != nil {
+		return err
+	}
+
+	uc.setURL(existing)
+	*m = *existing
+	return nil
+}
+
+func (uc *mediaUseCase) Delete(ctx context.Context, id string) error {
+	return uc.repo.Delete(ctx, id)
+}
+
+func (uc *mediaUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	return uc.repo.ListTrashed(ctx, filter)
+}
+
+func (uc *mediaUseCase) Restore(ctx context.Context, id string) error {
+	return uc.repo.Restore(ctx, id)
+}
+
+func (uc *mediaUseCase) Purge(ctx context.Context, id string) error {
+	m, err := uc.repo.GetTrashed(ctx, id)
+	if err != nil {
+		return err
+	}
+	return uc.remove(ctx, m)
+}
+
+// remove permanently deletes the trashed metadata row of m, its derivatives and its original file.
+func (uc *mediaUseCase) remove(ctx context.Context, m *media.Media) error {
+	if err := uc.repo.Purge(ctx, m.ID); err != nil {
+		return err
+	}
+	if err := uc.storage.DeletePrefix(ctx, derivativePrefix(m.ID)); err != nil {
+		return err
+	}
+	return uc.storage.Delete(ctx, m.StorageKey)
+}
+
+func (uc *mediaUseCase) List(ctx context.Context, filter media.ListFilter) ([]*media.Media, error) {
+	items, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range items {
+		uc.setURL(m)
+	}
+	return items, nil
+}
+
+func (uc *mediaUseCase) Open(ctx context.Context, id string) (*media.Media, io.ReadSeekCloser, error) {
+	m, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := uc.storage.Open(ctx, m.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	uc.setURL(m)
+	return m, f, nil
+}
+
+func (uc *mediaUseCase) SetFocalPoint(ctx context.Context, id string, x, y float64) (*media.Media, error) {
+	if x < 0 || x > 1 || y < 0 || y > 1 {
+		return nil, ErrInvalidFocalPoint
+	}
+
+	m, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !m.IsImage() {
+		return nil, ErrNotImage
+	}
+
+	m.FocalX, m.FocalY = x, y
+	if err := uc.repo.Update(ctx, m); err != nil {
+		return nil, err
+	}
+	// Crops made around the old focal point are no longer reachable; reclaim their space.
+	if err := uc.storage.DeletePrefix(ctx, derivativePrefix(id)); err != nil {
+		return nil, err
+	}
+
+	uc.setURL(m)
+	return m, nil
+}
+
+func (uc *mediaUseCase) Resize(ctx context.Context, id, size string) (*Rendition, error) {
+	p, ok := uc.opts.Sizes[size]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSize, size)
+	}
+
+	m, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.render(ctx, m, p)
+}
+
+func (uc *mediaUseCase) SignTransform(ctx context.Context, id string, p imaging.Params, ttl time.Duration) (string, error) {
+	if uc.opts.Signer == nil {
+		return "", signer.ErrInvalidSignature
+	}
+
+	m, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	p, err = normalizeFor(m, p)
+	if err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(ttl)
+	query := url.Values{}
+	query.Set("w", strconv.Itoa(p.Width))
+	query.Set("h", strconv.Itoa(p.Height))
+	query.Set("fit", p.Fit)
+	query.Set("fm", p.Format)
+	if p.Quality > 0 {
+		query.Set("q", strconv.Itoa(p.Quality))
+	}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("sig", uc.opts.Signer.Sign(transformPayload(id, p), expires))
+
+	return uc.opts.BaseURL + "/" + id + "/transform?" + query.Encode(), nil
+}
+
+func (uc *mediaUseCase) Transform(ctx context.Context, id string, p imaging.Params, expires int64, sig string) (*Rendition, error) {
+	if uc.opts.Signer == nil {
+		return nil, signer.ErrInvalidSignature
+	}
+
+	m, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeFor(m, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.opts.Signer.Verify(transformPayload(id, normalized), expires, sig); err != nil {
+		return nil, err
+	}
+
+	return uc.render(ctx, m, p)
+}
+
+func (uc *mediaUseCase) UploadAvatar(ctx context.Context, userID, filename string, body io.Reader) (string, error) {
+	m, err := uc.Upload(ctx, UploadInput{Filename: filename, AltText: "Avatar", UploaderID: userID, Body: body})
+	if err != nil {
+		return "", err
+	}
+	if !m.IsImage() {
+		if err := uc.repo.Delete(ctx, m.ID); err != nil {
+			return "", err
+		}
+		if err := uc.remove(ctx, m); err != nil {
+			return "", err
+		}
+		return "", ErrNotImage
+	}
+
+	if avatarURL, ok := m.Sizes[AvatarSize]; ok {
+		return avatarURL, nil
+	}
+	return m.URL, nil
+}
+
+// render returns the cached rendition of m for p, generating it when missing.
+func (uc *mediaUseCase) render(ctx context.Context, m *media.Media, p imaging.Params) (*Rendition, error) {
+	p, err := normalizeFor(m, p)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := p.Key()
+	if p.Fit == imaging.FitCover {
+		// Crops depend on the focal point, so it is part of the cache key.
+		cacheKey = fmt.Sprintf("fx%.4f-fy%.4f-%s", m.FocalX, m.FocalY, cacheKey)
+	}
+	key := derivativePrefix(m.ID) + "/" + cacheKey
+
+	body, err := uc.storage.Open(ctx, key)
+	if errors.Is(err, media.ErrNotFound) {
+		if err = uc.generate(ctx, m, p, key); err == nil {
+			body, err = uc.storage.Open(ctx, key)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	uc.setURL(m)
+	return &Rendition{Media: m, Key: key, ContentType: imaging.ContentType(p.Format), Body: body}, nil
+}
+
+func (uc *mediaUseCase) generate(ctx context.Context, m *media.Media, p imaging.Params, key string) error {
+	if int64(m.Width)*int64(m.Height) > maxSourcePixels {
+		return fmt.Errorf("%w: image exceeds %d pixels", ErrFileTooLarge, maxSourcePixels)
+	}
+
+	src, err := uc.storage.Open(ctx, m.StorageKey)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	img, _, err := image.Decode(src)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, imaging.Transform(img, p, m.FocalX, m.FocalY), p.Format, p.Quality); err != nil {
+		return err
+	}
+	_, err = uc.storage.Save(ctx, key, &buf)
+	return err
+}
+
+// normalizeFor validates p against the image m, defaulting the output format to the source format.
+func normalizeFor(m *media.Media, p imaging.Params) (imaging.Params, error) {
+	if !m.IsImage() {
+		return p, ErrNotImage
+	}
+	format, ok := decodableFormats[m.MimeType]
+	if !ok {
+		return p, fmt.Errorf("%w: cannot transform %s", ErrUnsupportedType, m.MimeType)
+	}
+	return p.Normalize(format)
+}
+
+func transformPayload(id string, p imaging.Params) string {
+	return id + "|" + p.Key()
+}
+
+func derivativePrefix(id string) string {
+	return "derivatives/" + id
+}
+
+// dimensions decodes just the image header; unknown formats report zero dimensions.
+func (uc *mediaUseCase) dimensions(ctx context.Context, key string) (int, int) {
+	f, err := uc.storage.Open(ctx, key)
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0
+	}
+	return cfg.Width, cfg.Height
+}
+
+// setURL fills the public URLs of m and, for images, its configured renditions.
+func (uc *mediaUseCase) setURL(m *media.Media) {
+	m.URL = uc.opts.BaseURL + "/" + m.ID
+	if !m.IsImage() || len(uc.opts.Sizes) == 0 {
+		return
+	}
+	m.Sizes = make(map[string]string, len(uc.opts.Sizes))
+	for name := range uc.opts.Sizes {
+		m.Sizes[name] = m.URL + "/sizes/" + name
+	}
+}
+
+// sniff detects the MIME type from content, ignoring parameters such as charset.
+func sniff(head []byte) string {
+	detected := http.DetectContentType(head)
+	mimeType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		return detected
+	}
+	return mimeType
+}
+
+// cleanFilename strips any client path and falls back to a generic name.
+func cleanFilename(name, mimeType string) string {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), `\`, "/"))
+	if name == "." || name == "/" || name == "" {
+		name = "file" + extensions[mimeType]
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
@@ -0,0 +1,346 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/utils/imaging"
+	"github.com/mashurimansur/goCMS/internal/utils/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockMediaRepository struct {
+	mock.Mock
+}
+
+func (m *MockMediaRepository) Create(ctx context.Context, item *media.Media) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) GetByID(ctx context.Context, id string) (*media.Media, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*media.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) Update(ctx context.Context, item *media.Media) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) List(ctx context.Context, filter media.ListFilter) ([]*media.Media, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*media.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) GetTrashed(ctx context.Context, id string) (*media.Media, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*media.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockMediaRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// memoryStorage is an in-memory media.Storage used to observe what the use case stores.
+type memoryStorage map[string][]byte
+
+func (s memoryStorage) Save(_ context.Context, key string, r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	s[key] = data
+	return int64(len(data)), nil
+}
+
+func (s memoryStorage) Open(_ context.Context, key string) (io.ReadSeekCloser, error) {
+	data, ok := s[key]
+	if !ok {
+		return nil, media.ErrNotFound
+	}
+	return nopCloser{bytes.NewReader(data)}, nil
+}
+
+func (s memoryStorage) Delete(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func (s memoryStorage) DeletePrefix(_ context.Context, prefix string) error {
+	for key := range s {
+		if strings.HasPrefix(key, prefix+"/") {
+			delete(s, key)
+		}
+	}
+	return nil
+}
+
+type nopCloser struct{ io.ReadSeeker }
+
+func (nopCloser) Close() error { return nil }
+
+func testOptions() Options {
+	return Options{MaxSize: 1 << 20, AllowedTypes: []string{"image/png", "application/pdf"}, BaseURL: "/api/v1/media/"}
+}
+
+func imageOptions(t *testing.T) Options {
+	s, err := signer.New("12345678901234567890123456789012")
+	require.NoError(t, err)
+
+	opts := testOptions()
+	opts.Sizes = map[string]imaging.Params{
+		"thumbnail": {Width: 16, Height: 16, Fit: imaging.FitCover},
+		AvatarSize:  {Width: 8, Height: 8, Fit: imaging.FitCover},
+	}
+	opts.Signer = s
+	return opts
+}
+
+// storedImage registers a PNG of w×h in storage and the repository mock under id.
+func storedImage(t *testing.T, repo *MockMediaRepository, storage memoryStorage, id string, w, h int) *media.Media {
+	m := &media.Media{ID: id, StorageKey: "2025/12/" + id + ".png", MimeType: "image/png", Width: w, Height: h, FocalX: 0.5, FocalY: 0.5}
+	storage[m.StorageKey] = pngBytes(t, w, h)
+	repo.On("GetByID", mock.Anything, id).Return(m, nil)
+	return m
+}
+
+func decodeRendition(t *testing.T, r *Rendition) image.Image {
+	defer r.Body.Close()
+	img, _, err := image.Decode(r.Body)
+	require.NoError(t, err)
+	return img
+}
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+func TestMediaUseCase_Upload_Image(t *testing.T) {
+	repo := new(MockMediaRepository)
+	storage := memoryStorage{}
+	uc := NewMediaUseCase(repo, storage, testOptions())
+
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*media.Media")).Run(func(args mock.Arguments) {
+		args.Get(1).(*media.Media).ID = "media-1"
+	}).Return(nil)
+
+	data := pngBytes(t, 64, 32)
+	m, err := uc.Upload(context.Background(), UploadInput{
+		Filename:   `C:\photos\cat.png`,
+		AltText:    "  A cat ",
+		UploaderID: "user-1",
+		Body:       bytes.NewReader(data),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "image/png", m.MimeType)
+	assert.Equal(t, "cat.png", m.Filename)
+	assert.Equal(t, "A cat", m.AltText)
+	assert.Equal(t, int64(len(data)), m.Size)
+	assert.Equal(t, 64, m.Width)
+	assert.Equal(t, 32, m.Height)
+	assert.Equal(t, "/api/v1/media/media-1", m.URL)
+	assert.Equal(t, 0.5, m.FocalX)
+	assert.True(t, strings.HasSuffix(m.StorageKey, ".png"))
+	assert.Equal(t, data, storage[m.StorageKey])
+}
+
+func TestMediaUseCase_Upload_SniffsInsteadOfTrustingFilename(t *testing.T) {
+	uc := NewMediaUseCase(new(MockMediaRepository), memoryStorage{}, testOptions())
+
+	_, err := uc.Upload(context.Background(), UploadInput{
+		Filename: "evil.png",
+		Body:     strings.NewReader("<html><script>alert(1)</script></html>"),
+	})
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestMediaUseCase_Upload_TooLarge(t *testing.T) {
+	storage := memoryStorage{}
+	opts := testOptions()
+	opts.MaxSize = 100
+	uc := NewMediaUseCase(new(MockMediaRepository), storage, opts)
+
+	_, err := uc.Upload(context.Background(), UploadInput{
+		Filename: "big.png",
+		Body:     bytes.NewReader(pngBytes(t, 200, 200)),
+	})
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+	assert.Empty(t, storage)
+}
+
+func TestMediaUseCase_Upload_Empty(t *testing.T) {
+	uc := NewMediaUseCase(new(MockMediaRepository), memoryStorage{}, testOptions())
+
+	_, err := uc.Upload(context.Background(), UploadInput{Filename: "empty.png", Body: strings.NewReader("")})
+	assert.ErrorIs(t, err, ErrEmptyFile)
+}
+
+func TestMediaUseCase_Delete_KeepsStoredObject(t *testing.T) {
+	repo := new(MockMediaRepository)
+	storage := memoryStorage{"2025/12/a.pdf": []byte("%PDF-1.4")}
+	uc := NewMediaUseCase(repo, storage, testOptions())
+
+	repo.On("Delete", mock.Anything, "media-1").Return(nil)
+
+	require.NoError(t, uc.Delete(context.Background(), "media-1"))
+	assert.Len(t, storage, 1)
+}
+
+func TestMediaUseCase_Purge_RemovesStoredObject(t *testing.T) {
+	repo := new(MockMediaRepository)
+	storage := memoryStorage{"2025/12/a.pdf": []byte("%PDF-1.4")}
+	uc := NewMediaUseCase(repo, storage, testOptions())
+
+	storage["derivatives/media-1/w10-h10-fit.png"] = []byte("derived")
+	repo.On("GetTrashed", mock.Anything, "media-1").Return(&media.Media{ID: "media-1", StorageKey: "2025/12/a.pdf"}, nil)
+	repo.On("Purge", mock.Anything, "media-1").Return(nil)
+
+	require.NoError(t, uc.Purge(context.Background(), "media-1"))
+	assert.Empty(t, storage)
+
+	repo.On("GetTrashed", mock.Anything, "media-2").Return(nil, trash.ErrNotFound)
+	assert.ErrorIs(t, uc.Purge(context.Background(), "media-2"), trash.ErrNotFound)
+}
+
+func TestMediaUseCase_Update_OnlyEditableFields(t *testing.T) {
+	repo := new(MockMediaRepository)
+	uc := NewMediaUseCase(repo, memoryStorage{}, testOptions())
+
+	existing := &media.Media{ID: "media-1", Filename: "a.png", StorageKey: "k", MimeType: "image/png", Size: 10}
+	repo.On("GetByID", mock.Anything, "media-1").Return(existing, nil)
+	repo.On("Update", mock.Anything, existing).Return(nil)
+
+	m := &media.Media{ID: "media-1", AltText: "New alt", MimeType: "text/html", Size: 1}
+	require.NoError(t, uc.Update(context.Background(), m))
+
+	assert.Equal(t, "New alt", m.AltText)
+	assert.Equal(t, "a.png", m.Filename)
+	assert.Equal(t, "image/png", m.MimeType)
+	assert.Equal(t, int64(10), m.Size)
+}
+
+func TestMediaUseCase_Resize_GeneratesAndCaches(t *testing.T) {
+	repo := new(MockMediaRepository)
+	storage := memoryStorage{}
+	uc := NewMediaUseCase(repo, storage, imageOptions(t))
+	storedImage(t, repo, storage, "media-1", 64, 32)
+
+	r, err := uc.Resize(context.Background(), "media-1", "thumbnail")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", r.ContentType)
+	assert.Equal(t, image.Rect(0, 0, 16, 16), decodeRendition(t, r).Bounds())
+
+	// A second request is served from the cached derivative.
+	storage[r.Key] = pngBytes(t, 1, 1)
+	r, err = uc.Resize(context.Background(), "media-1", "thumbnail")
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 1, 1), decodeRendition(t, r).Bounds())
+
+	_, err = uc.Resize(context.Background(), "media-1", "huge")
+	assert.ErrorIs(t, err, ErrUnknownSize)
+}
+
+func TestMediaUseCase_Transform_RequiresValidSignature(t *testing.T) {
+	repo := new(MockMediaRepository)
+	storage := memoryStorage{}
+	uc := NewMediaUseCase(repo, storage, imageOptions(t))
+	storedImage(t, repo, storage, "media-1", 64, 32)
+
+	signed, err := uc.SignTransform(context.Background(), "media-1", imaging.Params{Width: 20, Format: "jpg"}, time.Hour)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(signed, "/api/v1/media/media-1/transform?"))
+
+	query, err := url.ParseQuery(strings.SplitN(signed, "?", 2)[1])
+	require.NoError(t, err)
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	require.NoError(t, err)
+
+	r, err := uc.Transform(context.Background(), "media-1", imaging.Params{Width: 20, Format: "jpeg"}, expires, query.Get("sig"))
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", r.ContentType)
+	assert.Equal(t, image.Rect(0, 0, 20, 10), decodeRendition(t, r).Bounds())
+
+	_, err = uc.Transform(context.Background(), "media-1", imaging.Params{Width: 2000, Format: "jpeg"}, expires, query.Get("sig"))
+	assert.ErrorIs(t, err, signer.ErrInvalidSignature)
+}
+
+func TestMediaUseCase_SetFocalPoint(t *testing.T) {
+	repo := new(MockMediaRepository)
+	storage := memoryStorage{"derivatives/media-1/old.png": []byte("stale")}
+	uc := NewMediaUseCase(repo, storage, imageOptions(t))
+	m := storedImage(t, repo, storage, "media-1", 64, 32)
+	repo.On("Update", mock.Anything, m).Return(nil)
+
+	_, err := uc.SetFocalPoint(context.Background(), "media-1", 1.5, 0)
+	assert.ErrorIs(t, err, ErrInvalidFocalPoint)
+
+	updated, err := uc.SetFocalPoint(context.Background(), "media-1", 0.1, 0.9)
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, updated.FocalX)
+	assert.Equal(t, 0.9, updated.FocalY)
+	assert.NotContains(t, storage, "derivatives/media-1/old.png")
+}
+
+func TestMediaUseCase_UploadAvatar(t *testing.T) {
+	repo := new(MockMediaRepository)
+	uc := NewMediaUseCase(repo, memoryStorage{}, imageOptions(t))
+
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*media.Media")).Run(func(args mock.Arguments) {
+		args.Get(1).(*media.Media).ID = "media-1"
+	}).Return(nil)
+
+	avatarURL, err := uc.UploadAvatar(context.Background(), "user-1", "me.png", bytes.NewReader(pngBytes(t, 32, 32)))
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/media/media-1/sizes/avatar", avatarURL)
+}
+
+func TestMediaUseCase_UploadAvatar_RejectsNonImages(t *testing.T) {
+	repo := new(MockMediaRepository)
+	storage := memoryStorage{}
+	uc := NewMediaUseCase(repo, storage, imageOptions(t))
+
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*media.Media")).Run(func(args mock.Arguments) {
+		args.Get(1).(*media.Media).ID = "media-1"
+	}).Return(nil)
+	repo.On("Delete", mock.Anything, "media-1").Return(nil)
+	repo.On("Purge", mock.Anything, "media-1").Return(nil)
+
+	_, err := uc.UploadAvatar(context.Background(), "user-1", "cv.pdf", strings.NewReader("%PDF-1.4 resume"))
+	assert.ErrorIs(t, err, ErrNotImage)
+	assert.Empty(t, storage)
+}
@@ -0,0 +1,371 @@
+package menu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/menu"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+)
+
+var (
+	// ErrInvalidMenu is returned when a menu name is missing or malformed.
+	ErrInvalidMenu = errors.New("invalid menu")
+	// ErrInvalidItem is returned when a menu item has no label, an unknown link type or a bad target.
+	ErrInvalidItem = errors.New("invalid menu item")
+	// ErrMenuExists is returned when another menu, trashed or not, already uses the name.
+	ErrMenuExists = errors.New("menu name already exists")
+)
+
+// MaxDepth is the deepest level of nesting a menu item may have, top-level items being level 1.
+const MaxDepth = 4
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// urlSchemes lists the schemes accepted for absolute external links.
+var urlSchemes = map[string]bool{"http": true, "https": true, "mailto": true, "tel": true}
+
+type UseCase interface {
+	Create(ctx context.Context, m *menu.Menu) error
+	// Get returns a menu with its full item tree. Items linking to missing entries keep an empty URL.
+	Get(ctx context.Context, id string) (*menu.Menu, error)
+	Update(ctx context.Context, m *menu.Menu) error
+	// Delete moves a menu to the trash.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*menu.Menu, error)
+	// SetItems replaces the items of a menu with the given tree, in order. The items are part of the
+	// menu, so replacing them expects and increments the menu version like Update does.
+	SetItems(ctx context.Context, id string, version int, items []*menu.Item) (*menu.Menu, error)
+	// Resolve returns the menu named name for display to viewer. Items carry the current URL of their
+	// target; items whose target is missing, unpublished or not listed for viewer are left out
+	// together with their children.
+	Resolve(ctx context.Context, name string, viewer content.Viewer) (*menu.Menu, error)
+	// The trash of menus. Menus keep their items and their name while in the trash.
+	trash.Bin
+}
+
+type menuUseCase struct {
+	menuRepo     menu.Repository
+	contentRepo  content.Repository
+	categoryRepo category.Repository
+	permalinks   permalink.Patterns
+	categoryURL  string
+}
+
+// NewMenuUseCase creates the menu use case. categoryURL is the public path pattern of categories,
+// in which {slug} is replaced by the category slug.
+func NewMenuUseCase(menuRepo menu.Repository, contentRepo content.Repository, categoryRepo category.Repository, permalinks permalink.Patterns, categoryURL string) UseCase {
+	return &menuUseCase{
+		menuRepo:     menuRepo,
+		contentRepo:  contentRepo,
+		categoryRepo: categoryRepo,
+		permalinks:   permalinks,
+		categoryURL:  categoryURL,
+	}
+}
+
+func (uc *menuUseCase) Create(ctx context.Context, m *menu.Menu) error {
+	if err := uc.prepare(ctx, m); err != nil {
+		return err
+	}
+	return uc.menuRepo.Create(ctx, m)
+}
+
+func (uc *menuUseCase) Get(ctx context.Context, id string) (*menu.Menu, error) {
+	m, err := uc.menuRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.loadItems(ctx, m, nil); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (uc *menuUseCase) Update(ctx context.Context, m *menu.Menu) error {
+	existing, err := uc.menuRepo.GetByID(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	if m.Version, err = lock.Expect(m.Version, existing.Version); err != nil {
+		return err
+	}
+	if err := uc.prepare(ctx, m); err != nil {
+		return err
+	}
+	return uc.menuRepo.Update(ctx, m)
+}
+
+func (uc *menuUseCase) Delete(ctx context.Context, id string) error {
+	return uc.menuRepo.Delete(ctx, id)
+}
+
+func (uc *menuUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	return uc.menuRepo.ListTrashed(ctx, filter)
+}
+
+func (uc *menuUseCase) Restore(ctx context.Context, id string) error {
+	return uc.menuRepo.Restore(ctx, id)
+}
+
+func (uc *menuUseCase) Purge(ctx context.Context, id string) error {
+	return uc.menuRepo.Purge(ctx, id)
+}
+
+func (uc *menuUseCase) List(ctx context.Context) ([]*menu.Menu, error) {
+	return uc.menuRepo.List(ctx)
+}
+
+func (uc *menuUseCase) SetItems(ctx context.Context, id string, version int, items []*menu.Item) (*menu.Menu, error) {
+	m, err := uc.menuRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.Version, err = lock.Expect(version, m.Version); err != nil {
+		return nil, err
+	}
+
+	v := &itemValidator{uc: uc}
+	if err := v.flatten(ctx, items, "", 1); err != nil {
+		return nil, err
+	}
+	if err := uc.menuRepo.ReplaceItems(ctx, m, v.flat); err != nil {
+		return nil, err
+	}
+
+	if err := uc.loadItems(ctx, m, nil); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (uc *menuUseCase) Resolve(ctx context.Context, name string, viewer content.Viewer) (*menu.Menu, error) {
+	m, err := uc.menuRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.loadItems(ctx, m, &viewer); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// prepare validates the menu name, defaults the title and checks that the name is free.
+func (uc *menuUseCase) prepare(ctx context.Context, m *menu.Menu) error {
+	m.Name = strings.TrimSpace(m.Name)
+	if !namePattern.MatchString(m.Name) {
+		return fmt.Errorf("%w: name must be lower-case letters, digits, dashes or underscores", ErrInvalidMenu)
+	}
+	m.Title = strings.TrimSpace(m.Title)
+	if m.Title == "" {
+		m.Title = m.Name
+	}
+
+	taken, err := uc.menuRepo.NameInUse(ctx, m.Name, m.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrMenuExists
+	}
+	return nil
+}
+
+// permalink builds the current public path of an entry the way the content use case does.
+func (uc *menuUseCase) permalink(c *content.Content) string {
+	return uc.permalinks.Build(permalink.Fields{ID: c.ID, Type: c.Type, Slug: c.Slug, Locale: c.Locale, Date: c.PermalinkDate()})
+}
+
+// loadItems attaches the item tree of m with URLs resolved from the current state of their targets.
+// With a viewer, items pointing to targets the viewer may not see are dropped with their subtrees; a
+// nil viewer keeps every item for editors.
+func (uc *menuUseCase) loadItems(ctx context.Context, m *menu.Menu, viewer *content.Viewer) error {
+	items, err := uc.menuRepo.ListItems(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+
+	r := &resolver{uc: uc, contents: map[string]*content.Content{}}
+	kept := make(map[string]bool, len(items))
+	visible := make([]*menu.Item, 0, len(items))
+	for _, item := range items {
+		if item.ParentID != "" && !kept[item.ParentID] {
+			continue
+		}
+		ok, err := r.resolve(ctx, item, viewer)
+		if err != nil {
+			return err
+		}
+		if !ok && viewer != nil {
+			continue
+		}
+		kept[item.ID] = true
+		visible = append(visible, item)
+	}
+
+	m.Items = menu.BuildTree(visible)
+	if m.Items == nil {
+		m.Items = []*menu.Item{}
+	}
+	return nil
+}
+
+// resolver looks up menu targets, loading every category and each content entry at most once.
+type resolver struct {
+	uc         *menuUseCase
+	contents   map[string]*content.Content
+	categories map[string]*category.Category
+}
+
+// resolve fills in the URL of item and reports whether its target can be shown to viewer.
+func (r *resolver) resolve(ctx context.Context, item *menu.Item, viewer *content.Viewer) (bool, error) {
+	switch item.LinkType {
+	case menu.LinkURL:
+		return true, nil
+	case menu.LinkCategory:
+		cat, err := r.category(ctx, item.TargetID)
+		if err != nil || cat == nil {
+			return false, err
+		}
+		item.URL = strings.ReplaceAll(r.uc.categoryURL, "{slug}", cat.Slug)
+		return true, nil
+	case menu.LinkContent:
+		c, err := r.content(ctx, item.TargetID)
+		if err != nil || c == nil {
+			return false, err
+		}
+		item.URL = r.uc.permalink(c)
+		if viewer != nil && (c.Status != content.StatusPublished || c.PublishedAt.After(time.Now()) || !c.Listed(*viewer)) {
+			return false, nil
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *resolver) content(ctx context.Context, id string) (*content.Content, error) {
+	if c, ok := r.contents[id]; ok {
+		return c, nil
+	}
+	c, err := r.uc.contentRepo.GetByID(ctx, id)
+	if err != nil && !errors.Is(err, content.ErrNotFound) {
+		return nil, err
+	}
+	r.contents[id] = c
+	return c, nil
+}
+
+func (r *resolver) category(ctx context.Context, id string) (*category.Category, error) {
+	if r.categories == nil {
+		all, err := r.uc.categoryRepo.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.categories = make(map[string]*category.Category, len(all))
+		for _, cat := range all {
+			r.categories[cat.ID] = cat
+		}
+	}
+	return r.categories[id], nil
+}
+
+// itemValidator checks a submitted item tree and flattens it depth-first for storage.
+type itemValidator struct {
+	uc       *menuUseCase
+	resolver *resolver
+	flat     []*menu.Item
+}
+
+func (v *itemValidator) flatten(ctx context.Context, items []*menu.Item, parentID string, depth int) error {
+	if depth > MaxDepth {
+		return fmt.Errorf("%w: menus nest at most %d levels deep", ErrInvalidItem, MaxDepth)
+	}
+	for _, item := range items {
+		if err := v.validate(ctx, item); err != nil {
+			return err
+		}
+		children := item.Children
+		// Items get fresh IDs on every save so children can reference their parent before insertion.
+		item.ID = uuid.New().String()
+		item.ParentID = parentID
+		item.Position = len(v.flat)
+		item.Children = nil
+		v.flat = append(v.flat, item)
+
+		if err := v.flatten(ctx, children, item.ID, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate normalizes an item and checks its link. Items linking to entries or categories take the
+// target's title as label when none is given.
+func (v *itemValidator) validate(ctx context.Context, item *menu.Item) error {
+	if v.resolver == nil {
+		v.resolver = &resolver{uc: v.uc, contents: map[string]*content.Content{}}
+	}
+	item.Label = strings.TrimSpace(item.Label)
+
+	switch item.LinkType {
+	case menu.LinkURL:
+		item.TargetID = ""
+		item.URL = strings.TrimSpace(item.URL)
+		if !validURL(item.URL) {
+			return fmt.Errorf("%w: %q is not a valid link", ErrInvalidItem, item.URL)
+		}
+	case menu.LinkContent:
+		item.URL = ""
+		c, err := v.resolver.content(ctx, item.TargetID)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return fmt.Errorf("%w: content %q does not exist", ErrInvalidItem, item.TargetID)
+		}
+		if item.Label == "" {
+			item.Label = c.Title
+		}
+	case menu.LinkCategory:
+		item.URL = ""
+		cat, err := v.resolver.category(ctx, item.TargetID)
+		if err != nil {
+			return err
+		}
+		if cat == nil {
+			return fmt.Errorf("%w: category %q does not exist", ErrInvalidItem, item.TargetID)
+		}
+		if item.Label == "" {
+			item.Label = cat.Name
+		}
+	default:
+		return fmt.Errorf("%w: unknown link type %q", ErrInvalidItem, item.LinkType)
+	}
+
+	if item.Label == "" {
+		return fmt.Errorf("%w: label is required", ErrInvalidItem)
+	}
+	return nil
+}
+
+// validURL accepts site-relative paths and absolute http, https, mailto and tel links.
+func validURL(raw string) bool {
+	if strings.HasPrefix(raw, "/") {
+		return !strings.HasPrefix(raw, "//")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !urlSchemes[strings.ToLower(u.Scheme)] {
+		return false
+	}
+	return u.Host != "" || u.Opaque != ""
+}
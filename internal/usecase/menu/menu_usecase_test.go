@@ -0,0 +1,287 @@
+package menu
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/menu"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockMenuRepository struct {
+	mock.Mock
+}
+
+func (m *MockMenuRepository) Create(ctx context.Context, mn *menu.Menu) error {
+	args := m.Called(ctx, mn)
+	return args.Error(0)
+}
+
+func (m *MockMenuRepository) GetByID(ctx context.Context, id string) (*menu.Menu, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menu.Menu), args.Error(1)
+}
+
+func (m *MockMenuRepository) GetByName(ctx context.Context, name string) (*menu.Menu, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menu.Menu), args.Error(1)
+}
+
+func (m *MockMenuRepository) Update(ctx context.Context, mn *menu.Menu) error {
+	args := m.Called(ctx, mn)
+	return args.Error(0)
+}
+
+func (m *MockMenuRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMenuRepository) List(ctx context.Context) ([]*menu.Menu, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*menu.Menu), args.Error(1)
+}
+
+func (m *MockMenuRepository) ListItems(ctx context.Context, menuID string) ([]*menu.Item, error) {
+	args := m.Called(ctx, menuID)
+	return args.Get(0).([]*menu.Item), args.Error(1)
+}
+
+func (m *MockMenuRepository) ReplaceItems(ctx context.Context, mn *menu.Menu, items []*menu.Item) error {
+	args := m.Called(ctx, mn, items)
+	return args.Error(0)
+}
+
+func (m *MockMenuRepository) NameInUse(ctx context.Context, name, exceptID string) (bool, error) {
+	args := m.Called(ctx, name, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMenuRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockMenuRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMenuRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockContentRepository struct {
+	mock.Mock
+	content.Repository
+}
+
+func (m *MockContentRepository) GetByID(ctx context.Context, id string) (*content.Content, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+type MockCategoryRepository struct {
+	mock.Mock
+	category.Repository
+}
+
+func (m *MockCategoryRepository) List(ctx context.Context) ([]*category.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+var testPermalinks = permalink.Patterns{"post": "/{year}/{month}/{slug}", "page": "/{slug}"}
+
+func newTestUseCase() (UseCase, *MockMenuRepository, *MockContentRepository, *MockCategoryRepository) {
+	repo := new(MockMenuRepository)
+	contentRepo := new(MockContentRepository)
+	categoryRepo := new(MockCategoryRepository)
+	return NewMenuUseCase(repo, contentRepo, categoryRepo, testPermalinks, "/category/{slug}"), repo, contentRepo, categoryRepo
+}
+
+func TestMenuUseCase_Create(t *testing.T) {
+	uc, repo, _, _ := newTestUseCase()
+
+	repo.On("NameInUse", mock.Anything, "header", "").Return(false, nil)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	m := &menu.Menu{Name: " header "}
+	require.NoError(t, uc.Create(context.Background(), m))
+	assert.Equal(t, "header", m.Name)
+	assert.Equal(t, "header", m.Title)
+}
+
+func TestMenuUseCase_Create_Invalid(t *testing.T) {
+	uc, repo, _, _ := newTestUseCase()
+
+	assert.ErrorIs(t, uc.Create(context.Background(), &menu.Menu{Name: "Main Menu"}), ErrInvalidMenu)
+
+	repo.On("NameInUse", mock.Anything, "footer", "").Return(true, nil)
+	assert.ErrorIs(t, uc.Create(context.Background(), &menu.Menu{Name: "footer"}), ErrMenuExists)
+}
+
+func TestMenuUseCase_SetItems(t *testing.T) {
+	uc, repo, contentRepo, categoryRepo := newTestUseCase()
+
+	repo.On("GetByID", mock.Anything, "menu-1").Return(&menu.Menu{ID: "menu-1", Name: "header", Version: 2}, nil)
+	contentRepo.On("GetByID", mock.Anything, "page-1").Return(&content.Content{ID: "page-1", Type: "page", Title: "About us", Slug: "about"}, nil)
+	categoryRepo.On("List", mock.Anything).Return([]*category.Category{{ID: "cat-1", Name: "News", Slug: "news"}}, nil)
+
+	var stored []*menu.Item
+	repo.On("ReplaceItems", mock.Anything, mock.MatchedBy(func(m *menu.Menu) bool { return m.ID == "menu-1" && m.Version == 2 }), mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(2).([]*menu.Item)
+		repo.On("ListItems", mock.Anything, "menu-1").Return(stored, nil)
+	}).Return(nil)
+
+	tree := []*menu.Item{
+		{Label: "Home", LinkType: menu.LinkURL, URL: "/"},
+		{LinkType: menu.LinkContent, TargetID: "page-1", Children: []*menu.Item{
+			{LinkType: menu.LinkCategory, TargetID: "cat-1"},
+			{Label: "GitHub", LinkType: menu.LinkURL, URL: "https://github.com/example", NewTab: true},
+		}},
+	}
+	m, err := uc.SetItems(context.Background(), "menu-1", 0, tree)
+	require.NoError(t, err)
+
+	require.Len(t, stored, 4)
+	for i, item := range stored {
+		assert.Equal(t, i, item.Position)
+		assert.NotEmpty(t, item.ID)
+	}
+	assert.Equal(t, stored[1].ID, stored[2].ParentID)
+	assert.Equal(t, stored[1].ID, stored[3].ParentID)
+
+	require.Len(t, m.Items, 2)
+	about := m.Items[1]
+	assert.Equal(t, "About us", about.Label)
+	assert.Equal(t, "/about", about.URL)
+	require.Len(t, about.Children, 2)
+	assert.Equal(t, "News", about.Children[0].Label)
+	assert.Equal(t, "/category/news", about.Children[0].URL)
+}
+
+func TestMenuUseCase_SetItems_Invalid(t *testing.T) {
+	uc, repo, contentRepo, _ := newTestUseCase()
+
+	repo.On("GetByID", mock.Anything, "menu-1").Return(&menu.Menu{ID: "menu-1"}, nil)
+	contentRepo.On("GetByID", mock.Anything, "missing").Return(nil, content.ErrNotFound)
+
+	cases := [][]*menu.Item{
+		{{Label: "Bad", LinkType: "page"}},
+		{{LinkType: menu.LinkURL, URL: "/home"}},
+		{{Label: "Script", LinkType: menu.LinkURL, URL: "javascript:alert(1)"}},
+		{{Label: "Relative", LinkType: menu.LinkURL, URL: "//evil.example"}},
+		{{LinkType: menu.LinkContent, TargetID: "missing"}},
+		{{Label: "1", LinkType: menu.LinkURL, URL: "/", Children: []*menu.Item{
+			{Label: "2", LinkType: menu.LinkURL, URL: "/", Children: []*menu.Item{
+				{Label: "3", LinkType: menu.LinkURL, URL: "/", Children: []*menu.Item{
+					{Label: "4", LinkType: menu.LinkURL, URL: "/", Children: []*menu.Item{
+						{Label: "5", LinkType: menu.LinkURL, URL: "/"},
+					}},
+				}},
+			}},
+		}}},
+	}
+	for _, items := range cases {
+		_, err := uc.SetItems(context.Background(), "menu-1", 0, items)
+		assert.ErrorIs(t, err, ErrInvalidItem)
+	}
+	repo.AssertNotCalled(t, "ReplaceItems", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMenuUseCase_SetItems_VersionConflict(t *testing.T) {
+	uc, repo, _, _ := newTestUseCase()
+
+	repo.On("GetByID", mock.Anything, "menu-1").Return(&menu.Menu{ID: "menu-1", Version: 5}, nil)
+
+	_, err := uc.SetItems(context.Background(), "menu-1", 4, []*menu.Item{{Label: "Home", LinkType: menu.LinkURL, URL: "/"}})
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
+	repo.AssertNotCalled(t, "ReplaceItems", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMenuUseCase_Resolve(t *testing.T) {
+	uc, repo, contentRepo, _ := newTestUseCase()
+
+	published := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	repo.On("GetByName", mock.Anything, "header").Return(&menu.Menu{ID: "menu-1", Name: "header"}, nil)
+	repo.On("ListItems", mock.Anything, "menu-1").Return([]*menu.Item{
+		{ID: "i-1", Label: "Launch", LinkType: menu.LinkContent, TargetID: "post-1", Position: 0},
+		{ID: "i-2", Label: "Draft", LinkType: menu.LinkContent, TargetID: "post-2", Position: 1},
+		{ID: "i-3", ParentID: "i-2", Label: "Under draft", LinkType: menu.LinkURL, URL: "/x", Position: 2},
+		{ID: "i-4", Label: "Gone", LinkType: menu.LinkContent, TargetID: "post-3", Position: 3},
+		{ID: "i-5", Label: "Contact", LinkType: menu.LinkURL, URL: "mailto:hi@example.com", Position: 4},
+		{ID: "i-6", Label: "Members", LinkType: menu.LinkContent, TargetID: "post-4", Position: 5},
+	}, nil)
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(&content.Content{ID: "post-1", Type: "post", Slug: "launch-renamed", Status: content.StatusPublished, PublishedAt: published}, nil)
+	contentRepo.On("GetByID", mock.Anything, "post-2").Return(&content.Content{ID: "post-2", Type: "post", Slug: "wip", Status: content.StatusDraft}, nil)
+	contentRepo.On("GetByID", mock.Anything, "post-3").Return(nil, content.ErrNotFound)
+	contentRepo.On("GetByID", mock.Anything, "post-4").Return(&content.Content{
+		ID: "post-4", Type: "post", Slug: "members", Status: content.StatusPublished, PublishedAt: published,
+		Visibility: content.VisibilityAuthenticated,
+	}, nil)
+
+	m, err := uc.Resolve(context.Background(), "header", content.Viewer{})
+	require.NoError(t, err)
+	require.Len(t, m.Items, 2)
+	assert.Equal(t, "/2025/03/launch-renamed", m.Items[0].URL)
+	assert.Equal(t, "Contact", m.Items[1].Label)
+
+	m, err = uc.Resolve(context.Background(), "header", content.Viewer{UserID: "user-1"})
+	require.NoError(t, err)
+	require.Len(t, m.Items, 3)
+	assert.Equal(t, "Members", m.Items[2].Label)
+}
+
+func TestMenuUseCase_Resolve_NotFound(t *testing.T) {
+	uc, repo, _, _ := newTestUseCase()
+
+	repo.On("GetByName", mock.Anything, "missing").Return(nil, menu.ErrNotFound)
+
+	_, err := uc.Resolve(context.Background(), "missing", content.Viewer{})
+	assert.ErrorIs(t, err, menu.ErrNotFound)
+}
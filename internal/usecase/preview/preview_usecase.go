@@ -0,0 +1,174 @@
+package preview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/preview"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	"github.com/mashurimansur/goCMS/internal/utils/token"
+)
+
+var (
+	// ErrInvalidDuration is returned when a preview link would not expire within the allowed time.
+	ErrInvalidDuration = errors.New("invalid preview duration")
+	// ErrInvalidPreview is returned for preview tokens that are malformed, expired or revoked.
+	ErrInvalidPreview = errors.New("invalid preview link")
+	// ErrPreviewOutdated is returned when the entry changed since its preview link was created.
+	ErrPreviewOutdated = errors.New("preview link is outdated")
+)
+
+// Defaults applied to zero Options fields.
+const (
+	DefaultDuration    = 72 * time.Hour
+	DefaultMaxDuration = 30 * 24 * time.Hour
+)
+
+// scopePrefix marks tokens that grant a preview, scoped as "preview:<content id>:<revision>".
+const scopePrefix = "preview:"
+
+// Options configures preview links.
+type Options struct {
+	// URL is the page previews are viewed on. The token is passed to it as the token query parameter.
+	URL string
+	// Duration is how long links stay valid unless another duration is requested.
+	Duration time.Duration
+	// MaxDuration is the longest duration a link can be requested for.
+	MaxDuration time.Duration
+}
+
+type UseCase interface {
+	// Create issues a preview link to the current revision of an entry, valid for duration, or the
+	// default duration when zero.
+	Create(ctx context.Context, contentID, userID string, duration time.Duration) (*preview.Grant, error)
+	List(ctx context.Context, contentID string) ([]*preview.Link, error)
+	Revoke(ctx context.Context, id string) error
+	// Resolve returns the entry a preview token grants access to, whatever its status.
+	Resolve(ctx context.Context, tokenString string) (*content.Content, error)
+}
+
+type previewUseCase struct {
+	previewRepo preview.Repository
+	contents    contentusecase.UseCase
+	snippets    content.Snippets
+	tokenMaker  token.Maker
+	opts        Options
+}
+
+func NewPreviewUseCase(previewRepo preview.Repository, contents contentusecase.UseCase, snippets content.Snippets, tokenMaker token.Maker, opts Options) UseCase {
+	if opts.Duration <= 0 {
+		opts.Duration = DefaultDuration
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = DefaultMaxDuration
+	}
+	return &previewUseCase{
+		previewRepo: previewRepo,
+		contents:    contents,
+		snippets:    snippets,
+		tokenMaker:  tokenMaker,
+		opts:        opts,
+	}
+}
+
+func (uc *previewUseCase) Create(ctx context.Context, contentID, userID string, duration time.Duration) (*preview.Grant, error) {
+	if duration == 0 {
+		duration = uc.opts.Duration
+	}
+	if duration < 0 || duration > uc.opts.MaxDuration {
+		return nil, fmt.Errorf("%w: must be positive and at most %s", ErrInvalidDuration, uc.opts.MaxDuration)
+	}
+
+	c, err := uc.contents.Get(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := c.Revision()
+	tokenString, payload, err := uc.tokenMaker.CreateScopedToken(userID, scopePrefix+c.ID+":"+revision, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &preview.Link{
+		ID:        payload.ID.String(),
+		ContentID: c.ID,
+		Revision:  revision,
+		CreatedBy: userID,
+		ExpiresAt: payload.ExpiredAt,
+	}
+	if err := uc.previewRepo.Create(ctx, l); err != nil {
+		return nil, err
+	}
+
+	return &preview.Grant{Link: l, Token: tokenString, URL: uc.url(tokenString)}, nil
+}
+
+func (uc *previewUseCase) List(ctx context.Context, contentID string) ([]*preview.Link, error) {
+	if _, err := uc.contents.Get(ctx, contentID); err != nil {
+		return nil, err
+	}
+	return uc.previewRepo.ListByContent(ctx, contentID)
+}
+
+func (uc *previewUseCase) Revoke(ctx context.Context, id string) error {
+	l, err := uc.previewRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !l.RevokedAt.IsZero() {
+		return nil
+	}
+	return uc.previewRepo.Revoke(ctx, id, time.Now())
+}
+
+func (uc *previewUseCase) Resolve(ctx context.Context, tokenString string) (*content.Content, error) {
+	payload, err := uc.tokenMaker.VerifyToken(tokenString)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPreview, err)
+	}
+	contentID, revision, ok := strings.Cut(strings.TrimPrefix(payload.Scope, scopePrefix), ":")
+	if !ok || !strings.HasPrefix(payload.Scope, scopePrefix) {
+		return nil, fmt.Errorf("%w: token is not a preview token", ErrInvalidPreview)
+	}
+
+	// The stored link is what makes tokens revocable before they expire.
+	l, err := uc.previewRepo.GetByID(ctx, payload.ID.String())
+	if errors.Is(err, preview.ErrNotFound) {
+		return nil, fmt.Errorf("%w: link does not exist", ErrInvalidPreview)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !l.Active(time.Now()) || l.ContentID != contentID || l.Revision != revision {
+		return nil, fmt.Errorf("%w: link is no longer active", ErrInvalidPreview)
+	}
+
+	c, err := uc.contents.Get(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Revision() != revision {
+		return nil, ErrPreviewOutdated
+	}
+	// Snippets are expanded after the revision check: editing a snippet does not outdate the link.
+	if uc.snippets != nil {
+		if err := uc.snippets.Expand(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (uc *previewUseCase) url(tokenString string) string {
+	separator := "?"
+	if strings.Contains(uc.opts.URL, "?") {
+		separator = "&"
+	}
+	return uc.opts.URL + separator + "token=" + url.QueryEscape(tokenString)
+}
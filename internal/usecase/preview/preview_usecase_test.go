@@ -0,0 +1,190 @@
+package preview
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/preview"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	"github.com/mashurimansur/goCMS/internal/utils/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPreviewRepository struct {
+	mock.Mock
+}
+
+func (m *MockPreviewRepository) Create(ctx context.Context, l *preview.Link) error {
+	args := m.Called(ctx, l)
+	return args.Error(0)
+}
+
+func (m *MockPreviewRepository) GetByID(ctx context.Context, id string) (*preview.Link, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*preview.Link), args.Error(1)
+}
+
+func (m *MockPreviewRepository) ListByContent(ctx context.Context, contentID string) ([]*preview.Link, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).([]*preview.Link), args.Error(1)
+}
+
+func (m *MockPreviewRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+type MockContentUseCase struct {
+	mock.Mock
+	contentusecase.UseCase
+}
+
+func (m *MockContentUseCase) Get(ctx context.Context, id string) (*content.Content, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+var testOptions = Options{URL: "https://example.com/api/v1/preview"}
+
+func newTestUseCase(t *testing.T, opts Options) (UseCase, *MockPreviewRepository, *MockContentUseCase, token.Maker) {
+	maker, err := token.NewPasetoMaker(token.RandomString(32))
+	require.NoError(t, err)
+	repo := new(MockPreviewRepository)
+	contents := new(MockContentUseCase)
+	return NewPreviewUseCase(repo, contents, nil, maker, opts), repo, contents, maker
+}
+
+func draft() *content.Content {
+	return &content.Content{
+		ID: "post-1", Type: "post", Title: "Launch", Status: content.StatusDraft,
+		Version: 3,
+	}
+}
+
+// createLink issues a preview link to draft.
+func createLink(t *testing.T, uc UseCase, repo *MockPreviewRepository, contents *MockContentUseCase) *preview.Grant {
+	contents.On("Get", mock.Anything, "post-1").Return(draft(), nil)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*preview.Link")).Return(nil).Once()
+
+	grant, err := uc.Create(context.Background(), "post-1", "user-1", 0)
+	require.NoError(t, err)
+	return grant
+}
+
+func TestPreviewUseCase_Create(t *testing.T) {
+	uc, repo, contents, maker := newTestUseCase(t, testOptions)
+
+	grant := createLink(t, uc, repo, contents)
+
+	assert.Equal(t, "post-1", grant.Link.ContentID)
+	assert.Equal(t, draft().Revision(), grant.Link.Revision)
+	assert.Equal(t, "user-1", grant.Link.CreatedBy)
+	assert.WithinDuration(t, time.Now().Add(DefaultDuration), grant.Link.ExpiresAt, time.Minute)
+
+	u, err := url.Parse(grant.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/preview", u.Path)
+	assert.Equal(t, grant.Token, u.Query().Get("token"))
+
+	payload, err := maker.VerifyToken(grant.Token)
+	require.NoError(t, err)
+	assert.Equal(t, grant.Link.ID, payload.ID.String())
+	assert.Equal(t, "preview:post-1:"+draft().Revision(), payload.Scope)
+}
+
+func TestPreviewUseCase_Create_InvalidDuration(t *testing.T) {
+	uc, _, _, _ := newTestUseCase(t, testOptions)
+
+	_, err := uc.Create(context.Background(), "post-1", "user-1", 31*24*time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidDuration)
+
+	_, err = uc.Create(context.Background(), "post-1", "user-1", -time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidDuration)
+}
+
+func TestPreviewUseCase_Resolve(t *testing.T) {
+	uc, repo, contents, _ := newTestUseCase(t, testOptions)
+	grant := createLink(t, uc, repo, contents)
+
+	repo.On("GetByID", mock.Anything, grant.Link.ID).Return(grant.Link, nil)
+
+	c, err := uc.Resolve(context.Background(), grant.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "post-1", c.ID)
+	assert.Equal(t, content.StatusDraft, c.Status)
+}
+
+func TestPreviewUseCase_Resolve_Revoked(t *testing.T) {
+	uc, repo, contents, _ := newTestUseCase(t, testOptions)
+	grant := createLink(t, uc, repo, contents)
+
+	revoked := *grant.Link
+	revoked.RevokedAt = time.Now()
+	repo.On("GetByID", mock.Anything, grant.Link.ID).Return(&revoked, nil)
+
+	_, err := uc.Resolve(context.Background(), grant.Token)
+	assert.ErrorIs(t, err, ErrInvalidPreview)
+}
+
+func TestPreviewUseCase_Resolve_Outdated(t *testing.T) {
+	uc, repo, contents, _ := newTestUseCase(t, testOptions)
+	grant := createLink(t, uc, repo, contents)
+
+	edited := draft()
+	edited.Version++
+	contents.ExpectedCalls = nil
+	contents.On("Get", mock.Anything, "post-1").Return(edited, nil)
+	repo.On("GetByID", mock.Anything, grant.Link.ID).Return(grant.Link, nil)
+
+	_, err := uc.Resolve(context.Background(), grant.Token)
+	assert.ErrorIs(t, err, ErrPreviewOutdated)
+}
+
+func TestPreviewUseCase_Resolve_InvalidToken(t *testing.T) {
+	uc, _, _, maker := newTestUseCase(t, testOptions)
+
+	_, err := uc.Resolve(context.Background(), "garbage")
+	assert.ErrorIs(t, err, ErrInvalidPreview)
+
+	// Access tokens do not open previews.
+	access, _, err := maker.CreateToken("user-1", time.Minute)
+	require.NoError(t, err)
+	_, err = uc.Resolve(context.Background(), access)
+	assert.ErrorIs(t, err, ErrInvalidPreview)
+
+	expired, _, err := maker.CreateScopedToken("user-1", "preview:post-1:1", -time.Minute)
+	require.NoError(t, err)
+	_, err = uc.Resolve(context.Background(), expired)
+	assert.ErrorIs(t, err, ErrInvalidPreview)
+}
+
+func TestPreviewUseCase_Revoke(t *testing.T) {
+	uc, repo, _, _ := newTestUseCase(t, testOptions)
+
+	repo.On("GetByID", mock.Anything, "link-1").Return(&preview.Link{ID: "link-1"}, nil)
+	repo.On("Revoke", mock.Anything, "link-1", mock.AnythingOfType("time.Time")).Return(nil)
+	require.NoError(t, uc.Revoke(context.Background(), "link-1"))
+
+	repo.On("GetByID", mock.Anything, "link-2").Return(&preview.Link{ID: "link-2", RevokedAt: time.Now()}, nil)
+	require.NoError(t, uc.Revoke(context.Background(), "link-2"))
+	repo.AssertNumberOfCalls(t, "Revoke", 1)
+}
+
+func TestPreviewUseCase_URLWithQuery(t *testing.T) {
+	uc, repo, contents, _ := newTestUseCase(t, Options{URL: "https://front.example.com/preview?mode=draft"})
+	grant := createLink(t, uc, repo, contents)
+
+	assert.True(t, strings.HasPrefix(grant.URL, "https://front.example.com/preview?mode=draft&token="))
+}
@@ -0,0 +1,114 @@
+package redirect
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/mashurimansur/goCMS/internal/domain/redirect"
+)
+
+// compiledRule is a pattern rule prepared for matching.
+type compiledRule struct {
+	rule    *redirect.Rule
+	pattern *regexp.Regexp
+}
+
+// matcher holds an immutable, pre-compiled view of the enabled rules.
+// Exact rules are indexed by path; pattern rules are evaluated in position order.
+type matcher struct {
+	exact    map[string]*redirect.Rule
+	patterns []compiledRule
+}
+
+func newMatcher(rules []*redirect.Rule) (*matcher, error) {
+	m := &matcher{exact: make(map[string]*redirect.Rule)}
+	for _, rule := range rules {
+		if rule.MatchType == redirect.MatchExact {
+			key := normalizePath(rule.Source)
+			if _, exists := m.exact[key]; !exists {
+				m.exact[key] = rule
+			}
+			continue
+		}
+
+		pattern, err := compile(rule)
+		if err != nil {
+			return nil, err
+		}
+		m.patterns = append(m.patterns, compiledRule{rule: rule, pattern: pattern})
+	}
+	return m, nil
+}
+
+// match returns the rule that applies to path and the expanded target.
+func (m *matcher) match(path string) (*redirect.Rule, string, bool) {
+	if rule, ok := m.exact[normalizePath(path)]; ok {
+		return rule, rule.Target, true
+	}
+
+	for _, c := range m.patterns {
+		submatches := c.pattern.FindStringSubmatchIndex(path)
+		if submatches == nil {
+			continue
+		}
+		target := string(c.pattern.ExpandString(nil, c.rule.Target, path, submatches))
+		return c.rule, target, true
+	}
+
+	return nil, "", false
+}
+
+// compile turns wildcard and regex sources into anchored regular expressions.
+func compile(rule *redirect.Rule) (*regexp.Regexp, error) {
+	switch rule.MatchType {
+	case redirect.MatchWildcard:
+		parts := strings.Split(rule.Source, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		return regexp.Compile("^" + strings.Join(parts, "(.*)") + "$")
+	case redirect.MatchRegex:
+		pattern, err := regexp.Compile(rule.Source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+		return pattern, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown match type %q", ErrInvalidRule, rule.MatchType)
+	}
+}
+
+// validate checks that a rule can be compiled and carries a sensible status and target.
+func validate(rule *redirect.Rule) error {
+	switch rule.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound:
+		if rule.Target == "" {
+			return fmt.Errorf("%w: target is required for status %d", ErrInvalidRule, rule.StatusCode)
+		}
+	case http.StatusGone:
+	default:
+		return fmt.Errorf("%w: status must be 301, 302 or 410", ErrInvalidRule)
+	}
+
+	if !strings.HasPrefix(rule.Source, "/") && rule.MatchType != redirect.MatchRegex {
+		return fmt.Errorf("%w: source must start with /", ErrInvalidRule)
+	}
+	if rule.MatchType == redirect.MatchExact {
+		if normalizePath(rule.Source) == normalizePath(rule.Target) {
+			return fmt.Errorf("%w: source and target are the same", ErrInvalidRule)
+		}
+		return nil
+	}
+
+	_, err := compile(rule)
+	return err
+}
+
+func normalizePath(path string) string {
+	if len(path) > 1 {
+		return strings.TrimRight(path, "/")
+	}
+	return path
+}
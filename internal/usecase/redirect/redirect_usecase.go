@@ -0,0 +1,152 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/redirect"
+)
+
+// ErrInvalidRule is returned when a redirect rule cannot be compiled or is inconsistent.
+var ErrInvalidRule = errors.New("invalid redirect rule")
+
+type UseCase interface {
+	Create(ctx context.Context, r *redirect.Rule) error
+	Get(ctx context.Context, id string) (*redirect.Rule, error)
+	Update(ctx context.Context, r *redirect.Rule) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, limit, offset int) ([]*redirect.Rule, error)
+	// Reload rebuilds the in-memory matcher from the enabled rules in the repository.
+	Reload(ctx context.Context) error
+	// Redirect reports where a request for path should be sent and counts the hit.
+	Redirect(path string) (target string, status int, ok bool)
+	// FlushHits persists hit counters accumulated since the previous flush.
+	FlushHits(ctx context.Context) error
+}
+
+type redirectUseCase struct {
+	repo redirect.Repository
+
+	mu      sync.RWMutex
+	matcher *matcher
+
+	hitsMu  sync.Mutex
+	hits    map[string]int64
+	lastHit map[string]time.Time
+}
+
+func NewRedirectUseCase(repo redirect.Repository) UseCase {
+	return &redirectUseCase{
+		repo:    repo,
+		matcher: &matcher{exact: map[string]*redirect.Rule{}},
+		hits:    make(map[string]int64),
+		lastHit: make(map[string]time.Time),
+	}
+}
+
+func (uc *redirectUseCase) Create(ctx context.Context, r *redirect.Rule) error {
+	prepare(r)
+	if err := validate(r); err != nil {
+		return err
+	}
+	if err := uc.repo.Create(ctx, r); err != nil {
+		return err
+	}
+	return uc.Reload(ctx)
+}
+
+func (uc *redirectUseCase) Get(ctx context.Context, id string) (*redirect.Rule, error) {
+	return uc.repo.GetByID(ctx, id)
+}
+
+func (uc *redirectUseCase) Update(ctx context.Context, r *redirect.Rule) error {
+	prepare(r)
+	if err := validate(r); err != nil {
+		return err
+	}
+	existing, err := uc.repo.GetByID(ctx, r.ID)
+	if err != nil {
+		return err
+	}
+	if r.Version, err = lock.Expect(r.Version, existing.Version); err != nil {
+		return err
+	}
+	if err := uc.repo.Update(ctx, r); err != nil {
+		return err
+	}
+	return uc.Reload(ctx)
+}
+
+func (uc *redirectUseCase) Delete(ctx context.Context, id string) error {
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return uc.Reload(ctx)
+}
+
+func (uc *redirectUseCase) List(ctx context.Context, limit, offset int) ([]*redirect.Rule, error) {
+	return uc.repo.List(ctx, limit, offset)
+}
+
+func (uc *redirectUseCase) Reload(ctx context.Context) error {
+	rules, err := uc.repo.ListEnabled(ctx)
+	if err != nil {
+		return err
+	}
+	m, err := newMatcher(rules)
+	if err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	uc.matcher = m
+	uc.mu.Unlock()
+	return nil
+}
+
+func (uc *redirectUseCase) Redirect(path string) (string, int, bool) {
+	uc.mu.RLock()
+	m := uc.matcher
+	uc.mu.RUnlock()
+
+	rule, target, ok := m.match(path)
+	if !ok {
+		return "", 0, false
+	}
+
+	uc.hitsMu.Lock()
+	uc.hits[rule.ID]++
+	uc.lastHit[rule.ID] = time.Now()
+	uc.hitsMu.Unlock()
+
+	return target, rule.StatusCode, true
+}
+
+func (uc *redirectUseCase) FlushHits(ctx context.Context) error {
+	uc.hitsMu.Lock()
+	hits, lastHit := uc.hits, uc.lastHit
+	uc.hits = make(map[string]int64)
+	uc.lastHit = make(map[string]time.Time)
+	uc.hitsMu.Unlock()
+
+	var errs []error
+	for id, count := range hits {
+		if err := uc.repo.IncrementHits(ctx, id, count, lastHit[id]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func prepare(r *redirect.Rule) {
+	if r.MatchType == "" {
+		r.MatchType = redirect.MatchExact
+	}
+	if r.StatusCode == 0 {
+		r.StatusCode = http.StatusMovedPermanently
+	}
+}
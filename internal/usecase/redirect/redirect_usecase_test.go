@@ -0,0 +1,146 @@
+package redirect
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/redirect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRedirectRepository struct {
+	mock.Mock
+}
+
+func (m *MockRedirectRepository) Create(ctx context.Context, r *redirect.Rule) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRedirectRepository) GetByID(ctx context.Context, id string) (*redirect.Rule, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*redirect.Rule), args.Error(1)
+}
+
+func (m *MockRedirectRepository) Update(ctx context.Context, r *redirect.Rule) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRedirectRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRedirectRepository) List(ctx context.Context, limit, offset int) ([]*redirect.Rule, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*redirect.Rule), args.Error(1)
+}
+
+func (m *MockRedirectRepository) ListEnabled(ctx context.Context) ([]*redirect.Rule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*redirect.Rule), args.Error(1)
+}
+
+func (m *MockRedirectRepository) IncrementHits(ctx context.Context, id string, hits int64, at time.Time) error {
+	args := m.Called(ctx, id, hits, at)
+	return args.Error(0)
+}
+
+func ruleFixtures() []*redirect.Rule {
+	return []*redirect.Rule{
+		{ID: "exact", Source: "/about-us/", MatchType: redirect.MatchExact, Target: "/about", StatusCode: http.StatusMovedPermanently},
+		{ID: "wildcard", Source: "/blog/*/comments", MatchType: redirect.MatchWildcard, Target: "/posts/$1#comments", StatusCode: http.StatusFound},
+		{ID: "regex", Source: `^/archive/(\d{4})/(\d{2})$`, MatchType: redirect.MatchRegex, Target: "/$1/$2", StatusCode: http.StatusMovedPermanently},
+		{ID: "gone", Source: "/legacy/*", MatchType: redirect.MatchWildcard, StatusCode: http.StatusGone},
+	}
+}
+
+func TestRedirectUseCase_Redirect(t *testing.T) {
+	repo := new(MockRedirectRepository)
+	uc := NewRedirectUseCase(repo)
+
+	repo.On("ListEnabled", mock.Anything).Return(ruleFixtures(), nil)
+	require.NoError(t, uc.Reload(context.Background()))
+
+	testCases := []struct {
+		path   string
+		target string
+		status int
+		ok     bool
+	}{
+		{path: "/about-us", target: "/about", status: http.StatusMovedPermanently, ok: true},
+		{path: "/blog/hello-world/comments", target: "/posts/hello-world#comments", status: http.StatusFound, ok: true},
+		{path: "/archive/2019/07", target: "/2019/07", status: http.StatusMovedPermanently, ok: true},
+		{path: "/legacy/anything/here", status: http.StatusGone, ok: true},
+		{path: "/archive/2019/7", ok: false},
+		{path: "/unknown", ok: false},
+	}
+
+	for _, tc := range testCases {
+		target, status, ok := uc.Redirect(tc.path)
+		assert.Equal(t, tc.ok, ok, tc.path)
+		assert.Equal(t, tc.target, target, tc.path)
+		assert.Equal(t, tc.status, status, tc.path)
+	}
+}
+
+func TestRedirectUseCase_FlushHits(t *testing.T) {
+	repo := new(MockRedirectRepository)
+	uc := NewRedirectUseCase(repo)
+
+	repo.On("ListEnabled", mock.Anything).Return(ruleFixtures(), nil)
+	require.NoError(t, uc.Reload(context.Background()))
+
+	uc.Redirect("/about-us")
+	uc.Redirect("/about-us/")
+	uc.Redirect("/archive/2020/01")
+
+	repo.On("IncrementHits", mock.Anything, "exact", int64(2), mock.AnythingOfType("time.Time")).Return(nil).Once()
+	repo.On("IncrementHits", mock.Anything, "regex", int64(1), mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	require.NoError(t, uc.FlushHits(context.Background()))
+	repo.AssertExpectations(t)
+
+	// Counters are reset after a flush.
+	require.NoError(t, uc.FlushHits(context.Background()))
+	repo.AssertNumberOfCalls(t, "IncrementHits", 2)
+}
+
+func TestRedirectUseCase_Create_ReloadsMatcher(t *testing.T) {
+	repo := new(MockRedirectRepository)
+	uc := NewRedirectUseCase(repo)
+
+	rule := &redirect.Rule{Source: "/old", Target: "/new", Enabled: true}
+	repo.On("Create", mock.Anything, rule).Return(nil)
+	repo.On("ListEnabled", mock.Anything).Return([]*redirect.Rule{rule}, nil)
+
+	require.NoError(t, uc.Create(context.Background(), rule))
+	assert.Equal(t, redirect.MatchExact, rule.MatchType)
+	assert.Equal(t, http.StatusMovedPermanently, rule.StatusCode)
+
+	target, _, ok := uc.Redirect("/old")
+	assert.True(t, ok)
+	assert.Equal(t, "/new", target)
+}
+
+func TestRedirectUseCase_Create_Validation(t *testing.T) {
+	uc := NewRedirectUseCase(new(MockRedirectRepository))
+
+	invalid := []*redirect.Rule{
+		{Source: "/old", Target: "/new", StatusCode: http.StatusTemporaryRedirect},
+		{Source: "/old", StatusCode: http.StatusMovedPermanently},
+		{Source: "old", Target: "/new"},
+		{Source: "/same/", Target: "/same"},
+		{Source: "(unclosed", MatchType: redirect.MatchRegex, Target: "/new"},
+		{Source: "/x", MatchType: "fuzzy", Target: "/new"},
+	}
+
+	for _, rule := range invalid {
+		assert.ErrorIs(t, uc.Create(context.Background(), rule), ErrInvalidRule, rule.Source)
+	}
+}
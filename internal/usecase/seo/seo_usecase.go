@@ -0,0 +1,299 @@
+package seo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/seo"
+	mediausecase "github.com/mashurimansur/goCMS/internal/usecase/media"
+	"github.com/mashurimansur/goCMS/internal/utils/fulltext"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+)
+
+// ErrInvalidMetadata is returned when SEO fields are too long, the canonical URL is malformed or the
+// Open Graph image is not an image.
+var ErrInvalidMetadata = errors.New("invalid seo metadata")
+
+// MaxSitemapURLs is the most URLs the sitemap protocol allows in one sitemap file.
+const MaxSitemapURLs = 50000
+
+// Field limits, in characters.
+const (
+	maxTitleLength       = 255
+	maxDescriptionLength = 500
+	// descriptionLength is the length of descriptions derived from the entry body, about what
+	// search engines show in results.
+	descriptionLength = 160
+)
+
+// ogImageSize is the media rendition preferred for Open Graph images.
+const ogImageSize = "large"
+
+// Options configures the site-wide SEO settings.
+type Options struct {
+	// SiteURL is the absolute URL of the public site, e.g. "https://example.com".
+	SiteURL  string
+	SiteName string
+	// AllowIndexing set to false asks every crawler to stay away, e.g. on staging sites.
+	AllowIndexing bool
+	// Disallow lists the paths robots.txt keeps crawlers out of.
+	Disallow []string
+	// SitemapSize is the number of URLs per sitemap file. It defaults to MaxSitemapURLs.
+	SitemapSize int
+}
+
+type UseCase interface {
+	// Get returns the stored SEO fields of an entry, blank when none were set.
+	Get(ctx context.Context, contentID string) (*seo.Metadata, error)
+	Save(ctx context.Context, m *seo.Metadata) error
+	// Tags resolves the head tags of any entry, filling blank fields with defaults derived from it.
+	Tags(ctx context.Context, contentID string) (*seo.Tags, error)
+	// PublishedTags resolves the head tags of a published entry viewer may see. Teasers are
+	// described by their excerpt only.
+	PublishedTags(ctx context.Context, contentID string, viewer content.Viewer) (*seo.Tags, error)
+	// Sitemap returns the sitemap of the published entries: the URL set itself when it fits in one
+	// file, otherwise an index of its pages.
+	Sitemap(ctx context.Context) (*seo.Sitemap, error)
+	// SitemapPage returns page n, counted from 1, of a sitemap split into several files.
+	SitemapPage(ctx context.Context, n int) (*seo.Sitemap, error)
+	// Robots returns the robots.txt document.
+	Robots() string
+}
+
+type seoUseCase struct {
+	seoRepo     seo.Repository
+	contentRepo content.Repository
+	media       mediausecase.UseCase
+	permalinks  permalink.Patterns
+	opts        Options
+}
+
+func NewSEOUseCase(seoRepo seo.Repository, contentRepo content.Repository, media mediausecase.UseCase, permalinks permalink.Patterns, opts Options) UseCase {
+	opts.SiteURL = strings.TrimSuffix(opts.SiteURL, "/")
+	if opts.SitemapSize <= 0 || opts.SitemapSize > MaxSitemapURLs {
+		opts.SitemapSize = MaxSitemapURLs
+	}
+	return &seoUseCase{
+		seoRepo:     seoRepo,
+		contentRepo: contentRepo,
+		media:       media,
+		permalinks:  permalinks,
+		opts:        opts,
+	}
+}
+
+func (uc *seoUseCase) Get(ctx context.Context, contentID string) (*seo.Metadata, error) {
+	if _, err := uc.contentRepo.GetByID(ctx, contentID); err != nil {
+		return nil, err
+	}
+	return uc.metadata(ctx, contentID)
+}
+
+func (uc *seoUseCase) Save(ctx context.Context, m *seo.Metadata) error {
+	if _, err := uc.contentRepo.GetByID(ctx, m.ContentID); err != nil {
+		return err
+	}
+
+	m.MetaTitle = strings.TrimSpace(m.MetaTitle)
+	m.MetaDescription = strings.TrimSpace(m.MetaDescription)
+	m.CanonicalURL = strings.TrimSpace(m.CanonicalURL)
+	m.OGImageID = strings.TrimSpace(m.OGImageID)
+	if utf8.RuneCountInString(m.MetaTitle) > maxTitleLength {
+		return fmt.Errorf("%w: meta title is longer than %d characters", ErrInvalidMetadata, maxTitleLength)
+	}
+	if utf8.RuneCountInString(m.MetaDescription) > maxDescriptionLength {
+		return fmt.Errorf("%w: meta description is longer than %d characters", ErrInvalidMetadata, maxDescriptionLength)
+	}
+	if m.CanonicalURL != "" && !validCanonical(m.CanonicalURL) {
+		return fmt.Errorf("%w: canonical URL must be an absolute http(s) URL or a path", ErrInvalidMetadata)
+	}
+	if m.OGImageID != "" {
+		item, err := uc.media.Get(ctx, m.OGImageID)
+		if errors.Is(err, media.ErrNotFound) {
+			return fmt.Errorf("%w: media %q does not exist", ErrInvalidMetadata, m.OGImageID)
+		}
+		if err != nil {
+			return err
+		}
+		if !item.IsImage() {
+			return fmt.Errorf("%w: open graph image must be an image", ErrInvalidMetadata)
+		}
+	}
+
+	return uc.seoRepo.Save(ctx, m)
+}
+
+func (uc *seoUseCase) Tags(ctx context.Context, contentID string) (*seo.Tags, error) {
+	c, err := uc.contentRepo.GetByID(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.tags(ctx, c)
+}
+
+func (uc *seoUseCase) PublishedTags(ctx context.Context, contentID string, viewer content.Viewer) (*seo.Tags, error) {
+	c, err := uc.contentRepo.GetByID(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != content.StatusPublished || c.PublishedAt.After(time.Now()) || !c.Restrict(viewer) {
+		return nil, content.ErrNotFound
+	}
+	return uc.tags(ctx, c)
+}
+
+func (uc *seoUseCase) Sitemap(ctx context.Context) (*seo.Sitemap, error) {
+	now := time.Now()
+	total, err := uc.seoRepo.CountSitemapEntries(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	if total <= uc.opts.SitemapSize {
+		return uc.sitemapURLs(ctx, now, 0)
+	}
+
+	pages := (total + uc.opts.SitemapSize - 1) / uc.opts.SitemapSize
+	sitemap := &seo.Sitemap{Pages: make([]string, pages)}
+	for i := range sitemap.Pages {
+		sitemap.Pages[i] = uc.opts.SiteURL + "/sitemaps/" + strconv.Itoa(i+1) + ".xml"
+	}
+	return sitemap, nil
+}
+
+func (uc *seoUseCase) SitemapPage(ctx context.Context, n int) (*seo.Sitemap, error) {
+	if n < 1 {
+		return nil, seo.ErrNotFound
+	}
+	sitemap, err := uc.sitemapURLs(ctx, time.Now(), (n-1)*uc.opts.SitemapSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(sitemap.URLs) == 0 && n > 1 {
+		return nil, seo.ErrNotFound
+	}
+	return sitemap, nil
+}
+
+func (uc *seoUseCase) Robots() string {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if !uc.opts.AllowIndexing {
+		b.WriteString("Disallow: /\n")
+		return b.String()
+	}
+	if len(uc.opts.Disallow) == 0 {
+		b.WriteString("Disallow:\n")
+	}
+	for _, path := range uc.opts.Disallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\nSitemap: " + uc.opts.SiteURL + "/sitemap.xml\n")
+	return b.String()
+}
+
+// metadata returns the stored metadata of an entry, or blank metadata when it has none.
+func (uc *seoUseCase) metadata(ctx context.Context, contentID string) (*seo.Metadata, error) {
+	m, err := uc.seoRepo.Get(ctx, contentID)
+	if errors.Is(err, seo.ErrNotFound) {
+		return &seo.Metadata{ContentID: contentID}, nil
+	}
+	return m, err
+}
+
+func (uc *seoUseCase) tags(ctx context.Context, c *content.Content) (*seo.Tags, error) {
+	m, err := uc.metadata(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	permalinkURL := uc.absolute(uc.permalink(c.ID, c.Type, c.Slug, c.Locale, c.PermalinkDate()))
+	tags := &seo.Tags{
+		Title:       firstNonEmpty(m.MetaTitle, c.Title),
+		Description: firstNonEmpty(m.MetaDescription, c.Excerpt, fulltext.Summary(c.BodyHTML, c.Body, descriptionLength)),
+		Canonical:   permalinkURL,
+		Robots:      "index, follow",
+	}
+	if m.CanonicalURL != "" {
+		tags.Canonical = uc.absolute(m.CanonicalURL)
+	}
+	if m.NoIndex || !uc.opts.AllowIndexing {
+		tags.Robots = "noindex, follow"
+	}
+
+	tags.OpenGraph = seo.OpenGraph{
+		Type:        "website",
+		Title:       tags.Title,
+		Description: tags.Description,
+		URL:         tags.Canonical,
+		SiteName:    uc.opts.SiteName,
+		Locale:      c.Locale,
+	}
+	if c.Type == "post" {
+		tags.OpenGraph.Type = "article"
+	}
+	if m.OGImageID != "" {
+		item, err := uc.media.Get(ctx, m.OGImageID)
+		if err != nil && !errors.Is(err, media.ErrNotFound) {
+			return nil, err
+		}
+		if item != nil {
+			tags.OpenGraph.Image = uc.absolute(firstNonEmpty(item.Sizes[ogImageSize], item.URL))
+		}
+	}
+
+	return tags, nil
+}
+
+// sitemapURLs lists one sitemap file worth of URLs starting at offset.
+func (uc *seoUseCase) sitemapURLs(ctx context.Context, now time.Time, offset int) (*seo.Sitemap, error) {
+	entries, err := uc.seoRepo.ListSitemapEntries(ctx, now, uc.opts.SitemapSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	sitemap := &seo.Sitemap{URLs: make([]seo.SitemapURL, 0, len(entries))}
+	for _, e := range entries {
+		sitemap.URLs = append(sitemap.URLs, seo.SitemapURL{
+			Loc:     uc.absolute(uc.permalink(e.ContentID, e.Type, e.Slug, e.Locale, e.PublishedAt)),
+			LastMod: e.UpdatedAt,
+		})
+	}
+	return sitemap, nil
+}
+
+func (uc *seoUseCase) permalink(id, contentType, slug, locale string, date time.Time) string {
+	return uc.permalinks.Build(permalink.Fields{ID: id, Type: contentType, Slug: slug, Locale: locale, Date: date})
+}
+
+// absolute prefixes site-relative paths with the site URL.
+func (uc *seoUseCase) absolute(path string) string {
+	if strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "//") {
+		return uc.opts.SiteURL + path
+	}
+	return path
+}
+
+func validCanonical(raw string) bool {
+	if strings.HasPrefix(raw, "/") {
+		return !strings.HasPrefix(raw, "//")
+	}
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,244 @@
+package seo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/seo"
+	mediausecase "github.com/mashurimansur/goCMS/internal/usecase/media"
+	"github.com/mashurimansur/goCMS/internal/utils/permalink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSEORepository struct {
+	mock.Mock
+}
+
+func (m *MockSEORepository) Get(ctx context.Context, contentID string) (*seo.Metadata, error) {
+	args := m.Called(ctx, contentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*seo.Metadata), args.Error(1)
+}
+
+func (m *MockSEORepository) Save(ctx context.Context, md *seo.Metadata) error {
+	args := m.Called(ctx, md)
+	return args.Error(0)
+}
+
+func (m *MockSEORepository) CountSitemapEntries(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSEORepository) ListSitemapEntries(ctx context.Context, now time.Time, limit, offset int) ([]*seo.SitemapEntry, error) {
+	args := m.Called(ctx, now, limit, offset)
+	return args.Get(0).([]*seo.SitemapEntry), args.Error(1)
+}
+
+type MockContentRepository struct {
+	mock.Mock
+	content.Repository
+}
+
+func (m *MockContentRepository) GetByID(ctx context.Context, id string) (*content.Content, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+type MockMediaUseCase struct {
+	mock.Mock
+	mediausecase.UseCase
+}
+
+func (m *MockMediaUseCase) Get(ctx context.Context, id string) (*media.Media, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*media.Media), args.Error(1)
+}
+
+var (
+	testPermalinks = permalink.Patterns{"post": "/{year}/{month}/{slug}", "page": "/{slug}"}
+	testOptions    = Options{SiteURL: "https://example.com/", SiteName: "Example", AllowIndexing: true, Disallow: []string{"/api/"}}
+)
+
+func newTestUseCase(opts Options) (UseCase, *MockSEORepository, *MockContentRepository, *MockMediaUseCase) {
+	repo := new(MockSEORepository)
+	contentRepo := new(MockContentRepository)
+	mediaUseCase := new(MockMediaUseCase)
+	return NewSEOUseCase(repo, contentRepo, mediaUseCase, testPermalinks, opts), repo, contentRepo, mediaUseCase
+}
+
+func launchPost() *content.Content {
+	return &content.Content{
+		ID: "post-1", Type: "post", Locale: "en", Title: "Launch", Slug: "launch",
+		BodyHTML:    "<p>" + strings.Repeat("word ", 40) + "</p>",
+		Status:      content.StatusPublished,
+		PublishedAt: time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestSEOUseCase_Tags_Defaults(t *testing.T) {
+	uc, repo, contentRepo, _ := newTestUseCase(testOptions)
+
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(launchPost(), nil)
+	repo.On("Get", mock.Anything, "post-1").Return(nil, seo.ErrNotFound)
+
+	tags, err := uc.PublishedTags(context.Background(), "post-1", content.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, "Launch", tags.Title)
+	assert.Equal(t, "https://example.com/2025/03/launch", tags.Canonical)
+	assert.Equal(t, "index, follow", tags.Robots)
+	assert.True(t, strings.HasSuffix(tags.Description, "word…"))
+	assert.LessOrEqual(t, len([]rune(tags.Description)), descriptionLength+1)
+	assert.Equal(t, "article", tags.OpenGraph.Type)
+	assert.Equal(t, "Example", tags.OpenGraph.SiteName)
+	assert.Empty(t, tags.OpenGraph.Image)
+}
+
+func TestSEOUseCase_Tags_Overrides(t *testing.T) {
+	uc, repo, contentRepo, mediaUseCase := newTestUseCase(testOptions)
+
+	post := launchPost()
+	post.Excerpt = "Short excerpt"
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+	repo.On("Get", mock.Anything, "post-1").Return(&seo.Metadata{ContentID: "post-1", MetaTitle: "We launched", CanonicalURL: "/launch", NoIndex: true, OGImageID: "media-1"}, nil)
+	mediaUseCase.On("Get", mock.Anything, "media-1").Return(&media.Media{ID: "media-1", URL: "/api/v1/media/media-1", Sizes: map[string]string{"large": "/api/v1/media/media-1/sizes/large"}}, nil)
+
+	tags, err := uc.Tags(context.Background(), "post-1")
+	require.NoError(t, err)
+	assert.Equal(t, "We launched", tags.Title)
+	assert.Equal(t, "Short excerpt", tags.Description)
+	assert.Equal(t, "https://example.com/launch", tags.Canonical)
+	assert.Equal(t, "noindex, follow", tags.Robots)
+	assert.Equal(t, "https://example.com/api/v1/media/media-1/sizes/large", tags.OpenGraph.Image)
+}
+
+func TestSEOUseCase_PublishedTags_Draft(t *testing.T) {
+	uc, _, contentRepo, _ := newTestUseCase(testOptions)
+
+	contentRepo.On("GetByID", mock.Anything, "draft-1").Return(&content.Content{ID: "draft-1", Status: content.StatusDraft}, nil)
+
+	_, err := uc.PublishedTags(context.Background(), "draft-1", content.Viewer{})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+}
+
+func TestSEOUseCase_PublishedTags_Restricted(t *testing.T) {
+	uc, repo, contentRepo, _ := newTestUseCase(testOptions)
+
+	members := launchPost()
+	members.ID = "members-1"
+	members.Visibility = content.VisibilityAuthenticated
+	contentRepo.On("GetByID", mock.Anything, "members-1").Return(members, nil)
+	teaser := launchPost()
+	teaser.ID = "teaser-1"
+	teaser.Visibility = content.VisibilityAuthenticated
+	teaser.Teaser = true
+	contentRepo.On("GetByID", mock.Anything, "teaser-1").Return(teaser, nil)
+	repo.On("Get", mock.Anything, "teaser-1").Return(nil, seo.ErrNotFound)
+
+	_, err := uc.PublishedTags(context.Background(), "members-1", content.Viewer{})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+
+	tags, err := uc.PublishedTags(context.Background(), "teaser-1", content.Viewer{})
+	require.NoError(t, err)
+	assert.Empty(t, tags.Description, "the description is not cut from a locked body")
+}
+
+func TestSEOUseCase_Save_Invalid(t *testing.T) {
+	uc, repo, contentRepo, mediaUseCase := newTestUseCase(testOptions)
+
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(launchPost(), nil)
+	mediaUseCase.On("Get", mock.Anything, "doc-1").Return(&media.Media{ID: "doc-1", MimeType: "application/pdf"}, nil)
+	mediaUseCase.On("Get", mock.Anything, "missing").Return(nil, media.ErrNotFound)
+
+	cases := []*seo.Metadata{
+		{ContentID: "post-1", MetaTitle: strings.Repeat("a", 256)},
+		{ContentID: "post-1", CanonicalURL: "javascript:alert(1)"},
+		{ContentID: "post-1", CanonicalURL: "//other.example/launch"},
+		{ContentID: "post-1", OGImageID: "doc-1"},
+		{ContentID: "post-1", OGImageID: "missing"},
+	}
+	for _, m := range cases {
+		assert.ErrorIs(t, uc.Save(context.Background(), m), ErrInvalidMetadata)
+	}
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestSEOUseCase_Save(t *testing.T) {
+	uc, repo, contentRepo, _ := newTestUseCase(testOptions)
+
+	contentRepo.On("GetByID", mock.Anything, "post-1").Return(launchPost(), nil)
+	repo.On("Save", mock.Anything, &seo.Metadata{ContentID: "post-1", MetaTitle: "We launched", CanonicalURL: "https://other.example/launch"}).Return(nil)
+
+	require.NoError(t, uc.Save(context.Background(), &seo.Metadata{ContentID: "post-1", MetaTitle: " We launched ", CanonicalURL: "https://other.example/launch"}))
+	repo.AssertExpectations(t)
+}
+
+func TestSEOUseCase_Sitemap(t *testing.T) {
+	uc, repo, _, _ := newTestUseCase(testOptions)
+
+	updated := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	repo.On("CountSitemapEntries", mock.Anything, mock.Anything).Return(2, nil)
+	repo.On("ListSitemapEntries", mock.Anything, mock.Anything, MaxSitemapURLs, 0).Return([]*seo.SitemapEntry{
+		{ContentID: "post-1", Type: "post", Slug: "launch", Locale: "en", PublishedAt: time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC), UpdatedAt: updated},
+		{ContentID: "page-1", Type: "page", Slug: "about", Locale: "en", PublishedAt: updated, UpdatedAt: updated},
+	}, nil)
+
+	sitemap, err := uc.Sitemap(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, sitemap.Pages)
+	require.Len(t, sitemap.URLs, 2)
+	assert.Equal(t, "https://example.com/2025/03/launch", sitemap.URLs[0].Loc)
+	assert.Equal(t, updated, sitemap.URLs[0].LastMod)
+	assert.Equal(t, "https://example.com/about", sitemap.URLs[1].Loc)
+}
+
+func TestSEOUseCase_Sitemap_Index(t *testing.T) {
+	opts := testOptions
+	opts.SitemapSize = 2
+	uc, repo, _, _ := newTestUseCase(opts)
+
+	repo.On("CountSitemapEntries", mock.Anything, mock.Anything).Return(5, nil)
+	repo.On("ListSitemapEntries", mock.Anything, mock.Anything, 2, 4).Return([]*seo.SitemapEntry{
+		{ContentID: "page-1", Type: "page", Slug: "about", Locale: "en"},
+	}, nil)
+	repo.On("ListSitemapEntries", mock.Anything, mock.Anything, 2, 6).Return([]*seo.SitemapEntry{}, nil)
+
+	sitemap, err := uc.Sitemap(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://example.com/sitemaps/1.xml",
+		"https://example.com/sitemaps/2.xml",
+		"https://example.com/sitemaps/3.xml",
+	}, sitemap.Pages)
+
+	page, err := uc.SitemapPage(context.Background(), 3)
+	require.NoError(t, err)
+	require.Len(t, page.URLs, 1)
+
+	_, err = uc.SitemapPage(context.Background(), 4)
+	assert.ErrorIs(t, err, seo.ErrNotFound)
+}
+
+func TestSEOUseCase_Robots(t *testing.T) {
+	uc, _, _, _ := newTestUseCase(testOptions)
+	assert.Equal(t, "User-agent: *\nDisallow: /api/\n\nSitemap: https://example.com/sitemap.xml\n", uc.Robots())
+
+	closed := testOptions
+	closed.AllowIndexing = false
+	uc, _, _, _ = newTestUseCase(closed)
+	assert.Equal(t, "User-agent: *\nDisallow: /\n", uc.Robots())
+}
@@ -0,0 +1,247 @@
+package snippet
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/snippet"
+	"github.com/mashurimansur/goCMS/internal/utils/markdown"
+)
+
+var (
+	// ErrInvalidSnippet is returned when a snippet name is missing or malformed, or its body
+	// references another snippet.
+	ErrInvalidSnippet = errors.New("invalid snippet")
+	// ErrSnippetExists is returned when another snippet already uses the name.
+	ErrSnippetExists = errors.New("snippet name already exists")
+	// ErrSnippetInUse is returned when deleting a snippet entries still reference.
+	ErrSnippetInUse = errors.New("snippet is in use")
+)
+
+type UseCase interface {
+	Create(ctx context.Context, s *snippet.Snippet) error
+	Get(ctx context.Context, id string) (*snippet.Snippet, error)
+	// Update saves a snippet. Its name is fixed when it is created since entries reference it.
+	Update(ctx context.Context, s *snippet.Snippet) error
+	Delete(ctx context.Context, id string) error
+	// List returns snippets ordered by name, each with the number of entries referencing it.
+	List(ctx context.Context, limit, offset int) ([]*snippet.Snippet, error)
+	// Usages lists the entries outside the trash referencing a snippet.
+	Usages(ctx context.Context, id string, limit, offset int) ([]*snippet.Usage, error)
+	// Expand resolves the snippet references of entries read for the public site. The snippet
+	// revision it records changes whenever a referenced snippet is saved, created or deleted, so
+	// pages rendered from the entries are rebuilt.
+	content.Snippets
+}
+
+type snippetUseCase struct {
+	snippetRepo snippet.Repository
+	blocks      *block.Registry
+}
+
+// NewSnippetUseCase returns the snippet use case. blocks renders snippets written as blocks and
+// must not hold the snippet block type, as snippets cannot nest.
+func NewSnippetUseCase(snippetRepo snippet.Repository, blocks *block.Registry) UseCase {
+	return &snippetUseCase{
+		snippetRepo: snippetRepo,
+		blocks:      blocks,
+	}
+}
+
+func (uc *snippetUseCase) Create(ctx context.Context, s *snippet.Snippet) error {
+	s.Name = strings.TrimSpace(s.Name)
+	if !snippet.ValidName(s.Name) {
+		return fmt.Errorf("%w: name must be lower-case letters, digits, dashes or underscores", ErrInvalidSnippet)
+	}
+	if _, err := uc.snippetRepo.GetByName(ctx, s.Name); err == nil {
+		return ErrSnippetExists
+	} else if !errors.Is(err, snippet.ErrNotFound) {
+		return err
+	}
+	if err := uc.prepare(s); err != nil {
+		return err
+	}
+	return uc.snippetRepo.Create(ctx, s)
+}
+
+func (uc *snippetUseCase) Get(ctx context.Context, id string) (*snippet.Snippet, error) {
+	s, err := uc.snippetRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.countUsages(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (uc *snippetUseCase) Update(ctx context.Context, s *snippet.Snippet) error {
+	previous, err := uc.snippetRepo.GetByID(ctx, s.ID)
+	if err != nil {
+		return err
+	}
+	s.Name = previous.Name
+	s.CreatedAt = previous.CreatedAt
+	if s.BodyFormat == "" {
+		s.BodyFormat = previous.BodyFormat
+	}
+	if s.Version, err = lock.Expect(s.Version, previous.Version); err != nil {
+		return err
+	}
+	if err := uc.prepare(s); err != nil {
+		return err
+	}
+	if err := uc.snippetRepo.Update(ctx, s); err != nil {
+		return err
+	}
+	return uc.countUsages(ctx, s)
+}
+
+func (uc *snippetUseCase) Delete(ctx context.Context, id string) error {
+	s, err := uc.snippetRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := uc.countUsages(ctx, s); err != nil {
+		return err
+	}
+	if s.UsageCount > 0 {
+		return fmt.Errorf("%w: referenced by %d entries", ErrSnippetInUse, s.UsageCount)
+	}
+	return uc.snippetRepo.Delete(ctx, id)
+}
+
+func (uc *snippetUseCase) List(ctx context.Context, limit, offset int) ([]*snippet.Snippet, error) {
+	snippets, err := uc.snippetRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if snippets == nil {
+		return []*snippet.Snippet{}, nil
+	}
+	if err := uc.countUsages(ctx, snippets...); err != nil {
+		return nil, err
+	}
+	return snippets, nil
+}
+
+func (uc *snippetUseCase) Usages(ctx context.Context, id string, limit, offset int) ([]*snippet.Usage, error) {
+	s, err := uc.snippetRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.snippetRepo.ListUsages(ctx, s.Name, limit, offset)
+}
+
+func (uc *snippetUseCase) Expand(ctx context.Context, entries ...*content.Content) error {
+	references := make([][]string, len(entries))
+	var names []string
+	for i, c := range entries {
+		references[i] = snippet.References(c.BodyHTML)
+		names = append(names, references[i]...)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	found, err := uc.snippetRepo.ListByNames(ctx, unique(names))
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*snippet.Snippet, len(found))
+	for _, s := range found {
+		byName[s.Name] = s
+	}
+	for i, c := range entries {
+		if len(references[i]) == 0 {
+			continue
+		}
+		c.BodyHTML = snippet.Expand(c.BodyHTML, byName)
+		c.SnippetRevision = revision(references[i], byName)
+	}
+	return nil
+}
+
+// prepare applies defaults and renders the body of s to sanitized HTML.
+func (uc *snippetUseCase) prepare(s *snippet.Snippet) error {
+	s.Title = strings.TrimSpace(s.Title)
+	if s.Title == "" {
+		s.Title = s.Name
+	}
+	if s.BodyFormat == "" {
+		s.BodyFormat = markdown.FormatMarkdown
+	}
+
+	if s.BodyFormat == content.FormatBlocks {
+		html, err := uc.blocks.RenderHTML(s.Blocks)
+		if err != nil {
+			return err
+		}
+		text, err := uc.blocks.RenderText(s.Blocks)
+		if err != nil {
+			return err
+		}
+		s.BodyHTML, s.Body = html, text
+	} else {
+		html, err := markdown.Render(s.Body, s.BodyFormat)
+		if err != nil {
+			return err
+		}
+		s.BodyHTML, s.Blocks = html, nil
+	}
+
+	if len(snippet.References(s.BodyHTML)) > 0 {
+		return fmt.Errorf("%w: snippets cannot reference other snippets", ErrInvalidSnippet)
+	}
+	return nil
+}
+
+// countUsages fills the UsageCount of snippets.
+func (uc *snippetUseCase) countUsages(ctx context.Context, snippets ...*snippet.Snippet) error {
+	names := make([]string, len(snippets))
+	for i, s := range snippets {
+		names[i] = s.Name
+	}
+	counts, err := uc.snippetRepo.CountUsages(ctx, names)
+	if err != nil {
+		return err
+	}
+	for _, s := range snippets {
+		s.UsageCount = counts[s.Name]
+	}
+	return nil
+}
+
+// revision hashes the IDs and versions of the referenced snippets, so it also changes when a
+// snippet is deleted or recreated under the same name.
+func revision(names []string, snippets map[string]*snippet.Snippet) string {
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name + "@"))
+		if s, ok := snippets[name]; ok {
+			h.Write([]byte(s.ID + ":" + strconv.Itoa(s.Version)))
+		}
+		h.Write([]byte("\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+func unique(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var result []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package snippet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/snippet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository is an in-memory snippet.Repository whose usages are found in bodies.
+type memoryRepository struct {
+	snippets map[string]*snippet.Snippet
+	bodies   map[string]string
+	nextID   int
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{snippets: map[string]*snippet.Snippet{}, bodies: map[string]string{}}
+}
+
+func (r *memoryRepository) Create(_ context.Context, s *snippet.Snippet) error {
+	r.nextID++
+	s.ID = fmt.Sprintf("s-%d", r.nextID)
+	s.Version = 1
+	stored := *s
+	r.snippets[s.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) GetByID(_ context.Context, id string) (*snippet.Snippet, error) {
+	s, ok := r.snippets[id]
+	if !ok {
+		return nil, snippet.ErrNotFound
+	}
+	found := *s
+	return &found, nil
+}
+
+func (r *memoryRepository) GetByName(ctx context.Context, name string) (*snippet.Snippet, error) {
+	for id, s := range r.snippets {
+		if s.Name == name {
+			return r.GetByID(ctx, id)
+		}
+	}
+	return nil, snippet.ErrNotFound
+}
+
+func (r *memoryRepository) ListByNames(ctx context.Context, names []string) ([]*snippet.Snippet, error) {
+	var found []*snippet.Snippet
+	for _, name := range names {
+		if s, err := r.GetByName(ctx, name); err == nil {
+			found = append(found, s)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryRepository) Update(_ context.Context, s *snippet.Snippet) error {
+	stored, ok := r.snippets[s.ID]
+	if !ok {
+		return snippet.ErrNotFound
+	}
+	if stored.Version != s.Version {
+		return lock.ErrVersionConflict
+	}
+	s.Version++
+	updated := *s
+	r.snippets[s.ID] = &updated
+	return nil
+}
+
+func (r *memoryRepository) Delete(_ context.Context, id string) error {
+	delete(r.snippets, id)
+	return nil
+}
+
+func (r *memoryRepository) List(_ context.Context, limit, offset int) ([]*snippet.Snippet, error) {
+	var all []*snippet.Snippet
+	for _, s := range r.snippets {
+		found := *s
+		all = append(all, &found)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all, nil
+}
+
+func (r *memoryRepository) ListUsages(_ context.Context, name string, limit, offset int) ([]*snippet.Usage, error) {
+	usages := []*snippet.Usage{}
+	for id, body := range r.bodies {
+		if strings.Contains(body, snippet.Reference(name)) {
+			usages = append(usages, &snippet.Usage{ContentID: id})
+		}
+	}
+	return usages, nil
+}
+
+func (r *memoryRepository) CountUsages(ctx context.Context, names []string) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, name := range names {
+		usages, _ := r.ListUsages(ctx, name, 0, 0)
+		counts[name] = len(usages)
+	}
+	return counts, nil
+}
+
+func newUseCase() (UseCase, *memoryRepository) {
+	repo := newMemoryRepository()
+	return NewSnippetUseCase(repo, block.NewDefaultRegistry(block.Options{})), repo
+}
+
+func TestSnippetUseCase_Create(t *testing.T) {
+	uc, _ := newUseCase()
+	ctx := context.Background()
+
+	footer := &snippet.Snippet{Name: "footer", Body: "**Thanks** for reading"}
+	require.NoError(t, uc.Create(ctx, footer))
+	assert.Equal(t, "footer", footer.Title)
+	assert.Equal(t, "markdown", footer.BodyFormat)
+	assert.Equal(t, "<p><strong>Thanks</strong> for reading</p>\n", footer.BodyHTML)
+
+	assert.ErrorIs(t, uc.Create(ctx, &snippet.Snippet{Name: "footer"}), ErrSnippetExists)
+	assert.ErrorIs(t, uc.Create(ctx, &snippet.Snippet{Name: "Footer Links"}), ErrInvalidSnippet)
+	assert.ErrorIs(t, uc.Create(ctx, &snippet.Snippet{Name: "nested", Body: "{{snippet:footer}}"}), ErrInvalidSnippet)
+
+	promo := &snippet.Snippet{Name: "promo", BodyFormat: content.FormatBlocks, Blocks: []block.Block{
+		{Type: block.TypeParagraph, Data: json.RawMessage(`{"text":"Sale!"}`)},
+	}}
+	require.NoError(t, uc.Create(ctx, promo))
+	assert.Equal(t, "Sale!", promo.Body)
+	assert.Contains(t, promo.BodyHTML, "Sale!")
+}
+
+func TestSnippetUseCase_UpdateAndDelete(t *testing.T) {
+	uc, repo := newUseCase()
+	ctx := context.Background()
+
+	footer := &snippet.Snippet{Name: "footer", Body: "Bye"}
+	require.NoError(t, uc.Create(ctx, footer))
+	repo.bodies["c-1"] = "<p>Hello</p>{{snippet:footer}}"
+
+	// The name stays fixed so references keep working.
+	update := &snippet.Snippet{ID: footer.ID, Name: "renamed", Body: "See you", Version: 1}
+	require.NoError(t, uc.Update(ctx, update))
+	assert.Equal(t, "footer", update.Name)
+	assert.Equal(t, 2, update.Version)
+	assert.Equal(t, 1, update.UsageCount)
+
+	stale := &snippet.Snippet{ID: footer.ID, Body: "Old", Version: 1}
+	assert.ErrorIs(t, uc.Update(ctx, stale), lock.ErrVersionConflict)
+
+	list, err := uc.List(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, 1, list[0].UsageCount)
+
+	assert.ErrorIs(t, uc.Delete(ctx, footer.ID), ErrSnippetInUse)
+	delete(repo.bodies, "c-1")
+	require.NoError(t, uc.Delete(ctx, footer.ID))
+}
+
+func TestSnippetUseCase_Expand(t *testing.T) {
+	uc, _ := newUseCase()
+	ctx := context.Background()
+
+	footer := &snippet.Snippet{Name: "footer", Body: "Bye"}
+	require.NoError(t, uc.Create(ctx, footer))
+
+	hello := &content.Content{Version: 4, BodyHTML: "<p>Hello</p>\n<p>{{snippet:footer}}</p>\n<p>{{snippet:gone}}</p>"}
+	plain := &content.Content{Version: 2, BodyHTML: "<p>Plain</p>"}
+	require.NoError(t, uc.Expand(ctx, hello, plain))
+
+	assert.Equal(t, "<p>Hello</p>\n<p>Bye</p>\n\n", hello.BodyHTML)
+	assert.NotEmpty(t, hello.SnippetRevision)
+	assert.Equal(t, "2", plain.Revision())
+
+	// Saving the snippet changes the revision of the entries using it.
+	require.NoError(t, uc.Update(ctx, &snippet.Snippet{ID: footer.ID, Body: "See you", Version: 1}))
+	again := &content.Content{Version: 4, BodyHTML: "<p>Hello</p>\n<p>{{snippet:footer}}</p>\n<p>{{snippet:gone}}</p>"}
+	require.NoError(t, uc.Expand(ctx, again))
+	assert.Contains(t, again.BodyHTML, "See you")
+	assert.NotEqual(t, hello.Revision(), again.Revision())
+}
@@ -0,0 +1,471 @@
+package static
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/static"
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	feedusecase "github.com/mashurimansur/goCMS/internal/usecase/feed"
+	seousecase "github.com/mashurimansur/goCMS/internal/usecase/seo"
+	"github.com/mashurimansur/goCMS/internal/utils/syndication"
+)
+
+// Defaults applied to zero Options fields.
+const (
+	DefaultListSize = 20
+	DefaultListType = "post"
+)
+
+// pageSize is the number of entries loaded at a time while rendering.
+const pageSize = 100
+
+// mediaDir is the directory media files are copied to, one subdirectory per item.
+const mediaDir = "media"
+
+// Options configures the static export.
+type Options struct {
+	// SiteURL is the absolute URL the site is served from, e.g. "https://example.com".
+	SiteURL string
+	Title   string
+	// MediaBaseURL is the URL prefix the API serves media under. Links to it in rendered pages are
+	// rewritten to the copied files.
+	MediaBaseURL string
+	// ListSize is the number of entries listed on the home page.
+	ListSize int
+	// ListType is the type of the entries listed on the home page.
+	ListType string
+}
+
+// BuildOptions tunes one export.
+type BuildOptions struct {
+	// Full renders every entry and copies every media item again, ignoring the previous manifest.
+	Full bool
+}
+
+// Result reports what an export did. Rendered and Unchanged count content pages; Copied counts
+// media files and Removed the files of the previous export nothing generates anymore.
+type Result struct {
+	Manifest  *static.Manifest `json:"manifest"`
+	Rendered  int              `json:"rendered"`
+	Unchanged int              `json:"unchanged"`
+	Copied    int              `json:"copied"`
+	Removed   int              `json:"removed"`
+}
+
+type UseCase interface {
+	// Build renders the published content to the output, along with the home page, the feeds, the
+	// sitemap, robots.txt, the media the pages link to and the manifest. Entries whose revision,
+	// categories and tags did not change since the previous export are kept as they are, as are media
+	// at the same revision, unless the templates changed or opts.Full is set.
+	Build(ctx context.Context, opts BuildOptions) (*Result, error)
+}
+
+type staticUseCase struct {
+	contents     contentusecase.UseCase
+	mediaRepo    media.Repository
+	mediaStorage media.Storage
+	seo          seousecase.UseCase
+	feeds        feedusecase.UseCase
+	renderer     theme.Renderer
+	output       media.Storage
+	opts         Options
+	mediaURL     *regexp.Regexp
+}
+
+// NewStaticUseCase returns the static export writing to output, any storage backend such as a local
+// directory.
+func NewStaticUseCase(contents contentusecase.UseCase, mediaRepo media.Repository, mediaStorage media.Storage, seo seousecase.UseCase, feeds feedusecase.UseCase, renderer theme.Renderer, output media.Storage, opts Options) UseCase {
+	opts.SiteURL = strings.TrimSuffix(opts.SiteURL, "/")
+	opts.MediaBaseURL = strings.TrimSuffix(opts.MediaBaseURL, "/")
+	if opts.ListSize <= 0 {
+		opts.ListSize = DefaultListSize
+	}
+	if opts.ListType == "" {
+		opts.ListType = DefaultListType
+	}
+	return &staticUseCase{
+		contents:     contents,
+		mediaRepo:    mediaRepo,
+		mediaStorage: mediaStorage,
+		seo:          seo,
+		feeds:        feeds,
+		renderer:     renderer,
+		output:       output,
+		opts:         opts,
+		// Renditions and transforms link to the original file, the only one copied.
+		mediaURL: regexp.MustCompile(regexp.QuoteMeta(opts.MediaBaseURL) + `/([0-9a-fA-F-]{36})(?:/sizes/[\w-]+|/transform[^"'\s)]*)?`),
+	}
+}
+
+func (uc *staticUseCase) Build(ctx context.Context, opts BuildOptions) (*Result, error) {
+	previous, err := uc.readManifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b := &build{
+		staticUseCase: uc,
+		result: &Result{Manifest: &static.Manifest{
+			GeneratedAt: time.Now(),
+			Fingerprint: uc.renderer.Fingerprint(),
+			Files:       []static.File{},
+		}},
+		previous: make(map[string]static.File),
+		media:    make(map[string]*media.Media),
+	}
+	// Pages rendered with other templates are all stale.
+	if !opts.Full && previous.Fingerprint == b.result.Manifest.Fingerprint {
+		for _, f := range previous.Files {
+			b.previous[f.Path] = f
+		}
+	}
+
+	entries, err := uc.listPublished(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range entries {
+		if err := b.writeEntry(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.writeIndex(ctx, entries); err != nil {
+		return nil, err
+	}
+	if err := b.writeFeeds(ctx); err != nil {
+		return nil, err
+	}
+	if err := b.writeSitemap(ctx); err != nil {
+		return nil, err
+	}
+	if err := b.write(ctx, static.File{Path: "robots.txt", Source: static.SourceRobots}, []byte(uc.seo.Robots())); err != nil {
+		return nil, err
+	}
+	if err := b.copyMedia(ctx); err != nil {
+		return nil, err
+	}
+
+	// Files are only removed once everything else is in place.
+	generated := make(map[string]bool, len(b.result.Manifest.Files))
+	for _, f := range b.result.Manifest.Files {
+		generated[f.Path] = true
+	}
+	for _, f := range previous.Files {
+		if generated[f.Path] {
+			continue
+		}
+		if err := uc.output.Delete(ctx, f.Path); err != nil {
+			return nil, err
+		}
+		b.result.Removed++
+	}
+
+	slices.SortFunc(b.result.Manifest.Files, func(x, y static.File) int { return strings.Compare(x.Path, y.Path) })
+	manifest, err := json.MarshalIndent(b.result.Manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.output.Save(ctx, static.ManifestPath, bytes.NewReader(manifest)); err != nil {
+		return nil, err
+	}
+	return b.result, nil
+}
+
+// readManifest loads the manifest of the previous export, or an empty one.
+func (uc *staticUseCase) readManifest(ctx context.Context) (*static.Manifest, error) {
+	f, err := uc.output.Open(ctx, static.ManifestPath)
+	if errors.Is(err, media.ErrNotFound) {
+		return &static.Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var m static.Manifest
+	if err := json.NewDecoder(f).Decode(&m); err != nil {
+		// A damaged manifest only costs a full rebuild.
+		return &static.Manifest{}, nil
+	}
+	return &m, nil
+}
+
+// listPublished loads every published entry anonymous readers may see in every locale, latest
+// first. Members-only teasers are exported locked and other restricted entries are left out.
+func (uc *staticUseCase) listPublished(ctx context.Context) ([]*content.Content, error) {
+	var entries []*content.Content
+	for offset := 0; ; offset += pageSize {
+		page, err := uc.contents.ListPublished(ctx, content.ListFilter{Limit: pageSize, Offset: offset}, nil)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+		if len(page) < pageSize {
+			return entries, nil
+		}
+	}
+}
+
+// build carries the state of one export.
+type build struct {
+	*staticUseCase
+	result *Result
+	// previous holds the files of the previous export that may be kept as they are.
+	previous map[string]static.File
+	// media caches the media items pages link to, nil for the ones that no longer exist.
+	media map[string]*media.Media
+}
+
+func (b *build) writeEntry(ctx context.Context, c *content.Content) error {
+	// Pages show the names of their categories and tags and link to them by slug, so renaming either
+	// changes the page even though the entry itself did not change.
+	taxonomy, err := b.contents.GetTaxonomy(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	file := static.File{Path: pagePath(c.Permalink), Source: static.SourceContent + c.ID, Revision: revision(c, taxonomy)}
+	if f, ok := b.previous[file.Path]; ok && f.Source == file.Source && f.Revision == file.Revision {
+		for _, id := range f.Media {
+			if _, err := b.lookupMedia(ctx, id); err != nil {
+				return err
+			}
+		}
+		b.result.Manifest.Files = append(b.result.Manifest.Files, f)
+		b.result.Unchanged++
+		return nil
+	}
+
+	html, ids, err := b.render(ctx, theme.TemplateSingle, &theme.Page{
+		Title:      c.Title,
+		Path:       c.Permalink,
+		Locale:     c.Locale,
+		Entry:      c,
+		Categories: taxonomy.Categories,
+		Tags:       taxonomy.Tags,
+	})
+	if err != nil {
+		return err
+	}
+	file.Media = ids
+	b.result.Rendered++
+	return b.write(ctx, file, html)
+}
+
+func (b *build) writeIndex(ctx context.Context, entries []*content.Content) error {
+	var latest []*content.Content
+	for _, c := range entries {
+		if c.Type == b.opts.ListType && len(latest) < b.opts.ListSize {
+			latest = append(latest, c)
+		}
+	}
+
+	html, ids, err := b.render(ctx, theme.TemplateHome, &theme.Page{Path: "/", Locale: b.contents.Locales().Default(), Entries: latest})
+	if err != nil {
+		return err
+	}
+	return b.write(ctx, static.File{Path: "index.html", Source: static.SourceIndex, Media: ids}, html)
+}
+
+func (b *build) writeFeeds(ctx context.Context) error {
+	f, err := b.feeds.Site(ctx)
+	if err != nil {
+		return err
+	}
+	for name, encode := range map[string]func(*syndication.Feed) ([]byte, error){
+		"feed.xml": syndication.RSS,
+		"atom.xml": syndication.Atom,
+	} {
+		data, err := encode(f)
+		if err != nil {
+			return err
+		}
+		if err := b.write(ctx, static.File{Path: name, Source: static.SourceFeed}, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *build) writeSitemap(ctx context.Context) error {
+	sitemap, err := b.seo.Sitemap(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := sitemap.XML()
+	if err != nil {
+		return err
+	}
+	if err := b.write(ctx, static.File{Path: "sitemap.xml", Source: static.SourceSitemap}, data); err != nil {
+		return err
+	}
+
+	for n := 1; n <= len(sitemap.Pages); n++ {
+		page, err := b.seo.SitemapPage(ctx, n)
+		if err != nil {
+			return err
+		}
+		if data, err = page.XML(); err != nil {
+			return err
+		}
+		file := static.File{Path: "sitemaps/" + strconv.Itoa(n) + ".xml", Source: static.SourceSitemap}
+		if err := b.write(ctx, file, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyMedia copies the media items pages link to, skipping the ones copied by the previous export
+// at the same revision.
+func (b *build) copyMedia(ctx context.Context) error {
+	ids := make([]string, 0, len(b.media))
+	for id, m := range b.media {
+		if m != nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		m := b.media[id]
+		file := static.File{Path: mediaPath(m), Source: static.SourceMedia + m.ID, Revision: strconv.Itoa(m.Version)}
+		if f, ok := b.previous[file.Path]; ok && f.Source == file.Source && f.Revision == file.Revision {
+			b.result.Manifest.Files = append(b.result.Manifest.Files, f)
+			continue
+		}
+
+		r, err := b.mediaStorage.Open(ctx, m.StorageKey)
+		if errors.Is(err, media.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		h := sha256.New()
+		file.Size, err = b.output.Save(ctx, file.Path, io.TeeReader(r, h))
+		r.Close()
+		if err != nil {
+			return err
+		}
+		file.SHA256 = hex.EncodeToString(h.Sum(nil))
+		b.result.Manifest.Files = append(b.result.Manifest.Files, file)
+		b.result.Copied++
+	}
+	return nil
+}
+
+// render executes a page template and points its media links to the copied files, reporting the
+// media items it links to.
+func (b *build) render(ctx context.Context, template string, page *theme.Page) ([]byte, []string, error) {
+	page.Site = theme.Site{Title: b.opts.Title, URL: b.opts.SiteURL}
+	var buf bytes.Buffer
+	if err := b.renderer.Render(&buf, template, page); err != nil {
+		return nil, nil, err
+	}
+	if b.opts.MediaBaseURL == "" {
+		return buf.Bytes(), nil, nil
+	}
+
+	var ids []string
+	var lookupErr error
+	html := b.mediaURL.ReplaceAllFunc(buf.Bytes(), func(match []byte) []byte {
+		id := strings.ToLower(string(b.mediaURL.FindSubmatch(match)[1]))
+		m, err := b.lookupMedia(ctx, id)
+		if err != nil {
+			lookupErr = err
+		}
+		if m == nil {
+			return match
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+		return []byte("/" + mediaPath(m))
+	})
+	if lookupErr != nil {
+		return nil, nil, lookupErr
+	}
+	return html, ids, nil
+}
+
+// lookupMedia loads a media item pages link to, returning nil when it no longer exists.
+func (b *build) lookupMedia(ctx context.Context, id string) (*media.Media, error) {
+	if m, ok := b.media[id]; ok {
+		return m, nil
+	}
+	m, err := b.mediaRepo.GetByID(ctx, id)
+	if errors.Is(err, media.ErrNotFound) {
+		b.media[id] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.media[id] = m
+	return m, nil
+}
+
+// write saves a generated file unless the previous export wrote the same bytes at the same path.
+func (b *build) write(ctx context.Context, file static.File, data []byte) error {
+	sum := sha256.Sum256(data)
+	file.SHA256 = hex.EncodeToString(sum[:])
+	file.Size = int64(len(data))
+	b.result.Manifest.Files = append(b.result.Manifest.Files, file)
+	if f, ok := b.previous[file.Path]; ok && f.SHA256 == file.SHA256 {
+		return nil
+	}
+	_, err := b.output.Save(ctx, file.Path, bytes.NewReader(data))
+	return err
+}
+
+// revision identifies what the page of an entry is rendered from: the revision of the entry and the
+// slugs and names of its categories and tags.
+func revision(c *content.Content, taxonomy *contentusecase.Taxonomy) string {
+	if len(taxonomy.Categories) == 0 && len(taxonomy.Tags) == 0 {
+		return c.Revision()
+	}
+	h := sha256.New()
+	for _, cat := range taxonomy.Categories {
+		io.WriteString(h, "category\x00"+cat.Slug+"\x00"+cat.Name+"\x00")
+	}
+	for _, t := range taxonomy.Tags {
+		io.WriteString(h, "tag\x00"+t.Slug+"\x00"+t.Name+"\x00")
+	}
+	return c.Revision() + "-" + hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// pagePath maps a permalink to the file serving it: an index.html in the permalink directory, so
+// servers answer the permalink itself, unless the permalink names a file.
+func pagePath(permalink string) string {
+	p := strings.Trim(path.Clean("/"+permalink), "/")
+	if p == "" {
+		return "index.html"
+	}
+	if path.Ext(p) == ".html" {
+		return p
+	}
+	return p + "/index.html"
+}
+
+// mediaPath is where a media item is copied to, under its original file name.
+func mediaPath(m *media.Media) string {
+	name := path.Base(strings.ReplaceAll(m.Filename, "\\", "/"))
+	if name == "." || name == "/" || name == ".." {
+		name = "file"
+	}
+	return mediaDir + "/" + m.ID + "/" + name
+}
@@ -0,0 +1,285 @@
+package static
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/seo"
+	"github.com/mashurimansur/goCMS/internal/domain/static"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	feedusecase "github.com/mashurimansur/goCMS/internal/usecase/feed"
+	seousecase "github.com/mashurimansur/goCMS/internal/usecase/seo"
+	"github.com/mashurimansur/goCMS/internal/utils/locale"
+	"github.com/mashurimansur/goCMS/internal/utils/syndication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockContentUseCase struct {
+	mock.Mock
+	contentusecase.UseCase
+	// tags are the tags of every entry.
+	tags []*tag.Tag
+}
+
+func (m *MockContentUseCase) ListPublished(ctx context.Context, filter content.ListFilter, locales []string) ([]*content.Content, error) {
+	args := m.Called(ctx, filter, locales)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockContentUseCase) GetTaxonomy(ctx context.Context, id string) (*contentusecase.Taxonomy, error) {
+	return &contentusecase.Taxonomy{Tags: m.tags}, nil
+}
+
+func (m *MockContentUseCase) Locales() locale.Settings {
+	settings, _ := locale.Parse("en", "")
+	return settings
+}
+
+type MockMediaRepository struct {
+	mock.Mock
+	media.Repository
+}
+
+func (m *MockMediaRepository) GetByID(ctx context.Context, id string) (*media.Media, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*media.Media), args.Error(1)
+}
+
+type MockSEOUseCase struct {
+	mock.Mock
+	seousecase.UseCase
+}
+
+func (m *MockSEOUseCase) Sitemap(ctx context.Context) (*seo.Sitemap, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*seo.Sitemap), args.Error(1)
+}
+
+func (m *MockSEOUseCase) SitemapPage(ctx context.Context, n int) (*seo.Sitemap, error) {
+	args := m.Called(ctx, n)
+	return args.Get(0).(*seo.Sitemap), args.Error(1)
+}
+
+func (m *MockSEOUseCase) Robots() string {
+	return "User-agent: *\n"
+}
+
+type MockFeedUseCase struct {
+	mock.Mock
+	feedusecase.UseCase
+}
+
+func (m *MockFeedUseCase) Site(ctx context.Context) (*syndication.Feed, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*syndication.Feed), args.Error(1)
+}
+
+// memoryStorage is an in-memory media.Storage counting the writes the export makes.
+type memoryStorage struct {
+	files map[string][]byte
+	saves int
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{files: make(map[string][]byte)}
+}
+
+func (s *memoryStorage) Save(_ context.Context, key string, r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	s.files[key] = data
+	s.saves++
+	return int64(len(data)), nil
+}
+
+func (s *memoryStorage) Open(_ context.Context, key string) (io.ReadSeekCloser, error) {
+	data, ok := s.files[key]
+	if !ok {
+		return nil, media.ErrNotFound
+	}
+	return nopCloser{bytes.NewReader(data)}, nil
+}
+
+func (s *memoryStorage) Delete(_ context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+func (s *memoryStorage) DeletePrefix(context.Context, string) error {
+	return nil
+}
+
+type nopCloser struct{ io.ReadSeeker }
+
+func (nopCloser) Close() error { return nil }
+
+// stubRenderer renders the template name, the page title and the entry body or entry count.
+type stubRenderer struct {
+	fingerprint string
+}
+
+func (r *stubRenderer) Render(w io.Writer, name string, page *theme.Page) error {
+	if page.Entry != nil {
+		_, err := fmt.Fprintf(w, "%s|%s|%s|%d|%s", name, page.Site.Title, page.Title, len(page.Tags), page.Entry.BodyHTML)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s|%s|%s|%d", name, page.Site.Title, page.Locale, len(page.Entries))
+	return err
+}
+
+func (r *stubRenderer) Fingerprint() string {
+	return r.fingerprint
+}
+
+const imageID = "0b9f3c2e-6f0a-4c1e-9d57-3a4f5b6c7d8e"
+
+type fixture struct {
+	contents *MockContentUseCase
+	output   *memoryStorage
+	renderer *stubRenderer
+	uc       UseCase
+}
+
+func newFixture() *fixture {
+	mediaRepo := new(MockMediaRepository)
+	mediaRepo.On("GetByID", mock.Anything, imageID).Return(&media.Media{ID: imageID, Filename: "../cat.png", StorageKey: "2025/cat.png", Version: 1}, nil)
+	mediaRepo.On("GetByID", mock.Anything, mock.Anything).Return(nil, media.ErrNotFound)
+	mediaStorage := newMemoryStorage()
+	mediaStorage.files["2025/cat.png"] = []byte("png")
+
+	seoUseCase := new(MockSEOUseCase)
+	seoUseCase.On("Sitemap", mock.Anything).Return(&seo.Sitemap{URLs: []seo.SitemapURL{{Loc: "https://example.com/hello"}}}, nil)
+	feedUseCase := new(MockFeedUseCase)
+	feedUseCase.On("Site", mock.Anything).Return(&syndication.Feed{Title: "Blog", Link: "https://example.com", Updated: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)}, nil)
+
+	f := &fixture{contents: &MockContentUseCase{tags: []*tag.Tag{{Slug: "go", Name: "Go"}}}, output: newMemoryStorage(), renderer: &stubRenderer{fingerprint: "v1"}}
+	f.uc = NewStaticUseCase(f.contents, mediaRepo, mediaStorage, seoUseCase, feedUseCase, f.renderer, f.output, Options{
+		SiteURL:      "https://example.com/",
+		Title:        "Blog",
+		MediaBaseURL: "/api/v1/media/",
+	})
+	return f
+}
+
+func (f *fixture) publish(entries ...*content.Content) {
+	f.contents.ExpectedCalls = nil
+	f.contents.On("ListPublished", mock.Anything, content.ListFilter{Limit: pageSize}, []string(nil)).Return(entries, nil)
+}
+
+func TestStaticUseCase_Build(t *testing.T) {
+	f := newFixture()
+	hello := &content.Content{
+		ID: "c-1", Type: "post", Title: "Hello", Permalink: "/2025/04/hello", Version: 1,
+		BodyHTML: `<img src="/api/v1/media/` + imageID + `/sizes/medium"><img src="/api/v1/media/99999999-9999-9999-9999-999999999999">`,
+	}
+	about := &content.Content{ID: "c-2", Type: "page", Title: "About", Permalink: "/about", Version: 3}
+	f.publish(hello, about)
+
+	result, err := f.uc.Build(context.Background(), BuildOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Rendered)
+	assert.Equal(t, 1, result.Copied)
+	assert.Equal(t, `single|Blog|Hello|1|<img src="/media/`+imageID+`/cat.png"><img src="/api/v1/media/99999999-9999-9999-9999-999999999999">`,
+		string(f.output.files["2025/04/hello/index.html"]))
+	assert.Equal(t, "single|Blog|About|1|", string(f.output.files["about/index.html"]))
+	// Only posts are listed on the home page.
+	assert.Equal(t, "home|Blog|en|1", string(f.output.files["index.html"]))
+	assert.Equal(t, "png", string(f.output.files["media/"+imageID+"/cat.png"]))
+	assert.Contains(t, string(f.output.files["sitemap.xml"]), "<loc>https://example.com/hello</loc>")
+	assert.Contains(t, string(f.output.files["feed.xml"]), "<rss")
+	assert.Contains(t, string(f.output.files["atom.xml"]), "<feed")
+	assert.Equal(t, "User-agent: *\n", string(f.output.files["robots.txt"]))
+
+	var manifest static.Manifest
+	require.NoError(t, json.Unmarshal(f.output.files[static.ManifestPath], &manifest))
+	assert.Equal(t, "v1", manifest.Fingerprint)
+	paths := make([]string, len(manifest.Files))
+	for i, file := range manifest.Files {
+		paths[i] = file.Path
+	}
+	assert.Equal(t, []string{
+		"2025/04/hello/index.html", "about/index.html", "atom.xml", "feed.xml", "index.html",
+		"media/" + imageID + "/cat.png", "robots.txt", "sitemap.xml",
+	}, paths)
+	assert.Equal(t, static.File{
+		Path: "about/index.html", Size: 20, SHA256: manifest.Files[1].SHA256, Source: "content:c-2",
+		Revision: revision(about, &contentusecase.Taxonomy{Tags: f.contents.tags}),
+	}, manifest.Files[1])
+	assert.Regexp(t, `^3-[0-9a-f]{12}$`, manifest.Files[1].Revision)
+	assert.Equal(t, []string{imageID}, manifest.Files[0].Media)
+}
+
+func TestStaticUseCase_Build_Incremental(t *testing.T) {
+	f := newFixture()
+	hello := &content.Content{ID: "c-1", Type: "post", Title: "Hello", Permalink: "/hello", Version: 1, BodyHTML: `<img src="/api/v1/media/` + imageID + `">`}
+	about := &content.Content{ID: "c-2", Type: "page", Title: "About", Permalink: "/about", Version: 1}
+	f.publish(hello, about)
+	_, err := f.uc.Build(context.Background(), BuildOptions{})
+	require.NoError(t, err)
+
+	// About is unpublished and a new entry published; hello keeps its revision.
+	news := &content.Content{ID: "c-3", Type: "post", Title: "News", Permalink: "/news", Version: 1}
+	f.publish(news, hello)
+	f.output.saves = 0
+	result, err := f.uc.Build(context.Background(), BuildOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Rendered)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, 0, result.Copied)
+	assert.Equal(t, 1, result.Removed)
+	assert.NotContains(t, f.output.files, "about/index.html")
+	assert.Contains(t, f.output.files, "media/"+imageID+"/cat.png")
+	// The news page, the home page listing it and the manifest.
+	assert.Equal(t, 3, f.output.saves)
+
+	// Entries are rendered again once a tag they show is renamed.
+	f.contents.tags = []*tag.Tag{{Slug: "golang", Name: "Golang"}}
+	result, err = f.uc.Build(context.Background(), BuildOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Rendered)
+	assert.Equal(t, 0, result.Unchanged)
+
+	// Entries are rendered again once the templates change.
+	f.renderer.fingerprint = "v2"
+	result, err = f.uc.Build(context.Background(), BuildOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Rendered)
+	assert.Equal(t, 1, result.Copied)
+
+	result, err = f.uc.Build(context.Background(), BuildOptions{Full: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Rendered)
+	assert.Equal(t, 0, result.Unchanged)
+}
+
+func TestPagePath(t *testing.T) {
+	tests := map[string]string{
+		"/":                "index.html",
+		"":                 "index.html",
+		"/about":           "about/index.html",
+		"/2025/04/hello/":  "2025/04/hello/index.html",
+		"/docs/intro.html": "docs/intro.html",
+		"/../../etc":       "etc/index.html",
+	}
+	for permalink, want := range tests {
+		assert.Equal(t, want, pagePath(permalink), permalink)
+	}
+}
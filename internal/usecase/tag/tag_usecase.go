@@ -0,0 +1,160 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/utils/slug"
+)
+
+// ErrSelfMerge is returned when a tag is merged into itself.
+var ErrSelfMerge = errors.New("cannot merge a tag into itself")
+
+const maxAutocompleteResults = 20
+
+type UseCase interface {
+	Create(ctx context.Context, t *tag.Tag) error
+	Get(ctx context.Context, id string) (*tag.Tag, error)
+	Update(ctx context.Context, t *tag.Tag) error
+	// Delete moves a tag to the trash.
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, limit, offset int) ([]*tag.Tag, error)
+	Autocomplete(ctx context.Context, prefix string, limit int) ([]*tag.Tag, error)
+	Merge(ctx context.Context, sourceID, targetID string) (*tag.Tag, error)
+	// ListContent returns published content carrying the tag identified by slug.
+	// Entries are restricted to viewer, with the teasers it is not admitted to locked.
+	ListContent(ctx context.Context, slug string, limit, offset int, viewer content.Viewer) ([]*content.Content, error)
+	// The trash of tags. Content keeps its assignments to a trashed tag.
+	trash.Bin
+}
+
+type tagUseCase struct {
+	tagRepo     tag.Repository
+	contentRepo content.Repository
+}
+
+func NewTagUseCase(tagRepo tag.Repository, contentRepo content.Repository) UseCase {
+	return &tagUseCase{
+		tagRepo:     tagRepo,
+		contentRepo: contentRepo,
+	}
+}
+
+func (uc *tagUseCase) Create(ctx context.Context, t *tag.Tag) error {
+	if err := uc.assignSlug(ctx, t); err != nil {
+		return err
+	}
+	return uc.tagRepo.Create(ctx, t)
+}
+
+func (uc *tagUseCase) Get(ctx context.Context, id string) (*tag.Tag, error) {
+	return uc.tagRepo.GetByID(ctx, id)
+}
+
+func (uc *tagUseCase) Update(ctx context.Context, t *tag.Tag) error {
+	existing, err := uc.tagRepo.GetByID(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	if t.Version, err = lock.Expect(t.Version, existing.Version); err != nil {
+		return err
+	}
+	if err := uc.assignSlug(ctx, t); err != nil {
+		return err
+	}
+	return uc.tagRepo.Update(ctx, t)
+}
+
+func (uc *tagUseCase) Delete(ctx context.Context, id string) error {
+	return uc.tagRepo.Delete(ctx, id)
+}
+
+func (uc *tagUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	return uc.tagRepo.ListTrashed(ctx, filter)
+}
+
+func (uc *tagUseCase) Restore(ctx context.Context, id string) error {
+	return uc.tagRepo.Restore(ctx, id)
+}
+
+func (uc *tagUseCase) Purge(ctx context.Context, id string) error {
+	return uc.tagRepo.Purge(ctx, id)
+}
+
+func (uc *tagUseCase) List(ctx context.Context, limit, offset int) ([]*tag.Tag, error) {
+	return uc.tagRepo.List(ctx, limit, offset)
+}
+
+func (uc *tagUseCase) Autocomplete(ctx context.Context, prefix string, limit int) ([]*tag.Tag, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return []*tag.Tag{}, nil
+	}
+	if limit <= 0 || limit > maxAutocompleteResults {
+		limit = maxAutocompleteResults
+	}
+	return uc.tagRepo.Search(ctx, prefix, limit)
+}
+
+func (uc *tagUseCase) Merge(ctx context.Context, sourceID, targetID string) (*tag.Tag, error) {
+	if sourceID == targetID {
+		return nil, ErrSelfMerge
+	}
+	target, err := uc.tagRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.tagRepo.Merge(ctx, sourceID, targetID); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+func (uc *tagUseCase) ListContent(ctx context.Context, slug string, limit, offset int, viewer content.Viewer) ([]*content.Content, error) {
+	t, err := uc.tagRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	viewer = viewer.WithoutPassword()
+	entries, err := uc.contentRepo.List(ctx, content.ListFilter{
+		Status:          content.StatusPublished,
+		PublishedBefore: time.Now(),
+		TagID:           t.ID,
+		Viewer:          &viewer,
+		Limit:           limit,
+		Offset:          offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range entries {
+		c.Restrict(viewer)
+	}
+	return entries, nil
+}
+
+// assignSlug derives the slug from the name when missing and makes it unique. Slugs of trashed tags
+// stay taken so the tags can be restored.
+func (uc *tagUseCase) assignSlug(ctx context.Context, t *tag.Tag) error {
+	base := t.Slug
+	if base == "" {
+		base = t.Name
+	}
+
+	unique, err := slug.Unique(ctx, base, func(ctx context.Context, candidate string) (bool, error) {
+		return uc.tagRepo.SlugInUse(ctx, candidate, t.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	t.Slug = unique
+	return nil
+}
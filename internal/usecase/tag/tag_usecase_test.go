@@ -0,0 +1,186 @@
+package tag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) Create(ctx context.Context, t *tag.Tag) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetByID(ctx context.Context, id string) (*tag.Tag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetBySlug(ctx context.Context, slug string) (*tag.Tag, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Update(ctx context.Context, t *tag.Tag) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) List(ctx context.Context, limit, offset int) ([]*tag.Tag, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Search(ctx context.Context, prefix string, limit int) ([]*tag.Tag, error) {
+	args := m.Called(ctx, prefix, limit)
+	return args.Get(0).([]*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) ListByContent(ctx context.Context, contentID string) ([]*tag.Tag, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).([]*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) SetContentTags(ctx context.Context, contentID string, tagIDs []string) error {
+	args := m.Called(ctx, contentID, tagIDs)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Merge(ctx context.Context, sourceID, targetID string) error {
+	args := m.Called(ctx, sourceID, targetID)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTagRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockTagRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockContentRepository struct {
+	mock.Mock
+	content.Repository
+}
+
+func (m *MockContentRepository) List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func TestTagUseCase_Autocomplete(t *testing.T) {
+	repo := new(MockTagRepository)
+	uc := NewTagUseCase(repo, new(MockContentRepository))
+
+	repo.On("Search", mock.Anything, "go", maxAutocompleteResults).Return([]*tag.Tag{{Name: "golang"}}, nil)
+
+	tags, err := uc.Autocomplete(context.Background(), "  go ", 500)
+	require.NoError(t, err)
+	assert.Len(t, tags, 1)
+
+	tags, err = uc.Autocomplete(context.Background(), "   ", 5)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+	repo.AssertNumberOfCalls(t, "Search", 1)
+}
+
+func TestTagUseCase_Merge(t *testing.T) {
+	repo := new(MockTagRepository)
+	uc := NewTagUseCase(repo, new(MockContentRepository))
+
+	target := &tag.Tag{ID: "target", Name: "Go"}
+	repo.On("GetByID", mock.Anything, "target").Return(target, nil)
+	repo.On("Merge", mock.Anything, "source", "target").Return(nil)
+
+	got, err := uc.Merge(context.Background(), "source", "target")
+	require.NoError(t, err)
+	assert.Equal(t, target, got)
+	repo.AssertExpectations(t)
+}
+
+func TestTagUseCase_Merge_Errors(t *testing.T) {
+	repo := new(MockTagRepository)
+	uc := NewTagUseCase(repo, new(MockContentRepository))
+
+	_, err := uc.Merge(context.Background(), "same", "same")
+	assert.ErrorIs(t, err, ErrSelfMerge)
+
+	repo.On("GetByID", mock.Anything, "missing").Return(nil, tag.ErrNotFound)
+	_, err = uc.Merge(context.Background(), "source", "missing")
+	assert.ErrorIs(t, err, tag.ErrNotFound)
+	repo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTagUseCase_ListContent(t *testing.T) {
+	repo := new(MockTagRepository)
+	contentRepo := new(MockContentRepository)
+	uc := NewTagUseCase(repo, contentRepo)
+
+	repo.On("GetBySlug", mock.Anything, "go").Return(&tag.Tag{ID: "tag-1"}, nil)
+	contentRepo.On("List", mock.Anything, mock.MatchedBy(func(f content.ListFilter) bool {
+		return f.TagID == "tag-1" && f.Status == content.StatusPublished &&
+			f.Viewer != nil && *f.Viewer == content.Viewer{UserID: "user-1", Role: "user"}
+	})).Return([]*content.Content{
+		{ID: "content-1"},
+		{ID: "content-2", Visibility: content.VisibilityRoles, AllowedRoles: []string{"editor"}, Teaser: true, Body: "Full"},
+	}, nil)
+
+	entries, err := uc.ListContent(context.Background(), "go", 10, 0, content.Viewer{UserID: "user-1", Role: "user", Password: "s3cret"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.False(t, entries[0].Locked)
+	assert.True(t, entries[1].Locked)
+	assert.Empty(t, entries[1].Body)
+}
+
+func TestTagUseCase_Create_GeneratesUniqueSlug(t *testing.T) {
+	repo := new(MockTagRepository)
+	uc := NewTagUseCase(repo, new(MockContentRepository))
+
+	// A trashed tag still holds its slug.
+	repo.On("SlugInUse", mock.Anything, "go", "").Return(true, nil)
+	repo.On("SlugInUse", mock.Anything, "go-2", "").Return(false, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(tg *tag.Tag) bool {
+		return tg.Slug == "go-2"
+	})).Return(nil)
+
+	assert.NoError(t, uc.Create(context.Background(), &tag.Tag{Name: "Go"}))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,310 @@
+package theme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/setting"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+)
+
+// Defaults applied to zero Options fields.
+const (
+	DefaultListSize = 10
+	DefaultListType = "post"
+	// DefaultRefreshInterval bounds how long another instance keeps rendering with a theme after an
+	// administrator switched themes.
+	DefaultRefreshInterval = 30 * time.Second
+)
+
+// ErrInvalidTheme is returned when activating a theme whose templates do not parse.
+var ErrInvalidTheme = errors.New("invalid theme")
+
+// maxPage keeps list pages from turning into arbitrarily deep scans.
+const maxPage = 1000
+
+// Options configures the rendered public site.
+type Options struct {
+	// SiteURL is the absolute URL of the public site, e.g. "https://example.com".
+	SiteURL string
+	Title   string
+	// DefaultTheme is rendered with until another theme is activated.
+	DefaultTheme string
+	// ListSize is the number of entries per list page.
+	ListSize int
+	// ListType is the type of the entries listed on the home page.
+	ListType string
+	// RefreshInterval is how often the active theme setting is read again.
+	RefreshInterval time.Duration
+}
+
+// Themes lists the installed themes along with the active one.
+type Themes struct {
+	Active    string   `json:"active"`
+	Available []string `json:"available"`
+}
+
+type UseCase interface {
+	List(ctx context.Context) (*Themes, error)
+	// Activate switches the public site to the named theme, once it parses.
+	Activate(ctx context.Context, name string) error
+	// Reload loads the active theme named by the settings.
+	Reload(ctx context.Context) error
+	// Render renders page with the named template of the active theme.
+	Render(ctx context.Context, w io.Writer, template string, page *theme.Page) error
+	// Home returns the nth page of the latest entries. Lists hold translation groups once, in the
+	// first of locales available, and only the entries viewer may read or the teasers it is locked
+	// out of.
+	Home(ctx context.Context, n int, locales []string, viewer content.Viewer) (*theme.Page, error)
+	// Entry returns the page of the published entry served at path. When path is a former
+	// permalink, the current permalink is returned instead so callers can redirect. Teasers viewer
+	// is not admitted to are returned locked.
+	Entry(ctx context.Context, path string, locales []string, viewer content.Viewer) (*theme.Page, string, error)
+	// Category returns the nth page of the entries of a category, including its descendants.
+	Category(ctx context.Context, slug string, n int, locales []string, viewer content.Viewer) (*theme.Page, error)
+	Tag(ctx context.Context, slug string, n int, locales []string, viewer content.Viewer) (*theme.Page, error)
+	// NotFound returns the page of paths nothing is published at.
+	NotFound(path string, locales []string) *theme.Page
+}
+
+type themeUseCase struct {
+	contents     contentusecase.UseCase
+	categoryRepo category.Repository
+	tagRepo      tag.Repository
+	settings     setting.Repository
+	loader       theme.Loader
+	opts         Options
+
+	mu        sync.RWMutex
+	active    string
+	renderer  theme.Renderer
+	checkedAt time.Time
+}
+
+func NewThemeUseCase(contents contentusecase.UseCase, categoryRepo category.Repository, tagRepo tag.Repository, settings setting.Repository, loader theme.Loader, opts Options) UseCase {
+	opts.SiteURL = strings.TrimSuffix(opts.SiteURL, "/")
+	if opts.DefaultTheme == "" {
+		opts.DefaultTheme = theme.DefaultTheme
+	}
+	if opts.ListSize <= 0 {
+		opts.ListSize = DefaultListSize
+	}
+	if opts.ListType == "" {
+		opts.ListType = DefaultListType
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DefaultRefreshInterval
+	}
+	return &themeUseCase{
+		contents:     contents,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		settings:     settings,
+		loader:       loader,
+		opts:         opts,
+	}
+}
+
+func (uc *themeUseCase) List(ctx context.Context) (*Themes, error) {
+	available, err := uc.loader.List()
+	if err != nil {
+		return nil, err
+	}
+	active, err := uc.activeName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Themes{Active: active, Available: available}, nil
+}
+
+func (uc *themeUseCase) Activate(ctx context.Context, name string) error {
+	renderer, err := uc.loader.Load(name)
+	if errors.Is(err, theme.ErrNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+	}
+	if err := uc.settings.Save(ctx, &setting.Setting{Key: setting.KeyTheme, Value: name}); err != nil {
+		return err
+	}
+	uc.swap(name, renderer)
+	return nil
+}
+
+func (uc *themeUseCase) Reload(ctx context.Context) error {
+	name, err := uc.activeName(ctx)
+	if err != nil {
+		return err
+	}
+
+	uc.mu.RLock()
+	unchanged := uc.renderer != nil && uc.active == name
+	uc.mu.RUnlock()
+	if unchanged {
+		uc.swap(name, nil)
+		return nil
+	}
+
+	renderer, err := uc.loader.Load(name)
+	if errors.Is(err, theme.ErrNotFound) && name != uc.opts.DefaultTheme {
+		// The active theme was uninstalled; the site stays up with the default one.
+		log.Printf("theme %q not found, rendering with %q", name, uc.opts.DefaultTheme)
+		name = uc.opts.DefaultTheme
+		renderer, err = uc.loader.Load(name)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot load theme %q: %w", name, err)
+	}
+	uc.swap(name, renderer)
+	return nil
+}
+
+func (uc *themeUseCase) Render(ctx context.Context, w io.Writer, template string, page *theme.Page) error {
+	uc.mu.RLock()
+	renderer, stale := uc.renderer, time.Since(uc.checkedAt) >= uc.opts.RefreshInterval
+	uc.mu.RUnlock()
+
+	if renderer == nil || stale {
+		if err := uc.Reload(ctx); err != nil {
+			if renderer == nil {
+				return err
+			}
+			// Keep rendering with the loaded theme until the settings can be read again.
+			log.Printf("cannot reload theme: %v", err)
+		}
+		uc.mu.RLock()
+		renderer = uc.renderer
+		uc.mu.RUnlock()
+	}
+
+	page.Site = theme.Site{Title: uc.opts.Title, URL: uc.opts.SiteURL}
+	return renderer.Render(w, template, page)
+}
+
+func (uc *themeUseCase) Home(ctx context.Context, n int, locales []string, viewer content.Viewer) (*theme.Page, error) {
+	page := &theme.Page{Path: "/", Locale: uc.locale(locales)}
+	return page, uc.list(ctx, page, content.ListFilter{Type: uc.opts.ListType, Viewer: &viewer}, n, locales)
+}
+
+func (uc *themeUseCase) Entry(ctx context.Context, path string, locales []string, viewer content.Viewer) (*theme.Page, string, error) {
+	entry, location, err := uc.contents.Resolve(ctx, path, locales, viewer)
+	if err != nil || location != "" {
+		return nil, location, err
+	}
+	taxonomy, err := uc.contents.GetTaxonomy(ctx, entry.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	return &theme.Page{
+		Title:      entry.Title,
+		Path:       entry.Permalink,
+		Locale:     entry.Locale,
+		Entry:      entry,
+		Categories: taxonomy.Categories,
+		Tags:       taxonomy.Tags,
+	}, "", nil
+}
+
+func (uc *themeUseCase) Category(ctx context.Context, slug string, n int, locales []string, viewer content.Viewer) (*theme.Page, error) {
+	root, err := uc.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	all, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &theme.Page{
+		Title:  root.Name,
+		Locale: uc.locale(locales),
+		Term:   &theme.Term{Kind: theme.TermCategory, Name: root.Name, Slug: root.Slug, Description: root.Description},
+	}
+	return page, uc.list(ctx, page, content.ListFilter{CategoryIDs: category.DescendantIDs(all, root.ID), Viewer: &viewer}, n, locales)
+}
+
+func (uc *themeUseCase) Tag(ctx context.Context, slug string, n int, locales []string, viewer content.Viewer) (*theme.Page, error) {
+	t, err := uc.tagRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &theme.Page{
+		Title:  t.Name,
+		Locale: uc.locale(locales),
+		Term:   &theme.Term{Kind: theme.TermTag, Name: t.Name, Slug: t.Slug},
+	}
+	return page, uc.list(ctx, page, content.ListFilter{TagID: t.ID, Viewer: &viewer}, n, locales)
+}
+
+func (uc *themeUseCase) NotFound(path string, locales []string) *theme.Page {
+	return &theme.Page{Path: path, Locale: uc.locale(locales)}
+}
+
+// list fills page with the nth page of the published entries matching filter. Pages past the last
+// one are not found.
+func (uc *themeUseCase) list(ctx context.Context, page *theme.Page, filter content.ListFilter, n int, locales []string) error {
+	if n < 1 || n > maxPage {
+		return content.ErrNotFound
+	}
+	// One more entry than fits tells whether there is a next page.
+	filter.Limit = uc.opts.ListSize + 1
+	filter.Offset = (n - 1) * uc.opts.ListSize
+	entries, err := uc.contents.ListPublished(ctx, filter, locales)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 && n > 1 {
+		return content.ErrNotFound
+	}
+
+	if len(entries) > uc.opts.ListSize {
+		entries = entries[:uc.opts.ListSize]
+		page.NextPage = n + 1
+	}
+	if n > 1 {
+		page.PrevPage = n - 1
+	}
+	page.Entries = entries
+	return nil
+}
+
+// activeName reads the name of the active theme from the settings.
+func (uc *themeUseCase) activeName(ctx context.Context) (string, error) {
+	s, err := uc.settings.Get(ctx, setting.KeyTheme)
+	if errors.Is(err, setting.ErrNotFound) {
+		return uc.opts.DefaultTheme, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return s.Value, nil
+}
+
+// swap installs a loaded theme, or only records that the settings were checked when renderer is nil.
+func (uc *themeUseCase) swap(name string, renderer theme.Renderer) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if renderer != nil {
+		uc.active, uc.renderer = name, renderer
+	}
+	uc.checkedAt = time.Now()
+}
+
+func (uc *themeUseCase) locale(locales []string) string {
+	if len(locales) > 0 {
+		return locales[0]
+	}
+	return uc.contents.Locales().Default()
+}
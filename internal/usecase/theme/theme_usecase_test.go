@@ -0,0 +1,316 @@
+package theme
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/setting"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/theme"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	"github.com/mashurimansur/goCMS/internal/utils/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockContentUseCase struct {
+	mock.Mock
+	contentusecase.UseCase
+}
+
+func (m *MockContentUseCase) ListPublished(ctx context.Context, filter content.ListFilter, locales []string) ([]*content.Content, error) {
+	args := m.Called(ctx, filter, locales)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+func (m *MockContentUseCase) Resolve(ctx context.Context, path string, locales []string, viewer content.Viewer) (*content.Content, string, error) {
+	args := m.Called(ctx, path, locales, viewer)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).(*content.Content), args.String(1), args.Error(2)
+}
+
+func (m *MockContentUseCase) GetTaxonomy(ctx context.Context, id string) (*contentusecase.Taxonomy, error) {
+	return &contentusecase.Taxonomy{Tags: []*tag.Tag{{Name: "Go", Slug: "go"}}}, nil
+}
+
+func (m *MockContentUseCase) Locales() locale.Settings {
+	settings, _ := locale.Parse("en", "")
+	return settings
+}
+
+type MockCategoryRepository struct {
+	mock.Mock
+	category.Repository
+}
+
+func (m *MockCategoryRepository) GetBySlug(ctx context.Context, slug string) (*category.Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) List(ctx context.Context) ([]*category.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockTagRepository struct {
+	mock.Mock
+	tag.Repository
+}
+
+func (m *MockTagRepository) GetBySlug(ctx context.Context, slug string) (*tag.Tag, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTagRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockTagRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// memorySettings is an in-memory setting.Repository.
+type memorySettings struct {
+	values map[string]string
+	err    error
+}
+
+func (s *memorySettings) Get(_ context.Context, key string) (*setting.Setting, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	value, ok := s.values[key]
+	if !ok {
+		return nil, setting.ErrNotFound
+	}
+	return &setting.Setting{Key: key, Value: value}, nil
+}
+
+func (s *memorySettings) Save(_ context.Context, st *setting.Setting) error {
+	s.values[st.Key] = st.Value
+	return nil
+}
+
+// stubLoader loads themes rendering their name, the template name and the page title.
+type stubLoader struct {
+	themes map[string]error
+	loads  int
+}
+
+func (l *stubLoader) List() ([]string, error) {
+	return []string{"default", "minimal"}, nil
+}
+
+func (l *stubLoader) Load(name string) (theme.Renderer, error) {
+	err, ok := l.themes[name]
+	if !ok {
+		return nil, theme.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	l.loads++
+	return stubRenderer(name), nil
+}
+
+type stubRenderer string
+
+func (r stubRenderer) Render(w io.Writer, name string, page *theme.Page) error {
+	_, err := fmt.Fprintf(w, "%s|%s|%s|%s", r, name, page.Site.Title, page.Title)
+	return err
+}
+
+func (r stubRenderer) Fingerprint() string {
+	return string(r)
+}
+
+type fixture struct {
+	contents   *MockContentUseCase
+	categories *MockCategoryRepository
+	tags       *MockTagRepository
+	settings   *memorySettings
+	loader     *stubLoader
+	uc         UseCase
+}
+
+func newFixture(opts Options) *fixture {
+	f := &fixture{
+		contents:   new(MockContentUseCase),
+		categories: new(MockCategoryRepository),
+		tags:       new(MockTagRepository),
+		settings:   &memorySettings{values: map[string]string{}},
+		loader:     &stubLoader{themes: map[string]error{"default": nil, "minimal": nil, "broken": errors.New("theme has no single.html")}},
+	}
+	opts.Title = "Blog"
+	f.uc = NewThemeUseCase(f.contents, f.categories, f.tags, f.settings, f.loader, opts)
+	return f
+}
+
+func (f *fixture) render(t *testing.T, template string) string {
+	var buf bytes.Buffer
+	require.NoError(t, f.uc.Render(context.Background(), &buf, template, &theme.Page{Title: "Hello"}))
+	return buf.String()
+}
+
+func TestThemeUseCase_Activate(t *testing.T) {
+	f := newFixture(Options{})
+	require.NoError(t, f.uc.Reload(context.Background()))
+	assert.Equal(t, "default|single|Blog|Hello", f.render(t, theme.TemplateSingle))
+
+	require.NoError(t, f.uc.Activate(context.Background(), "minimal"))
+	assert.Equal(t, "minimal", f.settings.values[setting.KeyTheme])
+	assert.Equal(t, "minimal|single|Blog|Hello", f.render(t, theme.TemplateSingle))
+
+	themes, err := f.uc.List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &Themes{Active: "minimal", Available: []string{"default", "minimal"}}, themes)
+
+	assert.ErrorIs(t, f.uc.Activate(context.Background(), "broken"), ErrInvalidTheme)
+	assert.ErrorIs(t, f.uc.Activate(context.Background(), "missing"), theme.ErrNotFound)
+	assert.Equal(t, "minimal", f.settings.values[setting.KeyTheme])
+}
+
+func TestThemeUseCase_Render_Refresh(t *testing.T) {
+	f := newFixture(Options{RefreshInterval: time.Nanosecond})
+	assert.Equal(t, "default|home|Blog|Hello", f.render(t, theme.TemplateHome))
+
+	// Another instance switched themes.
+	f.settings.values[setting.KeyTheme] = "minimal"
+	assert.Equal(t, "minimal|home|Blog|Hello", f.render(t, theme.TemplateHome))
+	assert.Equal(t, "minimal|home|Blog|Hello", f.render(t, theme.TemplateHome))
+	assert.Equal(t, 2, f.loader.loads)
+
+	// The loaded theme keeps rendering while the settings cannot be read.
+	f.settings.err = errors.New("connection refused")
+	assert.Equal(t, "minimal|home|Blog|Hello", f.render(t, theme.TemplateHome))
+}
+
+func TestThemeUseCase_Reload_MissingTheme(t *testing.T) {
+	f := newFixture(Options{})
+	f.settings.values[setting.KeyTheme] = "uninstalled"
+
+	require.NoError(t, f.uc.Reload(context.Background()))
+	assert.Equal(t, "default|single|Blog|Hello", f.render(t, theme.TemplateSingle))
+}
+
+func TestThemeUseCase_Home(t *testing.T) {
+	f := newFixture(Options{ListSize: 2})
+	entries := []*content.Content{{ID: "c-1"}, {ID: "c-2"}, {ID: "c-3"}}
+	member := content.Viewer{UserID: "user-1", Role: "user"}
+	f.contents.On("ListPublished", mock.Anything, content.ListFilter{Type: "post", Viewer: &member, Limit: 3}, []string{"id", "en"}).Return(entries, nil)
+	f.contents.On("ListPublished", mock.Anything, content.ListFilter{Type: "post", Viewer: &content.Viewer{}, Limit: 3, Offset: 2}, []string(nil)).Return(entries[2:], nil)
+	f.contents.On("ListPublished", mock.Anything, content.ListFilter{Type: "post", Viewer: &content.Viewer{}, Limit: 3, Offset: 4}, []string(nil)).Return([]*content.Content{}, nil)
+
+	page, err := f.uc.Home(context.Background(), 1, []string{"id", "en"}, member)
+	require.NoError(t, err)
+	assert.Equal(t, "id", page.Locale)
+	assert.Equal(t, entries[:2], page.Entries)
+	assert.Equal(t, 0, page.PrevPage)
+	assert.Equal(t, 2, page.NextPage)
+
+	page, err = f.uc.Home(context.Background(), 2, nil, content.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, "en", page.Locale)
+	assert.Equal(t, entries[2:], page.Entries)
+	assert.Equal(t, 1, page.PrevPage)
+	assert.Equal(t, 0, page.NextPage)
+
+	_, err = f.uc.Home(context.Background(), 3, nil, content.Viewer{})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+	_, err = f.uc.Home(context.Background(), 0, nil, content.Viewer{})
+	assert.ErrorIs(t, err, content.ErrNotFound)
+}
+
+func TestThemeUseCase_Entry(t *testing.T) {
+	f := newFixture(Options{})
+	hello := &content.Content{ID: "c-1", Title: "Hello", Permalink: "/hello", Locale: "en"}
+	f.contents.On("Resolve", mock.Anything, "/hello", []string{"en"}, content.Viewer{}).Return(hello, "", nil)
+	f.contents.On("Resolve", mock.Anything, "/old-hello", []string{"en"}, content.Viewer{}).Return(nil, "/hello", nil)
+
+	page, location, err := f.uc.Entry(context.Background(), "/hello", []string{"en"}, content.Viewer{})
+	require.NoError(t, err)
+	assert.Empty(t, location)
+	assert.Equal(t, hello, page.Entry)
+	assert.Equal(t, "Hello", page.Title)
+	assert.Len(t, page.Tags, 1)
+
+	page, location, err = f.uc.Entry(context.Background(), "/old-hello", []string{"en"}, content.Viewer{})
+	require.NoError(t, err)
+	assert.Nil(t, page)
+	assert.Equal(t, "/hello", location)
+}
+
+func TestThemeUseCase_Category(t *testing.T) {
+	f := newFixture(Options{})
+	news := &category.Category{ID: "cat-1", Name: "News", Slug: "news"}
+	f.categories.On("GetBySlug", mock.Anything, "news").Return(news, nil)
+	f.categories.On("GetBySlug", mock.Anything, "missing").Return(nil, category.ErrNotFound)
+	f.categories.On("List", mock.Anything).Return([]*category.Category{news, {ID: "cat-2", ParentID: "cat-1"}, {ID: "cat-3"}}, nil)
+	f.contents.On("ListPublished", mock.Anything, content.ListFilter{CategoryIDs: []string{"cat-1", "cat-2"}, Viewer: &content.Viewer{}, Limit: 11}, []string(nil)).
+		Return([]*content.Content{{ID: "c-1"}}, nil)
+
+	page, err := f.uc.Category(context.Background(), "news", 1, nil, content.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, &theme.Term{Kind: theme.TermCategory, Name: "News", Slug: "news"}, page.Term)
+	assert.Len(t, page.Entries, 1)
+
+	_, err = f.uc.Category(context.Background(), "missing", 1, nil, content.Viewer{})
+	assert.ErrorIs(t, err, category.ErrNotFound)
+}
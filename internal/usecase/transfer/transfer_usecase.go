@@ -0,0 +1,585 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/transfer"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	"github.com/mashurimansur/goCMS/internal/utils/slug"
+)
+
+// ErrInvalidStrategy is returned for an unknown slug conflict strategy.
+var ErrInvalidStrategy = errors.New("invalid slug conflict strategy")
+
+// Slug conflict strategies, applied to imported entries whose slug is taken by an existing entry of
+// the same type and locale.
+const (
+	// SlugRename imports the entry under a free slug derived from its own.
+	SlugRename = "rename"
+	// SlugSkip leaves the existing entry alone and does not import the entry.
+	SlugSkip = "skip"
+	// SlugOverwrite replaces the existing entry with the imported one.
+	SlugOverwrite = "overwrite"
+)
+
+// Kinds of imported items, as named in reports.
+const (
+	KindUser     = "user"
+	KindCategory = "category"
+	KindTag      = "tag"
+	KindMedia    = "media"
+	KindContent  = "content"
+)
+
+// pageSize is the number of rows read per page while exporting.
+const pageSize = 100
+
+// ImportOptions configures an import.
+type ImportOptions struct {
+	// SlugConflict is one of the slug conflict strategies; it defaults to SlugRename.
+	SlugConflict string
+	// DryRun reports what the import would do without writing anything.
+	DryRun bool
+}
+
+// Counts tells what happened to the items of one kind.
+type Counts struct {
+	Created int `json:"created"`
+	// Matched counts items that already existed, such as users with the same email or tags with the
+	// same slug, and were used instead of importing a copy.
+	Matched int `json:"matched"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// Note explains what happened to one imported item when it is worth telling.
+type Note struct {
+	Kind string `json:"kind"`
+	// ID is the ID of the item in the bundle.
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// Report sums up an import.
+type Report struct {
+	DryRun     bool   `json:"dry_run"`
+	Users      Counts `json:"users"`
+	Categories Counts `json:"categories"`
+	Tags       Counts `json:"tags"`
+	Media      Counts `json:"media"`
+	Contents   Counts `json:"contents"`
+	Notes      []Note `json:"notes"`
+}
+
+type UseCase interface {
+	// Export copies the whole site into a bundle.
+	Export(ctx context.Context) (*transfer.Bundle, error)
+	// Import adds the items of a bundle to the site. Every item gets a new ID and references between
+	// items follow; users, categories and tags that already exist are reused. Items that cannot be
+	// imported are reported and do not stop the import.
+	Import(ctx context.Context, b *transfer.Bundle, opts ImportOptions) (*Report, error)
+}
+
+type transferUseCase struct {
+	contents     contentusecase.UseCase
+	contentRepo  content.Repository
+	categoryRepo category.Repository
+	tagRepo      tag.Repository
+	mediaRepo    media.Repository
+	userRepo     user.Repository
+}
+
+func NewTransferUseCase(contents contentusecase.UseCase, contentRepo content.Repository, categoryRepo category.Repository, tagRepo tag.Repository, mediaRepo media.Repository, userRepo user.Repository) UseCase {
+	return &transferUseCase{
+		contents:     contents,
+		contentRepo:  contentRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		mediaRepo:    mediaRepo,
+		userRepo:     userRepo,
+	}
+}
+
+func (uc *transferUseCase) Export(ctx context.Context) (*transfer.Bundle, error) {
+	b := &transfer.Bundle{Version: transfer.FormatVersion, ExportedAt: time.Now()}
+
+	for offset := 0; ; offset += pageSize {
+		users, err := uc.userRepo.List(ctx, pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		b.Users = append(b.Users, users...)
+		if len(users) < pageSize {
+			break
+		}
+	}
+
+	categories, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Categories = categories
+
+	for offset := 0; ; offset += pageSize {
+		tags, err := uc.tagRepo.List(ctx, pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		b.Tags = append(b.Tags, tags...)
+		if len(tags) < pageSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += pageSize {
+		items, err := uc.mediaRepo.List(ctx, media.ListFilter{Limit: pageSize, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range items {
+			b.Media = append(b.Media, &transfer.Media{Media: *m, StorageKey: m.StorageKey})
+		}
+		if len(items) < pageSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += pageSize {
+		entries, err := uc.contentRepo.List(ctx, content.ListFilter{Limit: pageSize, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range entries {
+			entry, err := uc.exportContent(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			b.Contents = append(b.Contents, entry)
+		}
+		if len(entries) < pageSize {
+			break
+		}
+	}
+	return b, nil
+}
+
+func (uc *transferUseCase) exportContent(ctx context.Context, c *content.Content) (*transfer.Content, error) {
+	entry := &transfer.Content{Content: *c, CategoryIDs: []string{}, TagIDs: []string{}, PasswordHash: c.PasswordHash}
+	categories, err := uc.categoryRepo.ListByContent(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, cat := range categories {
+		entry.CategoryIDs = append(entry.CategoryIDs, cat.ID)
+	}
+	tags, err := uc.tagRepo.ListByContent(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		entry.TagIDs = append(entry.TagIDs, t.ID)
+	}
+	return entry, nil
+}
+
+func (uc *transferUseCase) Import(ctx context.Context, b *transfer.Bundle, opts ImportOptions) (*Report, error) {
+	if b.Version < 1 || b.Version > transfer.FormatVersion {
+		return nil, fmt.Errorf("%w: %d, this version reads up to %d", transfer.ErrUnsupportedVersion, b.Version, transfer.FormatVersion)
+	}
+	if opts.SlugConflict == "" {
+		opts.SlugConflict = SlugRename
+	}
+	if !slices.Contains([]string{SlugRename, SlugSkip, SlugOverwrite}, opts.SlugConflict) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStrategy, opts.SlugConflict)
+	}
+
+	imp := &importer{
+		transferUseCase: uc,
+		opts:            opts,
+		report:          &Report{DryRun: opts.DryRun, Notes: []Note{}},
+		ids:             make(map[string]map[string]string),
+	}
+	// Items are imported before the items referencing them.
+	imp.importUsers(ctx, b.Users)
+	imp.importCategories(ctx, b.Categories)
+	imp.importTags(ctx, b.Tags)
+	imp.importMedia(ctx, b.Media)
+	imp.importContents(ctx, b.Contents)
+	return imp.report, nil
+}
+
+// importer carries the state of one import: the report and the IDs items of the bundle got on
+// this site, by kind. In a dry run items keep their bundle IDs.
+type importer struct {
+	*transferUseCase
+	opts   ImportOptions
+	report *Report
+	ids    map[string]map[string]string
+}
+
+func (imp *importer) remap(kind, bundleID, id string) {
+	if imp.ids[kind] == nil {
+		imp.ids[kind] = make(map[string]string)
+	}
+	imp.ids[kind][bundleID] = id
+}
+
+func (imp *importer) lookup(kind, bundleID string) (string, bool) {
+	id, ok := imp.ids[kind][bundleID]
+	return id, ok
+}
+
+// lookupAll maps bundle IDs to site IDs, leaving out the items that were not imported.
+func (imp *importer) lookupAll(kind string, bundleIDs []string) []string {
+	ids := []string{}
+	for _, bundleID := range bundleIDs {
+		if id, ok := imp.lookup(kind, bundleID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (imp *importer) note(kind, bundleID, format string, args ...any) {
+	imp.report.Notes = append(imp.report.Notes, Note{Kind: kind, ID: bundleID, Message: fmt.Sprintf(format, args...)})
+}
+
+func (imp *importer) fail(kind, bundleID string, counts *Counts, err error) {
+	counts.Failed++
+	imp.note(kind, bundleID, "%v", err)
+}
+
+// importUsers imports users without a password. Users whose email is known are reused.
+func (imp *importer) importUsers(ctx context.Context, users []*user.User) {
+	counts := &imp.report.Users
+	for _, u := range users {
+		if u.Email == "" {
+			imp.fail(KindUser, u.ID, counts, errors.New("user has no email"))
+			continue
+		}
+		existing, err := imp.userRepo.GetByEmail(ctx, u.Email)
+		if err != nil {
+			imp.fail(KindUser, u.ID, counts, err)
+			continue
+		}
+		if existing != nil {
+			imp.remap(KindUser, u.ID, existing.ID)
+			counts.Matched++
+			continue
+		}
+		if u.Username != "" {
+			taken, err := imp.userRepo.GetByUsername(ctx, u.Username)
+			if err != nil {
+				imp.fail(KindUser, u.ID, counts, err)
+				continue
+			}
+			if taken != nil {
+				imp.fail(KindUser, u.ID, counts, fmt.Errorf("username %q is taken", u.Username))
+				continue
+			}
+		}
+
+		created := *u
+		created.ID = ""
+		created.PasswordHash = ""
+		if created.Role == "" {
+			created.Role = user.RoleUser
+		}
+		if created.Status == "" {
+			created.Status = "active"
+		}
+		if imp.opts.DryRun {
+			imp.remap(KindUser, u.ID, u.ID)
+			counts.Created++
+			continue
+		}
+		if err := imp.userRepo.Create(ctx, &created); err != nil {
+			imp.fail(KindUser, u.ID, counts, err)
+			continue
+		}
+		imp.remap(KindUser, u.ID, created.ID)
+		counts.Created++
+	}
+	if counts.Created > 0 {
+		imp.note(KindUser, "", "imported users have no password and cannot log in until one is set")
+	}
+}
+
+// importCategories imports categories parents first. Categories whose slug is known are reused.
+func (imp *importer) importCategories(ctx context.Context, categories []*category.Category) {
+	byID := make(map[string]*category.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+	depth := func(c *category.Category) int {
+		d := 0
+		seen := map[string]bool{c.ID: true}
+		for parent, ok := byID[c.ParentID]; ok && !seen[parent.ID]; parent, ok = byID[parent.ParentID] {
+			seen[parent.ID] = true
+			d++
+		}
+		return d
+	}
+	ordered := slices.Clone(categories)
+	sort.SliceStable(ordered, func(i, j int) bool { return depth(ordered[i]) < depth(ordered[j]) })
+
+	counts := &imp.report.Categories
+	for _, c := range ordered {
+		s := c.Slug
+		if s == "" {
+			s = slug.Make(c.Name)
+		}
+		existing, err := imp.categoryRepo.GetBySlug(ctx, s)
+		if err == nil {
+			imp.remap(KindCategory, c.ID, existing.ID)
+			counts.Matched++
+			continue
+		}
+		if !errors.Is(err, category.ErrNotFound) {
+			imp.fail(KindCategory, c.ID, counts, err)
+			continue
+		}
+		if trashed, err := imp.categoryRepo.SlugInUse(ctx, s, ""); err != nil || trashed {
+			if err == nil {
+				err = fmt.Errorf("slug %q is held by a category in the trash", s)
+			}
+			imp.fail(KindCategory, c.ID, counts, err)
+			continue
+		}
+
+		created := *c
+		created.ID = ""
+		created.Slug = s
+		created.Children = nil
+		created.ParentID = ""
+		if c.ParentID != "" {
+			if parentID, ok := imp.lookup(KindCategory, c.ParentID); ok {
+				created.ParentID = parentID
+			} else {
+				imp.note(KindCategory, c.ID, "parent %s was not imported, imported as a top-level category", c.ParentID)
+			}
+		}
+		if imp.opts.DryRun {
+			imp.remap(KindCategory, c.ID, c.ID)
+			counts.Created++
+			continue
+		}
+		if err := imp.categoryRepo.Create(ctx, &created); err != nil {
+			imp.fail(KindCategory, c.ID, counts, err)
+			continue
+		}
+		imp.remap(KindCategory, c.ID, created.ID)
+		counts.Created++
+	}
+}
+
+// importTags imports tags. Tags whose slug is known are reused.
+func (imp *importer) importTags(ctx context.Context, tags []*tag.Tag) {
+	counts := &imp.report.Tags
+	for _, t := range tags {
+		s := t.Slug
+		if s == "" {
+			s = slug.Make(t.Name)
+		}
+		existing, err := imp.tagRepo.GetBySlug(ctx, s)
+		if err == nil {
+			imp.remap(KindTag, t.ID, existing.ID)
+			counts.Matched++
+			continue
+		}
+		if !errors.Is(err, tag.ErrNotFound) {
+			imp.fail(KindTag, t.ID, counts, err)
+			continue
+		}
+		if trashed, err := imp.tagRepo.SlugInUse(ctx, s, ""); err != nil || trashed {
+			if err == nil {
+				err = fmt.Errorf("slug %q is held by a tag in the trash", s)
+			}
+			imp.fail(KindTag, t.ID, counts, err)
+			continue
+		}
+
+		created := *t
+		created.ID = ""
+		created.Slug = s
+		if imp.opts.DryRun {
+			imp.remap(KindTag, t.ID, t.ID)
+			counts.Created++
+			continue
+		}
+		if err := imp.tagRepo.Create(ctx, &created); err != nil {
+			imp.fail(KindTag, t.ID, counts, err)
+			continue
+		}
+		imp.remap(KindTag, t.ID, created.ID)
+		counts.Created++
+	}
+}
+
+// importMedia imports media metadata; the files are expected under the same storage keys. Items
+// still present on the site, as when restoring a backup, are reused.
+func (imp *importer) importMedia(ctx context.Context, items []*transfer.Media) {
+	counts := &imp.report.Media
+	for _, m := range items {
+		if m.StorageKey == "" {
+			imp.fail(KindMedia, m.ID, counts, errors.New("media has no storage key"))
+			continue
+		}
+		existing, err := imp.mediaRepo.GetByID(ctx, m.ID)
+		if err == nil && existing.StorageKey == m.StorageKey {
+			imp.remap(KindMedia, m.ID, existing.ID)
+			counts.Matched++
+			continue
+		}
+		if err != nil && !errors.Is(err, media.ErrNotFound) {
+			imp.fail(KindMedia, m.ID, counts, err)
+			continue
+		}
+
+		created := m.Media
+		created.ID = ""
+		created.StorageKey = m.StorageKey
+		created.URL = ""
+		created.Sizes = nil
+		created.UploaderID, _ = imp.lookup(KindUser, m.UploaderID)
+		if imp.opts.DryRun {
+			imp.remap(KindMedia, m.ID, m.ID)
+			counts.Created++
+			continue
+		}
+		if err := imp.mediaRepo.Create(ctx, &created); err != nil {
+			imp.fail(KindMedia, m.ID, counts, err)
+			continue
+		}
+		imp.remap(KindMedia, m.ID, created.ID)
+		counts.Created++
+	}
+}
+
+// importContents imports source entries before their translations, resolving slug conflicts with
+// the configured strategy.
+func (imp *importer) importContents(ctx context.Context, entries []*transfer.Content) {
+	ordered := slices.Clone(entries)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].TranslationOf == "" && ordered[j].TranslationOf != ""
+	})
+
+	counts := &imp.report.Contents
+	for _, e := range ordered {
+		c := e.Content
+		c.ID = ""
+		c.Version = 0
+		c.PasswordHash = e.PasswordHash
+		c.BodyHTML = ""
+		c.Permalink = ""
+		c.AuthorID, _ = imp.lookup(KindUser, e.AuthorID)
+		if e.TranslationOf != "" {
+			sourceID, ok := imp.lookup(KindContent, e.TranslationOf)
+			if !ok {
+				imp.fail(KindContent, e.ID, counts, fmt.Errorf("source entry %s was not imported", e.TranslationOf))
+				continue
+			}
+			c.TranslationOf = sourceID
+		}
+		if !imp.opts.DryRun {
+			imp.rewriteMediaReferences(&c)
+		}
+		categoryIDs := imp.lookupAll(KindCategory, e.CategoryIDs)
+		tagIDs := imp.lookupAll(KindTag, e.TagIDs)
+
+		existing, err := imp.findBySlug(ctx, &c)
+		if err != nil {
+			imp.fail(KindContent, e.ID, counts, err)
+			continue
+		}
+		if existing != nil {
+			switch imp.opts.SlugConflict {
+			case SlugSkip:
+				imp.remap(KindContent, e.ID, existing.ID)
+				imp.note(KindContent, e.ID, "slug %q is taken by entry %s, skipped", c.Slug, existing.ID)
+				counts.Skipped++
+				continue
+			case SlugOverwrite:
+				imp.remap(KindContent, e.ID, existing.ID)
+				if !imp.opts.DryRun {
+					c.ID = existing.ID
+					if err := imp.contents.Update(ctx, &c, categoryIDs, tagIDs); err != nil {
+						imp.fail(KindContent, e.ID, counts, err)
+						continue
+					}
+				}
+				counts.Updated++
+				continue
+			}
+		}
+
+		if imp.opts.DryRun {
+			if existing != nil {
+				imp.note(KindContent, e.ID, "slug %q is taken by entry %s and will be renamed", c.Slug, existing.ID)
+			}
+			imp.remap(KindContent, e.ID, e.ID)
+			counts.Created++
+			continue
+		}
+		if err := imp.contents.Create(ctx, &c, categoryIDs, tagIDs); err != nil {
+			imp.fail(KindContent, e.ID, counts, err)
+			continue
+		}
+		if existing != nil {
+			imp.note(KindContent, e.ID, "slug %q is taken by entry %s, imported as %q", e.Slug, existing.ID, c.Slug)
+		}
+		imp.remap(KindContent, e.ID, c.ID)
+		counts.Created++
+	}
+}
+
+// findBySlug returns the entry holding the slug of c in its type and locale, if any.
+func (imp *importer) findBySlug(ctx context.Context, c *content.Content) (*content.Content, error) {
+	if c.Slug == "" {
+		return nil, nil
+	}
+	contentType, loc := c.Type, c.Locale
+	if contentType == "" {
+		contentType = "post"
+	}
+	if loc == "" {
+		loc = imp.contents.Locales().Default()
+	}
+	existing, err := imp.contentRepo.GetBySlug(ctx, contentType, loc, c.Slug)
+	if errors.Is(err, content.ErrNotFound) {
+		return nil, nil
+	}
+	return existing, err
+}
+
+// mediaIDPattern matches the UUIDs media are identified by in bodies and blocks.
+var mediaIDPattern = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+// rewriteMediaReferences points the media URLs and image blocks of an entry at the new IDs of the
+// media they show.
+func (imp *importer) rewriteMediaReferences(c *content.Content) {
+	replace := func(id []byte) []byte {
+		if newID, ok := imp.lookup(KindMedia, string(id)); ok {
+			return []byte(newID)
+		}
+		return id
+	}
+	c.Body = string(mediaIDPattern.ReplaceAllFunc([]byte(c.Body), replace))
+	blocks := slices.Clone(c.Blocks)
+	for i := range blocks {
+		blocks[i].Data = mediaIDPattern.ReplaceAllFunc(blocks[i].Data, replace)
+	}
+	c.Blocks = blocks
+}
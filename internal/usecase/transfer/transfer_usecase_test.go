@@ -0,0 +1,441 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mashurimansur/goCMS/internal/domain/block"
+	"github.com/mashurimansur/goCMS/internal/domain/category"
+	"github.com/mashurimansur/goCMS/internal/domain/content"
+	"github.com/mashurimansur/goCMS/internal/domain/media"
+	"github.com/mashurimansur/goCMS/internal/domain/tag"
+	"github.com/mashurimansur/goCMS/internal/domain/transfer"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+	contentusecase "github.com/mashurimansur/goCMS/internal/usecase/content"
+	"github.com/mashurimansur/goCMS/internal/utils/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockContentUseCase struct {
+	mock.Mock
+	contentusecase.UseCase
+}
+
+func (m *MockContentUseCase) Create(ctx context.Context, c *content.Content, categoryIDs, tagIDs []string) error {
+	args := m.Called(ctx, c, categoryIDs, tagIDs)
+	if c.ID == "" {
+		c.ID = "new-" + c.Slug
+	}
+	return args.Error(0)
+}
+
+func (m *MockContentUseCase) Update(ctx context.Context, c *content.Content, categoryIDs, tagIDs []string) error {
+	args := m.Called(ctx, c, categoryIDs, tagIDs)
+	return args.Error(0)
+}
+
+func (m *MockContentUseCase) Locales() locale.Settings {
+	settings, _ := locale.Parse("en", "")
+	return settings
+}
+
+type MockContentRepository struct {
+	mock.Mock
+	content.Repository
+}
+
+func (m *MockContentRepository) GetBySlug(ctx context.Context, contentType, loc, slug string) (*content.Content, error) {
+	args := m.Called(ctx, contentType, loc, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*content.Content), args.Error(1)
+}
+
+func (m *MockContentRepository) List(ctx context.Context, filter content.ListFilter) ([]*content.Content, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*content.Content), args.Error(1)
+}
+
+type MockCategoryRepository struct {
+	mock.Mock
+	category.Repository
+}
+
+func (m *MockCategoryRepository) Create(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	c.ID = "new-" + c.Slug
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) GetBySlug(ctx context.Context, slug string) (*category.Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) List(ctx context.Context) ([]*category.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListByContent(ctx context.Context, contentID string) ([]*category.Category, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).([]*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockTagRepository struct {
+	mock.Mock
+	tag.Repository
+}
+
+func (m *MockTagRepository) Create(ctx context.Context, t *tag.Tag) error {
+	args := m.Called(ctx, t)
+	t.ID = "new-" + t.Slug
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetBySlug(ctx context.Context, slug string) (*tag.Tag, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) List(ctx context.Context, limit, offset int) ([]*tag.Tag, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) ListByContent(ctx context.Context, contentID string) ([]*tag.Tag, error) {
+	args := m.Called(ctx, contentID)
+	return args.Get(0).([]*tag.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) SlugInUse(ctx context.Context, slug, exceptID string) (bool, error) {
+	args := m.Called(ctx, slug, exceptID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTagRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockTagRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockMediaRepository struct {
+	mock.Mock
+	media.Repository
+}
+
+func (m *MockMediaRepository) Create(ctx context.Context, item *media.Media) error {
+	args := m.Called(ctx, item)
+	item.ID = "22222222-2222-2222-2222-222222222222"
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) GetByID(ctx context.Context, id string) (*media.Media, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*media.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) List(ctx context.Context, filter media.ListFilter) ([]*media.Media, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*media.Media), args.Error(1)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+	user.Repository
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	u.ID = "new-" + u.Username
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+type mocks struct {
+	contents   *MockContentUseCase
+	contentDB  *MockContentRepository
+	categories *MockCategoryRepository
+	tags       *MockTagRepository
+	media      *MockMediaRepository
+	users      *MockUserRepository
+}
+
+func newTestUseCase() (UseCase, mocks) {
+	m := mocks{
+		contents:   new(MockContentUseCase),
+		contentDB:  new(MockContentRepository),
+		categories: new(MockCategoryRepository),
+		tags:       new(MockTagRepository),
+		media:      new(MockMediaRepository),
+		users:      new(MockUserRepository),
+	}
+	return NewTransferUseCase(m.contents, m.contentDB, m.categories, m.tags, m.media, m.users), m
+}
+
+const (
+	oldMediaID = "11111111-1111-1111-1111-111111111111"
+	newMediaID = "22222222-2222-2222-2222-222222222222"
+)
+
+func testBundle() *transfer.Bundle {
+	return &transfer.Bundle{
+		Version: transfer.FormatVersion,
+		Users: []*user.User{
+			{ID: "u-1", Username: "budi", Email: "budi@example.com", PasswordHash: "leaked"},
+			{ID: "u-2", Username: "sari", Email: "sari@example.com"},
+		},
+		Categories: []*category.Category{
+			{ID: "c-2", ParentID: "c-1", Name: "Local", Slug: "local"},
+			{ID: "c-1", Name: "News", Slug: "news"},
+		},
+		Tags:  []*tag.Tag{{ID: "t-1", Name: "Go", Slug: "go"}},
+		Media: []*transfer.Media{{Media: media.Media{ID: oldMediaID, Filename: "cat.png", UploaderID: "u-1"}, StorageKey: "2024/01/cat.png"}},
+		Contents: []*transfer.Content{
+			{Content: content.Content{ID: "p-2", Type: "post", Locale: "id", TranslationOf: "p-1", Title: "Halo", Slug: "halo", AuthorID: "u-9"}},
+			{
+				Content: content.Content{
+					ID: "p-1", Type: "post", Locale: "en", Title: "Hello", Slug: "hello", AuthorID: "u-1",
+					Body: "![cat](/api/v1/media/" + oldMediaID + ")", Version: 7,
+					Blocks: []block.Block{{Type: block.TypeImage, Data: json.RawMessage(`{"media_id":"` + oldMediaID + `"}`)}},
+				},
+				CategoryIDs: []string{"c-2", "c-missing"},
+				TagIDs:      []string{"t-1"},
+			},
+		},
+	}
+}
+
+// expectLookups sets up the site the test bundle is imported into: sari and the go tag exist.
+func expectLookups(m mocks) {
+	m.users.On("GetByEmail", mock.Anything, "budi@example.com").Return(nil, nil)
+	m.users.On("GetByEmail", mock.Anything, "sari@example.com").Return(&user.User{ID: "sari-id"}, nil)
+	m.users.On("GetByUsername", mock.Anything, "budi").Return(nil, nil)
+	m.categories.On("GetBySlug", mock.Anything, mock.Anything).Return(nil, category.ErrNotFound)
+	m.categories.On("SlugInUse", mock.Anything, mock.Anything, "").Return(false, nil)
+	m.tags.On("GetBySlug", mock.Anything, "go").Return(&tag.Tag{ID: "go-id"}, nil)
+	m.media.On("GetByID", mock.Anything, oldMediaID).Return(nil, media.ErrNotFound)
+}
+
+func TestTransferUseCase_Import_TrashedCategorySlug(t *testing.T) {
+	uc, m := newTestUseCase()
+	m.categories.On("GetBySlug", mock.Anything, mock.Anything).Return(nil, category.ErrNotFound)
+	m.categories.On("SlugInUse", mock.Anything, mock.Anything, "").Return(true, nil)
+
+	bundle := testBundle()
+	report, err := uc.Import(context.Background(), &transfer.Bundle{Version: bundle.Version, Categories: bundle.Categories}, ImportOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, Counts{Failed: 2}, report.Categories)
+	assert.Contains(t, report.Notes[0].Message, "in the trash")
+	m.categories.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestTransferUseCase_Import(t *testing.T) {
+	uc, m := newTestUseCase()
+	expectLookups(m)
+	m.contentDB.On("GetBySlug", mock.Anything, "post", mock.Anything, mock.Anything).Return(nil, content.ErrNotFound)
+
+	m.users.On("Create", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+		return u.ID == "" && u.PasswordHash == "" && u.Role == "user" && u.Status == "active"
+	})).Return(nil)
+	m.categories.On("Create", mock.Anything, mock.MatchedBy(func(c *category.Category) bool { return c.Slug == "news" })).Return(nil)
+	m.categories.On("Create", mock.Anything, mock.MatchedBy(func(c *category.Category) bool {
+		return c.Slug == "local" && c.ParentID == "new-news"
+	})).Return(nil)
+	m.media.On("Create", mock.Anything, mock.MatchedBy(func(item *media.Media) bool {
+		return item.StorageKey == "2024/01/cat.png" && item.UploaderID == "new-budi"
+	})).Return(nil)
+
+	var source *content.Content
+	m.contents.On("Create", mock.Anything, mock.MatchedBy(func(c *content.Content) bool { return c.Slug == "hello" }),
+		[]string{"new-local"}, []string{"go-id"}).Return(nil).Run(func(args mock.Arguments) {
+		source = args.Get(1).(*content.Content)
+	})
+	m.contents.On("Create", mock.Anything, mock.MatchedBy(func(c *content.Content) bool {
+		return c.Slug == "halo" && c.TranslationOf == "new-hello" && c.AuthorID == ""
+	}), []string{}, []string{}).Return(nil)
+
+	report, err := uc.Import(context.Background(), testBundle(), ImportOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, Counts{Created: 1, Matched: 1}, report.Users)
+	assert.Equal(t, Counts{Created: 2}, report.Categories)
+	assert.Equal(t, Counts{Matched: 1}, report.Tags)
+	assert.Equal(t, Counts{Created: 1}, report.Media)
+	assert.Equal(t, Counts{Created: 2}, report.Contents)
+
+	require.NotNil(t, source)
+	assert.Equal(t, "new-budi", source.AuthorID)
+	assert.Zero(t, source.Version)
+	assert.Equal(t, "![cat](/api/v1/media/"+newMediaID+")", source.Body)
+	assert.JSONEq(t, `{"media_id":"`+newMediaID+`"}`, string(source.Blocks[0].Data))
+	// The bundle itself is left untouched.
+	assert.Contains(t, testBundle().Contents[1].Body, oldMediaID)
+}
+
+func TestTransferUseCase_Import_DryRun(t *testing.T) {
+	uc, m := newTestUseCase()
+	expectLookups(m)
+	m.contentDB.On("GetBySlug", mock.Anything, "post", "en", "hello").Return(&content.Content{ID: "existing"}, nil)
+	m.contentDB.On("GetBySlug", mock.Anything, "post", "id", "halo").Return(nil, content.ErrNotFound)
+
+	report, err := uc.Import(context.Background(), testBundle(), ImportOptions{DryRun: true})
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, Counts{Created: 1, Matched: 1}, report.Users)
+	assert.Equal(t, Counts{Created: 2}, report.Contents)
+	assert.Contains(t, report.Notes, Note{Kind: KindContent, ID: "p-1", Message: `slug "hello" is taken by entry existing and will be renamed`})
+	m.users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	m.categories.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	m.media.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	m.contents.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransferUseCase_Import_SlugConflicts(t *testing.T) {
+	b := &transfer.Bundle{
+		Version: transfer.FormatVersion,
+		Contents: []*transfer.Content{
+			{Content: content.Content{ID: "p-1", Type: "page", Title: "About", Slug: "about"}},
+		},
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		uc, m := newTestUseCase()
+		m.contentDB.On("GetBySlug", mock.Anything, "page", "en", "about").Return(&content.Content{ID: "existing"}, nil)
+
+		report, err := uc.Import(context.Background(), b, ImportOptions{SlugConflict: SlugSkip})
+		require.NoError(t, err)
+		assert.Equal(t, Counts{Skipped: 1}, report.Contents)
+		m.contents.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		uc, m := newTestUseCase()
+		m.contentDB.On("GetBySlug", mock.Anything, "page", "en", "about").Return(&content.Content{ID: "existing"}, nil)
+		m.contents.On("Update", mock.Anything, mock.MatchedBy(func(c *content.Content) bool { return c.ID == "existing" }), []string{}, []string{}).Return(nil)
+
+		report, err := uc.Import(context.Background(), b, ImportOptions{SlugConflict: SlugOverwrite})
+		require.NoError(t, err)
+		assert.Equal(t, Counts{Updated: 1}, report.Contents)
+		m.contents.AssertExpectations(t)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		uc, m := newTestUseCase()
+		m.contentDB.On("GetBySlug", mock.Anything, "page", "en", "about").Return(&content.Content{ID: "existing"}, nil)
+		m.contents.On("Create", mock.Anything, mock.Anything, []string{}, []string{}).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*content.Content).Slug = "about-2"
+		})
+
+		report, err := uc.Import(context.Background(), b, ImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, Counts{Created: 1}, report.Contents)
+		assert.Equal(t, []Note{{Kind: KindContent, ID: "p-1", Message: `slug "about" is taken by entry existing, imported as "about-2"`}}, report.Notes)
+	})
+}
+
+func TestTransferUseCase_Import_Invalid(t *testing.T) {
+	uc, _ := newTestUseCase()
+
+	_, err := uc.Import(context.Background(), &transfer.Bundle{Version: transfer.FormatVersion + 1}, ImportOptions{})
+	assert.ErrorIs(t, err, transfer.ErrUnsupportedVersion)
+
+	_, err = uc.Import(context.Background(), &transfer.Bundle{Version: transfer.FormatVersion}, ImportOptions{SlugConflict: "merge"})
+	assert.ErrorIs(t, err, ErrInvalidStrategy)
+}
+
+func TestTransferUseCase_Export(t *testing.T) {
+	uc, m := newTestUseCase()
+
+	m.users.On("List", mock.Anything, pageSize, 0).Return([]*user.User{{ID: "u-1", PasswordHash: "secret"}}, nil)
+	m.categories.On("List", mock.Anything).Return([]*category.Category{{ID: "c-1"}}, nil)
+	m.tags.On("List", mock.Anything, pageSize, 0).Return([]*tag.Tag{{ID: "t-1"}}, nil)
+	m.media.On("List", mock.Anything, media.ListFilter{Limit: pageSize}).Return([]*media.Media{{ID: "m-1", StorageKey: "m-1.png"}}, nil)
+	m.contentDB.On("List", mock.Anything, content.ListFilter{Limit: pageSize}).Return([]*content.Content{{ID: "p-1"}}, nil)
+	m.categories.On("ListByContent", mock.Anything, "p-1").Return([]*category.Category{{ID: "c-1"}}, nil)
+	m.tags.On("ListByContent", mock.Anything, "p-1").Return([]*tag.Tag{}, nil)
+
+	b, err := uc.Export(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, transfer.FormatVersion, b.Version)
+	assert.Equal(t, "m-1.png", b.Media[0].StorageKey)
+	assert.Equal(t, []string{"c-1"}, b.Contents[0].CategoryIDs)
+	assert.Equal(t, []string{}, b.Contents[0].TagIDs)
+
+	encoded, err := json.Marshal(b)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "secret")
+	assert.Contains(t, string(encoded), `"storage_key":"m-1.png"`)
+}
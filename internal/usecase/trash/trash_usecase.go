@@ -0,0 +1,192 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+)
+
+var (
+	// ErrInvalidType is returned for types of items that have no trash.
+	ErrInvalidType = errors.New("invalid trash type")
+	// ErrAdminRequired is returned when someone other than an admin restores or purges trashed users.
+	ErrAdminRequired = errors.New("the trash of users requires the admin role")
+)
+
+// adminTypes are the types whose trash only admins may see, restore or purge.
+var adminTypes = map[string]bool{trash.TypeUser: true}
+
+// DefaultRetention is how long trashed items are kept unless Options say otherwise.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// purgeBatch is how many expired items of a type are purged per round.
+const purgeBatch = 100
+
+// Options configures the trash.
+type Options struct {
+	// Retention is how long an item stays in the trash before it is purged for good.
+	Retention time.Duration
+}
+
+// RoleFinder looks up the role of a user, which decides whether they may manage the trash of users.
+type RoleFinder interface {
+	Role(ctx context.Context, userID string) (string, error)
+}
+
+type UseCase interface {
+	// List returns trashed items of resourceType that userID may manage, most recently trashed
+	// first. An empty resourceType lists the items of every type.
+	List(ctx context.Context, resourceType string, limit, offset int, userID string) ([]*trash.Item, error)
+	// Restore brings a trashed item back on behalf of userID.
+	Restore(ctx context.Context, resourceType, id, userID string) error
+	// Purge deletes a trashed item for good on behalf of userID.
+	Purge(ctx context.Context, resourceType, id, userID string) error
+	// PurgeExpired deletes the items that outlived the retention period and returns how many.
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+type trashUseCase struct {
+	bins  map[string]trash.Bin
+	roles RoleFinder
+	opts  Options
+}
+
+// NewTrashUseCase builds the trash over the bins of each item type, keyed by type. Roles keeps the
+// trash of users to admins.
+func NewTrashUseCase(bins map[string]trash.Bin, roles RoleFinder, opts Options) UseCase {
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultRetention
+	}
+	return &trashUseCase{
+		bins:  bins,
+		roles: roles,
+		opts:  opts,
+	}
+}
+
+func (uc *trashUseCase) List(ctx context.Context, resourceType string, limit, offset int, userID string) ([]*trash.Item, error) {
+	if resourceType != "" {
+		bin, err := uc.authorizedBin(ctx, resourceType, userID)
+		if err != nil {
+			return nil, err
+		}
+		items, err := bin.ListTrashed(ctx, trash.ListFilter{Limit: limit, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		return uc.withPurgeAt(items), nil
+	}
+
+	admin, err := uc.isAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Every bin is sorted alike, so the first offset+limit items of each hold the requested page.
+	var items []*trash.Item
+	for _, t := range trash.Types {
+		bin, ok := uc.bins[t]
+		if !ok || (adminTypes[t] && !admin) {
+			continue
+		}
+		found, err := bin.ListTrashed(ctx, trash.ListFilter{Limit: offset + limit})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, found...)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+
+	if offset >= len(items) {
+		return []*trash.Item{}, nil
+	}
+	items = items[offset:]
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return uc.withPurgeAt(items), nil
+}
+
+func (uc *trashUseCase) Restore(ctx context.Context, resourceType, id, userID string) error {
+	bin, err := uc.authorizedBin(ctx, resourceType, userID)
+	if err != nil {
+		return err
+	}
+	return bin.Restore(ctx, id)
+}
+
+func (uc *trashUseCase) Purge(ctx context.Context, resourceType, id, userID string) error {
+	bin, err := uc.authorizedBin(ctx, resourceType, userID)
+	if err != nil {
+		return err
+	}
+	return bin.Purge(ctx, id)
+}
+
+func (uc *trashUseCase) PurgeExpired(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-uc.opts.Retention)
+	purged := 0
+	for _, t := range trash.Types {
+		bin, ok := uc.bins[t]
+		if !ok {
+			continue
+		}
+		for {
+			items, err := bin.ListTrashed(ctx, trash.ListFilter{DeletedBefore: cutoff, Limit: purgeBatch})
+			if err != nil {
+				return purged, err
+			}
+			for _, item := range items {
+				// Purging a content entry takes its translations along, which may be listed too.
+				if err := bin.Purge(ctx, item.ID); err != nil && !errors.Is(err, trash.ErrNotFound) {
+					return purged, err
+				}
+				purged++
+			}
+			if len(items) < purgeBatch {
+				break
+			}
+		}
+	}
+	return purged, nil
+}
+
+// authorizedBin returns the bin of resourceType if userID may manage it.
+func (uc *trashUseCase) authorizedBin(ctx context.Context, resourceType, userID string) (trash.Bin, error) {
+	bin, ok := uc.bins[resourceType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidType, resourceType)
+	}
+	if adminTypes[resourceType] {
+		admin, err := uc.isAdmin(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !admin {
+			return nil, ErrAdminRequired
+		}
+	}
+	return bin, nil
+}
+
+func (uc *trashUseCase) isAdmin(ctx context.Context, userID string) (bool, error) {
+	role, err := uc.roles.Role(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return role == user.RoleAdmin, nil
+}
+
+// withPurgeAt sets when each item is going to be purged.
+func (uc *trashUseCase) withPurgeAt(items []*trash.Item) []*trash.Item {
+	for _, item := range items {
+		item.PurgeAt = item.DeletedAt.Add(uc.opts.Retention)
+	}
+	return items
+}
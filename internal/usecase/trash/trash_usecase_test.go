@@ -0,0 +1,144 @@
+package trash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
+	"github.com/mashurimansur/goCMS/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockBin struct {
+	mock.Mock
+}
+
+func (m *MockBin) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockBin) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockBin) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockRoleFinder struct {
+	mock.Mock
+}
+
+func (m *MockRoleFinder) Role(ctx context.Context, userID string) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+// newRoles lets admin-1 be an admin and user-1 a regular user.
+func newRoles() *MockRoleFinder {
+	roles := new(MockRoleFinder)
+	roles.On("Role", mock.Anything, "admin-1").Return(user.RoleAdmin, nil)
+	roles.On("Role", mock.Anything, "user-1").Return(user.RoleUser, nil)
+	return roles
+}
+
+func TestTrashUseCase_List(t *testing.T) {
+	contents := new(MockBin)
+	uc := NewTrashUseCase(map[string]trash.Bin{trash.TypeContent: contents}, newRoles(), Options{Retention: time.Hour})
+
+	deletedAt := time.Now()
+	contents.On("ListTrashed", mock.Anything, trash.ListFilter{Limit: 10, Offset: 5}).
+		Return([]*trash.Item{{Type: trash.TypeContent, ID: "post-1", DeletedAt: deletedAt}}, nil)
+
+	items, err := uc.List(context.Background(), trash.TypeContent, 10, 5, "user-1")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, deletedAt.Add(time.Hour), items[0].PurgeAt)
+}
+
+func TestTrashUseCase_List_AllTypes(t *testing.T) {
+	contents, media := new(MockBin), new(MockBin)
+	uc := NewTrashUseCase(map[string]trash.Bin{trash.TypeContent: contents, trash.TypeMedia: media}, newRoles(), Options{})
+
+	now := time.Now()
+	contents.On("ListTrashed", mock.Anything, trash.ListFilter{Limit: 3}).Return([]*trash.Item{
+		{Type: trash.TypeContent, ID: "post-1", DeletedAt: now.Add(-time.Minute)},
+		{Type: trash.TypeContent, ID: "post-2", DeletedAt: now.Add(-3 * time.Minute)},
+	}, nil)
+	media.On("ListTrashed", mock.Anything, trash.ListFilter{Limit: 3}).Return([]*trash.Item{
+		{Type: trash.TypeMedia, ID: "media-1", DeletedAt: now},
+		{Type: trash.TypeMedia, ID: "media-2", DeletedAt: now.Add(-2 * time.Minute)},
+	}, nil)
+
+	items, err := uc.List(context.Background(), "", 2, 1, "user-1")
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "post-1", items[0].ID)
+	assert.Equal(t, "media-2", items[1].ID)
+	assert.Equal(t, items[0].DeletedAt.Add(DefaultRetention), items[0].PurgeAt)
+}
+
+func TestTrashUseCase_InvalidType(t *testing.T) {
+	uc := NewTrashUseCase(map[string]trash.Bin{}, newRoles(), Options{})
+
+	_, err := uc.List(context.Background(), "widget", 10, 0, "admin-1")
+	assert.ErrorIs(t, err, ErrInvalidType)
+	assert.ErrorIs(t, uc.Restore(context.Background(), "widget", "1", "admin-1"), ErrInvalidType)
+	assert.ErrorIs(t, uc.Purge(context.Background(), "widget", "1", "admin-1"), ErrInvalidType)
+}
+
+func TestTrashUseCase_RestoreAndPurge(t *testing.T) {
+	users := new(MockBin)
+	uc := NewTrashUseCase(map[string]trash.Bin{trash.TypeUser: users}, newRoles(), Options{})
+
+	users.On("Restore", mock.Anything, "user-1").Return(nil)
+	users.On("Purge", mock.Anything, "user-2").Return(trash.ErrNotFound)
+
+	require.NoError(t, uc.Restore(context.Background(), trash.TypeUser, "user-1", "admin-1"))
+	assert.ErrorIs(t, uc.Purge(context.Background(), trash.TypeUser, "user-2", "admin-1"), trash.ErrNotFound)
+	users.AssertExpectations(t)
+}
+
+func TestTrashUseCase_UsersRequireAdmin(t *testing.T) {
+	contents, users := new(MockBin), new(MockBin)
+	uc := NewTrashUseCase(map[string]trash.Bin{trash.TypeContent: contents, trash.TypeUser: users}, newRoles(), Options{})
+
+	contents.On("ListTrashed", mock.Anything, trash.ListFilter{Limit: 10}).
+		Return([]*trash.Item{{Type: trash.TypeContent, ID: "post-1"}}, nil)
+
+	_, err := uc.List(context.Background(), trash.TypeUser, 10, 0, "user-1")
+	assert.ErrorIs(t, err, ErrAdminRequired)
+	assert.ErrorIs(t, uc.Restore(context.Background(), trash.TypeUser, "user-2", "user-1"), ErrAdminRequired)
+	assert.ErrorIs(t, uc.Purge(context.Background(), trash.TypeUser, "user-2", "user-1"), ErrAdminRequired)
+
+	// Listing every type leaves the trashed users out.
+	items, err := uc.List(context.Background(), "", 10, 0, "user-1")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "post-1", items[0].ID)
+	users.AssertNotCalled(t, "ListTrashed", mock.Anything, mock.Anything)
+	users.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+	users.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+}
+
+func TestTrashUseCase_PurgeExpired(t *testing.T) {
+	media := new(MockBin)
+	uc := NewTrashUseCase(map[string]trash.Bin{trash.TypeMedia: media}, newRoles(), Options{Retention: time.Hour})
+
+	media.On("ListTrashed", mock.Anything, mock.MatchedBy(func(f trash.ListFilter) bool {
+		return f.Limit == purgeBatch && time.Since(f.DeletedBefore) >= time.Hour
+	})).Return([]*trash.Item{{ID: "media-1"}, {ID: "media-2"}}, nil)
+	media.On("Purge", mock.Anything, "media-1").Return(nil)
+	media.On("Purge", mock.Anything, "media-2").Return(trash.ErrNotFound)
+
+	purged, err := uc.PurgeExpired(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	media.AssertExpectations(t)
+}
@@ -3,33 +3,61 @@ package user
 import (
 	"context"
 	"errors"
+	"io"
 	"time"
 
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
 	"github.com/mashurimansur/goCMS/internal/domain/user"
 	"github.com/mashurimansur/goCMS/internal/utils/token"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	// ErrUserNotFound is returned when an operation targets a user that does not exist.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrAdminRequired is returned when someone other than an admin manages another user or changes
+	// the role, status or verification of an account.
+	ErrAdminRequired = errors.New("only admins may manage other users")
+)
+
+// AvatarUploader stores an uploaded avatar image and returns the URL to display it.
+type AvatarUploader interface {
+	UploadAvatar(ctx context.Context, userID, filename string, body io.Reader) (string, error)
+}
+
 type UseCase interface {
 	Register(ctx context.Context, u *user.User, password string) error
 	Login(ctx context.Context, email, password string) (string, *user.User, error)
 	GetProfile(ctx context.Context, id string) (*user.User, error)
-	UpdateProfile(ctx context.Context, u *user.User) error
+	// Role returns the role of a user, which decides the restricted entries they may read.
+	Role(ctx context.Context, id string) (string, error)
+	// UpdateProfile saves u on behalf of actorID. Admins may update anyone; others only themselves,
+	// keeping their role, status and verification.
+	UpdateProfile(ctx context.Context, u *user.User, actorID string) error
 	ListUsers(ctx context.Context, limit, offset int) ([]*user.User, error)
-	DeleteUser(ctx context.Context, id string) error
+	// DeleteUser moves a user to the trash on behalf of actorID, who must be an admin.
+	DeleteUser(ctx context.Context, id, actorID string) error
+	// UpdateAvatar replaces the avatar of a user on behalf of actorID. Admins may replace anyone's
+	// avatar, others only their own.
+	UpdateAvatar(ctx context.Context, id, actorID, filename string, body io.Reader) (*user.User, error)
+	// The trash of users.
+	trash.Bin
 }
 
 type userUseCase struct {
 	userRepo      user.Repository
 	tokenMaker    token.Maker
 	tokenDuration time.Duration
+	avatars       AvatarUploader
 }
 
-func NewUserUseCase(userRepo user.Repository, tokenMaker token.Maker, tokenDuration time.Duration) UseCase {
+func NewUserUseCase(userRepo user.Repository, tokenMaker token.Maker, tokenDuration time.Duration, avatars AvatarUploader) UseCase {
 	return &userUseCase{
 		userRepo:      userRepo,
 		tokenMaker:    tokenMaker,
 		tokenDuration: tokenDuration,
+		avatars:       avatars,
 	}
 }
 
@@ -40,7 +68,7 @@ func (uc *userUseCase) Register(ctx context.Context, u *user.User, password stri
 	}
 	u.PasswordHash = string(hashedPassword)
 	if u.Role == "" {
-		u.Role = "user"
+		u.Role = user.RoleUser
 	}
 	if u.Status == "" {
 		u.Status = "active"
@@ -74,7 +102,42 @@ func (uc *userUseCase) GetProfile(ctx context.Context, id string) (*user.User, e
 	return uc.userRepo.GetByID(ctx, id)
 }
 
-func (uc *userUseCase) UpdateProfile(ctx context.Context, u *user.User) error {
+func (uc *userUseCase) Role(ctx context.Context, id string) (string, error) {
+	u, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if u == nil {
+		return "", ErrUserNotFound
+	}
+	return u.Role, nil
+}
+
+func (uc *userUseCase) UpdateProfile(ctx context.Context, u *user.User, actorID string) error {
+	admin, err := uc.authorize(ctx, u.ID, actorID)
+	if err != nil {
+		return err
+	}
+	existing, err := uc.userRepo.GetByID(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrUserNotFound
+	}
+	if !admin {
+		// Users may leave these out of their own profile, but not change them.
+		if (u.Role != "" && u.Role != existing.Role) || (u.Status != "" && u.Status != existing.Status) {
+			return ErrAdminRequired
+		}
+		u.Role = existing.Role
+		u.Status = existing.Status
+		u.EmailVerified = existing.EmailVerified
+		u.PhoneVerified = existing.PhoneVerified
+	}
+	if u.Version, err = lock.Expect(u.Version, existing.Version); err != nil {
+		return err
+	}
 	return uc.userRepo.Update(ctx, u)
 }
 
@@ -82,6 +145,78 @@ func (uc *userUseCase) ListUsers(ctx context.Context, limit, offset int) ([]*use
 	return uc.userRepo.List(ctx, limit, offset)
 }
 
-func (uc *userUseCase) DeleteUser(ctx context.Context, id string) error {
+func (uc *userUseCase) DeleteUser(ctx context.Context, id, actorID string) error {
+	admin, err := uc.isAdmin(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return ErrAdminRequired
+	}
 	return uc.userRepo.Delete(ctx, id)
 }
+
+func (uc *userUseCase) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	return uc.userRepo.ListTrashed(ctx, filter)
+}
+
+func (uc *userUseCase) Restore(ctx context.Context, id string) error {
+	return uc.userRepo.Restore(ctx, id)
+}
+
+func (uc *userUseCase) Purge(ctx context.Context, id string) error {
+	return uc.userRepo.Purge(ctx, id)
+}
+
+func (uc *userUseCase) UpdateAvatar(ctx context.Context, id, actorID, filename string, body io.Reader) (*user.User, error) {
+	if uc.avatars == nil {
+		return nil, errors.New("avatar uploads are not configured")
+	}
+	if _, err := uc.authorize(ctx, id, actorID); err != nil {
+		return nil, err
+	}
+
+	u, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+
+	avatarURL, err := uc.avatars.UploadAvatar(ctx, id, filename, body)
+	if err != nil {
+		return nil, err
+	}
+
+	u.AvatarURL = avatarURL
+	if err := uc.userRepo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// authorize lets admins manage any user and others only themselves, reporting whether actorID is an
+// admin.
+func (uc *userUseCase) authorize(ctx context.Context, id, actorID string) (bool, error) {
+	admin, err := uc.isAdmin(ctx, actorID)
+	if err != nil {
+		return false, err
+	}
+	if !admin && (actorID == "" || id != actorID) {
+		return false, ErrAdminRequired
+	}
+	return admin, nil
+}
+
+// isAdmin reports whether actorID is an admin. Unknown users are not.
+func (uc *userUseCase) isAdmin(ctx context.Context, actorID string) (bool, error) {
+	role, err := uc.Role(ctx, actorID)
+	if errors.Is(err, ErrUserNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return role == user.RoleAdmin, nil
+}
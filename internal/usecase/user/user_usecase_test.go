@@ -2,9 +2,13 @@ package user
 
 import (
 	"context"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mashurimansur/goCMS/internal/domain/lock"
+	"github.com/mashurimansur/goCMS/internal/domain/trash"
 	"github.com/mashurimansur/goCMS/internal/domain/user"
 	"github.com/mashurimansur/goCMS/internal/utils/token"
 	"github.com/stretchr/testify/assert"
@@ -54,6 +58,21 @@ func (m *MockUserRepository) List(ctx context.Context, limit, offset int) ([]*us
 	return args.Get(0).([]*user.User), args.Error(1)
 }
 
+func (m *MockUserRepository) ListTrashed(ctx context.Context, filter trash.ListFilter) ([]*trash.Item, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*trash.Item), args.Error(1)
+}
+
+func (m *MockUserRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 type MockTokenMaker struct {
 	mock.Mock
 }
@@ -63,6 +82,11 @@ func (m *MockTokenMaker) CreateToken(username string, duration time.Duration) (s
 	return args.String(0), args.Get(1).(*token.Payload), args.Error(2)
 }
 
+func (m *MockTokenMaker) CreateScopedToken(username, scope string, duration time.Duration) (string, *token.Payload, error) {
+	args := m.Called(username, scope, duration)
+	return args.String(0), args.Get(1).(*token.Payload), args.Error(2)
+}
+
 func (m *MockTokenMaker) VerifyToken(tokenStr string) (*token.Payload, error) {
 	args := m.Called(tokenStr)
 	return args.Get(0).(*token.Payload), args.Error(1)
@@ -71,7 +95,7 @@ func (m *MockTokenMaker) VerifyToken(tokenStr string) (*token.Payload, error) {
 func TestUserUseCase_Register(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockMaker := new(MockTokenMaker)
-	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour)
+	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour, nil)
 
 	u := &user.User{
 		FullName: "Test User",
@@ -90,7 +114,7 @@ func TestUserUseCase_Register(t *testing.T) {
 func TestUserUseCase_Login(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockMaker := new(MockTokenMaker)
-	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour)
+	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour, nil)
 
 	email := "test@example.com"
 	password := "password123"
@@ -116,7 +140,7 @@ func TestUserUseCase_Login(t *testing.T) {
 func TestUserUseCase_GetProfile(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockMaker := new(MockTokenMaker)
-	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour)
+	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour, nil)
 
 	userID := "user-id"
 	u := &user.User{
@@ -133,10 +157,25 @@ func TestUserUseCase_GetProfile(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserUseCase_Role(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	uc := NewUserUseCase(mockRepo, new(MockTokenMaker), time.Hour, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "user-id").Return(&user.User{ID: "user-id", Role: "editor"}, nil)
+	mockRepo.On("GetByID", mock.Anything, "missing").Return((*user.User)(nil), nil)
+
+	role, err := uc.Role(context.Background(), "user-id")
+	assert.NoError(t, err)
+	assert.Equal(t, "editor", role)
+
+	_, err = uc.Role(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
 func TestUserUseCase_UpdateProfile(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockMaker := new(MockTokenMaker)
-	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour)
+	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour, nil)
 
 	u := &user.User{
 		ID:       "user-id",
@@ -144,17 +183,55 @@ func TestUserUseCase_UpdateProfile(t *testing.T) {
 		Email:    "updated@example.com",
 	}
 
+	mockRepo.On("GetByID", mock.Anything, "user-id").Return(&user.User{ID: "user-id", Role: "user", Status: "active", EmailVerified: true, Version: 3}, nil)
 	mockRepo.On("Update", mock.Anything, u).Return(nil)
 
-	err := uc.UpdateProfile(context.Background(), u)
+	// Users update their own profile, keeping what only admins may change.
+	err := uc.UpdateProfile(context.Background(), u, "user-id")
 	assert.NoError(t, err)
+	assert.Equal(t, 3, u.Version)
+	assert.Equal(t, "user", u.Role)
+	assert.Equal(t, "active", u.Status)
+	assert.True(t, u.EmailVerified)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserUseCase_UpdateProfile_AdminRequired(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	uc := NewUserUseCase(mockRepo, new(MockTokenMaker), time.Hour, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "user-id").Return(&user.User{ID: "user-id", Role: "user", Version: 3}, nil)
+	mockRepo.On("GetByID", mock.Anything, "other-id").Return(&user.User{ID: "other-id", Role: "user", Version: 1}, nil)
+	mockRepo.On("GetByID", mock.Anything, "admin-id").Return(&user.User{ID: "admin-id", Role: user.RoleAdmin, Version: 1}, nil)
+
+	err := uc.UpdateProfile(context.Background(), &user.User{ID: "user-id", Role: user.RoleAdmin}, "user-id")
+	assert.ErrorIs(t, err, ErrAdminRequired)
+	err = uc.UpdateProfile(context.Background(), &user.User{ID: "other-id", FullName: "Renamed"}, "user-id")
+	assert.ErrorIs(t, err, ErrAdminRequired)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	promoted := &user.User{ID: "user-id", Role: "editor"}
+	mockRepo.On("Update", mock.Anything, promoted).Return(nil)
+	assert.NoError(t, uc.UpdateProfile(context.Background(), promoted, "admin-id"))
+	assert.Equal(t, "editor", promoted.Role)
+}
+
+func TestUserUseCase_UpdateProfile_VersionConflict(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockMaker := new(MockTokenMaker)
+	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "user-id").Return(&user.User{ID: "user-id", Version: 3}, nil)
+
+	err := uc.UpdateProfile(context.Background(), &user.User{ID: "user-id", FullName: "Stale", Version: 2}, "user-id")
+	assert.ErrorIs(t, err, lock.ErrVersionConflict)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
 func TestUserUseCase_ListUsers(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockMaker := new(MockTokenMaker)
-	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour)
+	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour, nil)
 
 	users := []*user.User{
 		{ID: "user-1", Email: "user1@example.com"},
@@ -172,12 +249,58 @@ func TestUserUseCase_ListUsers(t *testing.T) {
 func TestUserUseCase_DeleteUser(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockMaker := new(MockTokenMaker)
-	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour)
+	uc := NewUserUseCase(mockRepo, mockMaker, time.Hour, nil)
 
 	userID := "user-id"
+	mockRepo.On("GetByID", mock.Anything, "admin-id").Return(&user.User{ID: "admin-id", Role: user.RoleAdmin}, nil)
+	mockRepo.On("GetByID", mock.Anything, userID).Return(&user.User{ID: userID, Role: user.RoleUser}, nil)
 	mockRepo.On("Delete", mock.Anything, userID).Return(nil)
 
-	err := uc.DeleteUser(context.Background(), userID)
+	err := uc.DeleteUser(context.Background(), userID, userID)
+	assert.ErrorIs(t, err, ErrAdminRequired)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	err = uc.DeleteUser(context.Background(), userID, "admin-id")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+type MockAvatarUploader struct {
+	mock.Mock
+}
+
+func (m *MockAvatarUploader) UploadAvatar(ctx context.Context, userID, filename string, body io.Reader) (string, error) {
+	args := m.Called(ctx, userID, filename, body)
+	return args.String(0), args.Error(1)
+}
+
+func TestUserUseCase_UpdateAvatar(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockAvatars := new(MockAvatarUploader)
+	uc := NewUserUseCase(mockRepo, new(MockTokenMaker), time.Hour, mockAvatars)
+
+	body := strings.NewReader("image")
+	mockRepo.On("GetByID", mock.Anything, "user-id").Return(&user.User{ID: "user-id"}, nil)
+	mockAvatars.On("UploadAvatar", mock.Anything, "user-id", "me.png", body).Return("/api/v1/media/m1/sizes/avatar", nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+		return u.AvatarURL == "/api/v1/media/m1/sizes/avatar"
+	})).Return(nil)
+
+	u, err := uc.UpdateAvatar(context.Background(), "user-id", "user-id", "me.png", body)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1/media/m1/sizes/avatar", u.AvatarURL)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_UpdateAvatar_UnknownUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockAvatars := new(MockAvatarUploader)
+	uc := NewUserUseCase(mockRepo, new(MockTokenMaker), time.Hour, mockAvatars)
+
+	mockRepo.On("GetByID", mock.Anything, "admin-id").Return(&user.User{ID: "admin-id", Role: user.RoleAdmin}, nil)
+	mockRepo.On("GetByID", mock.Anything, "missing").Return((*user.User)(nil), nil)
+
+	_, err := uc.UpdateAvatar(context.Background(), "missing", "admin-id", "me.png", strings.NewReader("image"))
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	mockAvatars.AssertNotCalled(t, "UploadAvatar", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -11,11 +11,152 @@ import (
 
 // AppConfig aggregates all runtime configuration required by the application.
 type AppConfig struct {
-	HTTPAddr          string
-	GinMode           string
+	HTTPAddr string
+	GinMode  string
+	// TrustedProxies lists the reverse proxies whose forwarding headers name the client, as
+	// comma-separated addresses or CIDR ranges, e.g. "10.0.0.0/8". Empty trusts none.
+	TrustedProxies    string
 	TokenSymmetricKey string
 	TokenDuration     string
-	Database          database.Config
+	// PermalinkPatterns maps content types to public URL patterns, e.g. "post=/{year}/{month}/{slug},page=/{slug}".
+	PermalinkPatterns string
+	// Locales lists the content locales, the first being the default, e.g. "id,en".
+	Locales string
+	// LocaleFallbacks maps locales to fallback chains, e.g. "ms=id>en". Every chain ends with the default locale.
+	LocaleFallbacks string
+	// SearchEngine selects the content search backend: "mysql" (FULLTEXT indexes) or "memory".
+	SearchEngine string
+	// LockTTL is how long an edit lock is held without heartbeats, e.g. "2m".
+	LockTTL string
+	// CategoryURLPattern is the public path of category archives linked from menus, e.g. "/category/{slug}".
+	// TagURLPattern is the public path of tag archives rendered by themes, e.g. "/tag/{slug}".
+	CategoryURLPattern string
+	TagURLPattern      string
+	Media              MediaConfig
+	Comments           CommentConfig
+	SEO                SEOConfig
+	Feed               FeedConfig
+	Preview            PreviewConfig
+	Trash              TrashConfig
+	Bulk               BulkConfig
+	Static             StaticConfig
+	Theme              ThemeConfig
+	Forms              FormConfig
+	Database           database.Config
+}
+
+// MediaConfig configures uploads and the local storage backend.
+type MediaConfig struct {
+	// StoragePath is the directory that holds uploaded files.
+	StoragePath string
+	// MaxUploadSize is the largest accepted upload in bytes.
+	MaxUploadSize string
+	// AllowedTypes is a comma-separated list of accepted MIME types.
+	AllowedTypes string
+	// BaseURL is the public URL prefix media files are served from.
+	BaseURL string
+	// ImageSizes lists named renditions, e.g. "thumbnail=150x150:crop,medium=800x800:fit".
+	ImageSizes string
+	// SigningKey signs ad-hoc image transform URLs, which are disabled when it is empty. It must not
+	// be the token key.
+	SigningKey string
+}
+
+// CommentConfig configures the spam heuristics applied to reader comments.
+type CommentConfig struct {
+	// MaxLinks is the number of links a comment may contain before it looks suspicious.
+	MaxLinks string
+	// BlockedWords is a comma-separated list of words that mark a comment as suspicious.
+	BlockedWords string
+	// MaxPerIP is the number of comments one address may post within RateWindow.
+	MaxPerIP string
+	// RateWindow is the duration over which comments per address are counted, e.g. "10m".
+	RateWindow string
+}
+
+// SEOConfig configures the site-wide search-engine settings.
+type SEOConfig struct {
+	// SiteURL is the absolute URL of the public site, used in canonical URLs and the sitemap.
+	SiteURL string
+	// SiteName is announced as og:site_name.
+	SiteName string
+	// AllowIndexing set to "false" asks crawlers to stay away from the whole site, e.g. on staging.
+	AllowIndexing string
+	// RobotsDisallow is a comma-separated list of paths robots.txt keeps crawlers out of.
+	RobotsDisallow string
+}
+
+// FeedConfig configures the RSS and Atom feeds.
+type FeedConfig struct {
+	// Title names the feeds. It falls back to the site name when empty.
+	Title       string
+	Description string
+	// FullContent set to "true" syndicates whole entries instead of their excerpts.
+	FullContent string
+	// Size is the number of entries per feed.
+	Size string
+	// ContentType is the type of the syndicated entries, e.g. "post".
+	ContentType string
+}
+
+// PreviewConfig configures the preview links shared with reviewers.
+type PreviewConfig struct {
+	// URL is the page previews are viewed on. It defaults to the preview endpoint of the API.
+	URL string
+	// Duration is how long preview links stay valid by default, e.g. "72h".
+	Duration string
+	// MaxDuration is the longest validity a preview link can be created with.
+	MaxDuration string
+}
+
+// TrashConfig configures how long trashed items are kept.
+type TrashConfig struct {
+	// Retention is how long items stay in the trash before they are purged, e.g. "720h".
+	Retention string
+	// PurgeInterval is how often expired items are purged.
+	PurgeInterval string
+}
+
+// BulkConfig configures bulk operations.
+type BulkConfig struct {
+	// SyncLimit is the largest number of items an operation processes before responding; larger ones
+	// run in the background.
+	SyncLimit string
+	// MaxItems is the largest number of items a single operation may select.
+	MaxItems string
+}
+
+// StaticConfig configures the static site export.
+type StaticConfig struct {
+	// OutputDir is the directory the site is exported to.
+	OutputDir string
+	// ThemeDir holds the templates pages are rendered with. The built-in theme is used when empty.
+	ThemeDir string
+}
+
+// ThemeConfig configures the server-side rendering of the public site.
+type ThemeConfig struct {
+	// Rendering set to "true" serves the public pages as HTML rendered by the active theme instead
+	// of JSON.
+	Rendering string
+	// Dir holds the installed themes, one subdirectory each.
+	Dir string
+	// Default is the theme used until administrators activate another one.
+	Default string
+}
+
+// FormConfig configures the protection, retention and notifications of form submissions.
+type FormConfig struct {
+	// HoneypotField names the hidden input only bots fill in; submissions carrying it are discarded.
+	HoneypotField string
+	// MaxPerIP is the number of submissions one address may make within RateWindow.
+	MaxPerIP string
+	// RateWindow is the duration over which submissions per address are counted, e.g. "10m".
+	RateWindow string
+	// PurgeInterval is how often submissions that outlived the retention of their form are purged.
+	PurgeInterval string
+	// WebhookSecret signs the notifications posted to form notify URLs. They are unsigned when empty.
+	WebhookSecret string
 }
 
 // Load reads the provided .env files (if present) and maps environment variables to AppConfig.
@@ -30,10 +171,74 @@ func Load(envFiles ...string) (AppConfig, error) {
 	}
 
 	cfg := AppConfig{
-		HTTPAddr:          envOrDefault("HTTP_ADDR", ":8080"),
-		GinMode:           os.Getenv("GIN_MODE"),
-		TokenSymmetricKey: envOrDefault("TOKEN_SYMMETRIC_KEY", "12345678901234567890123456789012"), // Default 32 chars
-		TokenDuration:     envOrDefault("TOKEN_DURATION", "24h"),
+		HTTPAddr:           envOrDefault("HTTP_ADDR", ":8080"),
+		GinMode:            os.Getenv("GIN_MODE"),
+		TrustedProxies:     os.Getenv("TRUSTED_PROXIES"),
+		TokenSymmetricKey:  envOrDefault("TOKEN_SYMMETRIC_KEY", "12345678901234567890123456789012"), // Default 32 chars
+		TokenDuration:      envOrDefault("TOKEN_DURATION", "24h"),
+		PermalinkPatterns:  envOrDefault("PERMALINK_PATTERNS", "post=/{year}/{month}/{slug},page=/{slug}"),
+		Locales:            envOrDefault("SITE_LOCALES", "en"),
+		LocaleFallbacks:    os.Getenv("LOCALE_FALLBACKS"),
+		SearchEngine:       envOrDefault("SEARCH_ENGINE", "mysql"),
+		LockTTL:            envOrDefault("LOCK_TTL", "2m"),
+		CategoryURLPattern: envOrDefault("CATEGORY_URL_PATTERN", "/category/{slug}"),
+		TagURLPattern:      envOrDefault("TAG_URL_PATTERN", "/tag/{slug}"),
+		Media: MediaConfig{
+			StoragePath:   envOrDefault("MEDIA_STORAGE_PATH", "./uploads"),
+			MaxUploadSize: envOrDefault("MEDIA_MAX_UPLOAD_SIZE", "10485760"), // 10 MiB
+			AllowedTypes:  envOrDefault("MEDIA_ALLOWED_TYPES", "image/jpeg,image/png,image/gif,image/webp,application/pdf"),
+			BaseURL:       envOrDefault("MEDIA_BASE_URL", "/api/v1/media"),
+			ImageSizes:    envOrDefault("MEDIA_IMAGE_SIZES", "thumbnail=150x150:crop,medium=800x800:fit,large=1600x1600:fit,avatar=256x256:crop"),
+			SigningKey:    os.Getenv("MEDIA_SIGNING_KEY"),
+		},
+		Comments: CommentConfig{
+			MaxLinks:     envOrDefault("COMMENT_MAX_LINKS", "2"),
+			BlockedWords: os.Getenv("COMMENT_BLOCKED_WORDS"),
+			MaxPerIP:     envOrDefault("COMMENT_MAX_PER_IP", "5"),
+			RateWindow:   envOrDefault("COMMENT_RATE_WINDOW", "10m"),
+		},
+		SEO: SEOConfig{
+			SiteURL:        envOrDefault("SITE_URL", "http://localhost:8080"),
+			SiteName:       os.Getenv("SITE_NAME"),
+			AllowIndexing:  envOrDefault("SEO_ALLOW_INDEXING", "true"),
+			RobotsDisallow: envOrDefault("SEO_ROBOTS_DISALLOW", "/api/"),
+		},
+		Feed: FeedConfig{
+			Title:       os.Getenv("FEED_TITLE"),
+			Description: os.Getenv("FEED_DESCRIPTION"),
+			FullContent: envOrDefault("FEED_FULL_CONTENT", "false"),
+			Size:        envOrDefault("FEED_SIZE", "20"),
+			ContentType: envOrDefault("FEED_CONTENT_TYPE", "post"),
+		},
+		Preview: PreviewConfig{
+			URL:         os.Getenv("PREVIEW_URL"),
+			Duration:    envOrDefault("PREVIEW_DURATION", "72h"),
+			MaxDuration: envOrDefault("PREVIEW_MAX_DURATION", "720h"),
+		},
+		Trash: TrashConfig{
+			Retention:     envOrDefault("TRASH_RETENTION", "720h"),
+			PurgeInterval: envOrDefault("TRASH_PURGE_INTERVAL", "1h"),
+		},
+		Bulk: BulkConfig{
+			SyncLimit: envOrDefault("BULK_SYNC_LIMIT", "50"),
+			MaxItems:  envOrDefault("BULK_MAX_ITEMS", "10000"),
+		},
+		Static: StaticConfig{
+			OutputDir: envOrDefault("STATIC_OUTPUT_DIR", "./public"),
+			ThemeDir:  envOrDefault("STATIC_THEME_DIR", ""),
+		},
+		Theme: ThemeConfig{
+			Rendering: envOrDefault("THEME_RENDERING", "false"),
+			Dir:       envOrDefault("THEMES_DIR", "./themes"),
+			Default:   envOrDefault("THEME", "default"),
+		},
+		Forms: FormConfig{
+			HoneypotField: envOrDefault("FORM_HONEYPOT_FIELD", "hp_website"),
+			MaxPerIP:      envOrDefault("FORM_MAX_PER_IP", "5"),
+			RateWindow:    envOrDefault("FORM_RATE_WINDOW", "10m"),
+			PurgeInterval: envOrDefault("FORM_PURGE_INTERVAL", "1h"),
+			WebhookSecret: os.Getenv("FORM_WEBHOOK_SECRET"),
+		},
 		Database: database.Config{
 			Driver:       os.Getenv("DB_DRIVER"),
 			Username:     os.Getenv("DB_USERNAME"),
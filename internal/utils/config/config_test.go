@@ -57,6 +57,9 @@ func TestLoad_UsesDefaultsWhenMissing(t *testing.T) {
 	if cfg.Database.Driver != "" {
 		t.Fatalf("expected empty database config, got %+v", cfg.Database)
 	}
+	if cfg.Media.StoragePath != "./uploads" || cfg.Media.MaxUploadSize != "10485760" {
+		t.Fatalf("expected default media config, got %+v", cfg.Media)
+	}
 }
 
 func TestEnvOrDefault(t *testing.T) {
@@ -0,0 +1,185 @@
+// Package fulltext holds the text handling shared by the search backends: tokenizing queries and
+// documents, and building highlighted snippets from matched terms.
+package fulltext
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TitleBoost is how much more a term matched in the title weighs than one matched in the body.
+const TitleBoost = 3
+
+// SnippetSize is the approximate length in characters of the body excerpts shown with search hits.
+const SnippetSize = 200
+
+// minTermLength drops one-letter tokens, which carry no meaning on their own.
+const minTermLength = 2
+
+// Highlight markers wrapped around matched terms.
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+)
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// Terms splits text into lower-case search terms, in order and including repeats.
+func Terms(text string) []string {
+	var terms []string
+	for _, tok := range tokens(text) {
+		if term := strings.ToLower(text[tok[0]:tok[1]]); utf8.RuneCountInString(term) >= minTermLength {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// PlainText strips the markup of sanitized HTML, leaving its text with collapsed whitespace.
+func PlainText(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(tagPattern.ReplaceAllString(s, " "))), " ")
+}
+
+// Summary returns the plain text of html, or the words of fallback when html holds no text, cut on a
+// word boundary to at most size characters and an ellipsis. Feeds and meta descriptions summarize
+// entries without an excerpt with it.
+func Summary(html, fallback string, size int) string {
+	text := PlainText(html)
+	if text == "" {
+		text = strings.Join(strings.Fields(fallback), " ")
+	}
+	if utf8.RuneCountInString(text) <= size {
+		return text
+	}
+
+	cut := string([]rune(text)[:size])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		return cut[:i] + "…"
+	}
+	return cut + "…"
+}
+
+// Highlight HTML-escapes text and wraps every word matching one of terms in <mark> tags.
+func Highlight(text string, terms []string) string {
+	set := termSet(terms)
+	var b strings.Builder
+	last := 0
+	for _, tok := range tokens(text) {
+		if !set[strings.ToLower(text[tok[0]:tok[1]])] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[last:tok[0]]))
+		b.WriteString(markOpen)
+		b.WriteString(html.EscapeString(text[tok[0]:tok[1]]))
+		b.WriteString(markClose)
+		last = tok[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// Snippet cuts a window of about size characters out of text around the first word matching one
+// of terms, falling back to the start of text, and highlights it like Highlight.
+func Snippet(text string, terms []string, size int) string {
+	if utf8.RuneCountInString(text) <= size {
+		return Highlight(text, terms)
+	}
+
+	set := termSet(terms)
+	match := 0
+	for _, tok := range tokens(text) {
+		if set[strings.ToLower(text[tok[0]:tok[1]])] {
+			match = tok[0]
+			break
+		}
+	}
+	// Show some context before the match, starting on a word boundary.
+	start := wordStart(text, backRunes(text, match, size/4), match)
+	end := len(text)
+	if n := forwardRunes(text, start, size); n < end {
+		end = wordEnd(text, n)
+	}
+
+	snippet := Highlight(strings.TrimSpace(text[start:end]), terms)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// tokens returns the byte ranges of the words in text.
+func tokens(text string) [][2]int {
+	var (
+		out   [][2]int
+		start = -1
+	)
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			out = append(out, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		out = append(out, [2]int{start, len(text)})
+	}
+	return out
+}
+
+func termSet(terms []string) map[string]bool {
+	set := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		set[strings.ToLower(term)] = true
+	}
+	return set
+}
+
+// backRunes moves n runes back from byte offset i.
+func backRunes(text string, i, n int) int {
+	for ; n > 0 && i > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(text[:i])
+		i -= size
+	}
+	return i
+}
+
+// forwardRunes moves n runes forward from byte offset i.
+func forwardRunes(text string, i, n int) int {
+	for ; n > 0 && i < len(text); n-- {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	return i
+}
+
+// wordStart moves i forward, no further than limit, past a partial word so a snippet does not
+// begin mid-word.
+func wordStart(text string, i, limit int) int {
+	if i == 0 || i == limit {
+		return i
+	}
+	if r, _ := utf8.DecodeLastRuneInString(text[:i]); unicode.IsSpace(r) {
+		return i
+	}
+	if idx := strings.IndexFunc(text[i:limit], unicode.IsSpace); idx >= 0 {
+		return i + idx + 1
+	}
+	return i
+}
+
+// wordEnd moves i back to the previous space so a snippet does not end mid-word.
+func wordEnd(text string, i int) int {
+	if idx := strings.LastIndexFunc(text[:i], unicode.IsSpace); idx > 0 {
+		return idx
+	}
+	return i
+}
@@ -0,0 +1,41 @@
+package fulltext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"go", "cms", "rilis", "2025"}, Terms("Go-CMS: a rilis (2025)!"))
+	assert.Empty(t, Terms("  ,  a "))
+}
+
+func TestPlainText(t *testing.T) {
+	assert.Equal(t, "Hello world & friends Next", PlainText("<h1>Hello <em>world</em> &amp; friends</h1><p>Next</p>"))
+}
+
+func TestSummary(t *testing.T) {
+	assert.Equal(t, "Hello world", Summary("<p>Hello <em>world</em></p>", "ignored", 20))
+	assert.Equal(t, "From the raw body", Summary("", "  From the\nraw body ", 20))
+	assert.Equal(t, "Hello…", Summary("<p>Hello wonderful world</p>", "", 10))
+	assert.Equal(t, "Supercalif…", Summary("Supercalifragilistic", "", 10))
+}
+
+func TestHighlight(t *testing.T) {
+	assert.Equal(t, "<mark>Go</mark> &amp; <mark>gophers</mark> &lt;b&gt;", Highlight("Go & gophers <b>", []string{"go", "gophers"}))
+	assert.Equal(t, "Going", Highlight("Going", []string{"go"}))
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("filler words here ", 20) + "the search term appears " + strings.Repeat("trailing text ", 20)
+
+	snippet := Snippet(text, []string{"search"}, 60)
+	assert.True(t, strings.HasPrefix(snippet, "…"))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+	assert.Contains(t, snippet, "<mark>search</mark>")
+
+	assert.Equal(t, "short <mark>text</mark>", Snippet("short text", []string{"text"}, 60))
+	assert.True(t, strings.HasPrefix(Snippet(text, []string{"missing"}, 60), "filler words"))
+}
@@ -0,0 +1,264 @@
+// Package imaging resizes, crops and re-encodes raster images using only the standard library.
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fit modes.
+const (
+	// FitContain scales the image down to fit inside the box, preserving its aspect ratio.
+	FitContain = "fit"
+	// FitCover fills the box exactly, cropping around the focal point.
+	FitCover = "crop"
+)
+
+// Output formats.
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+)
+
+// MaxDimension caps the width and height of any rendition.
+const MaxDimension = 4096
+
+// DefaultQuality is the JPEG quality used when none is requested.
+const DefaultQuality = 82
+
+// ErrInvalidParams is returned for transform parameters outside the supported range.
+var ErrInvalidParams = errors.New("invalid image parameters")
+
+// Params describes a rendition. Zero Width or Height is derived from the source aspect ratio.
+type Params struct {
+	Width   int
+	Height  int
+	Fit     string
+	Format  string
+	Quality int
+}
+
+// Normalize fills defaults and validates p. srcFormat is used when no output format is requested.
+func (p Params) Normalize(srcFormat string) (Params, error) {
+	if p.Width < 0 || p.Height < 0 || p.Width > MaxDimension || p.Height > MaxDimension {
+		return p, fmt.Errorf("%w: dimensions must be between 0 and %d", ErrInvalidParams, MaxDimension)
+	}
+	if p.Width == 0 && p.Height == 0 {
+		return p, fmt.Errorf("%w: width or height is required", ErrInvalidParams)
+	}
+
+	switch p.Fit {
+	case "":
+		p.Fit = FitContain
+	case FitContain, FitCover:
+	default:
+		return p, fmt.Errorf("%w: unknown fit %q", ErrInvalidParams, p.Fit)
+	}
+	if p.Fit == FitCover && (p.Width == 0 || p.Height == 0) {
+		return p, fmt.Errorf("%w: crop requires both width and height", ErrInvalidParams)
+	}
+
+	if p.Format == "" {
+		p.Format = srcFormat
+	}
+	switch p.Format {
+	case "jpg":
+		p.Format = FormatJPEG
+	case FormatJPEG, FormatPNG, FormatGIF:
+	default:
+		return p, fmt.Errorf("%w: unsupported format %q", ErrInvalidParams, p.Format)
+	}
+
+	if p.Format != FormatJPEG {
+		p.Quality = 0
+	} else if p.Quality == 0 {
+		p.Quality = DefaultQuality
+	} else if p.Quality < 1 || p.Quality > 100 {
+		return p, fmt.Errorf("%w: quality must be between 1 and 100", ErrInvalidParams)
+	}
+
+	return p, nil
+}
+
+// Key returns a stable identifier for p, suitable for cache keys and signatures.
+func (p Params) Key() string {
+	key := fmt.Sprintf("w%d-h%d-%s", p.Width, p.Height, p.Fit)
+	if p.Quality > 0 {
+		key += fmt.Sprintf("-q%d", p.Quality)
+	}
+	return key + "." + Extension(p.Format)
+}
+
+// Extension returns the file extension (without dot) for an output format.
+func Extension(format string) string {
+	if format == FormatJPEG {
+		return "jpg"
+	}
+	return format
+}
+
+// ContentType returns the MIME type for an output format.
+func ContentType(format string) string {
+	return "image/" + format
+}
+
+// ParsePresets parses a preset list such as "thumbnail=150x150:crop,medium=800x0:fit".
+// The fit suffix is optional and defaults to fit.
+func ParsePresets(spec string) (map[string]Params, error) {
+	presets := make(map[string]Params)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: preset %q must look like name=WxH[:fit]", ErrInvalidParams, entry)
+		}
+
+		size, fit, _ := strings.Cut(strings.TrimSpace(value), ":")
+		w, h, ok := strings.Cut(size, "x")
+		if !ok {
+			return nil, fmt.Errorf("%w: preset %q must look like name=WxH[:fit]", ErrInvalidParams, entry)
+		}
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if errW != nil || errH != nil {
+			return nil, fmt.Errorf("%w: preset %q has a non-numeric size", ErrInvalidParams, entry)
+		}
+
+		p := Params{Width: width, Height: height, Fit: fit}
+		// Validate against a representative source format; the real one is applied per image.
+		if _, err := p.Normalize(FormatPNG); err != nil {
+			return nil, fmt.Errorf("preset %q: %w", name, err)
+		}
+		presets[name] = p
+	}
+	return presets, nil
+}
+
+// PresetNames returns the preset names in a stable order.
+func PresetNames(presets map[string]Params) []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Transform produces the rendition described by p. focalX and focalY, in the 0..1 range,
+// mark the point that cover crops keep in view. Images are never enlarged.
+func Transform(src image.Image, p Params, focalX, focalY float64) image.Image {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if sw == 0 || sh == 0 {
+		return src
+	}
+
+	if p.Fit == FitCover {
+		// Crop the largest window with the target aspect ratio, centred on the focal point.
+		targetRatio := float64(p.Width) / float64(p.Height)
+		cw, ch := float64(sw), float64(sw)/targetRatio
+		if ch > float64(sh) {
+			cw, ch = float64(sh)*targetRatio, float64(sh)
+		}
+		x0 := clamp(focalX*float64(sw)-cw/2, 0, float64(sw)-cw)
+		y0 := clamp(focalY*float64(sh)-ch/2, 0, float64(sh)-ch)
+		crop := image.Rect(b.Min.X+int(math.Round(x0)), b.Min.Y+int(math.Round(y0)),
+			b.Min.X+int(math.Round(x0+cw)), b.Min.Y+int(math.Round(y0+ch)))
+
+		w, h := p.Width, p.Height
+		if w > crop.Dx() {
+			w, h = crop.Dx(), int(math.Round(float64(crop.Dx())/targetRatio))
+		}
+		return resize(src, crop, max(w, 1), max(h, 1))
+	}
+
+	scale := 1.0
+	if p.Width > 0 {
+		scale = math.Min(scale, float64(p.Width)/float64(sw))
+	}
+	if p.Height > 0 {
+		scale = math.Min(scale, float64(p.Height)/float64(sh))
+	}
+	w := max(int(math.Round(float64(sw)*scale)), 1)
+	h := max(int(math.Round(float64(sh)*scale)), 1)
+	return resize(src, b, w, h)
+}
+
+// Encode writes img to w in the requested format.
+func Encode(w io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case FormatJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case FormatPNG:
+		return png.Encode(w, img)
+	case FormatGIF:
+		return gif.Encode(w, img, nil)
+	default:
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidParams, format)
+	}
+}
+
+// resize scales the rect region of src to w×h by averaging every source pixel a destination
+// pixel covers, which keeps downscaled images free of aliasing.
+func resize(src image.Image, rect image.Rectangle, w, h int) *image.NRGBA {
+	in := image.NewNRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(in, in.Bounds(), src, rect.Min, draw.Src)
+	if w == rect.Dx() && h == rect.Dy() {
+		return in
+	}
+
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	sw, sh := rect.Dx(), rect.Dy()
+	for y := 0; y < h; y++ {
+		y0 := y * sh / h
+		y1 := max((y+1)*sh/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := x * sw / w
+			x1 := max((x+1)*sw/w, x0+1)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := in.Pix[sy*in.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					px := row[sx*4 : sx*4+4]
+					alpha := uint64(px[3])
+					// Weight colour by alpha so transparent pixels do not darken edges.
+					r += uint64(px[0]) * alpha
+					g += uint64(px[1]) * alpha
+					bl += uint64(px[2]) * alpha
+					a += alpha
+					n++
+				}
+			}
+
+			i := y*out.Stride + x*4
+			if a > 0 {
+				out.Pix[i] = uint8(r / a)
+				out.Pix[i+1] = uint8(g / a)
+				out.Pix[i+2] = uint8(bl / a)
+			}
+			out.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return out
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(v, hi))
+}
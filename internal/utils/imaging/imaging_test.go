@@ -0,0 +1,99 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// halves returns a w×h image whose left half is red and right half is blue.
+func halves(w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestTransform_FitPreservesAspectRatio(t *testing.T) {
+	out := Transform(halves(400, 200), Params{Width: 100, Height: 100, Fit: FitContain}, 0.5, 0.5)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), out.Bounds())
+
+	out = Transform(halves(400, 200), Params{Width: 0, Height: 50, Fit: FitContain}, 0.5, 0.5)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), out.Bounds())
+}
+
+func TestTransform_FitNeverEnlarges(t *testing.T) {
+	out := Transform(halves(40, 20), Params{Width: 400, Height: 400, Fit: FitContain}, 0.5, 0.5)
+	assert.Equal(t, image.Rect(0, 0, 40, 20), out.Bounds())
+}
+
+func TestTransform_CoverUsesFocalPoint(t *testing.T) {
+	src := halves(400, 200)
+
+	left := Transform(src, Params{Width: 50, Height: 50, Fit: FitCover}, 0, 0.5)
+	require.Equal(t, image.Rect(0, 0, 50, 50), left.Bounds())
+	r, _, b, _ := left.At(25, 25).RGBA()
+	assert.Greater(t, r, b, "focal point on the left keeps the red half")
+
+	right := Transform(src, Params{Width: 50, Height: 50, Fit: FitCover}, 1, 0.5)
+	r, _, b, _ = right.At(25, 25).RGBA()
+	assert.Greater(t, b, r, "focal point on the right keeps the blue half")
+}
+
+func TestParams_Normalize(t *testing.T) {
+	p, err := Params{Width: 100}.Normalize(FormatJPEG)
+	require.NoError(t, err)
+	assert.Equal(t, Params{Width: 100, Fit: FitContain, Format: FormatJPEG, Quality: DefaultQuality}, p)
+	assert.Equal(t, "w100-h0-fit-q82.jpg", p.Key())
+
+	p, err = Params{Width: 10, Height: 10, Fit: FitCover, Format: "png", Quality: 50}.Normalize(FormatJPEG)
+	require.NoError(t, err)
+	assert.Equal(t, 0, p.Quality)
+
+	invalid := []Params{
+		{},
+		{Width: MaxDimension + 1},
+		{Width: 10, Fit: FitCover},
+		{Width: 10, Fit: "stretch"},
+		{Width: 10, Format: "webp"},
+		{Width: 10, Format: FormatJPEG, Quality: 101},
+	}
+	for _, params := range invalid {
+		_, err := params.Normalize(FormatJPEG)
+		assert.ErrorIs(t, err, ErrInvalidParams, "%+v", params)
+	}
+}
+
+func TestParsePresets(t *testing.T) {
+	presets, err := ParsePresets("thumbnail=150x150:crop, medium=800x0, avatar=256x256:crop")
+	require.NoError(t, err)
+	assert.Equal(t, Params{Width: 150, Height: 150, Fit: FitCover}, presets["thumbnail"])
+	assert.Equal(t, Params{Width: 800}, presets["medium"])
+	assert.Equal(t, []string{"avatar", "medium", "thumbnail"}, PresetNames(presets))
+
+	for _, spec := range []string{"thumb", "thumb=axb", "thumb=100x0:crop", "=10x10"} {
+		_, err := ParsePresets(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, halves(10, 10), FormatJPEG, 80))
+
+	decoded, err := jpeg.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 10, 10), decoded.Bounds())
+}
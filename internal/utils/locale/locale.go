@@ -0,0 +1,130 @@
+// Package locale holds the configured site locales and negotiates the locale of public requests.
+package locale
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// Settings lists the supported locales, the first being the default, and the fallback chain of each
+// locale. A locale without a configured chain falls back to the default locale only.
+type Settings struct {
+	Locales   []string            `json:"locales"`
+	Fallbacks map[string][]string `json:"fallbacks"`
+}
+
+// Parse reads a comma-separated locale list such as "id,en" and fallback chains such as
+// "ms=id>en,en-gb=en". Every locale named in a chain must be supported.
+func Parse(locales, fallbacks string) (Settings, error) {
+	s := Settings{Fallbacks: map[string][]string{}}
+	for _, l := range strings.Split(locales, ",") {
+		l = Normalize(l)
+		if l == "" {
+			continue
+		}
+		if !tagPattern.MatchString(l) {
+			return Settings{}, fmt.Errorf("invalid locale %q", l)
+		}
+		if !slices.Contains(s.Locales, l) {
+			s.Locales = append(s.Locales, l)
+		}
+	}
+	if len(s.Locales) == 0 {
+		return Settings{}, fmt.Errorf("at least one locale is required")
+	}
+
+	for _, pair := range strings.Split(fallbacks, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		from, chain, ok := strings.Cut(pair, "=")
+		if !ok {
+			return Settings{}, fmt.Errorf("invalid locale fallback %q: expected locale=fallback>fallback", pair)
+		}
+		from = Normalize(from)
+		if !s.Supported(from) {
+			return Settings{}, fmt.Errorf("locale fallback %q names unsupported locale %q", pair, from)
+		}
+		for _, l := range strings.Split(chain, ">") {
+			l = Normalize(l)
+			if !s.Supported(l) {
+				return Settings{}, fmt.Errorf("locale fallback %q names unsupported locale %q", pair, l)
+			}
+			s.Fallbacks[from] = append(s.Fallbacks[from], l)
+		}
+	}
+	return s, nil
+}
+
+// Normalize lowercases a language tag and uses hyphens as separators, e.g. "en_US" becomes "en-us".
+func Normalize(tag string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "_", "-")
+}
+
+// Default returns the default locale.
+func (s Settings) Default() string {
+	return s.Locales[0]
+}
+
+// Supported reports whether l is one of the configured locales.
+func (s Settings) Supported(l string) bool {
+	return slices.Contains(s.Locales, l)
+}
+
+// Negotiate picks the locale of a request. An explicit, supported query locale wins; otherwise the
+// Accept-Language header is matched by preference, first exactly and then by base language, so
+// "en-US" selects "en". The default locale is returned when nothing matches.
+func (s Settings) Negotiate(query, acceptLanguage string) string {
+	if l := s.match(query); l != "" {
+		return l
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err == nil {
+		for _, tag := range tags {
+			if l := s.match(tag.String()); l != "" {
+				return l
+			}
+		}
+	}
+	return s.Default()
+}
+
+func (s Settings) match(tag string) string {
+	tag = Normalize(tag)
+	if tag == "" {
+		return ""
+	}
+	if s.Supported(tag) {
+		return tag
+	}
+	base, _, _ := strings.Cut(tag, "-")
+	if s.Supported(base) {
+		return base
+	}
+	return ""
+}
+
+// Chain returns the locales to try for l in order: l itself, its configured fallbacks and finally the
+// default locale. Unsupported locales start the chain at the default locale.
+func (s Settings) Chain(l string) []string {
+	var chain []string
+	add := func(l string) {
+		if s.Supported(l) && !slices.Contains(chain, l) {
+			chain = append(chain, l)
+		}
+	}
+	add(l)
+	for _, fallback := range s.Fallbacks[l] {
+		add(fallback)
+	}
+	add(s.Default())
+	return chain
+}
@@ -0,0 +1,46 @@
+package locale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	s, err := Parse("id, en, ms", "ms=id>en")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "en", "ms"}, s.Locales)
+	assert.Equal(t, "id", s.Default())
+	assert.Equal(t, []string{"id", "en"}, s.Fallbacks["ms"])
+
+	_, err = Parse("", "")
+	assert.Error(t, err)
+	_, err = Parse("english", "")
+	assert.Error(t, err)
+	_, err = Parse("id,en", "ms=id")
+	assert.Error(t, err)
+	_, err = Parse("id,en", "en>id")
+	assert.Error(t, err)
+}
+
+func TestSettings_Negotiate(t *testing.T) {
+	s, err := Parse("id,en", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "en", s.Negotiate("en", "id"))
+	assert.Equal(t, "en", s.Negotiate("", "fr-FR, en-US;q=0.8, id;q=0.5"))
+	assert.Equal(t, "id", s.Negotiate("de", "fr;q=0.9, id;q=0.8"))
+	assert.Equal(t, "id", s.Negotiate("", "fr"))
+	assert.Equal(t, "id", s.Negotiate("", "not a header;;"))
+}
+
+func TestSettings_Chain(t *testing.T) {
+	s, err := Parse("id,en,ms", "ms=en")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ms", "en", "id"}, s.Chain("ms"))
+	assert.Equal(t, []string{"en", "id"}, s.Chain("en"))
+	assert.Equal(t, []string{"id"}, s.Chain("id"))
+	assert.Equal(t, []string{"id"}, s.Chain("fr"))
+}
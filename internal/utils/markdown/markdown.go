@@ -0,0 +1,87 @@
+// Package markdown converts author-supplied Markdown or HTML into HTML that is safe to embed in public pages.
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// Source formats.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// ErrUnknownFormat is returned for a source format other than markdown or html.
+var ErrUnknownFormat = errors.New("unknown body format")
+
+var (
+	// converter renders GitHub-flavoured Markdown with footnotes. Code blocks are highlighted with
+	// CSS classes rather than inline styles, so the site stylesheet decides the colour scheme.
+	converter = goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+			highlighting.NewHighlighting(
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	policy = newPolicy()
+
+	// inputTag matches the input elements left by the policy, whose attribute values are escaped.
+	inputTag = regexp.MustCompile(`<input\b[^>]*>`)
+)
+
+// Render converts source written in format to sanitized HTML.
+func Render(source, format string) (string, error) {
+	switch format {
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := converter.Convert([]byte(source), &buf); err != nil {
+			return "", err
+		}
+		return Sanitize(buf.String()), nil
+	case FormatHTML:
+		return Sanitize(source), nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// Sanitize strips every element and attribute that is not on the allowlist, including scripts,
+// event handlers, inline styles and javascript: URLs. Inputs other than checkboxes are dropped.
+func Sanitize(html string) string {
+	return inputTag.ReplaceAllStringFunc(policy.Sanitize(html), func(tag string) string {
+		if !strings.Contains(tag, ` type="checkbox"`) {
+			return ""
+		}
+		return tag
+	})
+}
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+
+	// Syntax highlighting and footnotes rely on class names and ARIA roles.
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-zA-Z0-9 _-]+$`)).OnElements("pre", "code", "span", "div", "a", "sup", "li")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-[a-z]+$`)).OnElements("a", "div")
+	// GFM task lists render as disabled checkboxes; Sanitize drops inputs of any other type.
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	// Links opened in a new tab must not get a handle on the opener.
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return p
+}
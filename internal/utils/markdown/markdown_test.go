@@ -0,0 +1,60 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_Markdown(t *testing.T) {
+	source := "# Title\n\nSee note[^1].\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n- [x] done\n\n```go\nfunc main() {}\n```\n\n[^1]: A footnote.\n"
+
+	html, err := Render(source, FormatMarkdown)
+	require.NoError(t, err)
+
+	assert.Contains(t, html, `<h1 id="title">Title</h1>`)
+	assert.Contains(t, html, "<table>")
+	assert.Contains(t, html, `<td>1</td>`)
+	assert.Contains(t, html, `<input checked="" disabled="" type="checkbox">`)
+	assert.Contains(t, html, `<pre class="chroma">`)
+	assert.Contains(t, html, `<span class="kd">func</span>`)
+	assert.Contains(t, html, `class="footnote-ref"`)
+	assert.Contains(t, html, `<li id="fn:1">`)
+}
+
+func TestRender_StripsUnsafeMarkup(t *testing.T) {
+	testCases := map[string]string{
+		FormatMarkdown: "Hi <script>alert(1)</script> [x](javascript:alert(1)) <img src=x onerror=alert(1)>",
+		FormatHTML:     `<p onclick="alert(1)" style="color:red">Hi</p><script>alert(1)</script><a href="javascript:alert(1)">x</a><iframe src="https://evil"></iframe>`,
+	}
+
+	for format, source := range testCases {
+		html, err := Render(source, format)
+		require.NoError(t, err, format)
+
+		assert.NotContains(t, html, "<script", format)
+		assert.NotContains(t, html, "javascript:", format)
+		assert.NotContains(t, html, "onerror", format)
+		assert.NotContains(t, html, "onclick", format)
+		assert.NotContains(t, html, "style=", format)
+		assert.NotContains(t, html, "<iframe", format)
+	}
+}
+
+func TestSanitize_OnlyCheckboxInputs(t *testing.T) {
+	html := Sanitize(`<p>a<input disabled>b<input type="text" value="x">c<input type="CHECKBOX" checked>d<input checked type="checkbox" disabled>e</p>`)
+	assert.Equal(t, `<p>abcd<input checked="" type="checkbox" disabled="">e</p>`, html)
+}
+
+func TestRender_ExternalLinks(t *testing.T) {
+	html, err := Render("[site](https://example.com)", FormatMarkdown)
+	require.NoError(t, err)
+	assert.Contains(t, html, `rel="nofollow noopener"`)
+	assert.Contains(t, html, `target="_blank"`)
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	_, err := Render("text", "rst")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,177 @@
+package permalink
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultPattern is used for content types without a configured pattern. It names the type, so
+// their permalinks never resolve to a configured type.
+const DefaultPattern = "/{type}/{year}/{month}/{slug}"
+
+// Fields carries the values substituted into a permalink pattern.
+type Fields struct {
+	ID     string
+	Type   string
+	Slug   string
+	Locale string
+	Date   time.Time
+}
+
+// Patterns maps content types to permalink patterns such as "/{year}/{month}/{slug}".
+// Supported tokens are {id}, {type}, {slug}, {locale}, {year}, {month} and {day}.
+type Patterns map[string]string
+
+var tokens = map[string]bool{"{id}": true, "{type}": true, "{slug}": true, "{locale}": true, "{year}": true, "{month}": true, "{day}": true}
+
+// Parse reads a comma-separated list of type=pattern pairs, e.g. "post=/{year}/{slug},page=/{slug}".
+func Parse(spec string) (Patterns, error) {
+	patterns := Patterns{}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		contentType, pattern, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid permalink pattern %q: expected type=pattern", pair)
+		}
+		if err := Validate(pattern); err != nil {
+			return nil, err
+		}
+		patterns[strings.TrimSpace(contentType)] = strings.TrimSpace(pattern)
+	}
+	return patterns, nil
+}
+
+// Validate checks that a pattern is absolute, uses known tokens and identifies an entry by slug or id.
+func Validate(pattern string) error {
+	if !strings.HasPrefix(pattern, "/") {
+		return fmt.Errorf("permalink pattern %q must start with /", pattern)
+	}
+	identified := false
+	for _, segment := range segments(pattern) {
+		if strings.Contains(segment, "{") && !tokens[segment] {
+			return fmt.Errorf("permalink pattern %q has unsupported segment %q", pattern, segment)
+		}
+		if segment == "{slug}" || segment == "{id}" {
+			identified = true
+		}
+	}
+	if !identified {
+		return fmt.Errorf("permalink pattern %q must contain {slug} or {id}", pattern)
+	}
+	return nil
+}
+
+// For returns the pattern configured for contentType, falling back to DefaultPattern.
+func (p Patterns) For(contentType string) string {
+	if pattern, ok := p[contentType]; ok {
+		return pattern
+	}
+	return DefaultPattern
+}
+
+// Build expands the pattern for f.Type with the values in f.
+func (p Patterns) Build(f Fields) string {
+	return Expand(p.For(f.Type), f)
+}
+
+// Match finds the content type whose pattern matches path and extracts the fields it encodes.
+// Literal-prefixed patterns are tried before patterns made only of tokens, each in order of content
+// type, and DefaultPattern last for the types without a pattern.
+func (p Patterns) Match(path string) (Fields, bool) {
+	contentTypes := make([]string, 0, len(p))
+	for contentType := range p {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	var fallback []string
+	for _, contentType := range contentTypes {
+		if strings.HasPrefix(p[contentType], "/{") {
+			fallback = append(fallback, contentType)
+			continue
+		}
+		if f, ok := match(p[contentType], path); ok {
+			f.Type = contentType
+			return f, true
+		}
+	}
+	for _, contentType := range fallback {
+		if f, ok := match(p[contentType], path); ok {
+			f.Type = contentType
+			return f, true
+		}
+	}
+	if f, ok := match(DefaultPattern, path); ok {
+		// Configured types are only reachable through their own pattern.
+		if _, configured := p[f.Type]; !configured {
+			return f, true
+		}
+	}
+	return Fields{}, false
+}
+
+// Expand substitutes the tokens of pattern with values from f.
+func Expand(pattern string, f Fields) string {
+	date := f.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	return strings.NewReplacer(
+		"{id}", f.ID,
+		"{type}", f.Type,
+		"{slug}", f.Slug,
+		"{locale}", f.Locale,
+		"{year}", date.Format("2006"),
+		"{month}", date.Format("01"),
+		"{day}", date.Format("02"),
+	).Replace(pattern)
+}
+
+func match(pattern, path string) (Fields, bool) {
+	want := segments(pattern)
+	got := segments(path)
+	if len(want) != len(got) {
+		return Fields{}, false
+	}
+
+	var f Fields
+	for i, segment := range want {
+		switch segment {
+		case "{id}":
+			f.ID = got[i]
+		case "{type}":
+			f.Type = got[i]
+		case "{slug}":
+			f.Slug = got[i]
+		case "{locale}":
+			f.Locale = got[i]
+		case "{year}", "{month}", "{day}":
+			if !isDigits(got[i]) {
+				return Fields{}, false
+			}
+		default:
+			if segment != got[i] {
+				return Fields{}, false
+			}
+		}
+	}
+	return f, true
+}
+
+func segments(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,89 @@
+package permalink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	patterns, err := Parse("post=/{year}/{month}/{slug}, page=/{slug}")
+	require.NoError(t, err)
+	assert.Equal(t, "/{slug}", patterns.For("page"))
+	assert.Equal(t, DefaultPattern, patterns.For("product"))
+
+	_, err = Parse("post")
+	assert.Error(t, err)
+	_, err = Parse("post=/{year}/{unknown}")
+	assert.Error(t, err)
+	_, err = Parse("post={slug}")
+	assert.Error(t, err)
+	_, err = Parse("post=/{year}/{month}")
+	assert.Error(t, err)
+}
+
+func TestBuild(t *testing.T) {
+	patterns := Patterns{"page": "/{slug}", "post": "/blog/{year}/{month}/{day}/{slug}"}
+	date := time.Date(2025, 3, 7, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "/about", patterns.Build(Fields{Type: "page", Slug: "about"}))
+	assert.Equal(t, "/blog/2025/03/07/hello", patterns.Build(Fields{Type: "post", Slug: "hello", Date: date}))
+	assert.Equal(t, "/news/2025/03/news-item", patterns.Build(Fields{Type: "news", Slug: "news-item", Date: date}))
+	assert.Equal(t, "/en/about", Expand("/{locale}/{slug}", Fields{Slug: "about", Locale: "en"}))
+}
+
+func TestMatch(t *testing.T) {
+	patterns := Patterns{"page": "/{slug}", "post": "/blog/{year}/{month}/{slug}", "product": "/p/{id}", "news": "/news/{locale}/{slug}"}
+
+	f, ok := patterns.Match("/blog/2025/03/hello")
+	require.True(t, ok)
+	assert.Equal(t, Fields{Type: "post", Slug: "hello"}, f)
+
+	f, ok = patterns.Match("/about/")
+	require.True(t, ok)
+	assert.Equal(t, Fields{Type: "page", Slug: "about"}, f)
+
+	f, ok = patterns.Match("/p/42")
+	require.True(t, ok)
+	assert.Equal(t, Fields{Type: "product", ID: "42"}, f)
+
+	f, ok = patterns.Match("/news/id/halo")
+	require.True(t, ok)
+	assert.Equal(t, Fields{Type: "news", Locale: "id", Slug: "halo"}, f)
+
+	_, ok = patterns.Match("/blog/20x5/03/hello")
+	assert.False(t, ok)
+	_, ok = patterns.Match("/a/b/c/d/e")
+	assert.False(t, ok)
+}
+
+func TestMatch_UnconfiguredType(t *testing.T) {
+	patterns := Patterns{"post": "/{year}/{month}/{slug}", "page": "/{slug}"}
+	date := time.Date(2025, 3, 7, 10, 0, 0, 0, time.UTC)
+
+	path := patterns.Build(Fields{Type: "article", Slug: "hello", Date: date})
+	assert.Equal(t, "/article/2025/03/hello", path)
+	f, ok := patterns.Match(path)
+	require.True(t, ok)
+	assert.Equal(t, Fields{Type: "article", Slug: "hello"}, f)
+
+	f, ok = patterns.Match("/2025/03/hello")
+	require.True(t, ok)
+	assert.Equal(t, "post", f.Type)
+
+	// A configured type is not reachable through the default pattern.
+	_, ok = patterns.Match("/post/2025/03/hello")
+	assert.False(t, ok)
+}
+
+func TestMatch_Deterministic(t *testing.T) {
+	patterns := Patterns{"post": "/{year}/{slug}", "event": "/{year}/{slug}", "news": "/{year}/{slug}"}
+
+	for i := 0; i < 20; i++ {
+		f, ok := patterns.Match("/2025/hello")
+		require.True(t, ok)
+		assert.Equal(t, "event", f.Type)
+	}
+}
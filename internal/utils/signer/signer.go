@@ -0,0 +1,59 @@
+// Package signer produces and checks HMAC signatures for URLs that must not be forged.
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// MinKeySize is the minimum accepted key length in bytes.
+const MinKeySize = 32
+
+var (
+	// ErrInvalidSignature is returned when a signature does not match its payload.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrExpired is returned when a signature is past its expiry.
+	ErrExpired = errors.New("signature has expired")
+)
+
+// Signer signs payloads with HMAC-SHA256.
+type Signer struct {
+	key []byte
+}
+
+// New creates a Signer from a secret of at least MinKeySize bytes.
+func New(key string) (*Signer, error) {
+	if len(key) < MinKeySize {
+		return nil, errors.New("signing key must be at least 32 characters")
+	}
+	return &Signer{key: []byte(key)}, nil
+}
+
+// Sign returns a URL-safe signature of payload valid until expires.
+func (s *Signer) Sign(payload string, expires time.Time) string {
+	return base64.RawURLEncoding.EncodeToString(s.mac(payload, expires.Unix()))
+}
+
+// Verify checks sig against payload and expires, which is the unix time passed to Sign.
+func (s *Signer) Verify(payload string, expires int64, sig string) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(decoded, s.mac(payload, expires)) {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > expires {
+		return ErrExpired
+	}
+	return nil
+}
+
+func (s *Signer) mac(payload string, expires int64) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(payload))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(expires, 10)))
+	return h.Sum(nil)
+}
@@ -0,0 +1,39 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "12345678901234567890123456789012"
+
+func TestSigner_RoundTrip(t *testing.T) {
+	s, err := New(testKey)
+	require.NoError(t, err)
+
+	expires := time.Now().Add(time.Hour)
+	sig := s.Sign("media-1:w100", expires)
+
+	assert.NoError(t, s.Verify("media-1:w100", expires.Unix(), sig))
+	assert.ErrorIs(t, s.Verify("media-1:w200", expires.Unix(), sig), ErrInvalidSignature)
+	assert.ErrorIs(t, s.Verify("media-1:w100", expires.Unix()+1, sig), ErrInvalidSignature)
+	assert.ErrorIs(t, s.Verify("media-1:w100", expires.Unix(), "not-base64!"), ErrInvalidSignature)
+}
+
+func TestSigner_Expired(t *testing.T) {
+	s, err := New(testKey)
+	require.NoError(t, err)
+
+	expires := time.Now().Add(-time.Minute)
+	sig := s.Sign("payload", expires)
+
+	assert.ErrorIs(t, s.Verify("payload", expires.Unix(), sig), ErrExpired)
+}
+
+func TestNew_ShortKey(t *testing.T) {
+	_, err := New("short")
+	assert.Error(t, err)
+}
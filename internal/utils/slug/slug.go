@@ -0,0 +1,117 @@
+package slug
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength bounds generated slugs so they fit the slug columns with room for a uniqueness suffix.
+const MaxLength = 180
+
+// maxAttempts caps how many numbered suffixes Unique tries before giving up.
+const maxAttempts = 1000
+
+// ErrExhausted is returned when no unique slug could be found.
+var ErrExhausted = errors.New("unable to find a unique slug")
+
+// ExistsFunc reports whether a slug candidate is already taken.
+type ExistsFunc func(ctx context.Context, candidate string) (bool, error)
+
+// reserved lists slugs that would shadow application routes when used as a permalink segment.
+var reserved = map[string]bool{
+	"admin": true, "api": true, "assets": true, "atom": true, "auth": true,
+	"categories": true, "category": true, "feed": true, "health": true, "login": true,
+	"logout": true, "media": true, "preview": true, "robots": true, "rss": true,
+	"search": true, "sitemap": true, "static": true, "tag": true, "tags": true,
+}
+
+// transliterations covers letters that Unicode decomposition does not reduce to ASCII.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l", 'þ': "th", 'ı': "i",
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sch",
+	'ы': "y", 'э': "e", 'ю': "yu", 'я': "ya", 'ъ': "", 'ь': "",
+	'&': " and ",
+}
+
+// Make converts arbitrary text into a lowercase, hyphen-separated ASCII slug.
+func Make(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range norm.NFKD.String(strings.ToLower(s)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if t, ok := transliterations[r]; ok {
+			for _, tr := range t {
+				pendingHyphen = writeRune(&b, tr, pendingHyphen)
+			}
+			continue
+		}
+		pendingHyphen = writeRune(&b, r, pendingHyphen)
+	}
+
+	out := b.String()
+	if len(out) > MaxLength {
+		out = strings.TrimRight(out[:MaxLength], "-")
+	}
+	return out
+}
+
+func writeRune(b *strings.Builder, r rune, pendingHyphen bool) bool {
+	if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+		if pendingHyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+		return false
+	}
+	return true
+}
+
+// IsReserved reports whether the slug collides with a reserved route segment.
+func IsReserved(s string) bool {
+	return reserved[s]
+}
+
+// Unique normalizes base and appends -2, -3, ... until the candidate is neither reserved nor taken.
+func Unique(ctx context.Context, base string, exists ExistsFunc) (string, error) {
+	base = Make(base)
+	if base == "" {
+		base = "untitled"
+	}
+
+	for i := 1; i <= maxAttempts; i++ {
+		candidate := base
+		if i > 1 {
+			suffix := "-" + strconv.Itoa(i)
+			candidate = strings.TrimRight(truncate(base, MaxLength-len(suffix)), "-") + suffix
+		}
+		if IsReserved(candidate) {
+			continue
+		}
+		taken, err := exists(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+
+	return "", ErrExhausted
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,68 @@
+package slug
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMake(t *testing.T) {
+	testCases := map[string]string{
+		"Hello, World!":                "hello-world",
+		"  Crème Brûlée  ":             "creme-brulee",
+		"Straße & Café":                "strasse-and-cafe",
+		"Привет мир":                   "privet-mir",
+		"Go 1.25 -- released":          "go-1-25-released",
+		"Łódź Ærø":                     "lodz-aero",
+		"!!!":                          "",
+		"already-a-slug":               "already-a-slug",
+		"Berita Terkini: Jakarta 2025": "berita-terkini-jakarta-2025",
+	}
+
+	for input, want := range testCases {
+		assert.Equal(t, want, Make(input), "input %q", input)
+	}
+}
+
+func TestMake_TruncatesLongInput(t *testing.T) {
+	got := Make(strings.Repeat("word ", 100))
+	assert.LessOrEqual(t, len(got), MaxLength)
+	assert.False(t, strings.HasSuffix(got, "-"))
+}
+
+func TestUnique(t *testing.T) {
+	taken := map[string]bool{"hello": true, "hello-2": true}
+	exists := func(ctx context.Context, candidate string) (bool, error) {
+		return taken[candidate], nil
+	}
+
+	got, err := Unique(context.Background(), "Hello", exists)
+	require.NoError(t, err)
+	assert.Equal(t, "hello-3", got)
+
+	got, err = Unique(context.Background(), "Fresh Title", exists)
+	require.NoError(t, err)
+	assert.Equal(t, "fresh-title", got)
+}
+
+func TestUnique_SkipsReservedAndEmpty(t *testing.T) {
+	exists := func(ctx context.Context, candidate string) (bool, error) { return false, nil }
+
+	got, err := Unique(context.Background(), "Admin", exists)
+	require.NoError(t, err)
+	assert.Equal(t, "admin-2", got)
+
+	got, err = Unique(context.Background(), "???", exists)
+	require.NoError(t, err)
+	assert.Equal(t, "untitled", got)
+}
+
+func TestUnique_PropagatesErrors(t *testing.T) {
+	exists := func(ctx context.Context, candidate string) (bool, error) { return false, assert.AnError }
+
+	_, err := Unique(context.Background(), "hello", exists)
+	assert.ErrorIs(t, err, assert.AnError)
+}
@@ -0,0 +1,201 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2025, 3, 9, 10, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	return &Feed{
+		Title:   "News & Notes",
+		Link:    "https://example.com/",
+		RSSURL:  "https://example.com/feed.xml",
+		AtomURL: "https://example.com/atom.xml",
+		Updated: published.Add(time.Hour),
+		Items: []Item{
+			{
+				ID:         "urn:uuid:0b7c5f38-8a4b-4d2a-9a49-1f0f6f0d1e11",
+				Title:      "Launch <day>",
+				Link:       "https://example.com/2025/03/launch",
+				Author:     "Ana",
+				Categories: []string{"News", "Releases"},
+				Summary:    "We launched.",
+				Content:    "<p>We <b>launched</b>. Odd ]]> text</p>",
+				Published:  published,
+				Updated:    published.Add(time.Hour),
+			},
+			{
+				ID:        "urn:uuid:6a1d1d9e-33a1-4d4c-8f44-1c1b2b2f0a22",
+				Title:     "About",
+				Link:      "https://example.com/about",
+				Summary:   "Who we are.",
+				Published: published,
+				Updated:   published,
+			},
+		},
+	}
+}
+
+// rssDocument decodes the parts of RSS 2.0 the specification requires, resolving namespaces.
+type rssDocument struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		Title string `xml:"title"`
+		// Links holds both the RSS link and the atom:link, told apart by their namespace.
+		Links []struct {
+			XMLName xml.Name
+			Href    string `xml:"href,attr"`
+			Rel     string `xml:"rel,attr"`
+			Type    string `xml:"type,attr"`
+			Value   string `xml:",chardata"`
+		} `xml:"link"`
+		Description   string `xml:"description"`
+		LastBuildDate string `xml:"lastBuildDate"`
+		Items         []struct {
+			Title string `xml:"title"`
+			Link  string `xml:"link"`
+			GUID  struct {
+				IsPermaLink string `xml:"isPermaLink,attr"`
+				Value       string `xml:",chardata"`
+			} `xml:"guid"`
+			PubDate     string   `xml:"pubDate"`
+			Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+			Categories  []string `xml:"category"`
+			Description string   `xml:"description"`
+			Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+// atomDocument decodes the elements RFC 4287 requires of feeds and entries.
+type atomDocument struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string   `xml:"http://www.w3.org/2005/Atom id"`
+	Title   string   `xml:"http://www.w3.org/2005/Atom title"`
+	Updated string   `xml:"http://www.w3.org/2005/Atom updated"`
+	Links   []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"http://www.w3.org/2005/Atom link"`
+	Author struct {
+		Name string `xml:"http://www.w3.org/2005/Atom name"`
+	} `xml:"http://www.w3.org/2005/Atom author"`
+	Entries []struct {
+		ID        string `xml:"http://www.w3.org/2005/Atom id"`
+		Title     string `xml:"http://www.w3.org/2005/Atom title"`
+		Updated   string `xml:"http://www.w3.org/2005/Atom updated"`
+		Published string `xml:"http://www.w3.org/2005/Atom published"`
+		Link      struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"http://www.w3.org/2005/Atom link"`
+		Author *struct {
+			Name string `xml:"http://www.w3.org/2005/Atom name"`
+		} `xml:"http://www.w3.org/2005/Atom author"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"http://www.w3.org/2005/Atom category"`
+		Summary struct {
+			Type  string `xml:"type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"http://www.w3.org/2005/Atom summary"`
+		Content *struct {
+			Type  string `xml:"type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"http://www.w3.org/2005/Atom content"`
+	} `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+func TestRSS(t *testing.T) {
+	body, err := RSS(testFeed())
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(body), xml.Header))
+
+	var doc rssDocument
+	require.NoError(t, xml.Unmarshal(body, &doc))
+
+	assert.Equal(t, "2.0", doc.Version)
+	assert.Equal(t, "News & Notes", doc.Channel.Title)
+	assert.NotEmpty(t, doc.Channel.Description, "channel description is required")
+	_, err = time.Parse(time.RFC1123Z, doc.Channel.LastBuildDate)
+	assert.NoError(t, err)
+
+	require.Len(t, doc.Channel.Links, 2)
+	link, self := doc.Channel.Links[0], doc.Channel.Links[1]
+	assert.Empty(t, link.XMLName.Space)
+	assert.Equal(t, "https://example.com/", link.Value)
+	assert.Equal(t, "http://www.w3.org/2005/Atom", self.XMLName.Space)
+	assert.Equal(t, "https://example.com/feed.xml", self.Href)
+	assert.Equal(t, "self", self.Rel)
+	assert.Equal(t, "application/rss+xml", self.Type)
+
+	require.Len(t, doc.Channel.Items, 2)
+	first := doc.Channel.Items[0]
+	assert.Equal(t, "Launch <day>", first.Title)
+	assert.Equal(t, "false", first.GUID.IsPermaLink)
+	assert.Equal(t, "urn:uuid:0b7c5f38-8a4b-4d2a-9a49-1f0f6f0d1e11", first.GUID.Value)
+	pubDate, err := time.Parse(time.RFC1123Z, first.PubDate)
+	require.NoError(t, err)
+	assert.True(t, pubDate.Equal(testFeed().Items[0].Published))
+	assert.Equal(t, "Ana", first.Creator)
+	assert.Equal(t, []string{"News", "Releases"}, first.Categories)
+	assert.Equal(t, "We launched.", first.Description)
+	assert.Equal(t, "<p>We <b>launched</b>. Odd ]]> text</p>", first.Content)
+
+	second := doc.Channel.Items[1]
+	assert.Empty(t, second.Creator)
+	assert.Empty(t, second.Content)
+	assert.NotContains(t, string(body), "<content:encoded></content:encoded>")
+}
+
+func TestAtom(t *testing.T) {
+	body, err := Atom(testFeed())
+	require.NoError(t, err)
+
+	var doc atomDocument
+	require.NoError(t, xml.Unmarshal(body, &doc))
+
+	assert.Equal(t, "https://example.com/atom.xml", doc.ID)
+	assert.Equal(t, "News & Notes", doc.Title)
+	_, err = time.Parse(time.RFC3339, doc.Updated)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, doc.Author.Name, "a feed-level author keeps entries without one valid")
+
+	rels := map[string]string{}
+	for _, link := range doc.Links {
+		rels[link.Rel] = link.Href
+	}
+	assert.Equal(t, "https://example.com/", rels["alternate"])
+	assert.Equal(t, "https://example.com/atom.xml", rels["self"])
+
+	require.Len(t, doc.Entries, 2)
+	for _, entry := range doc.Entries {
+		assert.NotEmpty(t, entry.ID)
+		assert.NotEmpty(t, entry.Title)
+		_, err := time.Parse(time.RFC3339, entry.Updated)
+		assert.NoError(t, err)
+		_, err = time.Parse(time.RFC3339, entry.Published)
+		assert.NoError(t, err)
+		assert.Equal(t, "alternate", entry.Link.Rel)
+		assert.Equal(t, "text", entry.Summary.Type)
+	}
+
+	first := doc.Entries[0]
+	require.NotNil(t, first.Author)
+	assert.Equal(t, "Ana", first.Author.Name)
+	require.Len(t, first.Categories, 2)
+	assert.Equal(t, "News", first.Categories[0].Term)
+	require.NotNil(t, first.Content)
+	assert.Equal(t, "html", first.Content.Type)
+	assert.Equal(t, "<p>We <b>launched</b>. Odd ]]> text</p>", first.Content.Value)
+
+	assert.Nil(t, doc.Entries[1].Author)
+	assert.Nil(t, doc.Entries[1].Content)
+}
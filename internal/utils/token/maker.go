@@ -9,6 +9,10 @@ type Maker interface {
 	// CreateToken creates a new token for a specific username and duration
 	CreateToken(username string, duration time.Duration) (string, *Payload, error)
 
+	// CreateScopedToken creates a token that only grants access to scope, such as a single resource.
+	// Scoped tokens are not valid as access tokens.
+	CreateScopedToken(username, scope string, duration time.Duration) (string, *Payload, error)
+
 	// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
 }
@@ -33,10 +33,16 @@ func NewPasetoMaker(symmetricKeyHex string) (Maker, error) {
 
 // CreateToken creates a new token for a specific username and duration
 func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
+	return maker.CreateScopedToken(username, "", duration)
+}
+
+// CreateScopedToken creates a new token for a specific username and duration restricted to scope
+func (maker *PasetoMaker) CreateScopedToken(username, scope string, duration time.Duration) (string, *Payload, error) {
 	payload, err := NewPayload(username, duration)
 	if err != nil {
 		return "", payload, err
 	}
+	payload.Scope = scope
 
 	token := paseto.NewToken()
 	token.SetIssuedAt(payload.IssuedAt)
@@ -44,6 +50,9 @@ func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (
 	token.SetExpiration(payload.ExpiredAt)
 	token.SetString("id", payload.ID.String())
 	token.SetString("username", payload.Username)
+	if payload.Scope != "" {
+		token.SetString("scope", payload.Scope)
+	}
 
 	encrypted := token.V4Encrypt(maker.symmetricKey, nil)
 	return encrypted, payload, nil
@@ -80,9 +89,13 @@ func (maker *PasetoMaker) VerifyToken(tokenString string) (*Payload, error) {
 		return nil, errors.New("missing expiration in token")
 	}
 
+	// Access tokens carry no scope.
+	scope, _ := parsedToken.GetString("scope")
+
 	payload := &Payload{
 		ID:        uuid.MustParse(id),
 		Username:  username,
+		Scope:     scope,
 		IssuedAt:  issuedAt,
 		ExpiredAt: expiration,
 	}
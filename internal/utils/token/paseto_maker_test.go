@@ -80,3 +80,22 @@ func TestPayload_Expired(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, ErrExpiredToken, err)
 }
+
+func TestScopedPasetoToken(t *testing.T) {
+	maker, err := NewPasetoMaker(RandomString(32))
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateScopedToken(RandomOwner(), "preview:post-1", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "preview:post-1", payload.Scope)
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, "preview:post-1", payload.Scope)
+
+	token, _, err = maker.CreateToken(RandomOwner(), time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Empty(t, payload.Scope)
+}
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE contents (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    type VARCHAR(50) NOT NULL DEFAULT 'post',
+    title VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    excerpt TEXT,
+    body MEDIUMTEXT,
+    status ENUM('draft','published','archived') DEFAULT 'draft',
+    author_id CHAR(36) NULL,
+    published_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_contents_type_slug (type, slug),
+    KEY idx_contents_status_published_at (status, published_at),
+    CONSTRAINT fk_contents_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE contents;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE categories (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    parent_id CHAR(36) NULL,
+    name VARCHAR(150) NOT NULL,
+    slug VARCHAR(150) UNIQUE NOT NULL,
+    description TEXT,
+    position INT NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories(id) ON DELETE SET NULL
+);
+
+CREATE TABLE content_categories (
+    content_id CHAR(36) NOT NULL,
+    category_id CHAR(36) NOT NULL,
+    PRIMARY KEY (content_id, category_id),
+    KEY idx_content_categories_category (category_id),
+    CONSTRAINT fk_content_categories_content FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE,
+    CONSTRAINT fk_content_categories_category FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE content_categories;
+DROP TABLE categories;
+-- +goose StatementEnd
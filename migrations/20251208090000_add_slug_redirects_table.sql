@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE slug_redirects (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    content_id CHAR(36) NOT NULL,
+    old_path VARCHAR(255) UNIQUE NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_slug_redirects_content (content_id),
+    CONSTRAINT fk_slug_redirects_content FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE slug_redirects;
+-- +goose StatementEnd
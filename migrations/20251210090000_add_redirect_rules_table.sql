@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE redirect_rules (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    source VARCHAR(500) NOT NULL,
+    match_type ENUM('exact','wildcard','regex') NOT NULL DEFAULT 'exact',
+    target VARCHAR(1000) NOT NULL DEFAULT '',
+    status_code SMALLINT NOT NULL DEFAULT 301,
+    position INT NOT NULL DEFAULT 0,
+    enabled TINYINT(1) NOT NULL DEFAULT 1,
+    hits BIGINT NOT NULL DEFAULT 0,
+    last_hit_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_redirect_rules_enabled_position (enabled, position)
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE redirect_rules;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE media (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    filename VARCHAR(255) NOT NULL,
+    storage_key VARCHAR(255) UNIQUE NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    width INT NOT NULL DEFAULT 0,
+    height INT NOT NULL DEFAULT 0,
+    alt_text VARCHAR(255) NOT NULL DEFAULT '',
+    uploader_id CHAR(36) NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_media_mime_type (mime_type),
+    KEY idx_media_uploader (uploader_id),
+    KEY idx_media_created_at (created_at),
+    CONSTRAINT fk_media_uploader FOREIGN KEY (uploader_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE media;
+-- +goose StatementEnd
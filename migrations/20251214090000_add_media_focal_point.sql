@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE media
+    ADD COLUMN focal_x DECIMAL(5,4) NOT NULL DEFAULT 0.5 AFTER height,
+    ADD COLUMN focal_y DECIMAL(5,4) NOT NULL DEFAULT 0.5 AFTER focal_x;
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE media
+    DROP COLUMN focal_y,
+    DROP COLUMN focal_x;
+-- +goose StatementEnd
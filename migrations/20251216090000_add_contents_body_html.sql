@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE contents
+    ADD COLUMN body_format VARCHAR(20) NOT NULL DEFAULT 'markdown' AFTER body,
+    ADD COLUMN body_html MEDIUMTEXT AFTER body_format;
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE contents
+    DROP COLUMN body_html,
+    DROP COLUMN body_format;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE contents
+    ADD COLUMN blocks JSON NULL AFTER body_html;
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE contents
+    DROP COLUMN blocks;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE content_types (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    name VARCHAR(48) UNIQUE NOT NULL,
+    label VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT,
+    fields JSON NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Indexed fields get generated columns and indexes added to content_entries at runtime.
+CREATE TABLE content_entries (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    type VARCHAR(48) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    data JSON NOT NULL,
+    author_id CHAR(36) NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_content_entries_type_status (type, status, created_at),
+    CONSTRAINT fk_content_entries_type FOREIGN KEY (type) REFERENCES content_types(name) ON DELETE RESTRICT,
+    CONSTRAINT fk_content_entries_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE content_entries;
+DROP TABLE content_types;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE entry_references (
+    source_id CHAR(36) NOT NULL,
+    field VARCHAR(48) NOT NULL,
+    target_id CHAR(36) NOT NULL,
+    source_type VARCHAR(48) NOT NULL,
+    target_type VARCHAR(48) NOT NULL,
+    PRIMARY KEY (source_id, field, target_id),
+    KEY idx_entry_references_target (target_id),
+    CONSTRAINT fk_entry_references_source FOREIGN KEY (source_id) REFERENCES content_entries(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE entry_references;
+-- +goose StatementEnd
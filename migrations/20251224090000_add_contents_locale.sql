@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE contents
+    ADD COLUMN locale VARCHAR(16) NOT NULL DEFAULT 'en' AFTER type,
+    ADD COLUMN translation_of CHAR(36) NULL AFTER locale,
+    DROP INDEX uq_contents_type_slug,
+    ADD UNIQUE KEY uq_contents_type_locale_slug (type, locale, slug),
+    ADD UNIQUE KEY uq_contents_translation_locale (translation_of, locale),
+    ADD CONSTRAINT fk_contents_translation_of FOREIGN KEY (translation_of) REFERENCES contents(id) ON DELETE CASCADE;
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE contents
+    DROP FOREIGN KEY fk_contents_translation_of,
+    DROP INDEX uq_contents_translation_locale,
+    DROP INDEX uq_contents_type_locale_slug,
+    ADD UNIQUE KEY uq_contents_type_slug (type, slug),
+    DROP COLUMN translation_of,
+    DROP COLUMN locale;
+-- +goose StatementEnd
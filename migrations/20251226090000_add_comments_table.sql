@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE comments (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    content_id CHAR(36) NOT NULL,
+    parent_id CHAR(36) NULL,
+    author_id CHAR(36) NULL,
+    author_name VARCHAR(100) NOT NULL,
+    author_email VARCHAR(255) NULL,
+    body TEXT NOT NULL,
+    status ENUM('pending','approved','rejected','spam') NOT NULL DEFAULT 'pending',
+    spam_score INT NOT NULL DEFAULT 0,
+    ip VARCHAR(45) NULL,
+    user_agent VARCHAR(255) NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_comments_content (content_id, status, created_at),
+    KEY idx_comments_status (status, created_at),
+    KEY idx_comments_ip (ip, created_at),
+    CONSTRAINT fk_comments_content FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comments_parent FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comments_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE comments;
+-- +goose StatementEnd
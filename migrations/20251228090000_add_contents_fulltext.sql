@@ -0,0 +1,11 @@
+-- +goose Up
+-- InnoDB builds one FULLTEXT index per statement.
+ALTER TABLE contents ADD FULLTEXT KEY ft_contents_title (title);
+ALTER TABLE contents ADD FULLTEXT KEY ft_contents_title_body (title, body);
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE contents
+    DROP INDEX ft_contents_title_body,
+    DROP INDEX ft_contents_title;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE menus (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    name VARCHAR(64) UNIQUE NOT NULL,
+    title VARCHAR(150) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE menu_items (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    menu_id CHAR(36) NOT NULL,
+    parent_id CHAR(36) NULL,
+    label VARCHAR(150) NOT NULL,
+    link_type ENUM('content','category','url') NOT NULL,
+    target_id CHAR(36) NULL,
+    url VARCHAR(2048) NULL,
+    position INT NOT NULL DEFAULT 0,
+    new_tab BOOLEAN NOT NULL DEFAULT FALSE,
+    KEY idx_menu_items_menu (menu_id, position),
+    CONSTRAINT fk_menu_items_menu FOREIGN KEY (menu_id) REFERENCES menus(id) ON DELETE CASCADE,
+    CONSTRAINT fk_menu_items_parent FOREIGN KEY (parent_id) REFERENCES menu_items(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE menu_items;
+DROP TABLE menus;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE content_seo (
+    content_id CHAR(36) PRIMARY KEY,
+    meta_title VARCHAR(255) NULL,
+    meta_description VARCHAR(500) NULL,
+    canonical_url VARCHAR(2048) NULL,
+    noindex BOOLEAN NOT NULL DEFAULT FALSE,
+    og_image_id CHAR(36) NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_content_seo_content FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE,
+    CONSTRAINT fk_content_seo_og_image FOREIGN KEY (og_image_id) REFERENCES media(id) ON DELETE SET NULL
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE content_seo;
+-- +goose StatementEnd
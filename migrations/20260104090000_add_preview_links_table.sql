@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE preview_links (
+    id CHAR(36) PRIMARY KEY,
+    content_id CHAR(36) NOT NULL,
+    revision VARCHAR(64) NOT NULL,
+    created_by CHAR(36) NULL,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_preview_links_content (content_id, created_at),
+    CONSTRAINT fk_preview_links_content FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE,
+    CONSTRAINT fk_preview_links_user FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE preview_links;
+-- +goose StatementEnd
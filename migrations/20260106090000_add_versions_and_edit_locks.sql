@@ -0,0 +1,36 @@
+-- +goose Up
+ALTER TABLE contents ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE tags ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE menus ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE media ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE redirect_rules ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE content_types ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE content_entries ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+CREATE TABLE edit_locks (
+    resource_type VARCHAR(32) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL,
+    holder_id CHAR(36) NOT NULL,
+    acquired_at DATETIME NOT NULL,
+    heartbeat_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (resource_type, resource_id),
+    KEY idx_edit_locks_expires (resource_type, expires_at),
+    CONSTRAINT fk_edit_locks_holder FOREIGN KEY (holder_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE edit_locks;
+ALTER TABLE users DROP COLUMN version;
+ALTER TABLE content_entries DROP COLUMN version;
+ALTER TABLE content_types DROP COLUMN version;
+ALTER TABLE redirect_rules DROP COLUMN version;
+ALTER TABLE media DROP COLUMN version;
+ALTER TABLE menus DROP COLUMN version;
+ALTER TABLE tags DROP COLUMN version;
+ALTER TABLE categories DROP COLUMN version;
+ALTER TABLE contents DROP COLUMN version;
+-- +goose StatementEnd
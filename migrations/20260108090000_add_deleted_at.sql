@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE contents
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_contents_deleted_at (deleted_at);
+ALTER TABLE media
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_media_deleted_at (deleted_at);
+ALTER TABLE users
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_users_deleted_at (deleted_at);
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP KEY idx_users_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE media DROP KEY idx_media_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE contents DROP KEY idx_contents_deleted_at, DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE bulk_jobs (
+    id CHAR(36) PRIMARY KEY,
+    resource VARCHAR(32) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    params JSON NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL,
+    total INT NOT NULL DEFAULT 0,
+    processed INT NOT NULL DEFAULT 0,
+    succeeded INT NOT NULL DEFAULT 0,
+    skipped INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    results JSON NOT NULL,
+    error TEXT NULL,
+    created_by CHAR(36) NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    started_at DATETIME NULL,
+    finished_at DATETIME NULL,
+    CONSTRAINT fk_bulk_jobs_user FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE bulk_jobs;
+-- +goose StatementEnd
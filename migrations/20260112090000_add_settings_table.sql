@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE settings (
+    `key` VARCHAR(64) NOT NULL PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE settings;
+-- +goose StatementEnd
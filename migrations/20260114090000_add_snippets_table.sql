@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE snippets (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    name VARCHAR(64) UNIQUE NOT NULL,
+    title VARCHAR(150) NOT NULL,
+    body MEDIUMTEXT,
+    body_format VARCHAR(16) NOT NULL DEFAULT 'markdown',
+    body_html MEDIUMTEXT,
+    blocks JSON NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    version INT NOT NULL DEFAULT 1
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE snippets;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE forms (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    name VARCHAR(64) UNIQUE NOT NULL,
+    title VARCHAR(150) NOT NULL,
+    description TEXT,
+    fields JSON NOT NULL,
+    success_message VARCHAR(500) NOT NULL DEFAULT '',
+    redirect_url VARCHAR(2048) NOT NULL DEFAULT '',
+    notify_url VARCHAR(2048) NOT NULL DEFAULT '',
+    retention_days INT NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    version INT NOT NULL DEFAULT 1
+);
+
+CREATE TABLE form_submissions (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    form_id CHAR(36) NOT NULL,
+    data JSON NOT NULL,
+    ip VARCHAR(45) NULL,
+    user_agent VARCHAR(255) NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_form_submissions_form (form_id, created_at),
+    KEY idx_form_submissions_ip (ip, created_at),
+    CONSTRAINT fk_form_submissions_form FOREIGN KEY (form_id) REFERENCES forms(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE form_submissions;
+DROP TABLE forms;
+-- +goose StatementEnd
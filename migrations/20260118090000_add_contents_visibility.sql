@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE contents
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public' AFTER status,
+    ADD COLUMN allowed_roles JSON NULL AFTER visibility,
+    ADD COLUMN password_hash VARCHAR(255) NULL AFTER allowed_roles,
+    ADD COLUMN teaser BOOLEAN NOT NULL DEFAULT FALSE AFTER password_hash,
+    ADD KEY idx_contents_visibility (visibility);
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE contents
+    DROP KEY idx_contents_visibility,
+    DROP COLUMN teaser,
+    DROP COLUMN password_hash,
+    DROP COLUMN allowed_roles,
+    DROP COLUMN visibility;
+-- +goose StatementEnd
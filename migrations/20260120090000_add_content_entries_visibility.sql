@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE content_entries
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public' AFTER status,
+    ADD COLUMN allowed_roles JSON NULL AFTER visibility,
+    ADD COLUMN password_hash VARCHAR(255) NULL AFTER allowed_roles;
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE content_entries
+    DROP COLUMN password_hash,
+    DROP COLUMN allowed_roles,
+    DROP COLUMN visibility;
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Searches match the title and excerpt of teasers the reader may not open.
+ALTER TABLE contents ADD FULLTEXT KEY ft_contents_title_excerpt (title, excerpt);
+
+-- +goose Down
+ALTER TABLE contents DROP INDEX ft_contents_title_excerpt;
@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE content_entries
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_content_entries_deleted_at (deleted_at);
+ALTER TABLE categories
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_categories_deleted_at (deleted_at);
+ALTER TABLE tags
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_tags_deleted_at (deleted_at);
+ALTER TABLE menus
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_menus_deleted_at (deleted_at);
+ALTER TABLE comments
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_comments_deleted_at (deleted_at);
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE comments DROP KEY idx_comments_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE menus DROP KEY idx_menus_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE tags DROP KEY idx_tags_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE categories DROP KEY idx_categories_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE content_entries DROP KEY idx_content_entries_deleted_at, DROP COLUMN deleted_at;
+-- +goose StatementEnd
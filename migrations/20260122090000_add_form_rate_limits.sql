@@ -0,0 +1,9 @@
+-- +goose Up
+-- One row per address that submitted a form. A submission locks the row of its address while it
+-- counts the recent submissions from there, so concurrent ones cannot all slip under the limit.
+CREATE TABLE form_rate_limits (
+    ip VARCHAR(45) PRIMARY KEY
+);
+
+-- +goose Down
+DROP TABLE form_rate_limits;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The snippets each entry references, recorded when the entry is saved. References may name
+-- snippets that do not exist yet, so name is not a foreign key.
+CREATE TABLE content_snippets (
+    content_id CHAR(36) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    PRIMARY KEY (content_id, name),
+    KEY idx_content_snippets_name (name),
+    CONSTRAINT fk_content_snippets_content FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE
+);
+
+-- Seeded from the tokens in existing bodies. Entries only quoting a token in code drop out of the
+-- counts the next time they are saved.
+INSERT INTO content_snippets (content_id, name)
+SELECT c.id, s.name FROM contents c
+JOIN snippets s ON c.body_html LIKE CONCAT('%{{snippet:', REPLACE(s.name, '_', '\\_'), '}}%');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE content_snippets;
+-- +goose StatementEnd